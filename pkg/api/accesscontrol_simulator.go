@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /access-control/users/{user_id}/simulate access_control simulateUserAccess
+//
+// Explain whether a user or service account can perform an action on a scope.
+//
+// Evaluates the action and scope with the permissions of the user in the current organization, the same way
+// access is enforced, and returns the decision with the matching permissions, the roles and teams they come
+// from and how the scope was resolved, e.g. a dashboard to its parent folders.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: simulateUserAccessResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SimulateUserAccess(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	action := c.Query("action")
+	if action == "" {
+		return response.Error(http.StatusBadRequest, "action is required", nil)
+	}
+
+	target, err := hs.userService.GetSignedInUserWithCacheCtx(c.Req.Context(), &user.GetSignedInUserQuery{UserID: userID, OrgID: c.OrgID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, "User not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+
+	simulation, err := hs.policySimulator.Simulate(c.Req.Context(), target, action, c.Query("scope"))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to simulate access", err)
+	}
+	return response.JSON(http.StatusOK, simulation)
+}
+
+// swagger:parameters simulateUserAccess
+type SimulateUserAccessParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+	// in:query
+	// required:true
+	Action string `json:"action"`
+	// in:query
+	// required:false
+	Scope string `json:"scope"`
+}
+
+// swagger:response simulateUserAccessResponse
+type SimulateUserAccessResponse struct {
+	// in:body
+	Body accesscontrol.Simulation `json:"body"`
+}
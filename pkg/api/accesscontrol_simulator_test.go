@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_SimulateUserAccess(t *testing.T) {
+	simulation := &accesscontrol.Simulation{
+		Allowed: true,
+		Action:  "dashboards:read",
+		Scope:   "dashboards:uid:abc",
+		ScopeResolution: []accesscontrol.ScopeResolution{
+			{Scope: "dashboards:uid:abc", Resolved: []string{"dashboards:uid:abc", "folders:uid:parent"}},
+		},
+		Permissions: []accesscontrol.PermissionSource{
+			{Action: "dashboards:read", Scope: "folders:uid:parent", Source: accesscontrol.PermissionSourceTeam, RoleName: "managed:teams:2:permissions", TeamID: 2},
+		},
+		Roles: []string{"managed:teams:2:permissions"},
+		Teams: []int64{2},
+	}
+
+	type testCase struct {
+		desc         string
+		url          string
+		permissions  []accesscontrol.Permission
+		userErr      error
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should explain the decision with users.permissions:read permission",
+			url:          "/api/access-control/users/2/simulate?action=dashboards:read&scope=dashboards:uid:abc",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersPermissionsRead, Scope: "users:id:2"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not explain the decision for other users",
+			url:          "/api/access-control/users/2/simulate?action=dashboards:read&scope=dashboards:uid:abc",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersPermissionsRead, Scope: "users:id:3"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should require an action",
+			url:          "/api/access-control/users/2/simulate?scope=dashboards:uid:abc",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersPermissionsRead, Scope: accesscontrol.ScopeUsersAll}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should return 404 for unknown users",
+			url:          "/api/access-control/users/2/simulate?action=dashboards:read",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersPermissionsRead, Scope: accesscontrol.ScopeUsersAll}},
+			userErr:      user.ErrUserNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.userService = &usertest.FakeUserService{
+					ExpectedSignedInUser: &user.SignedInUser{UserID: 2, OrgID: 1},
+					ExpectedError:        tt.userErr,
+				}
+				hs.policySimulator = actest.FakePolicySimulator{ExpectedSimulation: simulation}
+			})
+
+			req := server.NewGetRequest(tt.url)
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, tt.permissions)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.expectedCode == http.StatusOK {
+				var output accesscontrol.Simulation
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+				assert.Equal(t, *simulation, output)
+			}
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
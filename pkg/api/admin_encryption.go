@@ -51,6 +51,19 @@ func (hs *HTTPServer) AdminRollbackSecrets(c *contextmodel.ReqContext) response.
 	return response.Respond(http.StatusOK, "Secrets rolled back successfully")
 }
 
+func (hs *HTTPServer) AdminMigrateDataKeysToCurrentProvider(c *contextmodel.ReqContext) response.Response {
+	success, err := hs.secretsMigrator.ReEncryptDataKeys(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to re-encrypt data keys with the current provider", err)
+	}
+
+	if !success {
+		return response.Error(http.StatusPartialContent, "Something unexpected happened, refer to the server logs for more details", err)
+	}
+
+	return response.Respond(http.StatusOK, "Data encryption keys re-encrypted with the current provider successfully")
+}
+
 // To migrate to the plugin, it must be installed and configured
 // so as not to lose access to migrated secrets
 func (hs *HTTPServer) AdminMigrateSecretsToPlugin(c *contextmodel.ReqContext) response.Response {
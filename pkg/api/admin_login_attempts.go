@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /admin/lockouts admin adminListLockouts
+//
+// List the usernames, IP addresses and subnets that are locked out after too many failed logins.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminListLockoutsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminListLockouts(c *contextmodel.ReqContext) response.Response {
+	lockouts, err := hs.loginAttemptService.ListLockouts(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list lockouts", err)
+	}
+	return response.JSON(http.StatusOK, lockouts)
+}
+
+// swagger:route POST /admin/lockouts/clear admin adminClearLockout
+//
+// Remove the failed logins of a username, IP address or subnet, which ends its lockout.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminClearLockout(c *contextmodel.ReqContext) response.Response {
+	cmd := loginattempt.ClearLockoutCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if !cmd.Type.IsValid() {
+		return response.Error(http.StatusBadRequest, "type must be one of username, ip or subnet", nil)
+	}
+
+	if err := hs.loginAttemptService.ClearLockout(c.Req.Context(), &cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to clear lockout", err)
+	}
+	return response.Success("Lockout cleared")
+}
+
+// swagger:parameters adminClearLockout
+type AdminClearLockoutParams struct {
+	// in:body
+	// required:true
+	Body loginattempt.ClearLockoutCommand `json:"body"`
+}
+
+// swagger:response adminListLockoutsResponse
+type AdminListLockoutsResponse struct {
+	// in:body
+	Body []*loginattempt.Lockout `json:"body"`
+}
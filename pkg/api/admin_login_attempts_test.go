@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_AdminClearLockout(t *testing.T) {
+	type testCase struct {
+		desc         string
+		body         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+		expectClear  bool
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should clear lockout with users:write permission",
+			body:         `{"type": "ip", "key": "192.168.1.10"}`,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: accesscontrol.ScopeGlobalUsersAll}},
+			expectedCode: http.StatusOK,
+			expectClear:  true,
+		},
+		{
+			desc:         "should reject unknown lockout types",
+			body:         `{"type": "country", "key": "SE"}`,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: accesscontrol.ScopeGlobalUsersAll}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not clear lockout without permission",
+			body:         `{"type": "ip", "key": "192.168.1.10"}`,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersRead, Scope: accesscontrol.ScopeGlobalUsersAll}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			loginAttemptService := &loginattempttest.MockLoginAttemptService{}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.loginAttemptService = loginAttemptService
+			})
+
+			req := server.NewPostRequest("/api/admin/lockouts/clear", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, tt.permissions)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			assert.Equal(t, tt.expectClear, loginAttemptService.ClearLockoutCalled)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
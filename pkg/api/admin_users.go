@@ -17,6 +17,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
@@ -167,6 +168,10 @@ func (hs *HTTPServer) AdminUpdateUserPermissions(c *contextmodel.ReqContext) res
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), accesscontrol.GlobalOrgID, provenance.RecordTypeUser, strconv.FormatInt(userID, 10)); resp != nil {
+		return resp
+	}
+
 	err = hs.userService.UpdatePermissions(c.Req.Context(), userID, form.IsGrafanaAdmin)
 	if err != nil {
 		if errors.Is(err, user.ErrLastGrafanaAdmin) {
@@ -200,6 +205,10 @@ func (hs *HTTPServer) AdminDeleteUser(c *contextmodel.ReqContext) response.Respo
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), accesscontrol.GlobalOrgID, provenance.RecordTypeUser, strconv.FormatInt(userID, 10)); resp != nil {
+		return resp
+	}
+
 	cmd := user.DeleteUserCommand{UserID: userID}
 
 	if err := hs.userService.Delete(c.Req.Context(), &cmd); err != nil {
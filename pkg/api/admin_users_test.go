@@ -18,6 +18,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
@@ -235,10 +236,11 @@ func putAdminScenario(t *testing.T, desc string, url string, routePattern string
 	cmd dtos.AdminUpdateUserPermissionsForm, fn scenarioFunc, sqlStore db.DB, userSvc user.Service) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
 		hs := &HTTPServer{
-			Cfg:             setting.NewCfg(),
-			SQLStore:        sqlStore,
-			authInfoService: &logintest.AuthInfoServiceFake{},
-			userService:     userSvc,
+			provenanceService: provenance.NewFakeService(),
+			Cfg:               setting.NewCfg(),
+			SQLStore:          sqlStore,
+			authInfoService:   &logintest.AuthInfoServiceFake{},
+			userService:       userSvc,
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -262,8 +264,9 @@ func putAdminScenario(t *testing.T, desc string, url string, routePattern string
 func adminLogoutUserScenario(t *testing.T, desc string, url string, routePattern string, fn scenarioFunc, userService *usertest.FakeUserService) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
 		hs := HTTPServer{
-			AuthTokenService: authtest.NewFakeUserAuthTokenService(),
-			userService:      userService,
+			provenanceService: provenance.NewFakeService(),
+			AuthTokenService:  authtest.NewFakeUserAuthTokenService(),
+			userService:       userService,
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -289,8 +292,9 @@ func adminRevokeUserAuthTokenScenario(t *testing.T, desc string, url string, rou
 		fakeAuthTokenService := authtest.NewFakeUserAuthTokenService()
 
 		hs := HTTPServer{
-			AuthTokenService: fakeAuthTokenService,
-			userService:      userService,
+			provenanceService: provenance.NewFakeService(),
+			AuthTokenService:  fakeAuthTokenService,
+			userService:       userService,
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -317,8 +321,9 @@ func adminGetUserAuthTokensScenario(t *testing.T, desc string, url string, route
 		fakeAuthTokenService := authtest.NewFakeUserAuthTokenService()
 
 		hs := HTTPServer{
-			AuthTokenService: fakeAuthTokenService,
-			userService:      userService,
+			provenanceService: provenance.NewFakeService(),
+			AuthTokenService:  fakeAuthTokenService,
+			userService:       userService,
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -345,10 +350,11 @@ func adminDisableUserScenario(t *testing.T, desc string, action string, url stri
 		authInfoService := &logintest.AuthInfoServiceFake{}
 
 		hs := HTTPServer{
-			SQLStore:         dbtest.NewFakeDB(),
-			AuthTokenService: fakeAuthTokenService,
-			authInfoService:  authInfoService,
-			userService:      usertest.NewUserServiceFake(),
+			provenanceService: provenance.NewFakeService(),
+			SQLStore:          dbtest.NewFakeDB(),
+			AuthTokenService:  fakeAuthTokenService,
+			authInfoService:   authInfoService,
+			userService:       usertest.NewUserServiceFake(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -374,8 +380,9 @@ func adminDisableUserScenario(t *testing.T, desc string, action string, url stri
 
 func adminDeleteUserScenario(t *testing.T, desc string, url string, routePattern string, fn scenarioFunc) {
 	hs := HTTPServer{
-		SQLStore:    dbtest.NewFakeDB(),
-		userService: usertest.NewUserServiceFake(),
+		provenanceService: provenance.NewFakeService(),
+		SQLStore:          dbtest.NewFakeDB(),
+		userService:       usertest.NewUserServiceFake(),
 	}
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
 		sc := setupScenarioContext(t, url)
@@ -398,7 +405,8 @@ func adminDeleteUserScenario(t *testing.T, desc string, url string, routePattern
 func adminCreateUserScenario(t *testing.T, desc string, url string, routePattern string, cmd dtos.AdminCreateUserForm, svc *usertest.FakeUserService, fn scenarioFunc) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
 		hs := HTTPServer{
-			userService: svc,
+			provenanceService: provenance.NewFakeService(),
+			userService:       svc,
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -41,6 +41,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	folderarchiveapi "github.com/grafana/grafana/pkg/services/folderarchive/api"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginaccesscontrol"
 	publicdashboardsapi "github.com/grafana/grafana/pkg/services/publicdashboards/api"
@@ -75,7 +76,11 @@ func (hs *HTTPServer) registerRoutes() {
 	// not logged in views
 	r.Get("/logout", hs.Logout)
 	r.Post("/login", quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginPost))
+	r.Post("/login/mfa", quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginMFA))
+	r.Post("/login/mfa/webauthn", routing.Wrap(hs.LoginMFAWebAuthn))
+	r.Post("/login/mfa/totp", routing.Wrap(hs.LoginMFAEnrollTOTP))
 	r.Get("/login/:name", quota(string(auth.QuotaTargetSrv)), hs.OAuthLogin)
+	r.Post("/login/:name/backchannel-logout", routing.Wrap(hs.OAuthBackChannelLogout))
 	r.Get("/login", hs.LoginView)
 	r.Get("/invite/:code", hs.Index)
 
@@ -160,6 +165,7 @@ func (hs *HTTPServer) registerRoutes() {
 		r.Get("/public-dashboards/:accessToken",
 			publicdashboardsapi.SetPublicDashboardFlag,
 			publicdashboardsapi.SetPublicDashboardOrgIdOnContext(hs.PublicDashboardsApi.PublicDashboardService),
+			publicdashboardsapi.SetEmailSessionFromMagicLink(hs.PublicDashboardsApi.PublicDashboardService),
 			publicdashboardsapi.CountPublicDashboardRequest(),
 			hs.Index,
 		)
@@ -256,6 +262,16 @@ func (hs *HTTPServer) registerRoutes() {
 
 			userRoute.Get("/auth-tokens", routing.Wrap(hs.GetUserAuthTokens))
 			userRoute.Post("/revoke-auth-token", routing.Wrap(hs.RevokeUserAuthToken))
+
+			userRoute.Get("/mfa", routing.Wrap(hs.GetUserMFAStatus))
+			userRoute.Post("/mfa/totp", routing.Wrap(hs.EnrollUserTOTP))
+			userRoute.Post("/mfa/totp/activate", routing.Wrap(hs.ActivateUserTOTP))
+			userRoute.Post("/mfa/totp/disable", routing.Wrap(hs.DisableUserTOTP))
+			userRoute.Post("/mfa/recovery-codes", routing.Wrap(hs.RegenerateUserRecoveryCodes))
+			userRoute.Post("/mfa/webauthn/register", routing.Wrap(hs.BeginUserWebAuthnRegistration))
+			userRoute.Post("/mfa/webauthn", routing.Wrap(hs.FinishUserWebAuthnRegistration))
+			userRoute.Post("/mfa/challenge", routing.Wrap(hs.CreateUserMFAChallenge))
+			userRoute.Delete("/mfa/webauthn/:id", routing.Wrap(hs.DeleteUserWebAuthnCredential))
 		}, reqSignedInNoAnonymous)
 
 		apiRoute.Group("/users", func(usersRoute routing.RouteRegister) {
@@ -330,6 +346,10 @@ func (hs *HTTPServer) registerRoutes() {
 			orgRoute.Get("/preferences", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgsPreferencesRead)), routing.Wrap(hs.GetOrgPreferences))
 			orgRoute.Put("/preferences", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgsPreferencesWrite)), routing.Wrap(hs.UpdateOrgPreferences))
 			orgRoute.Patch("/preferences", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgsPreferencesWrite)), routing.Wrap(hs.PatchOrgPreferences))
+
+			// multi-factor authentication policy
+			orgRoute.Get("/mfa", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(hs.GetOrgMFAPolicy))
+			orgRoute.Put("/mfa", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(hs.UpdateOrgMFAPolicy))
 		})
 
 		// current org without requirement of user to be org admin
@@ -410,6 +430,7 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Get("/plugins/:pluginId/settings", routing.Wrap(hs.GetPluginSettingByID)) // RBAC check performed in handler for App Plugins
 		apiRoute.Get("/plugins/:pluginId/markdown/:name", routing.Wrap(hs.GetPluginMarkdown))
 		apiRoute.Get("/plugins/:pluginId/health", routing.Wrap(hs.CheckHealth))
+		apiRoute.Get("/plugins/:pluginId/process", authorize(reqOrgAdmin, ac.EvalPermission(pluginaccesscontrol.ActionWrite, pluginIDScope)), routing.Wrap(hs.GetPluginProcessHealth))
 		apiRoute.Any("/plugins/:pluginId/resources", authorize(reqSignedIn, ac.EvalPermission(pluginaccesscontrol.ActionAppAccess, pluginIDScope)), hs.CallResource)
 		apiRoute.Any("/plugins/:pluginId/resources/*", authorize(reqSignedIn, ac.EvalPermission(pluginaccesscontrol.ActionAppAccess, pluginIDScope)), hs.CallResource)
 		apiRoute.Get("/plugins/errors", routing.Wrap(hs.GetPluginErrorsList))
@@ -470,9 +491,15 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Group("/dashboards", func(dashboardRoute routing.RouteRegister) {
 			dashboardRoute.Get("/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(hs.GetDashboard))
 			dashboardRoute.Delete("/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsDelete)), routing.Wrap(hs.DeleteDashboardByUID))
+			dashboardRoute.Group("/trash", func(trashRoute routing.RouteRegister) {
+				trashRoute.Get("/", authorize(reqOrgAdmin, ac.EvalPermission(dashboards.ActionDashboardsDelete, dashboards.ScopeDashboardsAll)), routing.Wrap(hs.GetTrashedDashboards))
+				trashRoute.Post("/:uid/restore", authorize(reqOrgAdmin, ac.EvalPermission(dashboards.ActionDashboardsDelete, dashboards.ScopeDashboardsAll)), routing.Wrap(hs.RestoreTrashedDashboard))
+				trashRoute.Delete("/:uid", authorize(reqOrgAdmin, ac.EvalPermission(dashboards.ActionDashboardsDelete, dashboards.ScopeDashboardsAll)), routing.Wrap(hs.DeleteTrashedDashboard))
+			})
 			dashboardRoute.Group("/uid/:uid", func(dashUidRoute routing.RouteRegister) {
 				dashUidRoute.Get("/versions", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
 				dashUidRoute.Post("/restore", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
+				dashUidRoute.Post("/merge", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.MergeDashboard))
 				dashUidRoute.Get("/versions/:id", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
@@ -605,8 +632,12 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Get("/stats", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionServerStatsRead)), routing.Wrap(hs.AdminGetStats))
 		adminRoute.Post("/pause-all-alerts", reqGrafanaAdmin, routing.Wrap(hs.PauseAllAlerts(setting.AlertingEnabled)))
 
+		adminRoute.Get("/lockouts", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersRead, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminListLockouts))
+		adminRoute.Post("/lockouts/clear", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersWrite, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminClearLockout))
+
 		adminRoute.Post("/encryption/rotate-data-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminRotateDataEncryptionKeys))
 		adminRoute.Post("/encryption/reencrypt-data-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminReEncryptEncryptionKeys))
+		adminRoute.Post("/encryption/migrate-data-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateDataKeysToCurrentProvider))
 		adminRoute.Post("/encryption/reencrypt-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminReEncryptSecrets))
 		adminRoute.Post("/encryption/rollback-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminRollbackSecrets))
 		adminRoute.Post("/encryption/migrate-secrets/to-plugin", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateSecretsToPlugin))
@@ -618,6 +649,8 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/datasources/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersNotifications)), routing.Wrap(hs.AdminProvisioningReloadNotifications))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+		adminRoute.Post("/provisioning/access/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAccess)), routing.Wrap(hs.AdminProvisioningReloadAccess))
+		adminRoute.Post("/provisioning/library-elements/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersLibraryElements)), routing.Wrap(hs.AdminProvisioningReloadLibraryElements))
 	}, reqSignedIn)
 
 	// Administering users
@@ -636,8 +669,20 @@ func (hs *HTTPServer) registerRoutes() {
 		adminUserRoute.Post("/:id/logout", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersLogout, userIDScope)), routing.Wrap(hs.AdminLogoutUser))
 		adminUserRoute.Get("/:id/auth-tokens", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersAuthTokenList, userIDScope)), routing.Wrap(hs.AdminGetUserAuthTokens))
 		adminUserRoute.Post("/:id/revoke-auth-token", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersAuthTokenUpdate, userIDScope)), routing.Wrap(hs.AdminRevokeUserAuthToken))
+
+		adminUserRoute.Get("/:id/mfa", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersRead, userIDScope)), routing.Wrap(hs.AdminGetUserMFAStatus))
+		adminUserRoute.Delete("/:id/mfa", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersWrite, userIDScope)), routing.Wrap(hs.AdminResetUserMFA))
+	}, reqSignedIn)
+
+	// Access control policy simulation
+	r.Group("/api/access-control/users", func(acUserRoute routing.RouteRegister) {
+		userIDScope := ac.Scope("users", "id", ac.Parameter(":userId"))
+		acUserRoute.Get("/:userId/simulate", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionUsersPermissionsRead, userIDScope)), routing.Wrap(hs.SimulateUserAccess))
 	}, reqSignedIn)
 
+	// Folder export, import and bulk move
+	folderarchiveapi.New(hs.folderArchiveService, hs.AccessControl).RegisterAPIEndpoints(r)
+
 	// rendering
 	r.Get("/render/*", reqSignedIn, hs.RenderToPng)
 
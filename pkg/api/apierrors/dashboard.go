@@ -30,6 +30,11 @@ func ToDashboardErrorResponse(ctx context.Context, pluginStore plugins.Store, er
 		return response.Error(http.StatusBadRequest, err.Error(), nil)
 	}
 
+	var schemaErr dashboards.SchemaValidationError
+	if ok := errors.As(err, &schemaErr); ok {
+		return response.JSON(http.StatusUnprocessableEntity, util.DynMap{"status": "invalid-schema", "message": schemaErr.Error(), "errors": schemaErr.Errors})
+	}
+
 	var validationErr alerting.ValidationError
 	if ok := errors.As(err, &validationErr); ok {
 		return response.Error(http.StatusUnprocessableEntity, validationErr.Error(), err)
@@ -42,6 +42,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/login/logintest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/search"
@@ -255,11 +256,12 @@ func setupSimpleHTTPServer(features *featuremgmt.FeatureManager) *HTTPServer {
 	cfg.IsFeatureToggleEnabled = features.IsEnabled
 
 	return &HTTPServer{
-		Cfg:             cfg,
-		Features:        features,
-		License:         &licensing.OSSLicensingService{},
-		AccessControl:   acimpl.ProvideAccessControl(cfg),
-		annotationsRepo: annotationstest.NewFakeAnnotationsRepo(),
+		Cfg:               cfg,
+		Features:          features,
+		License:           &licensing.OSSLicensingService{},
+		AccessControl:     acimpl.ProvideAccessControl(cfg),
+		annotationsRepo:   annotationstest.NewFakeAnnotationsRepo(),
+		provenanceService: provenance.NewFakeService(),
 		authInfoService: &logintest.AuthInfoServiceFake{
 			ExpectedLabels: map[int64]string{int64(1): login.GetAuthProviderLabel(login.LDAPAuthModule)},
 		},
@@ -288,6 +290,7 @@ func SetupAPITestServer(t *testing.T, opts ...APITestServerOption) *webtest.Serv
 		Features:           featuremgmt.WithFeatures(),
 		QuotaService:       quotatest.New(false, nil),
 		searchUsersService: &searchusers.OSSService{},
+		provenanceService:  provenance.NewFakeService(),
 	}
 
 	for _, opt := range opts {
@@ -327,7 +330,7 @@ func (mss *mockSearchService) SortOptions() []model.SortOption { return nil }
 
 func setUp(confs ...setUpConf) *HTTPServer {
 	store := dbtest.NewFakeDB()
-	hs := &HTTPServer{SQLStore: store, SearchService: &mockSearchService{}}
+	hs := &HTTPServer{SQLStore: store, SearchService: &mockSearchService{}, provenanceService: provenance.NewFakeService()}
 
 	aclMockResp := []*dashboards.DashboardACLInfoDTO{}
 	for _, c := range confs {
@@ -456,7 +456,15 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		Overwrite: cmd.Overwrite,
 	}
 
-	dashboard, err := hs.DashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), dashItem, allowUiUpdate)
+	saveCtx := alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled())
+	dashboard, err := hs.DashboardService.SaveDashboard(saveCtx, dashItem, allowUiUpdate)
+	if errors.Is(err, dashboards.ErrDashboardVersionMismatch) && cmd.AutoMerge && !cmd.Overwrite {
+		var conflicts []dashdiffs.MergeConflict
+		dashboard, conflicts, err = hs.autoMergeDashboard(saveCtx, dashItem, allowUiUpdate)
+		if len(conflicts) > 0 {
+			return versionMismatchResponse(conflicts)
+		}
+	}
 
 	if hs.Live != nil {
 		// Tell everyone listening that the dashboard changed
@@ -957,7 +965,7 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *contextmodel.ReqContext) respons
 		return response.Error(500, "Unable to compute diff", err)
 	}
 
-	if options.DiffType == dashdiffs.DiffDelta {
+	if options.DiffType == dashdiffs.DiffDelta || options.DiffType == dashdiffs.DiffJSONPatch {
 		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
 	}
 
@@ -1181,7 +1189,8 @@ type CalcDashboardDiffParams struct {
 		// Description:
 		// * `basic`
 		// * `json`
-		// Enum: basic,json
+		// * `jsonpatch`: an RFC 6902 JSON Patch with a summary of the changes
+		// Enum: basic,json,jsonpatch
 		DiffType string `json:"diffType" binding:"Required"`
 	}
 }
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route POST /dashboards/uid/{uid}/merge dashboard_versions mergeDashboard
+//
+// Merge changes with the current version of a dashboard.
+//
+// Merges the changes made to a version of the dashboard with the changes saved since that version,
+// without saving the result. Values changed differently on both sides are reported as conflicts
+// and taken from the current version.
+//
+// Responses:
+// 200: mergeDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) MergeDashboard(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.MergeDashboardCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	base, err := hs.getDashboardVersionData(c.Req.Context(), dash, cmd.BaseVersion)
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard version not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Unable to merge dashboard", err)
+	}
+
+	merge, err := mergeWithDashboard(base, cmd.Dashboard, dash)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to merge dashboard", err)
+	}
+	changes, err := dashdiffs.CalculateJSONPatch(base, dash.Data)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to merge dashboard", err)
+	}
+
+	return response.JSON(http.StatusOK, dtos.MergeDashboardResult{
+		Merged:    merge.Merged,
+		Version:   dash.Version,
+		Conflicts: merge.Conflicts,
+		Changes:   changes.Summary,
+	})
+}
+
+// autoMergeDashboard retries saving a dashboard that was changed by someone else since its version
+// by merging both changes. The merge conflicts are returned when the changes cannot be merged.
+func (hs *HTTPServer) autoMergeDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*dashboards.Dashboard, []dashdiffs.MergeConflict, error) {
+	current, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{ID: dto.Dashboard.ID, UID: dto.Dashboard.UID, OrgID: dto.OrgID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	base, err := hs.getDashboardVersionData(ctx, current, dto.Dashboard.Version)
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			// nothing to merge with, the save fails as it would without merging
+			return nil, nil, dashboards.ErrDashboardVersionMismatch
+		}
+		return nil, nil, err
+	}
+
+	merge, err := mergeWithDashboard(base, dto.Dashboard.Data, current)
+	if err != nil {
+		return nil, nil, err
+	}
+	if merge.HasConflicts() {
+		return nil, merge.Conflicts, nil
+	}
+
+	merged := *dto
+	merged.Dashboard = dashboards.NewDashboardFromJson(merge.Merged)
+	merged.Dashboard.UpdatedBy = dto.Dashboard.UpdatedBy
+	merged.Dashboard.OrgID = dto.Dashboard.OrgID
+	merged.Dashboard.PluginID = dto.Dashboard.PluginID
+	merged.Dashboard.IsFolder = dto.Dashboard.IsFolder
+	merged.Dashboard.FolderID = dto.Dashboard.FolderID
+	merged.Dashboard.UpdateSlug()
+
+	dashboard, err := hs.DashboardService.SaveDashboard(ctx, &merged, allowUiUpdate)
+	return dashboard, nil, err
+}
+
+// getDashboardVersionData gets the data of a stored version of a dashboard.
+func (hs *HTTPServer) getDashboardVersionData(ctx context.Context, dash *dashboards.Dashboard, version int) (*simplejson.Json, error) {
+	if version == dash.Version {
+		return dash.Data, nil
+	}
+	res, err := hs.dashboardVersionService.Get(ctx, &dashver.GetDashboardVersionQuery{
+		DashboardID:  dash.ID,
+		DashboardUID: dash.UID,
+		Version:      version,
+		OrgID:        dash.OrgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// mergeWithDashboard merges the changes made to base with the current version of a dashboard.
+// The merged dashboard can be saved as the next version of current.
+func mergeWithDashboard(base, changed *simplejson.Json, current *dashboards.Dashboard) (*dashdiffs.MergeResult, error) {
+	result, err := dashdiffs.Merge(base, changed, current.Data)
+	if err != nil {
+		return nil, err
+	}
+	result.Merged.Set("id", current.ID)
+	result.Merged.Set("uid", current.UID)
+	result.Merged.Set("version", current.Version)
+	return result, nil
+}
+
+func versionMismatchResponse(conflicts []dashdiffs.MergeConflict) response.Response {
+	return response.JSON(http.StatusPreconditionFailed, util.DynMap{
+		"status":    "version-mismatch",
+		"message":   "The dashboard has been changed by someone else and the changes conflict",
+		"conflicts": conflicts,
+	})
+}
+
+// swagger:parameters mergeDashboard
+type MergeDashboardParams struct {
+	// in:body
+	// required:true
+	Body dtos.MergeDashboardCommand
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response mergeDashboardResponse
+type MergeDashboardResponse struct {
+	// in: body
+	Body dtos.MergeDashboardResult `json:"body"`
+}
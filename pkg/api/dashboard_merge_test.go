@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/registry/corekind"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestDashboardAutoMerge(t *testing.T) {
+	base := simplejson.NewFromAny(map[string]interface{}{
+		"id": 2, "uid": "uid", "title": "Dash", "version": 1,
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "title": "Latency"},
+		},
+	})
+	current := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+		"id": 2, "uid": "uid", "title": "Dash", "version": 2,
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "title": "Latency"},
+			map[string]interface{}{"id": 2, "title": "Errors"},
+		},
+	}))
+	current.OrgID = testOrgID
+
+	t.Run("Should save the merged dashboard when the changes do not conflict", func(t *testing.T) {
+		cmd := dashboards.SaveDashboardCommand{
+			OrgID:     testOrgID,
+			AutoMerge: true,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"id": 2, "uid": "uid", "title": "Renamed", "version": 1,
+				"panels": []interface{}{
+					map[string]interface{}{"id": 1, "title": "Latency"},
+				},
+			}),
+		}
+
+		var saved *dashboards.Dashboard
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(current, nil)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(nil, dashboards.ErrDashboardVersionMismatch).Once()
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Run(func(args mock.Arguments) {
+				saved = args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard
+			}).Return(&dashboards.Dashboard{ID: 2, UID: "uid", Title: "Renamed", Slug: "renamed", Version: 3}, nil).Once()
+
+		autoMergeScenario(t, cmd, dashboardService, base, func(sc *scenarioContext) {
+			callPostDashboard(sc)
+			require.Equal(t, http.StatusOK, sc.resp.Code)
+
+			require.NotNil(t, saved)
+			assert.Equal(t, 2, saved.Version)
+			assert.Equal(t, "Renamed", saved.Title)
+			assert.Len(t, saved.Data.Get("panels").MustArray(), 2)
+		})
+	})
+
+	t.Run("Should return the conflicts when the changes conflict", func(t *testing.T) {
+		cmd := dashboards.SaveDashboardCommand{
+			OrgID:     testOrgID,
+			AutoMerge: true,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"id": 2, "uid": "uid", "title": "Dash", "version": 1,
+				"panels": []interface{}{
+					map[string]interface{}{"id": 1, "title": "Request latency"},
+				},
+			}),
+		}
+		theirs := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+			"id": 2, "uid": "uid", "title": "Dash", "version": 2,
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "title": "P99 latency"},
+			},
+		}))
+		theirs.OrgID = testOrgID
+
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(theirs, nil)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(nil, dashboards.ErrDashboardVersionMismatch).Once()
+
+		autoMergeScenario(t, cmd, dashboardService, base, func(sc *scenarioContext) {
+			callPostDashboard(sc)
+			require.Equal(t, http.StatusPreconditionFailed, sc.resp.Code)
+
+			result := sc.ToJSON()
+			assert.Equal(t, "version-mismatch", result.Get("status").MustString())
+			conflicts := result.Get("conflicts").MustArray()
+			require.Len(t, conflicts, 1)
+			assert.Equal(t, "/panels/0/title", result.Get("conflicts").GetIndex(0).Get("path").MustString())
+		})
+	})
+}
+
+func autoMergeScenario(t *testing.T, cmd dashboards.SaveDashboardCommand, dashboardService dashboards.DashboardService, base *simplejson.Json, fn scenarioFunc) {
+	t.Helper()
+
+	dashboardVersionService := dashvertest.NewDashboardVersionServiceFake()
+	dashboardVersionService.ExpectedDashboardVersion = &dashver.DashboardVersionDTO{DashboardID: 2, Version: 1, Data: base}
+
+	hs := HTTPServer{
+		Cfg:                          setting.NewCfg(),
+		ProvisioningService:          provisioning.NewProvisioningServiceMock(context.Background()),
+		Live:                         newTestLive(t, db.InitTestDB(t)),
+		QuotaService:                 quotatest.New(false, nil),
+		pluginStore:                  &plugins.FakePluginStore{},
+		LibraryPanelService:          &mockLibraryPanelService{},
+		LibraryElementService:        &mockLibraryElementService{},
+		DashboardService:             dashboardService,
+		dashboardProvisioningService: mockDashboardProvisioningService{},
+		dashboardVersionService:      dashboardVersionService,
+		Features:                     featuremgmt.WithFeatures(),
+		Kinds:                        corekind.NewBase(nil),
+		accesscontrolService:         actest.FakeService{},
+	}
+
+	sc := setupScenarioContext(t, "/api/dashboards")
+	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+		c.Req.Body = mockRequestBody(cmd)
+		c.Req.Header.Add("Content-Type", "application/json")
+		sc.context = c
+		sc.context.SignedInUser = &user.SignedInUser{OrgID: testOrgID, UserID: testUserID}
+
+		return hs.PostDashboard(c)
+	})
+	sc.m.Post("/api/dashboards", sc.defaultHandler)
+
+	fn(sc)
+}
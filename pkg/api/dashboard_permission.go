@@ -13,6 +13,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -156,6 +157,10 @@ func (hs *HTTPServer) UpdateDashboardPermissions(c *contextmodel.ReqContext) res
 		return dashboardGuardianResponse(err)
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), c.OrgID, provenance.RecordTypeDashboardPermissions, dash.UID); resp != nil {
+		return resp
+	}
+
 	items := make([]*dashboards.DashboardACL, 0, len(apiCmd.Items))
 	for _, item := range apiCmd.Items {
 		items = append(items, &dashboards.DashboardACL{
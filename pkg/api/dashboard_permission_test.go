@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -48,11 +49,12 @@ func TestDashboardPermissionAPIEndpoint(t *testing.T) {
 		)
 		require.NoError(t, err)
 		hs := &HTTPServer{
-			Cfg:              settings,
-			SQLStore:         mockSQLStore,
-			Features:         features,
-			DashboardService: dashboardService,
-			AccessControl:    accesscontrolmock.New().WithDisabled(),
+			provenanceService: provenance.NewFakeService(),
+			Cfg:               settings,
+			SQLStore:          mockSQLStore,
+			Features:          features,
+			DashboardService:  dashboardService,
+			AccessControl:     accesscontrolmock.New().WithDisabled(),
 		}
 
 		t.Run("Given user has no admin permissions", func(t *testing.T) {
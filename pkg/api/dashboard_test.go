@@ -1364,6 +1364,10 @@ func (l *mockLibraryElementService) GetElement(c context.Context, signedInUser *
 	return model.LibraryElementDTO{}, nil
 }
 
+func (l *mockLibraryElementService) GetAllElements(c context.Context, signedInUser *user.SignedInUser, query model.SearchLibraryElementsQuery) (model.LibraryElementSearchResult, error) {
+	return model.LibraryElementSearchResult{}, nil
+}
+
 // GetElementsForDashboard gets all connected elements for a specific dashboard.
 func (l *mockLibraryElementService) GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]model.LibraryElementDTO, error) {
 	return map[string]model.LibraryElementDTO{}, nil
@@ -1383,3 +1387,28 @@ func (l *mockLibraryElementService) DisconnectElementsFromDashboard(c context.Co
 func (l *mockLibraryElementService) DeleteLibraryElementsInFolder(c context.Context, signedInUser *user.SignedInUser, folderUID string) error {
 	return nil
 }
+
+// PatchElement updates an element identified by UID.
+func (l *mockLibraryElementService) PatchElement(c context.Context, signedInUser *user.SignedInUser, cmd model.PatchLibraryElementCommand, UID string) (model.LibraryElementDTO, error) {
+	return model.LibraryElementDTO{}, nil
+}
+
+// DeleteElement deletes an element identified by UID and returns its ID.
+func (l *mockLibraryElementService) DeleteElement(c context.Context, signedInUser *user.SignedInUser, UID string) (int64, error) {
+	return 0, nil
+}
+
+// ListElementVersions lists the saved versions of an element, newest first.
+func (l *mockLibraryElementService) ListElementVersions(c context.Context, signedInUser *user.SignedInUser, UID string, query model.ListLibraryElementVersionsQuery) ([]model.LibraryElementVersionDTO, error) {
+	return []model.LibraryElementVersionDTO{}, nil
+}
+
+// GetElementVersion gets a saved version of an element.
+func (l *mockLibraryElementService) GetElementVersion(c context.Context, signedInUser *user.SignedInUser, UID string, version int64) (model.LibraryElementVersionDTO, error) {
+	return model.LibraryElementVersionDTO{}, nil
+}
+
+// RestoreElementVersion saves a previous version of an element as its newest version.
+func (l *mockLibraryElementService) RestoreElementVersion(c context.Context, signedInUser *user.SignedInUser, UID string, version int64) (model.LibraryElementDTO, error) {
+	return model.LibraryElementDTO{}, nil
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/trash dashboards getTrashedDashboards
+//
+// List deleted dashboards.
+//
+// Returns the dashboards of the current organization that were deleted and can still be restored,
+// most recently deleted first. Dashboards are deleted permanently once the trash retention elapses.
+//
+// Responses:
+// 200: getTrashedDashboardsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetTrashedDashboards(c *contextmodel.ReqContext) response.Response {
+	trashed, err := hs.DashboardService.GetTrashedDashboards(c.Req.Context(), &dashboards.GetTrashedDashboardsQuery{
+		OrgID: c.OrgID,
+		Limit: c.QueryInt("limit"),
+		Page:  c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get deleted dashboards", err)
+	}
+	return response.JSON(http.StatusOK, trashed)
+}
+
+// swagger:route POST /dashboards/trash/{uid}/restore dashboards restoreTrashedDashboard
+//
+// Restore a deleted dashboard.
+//
+// Brings back the dashboard with its permissions and version history. When the folder of the dashboard is in
+// the trash, the folder and its parent folders are restored as well. Dashboards whose folder was deleted
+// permanently are restored to the General folder. Restoring a folder restores the dashboards deleted with it.
+//
+// Responses:
+// 200: restoreTrashedDashboardResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreTrashedDashboard(c *contextmodel.ReqContext) response.Response {
+	dash, err := hs.DashboardService.RestoreDashboard(c.Req.Context(), &dashboards.RestoreDashboardCommand{
+		OrgID: c.OrgID,
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Deleted dashboard not found", err)
+		}
+		var dashboardErr dashboards.DashboardErr
+		if errors.As(err, &dashboardErr) {
+			return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to restore dashboard", err)
+	}
+
+	// library panels were disconnected when the dashboard was deleted
+	if err := hs.LibraryPanelService.ConnectLibraryPanelsForDashboard(c.Req.Context(), c.SignedInUser, dash); err != nil {
+		hs.log.Error("Failed to connect library panels", "dashboard", dash.UID, "error", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"id":      dash.ID,
+		"uid":     dash.UID,
+		"title":   dash.Title,
+		"url":     dashboards.GetDashboardURL(dash.UID, dash.Slug),
+		"message": fmt.Sprintf("Dashboard %s restored", dash.Title),
+	})
+}
+
+// swagger:route DELETE /dashboards/trash/{uid} dashboards deleteTrashedDashboard
+//
+// Delete a deleted dashboard permanently.
+//
+// Removes the dashboard from the trash along with its permissions and version history.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteTrashedDashboard(c *contextmodel.ReqContext) response.Response {
+	_, err := hs.DashboardService.PurgeTrashedDashboards(c.Req.Context(), &dashboards.PurgeTrashedDashboardsCommand{
+		OrgID: c.OrgID,
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Deleted dashboard not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard", err)
+	}
+	return response.Success("Dashboard deleted permanently")
+}
+
+// swagger:parameters getTrashedDashboards
+type GetTrashedDashboardsParams struct {
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+	// in:query
+	// required:false
+	Page int `json:"page"`
+}
+
+// swagger:parameters restoreTrashedDashboard deleteTrashedDashboard
+type TrashedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getTrashedDashboardsResponse
+type GetTrashedDashboardsResponse struct {
+	// in:body
+	Body []*dashboards.TrashedDashboard `json:"body"`
+}
+
+// swagger:response restoreTrashedDashboardResponse
+type RestoreTrashedDashboardResponse struct {
+	// in:body
+	Body struct {
+		// ID Identifier of the restored dashboard.
+		// required: true
+		// example: 65
+		ID int64 `json:"id"`
+		// UID Identifier of the restored dashboard.
+		// required: true
+		// example: jcIIG-07z
+		UID string `json:"uid"`
+		// Title of the restored dashboard.
+		// required: true
+		// example: My Dashboard
+		Title string `json:"title"`
+		// URL of the restored dashboard.
+		// required: true
+		// example: /d/jcIIG-07z/my-dashboard
+		URL string `json:"url"`
+		// Message Message of the restored dashboard.
+		// required: true
+		// example: Dashboard My Dashboard restored
+		Message string `json:"message"`
+	} `json:"body"`
+}
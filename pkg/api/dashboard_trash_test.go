@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestDashboardTrashAPI(t *testing.T) {
+	type testCase struct {
+		desc         string
+		method       string
+		url          string
+		permissions  []accesscontrol.Permission
+		serviceErr   error
+		expectedCode int
+	}
+
+	deleteAll := []accesscontrol.Permission{{Action: dashboards.ActionDashboardsDelete, Scope: dashboards.ScopeDashboardsAll}}
+
+	tests := []testCase{
+		{
+			desc:         "should list deleted dashboards",
+			method:       http.MethodGet,
+			url:          "/api/dashboards/trash",
+			permissions:  deleteAll,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not list deleted dashboards without delete permission on all dashboards",
+			method:       http.MethodGet,
+			url:          "/api/dashboards/trash",
+			permissions:  []accesscontrol.Permission{{Action: dashboards.ActionDashboardsDelete, Scope: "dashboards:uid:abc"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should restore a deleted dashboard",
+			method:       http.MethodPost,
+			url:          "/api/dashboards/trash/abc/restore",
+			permissions:  deleteAll,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should return 404 when restoring a dashboard not in the trash",
+			method:       http.MethodPost,
+			url:          "/api/dashboards/trash/abc/restore",
+			permissions:  deleteAll,
+			serviceErr:   dashboards.ErrDashboardNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "should return 412 when a dashboard with the same title exists",
+			method:       http.MethodPost,
+			url:          "/api/dashboards/trash/abc/restore",
+			permissions:  deleteAll,
+			serviceErr:   dashboards.ErrDashboardWithSameNameInFolderExists,
+			expectedCode: http.StatusPreconditionFailed,
+		},
+		{
+			desc:         "should delete a dashboard permanently",
+			method:       http.MethodDelete,
+			url:          "/api/dashboards/trash/abc",
+			permissions:  deleteAll,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			dashboardService := dashboards.NewFakeDashboardService(t)
+			dash := &dashboards.Dashboard{ID: 1, UID: "abc", Title: "Dash", Data: simplejson.New()}
+			switch tt.method {
+			case http.MethodGet:
+				dashboardService.On("GetTrashedDashboards", mock.Anything, mock.Anything).
+					Return([]*dashboards.TrashedDashboard{{ID: 1, UID: "abc", Title: "Dash"}}, tt.serviceErr).Maybe()
+			case http.MethodPost:
+				if tt.serviceErr != nil {
+					dash = nil
+				}
+				dashboardService.On("RestoreDashboard", mock.Anything, mock.Anything).Return(dash, tt.serviceErr).Maybe()
+			case http.MethodDelete:
+				dashboardService.On("PurgeTrashedDashboards", mock.Anything, mock.Anything).Return(int64(1), tt.serviceErr).Maybe()
+			}
+
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.DashboardService = dashboardService
+				hs.LibraryPanelService = &mockLibraryPanelService{}
+			})
+
+			req := server.NewRequest(tt.method, tt.url, nil)
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, tt.permissions)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
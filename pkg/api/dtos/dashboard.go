@@ -3,6 +3,7 @@ package dtos
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -76,3 +77,17 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type MergeDashboardCommand struct {
+	// BaseVersion is the version the changes were made to.
+	BaseVersion int              `json:"baseVersion" binding:"Required"`
+	Dashboard   *simplejson.Json `json:"dashboard" binding:"Required"`
+}
+
+type MergeDashboardResult struct {
+	Merged    *simplejson.Json          `json:"merged"`
+	Version   int                       `json:"version"`
+	Conflicts []dashdiffs.MergeConflict `json:"conflicts"`
+	// Changes describes the changes saved by others since the base version.
+	Changes []dashdiffs.ChangeSummary `json:"changes"`
+}
@@ -0,0 +1,17 @@
+package dtos
+
+type MFACodeForm struct {
+	Code string `json:"code" binding:"Required"`
+}
+
+type MFAChallengeForm struct {
+	Challenge string `json:"challenge" binding:"Required"`
+}
+
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type UpdateOrgMFAPolicyForm struct {
+	Required bool `json:"required"`
+}
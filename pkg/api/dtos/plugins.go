@@ -63,4 +63,6 @@ func (slice PluginList) Swap(i, j int) {
 
 type InstallPluginCommand struct {
 	Version string `json:"version"`
+	// DryRun returns the plan of the installation, with the plugins it depends on, without installing the plugin.
+	DryRun bool `json:"dryRun"`
 }
@@ -29,6 +29,12 @@ func (pm *fakePluginInstaller) Add(_ context.Context, pluginID, version string,
 	return nil
 }
 
+func (pm *fakePluginInstaller) Plan(_ context.Context, pluginID, version string, _ plugins.CompatOpts) (*plugins.InstallPlan, error) {
+	return &plugins.InstallPlan{PluginID: pluginID, Version: version, Steps: []plugins.InstallStep{
+		{PluginID: pluginID, Version: version, Action: plugins.InstallActionInstall},
+	}}, nil
+}
+
 func (pm *fakePluginInstaller) Remove(_ context.Context, pluginID string) error {
 	delete(pm.plugins, pluginID)
 	return nil
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -44,6 +45,7 @@ func TestFolderPermissionAPIEndpoint(t *testing.T) {
 	require.NoError(t, err)
 
 	hs := &HTTPServer{
+		provenanceService:           provenance.NewFakeService(),
 		Cfg:                         settings,
 		Features:                    features,
 		folderService:               folderService,
@@ -20,6 +20,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	loginservice "github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -270,6 +271,19 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 
 	usr = authQuery.User
 
+	// users signing in with a Grafana password may have to pass a second factor challenge first
+	if authModule == "grafana" {
+		challenge, err := hs.mfaService.Challenge(c.Req.Context(), usr.ID)
+		if err != nil {
+			resp = response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+			return resp
+		}
+		if challenge != nil {
+			resp = response.Err(mfa.NewChallengeRequiredError(challenge))
+			return resp
+		}
+	}
+
 	err = hs.loginUserWithUser(usr, c)
 	if err != nil {
 		var createTokenErr *auth.CreateTokenErr
@@ -12,6 +12,7 @@ import (
 
 	"golang.org/x/oauth2"
 
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/login"
@@ -34,6 +35,7 @@ var (
 const (
 	OauthStateCookieName = "oauth_state"
 	OauthPKCECookieName  = "oauth_code_verifier"
+	OauthNonceCookieName = "oauth_nonce"
 )
 
 func GenStateString() (string, error) {
@@ -97,6 +99,10 @@ func (hs *HTTPServer) OAuthLogin(ctx *contextmodel.ReqContext) {
 				cookies.WriteCookie(ctx.Resp, OauthPKCECookieName, pkce, hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
 			}
 
+			if nonce := redirect.Extra[authn.KeyOAuthNonce]; nonce != "" {
+				cookies.WriteCookie(ctx.Resp, OauthNonceCookieName, nonce, hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
+			}
+
 			cookies.WriteCookie(ctx.Resp, OauthStateCookieName, redirect.Extra[authn.KeyOAuthState], hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
 			ctx.Redirect(redirect.URL)
 			return
@@ -106,6 +112,7 @@ func (hs *HTTPServer) OAuthLogin(ctx *contextmodel.ReqContext) {
 		// NOTE: always delete these cookies, even if login failed
 		cookies.DeleteCookie(ctx.Resp, OauthPKCECookieName, hs.CookieOptionsFromCfg)
 		cookies.DeleteCookie(ctx.Resp, OauthStateCookieName, hs.CookieOptionsFromCfg)
+		cookies.DeleteCookie(ctx.Resp, OauthNonceCookieName, hs.CookieOptionsFromCfg)
 
 		if err != nil {
 			ctx.Redirect(hs.redirectURLWithErrorCookie(ctx, err))
@@ -160,6 +167,22 @@ func (hs *HTTPServer) OAuthLogin(ctx *contextmodel.ReqContext) {
 			return
 		}
 
+		// OpenID Connect providers include the nonce in the ID token, the cookie holds the unhashed value
+		if _, ok := connect.(social.OIDCConnector); ok {
+			nonce, err := GenStateString()
+			if err != nil {
+				ctx.Logger.Error("Generating nonce failed", "err", err)
+				hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+					HttpStatus:    http.StatusInternalServerError,
+					PublicMessage: "An internal error occurred",
+				})
+				return
+			}
+
+			cookies.WriteCookie(ctx.Resp, OauthNonceCookieName, nonce, hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
+			opts = append(opts, oauth2.SetAuthURLParam("nonce", hs.hashStatecode(nonce, provider.ClientSecret)))
+		}
+
 		hashedState := hs.hashStatecode(state, provider.ClientSecret)
 		cookies.WriteCookie(ctx.Resp, OauthStateCookieName, hashedState, hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
 		if provider.HostedDomain != "" {
@@ -227,6 +250,27 @@ func (hs *HTTPServer) OAuthLogin(ctx *contextmodel.ReqContext) {
 	// token.TokenType was defaulting to "bearer", which is out of spec, so we explicitly set to "Bearer"
 	token.TokenType = "Bearer"
 
+	nonce := ctx.GetCookie(OauthNonceCookieName)
+	cookies.DeleteCookie(ctx.Resp, OauthNonceCookieName, hs.CookieOptionsFromCfg)
+	if oidc, ok := connect.(social.OIDCConnector); ok {
+		if nonce == "" {
+			hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+				HttpStatus:    http.StatusBadRequest,
+				PublicMessage: "login.OAuthLogin(missing saved nonce)",
+			})
+			return
+		}
+
+		if _, err := oidc.VerifyIDToken(oauthCtx, token, hs.hashStatecode(nonce, provider.ClientSecret)); err != nil {
+			hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+				HttpStatus:    http.StatusUnauthorized,
+				PublicMessage: "login.OAuthLogin(invalid id token)",
+				Err:           err,
+			})
+			return
+		}
+	}
+
 	if hs.Cfg.Env != setting.Dev {
 		oauthLogger.Debug("OAuthLogin: got token",
 			"expiry", fmt.Sprintf("%v", token.Expiry),
@@ -366,6 +410,63 @@ func (hs *HTTPServer) SyncUser(
 	return upsertedUser, nil
 }
 
+// OAuthBackChannelLogout implements OpenID Connect back-channel logout. The identity provider posts a
+// signed logout token and all sessions of the user it identifies are revoked.
+func (hs *HTTPServer) OAuthBackChannelLogout(c *contextmodel.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+
+	connector, err := hs.SocialService.GetConnector(name)
+	if err != nil {
+		return response.Error(http.StatusNotFound, "OAuth provider not found", err)
+	}
+	oidc, ok := connector.(social.OIDCConnector)
+	if !ok {
+		return response.Error(http.StatusNotFound, "OAuth provider does not support back-channel logout", nil)
+	}
+
+	logoutToken := c.Req.PostFormValue("logout_token")
+	if logoutToken == "" {
+		return backChannelLogoutError("missing logout_token")
+	}
+
+	claims, err := oidc.VerifyLogoutToken(c.Req.Context(), logoutToken)
+	if err != nil {
+		oauthLogger.Warn("Rejected back-channel logout token", "provider", name, "error", err)
+		return backChannelLogoutError("invalid logout_token")
+	}
+
+	// sessions are not linked to the provider session id, so the subject is required
+	if claims.Subject == "" {
+		return backChannelLogoutError("logout_token without sub claim is not supported")
+	}
+
+	authInfo, err := hs.authInfoService.GetAuthInfo(c.Req.Context(), &loginservice.GetAuthInfoQuery{
+		AuthModule: fmt.Sprintf("oauth_%s", name),
+		AuthId:     claims.Subject,
+	})
+	if errors.Is(err, user.ErrUserNotFound) {
+		return response.Empty(http.StatusOK).SetHeader("Cache-Control", "no-store")
+	}
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user", err)
+	}
+
+	if err := hs.AuthTokenService.RevokeAllUserTokens(c.Req.Context(), authInfo.UserId); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to revoke user sessions", err)
+	}
+
+	oauthLogger.Info("Revoked sessions after back-channel logout", "provider", name, "userId", authInfo.UserId)
+	return response.Empty(http.StatusOK).SetHeader("Cache-Control", "no-store")
+}
+
+// backChannelLogoutError returns an OAuth error response as required by OpenID Connect back-channel logout
+func backChannelLogoutError(description string) response.Response {
+	return response.JSON(http.StatusBadRequest, map[string]string{
+		"error":             "invalid_request",
+		"error_description": description,
+	}).SetHeader("Cache-Control", "no-store")
+}
+
 func (hs *HTTPServer) hashStatecode(code, seed string) string {
 	hashBytes := sha256.Sum256([]byte(code + hs.Cfg.SecretKey + seed))
 	return hex.EncodeToString(hashBytes[:])
@@ -7,11 +7,13 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/login/social"
@@ -34,7 +36,7 @@ func setupSocialHTTPServerWithConfig(t *testing.T, cfg *setting.Cfg) *HTTPServer
 		Cfg:            cfg,
 		License:        &licensing.OSSLicensingService{Cfg: cfg},
 		SQLStore:       sqlStore,
-		SocialService:  social.ProvideService(cfg, features, &usagestats.UsageStatsMock{}, supportbundlestest.NewFakeBundleService()),
+		SocialService:  social.ProvideService(cfg, features, &usagestats.UsageStatsMock{}, supportbundlestest.NewFakeBundleService(), nil),
 		HooksService:   hooks.ProvideService(),
 		SecretsService: fakes.NewFakeSecretsService(),
 		Features:       features,
@@ -234,3 +236,42 @@ func TestOAuthLogin_BuildExternalUserInfo(t *testing.T) {
 		require.Equal(t, tc.expectedOrgRoles, externalUser.OrgRoles)
 	}
 }
+
+func TestOAuthBackChannelLogout(t *testing.T) {
+	setupBackChannelLogoutTest := func(t *testing.T, provider string) *web.Mux {
+		cfg := setting.NewCfg()
+		sec := cfg.Raw.Section("auth." + provider)
+		_, err := sec.NewKey("enabled", "true")
+		require.NoError(t, err)
+		_, err = sec.NewKey("issuer", "https://idp.example.com")
+		require.NoError(t, err)
+		hs := setupSocialHTTPServerWithConfig(t, cfg)
+
+		m := web.New()
+		m.Use(getContextHandler(t, cfg).Middleware)
+		m.Post("/login/:name/backchannel-logout", routing.Wrap(hs.OAuthBackChannelLogout))
+		return m
+	}
+
+	t.Run("should return not found for providers without OpenID Connect support", func(t *testing.T) {
+		m := setupBackChannelLogoutTest(t, "generic_oauth")
+		req := httptest.NewRequest(http.MethodPost, "/login/generic_oauth/backchannel-logout", strings.NewReader("logout_token=token"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+
+		m.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should reject requests without logout token", func(t *testing.T) {
+		m := setupBackChannelLogoutTest(t, "oidc")
+		req := httptest.NewRequest(http.MethodPost, "/login/oidc/backchannel-logout", strings.NewReader(""))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+
+		m.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+		assert.JSONEq(t, `{"error":"invalid_request","error_description":"missing logout_token"}`, recorder.Body.String())
+	})
+}
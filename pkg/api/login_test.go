@@ -28,6 +28,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/hooks"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	loginservice "github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
 	"github.com/grafana/grafana/pkg/services/navtree"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
@@ -605,6 +607,7 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 		AuthTokenService: authtest.NewFakeUserAuthTokenService(),
 		Features:         featuremgmt.WithFeatures(),
 		HooksService:     hookService,
+		mfaService:       &mfatest.FakeService{},
 	}
 
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
@@ -623,11 +626,12 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 	}
 
 	testCases := []struct {
-		desc       string
-		authUser   *user.User
-		authModule string
-		authErr    error
-		info       loginservice.LoginInfo
+		desc         string
+		authUser     *user.User
+		authModule   string
+		authErr      error
+		mfaChallenge *mfa.Challenge
+		info         loginservice.LoginInfo
 	}{
 		{
 			desc:    "invalid credentials",
@@ -657,6 +661,17 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 				HTTPStatus: 200,
 			},
 		},
+		{
+			desc:         "Grafana user with second factor",
+			authUser:     testUser,
+			authModule:   "grafana",
+			mfaChallenge: &mfa.Challenge{Token: "token", Methods: []string{mfa.MethodTOTP}},
+			info: loginservice.LoginInfo{
+				AuthModule: "grafana",
+				User:       testUser,
+				HTTPStatus: 401,
+			},
+		},
 		{
 			desc:       "valid LDAP user",
 			authUser:   testUser,
@@ -672,6 +687,7 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 	for _, c := range testCases {
 		t.Run(c.desc, func(t *testing.T) {
 			hs.authenticator = &fakeAuthenticator{c.authUser, c.authModule, c.authErr}
+			hs.mfaService = &mfatest.FakeService{ExpectedChallenge: c.mfaChallenge}
 			sc.m.Post(sc.url, sc.defaultHandler)
 			sc.fakeReqNoAssertions("POST", sc.url).exec()
 
@@ -679,7 +695,11 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 			assert.Equal(t, c.info.AuthModule, info.AuthModule)
 			assert.Equal(t, "admin", info.LoginUsername)
 			assert.Equal(t, c.info.HTTPStatus, info.HTTPStatus)
-			assert.Equal(t, c.info.Error, info.Error)
+			if c.mfaChallenge != nil {
+				assert.ErrorIs(t, info.Error, mfa.ErrChallengeRequired)
+			} else {
+				assert.Equal(t, c.info.Error, info.Error)
+			}
 
 			if c.info.User != nil {
 				require.NotEmpty(t, info.User)
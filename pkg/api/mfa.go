@@ -0,0 +1,437 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route POST /login/mfa mfa loginMFA
+//
+// Complete the second factor challenge returned when signing in with a password.
+//
+// A session is created for the user once the challenge is passed. If the user enrolled TOTP as part of the
+// challenge the response contains the new recovery codes.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) LoginMFA(c *contextmodel.ReqContext) response.Response {
+	if hs.Features.IsEnabled(featuremgmt.FlagAuthnService) {
+		req := &authn.Request{HTTPRequest: c.Req, Resp: c.Resp}
+		identity, err := hs.authnService.Login(c.Req.Context(), authn.ClientMFA, req)
+		if err != nil {
+			tokenErr := &auth.CreateTokenErr{}
+			if errors.As(err, &tokenErr) {
+				return response.Error(tokenErr.StatusCode, tokenErr.ExternalErr, tokenErr.InternalErr)
+			}
+			return response.Err(err)
+		}
+
+		var data map[string]interface{}
+		if codes := req.GetMeta(authn.MetaKeyRecoveryCodes); codes != "" {
+			data = map[string]interface{}{"recoveryCodes": strings.Split(codes, ",")}
+		}
+
+		metrics.MApiLoginPost.Inc()
+		return authn.HandleLoginResponseWithData(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo, data)
+	}
+
+	if hs.Cfg.DisableLoginForm {
+		return response.Error(http.StatusUnauthorized, "Login is disabled", nil)
+	}
+
+	cmd := mfa.VerifyChallengeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad verification data", err)
+	}
+	cmd.IPAddress = c.RemoteAddr()
+
+	result, err := hs.mfaService.VerifyChallenge(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	usr, err := hs.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: result.UserID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+	}
+
+	if err := hs.loginUserWithUser(usr, c); err != nil {
+		var createTokenErr *auth.CreateTokenErr
+		if errors.As(err, &createTokenErr) {
+			return response.Error(createTokenErr.StatusCode, createTokenErr.ExternalErr, createTokenErr.InternalErr)
+		}
+		return response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+	}
+
+	body := map[string]interface{}{
+		"message":     "Logged in",
+		"redirectUrl": hs.GetRedirectURL(c),
+	}
+	if len(result.RecoveryCodes) > 0 {
+		body["recoveryCodes"] = result.RecoveryCodes
+	}
+
+	metrics.MApiLoginPost.Inc()
+	return response.JSON(http.StatusOK, body)
+}
+
+// swagger:route POST /login/mfa/webauthn mfa loginMFAWebAuthn
+//
+// Get the options for signing a pending second factor challenge with a security key.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) LoginMFAWebAuthn(c *contextmodel.ReqContext) response.Response {
+	form := dtos.MFAChallengeForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	assertion, err := hs.mfaService.BeginChallengeWebAuthn(c.Req.Context(), form.Challenge)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, assertion)
+}
+
+// swagger:route POST /login/mfa/totp mfa loginMFAEnrollTOTP
+//
+// Enroll TOTP as part of a pending second factor challenge.
+//
+// Used by users that have to enroll a second factor before signing in. The challenge is completed with a code of the new secret.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) LoginMFAEnrollTOTP(c *contextmodel.ReqContext) response.Response {
+	form := dtos.MFAChallengeForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	enrollment, err := hs.mfaService.EnrollChallengeTOTP(c.Req.Context(), form.Challenge)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+// swagger:route GET /user/mfa signed_in_user getUserMFAStatus
+//
+// Second factors of the actual User.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetUserMFAStatus(c *contextmodel.ReqContext) response.Response {
+	return hs.getUserMFAStatusInternal(c, c.UserID)
+}
+
+// swagger:route POST /user/mfa/totp signed_in_user enrollUserTOTP
+//
+// Generate a new TOTP secret for the actual User.
+//
+// The secret is used once it is activated with a code from the authenticator app.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) EnrollUserTOTP(c *contextmodel.ReqContext) response.Response {
+	enrollment, err := hs.mfaService.EnrollTOTP(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+// swagger:route POST /user/mfa/totp/activate signed_in_user activateUserTOTP
+//
+// Activate the enrolled TOTP secret of the actual User and return the recovery codes.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ActivateUserTOTP(c *contextmodel.ReqContext) response.Response {
+	form := dtos.MFACodeForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	codes, err := hs.mfaService.ActivateTOTP(c.Req.Context(), c.UserID, form.Code)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, dtos.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// swagger:route POST /user/mfa/totp/disable signed_in_user disableUserTOTP
+//
+// Disable TOTP for the actual User, the recovery codes are removed as well.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) DisableUserTOTP(c *contextmodel.ReqContext) response.Response {
+	form := dtos.MFACodeForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if err := hs.mfaService.DisableTOTP(c.Req.Context(), c.UserID, form.Code); err != nil {
+		return response.Err(err)
+	}
+	return response.Success("TOTP disabled")
+}
+
+// swagger:route POST /user/mfa/recovery-codes signed_in_user regenerateUserRecoveryCodes
+//
+// Replace the recovery codes of the actual User.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) RegenerateUserRecoveryCodes(c *contextmodel.ReqContext) response.Response {
+	form := dtos.MFACodeForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	codes, err := hs.mfaService.RegenerateRecoveryCodes(c.Req.Context(), c.UserID, form.Code)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, dtos.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// swagger:route POST /user/mfa/webauthn/register signed_in_user beginUserWebAuthnRegistration
+//
+// Get the options for registering a new security key for the actual User.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) BeginUserWebAuthnRegistration(c *contextmodel.ReqContext) response.Response {
+	creation, err := hs.mfaService.BeginWebAuthnRegistration(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, creation)
+}
+
+// swagger:route POST /user/mfa/webauthn signed_in_user finishUserWebAuthnRegistration
+//
+// Register the security key created by the browser for the actual User.
+//
+// The body is the credential returned by navigator.credentials.create, the name of the key is set with the name query parameter.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) FinishUserWebAuthnRegistration(c *contextmodel.ReqContext) response.Response {
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		name = "Security key"
+	}
+
+	credential, err := hs.mfaService.FinishWebAuthnRegistration(c.Req.Context(), c.UserID, name, c.Req.Body)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, credential)
+}
+
+// swagger:route POST /user/mfa/challenge signed_in_user createUserMFAChallenge
+//
+// Start a second factor challenge for the actual User.
+//
+// The challenge is used to verify a security key, with the assertion returned by POST /login/mfa/webauthn, before
+// the second factors of the User are changed.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreateUserMFAChallenge(c *contextmodel.ReqContext) response.Response {
+	challenge, err := hs.mfaService.Challenge(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.Err(err)
+	}
+	if challenge == nil {
+		return response.Error(http.StatusNotFound, "User has no second factor", nil)
+	}
+	return response.JSON(http.StatusOK, challenge)
+}
+
+// swagger:route DELETE /user/mfa/webauthn/{id} signed_in_user deleteUserWebAuthnCredential
+//
+// Remove a security key of the actual User.
+//
+// The request has to prove a second factor of the User with a TOTP code, a recovery code or the assertion of a
+// security key for a challenge created with POST /user/mfa/challenge.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteUserWebAuthnCredential(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	cmd := mfa.VerifyFactorCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if err := hs.mfaService.DeleteWebAuthnCredential(c.Req.Context(), c.UserID, id, &cmd); err != nil {
+		return response.Err(err)
+	}
+	return response.Success("Security key removed")
+}
+
+// swagger:route GET /admin/users/{user_id}/mfa admin_users adminGetUserMFAStatus
+//
+// Return the second factors enrolled by the user.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminGetUserMFAStatus(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	return hs.getUserMFAStatusInternal(c, userID)
+}
+
+// swagger:route DELETE /admin/users/{user_id}/mfa admin_users adminResetUserMFA
+//
+// Remove all second factors of the user, e.g. after the user lost the authenticator app and recovery codes.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminResetUserMFA(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := hs.mfaService.Reset(c.Req.Context(), userID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reset second factors", err)
+	}
+	return response.Success("Second factors removed")
+}
+
+// swagger:route GET /org/mfa org getOrgMFAPolicy
+//
+// Get the second factor policy of the current organization.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetOrgMFAPolicy(c *contextmodel.ReqContext) response.Response {
+	policy, err := hs.mfaService.GetOrgPolicy(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get second factor policy", err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /org/mfa org updateOrgMFAPolicy
+//
+// Update the second factor policy of the current organization.
+//
+// When required, members signing in with a password have to pass a second factor challenge and
+// enroll a second factor if they don't have one.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateOrgMFAPolicy(c *contextmodel.ReqContext) response.Response {
+	form := dtos.UpdateOrgMFAPolicyForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if !hs.mfaService.IsEnabled() {
+		return response.Err(mfa.ErrNotEnabled.Errorf("multi-factor authentication is not enabled"))
+	}
+
+	if err := hs.mfaService.UpdateOrgPolicy(c.Req.Context(), &mfa.OrgPolicy{OrgID: c.OrgID, Required: form.Required}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to update second factor policy", err)
+	}
+	return response.Success("Second factor policy updated")
+}
+
+func (hs *HTTPServer) getUserMFAStatusInternal(c *contextmodel.ReqContext, userID int64) response.Response {
+	status, err := hs.mfaService.GetStatus(c.Req.Context(), userID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get second factors", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
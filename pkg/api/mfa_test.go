@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_AdminResetUserMFA(t *testing.T) {
+	type testCase struct {
+		desc         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+		expectReset  bool
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should reset second factors with users:write permission",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: "global.users:id:2"}},
+			expectedCode: http.StatusOK,
+			expectReset:  true,
+		},
+		{
+			desc:         "should not reset second factors of other users",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersWrite, Scope: "global.users:id:3"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not reset second factors without permission",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersRead, Scope: "global.users:id:2"}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mfaService := &fakeResetMFAService{}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.mfaService = mfaService
+			})
+
+			res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/admin/users/2/mfa", nil), userWithPermissions(1, tt.permissions)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+
+			if tt.expectReset {
+				assert.Equal(t, []int64{2}, mfaService.resetUserIDs)
+			} else {
+				assert.Empty(t, mfaService.resetUserIDs)
+			}
+		})
+	}
+}
+
+func TestAPI_UpdateOrgMFAPolicy(t *testing.T) {
+	type testCase struct {
+		desc         string
+		enabled      bool
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should update policy with orgs:write permission",
+			enabled:      true,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsWrite}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not update policy without permission",
+			enabled:      true,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsRead}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not update policy when multi-factor authentication is disabled",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionOrgsWrite}},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.mfaService = &mfatest.FakeService{ExpectedEnabled: tt.enabled}
+			})
+
+			req := server.NewRequest(http.MethodPut, "/api/org/mfa", strings.NewReader(`{"required": true}`))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, tt.permissions)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+type fakeResetMFAService struct {
+	mfatest.FakeService
+	resetUserIDs []int64
+}
+
+func (f *fakeResetMFAService) Reset(_ context.Context, userID int64) error {
+	f.resetUserIDs = append(f.resetUserIDs, userID)
+	return nil
+}
+
+var _ mfa.Service = new(fakeResetMFAService)
@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/repo"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -432,6 +433,21 @@ func (hs *HTTPServer) CheckHealth(c *contextmodel.ReqContext) response.Response
 	return response.JSON(http.StatusOK, payload)
 }
 
+// GetPluginProcessHealth returns the state, restarts and resource limits of the process of a backend plugin.
+func (hs *HTTPServer) GetPluginProcessHealth(c *contextmodel.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+
+	health, err := hs.pluginProcessManager.Health(c.Req.Context(), pluginID)
+	if err != nil {
+		if errors.Is(err, process.ErrProcessNotFound) {
+			return response.Error(http.StatusNotFound, "Plugin process not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get plugin process health", err)
+	}
+
+	return response.JSON(http.StatusOK, health)
+}
+
 func (hs *HTTPServer) GetPluginErrorsList(_ *contextmodel.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, hs.pluginErrorResolver.PluginErrors())
 }
@@ -442,43 +458,72 @@ func (hs *HTTPServer) InstallPlugin(c *contextmodel.ReqContext) response.Respons
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	pluginID := web.Params(c.Req)[":pluginId"]
-
-	err := hs.pluginInstaller.Add(c.Req.Context(), pluginID, dto.Version, plugins.CompatOpts{
+	compatOpts := plugins.CompatOpts{
 		GrafanaVersion: hs.Cfg.BuildVersion,
 		OS:             runtime.GOOS,
 		Arch:           runtime.GOARCH,
-	})
-	if err != nil {
-		var dupeErr plugins.DuplicateError
-		if errors.As(err, &dupeErr) {
-			return response.Error(http.StatusConflict, "Plugin already installed", err)
-		}
-		var versionUnsupportedErr repo.ErrVersionUnsupported
-		if errors.As(err, &versionUnsupportedErr) {
-			return response.Error(http.StatusConflict, "Plugin version not supported", err)
-		}
-		var versionNotFoundErr repo.ErrVersionNotFound
-		if errors.As(err, &versionNotFoundErr) {
-			return response.Error(http.StatusNotFound, "Plugin version not found", err)
-		}
-		var clientError repo.Response4xxError
-		if errors.As(err, &clientError) {
-			return response.Error(clientError.StatusCode, clientError.Message, err)
-		}
-		if errors.Is(err, plugins.ErrInstallCorePlugin) {
-			return response.Error(http.StatusForbidden, "Cannot install or change a Core plugin", err)
-		}
-		var archError repo.ErrArcNotFound
-		if errors.As(err, &archError) {
-			return response.Error(http.StatusNotFound, archError.Error(), nil)
+	}
+
+	if dto.DryRun {
+		plan, err := hs.pluginInstaller.Plan(c.Req.Context(), pluginID, dto.Version, compatOpts)
+		if err != nil {
+			return installPluginErrorResponse(err)
 		}
+		return response.JSON(http.StatusOK, plan)
+	}
 
-		return response.Error(http.StatusInternalServerError, "Failed to install plugin", err)
+	err := hs.pluginInstaller.Add(c.Req.Context(), pluginID, dto.Version, compatOpts)
+	if err != nil {
+		return installPluginErrorResponse(err)
 	}
 
 	return response.JSON(http.StatusOK, []byte{})
 }
 
+func installPluginErrorResponse(err error) response.Response {
+	var dupeErr plugins.DuplicateError
+	if errors.As(err, &dupeErr) {
+		return response.Error(http.StatusConflict, "Plugin already installed", err)
+	}
+	var versionUnsupportedErr repo.ErrVersionUnsupported
+	if errors.As(err, &versionUnsupportedErr) {
+		return response.Error(http.StatusConflict, "Plugin version not supported", err)
+	}
+	var versionNotFoundErr repo.ErrVersionNotFound
+	if errors.As(err, &versionNotFoundErr) {
+		return response.Error(http.StatusNotFound, "Plugin version not found", err)
+	}
+	var pluginNotFoundErr repo.ErrPluginNotFound
+	if errors.As(err, &pluginNotFoundErr) {
+		return response.Error(http.StatusNotFound, "Plugin not found", err)
+	}
+	var checksumMissingErr repo.ErrChecksumMissing
+	if errors.As(err, &checksumMissingErr) {
+		return response.Error(http.StatusUnprocessableEntity, checksumMissingErr.Error(), nil)
+	}
+	var clientError repo.Response4xxError
+	if errors.As(err, &clientError) {
+		return response.Error(clientError.StatusCode, clientError.Message, err)
+	}
+	if errors.Is(err, plugins.ErrInstallCorePlugin) {
+		return response.Error(http.StatusForbidden, "Cannot install or change a Core plugin", err)
+	}
+	var archError repo.ErrArcNotFound
+	if errors.As(err, &archError) {
+		return response.Error(http.StatusNotFound, archError.Error(), nil)
+	}
+	var conflictErr repo.ErrDependencyConflict
+	if errors.As(err, &conflictErr) {
+		return response.Error(http.StatusConflict, conflictErr.Error(), nil)
+	}
+	var grafanaVersionErr repo.ErrGrafanaVersionUnsupported
+	if errors.As(err, &grafanaVersionErr) {
+		return response.Error(http.StatusConflict, grafanaVersionErr.Error(), nil)
+	}
+
+	return response.Error(http.StatusInternalServerError, "Failed to install plugin", err)
+}
+
 func (hs *HTTPServer) UninstallPlugin(c *contextmodel.ReqContext) response.Response {
 	pluginID := web.Params(c.Req)[":pluginId"]
 
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
 	"github.com/grafana/grafana/pkg/plugins/manager/filestore"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
 	"github.com/grafana/grafana/pkg/plugins/manager/store"
 	"github.com/grafana/grafana/pkg/plugins/pluginscdn"
@@ -110,6 +111,66 @@ func Test_PluginsInstallAndUninstall(t *testing.T) {
 	}
 }
 
+func Test_PluginsInstallDryRun(t *testing.T) {
+	inst := NewFakePluginInstaller()
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = &setting.Cfg{PluginAdminEnabled: true}
+		hs.pluginInstaller = inst
+		hs.QuotaService = quotatest.New(false, nil)
+	})
+
+	req := srv.NewPostRequest("/api/plugins/test/install", strings.NewReader(`{"version": "1.0.2", "dryRun": true}`))
+	webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor, IsGrafanaAdmin: true})
+	resp, err := srv.SendJSON(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var plan plugins.InstallPlan
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&plan))
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "1.0.2", plan.Version)
+	require.Len(t, plan.Steps, 1)
+	require.Empty(t, inst.plugins)
+}
+
+func Test_PluginProcessHealth(t *testing.T) {
+	pm := fakes.NewFakeProcessManager()
+	pm.HealthFunc = func(_ context.Context, pluginID string) (process.Health, error) {
+		if pluginID != "test-datasource" {
+			return process.Health{}, process.ErrProcessNotFound
+		}
+		return process.Health{PluginID: pluginID, State: process.StateFailed, Restarts: 3, LastExitReason: process.ExitReasonOOMKilled}, nil
+	}
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.pluginProcessManager = pm
+	})
+
+	canWrite := []ac.Permission{{Action: pluginaccesscontrol.ActionWrite, Scope: pluginaccesscontrol.ScopeProvider.GetResourceScope("test-datasource")}}
+
+	resp, err := srv.Send(webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/plugins/test-datasource/process"), userWithPermissions(1, canWrite)))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var health process.Health
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, process.StateFailed, health.State)
+	require.Equal(t, 3, health.Restarts)
+	require.Equal(t, process.ExitReasonOOMKilled, health.LastExitReason)
+
+	resp, err = srv.Send(webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/plugins/other-datasource/process"), userWithPermissions(1, canWrite)))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	resp, err = srv.Send(webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/plugins/other-datasource/process"), userWithPermissions(1, []ac.Permission{
+		{Action: pluginaccesscontrol.ActionWrite, Scope: pluginaccesscontrol.ScopeProvider.GetResourceAllScope()},
+	})))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
 func Test_PluginsInstallAndUninstall_AccessControl(t *testing.T) {
 	canInstall := []ac.Permission{{Action: pluginaccesscontrol.ActionInstall}}
 	cannotInstall := []ac.Permission{{Action: "plugins:cannotinstall"}}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+// checkProvenance returns an error response when the record is provisioned from a file, nil otherwise.
+func (hs *HTTPServer) checkProvenance(ctx context.Context, orgID int64, recordType, recordKey string) response.Response {
+	if err := provenance.CheckWritable(ctx, hs.provenanceService, orgID, recordType, recordKey); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to check provenance", err)
+	}
+	return nil
+}
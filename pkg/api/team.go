@@ -10,6 +10,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
@@ -96,6 +97,10 @@ func (hs *HTTPServer) UpdateTeam(c *contextmodel.ReqContext) response.Response {
 		}
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), cmd.OrgID, provenance.RecordTypeTeam, strconv.FormatInt(cmd.ID, 10)); resp != nil {
+		return resp
+	}
+
 	if err := hs.teamService.UpdateTeam(c.Req.Context(), &cmd); err != nil {
 		if errors.Is(err, team.ErrTeamNameTaken) {
 			return response.Error(400, "Team name taken", err)
@@ -130,6 +135,10 @@ func (hs *HTTPServer) DeleteTeamByID(c *contextmodel.ReqContext) response.Respon
 		}
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), orgID, provenance.RecordTypeTeam, strconv.FormatInt(teamID, 10)); resp != nil {
+		return resp
+	}
+
 	if err := hs.teamService.DeleteTeam(c.Req.Context(), &team.DeleteTeamCommand{OrgID: orgID, ID: teamID}); err != nil {
 		if errors.Is(err, team.ErrTeamNotFound) {
 			return response.Error(404, "Failed to delete Team. ID not found", nil)
@@ -13,6 +13,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
@@ -97,6 +98,10 @@ func (hs *HTTPServer) AddTeamMember(c *contextmodel.ReqContext) response.Respons
 		}
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), cmd.OrgID, provenance.RecordTypeTeam, strconv.FormatInt(cmd.TeamID, 10)); resp != nil {
+		return resp
+	}
+
 	isTeamMember, err := hs.teamService.IsTeamMember(c.OrgID, cmd.TeamID, cmd.UserID)
 	if err != nil {
 		return response.Error(500, "Failed to add team member.", err)
@@ -146,6 +151,10 @@ func (hs *HTTPServer) UpdateTeamMember(c *contextmodel.ReqContext) response.Resp
 		}
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), orgId, provenance.RecordTypeTeam, strconv.FormatInt(teamId, 10)); resp != nil {
+		return resp
+	}
+
 	isTeamMember, err := hs.teamService.IsTeamMember(orgId, teamId, userId)
 	if err != nil {
 		return response.Error(500, "Failed to update team member.", err)
@@ -198,6 +207,10 @@ func (hs *HTTPServer) RemoveTeamMember(c *contextmodel.ReqContext) response.Resp
 		}
 	}
 
+	if resp := hs.checkProvenance(c.Req.Context(), orgId, provenance.RecordTypeTeam, strconv.FormatInt(teamId, 10)); resp != nil {
+		return resp
+	}
+
 	teamIDString := strconv.FormatInt(teamId, 10)
 	if _, err := hs.teamPermissionsService.SetUserPermission(c.Req.Context(), orgId, accesscontrol.User{ID: userId}, teamIDString, ""); err != nil {
 		if errors.Is(err, team.ErrTeamNotFound) {
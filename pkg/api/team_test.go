@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/team/teamimpl"
 	"github.com/grafana/grafana/pkg/services/team/teamtest"
@@ -351,6 +352,44 @@ func TestTeamAPIEndpoint_UpdateTeam_RBAC(t *testing.T) {
 	})
 }
 
+func TestTeamAPIEndpoint_ProvisionedTeam(t *testing.T) {
+	provenances := provenance.NewFakeService()
+	require.NoError(t, provenances.SetProvenance(context.Background(), 1, provenance.RecordTypeTeam, "1", provenance.ProvenanceFile))
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.teamService = &teamtest.FakeService{ExpectedTeamDTO: &team.TeamDTO{}}
+		hs.provenanceService = provenances
+	})
+	admin := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsWrite, Scope: "teams:*"},
+		{Action: accesscontrol.ActionTeamsDelete, Scope: "teams:*"},
+	})
+
+	t.Run("Should not update a provisioned team", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPut, fmt.Sprintf(detailTeamURL, 1), strings.NewReader(teamCmd)), admin)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should not delete a provisioned team", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, fmt.Sprintf(detailTeamURL, 1), http.NoBody), admin)
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should update a team that is not provisioned", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPut, fmt.Sprintf(detailTeamURL, 2), strings.NewReader(teamCmd)), admin)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 // Given a team with a user, when the user is granted X permission,
 // Then the endpoint should return 200 if the user has accesscontrol.ActionTeamsDelete with teams:id:1 scope
 // else return 403
@@ -26,6 +26,7 @@ import (
 	authinfostore "github.com/grafana/grafana/pkg/services/login/authinfoservice/database"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/searchusers"
 	"github.com/grafana/grafana/pkg/services/searchusers/filters"
@@ -42,9 +43,10 @@ func TestUserAPIEndpoint_userLoggedIn(t *testing.T) {
 	settings := setting.NewCfg()
 	sqlStore := db.InitTestDB(t)
 	hs := &HTTPServer{
-		Cfg:           settings,
-		SQLStore:      sqlStore,
-		AccessControl: acmock.New(),
+		provenanceService: provenance.NewFakeService(),
+		Cfg:               settings,
+		SQLStore:          sqlStore,
+		AccessControl:     acmock.New(),
 	}
 
 	mockResult := user.SearchUserQueryResult{
@@ -217,9 +219,10 @@ func TestHTTPServer_UpdateUser(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 
 	hs := &HTTPServer{
-		Cfg:           settings,
-		SQLStore:      sqlStore,
-		AccessControl: acmock.New(),
+		provenanceService: provenance.NewFakeService(),
+		Cfg:               settings,
+		SQLStore:          sqlStore,
+		AccessControl:     acmock.New(),
 	}
 
 	updateUserCommand := user.UpdateUserCommand{
@@ -278,9 +281,10 @@ func TestHTTPServer_UpdateSignedInUser(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 
 	hs := &HTTPServer{
-		Cfg:           settings,
-		SQLStore:      sqlStore,
-		AccessControl: acmock.New(),
+		provenanceService: provenance.NewFakeService(),
+		Cfg:               settings,
+		SQLStore:          sqlStore,
+		AccessControl:     acmock.New(),
 	}
 
 	updateUserCommand := user.UpdateUserCommand{
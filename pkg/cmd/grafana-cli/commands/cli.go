@@ -42,10 +42,15 @@ func CLICommand(version string) *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:    "repo",
-				Usage:   "URL to the plugin repository",
+				Usage:   "URL to the plugin repository, or comma-separated list of repositories in order of priority as [type+]url, where type is grafana_com (default), index or oci",
 				Value:   "https://grafana.com/api/plugins",
 				EnvVars: []string{"GF_PLUGIN_REPO"},
 			},
+			&cli.StringFlag{
+				Name:    "repoToken",
+				Usage:   "Bearer token sent to the plugin repositories, for private repositories",
+				EnvVars: []string{"GF_PLUGIN_REPO_TOKEN"},
+			},
 			&cli.StringFlag{
 				Name:    "pluginUrl",
 				Usage:   "Full url to the plugin zip file instead of downloading the plugin from grafana.com/api",
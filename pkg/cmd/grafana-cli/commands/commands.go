@@ -95,6 +95,13 @@ var pluginCommands = []*cli.Command{
 		Name:   "install",
 		Usage:  "install <plugin id> <plugin version (optional)>",
 		Action: runPluginCommand(installCommand),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print the installation plan, with the plugins the plugin depends on, without installing",
+				Value: false,
+			},
+		},
 	}, {
 		Name:   "list-remote",
 		Usage:  "list remote available plugins",
@@ -173,6 +180,11 @@ var adminCommands = []*cli.Command{
 				Usage:  "Rotates persisted data encryption keys. Returns ok unless there is an error. Safe to execute multiple times.",
 				Action: runRunnerCommand(secretsmigrations.ReEncryptDEKS),
 			},
+			{
+				Name:   "migrate-data-keys",
+				Usage:  "Re-encrypts data encryption keys encrypted by a previous encryption provider with the current one, so the previous provider can be removed. Returns ok unless there is an error. Safe to execute multiple times.",
+				Action: runRunnerCommand(secretsmigrations.MigrateDataKeys),
+			},
 		},
 	},
 	{
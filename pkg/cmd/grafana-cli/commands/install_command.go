@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/repo"
 	"github.com/grafana/grafana/pkg/plugins/storage"
 )
@@ -58,54 +59,168 @@ func installCommand(c utils.CommandLine) error {
 	pluginID := c.Args().First()
 	version := c.Args().Get(1)
 	err := installPlugin(context.Background(), pluginID, version, c)
-	if err == nil {
+	if err == nil && !c.Bool("dry-run") {
 		logRestartNotice()
 	}
 	return err
 }
 
-// installPlugin downloads the plugin code as a zip file from the Grafana.com API
-// and then extracts the zip into the plugin's directory.
+// installPlugin downloads the plugin code as a zip file from the plugin repositories, with the plugins it
+// depends on, and then extracts the zip files into the plugin directories.
 func installPlugin(ctx context.Context, pluginID, version string, c utils.CommandLine) error {
-	skipTLSVerify := c.Bool("insecure")
-	repository := repo.New(skipTLSVerify, c.PluginRepoURL(), services.Logger)
+	repository, err := newPluginRepository(c)
+	if err != nil {
+		return err
+	}
 
 	compatOpts := repo.NewCompatOpts(services.GrafanaVersion, runtime.GOOS, runtime.GOARCH)
+	resolver := repo.NewResolver(repository, services.Logger)
+	pluginFs := storage.FileSystem(services.Logger, c.PluginDirectory())
+	// the archives downloaded by the resolver to read dependencies are not downloaded again
+	archives := repo.NewArchiveCache()
+	defer func() {
+		if err := archives.Close(); err != nil {
+			logger.Warnf("Failed to close plugin archives: %s\n", err)
+		}
+	}()
 
-	var archive *repo.PluginArchive
-	var err error
 	pluginZipURL := c.PluginURL()
 	if pluginZipURL != "" {
-		if archive, err = repository.GetPluginArchiveByURL(ctx, pluginZipURL, compatOpts); err != nil {
+		archive, err := repository.GetPluginArchiveByURL(ctx, pluginZipURL, compatOpts)
+		if err != nil {
 			return err
 		}
-	} else {
-		if archive, err = repository.GetPluginArchive(ctx, pluginID, version, compatOpts); err != nil {
+		extractedArchive, err := pluginFs.Extract(ctx, pluginID, archive.File)
+		if err != nil {
 			return err
 		}
+
+		// the dependencies of the plugin are resolved against the installed plugins, including the plugin
+		for _, dep := range extractedArchive.Dependencies {
+			plan, err := resolver.Resolve(ctx, dep.ID, "", installedPlugins(c.PluginDirectory()), compatOpts, archives)
+			if errors.Is(err, plugins.DuplicateError{}) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%v: %w", fmt.Sprintf("failed to resolve dependency %s of %s", dep.ID, pluginID), err)
+			}
+			if err := applyInstallPlan(ctx, plan, repository, archives, pluginFs, compatOpts); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	pluginFs := storage.FileSystem(services.Logger, c.PluginDirectory())
-	extractedArchive, err := pluginFs.Extract(ctx, pluginID, archive.File)
+	plan, err := resolver.Resolve(ctx, pluginID, version, installedPlugins(c.PluginDirectory()), compatOpts, archives)
+	if errors.Is(err, plugins.DuplicateError{}) {
+		logger.Infof("%s is already installed\n", pluginID)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	for _, dep := range extractedArchive.Dependencies {
-		services.Logger.Infof("Fetching %s dependency...", dep.ID)
-		d, err := repository.GetPluginArchive(ctx, dep.ID, dep.Version, compatOpts)
+	logInstallPlan(plan)
+	if c.Bool("dry-run") {
+		return nil
+	}
+	return applyInstallPlan(ctx, plan, repository, archives, pluginFs, compatOpts)
+}
+
+// applyInstallPlan downloads and extracts the archives of the plan to a staging directory before
+// installing them, so that a failed download or extraction leaves the installed plugins unchanged.
+func applyInstallPlan(ctx context.Context, plan *plugins.InstallPlan, repository repo.Service, archives *repo.ArchiveCache,
+	pluginFs storage.StagingZipExtractor, compatOpts repo.CompatOpts) error {
+	var staged []*storage.ExtractedPluginArchive
+	discard := func() {
+		for _, s := range staged {
+			if err := pluginFs.Discard(ctx, s); err != nil {
+				logger.Warnf("Failed to discard staged plugin %s: %s\n", s.ID, err)
+			}
+		}
+	}
+	for _, step := range plan.Steps {
+		if step.Action == plugins.InstallActionKeep {
+			continue
+		}
+		archive, exists := archives.Take(step.PluginID, step.Version)
+		if !exists {
+			var err error
+			archive, err = repository.GetPluginArchive(ctx, step.PluginID, step.Version, compatOpts)
+			if err != nil {
+				discard()
+				return fmt.Errorf("%v: %w", fmt.Sprintf("failed to download plugin %s from repository", step.PluginID), err)
+			}
+		}
+		s, err := pluginFs.Stage(ctx, step.PluginID, archive.File)
 		if err != nil {
-			return fmt.Errorf("%v: %w", fmt.Sprintf("failed to download plugin %s from repository", dep.ID), err)
+			discard()
+			return err
 		}
+		staged = append(staged, s)
+	}
 
-		_, err = pluginFs.Extract(ctx, dep.ID, d.File)
-		if err != nil {
+	for len(staged) > 0 {
+		if _, err := pluginFs.Commit(ctx, staged[0]); err != nil {
+			discard()
 			return err
 		}
+		staged = staged[1:]
 	}
 	return nil
 }
 
+func logInstallPlan(plan *plugins.InstallPlan) {
+	logger.Infof("Installation plan for %s v%s:\n", plan.PluginID, plan.Version)
+	for _, step := range plan.Steps {
+		var details []string
+		if step.InstalledVersion != "" && step.Action != plugins.InstallActionKeep {
+			details = append(details, "installed: v"+step.InstalledVersion)
+		}
+		if len(step.RequiredBy) > 0 {
+			details = append(details, "required by "+strings.Join(step.RequiredBy, ", "))
+		}
+		line := fmt.Sprintf("  %-7s %s v%s", step.Action, step.PluginID, step.Version)
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, "; ") + ")"
+		}
+		logger.Info(line + "\n")
+	}
+}
+
+// installedPlugins returns the plugins of the plugin directory, which the resolver keeps unless
+// a dependency requires another version.
+func installedPlugins(pluginsDir string) []repo.InstalledPlugin {
+	var installed []repo.InstalledPlugin
+	for _, p := range services.GetLocalPlugins(pluginsDir) {
+		deps := make([]plugins.Dependency, 0, len(p.Dependencies.Plugins))
+		for _, d := range p.Dependencies.Plugins {
+			deps = append(deps, plugins.Dependency{ID: d.ID, Version: d.Version})
+		}
+		installed = append(installed, repo.InstalledPlugin{ID: p.ID, Version: p.Info.Version, Dependencies: deps})
+	}
+	return installed
+}
+
+// newPluginRepository returns the plugin repositories given with the repo flag, in order of priority.
+// The server resolves plugins against the repositories of its configuration the same way.
+func newPluginRepository(c utils.CommandLine) (*repo.Manager, error) {
+	var repositories []config.PluginRepository
+	for _, s := range strings.Split(c.PluginRepoURL(), ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		repository, err := repo.ParseRepository(s)
+		if err != nil {
+			return nil, err
+		}
+		repository.Token = c.String("repoToken")
+		repository.SkipTLSVerify = c.Bool("insecure")
+		repositories = append(repositories, repository)
+	}
+	return repo.NewManager(c.Bool("insecure"), repositories, services.Logger)
+}
+
 // uninstallPlugin removes the plugin directory
 func uninstallPlugin(_ context.Context, pluginID string, c utils.CommandLine) error {
 	logger.Infof("Removing plugin: %v\n", pluginID)
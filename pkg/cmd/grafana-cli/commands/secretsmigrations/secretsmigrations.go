@@ -20,3 +20,8 @@ func RollBackSecrets(_ utils.CommandLine, runner server.Runner) error {
 	_, err := runner.SecretsMigrator.RollBackSecrets(context.Background())
 	return err
 }
+
+func MigrateDataKeys(_ utils.CommandLine, runner server.Runner) error {
+	_, err := runner.SecretsMigrator.ReEncryptDataKeys(context.Background())
+	return err
+}
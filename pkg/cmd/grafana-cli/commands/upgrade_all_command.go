@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"runtime"
 
 	"github.com/hashicorp/go-version"
 
@@ -9,47 +11,79 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/plugins/repo"
 )
 
-func shouldUpgrade(installed string, remote *models.Plugin) bool {
+func shouldUpgrade(installed string, latest string) bool {
 	installedVersion, err := version.NewVersion(installed)
 	if err != nil {
 		return false
 	}
 
-	latest := latestSupportedVersion(remote)
-	latestVersion, err := version.NewVersion(latest.Version)
+	latestVersion, err := version.NewVersion(latest)
 	if err != nil {
 		return false
 	}
 	return installedVersion.LessThan(latestVersion)
 }
 
+// latestVersion returns the latest version of a plugin supported by the system in the plugin repositories.
+// It returns an empty version if the plugin is not in the repositories.
+func latestVersion(ctx context.Context, repository *repo.Manager, pluginID string) (string, error) {
+	compatOpts := repo.NewCompatOpts(services.GrafanaVersion, runtime.GOOS, runtime.GOARCH)
+	dlOpts, err := repository.GetPluginDownloadOptions(ctx, pluginID, "", compatOpts)
+	var notFoundErr repo.ErrPluginNotFound
+	if errors.As(err, &notFoundErr) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return dlOpts.Version, nil
+}
+
+// isUnsupportedPluginErr reports whether err means that no version of a plugin in the repositories
+// can be installed on the system, in which case the plugin is left as it is.
+func isUnsupportedPluginErr(err error) bool {
+	var archErr repo.ErrArcNotFound
+	var versionUnsupportedErr repo.ErrVersionUnsupported
+	var versionNotFoundErr repo.ErrVersionNotFound
+	var grafanaVersionErr repo.ErrGrafanaVersionUnsupported
+	return errors.As(err, &archErr) || errors.As(err, &versionUnsupportedErr) ||
+		errors.As(err, &versionNotFoundErr) || errors.As(err, &grafanaVersionErr)
+}
+
 func upgradeAllCommand(c utils.CommandLine) error {
 	pluginsDir := c.PluginDirectory()
 
 	localPlugins := services.GetLocalPlugins(pluginsDir)
 
-	remotePlugins, err := services.ListAllPlugins(c.String("repo"))
+	repository, err := newPluginRepository(c)
 	if err != nil {
 		return err
 	}
 
+	ctx := context.Background()
 	pluginsToUpgrade := make([]models.InstalledPlugin, 0)
 
 	for _, localPlugin := range localPlugins {
-		for _, p := range remotePlugins.Plugins {
-			remotePlugin := p
-			if localPlugin.ID != remotePlugin.ID {
-				continue
-			}
-			if shouldUpgrade(localPlugin.Info.Version, &remotePlugin) {
-				pluginsToUpgrade = append(pluginsToUpgrade, localPlugin)
-			}
+		latest, err := latestVersion(ctx, repository, localPlugin.ID)
+		if isUnsupportedPluginErr(err) {
+			logger.Warnf("Skipping %s: %s\n", localPlugin.ID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if latest == "" {
+			logger.Debugf("%s is not in the plugin repositories\n", localPlugin.ID)
+			continue
+		}
+		if shouldUpgrade(localPlugin.Info.Version, latest) {
+			pluginsToUpgrade = append(pluginsToUpgrade, localPlugin)
 		}
 	}
 
-	ctx := context.Background()
 	for _, p := range pluginsToUpgrade {
 		logger.Infof("Updating %v \n", p.ID)
 
@@ -1,12 +1,14 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
+	"github.com/grafana/grafana/pkg/plugins/repo"
 )
 
 func TestVersionComparison(t *testing.T) {
@@ -24,7 +26,7 @@ func TestVersionComparison(t *testing.T) {
 		for k, v := range upgradeablePlugins {
 			val := v
 			t.Run(fmt.Sprintf("for %s should be true", k), func(t *testing.T) {
-				assert.True(t, shouldUpgrade(k, &val))
+				assert.True(t, shouldUpgrade(k, latestSupportedVersion(&val).Version))
 			})
 		}
 	})
@@ -43,8 +45,18 @@ func TestVersionComparison(t *testing.T) {
 		for k, v := range shouldNotUpgrade {
 			val := v
 			t.Run(fmt.Sprintf("for %s should be false", k), func(t *testing.T) {
-				assert.False(t, shouldUpgrade(k, &val))
+				assert.False(t, shouldUpgrade(k, latestSupportedVersion(&val).Version))
 			})
 		}
 	})
 }
+
+func TestIsUnsupportedPluginErr(t *testing.T) {
+	assert.True(t, isUnsupportedPluginErr(repo.ErrArcNotFound{PluginID: "test"}))
+	assert.True(t, isUnsupportedPluginErr(fmt.Errorf("wrapped: %w", repo.ErrVersionUnsupported{PluginID: "test"})))
+	assert.True(t, isUnsupportedPluginErr(repo.ErrVersionNotFound{PluginID: "test"}))
+	assert.True(t, isUnsupportedPluginErr(repo.ErrGrafanaVersionUnsupported{PluginID: "test"}))
+
+	assert.False(t, isUnsupportedPluginErr(nil))
+	assert.False(t, isUnsupportedPluginErr(errors.New("connection refused")))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/plugins/repo"
 )
 
 func upgradeCommand(c utils.CommandLine) error {
@@ -21,12 +22,19 @@ func upgradeCommand(c utils.CommandLine) error {
 		return err
 	}
 
-	plugin, err := services.GetPlugin(pluginID, c.PluginRepoURL())
+	repository, err := newPluginRepository(c)
 	if err != nil {
 		return err
 	}
+	latest, err := latestVersion(ctx, repository, pluginID)
+	if err != nil {
+		return err
+	}
+	if latest == "" {
+		return repo.ErrPluginNotFound{PluginID: pluginID}
+	}
 
-	if shouldUpgrade(localPlugin.Info.Version, &plugin) {
+	if shouldUpgrade(localPlugin.Info.Version, latest) {
 		if err = uninstallPlugin(ctx, pluginID, c); err != nil {
 			return fmt.Errorf("failed to remove plugin '%s': %w", pluginID, err)
 		}
@@ -15,8 +15,13 @@ type InstalledPlugin struct {
 }
 
 type Dependencies struct {
-	GrafanaVersion string   `json:"grafanaVersion"`
-	Plugins        []Plugin `json:"plugins"`
+	GrafanaVersion string       `json:"grafanaVersion"`
+	Plugins        []Dependency `json:"plugins"`
+}
+
+type Dependency struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
 }
 
 type PluginInfo struct {
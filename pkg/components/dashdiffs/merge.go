@@ -0,0 +1,204 @@
+package dashdiffs
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// MergeConflict is a value that was changed differently in both versions.
+type MergeConflict struct {
+	// Path is the JSON pointer of the conflicting value in the merged dashboard.
+	Path   string      `json:"path"`
+	Base   interface{} `json:"base"`
+	Ours   interface{} `json:"ours"`
+	Theirs interface{} `json:"theirs"`
+}
+
+// MergeResult is the result of a three-way merge of dashboards.
+type MergeResult struct {
+	// Merged holds the changes of both versions. Conflicting values are taken from theirs.
+	Merged    *simplejson.Json `json:"merged"`
+	Conflicts []MergeConflict  `json:"conflicts"`
+}
+
+// HasConflicts reports whether the versions could not be merged automatically.
+func (r *MergeResult) HasConflicts() bool {
+	return len(r.Conflicts) > 0
+}
+
+// absent marks a value that does not exist in a version.
+type absentValue struct{}
+
+var absent = absentValue{}
+
+// identityKeys identify the elements of arrays so that elements can be merged when they are
+// added, removed or reordered, like the panels by id and the variables by name.
+var identityKeys = []string{"id", "uid", "name"}
+
+// Merge merges the changes that ours and theirs made to base. Values changed on only one side
+// are taken from that side, values changed differently on both sides are reported as conflicts.
+// Arrays of objects with an id, uid or name are merged element by element.
+func Merge(baseData, oursData, theirsData *simplejson.Json) (*MergeResult, error) {
+	base, err := normalize(baseData)
+	if err != nil {
+		return nil, err
+	}
+	ours, err := normalize(oursData)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := normalize(theirsData)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &merger{conflicts: []MergeConflict{}}
+	merged := m.merge("", base, ours, theirs)
+	if merged == absent {
+		merged = map[string]interface{}{}
+	}
+	return &MergeResult{Merged: simplejson.NewFromAny(merged), Conflicts: m.conflicts}, nil
+}
+
+type merger struct {
+	conflicts []MergeConflict
+}
+
+func (m *merger) merge(path string, base, ours, theirs interface{}) interface{} {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours
+	case reflect.DeepEqual(base, ours):
+		return theirs
+	case reflect.DeepEqual(base, theirs):
+		return ours
+	}
+
+	oursMap, oursIsMap := ours.(map[string]interface{})
+	theirsMap, theirsIsMap := theirs.(map[string]interface{})
+	if oursIsMap && theirsIsMap {
+		baseMap, _ := base.(map[string]interface{})
+		return m.mergeMaps(path, baseMap, oursMap, theirsMap)
+	}
+
+	oursList, oursIsList := ours.([]interface{})
+	theirsList, theirsIsList := theirs.([]interface{})
+	if oursIsList && theirsIsList {
+		baseList, _ := base.([]interface{})
+		if key := identityKey(baseList, oursList, theirsList); key != "" {
+			return m.mergeLists(path, key, baseList, oursList, theirsList)
+		}
+	}
+
+	m.conflict(path, base, ours, theirs)
+	return theirs
+}
+
+func (m *merger) mergeMaps(path string, base, ours, theirs map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, key := range sortedKeys(mergeKeys(base, ours, theirs)) {
+		value := m.merge(path+"/"+escapePointer(key), valueOf(base, key), valueOf(ours, key), valueOf(theirs, key))
+		if value != absent {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// mergeLists merges arrays by the identity of their elements. The order of theirs is kept and
+// the elements only added by ours are appended.
+func (m *merger) mergeLists(path, key string, base, ours, theirs []interface{}) []interface{} {
+	baseByID, oursByID := indexByKey(base, key), indexByKey(ours, key)
+	theirsByID := indexByKey(theirs, key)
+
+	result := make([]interface{}, 0, len(theirs))
+	add := func(id string) {
+		value := m.merge(path+"/"+strconv.Itoa(len(result)), valueOf(baseByID, id), valueOf(oursByID, id), valueOf(theirsByID, id))
+		if value != absent {
+			result = append(result, value)
+		}
+	}
+
+	for _, item := range theirs {
+		add(elementID(item, key))
+	}
+	for _, item := range ours {
+		id := elementID(item, key)
+		if _, ok := theirsByID[id]; ok {
+			continue
+		}
+		if _, ok := baseByID[id]; ok {
+			// removed by theirs
+			add(id)
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func (m *merger) conflict(path string, base, ours, theirs interface{}) {
+	m.conflicts = append(m.conflicts, MergeConflict{Path: path, Base: presentOrNil(base), Ours: presentOrNil(ours), Theirs: presentOrNil(theirs)})
+}
+
+// identityKey returns the key identifying the elements of all lists, if any.
+func identityKey(lists ...[]interface{}) string {
+	for _, key := range identityKeys {
+		if hasUniqueKey(key, lists...) {
+			return key
+		}
+	}
+	return ""
+}
+
+func hasUniqueKey(key string, lists ...[]interface{}) bool {
+	for _, list := range lists {
+		seen := map[string]bool{}
+		for _, item := range list {
+			id := elementID(item, key)
+			if id == "" || seen[id] {
+				return false
+			}
+			seen[id] = true
+		}
+	}
+	return true
+}
+
+func elementID(item interface{}, key string) string {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch id := obj[key].(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return ""
+}
+
+func indexByKey(list []interface{}, key string) map[string]interface{} {
+	result := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		result[elementID(item, key)] = item
+	}
+	return result
+}
+
+func valueOf(m map[string]interface{}, key string) interface{} {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return absent
+}
+
+func presentOrNil(v interface{}) interface{} {
+	if v == absent {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package dashdiffs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	base := mustJSON(t, `{
+		"title": "Service",
+		"version": 3,
+		"panels": [
+			{"id": 1, "type": "graph", "title": "Latency"},
+			{"id": 2, "type": "stat", "title": "Errors"},
+			{"id": 3, "type": "text", "title": "Notes"}
+		]
+	}`)
+
+	t.Run("should merge changes to different values", func(t *testing.T) {
+		// ours renames panel 1 and removes panel 3, theirs changes panel 2 and adds panel 4
+		ours := mustJSON(t, `{
+			"title": "Service",
+			"version": 3,
+			"panels": [
+				{"id": 1, "type": "graph", "title": "Request latency"},
+				{"id": 2, "type": "stat", "title": "Errors"}
+			]
+		}`)
+		theirs := mustJSON(t, `{
+			"title": "Service",
+			"version": 4,
+			"panels": [
+				{"id": 1, "type": "graph", "title": "Latency"},
+				{"id": 2, "type": "gauge", "title": "Errors"},
+				{"id": 3, "type": "text", "title": "Notes"},
+				{"id": 4, "type": "text", "title": "Runbook"}
+			]
+		}`)
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.False(t, result.HasConflicts())
+
+		merged, err := result.Merged.Encode()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"title": "Service",
+			"version": 4,
+			"panels": [
+				{"id": 1, "type": "graph", "title": "Request latency"},
+				{"id": 2, "type": "gauge", "title": "Errors"},
+				{"id": 4, "type": "text", "title": "Runbook"}
+			]
+		}`, string(merged))
+	})
+
+	t.Run("should report values changed differently on both sides", func(t *testing.T) {
+		ours := mustJSON(t, `{"title": "Ours", "version": 3, "panels": [
+			{"id": 1, "type": "graph", "title": "Latency"},
+			{"id": 2, "type": "stat", "title": "Errors"},
+			{"id": 3, "type": "text", "title": "Edited notes"}
+		]}`)
+		theirs := mustJSON(t, `{"title": "Theirs", "version": 4, "panels": [
+			{"id": 1, "type": "graph", "title": "Latency"},
+			{"id": 2, "type": "stat", "title": "Errors"}
+		]}`)
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.True(t, result.HasConflicts())
+		assert.Equal(t, []MergeConflict{
+			{
+				Path:   "/panels/2",
+				Base:   map[string]interface{}{"id": float64(3), "type": "text", "title": "Notes"},
+				Ours:   map[string]interface{}{"id": float64(3), "type": "text", "title": "Edited notes"},
+				Theirs: nil,
+			},
+			{Path: "/title", Base: "Service", Ours: "Ours", Theirs: "Theirs"},
+		}, result.Conflicts)
+		assert.Equal(t, "Theirs", result.Merged.Get("title").MustString(), "conflicting values should be taken from theirs")
+	})
+}
@@ -0,0 +1,137 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// PatchOperation is a single operation of an RFC 6902 JSON Patch.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON omits the value of remove operations only, as null is a valid value to add or replace.
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+	type operation PatchOperation
+	return json.Marshal(operation(op))
+}
+
+// JSONPatchResult is the structural diff of two dashboards.
+type JSONPatchResult struct {
+	// Patch transforms the base dashboard into the new one.
+	Patch []PatchOperation `json:"patch"`
+	// Summary describes the changes in terms of panels, variables and settings.
+	Summary []ChangeSummary `json:"summary"`
+}
+
+// CalculateJSONPatch computes the RFC 6902 JSON Patch that transforms baseData into newData,
+// along with a semantic summary of the changes.
+func CalculateJSONPatch(baseData, newData *simplejson.Json) (*JSONPatchResult, error) {
+	base, err := normalize(baseData)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := normalize(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONPatchResult{
+		Patch:   diffValues("", base, newValue, []PatchOperation{}),
+		Summary: summarize(base, newValue),
+	}, nil
+}
+
+// diffValues appends the operations transforming a into b at path. Arrays are compared by
+// index, elements are replaced in place, appended at the end or removed from the end.
+func diffValues(path string, a, b interface{}, ops []PatchOperation) []PatchOperation {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(av) {
+			if _, ok := bv[key]; !ok {
+				ops = append(ops, PatchOperation{Op: "remove", Path: path + "/" + escapePointer(key)})
+			}
+		}
+		for _, key := range sortedKeys(bv) {
+			childPath := path + "/" + escapePointer(key)
+			if aChild, ok := av[key]; ok {
+				ops = diffValues(childPath, aChild, bv[key], ops)
+			} else {
+				ops = append(ops, PatchOperation{Op: "add", Path: childPath, Value: bv[key]})
+			}
+		}
+		return ops
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		common := len(av)
+		if len(bv) < common {
+			common = len(bv)
+		}
+		for i := 0; i < common; i++ {
+			ops = diffValues(path+"/"+strconv.Itoa(i), av[i], bv[i], ops)
+		}
+		for i := len(av) - 1; i >= common; i-- {
+			ops = append(ops, PatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := common; i < len(bv); i++ {
+			ops = append(ops, PatchOperation{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: bv[i]})
+		}
+		return ops
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		ops = append(ops, PatchOperation{Op: "replace", Path: path, Value: b})
+	}
+	return ops
+}
+
+// normalize decodes a JSON document into plain maps, slices and float64 numbers so that
+// documents can be compared with reflect.DeepEqual.
+func normalize(data *simplejson.Json) (interface{}, error) {
+	if data == nil {
+		return map[string]interface{}{}, nil
+	}
+	b, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// escapePointer escapes a key to be used as a JSON pointer reference token.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestCalculateJSONPatch(t *testing.T) {
+	base := mustJSON(t, `{
+		"title": "Service",
+		"version": 3,
+		"tags": ["a", "b", "c"],
+		"panels": [
+			{"id": 1, "type": "row", "collapsed": true, "panels": [
+				{"id": 4, "type": "graph", "targets": [{"expr": "up"}]}
+			]},
+			{"id": 2, "type": "stat", "title": "Errors", "gridPos": {"x": 0, "y": 0}}
+		],
+		"templating": {"list": [{"name": "env", "query": "dev,prod"}]},
+		"a/b": "escaped"
+	}`)
+	newDash := mustJSON(t, `{
+		"title": "Service overview",
+		"version": 4,
+		"tags": ["a"],
+		"panels": [
+			{"id": 1, "type": "row", "collapsed": true, "panels": [
+				{"id": 4, "type": "graph", "targets": [{"expr": "rate(up[5m])"}]}
+			]},
+			{"id": 2, "type": "stat", "title": "Errors", "gridPos": {"x": 12, "y": 0}},
+			{"id": 5, "type": "text"}
+		],
+		"templating": {"list": [{"name": "env", "query": "dev,prod"}, {"name": "region", "query": "eu,us"}]},
+		"refresh": null
+	}`)
+
+	t.Run("should produce an RFC 6902 patch", func(t *testing.T) {
+		result, err := CalculateJSONPatch(base, newDash)
+		require.NoError(t, err)
+
+		assert.Equal(t, []PatchOperation{
+			{Op: "remove", Path: "/a~1b"},
+			{Op: "replace", Path: "/panels/0/panels/0/targets/0/expr", Value: "rate(up[5m])"},
+			{Op: "replace", Path: "/panels/1/gridPos/x", Value: float64(12)},
+			{Op: "add", Path: "/panels/2", Value: map[string]interface{}{"id": float64(5), "type": "text"}},
+			{Op: "add", Path: "/refresh", Value: nil},
+			{Op: "remove", Path: "/tags/2"},
+			{Op: "remove", Path: "/tags/1"},
+			{Op: "add", Path: "/templating/list/1", Value: map[string]interface{}{"name": "region", "query": "eu,us"}},
+			{Op: "replace", Path: "/title", Value: "Service overview"},
+			{Op: "replace", Path: "/version", Value: float64(4)},
+		}, result.Patch)
+	})
+
+	t.Run("should summarize the changes", func(t *testing.T) {
+		result, err := CalculateJSONPatch(base, newDash)
+		require.NoError(t, err)
+
+		var messages []string
+		for _, change := range result.Summary {
+			messages = append(messages, change.Message)
+		}
+		assert.Equal(t, []string{
+			"panel 4 query changed",
+			"panel 2 moved or resized",
+			"panel 5 added",
+			"variable region added",
+			"setting a/b removed",
+			"setting refresh added",
+			"setting tags changed",
+			"setting title changed",
+		}, messages)
+	})
+
+	t.Run("should keep null values and omit the value of remove operations", func(t *testing.T) {
+		b, err := json.Marshal([]PatchOperation{{Op: "add", Path: "/refresh"}, {Op: "remove", Path: "/tags"}})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"op": "add", "path": "/refresh", "value": null}, {"op": "remove", "path": "/tags"}]`, string(b))
+	})
+
+	t.Run("identical dashboards should have an empty patch", func(t *testing.T) {
+		result, err := CalculateJSONPatch(base, base)
+		require.NoError(t, err)
+		assert.Empty(t, result.Patch)
+		assert.Empty(t, result.Summary)
+	})
+}
+
+func mustJSON(t *testing.T, s string) *simplejson.Json {
+	t.Helper()
+	j, err := simplejson.NewJson([]byte(s))
+	require.NoError(t, err)
+	return j
+}
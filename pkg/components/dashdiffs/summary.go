@@ -0,0 +1,210 @@
+package dashdiffs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ChangeKind is the kind of change of a dashboard element.
+type ChangeKind string
+
+const (
+	SummaryAdded   ChangeKind = "added"
+	SummaryRemoved ChangeKind = "removed"
+	SummaryChanged ChangeKind = "changed"
+)
+
+// ChangeSummary describes a change to a dashboard element in terms users understand,
+// like "panel 4 query changed" or "variable env added".
+type ChangeSummary struct {
+	Kind ChangeKind `json:"kind"`
+	// Target is the kind of element that changed: panel, variable, annotation, link or setting.
+	Target string `json:"target"`
+	// Key identifies the element, the panel id, the variable name or the setting.
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// ignoredSettings change on every save and are not reported.
+var ignoredSettings = map[string]bool{"id": true, "uid": true, "version": true}
+
+// panelFields maps the fields of a panel to the description of their change.
+var panelFields = []struct {
+	fields      []string
+	description string
+}{
+	{[]string{"targets"}, "query changed"},
+	{[]string{"datasource"}, "data source changed"},
+	{[]string{"title"}, "title changed"},
+	{[]string{"type"}, "visualization changed"},
+	{[]string{"gridPos"}, "moved or resized"},
+	{[]string{"options", "fieldConfig"}, "options changed"},
+	{[]string{"transformations"}, "transformations changed"},
+	{[]string{"libraryPanel"}, "library panel changed"},
+}
+
+// summarize describes the changes between two normalized dashboards.
+func summarize(base, newValue interface{}) []ChangeSummary {
+	baseDash, _ := base.(map[string]interface{})
+	newDash, _ := newValue.(map[string]interface{})
+	summary := make([]ChangeSummary, 0)
+
+	summary = append(summary, summarizePanels(collectPanels(baseDash["panels"]), collectPanels(newDash["panels"]))...)
+	summary = append(summary, summarizeList("variable", listAt(baseDash, "templating", "list"), listAt(newDash, "templating", "list"), "name")...)
+	summary = append(summary, summarizeList("annotation", listAt(baseDash, "annotations", "list"), listAt(newDash, "annotations", "list"), "name")...)
+	summary = append(summary, summarizeList("link", toList(baseDash["links"]), toList(newDash["links"]), "title")...)
+
+	for _, key := range sortedKeys(mergeKeys(baseDash, newDash)) {
+		if ignoredSettings[key] || key == "panels" || key == "templating" || key == "annotations" || key == "links" {
+			continue
+		}
+		baseSetting, inBase := baseDash[key]
+		newSetting, inNew := newDash[key]
+		switch {
+		case !inBase:
+			summary = append(summary, ChangeSummary{Kind: SummaryAdded, Target: "setting", Key: key, Message: fmt.Sprintf("setting %s added", key)})
+		case !inNew:
+			summary = append(summary, ChangeSummary{Kind: SummaryRemoved, Target: "setting", Key: key, Message: fmt.Sprintf("setting %s removed", key)})
+		case !reflect.DeepEqual(baseSetting, newSetting):
+			summary = append(summary, ChangeSummary{Kind: SummaryChanged, Target: "setting", Key: key, Message: fmt.Sprintf("setting %s changed", key)})
+		}
+	}
+
+	return summary
+}
+
+type keyedPanel struct {
+	key   string
+	panel map[string]interface{}
+}
+
+// collectPanels lists the panels of a dashboard, including the ones in collapsed rows, keyed by id.
+func collectPanels(panels interface{}) []keyedPanel {
+	var result []keyedPanel
+	for i, p := range toList(panels) {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key := "#" + strconv.Itoa(i)
+		if id, ok := panel["id"].(float64); ok {
+			key = strconv.FormatFloat(id, 'f', -1, 64)
+		}
+		result = append(result, keyedPanel{key: key, panel: panel})
+		result = append(result, collectPanels(panel["panels"])...)
+	}
+	return result
+}
+
+func summarizePanels(basePanels, newPanels []keyedPanel) []ChangeSummary {
+	summary := make([]ChangeSummary, 0)
+	baseByKey := map[string]map[string]interface{}{}
+	for _, p := range basePanels {
+		baseByKey[p.key] = p.panel
+	}
+	newByKey := map[string]bool{}
+
+	for _, p := range newPanels {
+		newByKey[p.key] = true
+		basePanel, ok := baseByKey[p.key]
+		if !ok {
+			summary = append(summary, ChangeSummary{Kind: SummaryAdded, Target: "panel", Key: p.key, Message: fmt.Sprintf("panel %s added", p.key)})
+			continue
+		}
+		if reflect.DeepEqual(withoutChildren(basePanel), withoutChildren(p.panel)) {
+			continue
+		}
+
+		described := false
+		for _, f := range panelFields {
+			for _, field := range f.fields {
+				if !reflect.DeepEqual(basePanel[field], p.panel[field]) {
+					summary = append(summary, ChangeSummary{Kind: SummaryChanged, Target: "panel", Key: p.key, Message: fmt.Sprintf("panel %s %s", p.key, f.description)})
+					described = true
+					break
+				}
+			}
+		}
+		if !described {
+			summary = append(summary, ChangeSummary{Kind: SummaryChanged, Target: "panel", Key: p.key, Message: fmt.Sprintf("panel %s changed", p.key)})
+		}
+	}
+
+	for _, p := range basePanels {
+		if !newByKey[p.key] {
+			summary = append(summary, ChangeSummary{Kind: SummaryRemoved, Target: "panel", Key: p.key, Message: fmt.Sprintf("panel %s removed", p.key)})
+		}
+	}
+	return summary
+}
+
+// summarizeList describes the changes of a list of named elements, like variables.
+func summarizeList(target string, baseList, newList []interface{}, keyField string) []ChangeSummary {
+	summary := make([]ChangeSummary, 0)
+	key := func(i int, item interface{}) string {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m[keyField].(string); ok && name != "" {
+				return name
+			}
+		}
+		return "#" + strconv.Itoa(i)
+	}
+
+	baseByKey := map[string]interface{}{}
+	for i, item := range baseList {
+		baseByKey[key(i, item)] = item
+	}
+	newByKey := map[string]bool{}
+	for i, item := range newList {
+		k := key(i, item)
+		newByKey[k] = true
+		baseItem, ok := baseByKey[k]
+		switch {
+		case !ok:
+			summary = append(summary, ChangeSummary{Kind: SummaryAdded, Target: target, Key: k, Message: fmt.Sprintf("%s %s added", target, k)})
+		case !reflect.DeepEqual(baseItem, item):
+			summary = append(summary, ChangeSummary{Kind: SummaryChanged, Target: target, Key: k, Message: fmt.Sprintf("%s %s changed", target, k)})
+		}
+	}
+	for i, item := range baseList {
+		if k := key(i, item); !newByKey[k] {
+			summary = append(summary, ChangeSummary{Kind: SummaryRemoved, Target: target, Key: k, Message: fmt.Sprintf("%s %s removed", target, k)})
+		}
+	}
+	return summary
+}
+
+// withoutChildren returns a panel without the panels of a collapsed row, which are compared on their own.
+func withoutChildren(panel map[string]interface{}) map[string]interface{} {
+	if _, ok := panel["panels"]; !ok {
+		return panel
+	}
+	result := make(map[string]interface{}, len(panel))
+	for k, v := range panel {
+		if k != "panels" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func listAt(dash map[string]interface{}, key, listKey string) []interface{} {
+	m, _ := dash[key].(map[string]interface{})
+	return toList(m[listKey])
+}
+
+func toList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func mergeKeys(maps ...map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, m := range maps {
+		for k := range m {
+			result[k] = nil
+		}
+	}
+	return result
+}
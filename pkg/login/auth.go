@@ -62,6 +62,14 @@ func (a *AuthenticatorService) AuthenticateUser(ctx context.Context, query *logi
 		return ErrTooManyLoginAttempts
 	}
 
+	ok, err = a.loginAttemptService.ValidateIPAddress(ctx, query.IpAddress)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTooManyLoginAttempts
+	}
+
 	if err := validatePasswordSet(query.Password); err != nil {
 		return err
 	}
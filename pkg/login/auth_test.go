@@ -68,6 +68,22 @@ func TestAuthenticateUser(t *testing.T) {
 		assert.True(t, loginAttemptService.ValidateCalled)
 	})
 
+	authScenario(t, "When a user authenticates from an ip address having too many login attempts", func(sc *authScenarioContext) {
+		cfg := setting.NewCfg()
+		mockLoginUsingGrafanaDB(nil, sc)
+		mockLoginUsingLDAP(true, nil, sc)
+
+		loginAttemptService := &loginattempttest.MockLoginAttemptService{ExpectedValid: true, ExpectedIPBlocked: true}
+		a := AuthenticatorService{loginAttemptService: loginAttemptService, loginService: &logintest.LoginServiceFake{}, cfg: cfg}
+		err := a.AuthenticateUser(context.Background(), sc.loginUserQuery)
+
+		require.EqualError(t, err, ErrTooManyLoginAttempts.Error())
+		assert.False(t, sc.grafanaLoginWasCalled)
+		assert.False(t, sc.ldapLoginWasCalled)
+		assert.False(t, loginAttemptService.AddCalled)
+		assert.True(t, loginAttemptService.ValidateIPAddressCalled)
+	})
+
 	authScenario(t, "When grafana user authenticate with valid credentials", func(sc *authScenarioContext) {
 		cfg := setting.NewCfg()
 		mockLoginUsingGrafanaDB(nil, sc)
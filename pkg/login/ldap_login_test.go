@@ -85,6 +85,13 @@ func (auth *mockAuth) Users(logins []string) (
 	return nil, nil
 }
 
+func (auth *mockAuth) UsersFromAllServers(logins []string) (
+	[]*login.ExternalUserInfo,
+	error,
+) {
+	return nil, nil
+}
+
 func (auth *mockAuth) User(login string) (
 	*login.ExternalUserInfo,
 	ldap.ServerConfig,
@@ -0,0 +1,411 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3/jwt"
+	"golang.org/x/oauth2"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models/roletype"
+	authjwt "github.com/grafana/grafana/pkg/services/auth/jwt"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// BackChannelLogoutEvent is the event of OpenID Connect back-channel logout tokens
+	BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+	// clock skew tolerated when validating ID and logout tokens
+	oidcLeeway = time.Minute
+)
+
+// OIDCConnector is implemented by connectors that verify OpenID Connect tokens
+type OIDCConnector interface {
+	SocialConnector
+	// VerifyIDToken verifies the signature and claims of the ID token of an OAuth token and returns its claims.
+	// The nonce claim is compared with nonce unless it is empty.
+	VerifyIDToken(ctx context.Context, token *oauth2.Token, nonce string) (map[string]interface{}, error)
+	// VerifyLogoutToken verifies a back-channel logout token posted by the identity provider.
+	VerifyLogoutToken(ctx context.Context, logoutToken string) (*LogoutToken, error)
+}
+
+// LogoutToken holds the claims of a back-channel logout token identifying the sessions to end
+type LogoutToken struct {
+	Subject   string
+	SessionID string
+}
+
+type oidcProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// SocialOIDC is a generic OpenID Connect connector, the endpoints of the identity provider are
+// read from its discovery document and ID tokens are verified against the keys of its JWKS.
+type SocialOIDC struct {
+	*SocialBase
+	issuer              string
+	apiUrl              string
+	jwkSetUrl           string
+	emailAttributePath  string
+	loginAttributePath  string
+	nameAttributePath   string
+	groupsAttributePath string
+	allowedGroups       []string
+	skipOrgRoleSync     bool
+
+	httpClient  *http.Client
+	remoteCache *remotecache.RemoteCache
+	jwkCacheTTL time.Duration
+
+	mu       sync.Mutex
+	metadata *oidcProviderMetadata
+	keySet   authjwt.KeySet
+}
+
+var _ OIDCConnector = new(SocialOIDC)
+
+// discover fetches the discovery document of the issuer once it is needed, endpoints configured
+// explicitly take precedence over the discovered ones.
+func (s *SocialOIDC) discover(ctx context.Context) (*oidcProviderMetadata, authjwt.KeySet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metadata != nil {
+		return s.metadata, s.keySet, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(s.issuer, "/") + oidcDiscoveryPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch OpenID Connect discovery document: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to fetch OpenID Connect discovery document: %s", resp.Status)
+	}
+
+	var metadata oidcProviderMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode OpenID Connect discovery document: %w", err)
+	}
+
+	// the issuer of the discovery document must match the issuer it was fetched from
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(s.issuer, "/") {
+		return nil, nil, fmt.Errorf("issuer %q of discovery document does not match configured issuer %q", metadata.Issuer, s.issuer)
+	}
+
+	if s.Config.Endpoint.AuthURL != "" {
+		metadata.AuthorizationEndpoint = s.Config.Endpoint.AuthURL
+	}
+	if s.Config.Endpoint.TokenURL != "" {
+		metadata.TokenEndpoint = s.Config.Endpoint.TokenURL
+	}
+	if s.apiUrl != "" {
+		metadata.UserinfoEndpoint = s.apiUrl
+	}
+	if s.jwkSetUrl != "" {
+		metadata.JWKSURI = s.jwkSetUrl
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, errors.New("OpenID Connect discovery document is missing the authorization, token or jwks endpoint")
+	}
+
+	s.log.Debug("Discovered OpenID Connect provider", "issuer", metadata.Issuer, "jwks_uri", metadata.JWKSURI)
+
+	s.metadata = &metadata
+	s.keySet = authjwt.NewKeySetHTTP(metadata.JWKSURI, s.httpClient, s.remoteCache, s.jwkCacheTTL)
+	return s.metadata, s.keySet, nil
+}
+
+// config returns the OAuth configuration with the discovered endpoints
+func (s *SocialOIDC) config(ctx context.Context) (*oauth2.Config, error) {
+	metadata, _, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := *s.Config
+	config.Endpoint.AuthURL = metadata.AuthorizationEndpoint
+	config.Endpoint.TokenURL = metadata.TokenEndpoint
+	return &config, nil
+}
+
+func (s *SocialOIDC) AuthCodeURL(state string, opts ...oauth2.AuthCodeOption) string {
+	config, err := s.config(context.Background())
+	if err != nil {
+		s.log.Error("Failed to discover OpenID Connect provider", "issuer", s.issuer, "error", err)
+		return s.Config.AuthCodeURL(state, opts...)
+	}
+	return config.AuthCodeURL(state, opts...)
+}
+
+func (s *SocialOIDC) Exchange(ctx context.Context, code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	config, err := s.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return config.Exchange(ctx, code, opts...)
+}
+
+func (s *SocialOIDC) Client(ctx context.Context, t *oauth2.Token) *http.Client {
+	config, err := s.config(ctx)
+	if err != nil {
+		s.log.Error("Failed to discover OpenID Connect provider", "issuer", s.issuer, "error", err)
+		return s.Config.Client(ctx, t)
+	}
+	return config.Client(ctx, t)
+}
+
+func (s *SocialOIDC) TokenSource(ctx context.Context, t *oauth2.Token) oauth2.TokenSource {
+	config, err := s.config(ctx)
+	if err != nil {
+		s.log.Error("Failed to discover OpenID Connect provider", "issuer", s.issuer, "error", err)
+		return s.Config.TokenSource(ctx, t)
+	}
+	return config.TokenSource(ctx, t)
+}
+
+func (s *SocialOIDC) VerifyIDToken(ctx context.Context, token *oauth2.Token, nonce string) (map[string]interface{}, error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("no id_token found")
+	}
+
+	metadata, claims, registered, err := s.verifySignature(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id token: %w", err)
+	}
+
+	if registered.Expiry == nil {
+		return nil, errors.New("id token has no exp claim")
+	}
+	if err := s.validateRegisteredClaims(metadata, registered); err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	// when the token is issued for several audiences the authorized party must be this client
+	if azp, ok := claims["azp"].(string); ok && azp != s.Config.ClientID {
+		return nil, fmt.Errorf("id token was issued for authorized party %q", azp)
+	}
+
+	if nonce != "" {
+		if claimed, _ := claims["nonce"].(string); claimed != nonce {
+			return nil, errors.New("id token nonce does not match")
+		}
+	}
+
+	return claims, nil
+}
+
+func (s *SocialOIDC) VerifyLogoutToken(ctx context.Context, logoutToken string) (*LogoutToken, error) {
+	metadata, claims, registered, err := s.verifySignature(ctx, logoutToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify logout token: %w", err)
+	}
+
+	if registered.IssuedAt == nil {
+		return nil, errors.New("logout token has no iat claim")
+	}
+	if err := s.validateRegisteredClaims(metadata, registered); err != nil {
+		return nil, fmt.Errorf("invalid logout token: %w", err)
+	}
+
+	events, _ := claims["events"].(map[string]interface{})
+	if _, ok := events[BackChannelLogoutEvent]; !ok {
+		return nil, errors.New("logout token does not contain the back-channel logout event")
+	}
+	// a nonce claim is prohibited to prevent ID tokens from being used as logout tokens
+	if _, ok := claims["nonce"]; ok {
+		return nil, errors.New("logout token must not contain a nonce claim")
+	}
+
+	sid, _ := claims["sid"].(string)
+	if registered.Subject == "" && sid == "" {
+		return nil, errors.New("logout token must contain a sub or sid claim")
+	}
+
+	return &LogoutToken{Subject: registered.Subject, SessionID: sid}, nil
+}
+
+func (s *SocialOIDC) verifySignature(ctx context.Context, rawToken string) (*oidcProviderMetadata, map[string]interface{}, *jwt.Claims, error) {
+	metadata, keySet, err := s.discover(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	claims, err := authjwt.VerifySignature(ctx, keySet, rawToken)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// decode the registered claims again to handle audience lists and numeric dates
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var registered jwt.Claims
+	if err := json.Unmarshal(data, &registered); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return metadata, claims, &registered, nil
+}
+
+func (s *SocialOIDC) validateRegisteredClaims(metadata *oidcProviderMetadata, claims *jwt.Claims) error {
+	return claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   metadata.Issuer,
+		Audience: jwt.Audience{s.Config.ClientID},
+		Time:     time.Now(),
+	}, oidcLeeway)
+}
+
+func (s *SocialOIDC) UserInfo(client *http.Client, token *oauth2.Token) (*BasicUserInfo, error) {
+	ctx := context.Background()
+
+	// the nonce is verified when the token is exchanged
+	claims, err := s.VerifyIDToken(ctx, token, "")
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.UserinfoEndpoint != "" {
+		response, err := s.httpGet(client, metadata.UserinfoEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("error getting user info: %w", err)
+		}
+
+		var userInfo map[string]interface{}
+		if err := json.Unmarshal(response.Body, &userInfo); err != nil {
+			return nil, fmt.Errorf("error decoding user info response: %w", err)
+		}
+		// the user info response must be about the user the id token was issued for
+		if sub, _ := userInfo["sub"].(string); sub != claims["sub"] {
+			return nil, errors.New("user info subject does not match id token subject")
+		}
+		for key, value := range userInfo {
+			claims[key] = value
+		}
+	}
+
+	rawJSON, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	userInfo := &BasicUserInfo{
+		Id:    sub,
+		Email: s.stringClaim(rawJSON, s.emailAttributePath, "email"),
+		Login: s.stringClaim(rawJSON, s.loginAttributePath, "preferred_username"),
+		Name:  s.stringClaim(rawJSON, s.nameAttributePath, "name"),
+	}
+	if userInfo.Login == "" {
+		userInfo.Login = userInfo.Email
+	}
+
+	if s.groupsAttributePath != "" {
+		groups, err := s.searchJSONForStringArrayAttr(s.groupsAttributePath, rawJSON)
+		if err != nil {
+			s.log.Warn("Failed to extract groups", "error", err)
+		}
+		userInfo.Groups = groups
+	}
+	if !s.IsGroupMember(userInfo.Groups) {
+		return nil, errMissingGroupMembership
+	}
+
+	if !s.skipOrgRoleSync {
+		var role roletype.RoleType
+		var grafanaAdmin bool
+		role, grafanaAdmin = s.extractRoleAndAdmin(rawJSON, userInfo.Groups, false)
+		if s.roleAttributeStrict && !role.IsValid() {
+			return nil, &InvalidBasicRoleError{idP: "OIDC", assignedRole: string(role)}
+		}
+		userInfo.Role = role
+		if s.allowAssignGrafanaAdmin {
+			userInfo.IsGrafanaAdmin = &grafanaAdmin
+		}
+	}
+	if s.allowAssignGrafanaAdmin && s.skipOrgRoleSync {
+		s.log.Debug("allowAssignGrafanaAdmin and skipOrgRoleSync are both set, Grafana Admin role will not be synced, consider setting one or the other")
+	}
+
+	s.log.Debug("Resolved user info", "data", userInfo.String())
+	return userInfo, nil
+}
+
+// stringClaim returns the value of the attribute path, or of the standard claim if no path is configured
+func (s *SocialOIDC) stringClaim(rawJSON []byte, attributePath, claim string) string {
+	if attributePath == "" {
+		attributePath = claim
+	}
+
+	value, err := s.searchJSONForStringAttr(attributePath, rawJSON)
+	if err != nil {
+		s.log.Debug("Failed to search claims", "attribute_path", attributePath, "error", err)
+		return ""
+	}
+	return value
+}
+
+func (s *SocialOIDC) IsGroupMember(groups []string) bool {
+	if len(s.allowedGroups) == 0 {
+		return true
+	}
+
+	for _, allowedGroup := range s.allowedGroups {
+		for _, group := range groups {
+			if group == allowedGroup {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *SocialOIDC) SupportBundleContent(bf *bytes.Buffer) error {
+	bf.WriteString("## OpenID Connect configuration\n\n")
+	bf.WriteString("```ini\n")
+	bf.WriteString(fmt.Sprintf("issuer = %v\n", s.issuer))
+	bf.WriteString(fmt.Sprintf("jwk_set_url = %v\n", s.jwkSetUrl))
+	bf.WriteString(fmt.Sprintf("allowed_groups = %v\n", s.allowedGroups))
+	bf.WriteString("```\n\n")
+
+	return s.SocialBase.SupportBundleContent(bf)
+}
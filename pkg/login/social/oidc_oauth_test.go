@@ -0,0 +1,257 @@
+package social
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/grafana/grafana/pkg/models/roletype"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+const oidcTestClientID = "grafana"
+
+type oidcTestProvider struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	userInfo map[string]interface{}
+}
+
+func newOIDCTestProvider(t *testing.T) *oidcTestProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &oidcTestProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcProviderMetadata{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			UserinfoEndpoint:      p.server.URL + "/userinfo",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "key-1", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(p.userInfo)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func (p *oidcTestProvider) sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "key-1"))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func (p *oidcTestProvider) idTokenClaims() map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":   p.server.URL,
+		"aud":   oidcTestClientID,
+		"sub":   "user-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": "expected-nonce",
+		"email": "octopus@grafana.com",
+	}
+}
+
+func newTestSocialOIDC(p *oidcTestProvider) *SocialOIDC {
+	return &SocialOIDC{
+		SocialBase: newSocialBase("oidc", &oauth2.Config{ClientID: oidcTestClientID},
+			&OAuthInfo{}, "Viewer", false, *featuremgmt.WithFeatures()),
+		issuer:     p.server.URL,
+		httpClient: p.server.Client(),
+	}
+}
+
+func TestSocialOIDC_Discovery(t *testing.T) {
+	p := newOIDCTestProvider(t)
+	s := newTestSocialOIDC(p)
+
+	authURL := s.AuthCodeURL("state")
+	assert.Contains(t, authURL, p.server.URL+"/authorize?")
+
+	t.Run("should reject discovery document of another issuer", func(t *testing.T) {
+		s := newTestSocialOIDC(p)
+		s.issuer = p.server.URL + "/other"
+		_, _, err := s.discover(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestSocialOIDC_VerifyIDToken(t *testing.T) {
+	p := newOIDCTestProvider(t)
+	s := newTestSocialOIDC(p)
+
+	tests := []struct {
+		name    string
+		modify  func(claims map[string]interface{})
+		nonce   string
+		wantErr bool
+	}{
+		{name: "valid token", nonce: "expected-nonce"},
+		{name: "nonce is not checked when empty", nonce: "", modify: func(claims map[string]interface{}) { claims["nonce"] = "other" }},
+		{name: "wrong nonce", nonce: "expected-nonce", modify: func(claims map[string]interface{}) { claims["nonce"] = "other" }, wantErr: true},
+		{name: "wrong audience", modify: func(claims map[string]interface{}) { claims["aud"] = "other-client" }, wantErr: true},
+		{name: "wrong authorized party", modify: func(claims map[string]interface{}) {
+			claims["aud"] = []string{oidcTestClientID, "other-client"}
+			claims["azp"] = "other-client"
+		}, wantErr: true},
+		{name: "wrong issuer", modify: func(claims map[string]interface{}) { claims["iss"] = "https://evil.example.com" }, wantErr: true},
+		{name: "expired", modify: func(claims map[string]interface{}) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }, wantErr: true},
+		{name: "missing expiry", modify: func(claims map[string]interface{}) { delete(claims, "exp") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := p.idTokenClaims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			token := (&oauth2.Token{}).WithExtra(map[string]interface{}{"id_token": p.sign(t, claims)})
+
+			_, err := s.VerifyIDToken(context.Background(), token, tt.nonce)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("should reject token signed with another key", func(t *testing.T) {
+		other := newOIDCTestProvider(t)
+		claims := p.idTokenClaims()
+		token := (&oauth2.Token{}).WithExtra(map[string]interface{}{"id_token": other.sign(t, claims)})
+
+		_, err := s.VerifyIDToken(context.Background(), token, "")
+		require.Error(t, err)
+	})
+}
+
+func TestSocialOIDC_VerifyLogoutToken(t *testing.T) {
+	p := newOIDCTestProvider(t)
+	s := newTestSocialOIDC(p)
+
+	logoutClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":    p.server.URL,
+			"aud":    oidcTestClientID,
+			"sub":    "user-1",
+			"sid":    "session-1",
+			"iat":    time.Now().Unix(),
+			"jti":    "logout-1",
+			"events": map[string]interface{}{BackChannelLogoutEvent: map[string]interface{}{}},
+		}
+	}
+
+	t.Run("valid logout token", func(t *testing.T) {
+		token, err := s.VerifyLogoutToken(context.Background(), p.sign(t, logoutClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, &LogoutToken{Subject: "user-1", SessionID: "session-1"}, token)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(claims map[string]interface{})
+	}{
+		{name: "missing event", modify: func(claims map[string]interface{}) { delete(claims, "events") }},
+		{name: "contains nonce", modify: func(claims map[string]interface{}) { claims["nonce"] = "n" }},
+		{name: "missing iat", modify: func(claims map[string]interface{}) { delete(claims, "iat") }},
+		{name: "missing sub and sid", modify: func(claims map[string]interface{}) {
+			delete(claims, "sub")
+			delete(claims, "sid")
+		}},
+		{name: "wrong audience", modify: func(claims map[string]interface{}) { claims["aud"] = "other-client" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := logoutClaims()
+			tt.modify(claims)
+			_, err := s.VerifyLogoutToken(context.Background(), p.sign(t, claims))
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("should reject id token", func(t *testing.T) {
+		_, err := s.VerifyLogoutToken(context.Background(), p.sign(t, p.idTokenClaims()))
+		require.Error(t, err)
+	})
+}
+
+func TestSocialOIDC_UserInfo(t *testing.T) {
+	p := newOIDCTestProvider(t)
+
+	t.Run("should merge user info and extract attributes", func(t *testing.T) {
+		p.userInfo = map[string]interface{}{
+			"sub":                "user-1",
+			"preferred_username": "octopus",
+			"name":               "Octopus",
+			"groups":             []string{"admins"},
+			"role":               "Editor",
+		}
+		s := newTestSocialOIDC(p)
+		s.groupsAttributePath = "groups"
+		s.roleAttributePath = "role"
+		s.allowedGroups = []string{"admins"}
+
+		token := (&oauth2.Token{AccessToken: "access"}).WithExtra(map[string]interface{}{"id_token": p.sign(t, p.idTokenClaims())})
+		userInfo, err := s.UserInfo(p.server.Client(), token)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", userInfo.Id)
+		assert.Equal(t, "octopus", userInfo.Login)
+		assert.Equal(t, "octopus@grafana.com", userInfo.Email)
+		assert.Equal(t, "Octopus", userInfo.Name)
+		assert.Equal(t, []string{"admins"}, userInfo.Groups)
+		assert.Equal(t, roletype.RoleEditor, userInfo.Role)
+	})
+
+	t.Run("should reject user info of another subject", func(t *testing.T) {
+		p.userInfo = map[string]interface{}{"sub": "user-2"}
+		s := newTestSocialOIDC(p)
+
+		token := (&oauth2.Token{AccessToken: "access"}).WithExtra(map[string]interface{}{"id_token": p.sign(t, p.idTokenClaims())})
+		_, err := s.UserInfo(p.server.Client(), token)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject users outside allowed groups", func(t *testing.T) {
+		p.userInfo = map[string]interface{}{"sub": "user-1", "groups": []string{"users"}}
+		s := newTestSocialOIDC(p)
+		s.groupsAttributePath = "groups"
+		s.allowedGroups = []string{"admins"}
+
+		token := (&oauth2.Token{AccessToken: "access"}).WithExtra(map[string]interface{}{"id_token": p.sign(t, p.idTokenClaims())})
+		_, err := s.UserInfo(p.server.Client(), token)
+		require.ErrorIs(t, err, errMissingGroupMembership)
+	})
+}
@@ -10,12 +10,14 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -68,6 +70,7 @@ func ProvideService(cfg *setting.Cfg,
 	features *featuremgmt.FeatureManager,
 	usageStats usagestats.Service,
 	bundleRegistry supportbundles.Service,
+	remoteCache *remotecache.RemoteCache,
 ) *SocialService {
 	ss := &SocialService{
 		cfg:           cfg,
@@ -217,6 +220,39 @@ func ProvideService(cfg *setting.Cfg,
 			}
 		}
 
+		// OpenID Connect - endpoints are discovered from the issuer.
+		if name == "oidc" {
+			hasOpenIDScope := false
+			for _, scope := range config.Scopes {
+				hasOpenIDScope = hasOpenIDScope || scope == "openid"
+			}
+			if !hasOpenIDScope {
+				config.Scopes = append([]string{"openid"}, config.Scopes...)
+			}
+
+			httpClient, err := ss.GetOAuthHttpClient(name)
+			if err != nil {
+				logger.Error("Failed to create OpenID Connect http client, using default client", "error", err)
+				httpClient = http.DefaultClient
+			}
+
+			ss.socialMap["oidc"] = &SocialOIDC{
+				SocialBase:          newSocialBase(name, &config, info, cfg.AutoAssignOrgRole, cfg.OAuthSkipOrgRoleUpdateSync, *features),
+				issuer:              sec.Key("issuer").String(),
+				apiUrl:              info.ApiUrl,
+				jwkSetUrl:           sec.Key("jwk_set_url").String(),
+				emailAttributePath:  info.EmailAttributePath,
+				loginAttributePath:  sec.Key("login_attribute_path").String(),
+				nameAttributePath:   sec.Key("name_attribute_path").String(),
+				groupsAttributePath: info.GroupsAttributePath,
+				allowedGroups:       util.SplitString(sec.Key("allowed_groups").String()),
+				skipOrgRoleSync:     cfg.OIDCSkipOrgRoleSync,
+				httpClient:          httpClient,
+				remoteCache:         remoteCache,
+				jwkCacheTTL:         sec.Key("jwk_set_cache_ttl").MustDuration(time.Hour),
+			}
+		}
+
 		if name == grafanaCom {
 			config = oauth2.Config{
 				ClientID:     info.ClientId,
@@ -301,7 +337,7 @@ const (
 var (
 	SocialBaseUrl = "/login/"
 	SocialMap     = make(map[string]SocialConnector)
-	allOauthes    = []string{"github", "gitlab", "google", "generic_oauth", "grafananet", grafanaCom, "azuread", "okta"}
+	allOauthes    = []string{"github", "gitlab", "google", "generic_oauth", "grafananet", grafanaCom, "azuread", "okta", "oidc"}
 )
 
 type Service interface {
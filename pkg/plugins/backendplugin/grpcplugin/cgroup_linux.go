@@ -0,0 +1,29 @@
+//go:build linux && go1.20
+// +build linux,go1.20
+
+package grpcplugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const cgroupsSupported = true
+
+// startInCgroup makes the command start in the cgroup v2 directory at path, instead of being moved to it once
+// started, so that none of its resource usage escapes the limits of the cgroup. The returned function closes the
+// cgroup directory, and must be called once the command is started.
+func startInCgroup(cmd *exec.Cmd, path string) (func(), error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cgroup of plugin process: %w", err)
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return func() { _ = dir.Close() }, nil
+}
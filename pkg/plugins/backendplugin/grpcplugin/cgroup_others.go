@@ -0,0 +1,15 @@
+//go:build !linux || !go1.20
+// +build !linux !go1.20
+
+package grpcplugin
+
+import (
+	"errors"
+	"os/exec"
+)
+
+const cgroupsSupported = false
+
+func startInCgroup(_ *exec.Cmd, _ string) (func(), error) {
+	return nil, errors.New("starting plugin processes in a cgroup is not supported on this platform")
+}
@@ -3,6 +3,7 @@ package grpcplugin
 import (
 	"context"
 	"errors"
+	"os/exec"
 	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -23,8 +24,10 @@ type pluginClient interface {
 
 type grpcPlugin struct {
 	descriptor     PluginDescriptor
-	clientFactory  func() *plugin.Client
+	clientFactory  func() *plugin.ClientConfig
 	client         *plugin.Client
+	cmd            *exec.Cmd
+	cgroup         string
 	pluginClient   pluginClient
 	logger         log.Logger
 	mutex          sync.RWMutex
@@ -37,8 +40,8 @@ func newPlugin(descriptor PluginDescriptor) backendplugin.PluginFactoryFunc {
 		return &grpcPlugin{
 			descriptor: descriptor,
 			logger:     logger,
-			clientFactory: func() *plugin.Client {
-				return plugin.NewClient(newClientConfig(descriptor.executablePath, env, logger, descriptor.versionedPlugins))
+			clientFactory: func() *plugin.ClientConfig {
+				return newClientConfig(descriptor.executablePath, env, logger, descriptor.versionedPlugins)
 			},
 		}, nil
 	}
@@ -56,7 +59,16 @@ func (p *grpcPlugin) Start(ctx context.Context) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	p.client = p.clientFactory()
+	cfg := p.clientFactory()
+	if p.cgroup != "" {
+		closeCgroup, err := startInCgroup(cfg.Cmd, p.cgroup)
+		if err != nil {
+			return err
+		}
+		defer closeCgroup()
+	}
+	p.cmd = cfg.Cmd
+	p.client = plugin.NewClient(cfg)
 	rpcClient, err := p.client.Client()
 	if err != nil {
 		return err
@@ -108,6 +120,35 @@ func (p *grpcPlugin) Exited() bool {
 	return true
 }
 
+func (p *grpcPlugin) Pid() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.cmd == nil || p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+func (p *grpcPlugin) ExitReason() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	// the state of the command is only set, and safe to read, once the client has seen the process exit
+	if p.client == nil || !p.client.Exited() || p.cmd == nil || p.cmd.ProcessState == nil {
+		return ""
+	}
+	return p.cmd.ProcessState.String()
+}
+
+func (p *grpcPlugin) SetCgroup(path string) error {
+	if !cgroupsSupported {
+		return errors.New("starting plugin processes in a cgroup is not supported on this platform")
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.cgroup = path
+	return nil
+}
+
 func (p *grpcPlugin) Decommission() error {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
@@ -26,6 +26,18 @@ type Plugin interface {
 	backend.StreamHandler
 }
 
+// Process is implemented by plugins that run in a process of their own.
+type Process interface {
+	// Pid returns the ID of the plugin process, or 0 if it was not started.
+	Pid() int
+	// ExitReason returns why the plugin process exited, like "exit status 2" or "signal: killed",
+	// or an empty string if it is running.
+	ExitReason() string
+	// SetCgroup makes the plugin process start in the cgroup v2 directory at path from its next start on.
+	// It returns an error if processes cannot be started in a cgroup on this platform.
+	SetCgroup(path string) error
+}
+
 type Target string
 
 const (
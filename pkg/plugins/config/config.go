@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/grafana/grafana-azure-sdk-go/azsettings"
 
 	"github.com/grafana/grafana/pkg/plugins"
@@ -37,10 +39,60 @@ type Cfg struct {
 	Tracing Tracing
 
 	GrafanaComURL string
+	// PluginRepositories are the repositories plugins are installed from, in order of priority.
+	// Plugins are installed from grafana.com when empty.
+	PluginRepositories []PluginRepository
+
+	// PluginProcess is the default resource limits and restart policy of backend plugin processes.
+	PluginProcess PluginProcess
+	// PluginProcesses are the resource limits and restart policies of plugins that override the default, by plugin ID.
+	PluginProcesses map[string]PluginProcess
+	// PluginCgroup is the cgroup v2 directory the cgroups limiting the resources of backend plugin processes
+	// are created in. Resource limits are not enforced when empty.
+	PluginCgroup string
 
 	Features plugins.FeatureToggles
 }
 
+// PluginProcess is the resource limits and restart policy of a backend plugin process.
+type PluginProcess struct {
+	// MemoryLimit is the maximum memory of the process in bytes, or 0 for no limit.
+	MemoryLimit int64
+	// CPULimit is the maximum CPU usage of the process in cores, like 0.5, or 0 for no limit.
+	CPULimit float64
+	// MaxRestarts is the number of consecutive restarts of a crashed process before it is given up, or 0 for no limit.
+	MaxRestarts int
+	// RestartBackoff is the delay before a crashed process is restarted. It doubles with each consecutive
+	// restart, up to MaxRestartBackoff.
+	RestartBackoff    time.Duration
+	MaxRestartBackoff time.Duration
+}
+
+// HasLimits returns whether the resources of the process are limited.
+func (p PluginProcess) HasLimits() bool {
+	return p.MemoryLimit > 0 || p.CPULimit > 0
+}
+
+// Types of plugin repositories.
+const (
+	// PluginRepositoryGrafanaCom is the grafana.com plugin API, or a mirror of it.
+	PluginRepositoryGrafanaCom = "grafana_com"
+	// PluginRepositoryIndex is a static index file of plugin versions and archives, like on a file server.
+	PluginRepositoryIndex = "index"
+	// PluginRepositoryOCI is an OCI registry with a repository per plugin and a tag per version.
+	PluginRepositoryOCI = "oci"
+)
+
+// PluginRepository is a repository plugins are installed from.
+type PluginRepository struct {
+	Name string
+	Type string
+	URL  string
+	// Token is sent as a bearer token to private repositories.
+	Token         string
+	SkipTLSVerify bool
+}
+
 func NewCfg(devMode bool, pluginsPath string, pluginSettings setting.PluginSettings, pluginsAllowUnsigned []string,
 	awsAllowedAuthProviders []string, awsAssumeRoleEnabled bool, azure *azsettings.AzureSettings, secureSocksDSProxy setting.SecureSocksDSProxySettings,
 	grafanaVersion string, logDatasourceRequests bool, pluginsCDNURLTemplate string, tracing Tracing, features plugins.FeatureToggles) *Cfg {
@@ -62,3 +114,11 @@ func NewCfg(devMode bool, pluginsPath string, pluginSettings setting.PluginSetti
 		Features:                features,
 	}
 }
+
+// Process returns the resource limits and restart policy of the process of a backend plugin.
+func (cfg *Cfg) Process(pluginID string) PluginProcess {
+	if p, exists := cfg.PluginProcesses[pluginID]; exists {
+		return p
+	}
+	return cfg.PluginProcess
+}
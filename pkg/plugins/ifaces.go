@@ -19,8 +19,10 @@ type Store interface {
 }
 
 type Installer interface {
-	// Add adds a new plugin.
+	// Add adds a new plugin, and the plugins it depends on.
 	Add(ctx context.Context, pluginID, version string, opts CompatOpts) error
+	// Plan returns the plan of the installation of a plugin without applying it.
+	Plan(ctx context.Context, pluginID, version string, opts CompatOpts) (*InstallPlan, error)
 	// Remove removes an existing plugin.
 	Remove(ctx context.Context, pluginID string) error
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/log"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/repo"
 	"github.com/grafana/grafana/pkg/plugins/storage"
 )
@@ -203,6 +204,7 @@ type FakePluginRepo struct {
 	GetPluginArchiveFunc         func(_ context.Context, pluginID, version string, _ repo.CompatOpts) (*repo.PluginArchive, error)
 	GetPluginArchiveByURLFunc    func(_ context.Context, archiveURL string, _ repo.CompatOpts) (*repo.PluginArchive, error)
 	GetPluginDownloadOptionsFunc func(_ context.Context, pluginID, version string, _ repo.CompatOpts) (*repo.PluginDownloadOptions, error)
+	GetPluginVersionsFunc        func(_ context.Context, pluginID string, _ repo.CompatOpts) ([]repo.Version, error)
 }
 
 // GetPluginArchive fetches the requested plugin archive.
@@ -231,8 +233,18 @@ func (r *FakePluginRepo) GetPluginDownloadOptions(ctx context.Context, pluginID,
 	return &repo.PluginDownloadOptions{}, nil
 }
 
+// GetPluginVersions fetches the versions of the requested plugin supported by the system.
+func (r *FakePluginRepo) GetPluginVersions(ctx context.Context, pluginID string, opts repo.CompatOpts) ([]repo.Version, error) {
+	if r.GetPluginVersionsFunc != nil {
+		return r.GetPluginVersionsFunc(ctx, pluginID, opts)
+	}
+	return []repo.Version{}, nil
+}
+
 type FakePluginStorage struct {
 	ExtractFunc func(_ context.Context, pluginID string, z *zip.ReadCloser) (*storage.ExtractedPluginArchive, error)
+	CommitFunc  func(_ context.Context, staged *storage.ExtractedPluginArchive) (*storage.ExtractedPluginArchive, error)
+	Discarded   []*storage.ExtractedPluginArchive
 }
 
 func NewFakePluginStorage() *FakePluginStorage {
@@ -246,11 +258,29 @@ func (s *FakePluginStorage) Extract(ctx context.Context, pluginID string, z *zip
 	return &storage.ExtractedPluginArchive{}, nil
 }
 
+// Stage extracts the archive with ExtractFunc.
+func (s *FakePluginStorage) Stage(ctx context.Context, pluginID string, z *zip.ReadCloser) (*storage.ExtractedPluginArchive, error) {
+	return s.Extract(ctx, pluginID, z)
+}
+
+func (s *FakePluginStorage) Commit(ctx context.Context, staged *storage.ExtractedPluginArchive) (*storage.ExtractedPluginArchive, error) {
+	if s.CommitFunc != nil {
+		return s.CommitFunc(ctx, staged)
+	}
+	return staged, nil
+}
+
+func (s *FakePluginStorage) Discard(_ context.Context, staged *storage.ExtractedPluginArchive) error {
+	s.Discarded = append(s.Discarded, staged)
+	return nil
+}
+
 type FakeProcessManager struct {
-	StartFunc func(_ context.Context, pluginID string) error
-	StopFunc  func(_ context.Context, pluginID string) error
-	Started   map[string]int
-	Stopped   map[string]int
+	StartFunc  func(_ context.Context, pluginID string) error
+	StopFunc   func(_ context.Context, pluginID string) error
+	HealthFunc func(_ context.Context, pluginID string) (process.Health, error)
+	Started    map[string]int
+	Stopped    map[string]int
 }
 
 func NewFakeProcessManager() *FakeProcessManager {
@@ -276,6 +306,13 @@ func (m *FakeProcessManager) Stop(ctx context.Context, pluginID string) error {
 	return nil
 }
 
+func (m *FakeProcessManager) Health(ctx context.Context, pluginID string) (process.Health, error) {
+	if m.HealthFunc != nil {
+		return m.HealthFunc(ctx, pluginID)
+	}
+	return process.Health{}, process.ErrProcessNotFound
+}
+
 type FakeBackendProcessProvider struct {
 	Requested          map[string]int
 	Invoked            map[string]int
@@ -18,7 +18,8 @@ var _ plugins.Installer = (*PluginInstaller)(nil)
 
 type PluginInstaller struct {
 	pluginRepo     repo.Service
-	pluginStorage  storage.ZipExtractor
+	resolver       *repo.Resolver
+	pluginStorage  storage.StagingZipExtractor
 	pluginRegistry registry.Service
 	pluginLoader   loader.Service
 	log            log.Logger
@@ -30,11 +31,12 @@ func ProvideInstaller(cfg *config.Cfg, pluginRegistry registry.Service, pluginLo
 }
 
 func New(pluginRegistry registry.Service, pluginLoader loader.Service, pluginRepo repo.Service,
-	pluginStorage storage.ZipExtractor) *PluginInstaller {
+	pluginStorage storage.StagingZipExtractor) *PluginInstaller {
 	return &PluginInstaller{
 		pluginLoader:   pluginLoader,
 		pluginRegistry: pluginRegistry,
 		pluginRepo:     pluginRepo,
+		resolver:       repo.NewResolver(pluginRepo, log.NewPrettyLogger("plugin.resolver")),
 		pluginStorage:  pluginStorage,
 		log:            log.New("plugin.installer"),
 	}
@@ -43,7 +45,6 @@ func New(pluginRegistry registry.Service, pluginLoader loader.Service, pluginRep
 func (m *PluginInstaller) Add(ctx context.Context, pluginID, version string, opts plugins.CompatOpts) error {
 	compatOpts := repo.NewCompatOpts(opts.GrafanaVersion, opts.OS, opts.Arch)
 
-	var pluginArchive *repo.PluginArchive
 	if plugin, exists := m.plugin(ctx, pluginID); exists {
 		if plugin.IsCorePlugin() || plugin.IsBundledPlugin() {
 			return plugins.ErrInstallCorePlugin
@@ -54,69 +55,73 @@ func (m *PluginInstaller) Add(ctx context.Context, pluginID, version string, opt
 				PluginID: plugin.ID,
 			}
 		}
+	}
 
-		// get plugin update information to confirm if target update is possible
-		dlOpts, err := m.pluginRepo.GetPluginDownloadOptions(ctx, pluginID, version, compatOpts)
-		if err != nil {
-			return err
+	// the archives downloaded by the resolver to read dependencies are not downloaded again
+	archives := repo.NewArchiveCache()
+	defer func() {
+		if err := archives.Close(); err != nil {
+			m.log.Warn("Failed to close plugin archives", "err", err)
 		}
+	}()
 
-		// if existing plugin version is the same as the target update version
-		if dlOpts.Version == plugin.Info.Version {
-			return plugins.DuplicateError{
-				PluginID: plugin.ID,
-			}
-		}
+	plan, err := m.resolver.Resolve(ctx, pluginID, version, m.installedPlugins(ctx), compatOpts, archives)
+	if err != nil {
+		return err
+	}
 
-		if dlOpts.PluginZipURL == "" && dlOpts.Version == "" {
-			return fmt.Errorf("could not determine update options for %s", pluginID)
+	// download and extract all archives to a staging directory before changing the installed plugins
+	staged := make(map[string]*storage.ExtractedPluginArchive, len(plan.Steps))
+	discard := func() {
+		for id, s := range staged {
+			if err := m.pluginStorage.Discard(ctx, s); err != nil {
+				m.log.Warn("Failed to discard staged plugin", "pluginId", id, "err", err)
+			}
 		}
-
-		// remove existing installation of plugin
-		err = m.Remove(ctx, plugin.ID)
-		if err != nil {
-			return err
+	}
+	for _, step := range plan.Steps {
+		if step.Action == plugins.InstallActionKeep {
+			continue
 		}
-
-		if dlOpts.PluginZipURL != "" {
-			pluginArchive, err = m.pluginRepo.GetPluginArchiveByURL(ctx, dlOpts.PluginZipURL, compatOpts)
-			if err != nil {
-				return err
-			}
-		} else {
-			pluginArchive, err = m.pluginRepo.GetPluginArchive(ctx, pluginID, dlOpts.Version, compatOpts)
+		archive, exists := archives.Take(step.PluginID, step.Version)
+		if !exists {
+			m.log.Info("Fetching plugin", "pluginId", step.PluginID, "version", step.Version, "requiredBy", step.RequiredBy)
+			archive, err = m.pluginRepo.GetPluginArchive(ctx, step.PluginID, step.Version, compatOpts)
 			if err != nil {
-				return err
+				discard()
+				return fmt.Errorf("%v: %w", fmt.Sprintf("failed to download plugin %s from repository", step.PluginID), err)
 			}
 		}
-	} else {
-		var err error
-		pluginArchive, err = m.pluginRepo.GetPluginArchive(ctx, pluginID, version, compatOpts)
+		s, err := m.pluginStorage.Stage(ctx, step.PluginID, archive.File)
 		if err != nil {
+			discard()
 			return err
 		}
+		staged[step.PluginID] = s
 	}
 
-	extractedArchive, err := m.pluginStorage.Extract(ctx, pluginID, pluginArchive.File)
-	if err != nil {
-		return err
-	}
+	var pathsToScan []string
+	for _, step := range plan.Steps {
+		s, exists := staged[step.PluginID]
+		if !exists {
+			continue
+		}
 
-	// download dependency plugins
-	pathsToScan := []string{extractedArchive.Path}
-	for _, dep := range extractedArchive.Dependencies {
-		m.log.Info("Fetching %s dependencies...", dep.ID)
-		d, err := m.pluginRepo.GetPluginArchive(ctx, dep.ID, dep.Version, compatOpts)
-		if err != nil {
-			return fmt.Errorf("%v: %w", fmt.Sprintf("failed to download plugin %s from repository", dep.ID), err)
+		if step.Action == plugins.InstallActionUpdate {
+			// remove existing installation of plugin
+			if err := m.Remove(ctx, step.PluginID); err != nil {
+				discard()
+				return err
+			}
 		}
 
-		depArchive, err := m.pluginStorage.Extract(ctx, dep.ID, d.File)
+		extractedArchive, err := m.pluginStorage.Commit(ctx, s)
 		if err != nil {
+			discard()
 			return err
 		}
-
-		pathsToScan = append(pathsToScan, depArchive.Path)
+		delete(staged, step.PluginID)
+		pathsToScan = append(pathsToScan, extractedArchive.Path)
 	}
 
 	_, err = m.pluginLoader.Load(ctx, sources.NewLocalSource(plugins.External, pathsToScan))
@@ -128,6 +133,11 @@ func (m *PluginInstaller) Add(ctx context.Context, pluginID, version string, opt
 	return nil
 }
 
+func (m *PluginInstaller) Plan(ctx context.Context, pluginID, version string, opts plugins.CompatOpts) (*plugins.InstallPlan, error) {
+	compatOpts := repo.NewCompatOpts(opts.GrafanaVersion, opts.OS, opts.Arch)
+	return m.resolver.Resolve(ctx, pluginID, version, m.installedPlugins(ctx), compatOpts, nil)
+}
+
 func (m *PluginInstaller) Remove(ctx context.Context, pluginID string) error {
 	plugin, exists := m.plugin(ctx, pluginID)
 	if !exists {
@@ -153,3 +163,17 @@ func (m *PluginInstaller) plugin(ctx context.Context, pluginID string) (*plugins
 
 	return p, true
 }
+
+// installedPlugins returns the installed plugins the resolver keeps unless a dependency requires another version.
+func (m *PluginInstaller) installedPlugins(ctx context.Context) []repo.InstalledPlugin {
+	var installed []repo.InstalledPlugin
+	for _, p := range m.pluginRegistry.Plugins(ctx) {
+		installed = append(installed, repo.InstalledPlugin{
+			ID:           p.ID,
+			Version:      p.Info.Version,
+			Locked:       p.IsCorePlugin() || p.IsBundledPlugin(),
+			Dependencies: p.Dependencies.Plugins,
+		})
+	}
+	return installed
+}
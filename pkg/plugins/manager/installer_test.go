@@ -3,6 +3,7 @@ package manager
 import (
 	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -49,6 +50,10 @@ func TestPluginManager_Add_Remove(t *testing.T) {
 					File: mockZipV1,
 				}, nil
 			},
+			GetPluginVersionsFunc: func(_ context.Context, id string, _ repo.CompatOpts) ([]repo.Version, error) {
+				require.Equal(t, pluginID, id)
+				return []repo.Version{{Version: v1, Dependencies: []plugins.Dependency{}}}, nil
+			},
 		}
 
 		fs := &fakes.FakePluginStorage{
@@ -96,13 +101,15 @@ func TestPluginManager_Add_Remove(t *testing.T) {
 				require.Equal(t, []string{zipNameV2}, src.PluginURIs(ctx))
 				return []*plugins.Plugin{pluginV2}, nil
 			}
-			pluginRepo.GetPluginDownloadOptionsFunc = func(_ context.Context, pluginID, version string, _ repo.CompatOpts) (*repo.PluginDownloadOptions, error) {
-				return &repo.PluginDownloadOptions{
-					PluginZipURL: "https://grafanaplugins.com",
+			pluginRepo.GetPluginVersionsFunc = func(_ context.Context, id string, _ repo.CompatOpts) ([]repo.Version, error) {
+				return []repo.Version{
+					{Version: v2, Dependencies: []plugins.Dependency{}},
+					{Version: v1, Dependencies: []plugins.Dependency{}},
 				}, nil
 			}
-			pluginRepo.GetPluginArchiveByURLFunc = func(_ context.Context, pluginZipURL string, _ repo.CompatOpts) (*repo.PluginArchive, error) {
-				require.Equal(t, "https://grafanaplugins.com", pluginZipURL)
+			pluginRepo.GetPluginArchiveFunc = func(_ context.Context, id, version string, _ repo.CompatOpts) (*repo.PluginArchive, error) {
+				require.Equal(t, pluginID, id)
+				require.Equal(t, v2, version)
 				return &repo.PluginArchive{
 					File: mockZipV2,
 				}, nil
@@ -182,6 +189,90 @@ func TestPluginManager_Add_Remove(t *testing.T) {
 	})
 }
 
+func TestPluginManager_Add_Dependencies(t *testing.T) {
+	pluginRepo := &fakes.FakePluginRepo{
+		GetPluginVersionsFunc: func(_ context.Context, id string, _ repo.CompatOpts) ([]repo.Version, error) {
+			switch id {
+			case "test-app":
+				return []repo.Version{{Version: "2.0.0", Dependencies: []plugins.Dependency{
+					{ID: "test-datasource", Version: ">=1.1.0"},
+					{ID: "test-panel", Version: "^1.0.0"},
+				}}}, nil
+			case "test-datasource":
+				return []repo.Version{{Version: "1.2.0", Dependencies: []plugins.Dependency{}}}, nil
+			}
+			return nil, repo.ErrPluginNotFound{PluginID: id}
+		},
+		GetPluginArchiveFunc: func(_ context.Context, id, version string, _ repo.CompatOpts) (*repo.PluginArchive, error) {
+			return &repo.PluginArchive{File: &zip.ReadCloser{Reader: zip.Reader{File: []*zip.File{{
+				FileHeader: zip.FileHeader{Name: id + "-" + version + ".zip"},
+			}}}}}, nil
+		},
+	}
+
+	var extracted []string
+	fs := &fakes.FakePluginStorage{
+		ExtractFunc: func(_ context.Context, id string, z *zip.ReadCloser) (*storage.ExtractedPluginArchive, error) {
+			extracted = append(extracted, z.File[0].Name)
+			return &storage.ExtractedPluginArchive{Path: id}, nil
+		},
+	}
+
+	var loadedPaths, unloaded []string
+	loader := &fakes.FakeLoader{
+		LoadFunc: func(ctx context.Context, src plugins.PluginSource) ([]*plugins.Plugin, error) {
+			loadedPaths = append(loadedPaths, src.PluginURIs(ctx)...)
+			return nil, nil
+		},
+		UnloadFunc: func(_ context.Context, id string) error {
+			unloaded = append(unloaded, id)
+			return nil
+		},
+	}
+
+	reg := &fakes.FakePluginRegistry{Store: map[string]*plugins.Plugin{
+		"test-datasource": createPlugin(t, "test-datasource", plugins.External, true, true, func(p *plugins.Plugin) {
+			p.Info.Version = "1.0.0"
+		}),
+		"test-panel": createPlugin(t, "test-panel", plugins.External, true, false, func(p *plugins.Plugin) {
+			p.Info.Version = "1.3.0"
+		}),
+	}}
+
+	inst := New(reg, loader, pluginRepo, fs)
+
+	plan, err := inst.Plan(context.Background(), "test-app", "", plugins.CompatOpts{GrafanaVersion: "10.0.0"})
+	require.NoError(t, err)
+	require.Equal(t, []plugins.InstallStep{
+		{PluginID: "test-datasource", Version: "1.2.0", Action: plugins.InstallActionUpdate, InstalledVersion: "1.0.0", RequiredBy: []string{"test-app"}},
+		{PluginID: "test-panel", Version: "1.3.0", Action: plugins.InstallActionKeep, InstalledVersion: "1.3.0", RequiredBy: []string{"test-app"}},
+		{PluginID: "test-app", Version: "2.0.0", Action: plugins.InstallActionInstall},
+	}, plan.Steps)
+	require.Empty(t, extracted)
+
+	err = inst.Add(context.Background(), "test-app", "", plugins.CompatOpts{GrafanaVersion: "10.0.0"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test-datasource-1.2.0.zip", "test-app-2.0.0.zip"}, extracted)
+	require.Equal(t, []string{"test-datasource"}, unloaded)
+	require.Equal(t, []string{"test-datasource", "test-app"}, loadedPaths)
+
+	t.Run("Installed plugins are kept when an archive can't be extracted", func(t *testing.T) {
+		unloaded, loadedPaths = nil, nil
+		fs.ExtractFunc = func(_ context.Context, id string, z *zip.ReadCloser) (*storage.ExtractedPluginArchive, error) {
+			if id == "test-app" {
+				return nil, errors.New("corrupted archive")
+			}
+			return &storage.ExtractedPluginArchive{Path: id}, nil
+		}
+
+		err := inst.Add(context.Background(), "test-app", "", plugins.CompatOpts{GrafanaVersion: "10.0.0"})
+		require.Error(t, err)
+		require.Empty(t, unloaded)
+		require.Empty(t, loadedPaths)
+		require.Equal(t, []*storage.ExtractedPluginArchive{{Path: "test-datasource"}}, fs.Discarded)
+	})
+}
+
 func createPlugin(t *testing.T, pluginID string, class plugins.Class, managed, backend bool, cbs ...func(*plugins.Plugin)) *plugins.Plugin {
 	t.Helper()
 
@@ -0,0 +1,108 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+// cpuPeriod is the period in microseconds the CPU time of a cgroup is limited over.
+const cpuPeriod = 100000
+
+// cgroup is the cgroup v2 limiting the resources of a plugin process.
+type cgroup struct {
+	path string
+	// oomKills is the number of processes of the cgroup killed for running out of memory when it was last checked.
+	oomKills int
+}
+
+// newCgroup creates the cgroup of a plugin in the parent cgroup, which must be delegated to Grafana and must
+// not have processes of its own, and applies the resource limits of the plugin process to it.
+func newCgroup(parent, pluginID string, p config.PluginProcess) (*cgroup, error) {
+	if _, err := os.Stat(filepath.Join(parent, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("%s is not a cgroup v2: %w", parent, err)
+	}
+
+	var controllers []string
+	if p.MemoryLimit > 0 {
+		controllers = append(controllers, "+memory")
+	}
+	if p.CPULimit > 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if err := writeCgroupFile(parent, "cgroup.subtree_control", strings.Join(controllers, " ")); err != nil {
+		return nil, err
+	}
+
+	c := &cgroup{path: filepath.Join(parent, "plugin-"+filepath.Base(pluginID))}
+	if err := os.Mkdir(c.path, 0750); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+
+	if p.MemoryLimit > 0 {
+		if err := writeCgroupFile(c.path, "memory.max", strconv.FormatInt(p.MemoryLimit, 10)); err != nil {
+			return nil, err
+		}
+		// the process is killed rather than swapped out when it reaches the limit, if swap is accounted for
+		_ = writeCgroupFile(c.path, "memory.swap.max", "0")
+	}
+	if p.CPULimit > 0 {
+		if err := writeCgroupFile(c.path, "cpu.max", fmt.Sprintf("%d %d", int(p.CPULimit*cpuPeriod), cpuPeriod)); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if c.oomKills, err = c.readOOMKills(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// oomKilled returns whether a process of the cgroup was killed for running out of memory since it was last checked.
+func (c *cgroup) oomKilled() bool {
+	oomKills, err := c.readOOMKills()
+	if err != nil {
+		return false
+	}
+	killed := oomKills > c.oomKills
+	c.oomKills = oomKills
+	return killed
+}
+
+func (c *cgroup) readOOMKills() (int, error) {
+	// memory.events is only there when the memory controller is enabled
+	b, err := os.ReadFile(filepath.Join(c.path, "memory.events"))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		if strings.HasPrefix(s.Text(), "oom_kill ") {
+			return strconv.Atoi(strings.TrimPrefix(s.Text(), "oom_kill "))
+		}
+	}
+	return 0, nil
+}
+
+// remove removes the cgroup once its processes have exited.
+func (c *cgroup) remove() error {
+	return os.Remove(c.path)
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0600); err != nil {
+		return fmt.Errorf("failed to write %s of cgroup %s: %w", name, dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+func TestCgroup(t *testing.T) {
+	parent := t.TempDir()
+
+	_, err := newCgroup(parent, "test-datasource", config.PluginProcess{MemoryLimit: 512 << 20})
+	require.Error(t, err, "parent is not a cgroup")
+
+	require.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.controllers"), []byte("cpu memory"), 0600))
+	cg, err := newCgroup(parent, "test-datasource", config.PluginProcess{MemoryLimit: 512 << 20, CPULimit: 0.5})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(parent, "plugin-test-datasource"), cg.path)
+
+	for name, expected := range map[string]string{
+		filepath.Join(parent, "cgroup.subtree_control"): "+memory +cpu",
+		filepath.Join(cg.path, "memory.max"):            "536870912",
+		filepath.Join(cg.path, "cpu.max"):               "50000 100000",
+	} {
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(b))
+	}
+
+	require.False(t, cg.oomKilled())
+	require.NoError(t, os.WriteFile(filepath.Join(cg.path, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0600))
+	require.True(t, cg.oomKilled())
+	require.False(t, cg.oomKilled())
+}
+
+func TestProcessManager_StartsProcessInCgroup(t *testing.T) {
+	parent := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.controllers"), []byte("cpu memory"), 0600))
+
+	bp := newFakeBackendPlugin(true)
+	p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+		plugin.Backend = true
+	})
+	cfg := &config.Cfg{PluginCgroup: parent, PluginProcesses: map[string]config.PluginProcess{
+		p.ID: {MemoryLimit: 1 << 20},
+	}}
+	m := NewManager(cfg, newFakePluginRegistry(map[string]*plugins.Plugin{
+		p.ID: p,
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, m.Start(ctx, p.ID))
+
+	bp.mutex.RLock()
+	require.Equal(t, filepath.Join(parent, "plugin-"+p.ID), bp.cgroup)
+	bp.mutex.RUnlock()
+
+	health, err := m.Health(ctx, p.ID)
+	require.NoError(t, err)
+	require.True(t, health.LimitsEnforced)
+}
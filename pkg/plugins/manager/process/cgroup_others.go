@@ -0,0 +1,22 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import (
+	"errors"
+
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+type cgroup struct {
+	path string
+}
+
+func newCgroup(_, _ string, _ config.PluginProcess) (*cgroup, error) {
+	return nil, errors.New("cgroups are only supported on Linux")
+}
+
+func (c *cgroup) oomKilled() bool { return false }
+
+func (c *cgroup) remove() error { return nil }
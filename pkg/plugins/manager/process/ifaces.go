@@ -7,4 +7,7 @@ type Service interface {
 	Start(ctx context.Context, pluginID string) error
 	// Stop terminates a backend plugin process.
 	Stop(ctx context.Context, pluginID string) error
+	// Health returns the health of a backend plugin process. It returns ErrProcessNotFound if the
+	// plugin has no process managed by Grafana.
+	Health(ctx context.Context, pluginID string) (Health, error)
 }
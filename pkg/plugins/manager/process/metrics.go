@@ -0,0 +1,27 @@
+package process
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	processRestartsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "plugin_process_restarts_total",
+		Help:      "The total amount of restarts of crashed backend plugin processes",
+	}, []string{"plugin_id"})
+
+	processLastExitReason = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_process_last_exit_reason",
+		Help:      "The reason a backend plugin process last exited for, with a value of 1",
+	}, []string{"plugin_id", "reason"})
+)
+
+func setLastExitReason(pluginID, previous, reason string) {
+	if previous != "" {
+		processLastExitReason.DeleteLabelValues(pluginID, previous)
+	}
+	processLastExitReason.WithLabelValues(pluginID, reason).Set(1)
+}
@@ -0,0 +1,43 @@
+package process
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrProcessNotFound = errors.New("plugin has no managed process")
+
+// State is the state of a backend plugin process.
+type State string
+
+const (
+	StateRunning State = "running"
+	// StateRestarting is a crashed process waiting to be restarted.
+	StateRestarting State = "restarting"
+	// StateFailed is a process that crashed more often in a row than its maximum number of restarts
+	// and is not restarted anymore.
+	StateFailed  State = "failed"
+	StateStopped State = "stopped"
+)
+
+// Reasons a backend plugin process exited for, besides the exit status of the process.
+const (
+	ExitReasonOOMKilled   = "oom_killed"
+	ExitReasonStartFailed = "start_failed"
+	ExitReasonUnknown     = "unknown"
+)
+
+// Health is the health of a backend plugin process.
+type Health struct {
+	PluginID string `json:"pluginId"`
+	State    State  `json:"state"`
+	// Restarts is the number of times the process was restarted after it crashed.
+	Restarts       int        `json:"restarts"`
+	LastExitReason string     `json:"lastExitReason,omitempty"`
+	LastExitTime   *time.Time `json:"lastExitTime,omitempty"`
+	// MemoryLimit and CPULimit are the resource limits of the process, which are only enforced
+	// when LimitsEnforced is true.
+	MemoryLimit    int64   `json:"memoryLimit,omitempty"`
+	CPULimit       float64 `json:"cpuLimit,omitempty"`
+	LimitsEnforced bool    `json:"limitsEnforced"`
+}
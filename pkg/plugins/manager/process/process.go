@@ -8,27 +8,48 @@ import (
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/log"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
 )
 
 var _ Service = (*Manager)(nil)
 
+// restartResetPeriod is how long a restarted process has to run for before its crashes no longer
+// count towards its maximum number of restarts.
+const restartResetPeriod = 10 * time.Minute
+
 type Manager struct {
+	cfg            *config.Cfg
 	pluginRegistry registry.Service
 
 	mu  sync.Mutex
 	log log.Logger
+
+	processesMu sync.RWMutex
+	processes   map[string]*processState
+}
+
+// processState is the health of a plugin process and the cgroup limiting its resources.
+type processState struct {
+	settings config.PluginProcess
+	cgroup   *cgroup
+	health   Health
+	// startedAt is when the process was last started and crashes the number of times it crashed in a row.
+	startedAt time.Time
+	crashes   int
 }
 
-func ProvideService(pluginRegistry registry.Service) *Manager {
-	return NewManager(pluginRegistry)
+func ProvideService(cfg *config.Cfg, pluginRegistry registry.Service) *Manager {
+	return NewManager(cfg, pluginRegistry)
 }
 
-func NewManager(pluginRegistry registry.Service) *Manager {
+func NewManager(cfg *config.Cfg, pluginRegistry registry.Service) *Manager {
 	return &Manager{
+		cfg:            cfg,
 		pluginRegistry: pluginRegistry,
 		log:            log.New("plugin.process.manager"),
+		processes:      make(map[string]*processState),
 	}
 }
 
@@ -51,7 +72,7 @@ func (m *Manager) Start(ctx context.Context, pluginID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if err := startPluginAndRestartKilledProcesses(ctx, p); err != nil {
+	if err := m.startPluginAndRestartKilledProcesses(ctx, p); err != nil {
 		return err
 	}
 
@@ -76,9 +97,31 @@ func (m *Manager) Stop(ctx context.Context, pluginID string) error {
 		return err
 	}
 
+	m.processesMu.Lock()
+	defer m.processesMu.Unlock()
+	if ps, exists := m.processes[p.ID]; exists {
+		ps.health.State = StateStopped
+		if ps.cgroup != nil {
+			if err := ps.cgroup.remove(); err != nil {
+				m.log.Debug("Failed to remove cgroup of plugin process", "pluginID", p.ID, "error", err)
+			}
+		}
+	}
+
 	return nil
 }
 
+func (m *Manager) Health(_ context.Context, pluginID string) (Health, error) {
+	m.processesMu.RLock()
+	defer m.processesMu.RUnlock()
+
+	ps, exists := m.processes[pluginID]
+	if !exists {
+		return Health{}, ErrProcessNotFound
+	}
+	return ps.health, nil
+}
+
 // shutdown stops all backend plugin processes
 func (m *Manager) shutdown(ctx context.Context) {
 	var wg sync.WaitGroup
@@ -96,17 +139,25 @@ func (m *Manager) shutdown(ctx context.Context) {
 	wg.Wait()
 }
 
-func startPluginAndRestartKilledProcesses(ctx context.Context, p *plugins.Plugin) error {
-	if err := p.Start(ctx); err != nil {
-		return err
+func (m *Manager) startPluginAndRestartKilledProcesses(ctx context.Context, p *plugins.Plugin) error {
+	if p.IsCorePlugin() {
+		return p.Start(ctx)
 	}
 
-	if p.IsCorePlugin() {
-		return nil
+	// the cgroup is created before the process is started, so that the process starts in it
+	ps := m.newProcessState(p)
+	if err := p.Start(ctx); err != nil {
+		if ps.cgroup != nil {
+			if err := ps.cgroup.remove(); err != nil {
+				p.Logger().Debug("Failed to remove cgroup of plugin process", "error", err)
+			}
+		}
+		return err
 	}
+	m.processStarted(p, ps, false)
 
 	go func(ctx context.Context, p *plugins.Plugin) {
-		if err := restartKilledProcess(ctx, p); err != nil {
+		if err := m.restartKilledProcess(ctx, p, ps); err != nil {
 			p.Logger().Error("Attempt to restart killed plugin process failed", "error", err)
 		}
 	}(ctx, p)
@@ -114,16 +165,114 @@ func startPluginAndRestartKilledProcesses(ctx context.Context, p *plugins.Plugin
 	return nil
 }
 
-func restartKilledProcess(ctx context.Context, p *plugins.Plugin) error {
+// newProcessState tracks the health of a plugin process, and creates the cgroup limiting its resources
+// if it has resource limits.
+func (m *Manager) newProcessState(p *plugins.Plugin) *processState {
+	settings := m.cfg.Process(p.ID)
+	ps := &processState{
+		settings:  settings,
+		startedAt: time.Now(),
+		health: Health{
+			PluginID:    p.ID,
+			MemoryLimit: settings.MemoryLimit,
+			CPULimit:    settings.CPULimit,
+		},
+	}
+
+	if settings.HasLimits() {
+		if m.cfg.PluginCgroup == "" {
+			p.Logger().Warn("Resource limits of plugin process are not enforced as no cgroup is configured for plugins")
+		} else if cg, err := newCgroup(m.cfg.PluginCgroup, p.ID, settings); err != nil {
+			p.Logger().Warn("Resource limits of plugin process are not enforced", "error", err)
+		} else if err := startInCgroup(p, cg); err != nil {
+			p.Logger().Warn("Resource limits of plugin process are not enforced", "error", err)
+			if err := cg.remove(); err != nil {
+				p.Logger().Debug("Failed to remove cgroup of plugin process", "error", err)
+			}
+		} else {
+			ps.cgroup = cg
+		}
+	}
+	return ps
+}
+
+// startInCgroup makes the process of a plugin start in a cgroup.
+func startInCgroup(p *plugins.Plugin, cg *cgroup) error {
+	process, ok := p.Process()
+	if !ok {
+		return errors.New("plugin does not run in a process of its own")
+	}
+	return process.SetCgroup(cg.path)
+}
+
+// processStarted marks a plugin process as running.
+func (m *Manager) processStarted(p *plugins.Plugin, ps *processState, restarted bool) {
+	m.processesMu.Lock()
+	defer m.processesMu.Unlock()
+
+	ps.health.State = StateRunning
+	ps.health.LimitsEnforced = ps.cgroup != nil
+	if restarted {
+		ps.health.Restarts++
+		processRestartsTotal.WithLabelValues(p.ID).Inc()
+	} else {
+		m.processes[p.ID] = ps
+	}
+}
+
+// processExited records why a plugin process exited and returns whether and after what backoff it should be restarted.
+func (m *Manager) processExited(p *plugins.Plugin, ps *processState, startErr error) (bool, time.Duration) {
+	reason := ExitReasonUnknown
+	if startErr != nil {
+		reason = ExitReasonStartFailed
+	} else if ps.cgroup != nil && ps.cgroup.oomKilled() {
+		reason = ExitReasonOOMKilled
+	} else if process, ok := p.Process(); ok && process.ExitReason() != "" {
+		reason = process.ExitReason()
+	}
+
+	m.processesMu.Lock()
+	defer m.processesMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(ps.startedAt) >= restartResetPeriod {
+		ps.crashes = 0
+	}
+	ps.crashes++
+
+	setLastExitReason(p.ID, ps.health.LastExitReason, reason)
+	ps.health.LastExitReason = reason
+	ps.health.LastExitTime = &now
+
+	if ps.settings.MaxRestarts > 0 && ps.crashes > ps.settings.MaxRestarts {
+		ps.health.State = StateFailed
+		return false, 0
+	}
+	ps.health.State = StateRestarting
+	return true, restartBackoff(ps.settings, ps.crashes)
+}
+
+// restartBackoff returns the delay before restarting a process that crashed a number of times in a row.
+func restartBackoff(settings config.PluginProcess, crashes int) time.Duration {
+	backoff := settings.RestartBackoff
+	for i := 1; i < crashes && backoff < settings.MaxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	if settings.MaxRestartBackoff > 0 && backoff > settings.MaxRestartBackoff {
+		return settings.MaxRestartBackoff
+	}
+	return backoff
+}
+
+func (m *Manager) restartKilledProcess(ctx context.Context, p *plugins.Plugin, ps *processState) error {
 	ticker := time.NewTicker(time.Second * 1)
+	defer ticker.Stop()
 
+	var startErr error
 	for {
 		select {
 		case <-ctx.Done():
-			if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
-				return err
-			}
-			return nil
+			return contextErr(ctx)
 		case <-ticker.C:
 			if p.IsDecommissioned() {
 				p.Logger().Debug("Plugin decommissioned")
@@ -134,12 +283,43 @@ func restartKilledProcess(ctx context.Context, p *plugins.Plugin) error {
 				continue
 			}
 
+			restart, backoff := m.processExited(p, ps, startErr)
+			if !restart {
+				p.Logger().Error("Plugin process crashed too many times in a row and will not be restarted",
+					"maxRestarts", ps.settings.MaxRestarts)
+				return nil
+			}
+
+			if backoff > 0 {
+				p.Logger().Debug("Waiting to restart plugin", "backoff", backoff)
+				select {
+				case <-ctx.Done():
+					return contextErr(ctx)
+				case <-time.After(backoff):
+				}
+				if p.IsDecommissioned() {
+					p.Logger().Debug("Plugin decommissioned")
+					return nil
+				}
+			}
+
 			p.Logger().Debug("Restarting plugin")
-			if err := p.Start(ctx); err != nil {
-				p.Logger().Error("Failed to restart plugin", "error", err)
+			m.processesMu.Lock()
+			ps.startedAt = time.Now()
+			m.processesMu.Unlock()
+			if startErr = p.Start(ctx); startErr != nil {
+				p.Logger().Error("Failed to restart plugin", "error", startErr)
 				continue
 			}
+			m.processStarted(p, ps, true)
 			p.Logger().Debug("Plugin restarted")
 		}
 	}
 }
+
+func contextErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
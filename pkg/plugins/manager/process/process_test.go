@@ -2,19 +2,22 @@ package process
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/log"
 )
 
 func TestProcessManager_Start(t *testing.T) {
 	t.Run("Plugin not found in registry", func(t *testing.T) {
-		m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{}))
+		m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{}))
 		err := m.Start(context.Background(), "non-existing-datasource")
 		require.ErrorIs(t, err, backendplugin.ErrPluginNotRegistered)
 	})
@@ -63,7 +66,7 @@ func TestProcessManager_Start(t *testing.T) {
 					plugin.SignatureError = tc.signatureError
 				})
 
-				m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{
+				m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
 					p.ID: p,
 				}))
 
@@ -83,7 +86,7 @@ func TestProcessManager_Start(t *testing.T) {
 
 func TestProcessManager_Stop(t *testing.T) {
 	t.Run("Plugin not found in registry", func(t *testing.T) {
-		m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{}))
+		m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{}))
 		err := m.Stop(context.Background(), "non-existing-datasource")
 		require.ErrorIs(t, err, backendplugin.ErrPluginNotRegistered)
 	})
@@ -97,7 +100,7 @@ func TestProcessManager_Stop(t *testing.T) {
 			plugin.Backend = true
 		})
 
-		m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{
+		m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
 			pluginID: p,
 		}))
 		err := m.Stop(context.Background(), pluginID)
@@ -116,7 +119,7 @@ func TestProcessManager_ManagedBackendPluginLifecycle(t *testing.T) {
 		plugin.Backend = true
 	})
 
-	m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{
+	m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
 		p.ID: p,
 	}))
 
@@ -162,6 +165,72 @@ func TestProcessManager_ManagedBackendPluginLifecycle(t *testing.T) {
 	})
 }
 
+func TestProcessManager_RestartPolicy(t *testing.T) {
+	bp := newFakeBackendPlugin(true)
+	p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+		plugin.Backend = true
+	})
+
+	cfg := &config.Cfg{PluginProcesses: map[string]config.PluginProcess{
+		p.ID: {MemoryLimit: 1 << 20, MaxRestarts: 1, RestartBackoff: time.Millisecond},
+	}}
+	m := NewManager(cfg, newFakePluginRegistry(map[string]*plugins.Plugin{
+		p.ID: p,
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	err := m.Start(ctx, p.ID)
+	require.NoError(t, err)
+
+	health, err := m.Health(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, Health{PluginID: p.ID, State: StateRunning, MemoryLimit: 1 << 20}, health)
+
+	t.Run("When plugin process crashes, the process is restarted", func(t *testing.T) {
+		bp.crash("exit status 2")
+		require.Eventually(t, func() bool { return !bp.Exited() }, 5*time.Second, 10*time.Millisecond)
+
+		health, err := m.Health(ctx, p.ID)
+		require.NoError(t, err)
+		require.Equal(t, StateRunning, health.State)
+		require.Equal(t, 1, health.Restarts)
+		require.Equal(t, "exit status 2", health.LastExitReason)
+		require.NotNil(t, health.LastExitTime)
+	})
+
+	t.Run("When plugin process crashes more than the maximum restarts, the process is not restarted", func(t *testing.T) {
+		bp.crash("signal: killed")
+		require.Eventually(t, func() bool {
+			health, err := m.Health(ctx, p.ID)
+			return err == nil && health.State == StateFailed
+		}, 5*time.Second, 10*time.Millisecond)
+
+		health, err := m.Health(ctx, p.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, health.Restarts)
+		require.Equal(t, "signal: killed", health.LastExitReason)
+		require.Equal(t, 2, bp.startCount)
+	})
+
+	_, err = m.Health(ctx, "other-datasource")
+	require.ErrorIs(t, err, ErrProcessNotFound)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	settings := config.PluginProcess{RestartBackoff: time.Second, MaxRestartBackoff: 5 * time.Second}
+	for crashes, expected := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+		9: 5 * time.Second,
+	} {
+		require.Equal(t, expected, restartBackoff(settings, crashes))
+	}
+	require.Equal(t, time.Duration(0), restartBackoff(config.PluginProcess{}, 3))
+}
+
 type fakePluginRegistry struct {
 	store map[string]*plugins.Plugin
 }
@@ -203,6 +272,8 @@ type fakeBackendPlugin struct {
 	stopCount      int
 	decommissioned bool
 	running        bool
+	exitReason     string
+	cgroup         string
 
 	mutex sync.RWMutex
 	backendplugin.Plugin
@@ -255,6 +326,36 @@ func (p *fakeBackendPlugin) Exited() bool {
 	return !p.running
 }
 
+func (p *fakeBackendPlugin) Pid() int {
+	return 0
+}
+
+func (p *fakeBackendPlugin) ExitReason() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.running {
+		return ""
+	}
+	return p.exitReason
+}
+
+func (p *fakeBackendPlugin) SetCgroup(path string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.running {
+		return errors.New("cgroup must be set before the process is started")
+	}
+	p.cgroup = path
+	return nil
+}
+
+func (p *fakeBackendPlugin) crash(reason string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.running = false
+	p.exitReason = reason
+}
+
 func (p *fakeBackendPlugin) kill() {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -298,3 +298,30 @@ type QueryCachingConfig struct {
 	Enabled bool  `json:"enabled"`
 	TTLMS   int64 `json:"TTLMs"`
 }
+
+// InstallPlan is the plan of the installation of a plugin, with the versions of the plugins it depends on.
+type InstallPlan struct {
+	PluginID string `json:"pluginId"`
+	Version  string `json:"version"`
+	// Steps are in installation order, the dependencies of a plugin before the plugin.
+	Steps []InstallStep `json:"steps"`
+}
+
+type InstallAction string
+
+const (
+	InstallActionInstall InstallAction = "install"
+	InstallActionUpdate  InstallAction = "update"
+	// InstallActionKeep is the action for installed plugins that satisfy the dependencies of the plan.
+	InstallActionKeep InstallAction = "keep"
+)
+
+type InstallStep struct {
+	PluginID string        `json:"pluginId"`
+	Version  string        `json:"version"`
+	Action   InstallAction `json:"action"`
+	// InstalledVersion is the version of the plugin installed before the plan is applied.
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	// RequiredBy lists the plugins of the plan that depend on the plugin.
+	RequiredBy []string `json:"requiredBy,omitempty"`
+}
@@ -337,6 +337,12 @@ func (p *Plugin) Client() (PluginClient, bool) {
 	return nil, false
 }
 
+// Process returns the process of the backend plugin, if it runs in a process of its own.
+func (p *Plugin) Process() (backendplugin.Process, bool) {
+	process, ok := p.client.(backendplugin.Process)
+	return process, ok
+}
+
 func (p *Plugin) ExecutablePath() string {
 	if p.IsRenderer() {
 		return p.executablePath("plugin_start")
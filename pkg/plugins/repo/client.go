@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/plugins/log"
@@ -22,6 +23,10 @@ type Client struct {
 	httpClient          http.Client
 	httpClientNoTimeout http.Client
 	retryCount          int
+	// token is sent as a bearer token to private repositories, only in requests to tokenScheme://tokenHost
+	token       string
+	tokenScheme string
+	tokenHost   string
 
 	log log.PrettyLogger
 }
@@ -82,10 +87,15 @@ func (c *Client) downloadFile(tmpFile *os.File, pluginURL, checksum string, comp
 				c.log.Warn("Failed to close file", "err", err)
 			}
 		}()
-		_, err = io.Copy(tmpFile, f)
+		h := sha256.New()
+		_, err = io.Copy(tmpFile, io.TeeReader(f, h))
 		if err != nil {
 			return fmt.Errorf("%v: %w", "Failed to copy plugin archive", err)
 		}
+		// archives of air-gapped mirrors are verified like downloaded ones
+		if len(checksum) > 0 && checksum != fmt.Sprintf("%x", h.Sum(nil)) {
+			return fmt.Errorf("expected SHA256 checksum does not match the plugin archive %s", pluginURL)
+		}
 		return nil
 	}
 
@@ -148,11 +158,34 @@ func (c *Client) downloadFile(tmpFile *os.File, pluginURL, checksum string, comp
 	return nil
 }
 
+// readFile reads a file from a URL or, for air-gapped installations, from a local path.
+func (c *Client) readFile(location string, compatOpts CompatOpts) ([]byte, error) {
+	if _, err := os.Stat(location); err == nil {
+		// We can ignore this gosec G304 warning since the location stems from the configuration of the repository.
+		// nolint:gosec
+		return os.ReadFile(location)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	return c.sendReq(u, compatOpts)
+}
+
 func (c *Client) sendReq(url *url.URL, compatOpts CompatOpts) ([]byte, error) {
+	return c.sendReqAccept(url, "", compatOpts)
+}
+
+// sendReqAccept sends a request that accepts the given media type, like the media type of OCI manifests.
+func (c *Client) sendReqAccept(url *url.URL, accept string, compatOpts CompatOpts) ([]byte, error) {
 	req, err := c.createReq(url, compatOpts)
 	if err != nil {
 		return nil, err
 	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
@@ -193,6 +226,10 @@ func (c *Client) createReq(url *url.URL, compatOpts CompatOpts) (*http.Request,
 	req.Header.Set("grafana-os", compatOpts.OS)
 	req.Header.Set("grafana-arch", compatOpts.Arch)
 	req.Header.Set("User-Agent", "grafana "+compatOpts.GrafanaVersion)
+	// archives of an index can be hosted anywhere, the token is only for the repository itself
+	if c.token != "" && strings.EqualFold(url.Scheme, c.tokenScheme) && strings.EqualFold(url.Host, c.tokenHost) {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 
 	return req, err
 }
@@ -14,6 +14,8 @@ type Service interface {
 	GetPluginArchiveByURL(ctx context.Context, archiveURL string, opts CompatOpts) (*PluginArchive, error)
 	// GetPluginDownloadOptions fetches information for downloading the requested plugin.
 	GetPluginDownloadOptions(ctx context.Context, pluginID, version string, opts CompatOpts) (*PluginDownloadOptions, error)
+	// GetPluginVersions fetches the versions of the requested plugin supported by the system, newest first.
+	GetPluginVersions(ctx context.Context, pluginID string, opts CompatOpts) ([]Version, error)
 }
 
 type CompatOpts struct {
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+// indexRepository is a static index of plugin versions and archives, served by a file server or read
+// from the local file system in air-gapped installations. The index has the format of the grafana.com
+// plugin list, with the location of the archive of each version and system next to its checksum:
+//
+//	{
+//	  "plugins": [{
+//	    "id": "grafana-clock-panel",
+//	    "versions": [{
+//	      "version": "2.1.3",
+//	      "arch": {"any": {"sha256": "...", "url": "grafana-clock-panel-2.1.3.zip"}},
+//	      "grafanaDependency": ">=8.0.0",
+//	      "dependencies": []
+//	    }]
+//	  }]
+//	}
+//
+// Archive locations are relative to the index. Archives without a checksum are not installed. The dependencies
+// of the plugin.json of a version are optional, the resolver reads them from the archive otherwise.
+type indexRepository struct {
+	repoName string
+	indexURL string
+	c        *Client
+	log      log.PrettyLogger
+}
+
+func (r *indexRepository) name() string    { return r.repoName }
+func (r *indexRepository) client() *Client { return r.c }
+
+func (r *indexRepository) plugin(_ context.Context, pluginID string, compatOpts CompatOpts) (*Plugin, error) {
+	body, err := r.c.readFile(r.indexURL, compatOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var index PluginRepo
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("invalid plugin index %s: %w", r.indexURL, err)
+	}
+
+	for _, p := range index.Plugins {
+		if p.ID != pluginID {
+			continue
+		}
+		plugin := p
+		sortVersions(plugin.Versions)
+		return &plugin, nil
+	}
+	return nil, ErrPluginNotFound{PluginID: pluginID}
+}
+
+func (r *indexRepository) downloadOptions(_ context.Context, pluginID string, v *Version, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
+	archMeta, exists := v.Arch[compatOpts.OSAndArch()]
+	if !exists {
+		archMeta = v.Arch["any"]
+	}
+	if archMeta.SHA256 == "" {
+		return nil, ErrChecksumMissing{PluginID: pluginID, Version: v.Version, Repository: r.repoName}
+	}
+	if archMeta.URL == "" {
+		return nil, fmt.Errorf("%s v%s has no archive in repository %s", pluginID, v.Version, r.repoName)
+	}
+
+	archiveURL, err := r.resolve(archMeta.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &PluginDownloadOptions{
+		Version:      v.Version,
+		Checksum:     archMeta.SHA256,
+		PluginZipURL: archiveURL,
+		Repository:   r.repoName,
+	}, nil
+}
+
+// resolve resolves the location of an archive relative to the index.
+func (r *indexRepository) resolve(location string) (string, error) {
+	if _, err := os.Stat(r.indexURL); err == nil {
+		if filepath.IsAbs(location) {
+			return location, nil
+		}
+		return filepath.Join(filepath.Dir(r.indexURL), filepath.FromSlash(location)), nil
+	}
+
+	base, err := url.Parse(r.indexURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// sortVersions sorts versions newest first, as expected by selectVersion.
+func sortVersions(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := version.NewVersion(versions[i].Version)
+		vj, errJ := version.NewVersion(versions[j].Version)
+		if errI != nil || errJ != nil {
+			return errJ != nil && errI == nil
+		}
+		return vi.GreaterThan(vj)
+	})
+}
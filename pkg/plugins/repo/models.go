@@ -3,6 +3,9 @@ package repo
 import (
 	"archive/zip"
 	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/plugins"
 )
 
 type PluginArchive struct {
@@ -13,6 +16,8 @@ type PluginDownloadOptions struct {
 	PluginZipURL string
 	Version      string
 	Checksum     string
+	// Repository is the name of the repository the plugin is downloaded from.
+	Repository string
 }
 
 type Plugin struct {
@@ -26,10 +31,16 @@ type Version struct {
 	URL     string              `json:"repoURL"`
 	Version string              `json:"version"`
 	Arch    map[string]ArchMeta `json:"arch"`
+	// GrafanaDependency and Dependencies are the dependencies declared in the plugin.json of the version,
+	// for repositories that provide them. Dependencies is nil if the repository does not.
+	GrafanaDependency string               `json:"grafanaDependency,omitempty"`
+	Dependencies      []plugins.Dependency `json:"dependencies,omitempty"`
 }
 
 type ArchMeta struct {
 	SHA256 string `json:"sha256"`
+	// URL is the location of the archive in static index repositories, relative to the index.
+	URL string `json:"url,omitempty"`
 }
 
 type PluginRepo struct {
@@ -53,6 +64,24 @@ func (e Response4xxError) Error() string {
 	return fmt.Sprintf("%d", e.StatusCode)
 }
 
+type ErrPluginNotFound struct {
+	PluginID string
+}
+
+func (e ErrPluginNotFound) Error() string {
+	return fmt.Sprintf("plugin %s not found in the plugin repositories", e.PluginID)
+}
+
+type ErrChecksumMissing struct {
+	PluginID   string
+	Version    string
+	Repository string
+}
+
+func (e ErrChecksumMissing) Error() string {
+	return fmt.Sprintf("%s v%s has no checksum in repository %s", e.PluginID, e.Version, e.Repository)
+}
+
 type ErrArcNotFound struct {
 	PluginID   string
 	SystemInfo string
@@ -81,3 +110,32 @@ type ErrVersionNotFound struct {
 func (e ErrVersionNotFound) Error() string {
 	return fmt.Sprintf("%s v%s either does not exist or is not supported on your system (%s)", e.PluginID, e.RequestedVersion, e.SystemInfo)
 }
+
+type ErrGrafanaVersionUnsupported struct {
+	PluginID          string
+	Version           string
+	GrafanaDependency string
+	GrafanaVersion    string
+}
+
+func (e ErrGrafanaVersionUnsupported) Error() string {
+	if e.Version == "" {
+		return fmt.Sprintf("no version of %s supports Grafana v%s", e.PluginID, e.GrafanaVersion)
+	}
+	return fmt.Sprintf("%s v%s requires Grafana %s, but the Grafana version is v%s", e.PluginID, e.Version, e.GrafanaDependency, e.GrafanaVersion)
+}
+
+// ErrDependencyConflict is returned when no version of a plugin satisfies the version ranges
+// that the other plugins require.
+type ErrDependencyConflict struct {
+	PluginID     string
+	Requirements []Requirement
+}
+
+func (e ErrDependencyConflict) Error() string {
+	requirements := make([]string, 0, len(e.Requirements))
+	for _, r := range e.Requirements {
+		requirements = append(requirements, r.String())
+	}
+	return fmt.Sprintf("no version of %s satisfies the dependencies: %s", e.PluginID, strings.Join(requirements, ", "))
+}
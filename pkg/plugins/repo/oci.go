@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// ociArchAnnotation is the annotation of the layers of a plugin version with the system the archive is for,
+	// like linux-amd64. Archives for any system are annotated with any or not annotated.
+	ociArchAnnotation = "com.grafana.plugin.arch"
+)
+
+// ociRepository is an OCI registry with a repository per plugin, named after the plugin ID, and a tag per
+// version, like 1.2.3. The archives of a version are the layers of its manifest and are verified against their digest.
+type ociRepository struct {
+	repoName string
+	// registry is the base URL of the registry API and namespace the path of the plugin repositories in it
+	registry  *url.URL
+	namespace string
+	c         *Client
+}
+
+type ociTags struct {
+	Tags []string `json:"tags"`
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func newOCIRepository(name, rawURL string, c *Client) (*ociRepository, error) {
+	u, err := url.Parse(strings.TrimPrefix(rawURL, "oci://"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" {
+		// oci://registry.example.com/plugins
+		u, err = url.Parse("https://" + strings.TrimPrefix(rawURL, "oci://"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	namespace := strings.Trim(u.Path, "/")
+	u.Path = "/v2"
+	return &ociRepository{repoName: name, registry: u, namespace: namespace, c: c}, nil
+}
+
+func (r *ociRepository) name() string    { return r.repoName }
+func (r *ociRepository) client() *Client { return r.c }
+
+func (r *ociRepository) endpoint(pluginID string, elem ...string) *url.URL {
+	u := *r.registry
+	u.Path = path.Join(append([]string{u.Path, r.namespace, pluginID}, elem...)...)
+	return &u
+}
+
+func (r *ociRepository) plugin(_ context.Context, pluginID string, compatOpts CompatOpts) (*Plugin, error) {
+	body, err := r.c.sendReq(r.endpoint(pluginID, "tags", "list"), compatOpts)
+	if err != nil {
+		if isNotFoundResponse(err) {
+			return nil, ErrPluginNotFound{PluginID: pluginID}
+		}
+		return nil, err
+	}
+
+	var tags ociTags
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("invalid tag list of plugin %s in repository %s: %w", pluginID, r.repoName, err)
+	}
+
+	plugin := &Plugin{ID: pluginID}
+	for _, tag := range tags.Tags {
+		// tags like latest are not versions
+		if _, err := version.NewVersion(tag); err != nil {
+			continue
+		}
+		plugin.Versions = append(plugin.Versions, Version{Version: tag})
+	}
+	if len(plugin.Versions) == 0 {
+		return nil, ErrPluginNotFound{PluginID: pluginID}
+	}
+	sortVersions(plugin.Versions)
+	return plugin, nil
+}
+
+func (r *ociRepository) downloadOptions(_ context.Context, pluginID string, v *Version, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
+	body, err := r.c.sendReqAccept(r.endpoint(pluginID, "manifests", v.Version), ociManifestMediaType, compatOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of %s v%s in repository %s: %w", pluginID, v.Version, r.repoName, err)
+	}
+
+	layer, ok := selectLayer(manifest.Layers, compatOpts)
+	if !ok {
+		return nil, ErrArcNotFound{PluginID: pluginID, SystemInfo: compatOpts.OSAndArch()}
+	}
+	checksum := strings.TrimPrefix(layer.Digest, "sha256:")
+	if checksum == layer.Digest || checksum == "" {
+		return nil, ErrChecksumMissing{PluginID: pluginID, Version: v.Version, Repository: r.repoName}
+	}
+
+	return &PluginDownloadOptions{
+		Version:      v.Version,
+		Checksum:     checksum,
+		PluginZipURL: r.endpoint(pluginID, "blobs", layer.Digest).String(),
+		Repository:   r.repoName,
+	}, nil
+}
+
+// selectLayer selects the archive for the system, or else the archive for any system.
+func selectLayer(layers []ociDescriptor, compatOpts CompatOpts) (ociDescriptor, bool) {
+	var anyArch *ociDescriptor
+	for i, layer := range layers {
+		switch layer.Annotations[ociArchAnnotation] {
+		case compatOpts.OSAndArch():
+			return layer, true
+		case "any", "":
+			if anyArch == nil {
+				anyArch = &layers[i]
+			}
+		}
+	}
+	if anyArch == nil {
+		return ociDescriptor{}, false
+	}
+	return *anyArch, true
+}
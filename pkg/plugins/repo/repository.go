@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/plugins/config"
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+// repository is a source of plugin versions and archives.
+type repository interface {
+	name() string
+	client() *Client
+	// plugin returns the versions of a plugin, newest first. It returns ErrPluginNotFound if the
+	// repository does not have the plugin.
+	plugin(ctx context.Context, pluginID string, compatOpts CompatOpts) (*Plugin, error)
+	// downloadOptions returns the location and checksum of the archive of a plugin version for the system.
+	downloadOptions(ctx context.Context, pluginID string, v *Version, compatOpts CompatOpts) (*PluginDownloadOptions, error)
+}
+
+func newRepository(cfg config.PluginRepository, logger log.PrettyLogger) (repository, error) {
+	c := newClient(cfg.SkipTLSVerify, logger)
+	if cfg.Token != "" {
+		u, err := url.Parse(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("plugin repository %s has an invalid url: %w", cfg.Name, err)
+		}
+		c.token, c.tokenScheme, c.tokenHost = cfg.Token, u.Scheme, u.Host
+	}
+
+	switch cfg.Type {
+	case config.PluginRepositoryGrafanaCom, "":
+		return &grafanaComRepository{repoName: cfg.Name, baseURL: cfg.URL, c: c, log: logger}, nil
+	case config.PluginRepositoryIndex:
+		return &indexRepository{repoName: cfg.Name, indexURL: cfg.URL, c: c, log: logger}, nil
+	case config.PluginRepositoryOCI:
+		return newOCIRepository(cfg.Name, cfg.URL, c)
+	}
+	return nil, fmt.Errorf("plugin repository %s has an unsupported type %q", cfg.Name, cfg.Type)
+}
+
+// ParseRepository parses a repository given on the command line as [type+]url, like
+// index+https://mirror.example.com/plugins/index.json. Repositories without a type are
+// grafana.com plugin APIs.
+func ParseRepository(s string) (config.PluginRepository, error) {
+	repoType, repoURL := config.PluginRepositoryGrafanaCom, strings.TrimSpace(s)
+	if before, after, found := strings.Cut(repoURL, "+"); found && !strings.Contains(before, "/") {
+		repoType, repoURL = before, after
+	}
+	switch repoType {
+	case config.PluginRepositoryGrafanaCom, config.PluginRepositoryIndex, config.PluginRepositoryOCI:
+	default:
+		return config.PluginRepository{}, fmt.Errorf("unsupported plugin repository type %q", repoType)
+	}
+	if repoURL == "" {
+		return config.PluginRepository{}, errors.New("plugin repository url is empty")
+	}
+	return config.PluginRepository{Name: repoURL, Type: repoType, URL: repoURL}, nil
+}
+
+// grafanaComRepository is the grafana.com plugin API, or a mirror of it.
+type grafanaComRepository struct {
+	repoName string
+	baseURL  string
+	c        *Client
+	log      log.PrettyLogger
+}
+
+func (r *grafanaComRepository) name() string    { return r.repoName }
+func (r *grafanaComRepository) client() *Client { return r.c }
+
+func (r *grafanaComRepository) plugin(_ context.Context, pluginID string, compatOpts CompatOpts) (*Plugin, error) {
+	u, err := url.Parse(r.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "repo", pluginID)
+
+	body, err := r.c.sendReq(u, compatOpts)
+	if err != nil {
+		if isNotFoundResponse(err) {
+			return nil, ErrPluginNotFound{PluginID: pluginID}
+		}
+		return nil, err
+	}
+
+	var data Plugin
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		r.log.Error("Failed to unmarshal plugin repo response error", err)
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (r *grafanaComRepository) downloadOptions(_ context.Context, pluginID string, v *Version, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
+	// Plugins which are downloaded just as sourcecode zipball from GitHub do not have checksum
+	var checksum string
+	if v.Arch != nil {
+		archMeta, exists := v.Arch[compatOpts.OSAndArch()]
+		if !exists {
+			archMeta = v.Arch["any"]
+		}
+		checksum = archMeta.SHA256
+	}
+
+	return &PluginDownloadOptions{
+		Version:      v.Version,
+		Checksum:     checksum,
+		PluginZipURL: fmt.Sprintf("%s/%s/versions/%s/download", r.baseURL, pluginID, v.Version),
+		Repository:   r.repoName,
+	}, nil
+}
+
+func isNotFoundResponse(err error) bool {
+	var clientErr Response4xxError
+	return errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound
+}
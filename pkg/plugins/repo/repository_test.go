@@ -0,0 +1,238 @@
+package repo
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+var testCompatOpts = NewCompatOpts("10.0.0", "linux", "amd64")
+
+func TestParseRepository(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		expected config.PluginRepository
+		err      bool
+	}{
+		{spec: "https://grafana.com/api/plugins", expected: config.PluginRepository{Type: config.PluginRepositoryGrafanaCom, URL: "https://grafana.com/api/plugins"}},
+		{spec: "index+/mnt/mirror/index.json", expected: config.PluginRepository{Type: config.PluginRepositoryIndex, URL: "/mnt/mirror/index.json"}},
+		{spec: " oci+https://registry.example.com/plugins", expected: config.PluginRepository{Type: config.PluginRepositoryOCI, URL: "https://registry.example.com/plugins"}},
+		{spec: "https://example.com/plugins?a=b+c", expected: config.PluginRepository{Type: config.PluginRepositoryGrafanaCom, URL: "https://example.com/plugins?a=b+c"}},
+		{spec: "git+https://example.com/plugins", err: true},
+		{spec: "index+", err: true},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			r, err := ParseRepository(tc.spec)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected.Type, r.Type)
+			require.Equal(t, tc.expected.URL, r.URL)
+		})
+	}
+}
+
+func TestIndexRepository(t *testing.T) {
+	archive := createArchive(t, "test-panel")
+	checksum := fmt.Sprintf("%x", sha256.Sum256(archive))
+
+	index := fmt.Sprintf(`{"plugins": [{"id": "test-panel", "versions": [
+		{"version": "1.0.0", "arch": {"any": {"sha256": %q, "url": "archives/test-panel-1.0.0.zip"}}},
+		{"version": "1.1.0", "arch": {"any": {"url": "archives/test-panel-1.1.0.zip"}}},
+		{"version": "1.2.0", "arch": {"darwin-arm64": {"sha256": %q, "url": "archives/test-panel-1.2.0.zip"}}}
+	]}]}`, checksum, checksum)
+
+	t.Run("Should install the latest version for the system from an index on disk", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "archives"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), []byte(index), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "archives", "test-panel-1.0.0.zip"), archive, 0600))
+
+		m := newTestManager(t, config.PluginRepository{Name: "mirror", Type: config.PluginRepositoryIndex, URL: filepath.Join(dir, "index.json")})
+
+		_, err := m.GetPluginDownloadOptions(context.Background(), "test-panel", "1.1.0", testCompatOpts)
+		var checksumErr ErrChecksumMissing
+		require.ErrorAs(t, err, &checksumErr)
+
+		dlOpts, err := m.GetPluginDownloadOptions(context.Background(), "test-panel", "1.0.0", testCompatOpts)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "archives", "test-panel-1.0.0.zip"), dlOpts.PluginZipURL)
+		require.Equal(t, "mirror", dlOpts.Repository)
+
+		pluginArchive, err := m.GetPluginArchive(context.Background(), "test-panel", "1.0.0", testCompatOpts)
+		require.NoError(t, err)
+		require.Len(t, pluginArchive.File.File, 1)
+		require.NoError(t, pluginArchive.File.Close())
+	})
+
+	t.Run("Should resolve archives relative to an index served over HTTP", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.URL.Path {
+			case "/mirror/index.json":
+				_, _ = w.Write([]byte(index))
+			case "/mirror/archives/test-panel-1.0.0.zip":
+				_, _ = w.Write(archive)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		m := newTestManager(t, config.PluginRepository{Name: "mirror", Type: config.PluginRepositoryIndex, URL: srv.URL + "/mirror/index.json", Token: "secret"})
+
+		dlOpts, err := m.GetPluginDownloadOptions(context.Background(), "test-panel", "1.0.0", testCompatOpts)
+		require.NoError(t, err)
+		require.Equal(t, srv.URL+"/mirror/archives/test-panel-1.0.0.zip", dlOpts.PluginZipURL)
+
+		pluginArchive, err := m.GetPluginArchive(context.Background(), "test-panel", "1.0.0", testCompatOpts)
+		require.NoError(t, err)
+		require.NoError(t, pluginArchive.File.Close())
+	})
+
+	t.Run("Should not send the token to other hosts", func(t *testing.T) {
+		archives := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write(archive)
+		}))
+		t.Cleanup(archives.Close)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"plugins": [{"id": "test-panel", "versions": [
+				{"version": "1.0.0", "arch": {"any": {"sha256": %q, "url": %q}}}
+			]}]}`, checksum, archives.URL+"/test-panel-1.0.0.zip")
+		}))
+		t.Cleanup(srv.Close)
+
+		m := newTestManager(t, config.PluginRepository{Name: "mirror", Type: config.PluginRepositoryIndex, URL: srv.URL + "/index.json", Token: "secret"})
+
+		pluginArchive, err := m.GetPluginArchive(context.Background(), "test-panel", "1.0.0", testCompatOpts)
+		require.NoError(t, err)
+		require.NoError(t, pluginArchive.File.Close())
+	})
+}
+
+func TestOCIRepository(t *testing.T) {
+	archive := createArchive(t, "test-panel")
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(archive))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/plugins/test-panel/tags/list":
+			_, _ = w.Write([]byte(`{"name": "plugins/test-panel", "tags": ["latest", "1.0.0", "1.1.0"]}`))
+		case "/v2/plugins/test-panel/manifests/1.1.0":
+			require.Equal(t, ociManifestMediaType, r.Header.Get("Accept"))
+			_, _ = fmt.Fprintf(w, `{"layers": [
+				{"mediaType": "application/zip", "digest": "sha256:0000", "annotations": {%q: "darwin-arm64"}},
+				{"mediaType": "application/zip", "digest": %q, "annotations": {%q: "linux-amd64"}}
+			]}`, ociArchAnnotation, digest, ociArchAnnotation)
+		case "/v2/plugins/test-panel/blobs/" + digest:
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	m := newTestManager(t, config.PluginRepository{Name: "registry", Type: config.PluginRepositoryOCI, URL: srv.URL + "/plugins"})
+
+	dlOpts, err := m.GetPluginDownloadOptions(context.Background(), "test-panel", "", testCompatOpts)
+	require.NoError(t, err)
+	require.Equal(t, "1.1.0", dlOpts.Version)
+	require.Equal(t, digest[len("sha256:"):], dlOpts.Checksum)
+
+	pluginArchive, err := m.GetPluginArchive(context.Background(), "test-panel", "", testCompatOpts)
+	require.NoError(t, err)
+	require.NoError(t, pluginArchive.File.Close())
+
+	_, err = m.GetPluginDownloadOptions(context.Background(), "other-panel", "", testCompatOpts)
+	var notFoundErr ErrPluginNotFound
+	require.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestManagerFallsBackToNextRepository(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), []byte(`{"plugins": [{"id": "test-panel", "versions": [
+		{"version": "1.0.0", "arch": {"any": {"sha256": "abc", "url": "test-panel-1.0.0.zip"}}}
+	]}]}`), 0600))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/plugins/repo/test-panel":
+			_, _ = w.Write([]byte(`{"id": "test-panel", "versions": [{"version": "2.0.0"}, {"version": "1.0.0"}]}`))
+		case "/api/plugins/repo/other-panel":
+			_, _ = w.Write([]byte(`{"id": "other-panel", "versions": [{"version": "1.0.0"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	m := newTestManager(t,
+		config.PluginRepository{Name: "mirror", Type: config.PluginRepositoryIndex, URL: filepath.Join(dir, "index.json")},
+		config.PluginRepository{Name: "grafana.com", Type: config.PluginRepositoryGrafanaCom, URL: srv.URL + "/api/plugins"},
+	)
+
+	for _, tc := range []struct {
+		pluginID, version, expectedVersion, expectedRepository string
+	}{
+		{pluginID: "test-panel", expectedVersion: "1.0.0", expectedRepository: "mirror"},
+		{pluginID: "test-panel", version: "2.0.0", expectedVersion: "2.0.0", expectedRepository: "grafana.com"},
+		{pluginID: "other-panel", expectedVersion: "1.0.0", expectedRepository: "grafana.com"},
+	} {
+		dlOpts, err := m.GetPluginDownloadOptions(context.Background(), tc.pluginID, tc.version, testCompatOpts)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedVersion, dlOpts.Version)
+		require.Equal(t, tc.expectedRepository, dlOpts.Repository)
+	}
+
+	_, err := m.GetPluginDownloadOptions(context.Background(), "test-panel", "3.0.0", testCompatOpts)
+	var versionNotFoundErr ErrVersionNotFound
+	require.ErrorAs(t, err, &versionNotFoundErr)
+
+	_, err = m.GetPluginDownloadOptions(context.Background(), "missing-panel", "", testCompatOpts)
+	var notFoundErr ErrPluginNotFound
+	require.ErrorAs(t, err, &notFoundErr)
+}
+
+func newTestManager(t *testing.T, repositories ...config.PluginRepository) *Manager {
+	t.Helper()
+	m, err := NewManager(false, repositories, &fakeLogger{})
+	require.NoError(t, err)
+	return m
+}
+
+func createArchive(t *testing.T, pluginID string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create(pluginID + "/plugin.json")
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(f, `{"id": %q, "type": "panel"}`, pluginID)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,586 @@
+package repo
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+// maxArchiveReads limits the number of versions of a plugin whose archive is downloaded to read their
+// dependencies when the repository does not provide them. Older versions are not considered.
+const maxArchiveReads = 5
+
+var errArchiveReadLimit = errors.New("too many plugin archives downloaded to read dependencies")
+
+// Requirement is a version range of a plugin that another plugin depends on.
+type Requirement struct {
+	PluginID string `json:"pluginId"`
+	// Version is a semver range, like >=1.2.0 or ^2.0.0. Any version satisfies an empty range.
+	Version string `json:"version"`
+	// RequiredBy is the plugin that depends on the plugin.
+	RequiredBy string `json:"requiredBy"`
+}
+
+func (r Requirement) String() string {
+	if r.Version == "" {
+		return fmt.Sprintf("%s requires any version", r.RequiredBy)
+	}
+	return fmt.Sprintf("%s requires %s", r.RequiredBy, r.Version)
+}
+
+// InstalledPlugin is a plugin installed in Grafana. The resolver keeps installed plugins unless
+// a plugin of the plan requires another version.
+type InstalledPlugin struct {
+	ID      string
+	Version string
+	// Locked plugins, like core and bundled plugins, can't be updated.
+	Locked       bool
+	Dependencies []plugins.Dependency
+}
+
+// Resolver plans the installation of a plugin and of the plugins it depends on. It selects the newest
+// versions that support the Grafana version and the system, and that satisfy the version ranges the
+// plugins require from each other and the installed plugins require from them.
+type Resolver struct {
+	repo Service
+	log  log.PrettyLogger
+}
+
+func NewResolver(repo Service, logger log.PrettyLogger) *Resolver {
+	return &Resolver{repo: repo, log: logger}
+}
+
+// Resolve returns the plan of the installation of a plugin, in the latest version if version is empty.
+// The archives downloaded to read dependencies are kept in archives to install the plan, unless it is nil.
+func (r *Resolver) Resolve(ctx context.Context, pluginID, version string, installed []InstalledPlugin,
+	compatOpts CompatOpts, archives *ArchiveCache) (*plugins.InstallPlan, error) {
+	res := &resolution{
+		Resolver:     r,
+		ctx:          ctx,
+		compatOpts:   compatOpts,
+		pluginID:     pluginID,
+		version:      normalizeVersion(version),
+		installed:    map[string]InstalledPlugin{},
+		versions:     map[string][]Version{},
+		dependencies: map[string]versionDependencies{},
+		archives:     archives,
+		archiveReads: map[string]int{},
+	}
+
+	st := &state{selected: map[string]selection{}}
+	for _, p := range installed {
+		res.installed[p.ID] = p
+		if p.ID == pluginID {
+			if p.Locked {
+				return nil, plugins.ErrInstallCorePlugin
+			}
+			// the requirements of the installed version don't apply to the version that replaces it
+			continue
+		}
+		for _, d := range p.Dependencies {
+			st.requirements = append(st.requirements, Requirement{PluginID: d.ID, Version: d.Version, RequiredBy: p.ID})
+		}
+	}
+
+	solved, err := res.solve(st, []string{pluginID})
+	if err != nil {
+		return nil, err
+	}
+	if solved.selected[pluginID].action == plugins.InstallActionKeep {
+		return nil, plugins.DuplicateError{PluginID: pluginID}
+	}
+	return res.plan(solved), nil
+}
+
+// resolution is the context of the resolution of a plan, with the metadata fetched from the repository.
+type resolution struct {
+	*Resolver
+	ctx        context.Context
+	compatOpts CompatOpts
+	// pluginID and version are the requested plugin and version
+	pluginID string
+	version  string
+
+	installed    map[string]InstalledPlugin
+	versions     map[string][]Version
+	dependencies map[string]versionDependencies
+	archives     *ArchiveCache
+	archiveReads map[string]int
+}
+
+// ArchiveCache keeps the plugin archives downloaded while resolving a plan, so that they are not
+// downloaded again to install it.
+type ArchiveCache struct {
+	archives map[string]*PluginArchive
+}
+
+func NewArchiveCache() *ArchiveCache {
+	return &ArchiveCache{archives: map[string]*PluginArchive{}}
+}
+
+// Take returns the archive of a plugin version and removes it from the cache. The caller closes it.
+func (c *ArchiveCache) Take(pluginID, version string) (*PluginArchive, bool) {
+	key := pluginID + "@" + version
+	archive, exists := c.archives[key]
+	delete(c.archives, key)
+	return archive, exists
+}
+
+// Close closes the archives that were not taken.
+func (c *ArchiveCache) Close() error {
+	var closeErr error
+	for key, archive := range c.archives {
+		if err := archive.File.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+		delete(c.archives, key)
+	}
+	return closeErr
+}
+
+func (c *ArchiveCache) add(pluginID, version string, archive *PluginArchive) {
+	c.archives[pluginID+"@"+version] = archive
+}
+
+type versionDependencies struct {
+	grafanaDependency string
+	plugins           []plugins.Dependency
+}
+
+// state is a partial solution: the versions selected so far and the requirements they have to satisfy.
+type state struct {
+	selected     map[string]selection
+	requirements []Requirement
+}
+
+type selection struct {
+	version      string
+	action       plugins.InstallAction
+	dependencies []plugins.Dependency
+}
+
+// solve selects a version of the plugins of the queue and of their dependencies, backtracking to the
+// next candidate version of a plugin when the dependencies of a version can't be satisfied.
+func (res *resolution) solve(st *state, queue []string) (*state, error) {
+	if len(queue) == 0 {
+		return st, nil
+	}
+	pluginID, queue := queue[0], queue[1:]
+	if _, selected := st.selected[pluginID]; selected {
+		// the requirements on a selected plugin are checked when they are added
+		return res.solve(st, queue)
+	}
+
+	candidates, err := res.candidates(st, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolveErr error
+	for _, c := range candidates {
+		if c.action != plugins.InstallActionKeep {
+			deps, err := res.dependenciesOf(pluginID, c.version)
+			if errors.Is(err, errArchiveReadLimit) {
+				res.log.Debugf("Not considering versions of %s older than v%s", pluginID, c.version)
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !res.supportsGrafana(deps.grafanaDependency) {
+				resolveErr = ErrGrafanaVersionUnsupported{
+					PluginID:          pluginID,
+					Version:           c.version,
+					GrafanaDependency: deps.grafanaDependency,
+					GrafanaVersion:    res.compatOpts.GrafanaVersion,
+				}
+				continue
+			}
+			c.dependencies = deps.plugins
+		}
+
+		next, err := st.with(pluginID, c, res.installed)
+		if err != nil {
+			resolveErr = err
+			continue
+		}
+
+		depIDs := make([]string, 0, len(c.dependencies))
+		for _, d := range c.dependencies {
+			depIDs = append(depIDs, d.ID)
+		}
+		solved, err := res.solve(next, append(append([]string{}, queue...), depIDs...))
+		if err == nil {
+			return solved, nil
+		}
+		if !isResolveError(err) {
+			return nil, err
+		}
+		resolveErr = err
+	}
+
+	if resolveErr != nil {
+		var grafanaErr ErrGrafanaVersionUnsupported
+		if errors.As(resolveErr, &grafanaErr) && grafanaErr.PluginID == pluginID && (pluginID != res.pluginID || res.version == "") {
+			// the error of the oldest version is not the one to report when no version supports Grafana
+			grafanaErr.Version, grafanaErr.GrafanaDependency = "", ""
+			return nil, grafanaErr
+		}
+		return nil, resolveErr
+	}
+	return nil, ErrDependencyConflict{PluginID: pluginID, Requirements: st.requirementsOf(pluginID)}
+}
+
+// candidates returns the versions of a plugin that satisfy the requirements, in order of preference.
+func (res *resolution) candidates(st *state, pluginID string) ([]selection, error) {
+	if pluginID == res.pluginID {
+		return res.requestedCandidates(st)
+	}
+
+	requirements := st.requirementsOf(pluginID)
+	installed, isInstalled := res.installed[pluginID]
+
+	// keeping the installed version is preferred
+	var candidates []selection
+	if isInstalled && satisfiesAll(installed.Version, requirements) {
+		candidates = append(candidates, selection{
+			version:      installed.Version,
+			action:       plugins.InstallActionKeep,
+			dependencies: installed.Dependencies,
+		})
+	}
+	if isInstalled && installed.Locked {
+		return candidates, nil
+	}
+
+	versions, err := res.versionsOf(pluginID)
+	if err != nil {
+		var notFoundErr ErrPluginNotFound
+		var archErr ErrArcNotFound
+		if (errors.As(err, &notFoundErr) || errors.As(err, &archErr)) && len(candidates) > 0 {
+			return candidates, nil
+		}
+		return nil, err
+	}
+
+	action := plugins.InstallActionInstall
+	if isInstalled {
+		action = plugins.InstallActionUpdate
+	}
+	for _, v := range versions {
+		if isInstalled && normalizeVersion(v.Version) == normalizeVersion(installed.Version) {
+			continue
+		}
+		if satisfiesAll(v.Version, requirements) {
+			candidates = append(candidates, selection{version: v.Version, action: action})
+		}
+	}
+	return candidates, nil
+}
+
+// requestedCandidates returns the requested version of the requested plugin, or else its versions
+// newer than the installed one, newest first.
+func (res *resolution) requestedCandidates(st *state) ([]selection, error) {
+	requirements := st.requirementsOf(res.pluginID)
+	installed, isInstalled := res.installed[res.pluginID]
+	keep := selection{version: installed.Version, action: plugins.InstallActionKeep, dependencies: installed.Dependencies}
+	action := plugins.InstallActionInstall
+	if isInstalled {
+		action = plugins.InstallActionUpdate
+	}
+
+	versions, err := res.versionsOf(res.pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []selection
+	found := false
+	for _, v := range versions {
+		if res.version != "" && normalizeVersion(v.Version) != res.version {
+			continue
+		}
+		if isInstalled && res.version == "" && !isNewer(v.Version, installed.Version) {
+			break
+		}
+		if isInstalled && normalizeVersion(v.Version) == normalizeVersion(installed.Version) {
+			// the requested version is installed
+			return []selection{keep}, nil
+		}
+		found = true
+		if satisfiesAll(v.Version, requirements) {
+			candidates = append(candidates, selection{version: v.Version, action: action})
+		}
+	}
+	if isInstalled && res.version == "" && !found {
+		// the latest version is installed
+		return []selection{keep}, nil
+	}
+
+	if res.version != "" && !found {
+		return nil, ErrVersionNotFound{
+			PluginID:         res.pluginID,
+			RequestedVersion: res.version,
+			SystemInfo:       res.compatOpts.String(),
+		}
+	}
+	return candidates, nil
+}
+
+func (res *resolution) versionsOf(pluginID string) ([]Version, error) {
+	if versions, exists := res.versions[pluginID]; exists {
+		return versions, nil
+	}
+	versions, err := res.repo.GetPluginVersions(res.ctx, pluginID, res.compatOpts)
+	if err != nil {
+		return nil, err
+	}
+	res.versions[pluginID] = versions
+	return versions, nil
+}
+
+// dependenciesOf returns the dependencies of a plugin version. If the repository does not provide
+// them, they are read from the plugin.json of the archive of the version.
+func (res *resolution) dependenciesOf(pluginID, version string) (versionDependencies, error) {
+	key := pluginID + "@" + version
+	if deps, exists := res.dependencies[key]; exists {
+		return deps, nil
+	}
+
+	var deps versionDependencies
+	versions, err := res.versionsOf(pluginID)
+	if err != nil {
+		return deps, err
+	}
+	for _, v := range versions {
+		if v.Version == version && v.Dependencies != nil {
+			deps = versionDependencies{grafanaDependency: v.GrafanaDependency, plugins: v.Dependencies}
+			res.dependencies[key] = deps
+			return deps, nil
+		}
+	}
+
+	if res.archiveReads[pluginID] >= maxArchiveReads {
+		return deps, errArchiveReadLimit
+	}
+	res.archiveReads[pluginID]++
+
+	res.log.Debugf("Reading the dependencies of %s v%s from its archive", pluginID, version)
+	archive, err := res.repo.GetPluginArchive(res.ctx, pluginID, version, res.compatOpts)
+	if err != nil {
+		return deps, err
+	}
+	deps, err = readDependencies(archive.File)
+	if err == nil && res.archives != nil {
+		res.archives.add(pluginID, version, archive)
+	} else if closeErr := archive.File.Close(); closeErr != nil {
+		res.log.Warn("Failed to close plugin archive", "err", closeErr)
+	}
+	if err != nil {
+		return deps, fmt.Errorf("failed to read the dependencies of %s v%s: %w", pluginID, version, err)
+	}
+	res.dependencies[key] = deps
+	return deps, nil
+}
+
+func (res *resolution) supportsGrafana(grafanaDependency string) bool {
+	if grafanaDependency == "" {
+		return true
+	}
+	v, err := semver.NewVersion(res.compatOpts.GrafanaVersion)
+	if err != nil {
+		// development builds support all plugins
+		return true
+	}
+	// pre-releases of Grafana support the plugins of their release
+	release, err := v.SetPrerelease("")
+	if err != nil {
+		return true
+	}
+	c, err := semver.NewConstraint(grafanaDependency)
+	if err != nil {
+		res.log.Debugf("Ignoring invalid Grafana dependency %q", grafanaDependency)
+		return true
+	}
+	return c.Check(&release)
+}
+
+// with returns the state with a version selected for the plugin, or an error if the dependencies
+// of the version conflict with the versions already selected.
+func (st *state) with(pluginID string, s selection, installed map[string]InstalledPlugin) (*state, error) {
+	next := &state{
+		selected:     make(map[string]selection, len(st.selected)+1),
+		requirements: make([]Requirement, 0, len(st.requirements)+len(s.dependencies)),
+	}
+	for id, sel := range st.selected {
+		next.selected[id] = sel
+	}
+	next.selected[pluginID] = s
+
+	for _, r := range st.requirements {
+		if _, isInstalled := installed[pluginID]; isInstalled && r.RequiredBy == pluginID && s.action != plugins.InstallActionKeep {
+			// the requirements of the installed version don't apply to the version that replaces it
+			continue
+		}
+		next.requirements = append(next.requirements, r)
+	}
+
+	for _, d := range s.dependencies {
+		r := Requirement{PluginID: d.ID, Version: d.Version, RequiredBy: pluginID}
+		next.requirements = append(next.requirements, r)
+		if sel, selected := next.selected[d.ID]; selected && !satisfies(sel.version, r.Version) {
+			return nil, ErrDependencyConflict{PluginID: d.ID, Requirements: next.requirementsOf(d.ID)}
+		}
+	}
+	return next, nil
+}
+
+func (st *state) requirementsOf(pluginID string) []Requirement {
+	var requirements []Requirement
+	for _, r := range st.requirements {
+		if r.PluginID == pluginID {
+			requirements = append(requirements, r)
+		}
+	}
+	return requirements
+}
+
+// plan returns the steps to apply a solution, the dependencies of a plugin before the plugin.
+func (res *resolution) plan(st *state) *plugins.InstallPlan {
+	requiredBy := map[string][]string{}
+	for id, sel := range st.selected {
+		for _, d := range sel.dependencies {
+			requiredBy[d.ID] = append(requiredBy[d.ID], id)
+		}
+	}
+
+	p := &plugins.InstallPlan{PluginID: res.pluginID, Version: st.selected[res.pluginID].version}
+	visited := map[string]bool{}
+	var visit func(id string)
+	visit = func(id string) {
+		sel, selected := st.selected[id]
+		if visited[id] || !selected {
+			return
+		}
+		visited[id] = true
+		for _, d := range sel.dependencies {
+			visit(d.ID)
+		}
+		step := plugins.InstallStep{
+			PluginID:   id,
+			Version:    sel.version,
+			Action:     sel.action,
+			RequiredBy: sortedStrings(requiredBy[id]),
+		}
+		if installed, isInstalled := res.installed[id]; isInstalled {
+			step.InstalledVersion = installed.Version
+		}
+		p.Steps = append(p.Steps, step)
+	}
+	visit(res.pluginID)
+	return p
+}
+
+// isNewer compares versions like sortVersions does.
+func isNewer(v, than string) bool {
+	newer := []Version{{Version: than}, {Version: v}}
+	sortVersions(newer)
+	return newer[0].Version == v && normalizeVersion(v) != normalizeVersion(than)
+}
+
+func isResolveError(err error) bool {
+	var conflictErr ErrDependencyConflict
+	var grafanaErr ErrGrafanaVersionUnsupported
+	return errors.As(err, &conflictErr) || errors.As(err, &grafanaErr)
+}
+
+func satisfiesAll(version string, requirements []Requirement) bool {
+	for _, r := range requirements {
+		if !satisfies(version, r.Version) {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfies checks a version against a semver range. Versions and ranges that are not semver
+// have to match exactly, as dependencies of older plugins are pinned versions.
+func satisfies(version, versionRange string) bool {
+	if versionRange == "" || versionRange == "*" {
+		return true
+	}
+	c, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		return normalizeVersion(version) == normalizeVersion(versionRange)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return normalizeVersion(version) == normalizeVersion(versionRange)
+	}
+	return c.Check(v)
+}
+
+// readDependencies reads the dependencies from the plugin.json of a plugin archive.
+func readDependencies(archive *zip.ReadCloser) (versionDependencies, error) {
+	var pluginJSON *zip.File
+	for _, f := range archive.File {
+		if path.Base(f.Name) != "plugin.json" {
+			continue
+		}
+		// the plugin.json of the dist folder of the plugin, or else the top-most one
+		if pluginJSON == nil || isDist(f.Name) && !isDist(pluginJSON.Name) ||
+			isDist(f.Name) == isDist(pluginJSON.Name) && strings.Count(f.Name, "/") < strings.Count(pluginJSON.Name, "/") {
+			pluginJSON = f
+		}
+	}
+	if pluginJSON == nil {
+		return versionDependencies{}, errors.New("plugin.json not found in the plugin archive")
+	}
+
+	rc, err := pluginJSON.Open()
+	if err != nil {
+		return versionDependencies{}, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return versionDependencies{}, err
+	}
+
+	var jsonData struct {
+		Dependencies plugins.Dependencies `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return versionDependencies{}, err
+	}
+	return versionDependencies{
+		grafanaDependency: jsonData.Dependencies.GrafanaDependency,
+		plugins:           jsonData.Dependencies.Plugins,
+	}, nil
+}
+
+func isDist(name string) bool {
+	return strings.HasSuffix(name, "dist/plugin.json")
+}
+
+func sortedStrings(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	sorted := append([]string{}, s...)
+	sort.Strings(sorted)
+	return sorted
+}
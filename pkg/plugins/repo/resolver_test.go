@@ -0,0 +1,230 @@
+package repo
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+)
+
+func TestResolver(t *testing.T) {
+	compatOpts := NewCompatOpts("10.0.0-pre", "linux", "amd64")
+
+	repository := &fakeRepository{versions: map[string][]Version{
+		"app": {
+			pluginVersion("2.0.0", ">=10.0.0", dep("datasource", ">=1.1.0 <2.0.0"), dep("panel", "")),
+			pluginVersion("1.0.0", ">=9.0.0", dep("datasource", "^1.0.0")),
+		},
+		"datasource": {
+			pluginVersion("2.0.0", ""),
+			pluginVersion("1.2.0", ""),
+			pluginVersion("1.0.0", ""),
+		},
+		"panel": {
+			pluginVersion("1.0.0", ">=9.0.0", dep("app", ">=1.0.0")),
+		},
+		"new-panel": {
+			pluginVersion("1.0.0", ">=11.0.0"),
+		},
+	}}
+
+	resolve := func(t *testing.T, pluginID, version string, installed ...InstalledPlugin) (*plugins.InstallPlan, error) {
+		t.Helper()
+		return NewResolver(repository, &fakeLogger{}).Resolve(context.Background(), pluginID, version, installed, compatOpts, nil)
+	}
+
+	t.Run("Should select the newest versions that satisfy the dependencies", func(t *testing.T) {
+		plan, err := resolve(t, "app", "")
+		require.NoError(t, err)
+		require.Equal(t, &plugins.InstallPlan{PluginID: "app", Version: "2.0.0", Steps: []plugins.InstallStep{
+			{PluginID: "datasource", Version: "1.2.0", Action: plugins.InstallActionInstall, RequiredBy: []string{"app"}},
+			{PluginID: "panel", Version: "1.0.0", Action: plugins.InstallActionInstall, RequiredBy: []string{"app"}},
+			{PluginID: "app", Version: "2.0.0", Action: plugins.InstallActionInstall, RequiredBy: []string{"panel"}},
+		}}, plan)
+	})
+
+	t.Run("Should keep installed plugins that satisfy the dependencies", func(t *testing.T) {
+		plan, err := resolve(t, "app", "1.0.0", InstalledPlugin{ID: "datasource", Version: "1.0.0"})
+		require.NoError(t, err)
+		require.Equal(t, []plugins.InstallStep{
+			{PluginID: "datasource", Version: "1.0.0", Action: plugins.InstallActionKeep, InstalledVersion: "1.0.0", RequiredBy: []string{"app"}},
+			{PluginID: "app", Version: "1.0.0", Action: plugins.InstallActionInstall},
+		}, plan.Steps)
+	})
+
+	t.Run("Should update installed plugins that don't satisfy the dependencies", func(t *testing.T) {
+		plan, err := resolve(t, "app", "", InstalledPlugin{ID: "datasource", Version: "1.0.0"})
+		require.NoError(t, err)
+		require.Equal(t, plugins.InstallStep{
+			PluginID: "datasource", Version: "1.2.0", Action: plugins.InstallActionUpdate, InstalledVersion: "1.0.0", RequiredBy: []string{"app"},
+		}, plan.Steps[0])
+	})
+
+	t.Run("Should select older versions when installed plugins require it", func(t *testing.T) {
+		installed := []InstalledPlugin{
+			{ID: "datasource", Version: "1.0.0"},
+			{ID: "other-app", Version: "1.0.0", Dependencies: []plugins.Dependency{dep("datasource", "~1.0.0")}},
+		}
+		plan, err := resolve(t, "app", "", installed...)
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0", plan.Version)
+		require.Equal(t, plugins.InstallActionKeep, plan.Steps[0].Action)
+
+		_, err = resolve(t, "app", "2.0.0", installed...)
+		var conflictErr ErrDependencyConflict
+		require.ErrorAs(t, err, &conflictErr)
+		require.Equal(t, "datasource", conflictErr.PluginID)
+		require.ElementsMatch(t, []Requirement{
+			{PluginID: "datasource", Version: "~1.0.0", RequiredBy: "other-app"},
+			{PluginID: "datasource", Version: ">=1.1.0 <2.0.0", RequiredBy: "app"},
+		}, conflictErr.Requirements)
+	})
+
+	t.Run("Should not update locked plugins", func(t *testing.T) {
+		_, err := resolve(t, "app", "2.0.0", InstalledPlugin{ID: "datasource", Version: "1.0.0", Locked: true})
+		var conflictErr ErrDependencyConflict
+		require.ErrorAs(t, err, &conflictErr)
+
+		_, err = resolve(t, "datasource", "", InstalledPlugin{ID: "datasource", Version: "1.0.0", Locked: true})
+		require.ErrorIs(t, err, plugins.ErrInstallCorePlugin)
+	})
+
+	t.Run("Should not install versions that don't support the Grafana version", func(t *testing.T) {
+		_, err := resolve(t, "new-panel", "")
+		var grafanaErr ErrGrafanaVersionUnsupported
+		require.ErrorAs(t, err, &grafanaErr)
+		require.Equal(t, ErrGrafanaVersionUnsupported{PluginID: "new-panel", GrafanaVersion: "10.0.0-pre"}, grafanaErr)
+
+		_, err = NewResolver(repository, &fakeLogger{}).Resolve(context.Background(), "app", "2.0.0", nil, NewCompatOpts("9.5.0", "linux", "amd64"), nil)
+		require.ErrorAs(t, err, &grafanaErr)
+		require.Equal(t, "2.0.0", grafanaErr.Version)
+		require.Equal(t, ">=10.0.0", grafanaErr.GrafanaDependency)
+	})
+
+	t.Run("Should return an error if the requested version is installed", func(t *testing.T) {
+		_, err := resolve(t, "datasource", "", InstalledPlugin{ID: "datasource", Version: "2.0.0"})
+		require.ErrorIs(t, err, plugins.DuplicateError{PluginID: "datasource"})
+
+		plan, err := resolve(t, "datasource", "1.0.0", InstalledPlugin{ID: "datasource", Version: "2.0.0"})
+		require.NoError(t, err)
+		require.Equal(t, plugins.InstallActionUpdate, plan.Steps[0].Action)
+	})
+
+	t.Run("Should return an error if the requested version does not exist", func(t *testing.T) {
+		_, err := resolve(t, "app", "3.0.0")
+		var versionNotFoundErr ErrVersionNotFound
+		require.ErrorAs(t, err, &versionNotFoundErr)
+	})
+
+	t.Run("Should read the dependencies from the archive if the repository does not provide them", func(t *testing.T) {
+		archivePath := writeArchive(t, map[string]string{
+			"legacy-app/src/plugin.json":  `{"id": "legacy-app"}`,
+			"legacy-app/dist/plugin.json": `{"id": "legacy-app", "dependencies": {"grafanaDependency": ">=9.0.0", "plugins": [{"id": "datasource", "version": "1.0.0"}]}}`,
+		})
+		legacy := &fakeRepository{
+			versions: map[string][]Version{"legacy-app": {{Version: "1.0.0"}}, "datasource": repository.versions["datasource"]},
+			archives: map[string]string{"legacy-app@1.0.0": archivePath},
+		}
+
+		archives := NewArchiveCache()
+		plan, err := NewResolver(legacy, &fakeLogger{}).Resolve(context.Background(), "legacy-app", "", nil, compatOpts, archives)
+		require.NoError(t, err)
+		require.Equal(t, []plugins.InstallStep{
+			{PluginID: "datasource", Version: "1.0.0", Action: plugins.InstallActionInstall, RequiredBy: []string{"legacy-app"}},
+			{PluginID: "legacy-app", Version: "1.0.0", Action: plugins.InstallActionInstall},
+		}, plan.Steps)
+
+		// the archive is kept to install the plan
+		archive, exists := archives.Take("legacy-app", "1.0.0")
+		require.True(t, exists)
+		require.NoError(t, archive.File.Close())
+		require.NoError(t, archives.Close())
+		require.Equal(t, 1, legacy.downloads)
+	})
+
+	t.Run("Should limit the archives downloaded to read dependencies", func(t *testing.T) {
+		archivePath := writeArchive(t, map[string]string{
+			"legacy-app/plugin.json": `{"id": "legacy-app", "dependencies": {"grafanaDependency": ">=11.0.0"}}`,
+		})
+		legacy := &fakeRepository{versions: map[string][]Version{}, archives: map[string]string{}}
+		for _, v := range []string{"1.7.0", "1.6.0", "1.5.0", "1.4.0", "1.3.0", "1.2.0", "1.1.0"} {
+			legacy.versions["legacy-app"] = append(legacy.versions["legacy-app"], Version{Version: v})
+			legacy.archives["legacy-app@"+v] = archivePath
+		}
+
+		archives := NewArchiveCache()
+		_, err := NewResolver(legacy, &fakeLogger{}).Resolve(context.Background(), "legacy-app", "", nil, compatOpts, archives)
+		var grafanaErr ErrGrafanaVersionUnsupported
+		require.ErrorAs(t, err, &grafanaErr)
+		require.Equal(t, maxArchiveReads, legacy.downloads)
+		require.NoError(t, archives.Close())
+	})
+}
+
+// writeArchive writes a plugin archive with the files and returns its path.
+func writeArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "archive.zip")
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+	return archivePath
+}
+
+func pluginVersion(v, grafanaDependency string, dependencies ...plugins.Dependency) Version {
+	return Version{Version: v, GrafanaDependency: grafanaDependency, Dependencies: append([]plugins.Dependency{}, dependencies...)}
+}
+
+func dep(pluginID, versionRange string) plugins.Dependency {
+	return plugins.Dependency{ID: pluginID, Version: versionRange}
+}
+
+// fakeRepository is a repository with the versions of each plugin, newest first, and the paths of their archives.
+type fakeRepository struct {
+	versions  map[string][]Version
+	archives  map[string]string
+	downloads int
+}
+
+func (r *fakeRepository) GetPluginVersions(_ context.Context, pluginID string, _ CompatOpts) ([]Version, error) {
+	versions, exists := r.versions[pluginID]
+	if !exists {
+		return nil, ErrPluginNotFound{PluginID: pluginID}
+	}
+	return versions, nil
+}
+
+func (r *fakeRepository) GetPluginArchive(_ context.Context, pluginID, version string, _ CompatOpts) (*PluginArchive, error) {
+	archivePath, exists := r.archives[pluginID+"@"+version]
+	if !exists {
+		return nil, ErrVersionNotFound{PluginID: pluginID, RequestedVersion: version}
+	}
+	rc, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	r.downloads++
+	return &PluginArchive{File: rc}, nil
+}
+
+func (r *fakeRepository) GetPluginArchiveByURL(_ context.Context, _ string, _ CompatOpts) (*PluginArchive, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeRepository) GetPluginDownloadOptions(_ context.Context, _, _ string, _ CompatOpts) (*PluginDownloadOptions, error) {
+	return nil, errors.New("not implemented")
+}
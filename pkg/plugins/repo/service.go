@@ -2,10 +2,8 @@ package repo
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"net/url"
-	"path"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/plugins/config"
@@ -13,36 +11,63 @@ import (
 )
 
 type Manager struct {
-	client  *Client
-	baseURL string
+	// client downloads archives from URLs outside of the repositories
+	client       *Client
+	repositories []repository
 
 	log log.PrettyLogger
 }
 
 func ProvideService(cfg *config.Cfg) (*Manager, error) {
-	defaultBaseURL, err := url.JoinPath(cfg.GrafanaComURL, "/api/plugins")
-	if err != nil {
-		return nil, err
+	repositories := cfg.PluginRepositories
+	if len(repositories) == 0 {
+		defaultBaseURL, err := url.JoinPath(cfg.GrafanaComURL, "/api/plugins")
+		if err != nil {
+			return nil, err
+		}
+		repositories = []config.PluginRepository{{Name: "grafana.com", Type: config.PluginRepositoryGrafanaCom, URL: defaultBaseURL}}
 	}
-	return New(false, defaultBaseURL, log.NewPrettyLogger("plugin.repository")), nil
+	return NewManager(false, repositories, log.NewPrettyLogger("plugin.repository"))
 }
 
+// New returns a Manager for a single grafana.com plugin API.
 func New(skipTLSVerify bool, baseURL string, logger log.PrettyLogger) *Manager {
 	return &Manager{
-		client:  newClient(skipTLSVerify, logger),
-		baseURL: baseURL,
-		log:     logger,
+		client: newClient(skipTLSVerify, logger),
+		repositories: []repository{&grafanaComRepository{
+			repoName: baseURL,
+			baseURL:  baseURL,
+			c:        newClient(skipTLSVerify, logger),
+			log:      logger,
+		}},
+		log: logger,
+	}
+}
+
+// NewManager returns a Manager that installs plugins from the first of the repositories that has them.
+func NewManager(skipTLSVerify bool, repositories []config.PluginRepository, logger log.PrettyLogger) (*Manager, error) {
+	m := &Manager{
+		client: newClient(skipTLSVerify, logger),
+		log:    logger,
+	}
+	for _, cfg := range repositories {
+		r, err := newRepository(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		m.repositories = append(m.repositories, r)
 	}
+	return m, nil
 }
 
 // GetPluginArchive fetches the requested plugin archive
 func (m *Manager) GetPluginArchive(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginArchive, error) {
-	dlOpts, err := m.GetPluginDownloadOptions(ctx, pluginID, version, compatOpts)
+	dlOpts, r, err := m.resolve(ctx, pluginID, version, compatOpts)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.client.download(ctx, dlOpts.PluginZipURL, dlOpts.Checksum, compatOpts)
+	return r.client().download(ctx, dlOpts.PluginZipURL, dlOpts.Checksum, compatOpts)
 }
 
 // GetPluginArchiveByURL fetches the requested plugin archive from the provided `pluginZipURL`
@@ -51,56 +76,81 @@ func (m *Manager) GetPluginArchiveByURL(ctx context.Context, pluginZipURL string
 }
 
 // GetPluginDownloadOptions returns the options for downloading the requested plugin (with optional `version`)
-func (m *Manager) GetPluginDownloadOptions(_ context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
-	plugin, err := m.pluginMetadata(pluginID, compatOpts)
-	if err != nil {
-		return nil, err
-	}
+func (m *Manager) GetPluginDownloadOptions(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginDownloadOptions, error) {
+	dlOpts, _, err := m.resolve(ctx, pluginID, version, compatOpts)
+	return dlOpts, err
+}
 
-	v, err := m.selectVersion(&plugin, version, compatOpts)
-	if err != nil {
-		return nil, err
-	}
+// GetPluginVersions returns the versions of a plugin supported by the system, newest first. A version that
+// is in several repositories is the one of the first repository that has it, as for GetPluginArchive.
+func (m *Manager) GetPluginVersions(ctx context.Context, pluginID string, compatOpts CompatOpts) ([]Version, error) {
+	var versions []Version
+	found := false
+	seen := map[string]bool{}
+	for _, r := range m.repositories {
+		plugin, err := r.plugin(ctx, pluginID, compatOpts)
+		var notFoundErr ErrPluginNotFound
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	// Plugins which are downloaded just as sourcecode zipball from GitHub do not have checksum
-	var checksum string
-	if v.Arch != nil {
-		archMeta, exists := v.Arch[compatOpts.OSAndArch()]
-		if !exists {
-			archMeta = v.Arch["any"]
+		found = true
+		for _, v := range plugin.Versions {
+			ver := v
+			if seen[normalizeVersion(ver.Version)] || !supportsCurrentArch(&ver, compatOpts) {
+				continue
+			}
+			seen[normalizeVersion(ver.Version)] = true
+			versions = append(versions, ver)
 		}
-		checksum = archMeta.SHA256
 	}
 
-	return &PluginDownloadOptions{
-		Version:      v.Version,
-		Checksum:     checksum,
-		PluginZipURL: fmt.Sprintf("%s/%s/versions/%s/download", m.baseURL, pluginID, v.Version),
-	}, nil
+	if !found {
+		return nil, ErrPluginNotFound{PluginID: pluginID}
+	}
+	if len(versions) == 0 {
+		return nil, ErrArcNotFound{PluginID: pluginID, SystemInfo: compatOpts.OSAndArch()}
+	}
+	sortVersions(versions)
+	return versions, nil
 }
 
-func (m *Manager) pluginMetadata(pluginID string, compatOpts CompatOpts) (Plugin, error) {
-	m.log.Debugf("Fetching metadata for plugin \"%s\" from repo %s", pluginID, m.baseURL)
+// resolve finds the requested plugin version in the first repository that has it.
+func (m *Manager) resolve(ctx context.Context, pluginID, version string, compatOpts CompatOpts) (*PluginDownloadOptions, repository, error) {
+	var resolveErr error = ErrPluginNotFound{PluginID: pluginID}
+	for _, r := range m.repositories {
+		m.log.Debugf("Fetching metadata for plugin \"%s\" from repo %s", pluginID, r.name())
 
-	u, err := url.Parse(m.baseURL)
-	if err != nil {
-		return Plugin{}, err
-	}
-	u.Path = path.Join(u.Path, "repo", pluginID)
+		plugin, err := r.plugin(ctx, pluginID, compatOpts)
+		var notFoundErr ErrPluginNotFound
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
 
-	body, err := m.client.sendReq(u, compatOpts)
-	if err != nil {
-		return Plugin{}, err
-	}
+		v, err := m.selectVersion(plugin, version, compatOpts)
+		var versionNotFoundErr ErrVersionNotFound
+		if errors.As(err, &versionNotFoundErr) {
+			// the version may be in one of the other repositories
+			resolveErr = err
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
 
-	var data Plugin
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		m.log.Error("Failed to unmarshal plugin repo response error", err)
-		return Plugin{}, err
+		dlOpts, err := r.downloadOptions(ctx, pluginID, v, compatOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dlOpts, r, nil
 	}
-
-	return data, nil
+	return nil, nil, resolveErr
 }
 
 // selectVersion selects the most appropriate plugin version
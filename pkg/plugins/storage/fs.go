@@ -16,7 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/log"
 )
 
-var _ ZipExtractor = (*FS)(nil)
+var _ StagingZipExtractor = (*FS)(nil)
 
 var reGitBuild = regexp.MustCompile("^[a-zA-Z0-9_.-]*/")
 
@@ -62,6 +62,48 @@ func (fs *FS) Extract(ctx context.Context, pluginID string, pluginArchive *zip.R
 	}, nil
 }
 
+// Stage extracts the archive to a directory of its own in the plugins directory, so that Commit only
+// has to rename it.
+func (fs *FS) Stage(ctx context.Context, pluginID string, pluginArchive *zip.ReadCloser) (*ExtractedPluginArchive, error) {
+	if err := os.MkdirAll(fs.pluginsDir, 0750); err != nil {
+		return nil, err
+	}
+	stagingDir, err := os.MkdirTemp(fs.pluginsDir, ".staging-"+pluginID+"-")
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", "failed to create staging directory", err)
+	}
+
+	staged, err := FileSystem(fs.log, stagingDir).Extract(ctx, pluginID, pluginArchive)
+	if err != nil {
+		if err := os.RemoveAll(stagingDir); err != nil {
+			fs.log.Warn("Failed to remove staging directory", "dir", stagingDir, "err", err)
+		}
+		return nil, err
+	}
+	return staged, nil
+}
+
+func (fs *FS) Commit(_ context.Context, staged *ExtractedPluginArchive) (*ExtractedPluginArchive, error) {
+	installDir := filepath.Join(fs.pluginsDir, filepath.Base(staged.Path))
+	if err := os.RemoveAll(installDir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staged.Path, installDir); err != nil {
+		return nil, fmt.Errorf("%v: %w", "failed to install staged plugin", err)
+	}
+	if err := os.RemoveAll(filepath.Dir(staged.Path)); err != nil {
+		fs.log.Warn("Failed to remove staging directory", "dir", filepath.Dir(staged.Path), "err", err)
+	}
+
+	installed := *staged
+	installed.Path = installDir
+	return &installed, nil
+}
+
+func (fs *FS) Discard(_ context.Context, staged *ExtractedPluginArchive) error {
+	return os.RemoveAll(filepath.Dir(staged.Path))
+}
+
 func (fs *FS) extractFiles(_ context.Context, pluginArchive *zip.ReadCloser, pluginID string) (string, error) {
 	installDir := filepath.Join(fs.pluginsDir, pluginID)
 	if _, err := os.Stat(installDir); !os.IsNotExist(err) {
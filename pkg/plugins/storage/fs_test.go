@@ -48,6 +48,43 @@ func TestAdd(t *testing.T) {
 	require.Equal(t, files[5].Name(), "text.txt")
 }
 
+func TestStage(t *testing.T) {
+	pluginsDir := t.TempDir()
+	pluginID := "test-app"
+	fs := FileSystem(&fakeLogger{}, pluginsDir)
+
+	t.Run("Should only replace the installed plugin on commit", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginsDir, pluginID), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginsDir, pluginID, "old.txt"), []byte("old"), 0600))
+
+		staged, err := fs.Stage(context.Background(), pluginID, zipFile(t, "./testdata/plugin-with-symlinks.zip"))
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(pluginsDir, pluginID, "old.txt"))
+		require.FileExists(t, filepath.Join(staged.Path, "plugin.json"))
+
+		installed, err := fs.Commit(context.Background(), staged)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(pluginsDir, pluginID), installed.Path)
+		require.FileExists(t, filepath.Join(installed.Path, "plugin.json"))
+		require.NoFileExists(t, filepath.Join(installed.Path, "old.txt"))
+
+		entries, err := os.ReadDir(pluginsDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("Should remove a discarded plugin", func(t *testing.T) {
+		staged, err := fs.Stage(context.Background(), "other-app", zipFile(t, "./testdata/plugin-with-symlinks.zip"))
+		require.NoError(t, err)
+		require.NoError(t, fs.Discard(context.Background(), staged))
+
+		entries, err := os.ReadDir(pluginsDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, pluginID, entries[0].Name())
+	})
+}
+
 func TestExtractFiles(t *testing.T) {
 	pluginsDir := setupFakePluginsDir(t)
 
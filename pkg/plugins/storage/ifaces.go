@@ -8,3 +8,15 @@ import (
 type ZipExtractor interface {
 	Extract(ctx context.Context, pluginID string, rc *zip.ReadCloser) (*ExtractedPluginArchive, error)
 }
+
+// StagingZipExtractor extracts plugin archives to a staging directory before installing them, so that
+// the installed plugins are only replaced once all the archives of an installation are extracted.
+type StagingZipExtractor interface {
+	ZipExtractor
+	// Stage extracts the archive of a plugin to a staging directory.
+	Stage(ctx context.Context, pluginID string, rc *zip.ReadCloser) (*ExtractedPluginArchive, error)
+	// Commit moves a staged plugin to the plugins directory, replacing the installed version.
+	Commit(ctx context.Context, staged *ExtractedPluginArchive) (*ExtractedPluginArchive, error)
+	// Discard removes a staged plugin that is not installed.
+	Discard(ctx context.Context, staged *ExtractedPluginArchive) error
+}
@@ -55,6 +55,9 @@ var wireExtsBasicSet = wire.NewSet(
 	wire.Bind(new(accesscontrol.RoleRegistry), new(*acimpl.Service)),
 	wire.Bind(new(plugins.RoleRegistry), new(*acimpl.Service)),
 	wire.Bind(new(accesscontrol.Service), new(*acimpl.Service)),
+	wire.Bind(new(accesscontrol.RoleAssignmentService), new(*acimpl.Service)),
+	acimpl.ProvideSimulator,
+	wire.Bind(new(accesscontrol.PolicySimulator), new(*acimpl.Simulator)),
 	validations.ProvideValidator,
 	wire.Bind(new(validations.PluginRequestValidator), new(*validations.OSSPluginRequestValidator)),
 	provisioning.ProvideService,
@@ -42,6 +42,29 @@ type Service interface {
 	IsDisabled() bool
 }
 
+// RoleAssignmentService manages time-bound assignments of fixed roles to users, teams and basic roles.
+type RoleAssignmentService interface {
+	// GetAssignableRole returns the declared fixed role with the given name.
+	GetAssignableRole(name string) (*RoleDTO, error)
+	// AssignRole grants a role until the assignment expires.
+	AssignRole(ctx context.Context, cmd *AssignRoleCommand) (*RoleAssignment, error)
+	// GetRoleAssignments returns the role assignments of an organization.
+	GetRoleAssignments(ctx context.Context, orgID int64) ([]RoleAssignment, error)
+	// RemoveRoleAssignment revokes a role assignment before it expires.
+	RemoveRoleAssignment(ctx context.Context, orgID, assignmentID, actorID int64) error
+	// GetRoleAssignmentEvents returns the audit log of role assignments of an organization.
+	GetRoleAssignmentEvents(ctx context.Context, orgID int64, limit int) ([]RoleAssignmentEvent, error)
+	// DeleteExpiredRoleAssignments removes all lapsed role assignments.
+	DeleteExpiredRoleAssignments(ctx context.Context) (int64, error)
+}
+
+// PolicySimulator explains access control decisions.
+type PolicySimulator interface {
+	// Simulate evaluates whether the user can perform the action on the scope and reports the permissions,
+	// roles and teams the decision is based on, along with the scope resolution chain.
+	Simulate(ctx context.Context, user *user.SignedInUser, action, scope string) (*Simulation, error)
+}
+
 type RoleRegistry interface {
 	// RegisterFixedRoles registers all roles declared to AccessControl
 	RegisterFixedRoles(ctx context.Context) error
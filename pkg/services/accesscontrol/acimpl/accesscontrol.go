@@ -33,6 +33,25 @@ func (a *AccessControl) Evaluate(ctx context.Context, user *user.SignedInUser, e
 	defer timer.ObserveDuration()
 	metrics.MAccessEvaluationCount.Inc()
 
+	return a.evaluate(ctx, user, evaluator, a.resolvers.GetScopeAttributeMutator(user.OrgID))
+}
+
+// Explain evaluates access the same way Evaluate does and also returns the scope resolution
+// steps that were performed to reach the decision.
+func (a *AccessControl) Explain(ctx context.Context, user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, []accesscontrol.ScopeResolution, error) {
+	resolutions := make([]accesscontrol.ScopeResolution, 0)
+	mutator := a.resolvers.GetScopeAttributeMutator(user.OrgID)
+	hasAccess, err := a.evaluate(ctx, user, evaluator, func(ctx context.Context, scope string) ([]string, error) {
+		resolved, err := mutator(ctx, scope)
+		if err == nil {
+			resolutions = append(resolutions, accesscontrol.ScopeResolution{Scope: scope, Resolved: resolved})
+		}
+		return resolved, err
+	})
+	return hasAccess, resolutions, err
+}
+
+func (a *AccessControl) evaluate(ctx context.Context, user *user.SignedInUser, evaluator accesscontrol.Evaluator, mutator accesscontrol.ScopeAttributeMutator) (bool, error) {
 	if !verifyPermissions(user) {
 		a.log.Warn("no permissions set for user", "userID", user.UserID, "orgID", user.OrgID, "login", user.Login)
 		return false, nil
@@ -42,7 +61,7 @@ func (a *AccessControl) Evaluate(ctx context.Context, user *user.SignedInUser, e
 		return true, nil
 	}
 
-	resolvedEvaluator, err := evaluator.MutateScopes(ctx, a.resolvers.GetScopeAttributeMutator(user.OrgID))
+	resolvedEvaluator, err := evaluator.MutateScopes(ctx, mutator)
 	if err != nil {
 		if errors.Is(err, accesscontrol.ErrResolverNotFound) {
 			return false, nil
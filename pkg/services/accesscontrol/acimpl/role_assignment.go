@@ -0,0 +1,182 @@
+package acimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+var _ accesscontrol.RoleAssignmentService = new(Service)
+
+// GetAssignableRole returns the declared fixed or plugin role with the given name.
+func (s *Service) GetAssignableRole(name string) (*accesscontrol.RoleDTO, error) {
+	var role *accesscontrol.RoleDTO
+	s.registrations.Range(func(registration accesscontrol.RoleRegistration) bool {
+		if registration.Role.Name == name {
+			r := registration.Role
+			role = &r
+			return false
+		}
+		return true
+	})
+
+	if role == nil {
+		return nil, accesscontrol.ErrRoleAssignmentRoleNotFound.Errorf("role %s is not a declared fixed role", name)
+	}
+	return role, nil
+}
+
+// AssignRole grants a role until the assignment expires.
+func (s *Service) AssignRole(ctx context.Context, cmd *accesscontrol.AssignRoleCommand) (*accesscontrol.RoleAssignment, error) {
+	if _, err := s.GetAssignableRole(cmd.RoleName); err != nil {
+		return nil, err
+	}
+
+	targets := 0
+	if cmd.UserID > 0 {
+		targets++
+	}
+	if cmd.TeamID > 0 {
+		targets++
+	}
+	if cmd.BuiltInRole != "" {
+		if err := accesscontrol.ValidateBuiltInRoles([]string{cmd.BuiltInRole}); err != nil {
+			return nil, accesscontrol.ErrRoleAssignmentInvalid.Errorf("invalid basic role %s: %w", cmd.BuiltInRole, err)
+		}
+		targets++
+	}
+	if targets != 1 {
+		return nil, accesscontrol.ErrRoleAssignmentInvalid.Errorf("exactly one of userId, teamId or builtInRole must be set")
+	}
+
+	now := time.Now()
+	if !cmd.Expires.After(now) {
+		return nil, accesscontrol.ErrRoleAssignmentInvalid.Errorf("expiry %s is not in the future", cmd.Expires)
+	}
+	if max := s.cfg.RBACRoleAssignmentMaxDuration; max > 0 && cmd.Expires.After(now.Add(max)) {
+		return nil, accesscontrol.ErrRoleAssignmentInvalid.Errorf("expiry %s exceeds the maximum duration of %s", cmd.Expires, max)
+	}
+
+	assignment := &accesscontrol.RoleAssignment{
+		OrgID:         cmd.OrgID,
+		RoleName:      cmd.RoleName,
+		UserID:        cmd.UserID,
+		TeamID:        cmd.TeamID,
+		BuiltInRole:   cmd.BuiltInRole,
+		Justification: cmd.Justification,
+		CreatedBy:     cmd.ActorID,
+		Created:       now,
+		Expires:       cmd.Expires,
+	}
+	if err := s.store.AddRoleAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Role assigned", "orgId", assignment.OrgID, "role", assignment.RoleName, "userId", assignment.UserID,
+		"teamId", assignment.TeamID, "builtInRole", assignment.BuiltInRole, "expires", assignment.Expires,
+		"actorId", cmd.ActorID, "justification", assignment.Justification)
+	return assignment, nil
+}
+
+// GetRoleAssignments returns all role assignments of the organization that have not been removed yet.
+func (s *Service) GetRoleAssignments(ctx context.Context, orgID int64) ([]accesscontrol.RoleAssignment, error) {
+	return s.store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{OrgID: orgID})
+}
+
+// RemoveRoleAssignment revokes an assignment before it expires.
+func (s *Service) RemoveRoleAssignment(ctx context.Context, orgID, assignmentID, actorID int64) error {
+	if err := s.store.DeleteRoleAssignment(ctx, orgID, assignmentID, actorID); err != nil {
+		return err
+	}
+	s.log.Info("Role assignment revoked", "orgId", orgID, "assignmentId", assignmentID, "actorId", actorID)
+	return nil
+}
+
+// GetRoleAssignmentEvents returns the audit log of role assignments of the organization.
+func (s *Service) GetRoleAssignmentEvents(ctx context.Context, orgID int64, limit int) ([]accesscontrol.RoleAssignmentEvent, error) {
+	return s.store.GetRoleAssignmentEvents(ctx, orgID, limit)
+}
+
+// DeleteExpiredRoleAssignments removes all lapsed role assignments.
+func (s *Service) DeleteExpiredRoleAssignments(ctx context.Context) (int64, error) {
+	removed, err := s.store.DeleteExpiredRoleAssignments(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, a := range removed {
+		s.log.Info("Role assignment expired", "orgId", a.OrgID, "assignmentId", a.ID, "role", a.RoleName,
+			"userId", a.UserID, "teamId", a.TeamID, "builtInRole", a.BuiltInRole, "expires", a.Expires)
+	}
+	return int64(len(removed)), nil
+}
+
+// getAssignedPermissions returns the permissions of the roles temporarily assigned to the user,
+// its teams or its basic roles.
+func (s *Service) getAssignedPermissions(ctx context.Context, usr *user.SignedInUser) ([]accesscontrol.Permission, error) {
+	sources, err := s.getAssignedPermissionSources(ctx, usr)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]accesscontrol.Permission, 0, len(sources))
+	for _, source := range sources {
+		permissions = append(permissions, accesscontrol.Permission{Action: source.Action, Scope: source.Scope})
+	}
+	return permissions, nil
+}
+
+func (s *Service) getAssignedPermissionSources(ctx context.Context, usr *user.SignedInUser) ([]accesscontrol.PermissionSource, error) {
+	roles := map[string]struct{}{}
+	for _, role := range accesscontrol.GetOrgRoles(usr) {
+		roles[role] = struct{}{}
+		if role == accesscontrol.RoleGrafanaAdmin {
+			continue
+		}
+		// assignments to a basic role apply to the roles including it
+		for _, child := range org.RoleType(role).Children() {
+			roles[string(child)] = struct{}{}
+		}
+	}
+	basicRoles := make([]string, 0, len(roles))
+	for role := range roles {
+		basicRoles = append(basicRoles, role)
+	}
+
+	assignments, err := s.store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{
+		OrgID:    usr.OrgID,
+		UserID:   usr.UserID,
+		TeamIDs:  usr.Teams,
+		Roles:    basicRoles,
+		ActiveAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]accesscontrol.PermissionSource, 0)
+	for i := range assignments {
+		a := assignments[i]
+		role, err := s.GetAssignableRole(a.RoleName)
+		if err != nil {
+			s.log.Warn("Ignoring assignment of unknown role", "assignmentId", a.ID, "role", a.RoleName)
+			continue
+		}
+		for _, p := range role.Permissions {
+			sources = append(sources, accesscontrol.PermissionSource{
+				Action:       p.Action,
+				Scope:        p.Scope,
+				Source:       accesscontrol.PermissionSourceAssignment,
+				RoleName:     a.RoleName,
+				TeamID:       a.TeamID,
+				BuiltInRole:  a.BuiltInRole,
+				AssignmentID: a.ID,
+				Expires:      &assignments[i].Expires,
+			})
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,123 @@
+package acimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestService_AssignRole(t *testing.T) {
+	ac := setupTestEnv(t)
+	ac.cfg.RBACRoleAssignmentMaxDuration = 24 * time.Hour
+	require.NoError(t, ac.DeclareFixedRoles(accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        "fixed:test:writer",
+			Permissions: []accesscontrol.Permission{{Action: "test:write", Scope: "test:*"}},
+		},
+	}))
+
+	tests := []struct {
+		desc string
+		cmd  accesscontrol.AssignRoleCommand
+		err  error
+	}{
+		{
+			desc: "should assign a declared role to a user",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", UserID: 1, Expires: time.Now().Add(time.Hour)},
+		},
+		{
+			desc: "should assign a declared role to a basic role",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", BuiltInRole: "Editor", Expires: time.Now().Add(time.Hour)},
+		},
+		{
+			desc: "should fail for unknown roles",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:unknown:writer", UserID: 1, Expires: time.Now().Add(time.Hour)},
+			err:  accesscontrol.ErrRoleAssignmentRoleNotFound,
+		},
+		{
+			desc: "should fail without target",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", Expires: time.Now().Add(time.Hour)},
+			err:  accesscontrol.ErrRoleAssignmentInvalid,
+		},
+		{
+			desc: "should fail with several targets",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", UserID: 1, TeamID: 1, Expires: time.Now().Add(time.Hour)},
+			err:  accesscontrol.ErrRoleAssignmentInvalid,
+		},
+		{
+			desc: "should fail for invalid basic roles",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", BuiltInRole: "Owner", Expires: time.Now().Add(time.Hour)},
+			err:  accesscontrol.ErrRoleAssignmentInvalid,
+		},
+		{
+			desc: "should fail when expiry is in the past",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", UserID: 1, Expires: time.Now().Add(-time.Hour)},
+			err:  accesscontrol.ErrRoleAssignmentInvalid,
+		},
+		{
+			desc: "should fail when expiry exceeds the maximum duration",
+			cmd:  accesscontrol.AssignRoleCommand{OrgID: 1, RoleName: "fixed:test:writer", UserID: 1, Expires: time.Now().Add(48 * time.Hour)},
+			err:  accesscontrol.ErrRoleAssignmentInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assignment, err := ac.AssignRole(context.Background(), &tt.cmd)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotZero(t, assignment.ID)
+		})
+	}
+}
+
+func TestService_GetUserPermissionsWithRoleAssignments(t *testing.T) {
+	ctx := context.Background()
+	ac := setupTestEnv(t)
+	require.NoError(t, ac.DeclareFixedRoles(accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        "fixed:test:writer",
+			Permissions: []accesscontrol.Permission{{Action: "test:write", Scope: "test:*"}},
+		},
+	}))
+
+	assignment, err := ac.AssignRole(ctx, &accesscontrol.AssignRoleCommand{
+		OrgID: 1, RoleName: "fixed:test:writer", BuiltInRole: string(org.RoleViewer), Expires: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	usr := &user.SignedInUser{OrgID: 1, UserID: 2, OrgRole: org.RoleEditor}
+	hasPermission := func() bool {
+		permissions, err := ac.GetUserPermissions(ctx, usr, accesscontrol.Options{ReloadCache: true})
+		require.NoError(t, err)
+		for _, p := range permissions {
+			if p.Action == "test:write" && p.Scope == "test:*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// assignments to Viewer also apply to Editors
+	assert.True(t, hasPermission())
+
+	// simulate the assignment lapsing
+	_, err = ac.store.DeleteExpiredRoleAssignments(ctx, assignment.Expires)
+	require.NoError(t, err)
+	assert.False(t, hasPermission())
+
+	events, err := ac.GetRoleAssignmentEvents(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, accesscontrol.RoleAssignmentEventExpired, events[0].Event)
+}
@@ -36,6 +36,7 @@ func ProvideService(cfg *setting.Cfg, store db.DB, routeRegister routing.RouteRe
 
 	if !accesscontrol.IsDisabled(cfg) {
 		api.NewAccessControlAPI(routeRegister, accessControl, service, features).RegisterAPIEndpoints()
+		api.NewRoleAssignmentAPI(routeRegister, accessControl, service).RegisterAPIEndpoints()
 		if err := accesscontrol.DeclareFixedRoles(service, cfg); err != nil {
 			return nil, err
 		}
@@ -59,9 +60,15 @@ func ProvideOSSService(cfg *setting.Cfg, store store, cache *localcache.CacheSer
 
 type store interface {
 	GetUserPermissions(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) ([]accesscontrol.Permission, error)
+	GetUserPermissionSources(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) ([]accesscontrol.PermissionSource, error)
 	SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error)
 	GetUsersBasicRoles(ctx context.Context, userFilter []int64, orgID int64) (map[int64][]string, error)
 	DeleteUserPermissions(ctx context.Context, orgID, userID int64) error
+	AddRoleAssignment(ctx context.Context, assignment *accesscontrol.RoleAssignment) error
+	GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error)
+	DeleteRoleAssignment(ctx context.Context, orgID, assignmentID, actorID int64) error
+	DeleteExpiredRoleAssignments(ctx context.Context, now time.Time) ([]accesscontrol.RoleAssignment, error)
+	GetRoleAssignmentEvents(ctx context.Context, orgID int64, limit int) ([]accesscontrol.RoleAssignmentEvent, error)
 }
 
 // Service is the service implementing role based access control.
@@ -116,8 +123,56 @@ func (s *Service) getUserPermissions(ctx context.Context, user *user.SignedInUse
 	if err != nil {
 		return nil, err
 	}
+	permissions = append(permissions, dbPermissions...)
 
-	return append(permissions, dbPermissions...), nil
+	assignedPermissions, err := s.getAssignedPermissions(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(permissions, assignedPermissions...), nil
+}
+
+// GetUserPermissionSources returns the permissions of the user along with the roles, teams and basic roles
+// they are granted through. It resolves the same sources as GetUserPermissions but bypasses the cache.
+func (s *Service) GetUserPermissionSources(ctx context.Context, user *user.SignedInUser) ([]accesscontrol.PermissionSource, error) {
+	sources := make([]accesscontrol.PermissionSource, 0)
+	for _, builtin := range accesscontrol.GetOrgRoles(user) {
+		s.registrations.Range(func(registration accesscontrol.RoleRegistration) bool {
+			if _, ok := accesscontrol.BuiltInRolesWithParents(registration.Grants)[builtin]; !ok {
+				return true
+			}
+			for _, p := range registration.Role.Permissions {
+				sources = append(sources, accesscontrol.PermissionSource{
+					Action:      p.Action,
+					Scope:       p.Scope,
+					Source:      accesscontrol.PermissionSourceBasicRole,
+					RoleName:    registration.Role.Name,
+					BuiltInRole: builtin,
+				})
+			}
+			return true
+		})
+	}
+
+	dbSources, err := s.store.GetUserPermissionSources(ctx, accesscontrol.GetUserPermissionsQuery{
+		OrgID:      user.OrgID,
+		UserID:     user.UserID,
+		Roles:      accesscontrol.GetOrgRoles(user),
+		TeamIDs:    user.Teams,
+		RolePrefix: accesscontrol.ManagedRolePrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sources = append(sources, dbSources...)
+
+	assignedSources, err := s.getAssignedPermissionSources(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(sources, assignedSources...), nil
 }
 
 func (s *Service) getCachedUserPermissions(ctx context.Context, user *user.SignedInUser, options accesscontrol.Options) ([]accesscontrol.Permission, error) {
@@ -0,0 +1,84 @@
+package acimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+var _ accesscontrol.PolicySimulator = new(Simulator)
+
+func ProvideSimulator(accessControl *AccessControl, service *Service) *Simulator {
+	return &Simulator{accessControl: accessControl, service: service}
+}
+
+// Simulator answers "why can (or can't) a user do this" using the same evaluation as enforcement.
+type Simulator struct {
+	accessControl *AccessControl
+	service       *Service
+}
+
+func (s *Simulator) Simulate(ctx context.Context, usr *user.SignedInUser, action, scope string) (*accesscontrol.Simulation, error) {
+	permissions, err := s.service.GetUserPermissions(ctx, usr, accesscontrol.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	target := *usr
+	target.Permissions = map[int64]map[string][]string{usr.OrgID: accesscontrol.GroupScopesByAction(permissions)}
+
+	evaluator := accesscontrol.EvalPermission(action)
+	if scope != "" {
+		evaluator = accesscontrol.EvalPermission(action, scope)
+	}
+
+	allowed, resolutions, err := s.accessControl.Explain(ctx, &target, evaluator)
+	if err != nil {
+		return nil, err
+	}
+
+	// a permission contributes when it matches the requested scope or any scope it resolved to
+	matching := evaluator
+	if scope != "" {
+		scopes := []string{scope}
+		for _, r := range resolutions {
+			scopes = append(scopes, r.Resolved...)
+		}
+		matching = accesscontrol.EvalPermission(action, scopes...)
+	}
+
+	sources, err := s.service.GetUserPermissionSources(ctx, &target)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &accesscontrol.Simulation{
+		Allowed:         allowed,
+		Action:          action,
+		Scope:           scope,
+		ScopeResolution: resolutions,
+		Permissions:     make([]accesscontrol.PermissionSource, 0),
+		Roles:           make([]string, 0),
+		Teams:           make([]int64, 0),
+	}
+
+	roles := map[string]bool{}
+	teams := map[int64]bool{}
+	for _, source := range sources {
+		if !matching.Evaluate(map[string][]string{source.Action: {source.Scope}}) {
+			continue
+		}
+		simulation.Permissions = append(simulation.Permissions, source)
+		if !roles[source.RoleName] {
+			roles[source.RoleName] = true
+			simulation.Roles = append(simulation.Roles, source.RoleName)
+		}
+		if source.TeamID != 0 && !teams[source.TeamID] {
+			teams[source.TeamID] = true
+			simulation.Teams = append(simulation.Teams, source.TeamID)
+		}
+	}
+
+	return simulation, nil
+}
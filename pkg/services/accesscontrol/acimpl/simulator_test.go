@@ -0,0 +1,85 @@
+package acimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestSimulator_Simulate(t *testing.T) {
+	ctx := context.Background()
+	service := setupTestEnv(t)
+	require.NoError(t, service.DeclareFixedRoles(
+		accesscontrol.RoleRegistration{
+			Role: accesscontrol.RoleDTO{
+				Name:        "fixed:test:reader",
+				Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "folders:uid:parent"}},
+			},
+			Grants: []string{string(org.RoleViewer)},
+		},
+		accesscontrol.RoleRegistration{
+			Role: accesscontrol.RoleDTO{
+				Name:        "fixed:test:writer",
+				Permissions: []accesscontrol.Permission{{Action: "dashboards:write", Scope: "folders:uid:parent"}},
+			},
+		},
+	))
+	require.NoError(t, service.RegisterFixedRoles(ctx))
+	_, err := service.AssignRole(ctx, &accesscontrol.AssignRoleCommand{
+		OrgID: 1, RoleName: "fixed:test:writer", TeamID: 5, Expires: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	accessControl := ProvideAccessControl(service.cfg)
+	accessControl.RegisterScopeAttributeResolver("dashboards:uid:", accesscontrol.ScopeAttributeResolverFunc(
+		func(ctx context.Context, orgID int64, scope string) ([]string, error) {
+			return []string{scope, "folders:uid:parent"}, nil
+		}),
+	)
+	simulator := ProvideSimulator(accessControl, service)
+	usr := &user.SignedInUser{OrgID: 1, UserID: 2, OrgRole: org.RoleEditor, Teams: []int64{5}}
+
+	t.Run("should explain access inherited from the parent folder", func(t *testing.T) {
+		simulation, err := simulator.Simulate(ctx, usr, "dashboards:read", "dashboards:uid:abc")
+		require.NoError(t, err)
+
+		assert.True(t, simulation.Allowed)
+		assert.Equal(t, []accesscontrol.ScopeResolution{
+			{Scope: "dashboards:uid:abc", Resolved: []string{"dashboards:uid:abc", "folders:uid:parent"}},
+		}, simulation.ScopeResolution)
+		require.Len(t, simulation.Permissions, 1)
+		assert.Equal(t, accesscontrol.PermissionSourceBasicRole, simulation.Permissions[0].Source)
+		assert.Equal(t, string(org.RoleEditor), simulation.Permissions[0].BuiltInRole)
+		assert.Equal(t, []string{"fixed:test:reader"}, simulation.Roles)
+		assert.Empty(t, simulation.Teams)
+	})
+
+	t.Run("should explain access granted by a team role assignment", func(t *testing.T) {
+		simulation, err := simulator.Simulate(ctx, usr, "dashboards:write", "dashboards:uid:abc")
+		require.NoError(t, err)
+
+		assert.True(t, simulation.Allowed)
+		require.Len(t, simulation.Permissions, 1)
+		assert.Equal(t, accesscontrol.PermissionSourceAssignment, simulation.Permissions[0].Source)
+		assert.NotNil(t, simulation.Permissions[0].Expires)
+		assert.Equal(t, []string{"fixed:test:writer"}, simulation.Roles)
+		assert.Equal(t, []int64{5}, simulation.Teams)
+	})
+
+	t.Run("should deny without matching permissions", func(t *testing.T) {
+		simulation, err := simulator.Simulate(ctx, usr, "dashboards:delete", "dashboards:uid:abc")
+		require.NoError(t, err)
+
+		assert.False(t, simulation.Allowed)
+		assert.Len(t, simulation.ScopeResolution, 1)
+		assert.Empty(t, simulation.Permissions)
+		assert.Empty(t, simulation.Roles)
+	})
+}
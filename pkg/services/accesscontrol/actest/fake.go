@@ -2,6 +2,7 @@ package actest
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -73,10 +74,12 @@ func (f FakeAccessControl) IsDisabled() bool {
 }
 
 type FakeStore struct {
-	ExpectedUserPermissions  []accesscontrol.Permission
-	ExpectedUsersPermissions map[int64][]accesscontrol.Permission
-	ExpectedUsersRoles       map[int64][]string
-	ExpectedErr              error
+	ExpectedUserPermissions   []accesscontrol.Permission
+	ExpectedUsersPermissions  map[int64][]accesscontrol.Permission
+	ExpectedUsersRoles        map[int64][]string
+	ExpectedRoleAssignments   []accesscontrol.RoleAssignment
+	ExpectedPermissionSources []accesscontrol.PermissionSource
+	ExpectedErr               error
 }
 
 func (f FakeStore) GetUserPermissions(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) ([]accesscontrol.Permission, error) {
@@ -95,6 +98,30 @@ func (f FakeStore) DeleteUserPermissions(ctx context.Context, orgID, userID int6
 	return f.ExpectedErr
 }
 
+func (f FakeStore) GetUserPermissionSources(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) ([]accesscontrol.PermissionSource, error) {
+	return f.ExpectedPermissionSources, f.ExpectedErr
+}
+
+func (f FakeStore) AddRoleAssignment(ctx context.Context, assignment *accesscontrol.RoleAssignment) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	return f.ExpectedRoleAssignments, f.ExpectedErr
+}
+
+func (f FakeStore) DeleteRoleAssignment(ctx context.Context, orgID, assignmentID, actorID int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) DeleteExpiredRoleAssignments(ctx context.Context, now time.Time) ([]accesscontrol.RoleAssignment, error) {
+	return f.ExpectedRoleAssignments, f.ExpectedErr
+}
+
+func (f FakeStore) GetRoleAssignmentEvents(ctx context.Context, orgID int64, limit int) ([]accesscontrol.RoleAssignmentEvent, error) {
+	return nil, f.ExpectedErr
+}
+
 var _ accesscontrol.PermissionsService = new(FakePermissionsService)
 
 type FakePermissionsService struct {
@@ -127,3 +154,49 @@ func (f *FakePermissionsService) SetPermissions(ctx context.Context, orgID int64
 func (f *FakePermissionsService) MapActions(permission accesscontrol.ResourcePermission) string {
 	return f.ExpectedMappedAction
 }
+
+var _ accesscontrol.RoleAssignmentService = new(FakeRoleAssignmentService)
+
+type FakeRoleAssignmentService struct {
+	ExpectedErr         error
+	ExpectedRole        *accesscontrol.RoleDTO
+	ExpectedAssignment  *accesscontrol.RoleAssignment
+	ExpectedAssignments []accesscontrol.RoleAssignment
+	ExpectedEvents      []accesscontrol.RoleAssignmentEvent
+	ExpectedDeleted     int64
+}
+
+func (f FakeRoleAssignmentService) GetAssignableRole(name string) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleAssignmentService) AssignRole(ctx context.Context, cmd *accesscontrol.AssignRoleCommand) (*accesscontrol.RoleAssignment, error) {
+	return f.ExpectedAssignment, f.ExpectedErr
+}
+
+func (f FakeRoleAssignmentService) GetRoleAssignments(ctx context.Context, orgID int64) ([]accesscontrol.RoleAssignment, error) {
+	return f.ExpectedAssignments, f.ExpectedErr
+}
+
+func (f FakeRoleAssignmentService) RemoveRoleAssignment(ctx context.Context, orgID, assignmentID, actorID int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleAssignmentService) GetRoleAssignmentEvents(ctx context.Context, orgID int64, limit int) ([]accesscontrol.RoleAssignmentEvent, error) {
+	return f.ExpectedEvents, f.ExpectedErr
+}
+
+func (f FakeRoleAssignmentService) DeleteExpiredRoleAssignments(ctx context.Context) (int64, error) {
+	return f.ExpectedDeleted, f.ExpectedErr
+}
+
+var _ accesscontrol.PolicySimulator = new(FakePolicySimulator)
+
+type FakePolicySimulator struct {
+	ExpectedErr        error
+	ExpectedSimulation *accesscontrol.Simulation
+}
+
+func (f FakePolicySimulator) Simulate(ctx context.Context, user *user.SignedInUser, action, scope string) (*accesscontrol.Simulation, error) {
+	return f.ExpectedSimulation, f.ExpectedErr
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const defaultRoleAssignmentEventsLimit = 100
+
+func NewRoleAssignmentAPI(router routing.RouteRegister, accesscontrol ac.AccessControl, service ac.RoleAssignmentService) *RoleAssignmentAPI {
+	return &RoleAssignmentAPI{
+		RouteRegister: router,
+		Service:       service,
+		AccessControl: accesscontrol,
+	}
+}
+
+type RoleAssignmentAPI struct {
+	Service       ac.RoleAssignmentService
+	AccessControl ac.AccessControl
+	RouteRegister routing.RouteRegister
+}
+
+func (api *RoleAssignmentAPI) RegisterAPIEndpoints() {
+	authorize := ac.Middleware(api.AccessControl)
+	api.RouteRegister.Group("/api/access-control/assignments", func(rr routing.RouteRegister) {
+		rr.Get("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionRoleAssignmentsRead)), routing.Wrap(api.getRoleAssignments))
+		rr.Post("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionRoleAssignmentsWrite)), routing.Wrap(api.assignRole))
+		rr.Get("/events", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionRoleAssignmentsRead)), routing.Wrap(api.getRoleAssignmentEvents))
+		rr.Delete("/:assignmentId", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionRoleAssignmentsWrite)), routing.Wrap(api.removeRoleAssignment))
+	})
+}
+
+// GET /api/access-control/assignments
+func (api *RoleAssignmentAPI) getRoleAssignments(c *contextmodel.ReqContext) response.Response {
+	assignments, err := api.Service.GetRoleAssignments(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role assignments", err)
+	}
+	return response.JSON(http.StatusOK, assignments)
+}
+
+// POST /api/access-control/assignments
+func (api *RoleAssignmentAPI) assignRole(c *contextmodel.ReqContext) response.Response {
+	cmd := ac.AssignRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.ActorID = c.UserID
+
+	role, err := api.Service.GetAssignableRole(cmd.RoleName)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Failed to assign role", err)
+	}
+
+	// prevent privilege escalation: users can only hand out permissions they already hold
+	evaluators := make([]ac.Evaluator, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		if p.Scope == "" {
+			evaluators = append(evaluators, ac.EvalPermission(p.Action))
+			continue
+		}
+		evaluators = append(evaluators, ac.EvalPermission(p.Action, p.Scope))
+	}
+	hasAccess, err := api.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalAll(evaluators...))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+	}
+	if !hasAccess {
+		return response.Error(http.StatusForbidden, "Cannot assign a role with permissions you do not have", nil)
+	}
+
+	assignment, err := api.Service.AssignRole(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to assign role", err)
+	}
+	return response.JSON(http.StatusOK, assignment)
+}
+
+// DELETE /api/access-control/assignments/:assignmentId
+func (api *RoleAssignmentAPI) removeRoleAssignment(c *contextmodel.ReqContext) response.Response {
+	assignmentID, err := strconv.ParseInt(web.Params(c.Req)[":assignmentId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Assignment ID is invalid", err)
+	}
+
+	if err := api.Service.RemoveRoleAssignment(c.Req.Context(), c.OrgID, assignmentID, c.UserID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove role assignment", err)
+	}
+	return response.Success("Role assignment removed")
+}
+
+// GET /api/access-control/assignments/events
+func (api *RoleAssignmentAPI) getRoleAssignmentEvents(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = defaultRoleAssignmentEventsLimit
+	}
+
+	events, err := api.Service.GetRoleAssignmentEvents(c.Req.Context(), c.OrgID, limit)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role assignment events", err)
+	}
+	return response.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+// evaluatingAccessControl evaluates permissions against the ones of the signed in user
+type evaluatingAccessControl struct {
+	actest.FakeAccessControl
+}
+
+func (e evaluatingAccessControl) Evaluate(ctx context.Context, user *user.SignedInUser, evaluator ac.Evaluator) (bool, error) {
+	return evaluator.Evaluate(user.Permissions[user.OrgID]), nil
+}
+
+func TestAPI_RoleAssignments(t *testing.T) {
+	role := &ac.RoleDTO{
+		Name:        "fixed:alerting.rules:writer",
+		Permissions: []ac.Permission{{Action: "alert.rules:write", Scope: "folders:*"}},
+	}
+
+	type testCase struct {
+		desc         string
+		method       string
+		url          string
+		body         string
+		permissions  map[string][]string
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should list assignments with read permission",
+			method:       http.MethodGet,
+			url:          "/api/access-control/assignments",
+			permissions:  map[string][]string{ac.ActionRoleAssignmentsRead: {}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not list assignments without read permission",
+			method:       http.MethodGet,
+			url:          "/api/access-control/assignments",
+			permissions:  map[string][]string{},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should list audit log with read permission",
+			method:       http.MethodGet,
+			url:          "/api/access-control/assignments/events?limit=10",
+			permissions:  map[string][]string{ac.ActionRoleAssignmentsRead: {}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "should assign a role when holding all of its permissions",
+			method: http.MethodPost,
+			url:    "/api/access-control/assignments",
+			body:   `{"roleName": "fixed:alerting.rules:writer", "userId": 2, "expires": "2100-01-01T00:00:00Z", "justification": "incident"}`,
+			permissions: map[string][]string{
+				ac.ActionRoleAssignmentsWrite: {},
+				"alert.rules:write":           {"folders:*"},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not assign a role with permissions the user does not have",
+			method:       http.MethodPost,
+			url:          "/api/access-control/assignments",
+			body:         `{"roleName": "fixed:alerting.rules:writer", "userId": 2, "expires": "2100-01-01T00:00:00Z"}`,
+			permissions:  map[string][]string{ac.ActionRoleAssignmentsWrite: {}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not assign a role without write permission",
+			method:       http.MethodPost,
+			url:          "/api/access-control/assignments",
+			body:         `{"roleName": "fixed:alerting.rules:writer", "userId": 2, "expires": "2100-01-01T00:00:00Z"}`,
+			permissions:  map[string][]string{ac.ActionRoleAssignmentsRead: {}, "alert.rules:write": {"folders:*"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should remove an assignment with write permission",
+			method:       http.MethodDelete,
+			url:          "/api/access-control/assignments/1",
+			permissions:  map[string][]string{ac.ActionRoleAssignmentsWrite: {}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should reject invalid assignment ids",
+			method:       http.MethodDelete,
+			url:          "/api/access-control/assignments/abc",
+			permissions:  map[string][]string{ac.ActionRoleAssignmentsWrite: {}},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			svc := actest.FakeRoleAssignmentService{
+				ExpectedRole:       role,
+				ExpectedAssignment: &ac.RoleAssignment{ID: 1, OrgID: 1, RoleName: role.Name, UserID: 2},
+			}
+			api := NewRoleAssignmentAPI(routing.NewRouteRegister(), evaluatingAccessControl{}, svc)
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       1,
+				UserID:      1,
+				Permissions: map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+		})
+	}
+}
@@ -50,6 +50,67 @@ func (s *AccessControlStore) GetUserPermissions(ctx context.Context, query acces
 	return result, err
 }
 
+// GetUserPermissionSources returns the permissions of the user along with the managed role, and the team or
+// basic role they are granted through.
+func (s *AccessControlStore) GetUserPermissionSources(ctx context.Context, query accesscontrol.GetUserPermissionsQuery) ([]accesscontrol.PermissionSource, error) {
+	result := make([]accesscontrol.PermissionSource, 0)
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		q := `
+		SELECT
+			permission.action,
+			permission.scope,
+			role.name AS role_name,
+			src.source,
+			src.team_id,
+			src.builtin_role
+			FROM permission
+			INNER JOIN role ON role.id = permission.role_id
+			INNER JOIN (
+				SELECT ur.role_id, '` + accesscontrol.PermissionSourceUser + `' AS source, 0 AS team_id, '' AS builtin_role
+				FROM user_role AS ur
+				WHERE ur.user_id = ? AND (ur.org_id = ? OR ur.org_id = ?)
+		`
+		params := []interface{}{query.UserID, query.OrgID, accesscontrol.GlobalOrgID}
+
+		if len(query.TeamIDs) > 0 {
+			q += `
+			UNION ALL
+				SELECT tr.role_id, '` + accesscontrol.PermissionSourceTeam + `' AS source, tr.team_id, '' AS builtin_role
+				FROM team_role AS tr
+				WHERE tr.team_id IN (?` + strings.Repeat(", ?", len(query.TeamIDs)-1) + `) AND tr.org_id = ?
+			`
+			for _, id := range query.TeamIDs {
+				params = append(params, id)
+			}
+			params = append(params, query.OrgID)
+		}
+
+		if len(query.Roles) > 0 {
+			q += `
+			UNION ALL
+				SELECT br.role_id, '` + accesscontrol.PermissionSourceBasicRole + `' AS source, 0 AS team_id, br.role AS builtin_role
+				FROM builtin_role AS br
+				WHERE br.role IN (?` + strings.Repeat(", ?", len(query.Roles)-1) + `) AND (br.org_id = ? OR br.org_id = ?)
+			`
+			for _, role := range query.Roles {
+				params = append(params, role)
+			}
+			params = append(params, query.OrgID, accesscontrol.GlobalOrgID)
+		}
+
+		q += `) AS src ON src.role_id = permission.role_id`
+
+		if query.RolePrefix != "" {
+			q += " WHERE role.name LIKE ?"
+			params = append(params, query.RolePrefix+"%")
+		}
+
+		return sess.SQL(q, params...).Find(&result)
+	})
+
+	return result, err
+}
+
 // SearchUsersPermissions returns the list of user permissions indexed by UserID
 func (s *AccessControlStore) SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error) {
 	type UserRBACPermission struct {
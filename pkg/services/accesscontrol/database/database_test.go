@@ -136,6 +136,59 @@ func TestAccessControlStore_GetUserPermissions(t *testing.T) {
 	}
 }
 
+func TestAccessControlStore_GetUserPermissionSources(t *testing.T) {
+	store, permissionStore, sql, teamSvc, _ := setupTestEnv(t)
+	user, team := createUserAndTeam(t, sql, teamSvc, 1)
+
+	setPermission := func(set func(cmd rs.SetResourcePermissionCommand) error, action, id string) {
+		require.NoError(t, set(rs.SetResourcePermissionCommand{Actions: []string{action}, Resource: "dashboards", ResourceAttribute: "uid", ResourceID: id}))
+	}
+	setPermission(func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetUserResourcePermission(context.Background(), 1, accesscontrol.User{ID: user.ID}, cmd, nil)
+		return err
+	}, "dashboards:write", "1")
+	setPermission(func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetTeamResourcePermission(context.Background(), 1, team.ID, cmd, nil)
+		return err
+	}, "dashboards:read", "2")
+	setPermission(func(cmd rs.SetResourcePermissionCommand) error {
+		_, err := permissionStore.SetBuiltInResourcePermission(context.Background(), 1, "Editor", cmd, nil)
+		return err
+	}, "dashboards:read", "3")
+
+	sources, err := store.GetUserPermissionSources(context.Background(), accesscontrol.GetUserPermissionsQuery{
+		OrgID:      1,
+		UserID:     user.ID,
+		Roles:      []string{"Editor", "Viewer"},
+		TeamIDs:    []int64{team.ID},
+		RolePrefix: accesscontrol.ManagedRolePrefix,
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []accesscontrol.PermissionSource{
+		{
+			Action:   "dashboards:write",
+			Scope:    "dashboards:uid:1",
+			Source:   accesscontrol.PermissionSourceUser,
+			RoleName: accesscontrol.ManagedUserRoleName(user.ID),
+		},
+		{
+			Action:   "dashboards:read",
+			Scope:    "dashboards:uid:2",
+			Source:   accesscontrol.PermissionSourceTeam,
+			RoleName: accesscontrol.ManagedTeamRoleName(team.ID),
+			TeamID:   team.ID,
+		},
+		{
+			Action:      "dashboards:read",
+			Scope:       "dashboards:uid:3",
+			Source:      accesscontrol.PermissionSourceBasicRole,
+			RoleName:    accesscontrol.ManagedBuiltInRoleName("Editor"),
+			BuiltInRole: "Editor",
+		},
+	}, sources)
+}
+
 func TestAccessControlStore_DeleteUserPermissions(t *testing.T) {
 	t.Run("expect permissions in all orgs to be deleted", func(t *testing.T) {
 		store, permissionsStore, sql, teamSvc, _ := setupTestEnv(t)
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// AddRoleAssignment stores the assignment and records it in the audit log.
+func (s *AccessControlStore) AddRoleAssignment(ctx context.Context, assignment *accesscontrol.RoleAssignment) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(assignment); err != nil {
+			return err
+		}
+
+		event := accesscontrol.NewRoleAssignmentEvent(assignment, accesscontrol.RoleAssignmentEventAssigned, assignment.CreatedBy, assignment.Created)
+		_, err := sess.Insert(event)
+		return err
+	})
+}
+
+// GetRoleAssignments returns the role assignments of an organization, optionally
+// restricted to the ones applying to a user, its teams and basic roles.
+func (s *AccessControlStore) GetRoleAssignments(ctx context.Context, query accesscontrol.GetRoleAssignmentsQuery) ([]accesscontrol.RoleAssignment, error) {
+	result := make([]accesscontrol.RoleAssignment, 0)
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+
+		if !query.ActiveAt.IsZero() {
+			q = q.And("expires > ?", query.ActiveAt)
+		}
+
+		if query.UserID != 0 || len(query.TeamIDs) > 0 || len(query.Roles) > 0 {
+			filters := make([]string, 0, 3)
+			params := make([]interface{}, 0, 1+len(query.TeamIDs)+len(query.Roles))
+			if query.UserID != 0 {
+				filters = append(filters, "user_id = ?")
+				params = append(params, query.UserID)
+			}
+			if len(query.TeamIDs) > 0 {
+				filters = append(filters, "team_id IN (?"+strings.Repeat(",?", len(query.TeamIDs)-1)+")")
+				for _, id := range query.TeamIDs {
+					params = append(params, id)
+				}
+			}
+			if len(query.Roles) > 0 {
+				filters = append(filters, "builtin_role IN (?"+strings.Repeat(",?", len(query.Roles)-1)+")")
+				for _, role := range query.Roles {
+					params = append(params, role)
+				}
+			}
+			q = q.And("("+strings.Join(filters, " OR ")+")", params...)
+		}
+
+		return q.Asc("expires").Find(&result)
+	})
+	return result, err
+}
+
+// DeleteRoleAssignment removes an assignment before it expires and records the revocation in the audit log.
+func (s *AccessControlStore) DeleteRoleAssignment(ctx context.Context, orgID, assignmentID, actorID int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		assignment := accesscontrol.RoleAssignment{}
+		has, err := sess.Where("org_id = ? AND id = ?", orgID, assignmentID).Get(&assignment)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return accesscontrol.ErrRoleAssignmentNotFound.Errorf("role assignment %d not found", assignmentID)
+		}
+
+		if _, err := sess.Exec("DELETE FROM role_assignment WHERE id = ?", assignment.ID); err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(accesscontrol.NewRoleAssignmentEvent(&assignment, accesscontrol.RoleAssignmentEventRevoked, actorID, time.Now()))
+		return err
+	})
+}
+
+// DeleteExpiredRoleAssignments removes all assignments that expired before now and
+// records their expiry in the audit log. It returns the removed assignments.
+func (s *AccessControlStore) DeleteExpiredRoleAssignments(ctx context.Context, now time.Time) ([]accesscontrol.RoleAssignment, error) {
+	expired := make([]accesscontrol.RoleAssignment, 0)
+	removed := make([]accesscontrol.RoleAssignment, 0)
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := sess.Where("expires <= ?", now).Find(&expired); err != nil {
+			return err
+		}
+
+		for i := range expired {
+			res, err := sess.Exec("DELETE FROM role_assignment WHERE id = ?", expired[i].ID)
+			if err != nil {
+				return err
+			}
+			// skip assignments removed concurrently by another instance to avoid duplicated audit entries
+			if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+				continue
+			}
+
+			if _, err := sess.Insert(accesscontrol.NewRoleAssignmentEvent(&expired[i], accesscontrol.RoleAssignmentEventExpired, 0, now)); err != nil {
+				return err
+			}
+			removed = append(removed, expired[i])
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// GetRoleAssignmentEvents returns the most recent audit log entries of an organization.
+func (s *AccessControlStore) GetRoleAssignmentEvents(ctx context.Context, orgID int64, limit int) ([]accesscontrol.RoleAssignmentEvent, error) {
+	result := make([]accesscontrol.RoleAssignmentEvent, 0)
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Desc("created").Desc("id").Limit(limit).Find(&result)
+	})
+	return result, err
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestIntegrationAccessControlStore_RoleAssignments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	store := ProvideService(db.InitTestDB(t))
+	now := time.Now().Truncate(time.Second)
+
+	assignments := []*accesscontrol.RoleAssignment{
+		{OrgID: 1, RoleName: "fixed:alerting.rules:writer", UserID: 1, Justification: "incident", CreatedBy: 10, Created: now, Expires: now.Add(time.Hour)},
+		{OrgID: 1, RoleName: "fixed:alerting.rules:writer", TeamID: 2, CreatedBy: 10, Created: now, Expires: now.Add(2 * time.Hour)},
+		{OrgID: 1, RoleName: "fixed:users:writer", BuiltInRole: "Editor", CreatedBy: 10, Created: now, Expires: now.Add(-time.Minute)},
+		{OrgID: 2, RoleName: "fixed:users:writer", UserID: 1, CreatedBy: 10, Created: now, Expires: now.Add(time.Hour)},
+	}
+	for _, a := range assignments {
+		require.NoError(t, store.AddRoleAssignment(ctx, a))
+		require.NotZero(t, a.ID)
+	}
+
+	t.Run("should list all assignments of an organization", func(t *testing.T) {
+		res, err := store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Len(t, res, 3)
+	})
+
+	t.Run("should only return active assignments applying to the user", func(t *testing.T) {
+		res, err := store.GetRoleAssignments(ctx, accesscontrol.GetRoleAssignmentsQuery{
+			OrgID:    1,
+			UserID:   1,
+			TeamIDs:  []int64{2},
+			Roles:    []string{"Editor", "Viewer"},
+			ActiveAt: now,
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, assignments[0].ID, res[0].ID)
+		assert.Equal(t, assignments[1].ID, res[1].ID)
+	})
+
+	t.Run("should delete expired assignments and record the expiry", func(t *testing.T) {
+		removed, err := store.DeleteExpiredRoleAssignments(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		assert.Equal(t, assignments[2].ID, removed[0].ID)
+
+		removed, err = store.DeleteExpiredRoleAssignments(ctx, now)
+		require.NoError(t, err)
+		assert.Len(t, removed, 0)
+	})
+
+	t.Run("should revoke an assignment and record the revocation", func(t *testing.T) {
+		require.NoError(t, store.DeleteRoleAssignment(ctx, 1, assignments[0].ID, 20))
+
+		err := store.DeleteRoleAssignment(ctx, 1, assignments[0].ID, 20)
+		assert.ErrorIs(t, err, accesscontrol.ErrRoleAssignmentNotFound)
+
+		err = store.DeleteRoleAssignment(ctx, 1, assignments[3].ID, 20)
+		assert.ErrorIs(t, err, accesscontrol.ErrRoleAssignmentNotFound)
+	})
+
+	t.Run("should return the audit log of an organization", func(t *testing.T) {
+		events, err := store.GetRoleAssignmentEvents(ctx, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 5)
+
+		counts := map[string]int{}
+		for _, e := range events {
+			counts[e.Event]++
+		}
+		assert.Equal(t, map[string]int{
+			accesscontrol.RoleAssignmentEventAssigned: 3,
+			accesscontrol.RoleAssignmentEventExpired:  1,
+			accesscontrol.RoleAssignmentEventRevoked:  1,
+		}, counts)
+
+		events, err = store.GetRoleAssignmentEvents(ctx, 1, 2)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
+}
@@ -3,6 +3,8 @@ package accesscontrol
 import (
 	"errors"
 	"fmt"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
@@ -13,6 +15,12 @@ var (
 	ErrPluginIDRequired       = errors.New("plugin ID is required")
 )
 
+var (
+	ErrRoleAssignmentInvalid      = errutil.NewBase(errutil.StatusBadRequest, "accesscontrol.roleAssignmentInvalid", errutil.WithPublicMessage("invalid role assignment"))
+	ErrRoleAssignmentNotFound     = errutil.NewBase(errutil.StatusNotFound, "accesscontrol.roleAssignmentNotFound", errutil.WithPublicMessage("role assignment not found"))
+	ErrRoleAssignmentRoleNotFound = errutil.NewBase(errutil.StatusBadRequest, "accesscontrol.roleAssignmentRoleNotFound", errutil.WithPublicMessage("role cannot be assigned"))
+)
+
 type ErrorInvalidRole struct{}
 
 func (e *ErrorInvalidRole) Error() string {
@@ -169,6 +169,86 @@ type BuiltinRole struct {
 	Created time.Time
 }
 
+// RoleAssignment grants a fixed role to a user, a team or a basic role until it expires.
+// Exactly one of UserID, TeamID and BuiltInRole is set.
+type RoleAssignment struct {
+	ID            int64     `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID         int64     `json:"orgId" xorm:"org_id"`
+	RoleName      string    `json:"roleName" xorm:"role_name"`
+	UserID        int64     `json:"userId,omitempty" xorm:"user_id"`
+	TeamID        int64     `json:"teamId,omitempty" xorm:"team_id"`
+	BuiltInRole   string    `json:"builtInRole,omitempty" xorm:"builtin_role"`
+	Justification string    `json:"justification" xorm:"justification"`
+	CreatedBy     int64     `json:"createdBy" xorm:"created_by"`
+	Created       time.Time `json:"created" xorm:"created"`
+	Expires       time.Time `json:"expires" xorm:"expires"`
+}
+
+func (a RoleAssignment) TableName() string { return "role_assignment" }
+
+const (
+	RoleAssignmentEventAssigned = "assigned"
+	RoleAssignmentEventRevoked  = "revoked"
+	RoleAssignmentEventExpired  = "expired"
+)
+
+// RoleAssignmentEvent is the audit log entry written whenever a role assignment
+// is created, revoked or expires. ActorID is 0 for events raised by Grafana itself.
+type RoleAssignmentEvent struct {
+	ID            int64     `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID         int64     `json:"orgId" xorm:"org_id"`
+	AssignmentID  int64     `json:"assignmentId" xorm:"assignment_id"`
+	Event         string    `json:"event" xorm:"event"`
+	RoleName      string    `json:"roleName" xorm:"role_name"`
+	UserID        int64     `json:"userId,omitempty" xorm:"user_id"`
+	TeamID        int64     `json:"teamId,omitempty" xorm:"team_id"`
+	BuiltInRole   string    `json:"builtInRole,omitempty" xorm:"builtin_role"`
+	Justification string    `json:"justification" xorm:"justification"`
+	ActorID       int64     `json:"actorId" xorm:"actor_id"`
+	Expires       time.Time `json:"expires" xorm:"expires"`
+	Created       time.Time `json:"created" xorm:"created"`
+}
+
+func (e RoleAssignmentEvent) TableName() string { return "role_assignment_event" }
+
+// NewRoleAssignmentEvent creates an audit log entry for the given assignment.
+func NewRoleAssignmentEvent(a *RoleAssignment, event string, actorID int64, now time.Time) *RoleAssignmentEvent {
+	return &RoleAssignmentEvent{
+		OrgID:         a.OrgID,
+		AssignmentID:  a.ID,
+		Event:         event,
+		RoleName:      a.RoleName,
+		UserID:        a.UserID,
+		TeamID:        a.TeamID,
+		BuiltInRole:   a.BuiltInRole,
+		Justification: a.Justification,
+		ActorID:       actorID,
+		Expires:       a.Expires,
+		Created:       now,
+	}
+}
+
+type AssignRoleCommand struct {
+	OrgID         int64     `json:"-"`
+	ActorID       int64     `json:"-"`
+	RoleName      string    `json:"roleName"`
+	UserID        int64     `json:"userId"`
+	TeamID        int64     `json:"teamId"`
+	BuiltInRole   string    `json:"builtInRole"`
+	Justification string    `json:"justification"`
+	Expires       time.Time `json:"expires"`
+}
+
+type GetRoleAssignmentsQuery struct {
+	OrgID int64
+	// Filter on the assignments of a user, its teams and basic roles. Returns all assignments of the org when empty.
+	UserID  int64
+	TeamIDs []int64
+	Roles   []string
+	// Only return the assignments that have not expired at the given time.
+	ActiveAt time.Time
+}
+
 // Permission is the model for access control permissions.
 type Permission struct {
 	ID     int64  `json:"-" xorm:"pk autoincr 'id'"`
@@ -195,6 +275,47 @@ type GetUserPermissionsQuery struct {
 	RolePrefix string
 }
 
+const (
+	PermissionSourceBasicRole  = "basicRole"
+	PermissionSourceUser       = "user"
+	PermissionSourceTeam       = "team"
+	PermissionSourceAssignment = "assignment"
+)
+
+// PermissionSource is a permission granted to a user along with the role, and the team or
+// basic role the user got it from.
+type PermissionSource struct {
+	Action       string     `json:"action" xorm:"action"`
+	Scope        string     `json:"scope" xorm:"scope"`
+	Source       string     `json:"source" xorm:"source"`
+	RoleName     string     `json:"roleName" xorm:"role_name"`
+	TeamID       int64      `json:"teamId,omitempty" xorm:"team_id"`
+	BuiltInRole  string     `json:"builtInRole,omitempty" xorm:"builtin_role"`
+	AssignmentID int64      `json:"assignmentId,omitempty" xorm:"-"`
+	Expires      *time.Time `json:"expires,omitempty" xorm:"-"`
+}
+
+// ScopeResolution is a step of the scope resolution chain, e.g. a dashboard scope
+// resolved to the scopes of the folders it inherits permissions from.
+type ScopeResolution struct {
+	Scope    string   `json:"scope"`
+	Resolved []string `json:"resolved"`
+}
+
+// Simulation is the access control decision on an action and scope for a user along with what led to it.
+type Simulation struct {
+	Allowed bool   `json:"allowed"`
+	Action  string `json:"action"`
+	Scope   string `json:"scope"`
+	// ScopeResolution lists the resolution steps performed by the scope attribute resolvers.
+	ScopeResolution []ScopeResolution `json:"scopeResolution"`
+	// Permissions lists the permissions of the user matching the action and one of the resolved scopes.
+	Permissions []PermissionSource `json:"permissions"`
+	// Roles and Teams list the roles and teams the matching permissions come from.
+	Roles []string `json:"roles"`
+	Teams []int64  `json:"teams"`
+}
+
 // ResourcePermission is structure that holds all actions that either a team / user / builtin-role
 // can perform against specific resource.
 type ResourcePermission struct {
@@ -284,6 +405,10 @@ const (
 	ActionUsersQuotasUpdate      = "users.quotas:write"
 	ActionUsersPermissionsRead   = "users.permissions:read"
 
+	// Role assignment actions
+	ActionRoleAssignmentsRead  = "roles.assignments:read"
+	ActionRoleAssignmentsWrite = "roles.assignments:write"
+
 	// Org actions
 	ActionOrgsRead             = "orgs:read"
 	ActionOrgsPreferencesRead  = "orgs.preferences:read"
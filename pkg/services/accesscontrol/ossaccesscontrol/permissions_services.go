@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/licensing"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/retriever"
 	"github.com/grafana/grafana/pkg/services/team"
@@ -42,7 +43,7 @@ var (
 func ProvideTeamPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB,
 	ac accesscontrol.AccessControl, license licensing.Licensing, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, provenanceService provenance.Service,
 ) (*TeamPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "teams",
@@ -73,6 +74,7 @@ func ProvideTeamPermissions(
 			"Member": TeamMemberActions,
 			"Admin":  TeamAdminActions,
 		},
+		WriteValidator: provenanceValidator(provenanceService, provenance.RecordTypeTeam),
 		ReaderRoleName: "Team permission reader",
 		WriterRoleName: "Team permission writer",
 		RoleGroup:      "Teams",
@@ -116,7 +118,7 @@ var DashboardAdminActions = append(DashboardEditActions, []string{dashboards.Act
 func ProvideDashboardPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB, ac accesscontrol.AccessControl,
 	license licensing.Licensing, dashboardStore dashboards.Store, folderService folder.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, provenanceService provenance.Service,
 ) (*DashboardPermissionsService, error) {
 	getDashboard := func(ctx context.Context, orgID int64, resourceID string) (*dashboards.Dashboard, error) {
 		query := &dashboards.GetDashboardQuery{UID: resourceID, OrgID: orgID}
@@ -173,6 +175,7 @@ func ProvideDashboardPermissions(
 			"Edit":  DashboardEditActions,
 			"Admin": DashboardAdminActions,
 		},
+		WriteValidator: provenanceValidator(provenanceService, provenance.RecordTypeDashboardPermissions),
 		ReaderRoleName: "Dashboard permission reader",
 		WriterRoleName: "Dashboard permission writer",
 		RoleGroup:      "Dashboards",
@@ -203,7 +206,7 @@ var FolderAdminActions = append(FolderEditActions, []string{dashboards.ActionFol
 func ProvideFolderPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB, accesscontrol accesscontrol.AccessControl,
 	license licensing.Licensing, dashboardStore dashboards.Store, folderService folder.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, provenanceService provenance.Service,
 ) (*FolderPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "folders",
@@ -234,6 +237,7 @@ func ProvideFolderPermissions(
 			"Edit":  append(DashboardEditActions, FolderEditActions...),
 			"Admin": append(DashboardAdminActions, FolderAdminActions...),
 		},
+		WriteValidator: provenanceValidator(provenanceService, provenance.RecordTypeFolderPermissions),
 		ReaderRoleName: "Folder permission reader",
 		WriterRoleName: "Folder permission writer",
 		RoleGroup:      "Folders",
@@ -331,3 +335,10 @@ func ProvideServiceAccountPermissions(
 	}
 	return &ServiceAccountPermissionsService{srv}, nil
 }
+
+// provenanceValidator blocks changes through the API to the permissions of resources that are provisioned from files.
+func provenanceValidator(provenanceService provenance.Service, recordType string) resourcepermissions.ResourceValidator {
+	return func(ctx context.Context, orgID int64, resourceID string) error {
+		return provenance.CheckWritable(ctx, provenanceService, orgID, recordType, resourceID)
+	}
+}
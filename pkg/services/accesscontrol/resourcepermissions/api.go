@@ -156,6 +156,10 @@ func (a *api) setUserPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.validateWrite(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err = a.service.SetUserPermission(c.Req.Context(), c.OrgID, accesscontrol.User{ID: userID}, resourceID, cmd.Permission)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set user permission", err)
@@ -176,6 +180,10 @@ func (a *api) setTeamPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.validateWrite(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err = a.service.SetTeamPermission(c.Req.Context(), c.OrgID, teamID, resourceID, cmd.Permission)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set team permission", err)
@@ -193,6 +201,10 @@ func (a *api) setBuiltinRolePermission(c *contextmodel.ReqContext) response.Resp
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.validateWrite(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err := a.service.SetBuiltInRolePermission(c.Req.Context(), c.OrgID, builtInRole, resourceID, cmd.Permission)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set role permission", err)
@@ -209,6 +221,10 @@ func (a *api) setPermissions(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if resp := a.validateWrite(c, resourceID); resp != nil {
+		return resp
+	}
+
 	_, err := a.service.SetPermissions(c.Req.Context(), c.OrgID, resourceID, cmd.Permissions...)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "failed to set permissions", err)
@@ -217,6 +233,17 @@ func (a *api) setPermissions(c *contextmodel.ReqContext) response.Response {
 	return response.Success("Permissions updated")
 }
 
+// validateWrite returns an error response when the permissions of the resource cannot be changed through the API.
+func (a *api) validateWrite(c *contextmodel.ReqContext, resourceID string) response.Response {
+	if a.service.options.WriteValidator == nil {
+		return nil
+	}
+	if err := a.service.options.WriteValidator(c.Req.Context(), c.OrgID, resourceID); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "failed to set permissions", err)
+	}
+	return nil
+}
+
 func permissionSetResponse(cmd setPermissionCommand) response.Response {
 	message := "Permission updated"
 	if cmd.Permission == "" {
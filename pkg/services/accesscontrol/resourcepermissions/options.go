@@ -42,4 +42,7 @@ type Options struct {
 	InheritedScopesSolver InheritedScopesSolver
 	// LicenseMV if configured is applied to endpoints that can modify permissions
 	LicenseMW web.Handler
+	// WriteValidator if configured will be called by the endpoints that can modify permissions before each change.
+	// Unlike ResourceValidator it is not called for changes made through the service, e.g. by provisioning
+	WriteValidator ResourceValidator
 }
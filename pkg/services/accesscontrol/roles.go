@@ -173,6 +173,30 @@ var (
 		}),
 	}
 
+	roleAssignmentsReaderRole = RoleDTO{
+		Name:        "fixed:roles.assignments:reader",
+		DisplayName: "Role assignment reader",
+		Description: "Read time-bound role assignments and their audit log.",
+		Group:       "Access control",
+		Permissions: []Permission{
+			{
+				Action: ActionRoleAssignmentsRead,
+			},
+		},
+	}
+
+	roleAssignmentsWriterRole = RoleDTO{
+		Name:        "fixed:roles.assignments:writer",
+		DisplayName: "Role assignment writer",
+		Description: "Read, create and revoke time-bound role assignments.",
+		Group:       "Access control",
+		Permissions: ConcatPermissions(roleAssignmentsReaderRole.Permissions, []Permission{
+			{
+				Action: ActionRoleAssignmentsWrite,
+			},
+		}),
+	}
+
 	authenticationConfigWriterRole = RoleDTO{
 		Name:        "fixed:authentication.config:writer",
 		DisplayName: "Authentication config writer",
@@ -226,6 +250,15 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 		Grants: []string{RoleGrafanaAdmin},
 	}
 
+	roleAssignmentsReader := RoleRegistration{
+		Role:   roleAssignmentsReaderRole,
+		Grants: []string{RoleGrafanaAdmin, string(org.RoleAdmin)},
+	}
+	roleAssignmentsWriter := RoleRegistration{
+		Role:   roleAssignmentsWriterRole,
+		Grants: []string{RoleGrafanaAdmin, string(org.RoleAdmin)},
+	}
+
 	// TODO: Move to own service when implemented
 	authenticationConfigWriter := RoleRegistration{
 		Role:   authenticationConfigWriterRole,
@@ -237,7 +270,8 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 	}
 
 	return service.DeclareFixedRoles(ldapReader, ldapWriter, orgUsersReader, orgUsersWriter,
-		settingsReader, statsReader, usersReader, usersWriter, authenticationConfigWriter)
+		settingsReader, statsReader, usersReader, usersWriter, roleAssignmentsReader, roleAssignmentsWriter,
+		authenticationConfigWriter)
 }
 
 func ConcatPermissions(permissions ...[]Permission) []Permission {
@@ -12,7 +12,8 @@ type Service interface {
 	GetApiKeyById(ctx context.Context, query *GetByIDQuery) (res *APIKey, err error)
 	GetApiKeyByName(ctx context.Context, query *GetByNameQuery) (res *APIKey, err error)
 	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
-	UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error
+	// UpdateAPIKeyLastUsed records the time and client IP of the latest use of the key.
+	UpdateAPIKeyLastUsed(ctx context.Context, tokenID int64, ip string) error
 	// IsDisabled returns true if the API key is not available for use.
 	IsDisabled(ctx context.Context, orgID int64) (bool, error)
 }
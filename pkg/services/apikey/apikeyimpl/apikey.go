@@ -65,8 +65,8 @@ func (s *Service) DeleteApiKey(ctx context.Context, cmd *apikey.DeleteCommand) e
 func (s *Service) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) (res *apikey.APIKey, err error) {
 	return s.store.AddAPIKey(ctx, cmd)
 }
-func (s *Service) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
-	return s.store.UpdateAPIKeyLastUsedDate(ctx, tokenID)
+func (s *Service) UpdateAPIKeyLastUsed(ctx context.Context, tokenID int64, ip string) error {
+	return s.store.UpdateAPIKeyLastUsed(ctx, tokenID, ip)
 }
 
 // IsDisabled returns true if the apikey service is disabled for the given org.
@@ -147,9 +147,9 @@ func (ss *sqlxStore) GetAPIKeyByHash(ctx context.Context, hash string) (*apikey.
 	return &key, err
 }
 
-func (ss *sqlxStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
+func (ss *sqlxStore) UpdateAPIKeyLastUsed(ctx context.Context, tokenID int64, ip string) error {
 	now := timeNow()
-	_, err := ss.sess.Exec(ctx, `UPDATE api_key SET last_used_at=? WHERE id=?`, &now, tokenID)
+	_, err := ss.sess.Exec(ctx, `UPDATE api_key SET last_used_at=?, last_used_ip=? WHERE id=?`, &now, ip, tokenID)
 	return err
 }
 
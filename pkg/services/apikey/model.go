@@ -25,6 +25,7 @@ type APIKey struct {
 	Created          time.Time    `db:"created"`
 	Updated          time.Time    `db:"updated"`
 	LastUsedAt       *time.Time   `xorm:"last_used_at" db:"last_used_at"`
+	LastUsedIP       *string      `xorm:"last_used_ip" db:"last_used_ip"`
 	Expires          *int64       `db:"expires"`
 	ServiceAccountId *int64       `db:"service_account_id"`
 	IsRevoked        *bool        `xorm:"is_revoked" db:"is_revoked"`
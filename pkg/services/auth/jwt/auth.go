@@ -51,7 +51,7 @@ type AuthService struct {
 	Cfg         *setting.Cfg
 	RemoteCache *remotecache.RemoteCache
 
-	keySet           keySet
+	keySet           KeySet
 	log              log.Logger
 	expect           map[string]interface{}
 	expectRegistered jwt.Expected
@@ -68,13 +68,29 @@ func sanitizeJWT(jwtToken string) string {
 func (s *AuthService) Verify(ctx context.Context, strToken string) (JWTClaims, error) {
 	s.log.Debug("Parsing JSON Web Token")
 
-	strToken = sanitizeJWT(strToken)
-	token, err := jwt.ParseSigned(strToken)
+	claims, err := VerifySignature(ctx, s.keySet, strToken)
 	if err != nil {
 		return nil, err
 	}
 
-	keys, err := s.keySet.Key(ctx, token.Headers[0].KeyID)
+	s.log.Debug("Validating JSON Web Token claims")
+
+	if err = s.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// VerifySignature verifies the signature of a token with the keys of the key set and returns its claims.
+// Claims are not validated.
+func VerifySignature(ctx context.Context, keySet KeySet, strToken string) (JWTClaims, error) {
+	token, err := jwt.ParseSigned(sanitizeJWT(strToken))
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := keySet.Key(ctx, token.Headers[0].KeyID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +98,6 @@ func (s *AuthService) Verify(ctx context.Context, strToken string) (JWTClaims, e
 		return nil, errors.New("no keys found")
 	}
 
-	s.log.Debug("Trying to verify JSON Web Token using a key")
-
 	var claims JWTClaims
 	for _, key := range keys {
 		if err = token.Claims(key, &claims); err == nil {
@@ -94,12 +108,6 @@ func (s *AuthService) Verify(ctx context.Context, strToken string) (JWTClaims, e
 		return nil, err
 	}
 
-	s.log.Debug("Validating JSON Web Token claims")
-
-	if err = s.validateClaims(claims); err != nil {
-		return nil, err
-	}
-
 	return claims, nil
 }
 
@@ -25,7 +25,8 @@ var ErrKeySetIsNotConfigured = errors.New("key set for jwt verification is not c
 var ErrKeySetConfigurationAmbiguous = errors.New("key set configuration is ambiguous: you should set either key_file, jwk_set_file or jwk_set_url")
 var ErrJWTSetURLMustHaveHTTPSScheme = errors.New("jwt_set_url must have https scheme")
 
-type keySet interface {
+// KeySet provides the keys used to verify the signature of JSON Web Tokens
+type KeySet interface {
 	Key(ctx context.Context, kid string) ([]jose.JSONWebKey, error)
 }
 
@@ -150,19 +151,28 @@ func (s *AuthService) initKeySet() error {
 		if urlParsed.Scheme != "https" {
 			return ErrJWTSetURLMustHaveHTTPSScheme
 		}
-		s.keySet = &keySetHTTP{
-			url:             urlStr,
-			log:             s.log,
-			client:          &http.Client{},
-			cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", urlStr),
-			cacheExpiration: s.Cfg.JWTAuthCacheTTL,
-			cache:           s.RemoteCache,
-		}
+		s.keySet = NewKeySetHTTP(urlStr, &http.Client{}, s.RemoteCache, s.Cfg.JWTAuthCacheTTL)
 	}
 
 	return nil
 }
 
+// NewKeySetHTTP returns a key set fetched from a JWKS endpoint, keys are stored in the remote cache
+// when cacheExpiration is positive.
+func NewKeySetHTTP(url string, client *http.Client, cache *remotecache.RemoteCache, cacheExpiration time.Duration) KeySet {
+	if cache == nil {
+		cacheExpiration = 0
+	}
+	return &keySetHTTP{
+		url:             url,
+		log:             log.New("auth.jwt"),
+		client:          client,
+		cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", url),
+		cacheExpiration: cacheExpiration,
+		cache:           cache,
+	}
+}
+
 func (ks keySetJWKS) Key(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
 	return ks.JSONWebKeySet.Key(keyID), nil
 }
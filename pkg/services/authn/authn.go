@@ -31,11 +31,15 @@ const (
 	ClientForm      = "auth.client.form"
 	ClientProxy     = "auth.client.proxy"
 	ClientSAML      = "auth.client.saml"
+	ClientMFA       = "auth.client.mfa"
 )
 
 const (
 	MetaKeyUsername   = "username"
 	MetaKeyAuthModule = "authModule"
+	// MetaKeyRecoveryCodes holds the comma separated recovery codes generated when
+	// a user enrolled a second factor while signing in
+	MetaKeyRecoveryCodes = "recoveryCodes"
 )
 
 // ClientParams are hints to the auth service about how to handle the identity management
@@ -160,6 +164,7 @@ func (r *Request) GetMeta(k string) string {
 const (
 	KeyOAuthPKCE  = "pkce"
 	KeyOAuthState = "state"
+	KeyOAuthNonce = "nonce"
 )
 
 type Redirect struct {
@@ -338,7 +343,15 @@ type RedirectValidator func(url string) error
 
 // HandleLoginResponse is a utility function to perform common operations after a successful login and returns response.NormalResponse
 func HandleLoginResponse(r *http.Request, w http.ResponseWriter, cfg *setting.Cfg, identity *Identity, validator RedirectValidator) *response.NormalResponse {
+	return HandleLoginResponseWithData(r, w, cfg, identity, validator, nil)
+}
+
+// HandleLoginResponseWithData is HandleLoginResponse with additional fields in the response body
+func HandleLoginResponseWithData(r *http.Request, w http.ResponseWriter, cfg *setting.Cfg, identity *Identity, validator RedirectValidator, data map[string]interface{}) *response.NormalResponse {
 	result := map[string]interface{}{"message": "Logged in"}
+	for k, v := range data {
+		result[k] = v
+	}
 	if redirectURL := handleLogin(r, w, cfg, identity, validator); redirectURL != cfg.AppSubURL+"/" {
 		result["redirectUrl"] = redirectURL
 	}
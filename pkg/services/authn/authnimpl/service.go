@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -63,6 +64,7 @@ func ProvideService(
 	features *featuremgmt.FeatureManager, oauthTokenService oauthtoken.OAuthTokenService,
 	socialService social.Service, cache *remotecache.RemoteCache,
 	ldapService service.LDAP, registerer prometheus.Registerer,
+	mfaService mfa.Service,
 ) authn.Service {
 	s := &Service{
 		log:            log.New("authn.service"),
@@ -72,6 +74,7 @@ func ProvideService(
 		tracer:         tracer,
 		metrics:        newMetrics(registerer),
 		sessionService: sessionService,
+		mfaService:     mfaService,
 		postAuthHooks:  newQueue[authn.PostAuthHookFn](),
 		postLoginHooks: newQueue[authn.PostLoginHookFn](),
 	}
@@ -107,11 +110,12 @@ func ProvideService(
 	if len(passwordClients) > 0 {
 		passwordClient := clients.ProvidePassword(loginAttempts, passwordClients...)
 		if s.cfg.BasicAuthEnabled {
-			s.RegisterClient(clients.ProvideBasic(passwordClient))
+			s.RegisterClient(clients.ProvideBasic(passwordClient, mfaService))
 		}
 
 		if !s.cfg.DisableLoginForm {
 			s.RegisterClient(clients.ProvideForm(passwordClient))
+			s.RegisterClient(clients.ProvideMFA(mfaService, userService))
 		}
 	}
 
@@ -172,6 +176,7 @@ type Service struct {
 	metrics *metrics
 
 	sessionService auth.UserTokenService
+	mfaService     mfa.Service
 
 	// postAuthHooks are called after a successful authentication. They can modify the identity.
 	postAuthHooks *queue[authn.PostAuthHookFn]
@@ -278,6 +283,19 @@ func (s *Service) Login(ctx context.Context, client string, r *authn.Request) (i
 		return nil, authn.ErrUnsupportedIdentity.Errorf("expected identity of type user but got: %s", namespace)
 	}
 
+	// users signing in with a Grafana password may have to pass a second factor challenge before a session is created,
+	// they complete it with the mfa client
+	if client == authn.ClientForm && identity.AuthModule == "" {
+		challenge, err := s.mfaService.Challenge(ctx, id)
+		if err != nil {
+			s.metrics.failedLogin.WithLabelValues(client).Inc()
+			return nil, err
+		}
+		if challenge != nil {
+			return nil, mfa.NewChallengeRequiredError(challenge)
+		}
+	}
+
 	addr := web.RemoteAddr(r.HTTPRequest)
 	ip, err := network.GetIPFromAddress(addr)
 	if err != nil {
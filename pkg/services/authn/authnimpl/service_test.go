@@ -17,6 +17,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -262,6 +264,74 @@ func TestService_Login(t *testing.T) {
 	}
 }
 
+func TestService_LoginSecondFactor(t *testing.T) {
+	type TestCase struct {
+		desc              string
+		client            string
+		identity          *authn.Identity
+		challenge         *mfa.Challenge
+		expectedErr       error
+		expectedSessionOK bool
+	}
+
+	tests := []TestCase{
+		{
+			desc:        "should require second factor for password login",
+			client:      authn.ClientForm,
+			identity:    &authn.Identity{ID: "user:1"},
+			challenge:   &mfa.Challenge{Token: "token", Methods: []string{mfa.MethodTOTP}},
+			expectedErr: mfa.ErrChallengeRequired,
+		},
+		{
+			desc:              "should create session when no second factor is required",
+			client:            authn.ClientForm,
+			identity:          &authn.Identity{ID: "user:1"},
+			expectedSessionOK: true,
+		},
+		{
+			desc:              "should not require second factor for users authenticated by an external system",
+			client:            authn.ClientForm,
+			identity:          &authn.Identity{ID: "user:1", AuthModule: "ldap"},
+			challenge:         &mfa.Challenge{Token: "token", Methods: []string{mfa.MethodTOTP}},
+			expectedSessionOK: true,
+		},
+		{
+			desc:              "should create session after challenge is completed",
+			client:            authn.ClientMFA,
+			identity:          &authn.Identity{ID: "user:1"},
+			challenge:         &mfa.Challenge{Token: "token", Methods: []string{mfa.MethodTOTP}},
+			expectedSessionOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := setupTests(t, func(svc *Service) {
+				svc.RegisterClient(&authntest.FakeClient{ExpectedName: tt.client, ExpectedIdentity: tt.identity})
+				svc.mfaService = &mfatest.FakeService{ExpectedChallenge: tt.challenge}
+				svc.sessionService = &authtest.FakeUserAuthTokenService{
+					CreateTokenProvider: func(ctx context.Context, user *user.User, clientIP net.IP, userAgent string) (*auth.UserToken, error) {
+						return &auth.UserToken{UserId: user.ID}, nil
+					},
+				}
+			})
+
+			identity, err := s.Login(context.Background(), tt.client, &authn.Request{HTTPRequest: &http.Request{
+				Header: map[string][]string{},
+				URL:    &url.URL{},
+			}})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, identity)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSessionOK, identity.SessionToken != nil)
+		})
+	}
+}
+
 func TestService_RedirectURL(t *testing.T) {
 	type testCase struct {
 		desc        string
@@ -317,6 +387,7 @@ func setupTests(t *testing.T, opts ...func(svc *Service)) *Service {
 		clientQueue:    newQueue[authn.ContextAwareClient](),
 		tracer:         tracing.InitializeTracerForTest(),
 		metrics:        newMetrics(nil),
+		mfaService:     &mfatest.FakeService{},
 		postAuthHooks:  newQueue[authn.PostAuthHookFn](),
 		postLoginHooks: newQueue[authn.PostLoginHookFn](),
 	}
@@ -3,6 +3,7 @@ package clients
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 var (
@@ -23,6 +25,8 @@ var (
 	errAPIKeyRevoked = errutil.NewBase(errutil.StatusUnauthorized, "api-key.revoked", errutil.WithPublicMessage("Revoked API key"))
 )
 
+const metaKeyID = "keyID"
+
 var _ authn.HookClient = new(APIKey)
 var _ authn.ContextAwareClient = new(APIKey)
 
@@ -61,6 +65,9 @@ func (s *APIKey) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ide
 		return nil, errAPIKeyRevoked.Errorf("Api key is revoked")
 	}
 
+	// remember the key so the usage can be recorded once authentication succeeded
+	r.SetMeta(metaKeyID, strconv.FormatInt(apiKey.ID, 10))
+
 	// if the api key don't belong to a service account construct the identity and return it
 	if apiKey.ServiceAccountId == nil || *apiKey.ServiceAccountId < 1 {
 		return &authn.Identity{
@@ -145,18 +152,24 @@ func (s *APIKey) Priority() uint {
 }
 
 func (s *APIKey) Hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
-	namespace, id := identity.NamespacedID()
-	if namespace != authn.NamespaceAPIKey {
+	namespace, _ := identity.NamespacedID()
+	if namespace != authn.NamespaceAPIKey && namespace != authn.NamespaceServiceAccount {
+		return nil
+	}
+
+	id, err := strconv.ParseInt(r.GetMeta(metaKeyID), 10, 64)
+	if err != nil {
 		return nil
 	}
 
+	ip := web.RemoteAddr(r.HTTPRequest)
 	go func(apikeyID int64) {
 		defer func() {
 			if err := recover(); err != nil {
 				s.log.Error("panic during user last seen sync", "err", err)
 			}
 		}()
-		if err := s.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), apikeyID); err != nil {
+		if err := s.apiKeyService.UpdateAPIKeyLastUsed(context.Background(), apikeyID, ip); err != nil {
 			s.log.Warn("failed to update last use date for api key", "id", apikeyID)
 		}
 	}(id)
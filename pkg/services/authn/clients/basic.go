@@ -4,21 +4,25 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
 	errDecodingBasicAuthHeader = errutil.NewBase(errutil.StatusBadRequest, "basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
+	errBasicAuthMFARequired    = errutil.NewBase(errutil.StatusUnauthorized, "basic-auth.mfa-required", errutil.WithPublicMessage("Basic auth is not allowed for users with multi-factor authentication"))
 )
 
 var _ authn.ContextAwareClient = new(Basic)
+var _ authn.HookClient = new(Basic)
 
-func ProvideBasic(client authn.PasswordClient) *Basic {
-	return &Basic{client}
+func ProvideBasic(client authn.PasswordClient, mfaService mfa.Service) *Basic {
+	return &Basic{client, mfaService}
 }
 
 type Basic struct {
-	client authn.PasswordClient
+	client     authn.PasswordClient
+	mfaService mfa.Service
 }
 
 func (c *Basic) String() string {
@@ -38,6 +42,29 @@ func (c *Basic) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 	return c.client.AuthenticatePassword(ctx, r, username, password)
 }
 
+// Hook rejects users that have to pass a second factor challenge to sign in,
+// basic auth would let them skip it
+func (c *Basic) Hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
+	// second factors are only used by users signing in with a Grafana password
+	if !c.mfaService.IsEnabled() || identity.AuthModule != "" {
+		return nil
+	}
+
+	namespace, id := identity.NamespacedID()
+	if namespace != authn.NamespaceUser {
+		return nil
+	}
+
+	allowed, err := c.mfaService.AllowsBasicAuth(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errBasicAuthMFARequired.Errorf("user %d has to pass a second factor challenge to sign in", id)
+	}
+	return nil
+}
+
 func (c *Basic) Test(ctx context.Context, r *authn.Request) bool {
 	return looksLikeBasicAuthRequest(r)
 }
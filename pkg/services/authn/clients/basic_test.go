@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
 )
 
 func TestBasic_Authenticate(t *testing.T) {
@@ -36,7 +37,7 @@ func TestBasic_Authenticate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideBasic(tt.client)
+			c := ProvideBasic(tt.client, &mfatest.FakeService{})
 
 			identity, err := c.Authenticate(context.Background(), tt.req)
 			if tt.expectedErr != nil {
@@ -89,8 +90,52 @@ func TestBasic_Test(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideBasic(authntest.FakePasswordClient{})
+			c := ProvideBasic(authntest.FakePasswordClient{}, &mfatest.FakeService{})
 			assert.Equal(t, tt.expected, c.Test(context.Background(), tt.req))
 		})
 	}
 }
+
+func TestBasic_Hook(t *testing.T) {
+	type TestCase struct {
+		desc        string
+		identity    *authn.Identity
+		disabled    bool
+		allowed     bool
+		expectedErr error
+	}
+
+	tests := []TestCase{
+		{
+			desc:     "should allow users without second factor",
+			identity: &authn.Identity{ID: "user:1"},
+			allowed:  true,
+		},
+		{
+			desc:        "should reject users that have to pass a second factor challenge",
+			identity:    &authn.Identity{ID: "user:1"},
+			expectedErr: errBasicAuthMFARequired,
+		},
+		{
+			desc:     "should skip users authenticated by an external system",
+			identity: &authn.Identity{ID: "user:1", AuthModule: "ldap"},
+		},
+		{
+			desc:     "should skip the check when multi-factor authentication is disabled",
+			identity: &authn.Identity{ID: "user:1"},
+			disabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := ProvideBasic(authntest.FakePasswordClient{}, &mfatest.FakeService{ExpectedEnabled: !tt.disabled, ExpectedAllowsBasicAuth: tt.allowed})
+			err := c.Hook(context.Background(), tt.identity, &authn.Request{})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
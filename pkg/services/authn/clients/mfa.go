@@ -0,0 +1,56 @@
+package clients
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var (
+	errBadMFAForm = errutil.NewBase(errutil.StatusBadRequest, "mfa-auth.invalid", errutil.WithPublicMessage("bad verification data"))
+)
+
+var _ authn.Client = new(MFA)
+
+func ProvideMFA(mfaService mfa.Service, userService user.Service) *MFA {
+	return &MFA{mfaService, userService}
+}
+
+// MFA completes the second factor challenge of users that signed in with a password
+type MFA struct {
+	mfaService  mfa.Service
+	userService user.Service
+}
+
+func (c *MFA) Name() string {
+	return authn.ClientMFA
+}
+
+func (c *MFA) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	cmd := mfa.VerifyChallengeCommand{}
+	if err := web.Bind(r.HTTPRequest, &cmd); err != nil {
+		return nil, errBadMFAForm.Errorf("failed to parse request: %w", err)
+	}
+	cmd.IPAddress = web.RemoteAddr(r.HTTPRequest)
+
+	result, err := c.mfaService.VerifyChallenge(ctx, &cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.RecoveryCodes) > 0 {
+		r.SetMeta(authn.MetaKeyRecoveryCodes, strings.Join(result.RecoveryCodes, ","))
+	}
+
+	signedInUser, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{OrgID: r.OrgID, UserID: result.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceUser, signedInUser.UserID), signedInUser, authn.ClientParams{SyncPermissions: true}), nil
+}
@@ -0,0 +1,87 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestMFA_Authenticate(t *testing.T) {
+	type testCase struct {
+		desc                  string
+		body                  string
+		result                *mfa.VerifyChallengeResult
+		verifyErr             error
+		expectedErr           error
+		expectedIdentity      *authn.Identity
+		expectedRecoveryCodes string
+	}
+
+	tests := []testCase{
+		{
+			desc:             "should return identity of user that completed the challenge",
+			body:             `{"challenge": "token", "code": "123456"}`,
+			result:           &mfa.VerifyChallengeResult{UserID: 1},
+			expectedIdentity: &authn.Identity{ID: "user:1", OrgID: 1},
+		},
+		{
+			desc:                  "should return recovery codes when user enrolled during the challenge",
+			body:                  `{"challenge": "token", "code": "123456"}`,
+			result:                &mfa.VerifyChallengeResult{UserID: 1, RecoveryCodes: []string{"aaaaa-aaaaa", "bbbbb-bbbbb"}},
+			expectedIdentity:      &authn.Identity{ID: "user:1", OrgID: 1},
+			expectedRecoveryCodes: "aaaaa-aaaaa,bbbbb-bbbbb",
+		},
+		{
+			desc:        "should fail when challenge is not verified",
+			body:        `{"challenge": "token", "code": "000000"}`,
+			verifyErr:   mfa.ErrInvalidCode.Errorf("invalid"),
+			expectedErr: mfa.ErrInvalidCode,
+		},
+		{
+			desc:        "should fail without challenge token",
+			body:        `{"code": "123456"}`,
+			expectedErr: errBadMFAForm,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mfaService := &mfatest.FakeService{ExpectedVerifyResult: tt.result, ExpectedErr: tt.verifyErr}
+			c := ProvideMFA(
+				mfaService,
+				&usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 1, OrgID: 1}},
+			)
+
+			r := &authn.Request{HTTPRequest: &http.Request{
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+				RemoteAddr: "192.168.1.10:3000",
+			}}
+			identity, err := c.Authenticate(context.Background(), r)
+			if mfaService.VerifyCommand != nil {
+				// attempts are recorded with the address of the client, for its lockout
+				assert.Equal(t, "192.168.1.10", mfaService.VerifyCommand.IPAddress)
+			}
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, identity)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIdentity.ID, identity.ID)
+			assert.Equal(t, tt.expectedIdentity.OrgID, identity.OrgID)
+			assert.Equal(t, tt.expectedRecoveryCodes, r.GetMeta(authn.MetaKeyRecoveryCodes))
+		})
+	}
+}
@@ -28,10 +28,12 @@ const (
 	codeChallengeParamName       = "code_challenge"
 	codeChallengeMethodParamName = "code_challenge_method"
 	codeChallengeMethod          = "S256"
+	nonceParamName               = "nonce"
 
 	oauthStateQueryName  = "state"
 	oauthStateCookieName = "oauth_state"
 	oauthPKCECookieName  = "oauth_code_verifier"
+	oauthNonceCookieName = "oauth_nonce"
 )
 
 var (
@@ -42,6 +44,10 @@ var (
 	errOAuthMissingState = errutil.NewBase(errutil.StatusBadRequest, "auth.oauth.state.missing", errutil.WithPublicMessage("Missing saved oauth state"))
 	errOAuthInvalidState = errutil.NewBase(errutil.StatusUnauthorized, "auth.oauth.state.invalid", errutil.WithPublicMessage("Provided state does not match stored state"))
 
+	errOAuthGenNonce     = errutil.NewBase(errutil.StatusInternal, "auth.oauth.nonce.internal", errutil.WithPublicMessage("An internal error occurred"))
+	errOAuthMissingNonce = errutil.NewBase(errutil.StatusBadRequest, "auth.oauth.nonce.missing", errutil.WithPublicMessage("Missing required nonce cookie"))
+	errOAuthInvalidToken = errutil.NewBase(errutil.StatusUnauthorized, "auth.oauth.idtoken.invalid", errutil.WithPublicMessage("Provider returned an invalid ID token"))
+
 	errOAuthTokenExchange = errutil.NewBase(errutil.StatusInternal, "auth.oauth.token.exchange", errutil.WithPublicMessage("Failed to get token from provider"))
 	errOAuthUserInfo      = errutil.NewBase(errutil.StatusInternal, "auth.oauth.userinfo.error")
 
@@ -116,6 +122,18 @@ func (c *OAuth) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 	}
 	token.TokenType = "Bearer"
 
+	// OpenID Connect providers must return an ID token issued for the nonce sent in the authorization request
+	if oidc, ok := c.connector.(social.OIDCConnector); ok {
+		nonceCookie, err := r.HTTPRequest.Cookie(oauthNonceCookieName)
+		if err != nil || nonceCookie.Value == "" {
+			return nil, errOAuthMissingNonce.Errorf("no nonce cookie found: %w", err)
+		}
+		nonce := hashOAuthState(nonceCookie.Value, c.cfg.SecretKey, c.oauthCfg.ClientSecret)
+		if _, err := oidc.VerifyIDToken(clientCtx, token, nonce); err != nil {
+			return nil, errOAuthInvalidToken.Errorf("failed to verify id token: %w", err)
+		}
+	}
+
 	userInfo, err := c.connector.UserInfo(c.connector.Client(clientCtx, token), token)
 	if err != nil {
 		var sErr *social.Error
@@ -184,6 +202,18 @@ func (c *OAuth) RedirectURL(ctx context.Context, r *authn.Request) (*authn.Redir
 		)
 	}
 
+	// the nonce cookie holds the random value and the hashed value is sent to the provider
+	var plainNonce string
+	if _, ok := c.connector.(social.OIDCConnector); ok {
+		nonce, hashedNonce, err := genOAuthState(c.cfg.SecretKey, c.oauthCfg.ClientSecret)
+		if err != nil {
+			return nil, errOAuthGenNonce.Errorf("failed to generate nonce: %w", err)
+		}
+
+		plainNonce = nonce
+		opts = append(opts, oauth2.SetAuthURLParam(nonceParamName, hashedNonce))
+	}
+
 	state, hashedSate, err := genOAuthState(c.cfg.SecretKey, c.oauthCfg.ClientSecret)
 	if err != nil {
 		return nil, errOAuthGenState.Errorf("failed to generate state: %w", err)
@@ -194,6 +224,7 @@ func (c *OAuth) RedirectURL(ctx context.Context, r *authn.Request) (*authn.Redir
 		Extra: map[string]string{
 			authn.KeyOAuthState: hashedSate,
 			authn.KeyOAuthPKCE:  plainPKCE,
+			authn.KeyOAuthNonce: plainNonce,
 		},
 	}, nil
 }
@@ -43,6 +43,16 @@ func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, u
 		return nil, errLoginAttemptBlocked.Errorf("too many consecutive incorrect login attempts for user - login for user temporarily blocked")
 	}
 
+	if r.HTTPRequest != nil {
+		ok, err = c.loginAttempts.ValidateIPAddress(ctx, web.RemoteAddr(r.HTTPRequest))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errLoginAttemptBlocked.Errorf("too many incorrect login attempts from ip address - login temporarily blocked")
+		}
+	}
+
 	if len(password) == 0 {
 		return nil, errEmptyPassword.Errorf("no password provided")
 	}
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,7 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 		password         string
 		req              *authn.Request
 		blockLogin       bool
+		blockIP          bool
 		clients          []authn.PasswordClient
 		expectedErr      error
 		expectedIdentity *authn.Identity
@@ -55,6 +57,14 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 			blockLogin:  true,
 			expectedErr: errLoginAttemptBlocked,
 		},
+		{
+			desc:        "should fail if login is blocked for ip address",
+			username:    "test",
+			password:    "test",
+			req:         &authn.Request{HTTPRequest: &http.Request{RemoteAddr: "192.168.1.10:3000"}},
+			blockIP:     true,
+			expectedErr: errLoginAttemptBlocked,
+		},
 		{
 			desc:        "should fail when not found in any clients",
 			username:    "test",
@@ -67,7 +77,7 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvidePassword(loginattempttest.FakeLoginAttemptService{ExpectedValid: !tt.blockLogin}, tt.clients...)
+			c := ProvidePassword(loginattempttest.FakeLoginAttemptService{ExpectedValid: !tt.blockLogin, ExpectedIPBlocked: tt.blockIP}, tt.clients...)
 
 			identity, err := c.AuthenticatePassword(context.Background(), tt.req, tt.username, tt.password)
 			if tt.expectedErr != nil {
@@ -0,0 +1,134 @@
+package caching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/textproto"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// Query fields that change on every request without changing the results
+var volatileQueryFields = []string{"requestId", "queryCachingTTL"}
+
+// Forwarded headers that make responses specific to the signed in user
+var identityHeaders = []string{"Authorization", "X-Id-Token", "Cookie"}
+
+type queryKey struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	IntervalMS    int64           `json:"intervalMs"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	JSON          json.RawMessage `json:"json"`
+}
+
+type requestKey struct {
+	Kind       string     `json:"kind"`
+	OrgID      int64      `json:"orgId"`
+	PluginID   string     `json:"pluginId"`
+	DataSource string     `json:"datasource"`
+	Updated    int64      `json:"updated"`
+	Scope      string     `json:"scope"`
+	Queries    []queryKey `json:"queries,omitempty"`
+	Method     string     `json:"method,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Body       []byte     `json:"body,omitempty"`
+}
+
+// queryCacheKey returns the cache key of a query request. Time ranges are part of the key as they are, results for
+// other ranges, even overlapping ones, are never returned.
+func queryCacheKey(req *backend.QueryDataRequest) (string, error) {
+	key := newRequestKey("query", req.PluginContext, req.Headers)
+	for _, q := range req.Queries {
+		normalized, err := normalizeQueryJSON(q.JSON)
+		if err != nil {
+			return "", err
+		}
+		key.Queries = append(key.Queries, queryKey{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			IntervalMS:    q.Interval.Milliseconds(),
+			From:          q.TimeRange.From.UnixMilli(),
+			To:            q.TimeRange.To.UnixMilli(),
+			JSON:          normalized,
+		})
+	}
+	return hashKey(key)
+}
+
+// resourceCacheKey returns the cache key of a resource request
+func resourceCacheKey(req *backend.CallResourceRequest) (string, error) {
+	headers := make(map[string]string, len(req.Headers))
+	for name, values := range req.Headers {
+		headers[name] = strings.Join(values, ",")
+	}
+
+	key := newRequestKey("resource", req.PluginContext, headers)
+	key.Method = req.Method
+	key.URL = req.URL
+	key.Body = req.Body
+	return hashKey(key)
+}
+
+func newRequestKey(kind string, pCtx backend.PluginContext, headers map[string]string) requestKey {
+	key := requestKey{
+		Kind:     kind,
+		OrgID:    pCtx.OrgID,
+		PluginID: pCtx.PluginID,
+		Scope:    requestScope(pCtx, headers),
+	}
+	if ds := pCtx.DataSourceInstanceSettings; ds != nil {
+		key.DataSource = ds.UID
+		// editing the data source invalidates its cached responses
+		key.Updated = ds.Updated.UnixMilli()
+	}
+	return key
+}
+
+// requestScope returns who can share a cached response. Responses are shared by the whole org, unless the request
+// forwards the identity of the user to the data source, in which case the data source may return different results
+// per user.
+func requestScope(pCtx backend.PluginContext, headers map[string]string) string {
+	for name := range headers {
+		canonical := textproto.CanonicalMIMEHeaderKey(strings.TrimPrefix(name, "http_"))
+		for _, identityHeader := range identityHeaders {
+			if canonical == identityHeader {
+				if pCtx.User == nil {
+					return "user:"
+				}
+				return "user:" + pCtx.User.Login
+			}
+		}
+	}
+	return "org"
+}
+
+// normalizeQueryJSON drops volatile fields and sorts keys, so equal queries get equal keys
+func normalizeQueryJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+
+	var query map[string]interface{}
+	if err := json.Unmarshal(raw, &query); err != nil {
+		return nil, err
+	}
+	for _, field := range volatileQueryFields {
+		delete(query, field)
+	}
+	return json.Marshal(query)
+}
+
+func hashKey(key requestKey) (string, error) {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return key.Kind + ":" + hex.EncodeToString(sum[:]), nil
+}
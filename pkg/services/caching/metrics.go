@@ -0,0 +1,49 @@
+package caching
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "grafana"
+	metricsSubSystem = "caching"
+)
+
+type metrics struct {
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+	errors *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "hits_total",
+			Help:      "Number of query and resource requests answered from the cache",
+		}, []string{"kind", "plugin_id"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "misses_total",
+			Help:      "Number of cacheable query and resource requests not found in the cache",
+		}, []string{"kind", "plugin_id"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "errors_total",
+			Help:      "Number of failed cache reads and writes",
+		}, []string{"kind", "operation"}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(
+			m.hits,
+			m.misses,
+			m.errors,
+		)
+	}
+
+	return m
+}
@@ -2,8 +2,19 @@ package caching
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
@@ -35,8 +46,28 @@ type CachedResourceDataResponse struct {
 	UpdateCacheFn CacheResourceResponseFn
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
+func ProvideCachingService(cfg *setting.Cfg, remoteCache remotecache.CacheStorage, reg prometheus.Registerer) *OSSCachingService {
+	return newCachingService(cfg.QueryCaching, remoteCache, reg)
+}
+
+func newCachingService(settings setting.QueryCachingSettings, remoteCache remotecache.CacheStorage, reg prometheus.Registerer) *OSSCachingService {
+	s := &OSSCachingService{
+		settings: settings,
+		log:      log.New("caching"),
+		metrics:  newMetrics(reg),
+	}
+
+	if !settings.Enabled {
+		return s
+	}
+
+	if settings.Backend == setting.QueryCachingBackendRemote {
+		s.storage = &remoteStorage{cache: remoteCache}
+	} else {
+		s.storage = newMemoryStorage(settings.MaxItems)
+	}
+
+	return s
 }
 
 type CachingService interface {
@@ -49,16 +80,183 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
+// OSSCachingService caches query and resource responses in memory or in the remote cache.
+// The zero value never caches anything.
 type OSSCachingService struct {
+	settings setting.QueryCachingSettings
+	storage  storage
+	log      log.Logger
+	metrics  *metrics
 }
 
 func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
+	if s.storage == nil {
+		return false, CachedQueryDataResponse{}
+	}
+
+	ds := req.PluginContext.DataSourceInstanceSettings
+	if ds == nil {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedQueryDataResponse{}
+	}
+
+	ttl := s.dataSourceTTL(ds.UID, s.settings.TTL)
+	if ttl <= 0 {
+		setCacheStatus(ctx, StatusDisabled)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req)
+	if err != nil {
+		s.log.Debug("Failed to create query cache key", "datasource", ds.UID, "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	if cached, ok := s.get(ctx, "query", key); ok {
+		resp := &backend.QueryDataResponse{}
+		if err := json.Unmarshal(cached, resp); err == nil {
+			s.metrics.hits.WithLabelValues("query", req.PluginContext.PluginID).Inc()
+			setCacheStatus(ctx, StatusHit)
+			return true, CachedQueryDataResponse{Response: resp}
+		}
+		s.metrics.errors.WithLabelValues("query", "decode").Inc()
+	}
+
+	s.metrics.misses.WithLabelValues("query", req.PluginContext.PluginID).Inc()
+	setCacheStatus(ctx, StatusMiss)
+
+	return false, CachedQueryDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.QueryDataResponse) {
+			if !isCacheableQueryResponse(resp) {
+				return
+			}
+			value, err := json.Marshal(resp)
+			if err != nil {
+				s.metrics.errors.WithLabelValues("query", "encode").Inc()
+				return
+			}
+			s.set(ctx, "query", key, value, ttl)
+		},
+	}
 }
 
 func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
+	if s.storage == nil || s.settings.ResourceTTL <= 0 {
+		return false, CachedResourceDataResponse{}
+	}
+
+	if req.Method != http.MethodGet {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	ttl := s.settings.ResourceTTL
+	if ds := req.PluginContext.DataSourceInstanceSettings; ds != nil {
+		ttl = s.dataSourceTTL(ds.UID, ttl)
+		if ttl <= 0 {
+			setCacheStatus(ctx, StatusDisabled)
+			return false, CachedResourceDataResponse{}
+		}
+		if ttl > s.settings.ResourceTTL {
+			ttl = s.settings.ResourceTTL
+		}
+	}
+
+	key, err := resourceCacheKey(req)
+	if err != nil {
+		s.log.Debug("Failed to create resource cache key", "plugin", req.PluginContext.PluginID, "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	if cached, ok := s.get(ctx, "resource", key); ok {
+		resp := &backend.CallResourceResponse{}
+		if err := json.Unmarshal(cached, resp); err == nil {
+			s.metrics.hits.WithLabelValues("resource", req.PluginContext.PluginID).Inc()
+			setCacheStatus(ctx, StatusHit)
+			return true, CachedResourceDataResponse{Response: resp}
+		}
+		s.metrics.errors.WithLabelValues("resource", "decode").Inc()
+	}
+
+	s.metrics.misses.WithLabelValues("resource", req.PluginContext.PluginID).Inc()
+	setCacheStatus(ctx, StatusMiss)
+
+	var calls int32
+	return false, CachedResourceDataResponse{
+		UpdateCacheFn: func(ctx context.Context, resp *backend.CallResourceResponse) {
+			// streamed responses are sent in several parts, only single responses can be replayed from the cache
+			if atomic.AddInt32(&calls, 1) > 1 {
+				if err := s.storage.Delete(ctx, key); err != nil {
+					s.metrics.errors.WithLabelValues("resource", "delete").Inc()
+				}
+				return
+			}
+			if resp == nil || resp.Status < http.StatusOK || resp.Status >= http.StatusMultipleChoices {
+				return
+			}
+			value, err := json.Marshal(resp)
+			if err != nil {
+				s.metrics.errors.WithLabelValues("resource", "encode").Inc()
+				return
+			}
+			s.set(ctx, "resource", key, value, ttl)
+		},
+	}
+}
+
+// dataSourceTTL returns the TTL configured for the data source, or the default
+func (s *OSSCachingService) dataSourceTTL(uid string, defaultTTL time.Duration) time.Duration {
+	if ttl, ok := s.settings.DataSourceTTLs[uid]; ok {
+		return ttl
+	}
+	return defaultTTL
+}
+
+func (s *OSSCachingService) get(ctx context.Context, kind string, key string) ([]byte, bool) {
+	value, err := s.storage.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, errCacheMiss) {
+			s.log.Warn("Failed to read from the cache", "kind", kind, "error", err)
+			s.metrics.errors.WithLabelValues(kind, "get").Inc()
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (s *OSSCachingService) set(ctx context.Context, kind string, key string, value []byte, ttl time.Duration) {
+	if s.settings.MaxValueSize > 0 && len(value) > s.settings.MaxValueSize {
+		s.log.Debug("Response is too big to be cached", "kind", kind, "size", len(value))
+		return
+	}
+	if err := s.storage.Set(ctx, key, value, ttl); err != nil {
+		s.log.Warn("Failed to write to the cache", "kind", kind, "error", err)
+		s.metrics.errors.WithLabelValues(kind, "set").Inc()
+	}
+}
+
+// isCacheableQueryResponse returns false for responses with errors, which may succeed when retried
+func isCacheableQueryResponse(resp *backend.QueryDataResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// setCacheStatus reports the cache status of the request in the X-Cache response header
+func setCacheStatus(ctx context.Context, status string) {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || reqCtx.Resp == nil {
+		return
+	}
+	reqCtx.Resp.Header().Set(XCacheHeader, status)
 }
 
 var _ CachingService = &OSSCachingService{}
@@ -0,0 +1,218 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func newTestSettings() setting.QueryCachingSettings {
+	return setting.QueryCachingSettings{
+		Enabled:        true,
+		Backend:        setting.QueryCachingBackendMemory,
+		TTL:            5 * time.Minute,
+		ResourceTTL:    time.Minute,
+		MaxItems:       10,
+		DataSourceTTLs: map[string]time.Duration{"disabled": 0},
+	}
+}
+
+func newTestContext(t *testing.T) (context.Context, *contextmodel.ReqContext) {
+	t.Helper()
+	reqCtx := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Resp: web.NewResponseWriter(http.MethodPost, httptest.NewRecorder()),
+		},
+	}
+	return ctxkey.Set(context.Background(), reqCtx), reqCtx
+}
+
+func newQueryRequest(dsUID string, from time.Time, expr string) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			OrgID:    1,
+			PluginID: "prometheus",
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				UID:  dsUID,
+				Type: "prometheus",
+			},
+			User: &backend.User{Login: "viewer"},
+		},
+		Queries: []backend.DataQuery{
+			{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+				JSON:      json.RawMessage(`{"refId":"A","expr":"` + expr + `","requestId":"` + from.String() + `"}`),
+			},
+		},
+	}
+}
+
+func TestHandleQueryRequest(t *testing.T) {
+	from := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("caches query responses", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		s := newCachingService(newTestSettings(), nil, reg)
+
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		require.False(t, hit)
+		require.NotNil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusMiss, reqCtx.Resp.Header().Get(XCacheHeader))
+
+		resp := backend.NewQueryDataResponse()
+		resp.Responses["A"] = backend.DataResponse{Frames: data.Frames{data.NewFrame("up", data.NewField("value", nil, []float64{1}))}}
+		cr.UpdateCacheFn(ctx, resp)
+
+		ctx, reqCtx = newTestContext(t)
+		hit, cr = s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, reqCtx.Resp.Header().Get(XCacheHeader))
+		require.Contains(t, cr.Response.Responses, "A")
+		assert.Equal(t, "up", cr.Response.Responses["A"].Frames[0].Name)
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.hits.WithLabelValues("query", "prometheus")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.metrics.misses.WithLabelValues("query", "prometheus")))
+	})
+
+	t.Run("misses when the query, time range or data source changes", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		cr.UpdateCacheFn(ctx, backend.NewQueryDataResponse())
+
+		for _, req := range []*backend.QueryDataRequest{
+			newQueryRequest("prom", from, "down"),
+			newQueryRequest("prom", from.Add(time.Second), "up"),
+			newQueryRequest("other", from, "up"),
+		} {
+			hit, _ := s.HandleQueryRequest(ctx, req)
+			assert.False(t, hit)
+		}
+	})
+
+	t.Run("keys responses by user when the identity is forwarded", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, _ := newTestContext(t)
+
+		req := newQueryRequest("prom", from, "up")
+		req.Headers = map[string]string{"Authorization": "Bearer viewer"}
+		_, cr := s.HandleQueryRequest(ctx, req)
+		cr.UpdateCacheFn(ctx, backend.NewQueryDataResponse())
+
+		req = newQueryRequest("prom", from, "up")
+		req.Headers = map[string]string{"Authorization": "Bearer admin"}
+		req.PluginContext.User = &backend.User{Login: "admin"}
+		hit, _ := s.HandleQueryRequest(ctx, req)
+		assert.False(t, hit)
+	})
+
+	t.Run("does not cache responses with errors", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+
+		resp := backend.NewQueryDataResponse()
+		resp.Responses["A"] = backend.DataResponse{Error: assert.AnError}
+		cr.UpdateCacheFn(ctx, resp)
+
+		hit, _ := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		assert.False(t, hit)
+	})
+
+	t.Run("is disabled per data source", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest("disabled", from, "up"))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusDisabled, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("does nothing when caching is disabled", func(t *testing.T) {
+		settings := newTestSettings()
+		settings.Enabled = false
+		s := newCachingService(settings, nil, nil)
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Empty(t, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("stores responses in the remote cache", func(t *testing.T) {
+		settings := newTestSettings()
+		settings.Backend = setting.QueryCachingBackendRemote
+		s := newCachingService(settings, remotecache.NewFakeStore(t), nil)
+
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		cr.UpdateCacheFn(ctx, backend.NewQueryDataResponse())
+
+		hit, _ := s.HandleQueryRequest(ctx, newQueryRequest("prom", from, "up"))
+		assert.True(t, hit)
+	})
+}
+
+func TestHandleResourceRequest(t *testing.T) {
+	newResourceRequest := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				PluginID:                   "prometheus",
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "prom", Type: "prometheus"},
+			},
+			Method: method,
+			URL:    "/api/v1/labels",
+		}
+	}
+
+	t.Run("caches single successful responses", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, _ := newTestContext(t)
+		hit, cr := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		require.False(t, hit)
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`["job"]`)})
+
+		hit, cr = s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		require.True(t, hit)
+		assert.Equal(t, []byte(`["job"]`), cr.Response.Body)
+	})
+
+	t.Run("does not cache streamed responses", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, _ := newTestContext(t)
+		_, cr := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`part 1`)})
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`part 2`)})
+
+		hit, _ := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodGet))
+		assert.False(t, hit)
+	})
+
+	t.Run("bypasses requests that are not GET", func(t *testing.T) {
+		s := newCachingService(newTestSettings(), nil, nil)
+		ctx, reqCtx := newTestContext(t)
+		hit, cr := s.HandleResourceRequest(ctx, newResourceRequest(http.MethodPost))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, reqCtx.Resp.Header().Get(XCacheHeader))
+	})
+}
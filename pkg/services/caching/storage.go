@@ -0,0 +1,120 @@
+package caching
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+var errCacheMiss = errors.New("cache miss")
+
+// storage stores encoded responses. Get returns errCacheMiss when there is no unexpired value for the key.
+type storage interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// memoryStorage is an LRU cache of at most maxItems values, expired values are removed on read
+type memoryStorage struct {
+	mu       sync.Mutex
+	maxItems int
+	items    map[string]*list.Element
+	// most recently used values are at the front
+	order *list.List
+	now   func() time.Time
+}
+
+type memoryItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func newMemoryStorage(maxItems int) *memoryStorage {
+	return &memoryStorage{
+		maxItems: maxItems,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (s *memoryStorage) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, errCacheMiss
+	}
+
+	item := el.Value.(*memoryItem)
+	if !s.now().Before(item.expires) {
+		s.remove(el)
+		return nil, errCacheMiss
+	}
+
+	s.order.MoveToFront(el)
+	return item.value, nil
+}
+
+func (s *memoryStorage) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := s.now().Add(ttl)
+	if el, ok := s.items[key]; ok {
+		item := el.Value.(*memoryItem)
+		item.value = value
+		item.expires = expires
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&memoryItem{key: key, value: value, expires: expires})
+	for s.order.Len() > s.maxItems {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+func (s *memoryStorage) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.items, el.Value.(*memoryItem).key)
+}
+
+// remoteStorage stores values in the configured remote cache (database, redis or memcached)
+type remoteStorage struct {
+	cache remotecache.CacheStorage
+}
+
+func (s *remoteStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.cache.Get(ctx, key)
+	if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		return nil, errCacheMiss
+	}
+	return value, err
+}
+
+func (s *remoteStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.cache.Set(ctx, key, value, ttl)
+}
+
+func (s *remoteStorage) Delete(ctx context.Context, key string) error {
+	return s.cache.Delete(ctx, key)
+}
@@ -0,0 +1,54 @@
+package caching
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("evicts the least recently used values", func(t *testing.T) {
+		s := newMemoryStorage(2)
+		require.NoError(t, s.Set(ctx, "a", []byte("a"), time.Minute))
+		require.NoError(t, s.Set(ctx, "b", []byte("b"), time.Minute))
+
+		_, err := s.Get(ctx, "a")
+		require.NoError(t, err)
+		require.NoError(t, s.Set(ctx, "c", []byte("c"), time.Minute))
+
+		_, err = s.Get(ctx, "b")
+		assert.ErrorIs(t, err, errCacheMiss)
+		value, err := s.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("a"), value)
+		value, err = s.Get(ctx, "c")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("c"), value)
+	})
+
+	t.Run("expires values", func(t *testing.T) {
+		now := time.Now()
+		s := newMemoryStorage(2)
+		s.now = func() time.Time { return now }
+		require.NoError(t, s.Set(ctx, "a", []byte("a"), time.Minute))
+
+		now = now.Add(time.Minute)
+		_, err := s.Get(ctx, "a")
+		assert.ErrorIs(t, err, errCacheMiss)
+		assert.Equal(t, 0, s.order.Len())
+	})
+
+	t.Run("deletes values", func(t *testing.T) {
+		s := newMemoryStorage(2)
+		require.NoError(t, s.Set(ctx, "a", []byte("a"), time.Minute))
+		require.NoError(t, s.Delete(ctx, "a"))
+
+		_, err := s.Get(ctx, "a")
+		assert.ErrorIs(t, err, errCacheMiss)
+	})
+}
@@ -16,7 +16,9 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
@@ -29,7 +31,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	roleAssignmentService accesscontrol.RoleAssignmentService, dashboardService dashboards.DashboardService) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,6 +46,8 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		roleAssignmentService:     roleAssignmentService,
+		dashboardService:          dashboardService,
 	}
 	return s
 }
@@ -60,6 +65,8 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	roleAssignmentService     accesscontrol.RoleAssignmentService
+	dashboardService          dashboards.DashboardService
 }
 
 type cleanUpJob struct {
@@ -102,6 +109,8 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"delete expired role assignments", srv.deleteExpiredRoleAssignments},
+		{"delete trashed dashboards", srv.deleteTrashedDashboards},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -279,3 +288,22 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) deleteExpiredRoleAssignments(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if rowsAffected, err := srv.roleAssignmentService.DeleteExpiredRoleAssignments(ctx); err != nil {
+		logger.Error("Failed to delete expired role assignments", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired role assignments", "rows affected", rowsAffected)
+	}
+}
+
+func (srv *CleanUpService) deleteTrashedDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	cmd := dashboards.PurgeTrashedDashboardsCommand{DeletedBefore: time.Now().Add(-srv.Cfg.DashboardTrashRetention)}
+	if rowsAffected, err := srv.dashboardService.PurgeTrashedDashboards(ctx, &cmd); err != nil {
+		logger.Error("Failed to delete trashed dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Deleted trashed dashboards", "rows affected", rowsAffected)
+	}
+}
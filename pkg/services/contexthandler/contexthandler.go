@@ -358,13 +358,14 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *contextmodel.ReqConte
 	}
 
 	// non-blocking update api_key last used date
+	ip := reqContext.RemoteAddr()
 	go func(id int64) {
 		defer func() {
 			if err := recover(); err != nil {
 				reqContext.Logger.Error("api key authentication panic", "err", err)
 			}
 		}()
-		if err := h.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), id); err != nil {
+		if err := h.apiKeyService.UpdateAPIKeyLastUsed(context.Background(), id, ip); err != nil {
 			reqContext.Logger.Warn("failed to update last use date for api key", "id", id)
 		}
 	}(apiKey.ID)
@@ -2,6 +2,7 @@ package correlations
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -22,6 +23,7 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 	s.RouteRegister.Group("/api/datasources/uid/:uid/correlations", func(entities routing.RouteRegister) {
 		entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationsBySourceUIDHandler))
 		entities.Post("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.createHandler))
+		entities.Get("/export", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(s.exportHandler))
 
 		entities.Group("/:correlationUID", func(entities routing.RouteRegister) {
 			entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationHandler))
@@ -174,6 +176,10 @@ func (s *CorrelationsService) updateHandler(c *contextmodel.ReqContext) response
 			return response.Error(http.StatusForbidden, "Data source is read only", err)
 		}
 
+		if errors.Is(err, ErrInvalidCorrelationConfig) {
+			return response.Error(http.StatusBadRequest, "Invalid correlation config", err)
+		}
+
 		return response.Error(http.StatusInternalServerError, "Failed to update correlation", err)
 	}
 
@@ -319,3 +325,56 @@ type GetCorrelationsResponse struct {
 	// in: body
 	Body []Correlation `json:"body"`
 }
+
+// swagger:route GET /datasources/uid/{sourceUID}/correlations/export correlations exportCorrelations
+//
+// Exports the correlations originating from the given data source as a data source provisioning file.
+//
+// Produces:
+// - text/yaml
+// - application/yaml
+//
+// Responses:
+// 200: exportCorrelationsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) exportHandler(c *contextmodel.ReqContext) response.Response {
+	query := GetCorrelationsBySourceUIDQuery{
+		SourceUID: web.Params(c.Req)[":uid"],
+		OrgId:     c.OrgID,
+	}
+
+	export, err := s.exportCorrelations(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
+			return response.Error(http.StatusNotFound, "Source data source not found", err)
+		}
+
+		return response.Error(http.StatusInternalServerError, "Failed to export correlations", err)
+	}
+
+	if c.QueryBoolWithDefault("download", false) {
+		return response.YAMLDownload(http.StatusOK, export, fmt.Sprintf("correlations-%s.yaml", query.SourceUID))
+	}
+	return response.YAML(http.StatusOK, export)
+}
+
+// swagger:parameters exportCorrelations
+type ExportCorrelationsParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// Whether to initiate a download of the file or not.
+	// in: query
+	// required: false
+	// default: false
+	Download bool `json:"download"`
+}
+
+//swagger:response exportCorrelationsResponse
+type ExportCorrelationsResponse struct {
+	// in: body
+	Body ProvisioningExport `json:"body"`
+}
@@ -2,6 +2,7 @@ package correlations
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -9,6 +10,10 @@ import (
 	"github.com/grafana/grafana/pkg/util"
 )
 
+// Correlations without a target data source, like external links, are joined with no target. Correlations with a
+// target are only returned when the target belongs to the same org.
+const targetInOrgCondition = "(correlation.target_uid IS NULL OR dst.uid IS NOT NULL)"
+
 // createCorrelation adds a correlation
 func (s CorrelationsService) createCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, error) {
 	correlation := Correlation{
@@ -133,6 +138,13 @@ func (s CorrelationsService) updateCorrelation(ctx context.Context, cmd UpdateCo
 			if cmd.Config.Transformations != nil {
 				correlation.Config.Transformations = cmd.Config.Transformations
 			}
+
+			if err := correlation.Config.Validate(); err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidCorrelationConfig, err)
+			}
+			if correlation.Config.Type == ConfigTypeQuery && correlation.TargetUID == nil {
+				return fmt.Errorf("%w: correlations of type \"%s\" must have a targetUID", ErrInvalidCorrelationConfig, ConfigTypeQuery)
+			}
 		}
 
 		updateCount, err := session.Where("uid = ? AND source_uid = ?", correlation.UID, correlation.SourceUID).Limit(1).Update(correlation)
@@ -164,7 +176,7 @@ func (s CorrelationsService) getCorrelation(ctx context.Context, cmd GetCorrelat
 			return ErrSourceDataSourceDoesNotExists
 		}
 
-		found, err := session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where("correlation.uid = ? AND correlation.source_uid = ?", correlation.UID, correlation.SourceUID).Get(&correlation)
+		found, err := session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where("correlation.uid = ? AND correlation.source_uid = ?", correlation.UID, correlation.SourceUID).And(targetInOrgCondition).Get(&correlation)
 		if !found {
 			return ErrCorrelationNotFound
 		}
@@ -215,7 +227,7 @@ func (s CorrelationsService) getCorrelationsBySourceUID(ctx context.Context, cmd
 			return ErrSourceDataSourceDoesNotExists
 		}
 
-		return session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where("correlation.source_uid = ?", cmd.SourceUID).Find(&correlations)
+		return session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where("correlation.source_uid = ?", cmd.SourceUID).And(targetInOrgCondition).Find(&correlations)
 	})
 
 	if err != nil {
@@ -229,7 +241,7 @@ func (s CorrelationsService) getCorrelations(ctx context.Context, cmd GetCorrela
 	correlations := make([]Correlation, 0)
 
 	err := s.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
-		return session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Find(&correlations)
+		return session.Select("correlation.*").Join("", "data_source AS dss", "correlation.source_uid = dss.uid and dss.org_id = ?", cmd.OrgId).Join("LEFT", "data_source AS dst", "correlation.target_uid = dst.uid and dst.org_id = ?", cmd.OrgId).Where(targetInOrgCondition).Find(&correlations)
 	})
 	if err != nil {
 		return []Correlation{}, err
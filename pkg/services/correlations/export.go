@@ -0,0 +1,145 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// provisioningAPIVersion is the version of the data source provisioning files correlations are exported to
+const provisioningAPIVersion = 1
+
+// ProvisioningExport mirrors the data source provisioning file format, so exported correlations can be
+// provisioned again as they are
+type ProvisioningExport struct {
+	APIVersion  int64                          `yaml:"apiVersion"`
+	Datasources []ProvisioningDataSourceExport `yaml:"datasources"`
+}
+
+// ProvisioningDataSourceExport is a provisioned data source. Secure settings are never exported.
+type ProvisioningDataSourceExport struct {
+	OrgID           int64                           `yaml:"orgId"`
+	Version         int                             `yaml:"version,omitempty"`
+	Name            string                          `yaml:"name"`
+	Type            string                          `yaml:"type"`
+	Access          string                          `yaml:"access,omitempty"`
+	URL             string                          `yaml:"url,omitempty"`
+	User            string                          `yaml:"user,omitempty"`
+	Database        string                          `yaml:"database,omitempty"`
+	BasicAuth       bool                            `yaml:"basicAuth,omitempty"`
+	BasicAuthUser   string                          `yaml:"basicAuthUser,omitempty"`
+	WithCredentials bool                            `yaml:"withCredentials,omitempty"`
+	IsDefault       bool                            `yaml:"isDefault"`
+	JSONData        map[string]interface{}          `yaml:"jsonData,omitempty"`
+	Editable        bool                            `yaml:"editable"`
+	UID             string                          `yaml:"uid"`
+	Correlations    []ProvisioningCorrelationExport `yaml:"correlations"`
+}
+
+type ProvisioningCorrelationExport struct {
+	TargetUID   *string                `yaml:"targetUID,omitempty"`
+	Label       string                 `yaml:"label"`
+	Description string                 `yaml:"description"`
+	Config      map[string]interface{} `yaml:"config"`
+}
+
+func (s CorrelationsService) exportCorrelations(ctx context.Context, cmd GetCorrelationsBySourceUIDQuery) (ProvisioningExport, error) {
+	dataSource, err := s.DataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{
+		OrgID: cmd.OrgId,
+		UID:   cmd.SourceUID,
+	})
+	if err != nil {
+		return ProvisioningExport{}, ErrSourceDataSourceDoesNotExists
+	}
+
+	correlations, err := s.getCorrelationsBySourceUID(ctx, cmd)
+	if err != nil {
+		return ProvisioningExport{}, err
+	}
+
+	return newProvisioningExport(dataSource, correlations)
+}
+
+func newProvisioningExport(dataSource *datasources.DataSource, correlations []Correlation) (ProvisioningExport, error) {
+	ds := ProvisioningDataSourceExport{
+		OrgID:           dataSource.OrgID,
+		Version:         dataSource.Version,
+		Name:            dataSource.Name,
+		Type:            dataSource.Type,
+		Access:          string(dataSource.Access),
+		URL:             dataSource.URL,
+		User:            dataSource.User,
+		Database:        dataSource.Database,
+		BasicAuth:       dataSource.BasicAuth,
+		BasicAuthUser:   dataSource.BasicAuthUser,
+		WithCredentials: dataSource.WithCredentials,
+		IsDefault:       dataSource.IsDefault,
+		Editable:        !dataSource.ReadOnly,
+		UID:             dataSource.UID,
+		Correlations:    make([]ProvisioningCorrelationExport, 0, len(correlations)),
+	}
+	if dataSource.JsonData != nil {
+		ds.JSONData = dataSource.JsonData.MustMap()
+	}
+
+	for _, correlation := range correlations {
+		export, err := NewProvisioningCorrelationExport(correlation)
+		if err != nil {
+			return ProvisioningExport{}, err
+		}
+		ds.Correlations = append(ds.Correlations, export)
+	}
+
+	return ProvisioningExport{
+		APIVersion:  provisioningAPIVersion,
+		Datasources: []ProvisioningDataSourceExport{ds},
+	}, nil
+}
+
+// NewProvisioningCorrelationExport converts a correlation to the shape it has in data source provisioning files
+func NewProvisioningCorrelationExport(correlation Correlation) (ProvisioningCorrelationExport, error) {
+	config, err := configToMap(correlation.Config)
+	if err != nil {
+		return ProvisioningCorrelationExport{}, err
+	}
+
+	return ProvisioningCorrelationExport{
+		TargetUID:   correlation.TargetUID,
+		Label:       escapeProvisioningValues(correlation.Label).(string),
+		Description: escapeProvisioningValues(correlation.Description).(string),
+		Config:      config,
+	}, nil
+}
+
+// configToMap converts a config to the generic shape provisioning reads, using the same keys as the API
+func configToMap(config CorrelationConfig) (map[string]interface{}, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return escapeProvisioningValues(m).(map[string]interface{}), nil
+}
+
+// escapeProvisioningValues escapes '$' in strings, provisioning would otherwise expand ${variables} in
+// correlations, e.g. in queries and external urls, as environment variables
+func escapeProvisioningValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return strings.ReplaceAll(v, "$", "$$")
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = escapeProvisioningValues(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = escapeProvisioningValues(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,96 @@
+package correlations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestProvisioningExport(t *testing.T) {
+	targetUID := "tempo"
+	dataSource := &datasources.DataSource{
+		OrgID:          1,
+		Version:        3,
+		Name:           "Loki",
+		Type:           "loki",
+		Access:         datasources.DS_ACCESS_PROXY,
+		URL:            "http://loki:3100",
+		UID:            "loki",
+		JsonData:       simplejson.NewFromAny(map[string]interface{}{"maxLines": 1000}),
+		SecureJsonData: map[string][]byte{"password": []byte("secret")},
+	}
+	correlations := []Correlation{
+		{
+			UID:         "query",
+			SourceUID:   "loki",
+			TargetUID:   &targetUID,
+			Label:       "Trace",
+			Description: "Logs to traces",
+			Config: CorrelationConfig{
+				Field:  "traceId",
+				Type:   ConfigTypeQuery,
+				Target: map[string]interface{}{"query": "${traceId}"},
+				Transformations: Transformations{
+					{Type: "jsonpath", Field: "line", Expression: "$.traceId", MapValue: "traceId"},
+				},
+			},
+		},
+		{
+			UID:       "external",
+			SourceUID: "loki",
+			Label:     "Ticket",
+			Config: CorrelationConfig{
+				Field:  "ticket",
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://tickets.example.com/${ticket}"},
+			},
+		},
+	}
+
+	export, err := newProvisioningExport(dataSource, correlations)
+	require.NoError(t, err)
+
+	out, err := yaml.Marshal(export)
+	require.NoError(t, err)
+	require.NotContains(t, string(out), "secret")
+	require.YAMLEq(t, `
+apiVersion: 1
+datasources:
+  - orgId: 1
+    version: 3
+    name: Loki
+    type: loki
+    access: proxy
+    url: http://loki:3100
+    isDefault: false
+    jsonData:
+      maxLines: 1000
+    editable: true
+    uid: loki
+    correlations:
+      - targetUID: tempo
+        label: Trace
+        description: Logs to traces
+        config:
+          type: query
+          field: traceId
+          target:
+            query: $${traceId}
+          transformations:
+            - type: jsonpath
+              field: line
+              expression: $$.traceId
+              mapValue: traceId
+      - label: Ticket
+        description: ""
+        config:
+          type: external
+          field: ticket
+          target:
+            url: https://tickets.example.com/$${ticket}
+`, string(out))
+}
@@ -0,0 +1,105 @@
+package correlations
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// validateJSONPath checks the syntax of the JSONPath expressions supported by jsonpath transformations:
+// a root ($) followed by child (.name, ['name']), recursive descent (..name), wildcard (.*, [*]),
+// index ([0], [-1]) and slice ([0:2]) segments.
+// Filters and scripts are not supported.
+func validateJSONPath(expr string) error {
+	if !strings.HasPrefix(expr, "$") {
+		return errors.New("expression must start with $")
+	}
+
+	rest := expr[1:]
+	for rest != "" {
+		var err error
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			rest, err = parseJSONPathName(rest[2:])
+		case strings.HasPrefix(rest, "."):
+			rest, err = parseJSONPathName(rest[1:])
+		case strings.HasPrefix(rest, "["):
+			rest, err = parseJSONPathBracket(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected %q", rest)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseJSONPathName(s string) (string, error) {
+	if strings.HasPrefix(s, "*") {
+		return s[1:], nil
+	}
+
+	end := strings.IndexAny(s, ".[")
+	if end == -1 {
+		end = len(s)
+	}
+	if end == 0 {
+		return "", errors.New("missing member name")
+	}
+	for _, r := range s[:end] {
+		if r != '_' && r != '-' && r != '$' && !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') {
+			return "", fmt.Errorf("invalid character %q in member name", r)
+		}
+	}
+	return s[end:], nil
+}
+
+func parseJSONPathBracket(s string) (string, error) {
+	if strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`) {
+		quote := s[:1]
+		end := strings.Index(s[1:], quote)
+		if end == -1 {
+			return "", errors.New("unterminated quoted member name")
+		}
+		rest := s[end+2:]
+		if !strings.HasPrefix(rest, "]") {
+			return "", errors.New("missing ]")
+		}
+		return rest[1:], nil
+	}
+
+	end := strings.Index(s, "]")
+	if end == -1 {
+		return "", errors.New("missing ]")
+	}
+	selector := strings.TrimSpace(s[:end])
+	if selector == "*" {
+		return s[end+1:], nil
+	}
+
+	if strings.Contains(selector, ":") {
+		bounds := strings.Split(selector, ":")
+		if len(bounds) > 3 {
+			return "", fmt.Errorf("invalid slice %q", selector)
+		}
+		for _, bound := range bounds {
+			if bound = strings.TrimSpace(bound); bound == "" {
+				continue
+			}
+			if _, err := strconv.Atoi(bound); err != nil {
+				return "", fmt.Errorf("invalid slice %q", selector)
+			}
+		}
+		return s[end+1:], nil
+	}
+
+	for _, index := range strings.Split(selector, ",") {
+		if _, err := strconv.Atoi(strings.TrimSpace(index)); err != nil {
+			return "", fmt.Errorf("invalid index %q", selector)
+		}
+	}
+	return s[end+1:], nil
+}
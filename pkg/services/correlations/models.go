@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 )
@@ -16,13 +19,21 @@ var (
 	ErrCorrelationNotFound                = errors.New("correlation not found")
 	ErrUpdateCorrelationEmptyParams       = errors.New("not enough parameters to edit correlation")
 	ErrInvalidConfigType                  = errors.New("invalid correlation config type")
+	ErrInvalidCorrelationConfig           = errors.New("invalid correlation config")
 	ErrInvalidTransformationType          = errors.New("invalid transformation type")
 	ErrTransformationNotNested            = errors.New("transformations must be nested under config")
 	ErrTransformationRegexReqExp          = errors.New("regex transformations require expression")
+	ErrTransformationJSONPathReqExp       = errors.New("jsonpath transformations require expression")
+	ErrTransformationInvalidJSONPath      = errors.New("invalid jsonpath expression")
+	ErrExternalTargetURLRequired          = errors.New("external correlations require a target url")
+	ErrInvalidExternalTargetURL           = errors.New("invalid external correlation target url")
 	ErrCorrelationsQuotaFailed            = errors.New("error getting correlations quota")
 	ErrCorrelationsQuotaReached           = errors.New("correlations quota reached")
 )
 
+// ${name} variables in external correlation urls
+var urlVariableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.]*)\}`)
+
 const (
 	QuotaTargetSrv quota.TargetSrv = "correlations"
 	QuotaTarget    quota.Target    = "correlations"
@@ -31,7 +42,7 @@ const (
 type CorrelationConfigType string
 
 type Transformation struct {
-	//Enum: regex,logfmt
+	//Enum: regex,logfmt,jsonpath
 	Type       string `json:"type"`
 	Expression string `json:"expression,omitempty"`
 	Field      string `json:"field,omitempty"`
@@ -40,10 +51,13 @@ type Transformation struct {
 
 const (
 	ConfigTypeQuery CorrelationConfigType = "query"
+	// ConfigTypeExternal links to an external URL. The URL is set in target.url and can use ${variables} from the
+	// source field and transformations.
+	ConfigTypeExternal CorrelationConfigType = "external"
 )
 
 func (t CorrelationConfigType) Validate() error {
-	if t != ConfigTypeQuery {
+	if t != ConfigTypeQuery && t != ConfigTypeExternal {
 		return fmt.Errorf("%s: \"%s\"", ErrInvalidConfigType, t)
 	}
 	return nil
@@ -51,10 +65,21 @@ func (t CorrelationConfigType) Validate() error {
 
 func (t Transformations) Validate() error {
 	for _, v := range t {
-		if v.Type != "regex" && v.Type != "logfmt" {
+		switch v.Type {
+		case "logfmt":
+		case "regex":
+			if len(v.Expression) == 0 {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
+			}
+		case "jsonpath":
+			if len(v.Expression) == 0 {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationJSONPathReqExp, t)
+			}
+			if err := validateJSONPath(v.Expression); err != nil {
+				return fmt.Errorf("%w: %q: %s", ErrTransformationInvalidJSONPath, v.Expression, err)
+			}
+		default:
 			return fmt.Errorf("%s: \"%s\"", ErrInvalidTransformationType, t)
-		} else if v.Type == "regex" && len(v.Expression) == 0 {
-			return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
 		}
 	}
 	return nil
@@ -62,6 +87,28 @@ func (t Transformations) Validate() error {
 
 type Transformations []Transformation
 
+func validateExternalTarget(target map[string]interface{}) error {
+	rawURL, _ := target["url"].(string)
+	if rawURL == "" {
+		return ErrExternalTargetURLRequired
+	}
+
+	// variables can be anywhere in the url, so they are replaced before parsing it
+	replaced := urlVariableRegex.ReplaceAllString(rawURL, "variable")
+	if strings.Contains(replaced, "${") {
+		return fmt.Errorf("%w: invalid variable in %q", ErrInvalidExternalTargetURL, rawURL)
+	}
+
+	parsed, err := url.Parse(replaced)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidExternalTargetURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https", ErrInvalidExternalTargetURL)
+	}
+	return nil
+}
+
 // swagger:model
 type CorrelationConfig struct {
 	// Field used to attach the correlation link
@@ -87,13 +134,17 @@ func (c CorrelationConfig) MarshalJSON() ([]byte, error) {
 	if target == nil {
 		target = map[string]interface{}{}
 	}
+	configType := c.Type
+	if configType == "" {
+		configType = ConfigTypeQuery
+	}
 	return json.Marshal(struct {
 		Type            CorrelationConfigType  `json:"type"`
 		Field           string                 `json:"field"`
 		Target          map[string]interface{} `json:"target"`
 		Transformations Transformations        `json:"transformations,omitempty"`
 	}{
-		Type:            ConfigTypeQuery,
+		Type:            configType,
 		Field:           c.Field,
 		Target:          target,
 		Transformations: transformations,
@@ -150,17 +201,28 @@ type CreateCorrelationCommand struct {
 	Config CorrelationConfig `json:"config" binding:"Required"`
 }
 
-func (c CreateCorrelationCommand) Validate() error {
-	if err := c.Config.Type.Validate(); err != nil {
+// Validate checks the type, target and transformations of a correlation config
+func (c CorrelationConfig) Validate() error {
+	if err := c.Type.Validate(); err != nil {
 		return err
 	}
-	if c.TargetUID == nil && c.Config.Type == ConfigTypeQuery {
-		return fmt.Errorf("correlations of type \"%s\" must have a targetUID", ConfigTypeQuery)
+
+	if c.Type == ConfigTypeExternal {
+		if err := validateExternalTarget(c.Target); err != nil {
+			return err
+		}
 	}
 
-	if err := c.Config.Transformations.Validate(); err != nil {
+	return c.Transformations.Validate()
+}
+
+func (c CreateCorrelationCommand) Validate() error {
+	if err := c.Config.Validate(); err != nil {
 		return err
 	}
+	if c.TargetUID == nil && c.Config.Type == ConfigTypeQuery {
+		return fmt.Errorf("correlations of type \"%s\" must have a targetUID", ConfigTypeQuery)
+	}
 	return nil
 }
 
@@ -207,6 +269,12 @@ func (c CorrelationConfigUpdateDTO) Validate() error {
 		}
 	}
 
+	if c.Transformations != nil {
+		if err := Transformations(c.Transformations).Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
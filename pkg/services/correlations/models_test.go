@@ -55,6 +55,67 @@ func TestCorrelationModels(t *testing.T) {
 
 			require.Error(t, cmd.Validate())
 		})
+
+		t.Run("Successfully validates an external correlation without target UID", func(t *testing.T) {
+			cmd := &CreateCorrelationCommand{
+				SourceUID: "some-uid",
+				OrgId:     1,
+				Config: CorrelationConfig{
+					Field:  "traceId",
+					Type:   ConfigTypeExternal,
+					Target: map[string]interface{}{"url": "https://tracing.example.com/trace/${traceId}?env=${env}"},
+				},
+			}
+
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Fails if the external target url is invalid", func(t *testing.T) {
+			tests := []map[string]interface{}{
+				{},
+				{"url": ""},
+				{"url": "ftp://example.com/${traceId}"},
+				{"url": "/relative/${traceId}"},
+				{"url": "https://example.com/${traceId"},
+			}
+
+			for _, target := range tests {
+				cmd := &CreateCorrelationCommand{
+					SourceUID: "some-uid",
+					OrgId:     1,
+					Config:    CorrelationConfig{Field: "field", Type: ConfigTypeExternal, Target: target},
+				}
+				require.Error(t, cmd.Validate(), target)
+			}
+		})
+	})
+
+	t.Run("Transformations Validate", func(t *testing.T) {
+		type test struct {
+			transformation Transformation
+			assertion      require.ErrorAssertionFunc
+		}
+
+		tests := []test{
+			{transformation: Transformation{Type: "logfmt"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "regex", Expression: "(\\w+)"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "regex"}, assertion: require.Error},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$.spans[0].traceId"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$..labels['service.name']"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$.items[*].id"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$.items[1:3]"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$.items[0,2]"}, assertion: require.NoError},
+			{transformation: Transformation{Type: "jsonpath"}, assertion: require.Error},
+			{transformation: Transformation{Type: "jsonpath", Expression: "spans.traceId"}, assertion: require.Error},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$.items[?(@.id > 1)]"}, assertion: require.Error},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$.items[0"}, assertion: require.Error},
+			{transformation: Transformation{Type: "jsonpath", Expression: "$."}, assertion: require.Error},
+			{transformation: Transformation{Type: "unknown"}, assertion: require.Error},
+		}
+
+		for _, tc := range tests {
+			tc.assertion(t, Transformations{tc.transformation}.Validate(), tc.transformation.Expression)
+		}
 	})
 
 	t.Run("CorrelationConfigType Validate", func(t *testing.T) {
@@ -66,6 +127,7 @@ func TestCorrelationModels(t *testing.T) {
 
 			tests := []test{
 				{input: "query", assertion: require.NoError},
+				{input: "external", assertion: require.NoError},
 				{input: "link", assertion: require.Error},
 			}
 
@@ -87,5 +149,18 @@ func TestCorrelationModels(t *testing.T) {
 
 			require.Equal(t, `{"type":"query","field":"field","target":{}}`, string(data))
 		})
+
+		t.Run("Keeps the external type", func(t *testing.T) {
+			config := CorrelationConfig{
+				Field:  "field",
+				Type:   ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://example.com/${field}"},
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			require.Equal(t, `{"type":"external","field":"field","target":{"url":"https://example.com/${field}"}}`, string(data))
+		})
 	})
 }
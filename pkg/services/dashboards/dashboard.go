@@ -26,6 +26,12 @@ type DashboardService interface {
 	HasEditPermissionInFolders(ctx context.Context, query *folder.HasEditPermissionInFoldersQuery) (bool, error)
 	ImportDashboard(ctx context.Context, dto *SaveDashboardDTO) (*Dashboard, error)
 	MakeUserAdmin(ctx context.Context, orgID int64, userID, dashboardID int64, setViewAndEditPermissions bool) error
+	// GetTrashedDashboards lists the deleted dashboards that can still be restored.
+	GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)
+	// RestoreDashboard brings back a deleted dashboard with its permissions and version history.
+	RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) (*Dashboard, error)
+	// PurgeTrashedDashboards permanently deletes dashboards from the trash and returns how many were deleted.
+	PurgeTrashedDashboards(ctx context.Context, cmd *PurgeTrashedDashboardsCommand) (int64, error)
 	SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error)
 	SearchDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) (model.HitList, error)
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error
@@ -86,4 +92,11 @@ type Store interface {
 	// the given parent folder ID.
 	CountDashboardsInFolder(ctx context.Context, request *CountDashboardsInFolderRequest) (int64, error)
 	DeleteDashboardsInFolder(ctx context.Context, request *DeleteDashboardsInFolderRequest) error
+	// SoftDeleteDashboard moves a dashboard to the trash. Folders are moved to the trash with their dashboards.
+	SoftDeleteDashboard(ctx context.Context, cmd *DeleteDashboardCommand) error
+	// SoftDeleteDashboardsInFolder moves all dashboards of a folder to the trash.
+	SoftDeleteDashboardsInFolder(ctx context.Context, request *DeleteDashboardsInFolderRequest) error
+	GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)
+	RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) (*Dashboard, error)
+	PurgeTrashedDashboards(ctx context.Context, cmd *PurgeTrashedDashboardsCommand) (int64, error)
 }
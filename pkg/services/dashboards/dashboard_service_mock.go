@@ -555,6 +555,61 @@ func (_c *FakeDashboardService_GetDashboards_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// GetTrashedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*TrashedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetTrashedDashboardsQuery) []*TrashedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*TrashedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetTrashedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FakeDashboardService_GetTrashedDashboards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTrashedDashboards'
+type FakeDashboardService_GetTrashedDashboards_Call struct {
+	*mock.Call
+}
+
+// GetTrashedDashboards is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *GetTrashedDashboardsQuery
+func (_e *FakeDashboardService_Expecter) GetTrashedDashboards(ctx interface{}, query interface{}) *FakeDashboardService_GetTrashedDashboards_Call {
+	return &FakeDashboardService_GetTrashedDashboards_Call{Call: _e.mock.On("GetTrashedDashboards", ctx, query)}
+}
+
+func (_c *FakeDashboardService_GetTrashedDashboards_Call) Run(run func(ctx context.Context, query *GetTrashedDashboardsQuery)) *FakeDashboardService_GetTrashedDashboards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*GetTrashedDashboardsQuery))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardService_GetTrashedDashboards_Call) Return(_a0 []*TrashedDashboard, _a1 error) *FakeDashboardService_GetTrashedDashboards_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FakeDashboardService_GetTrashedDashboards_Call) RunAndReturn(run func(context.Context, *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)) *FakeDashboardService_GetTrashedDashboards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // HasAdminPermissionInDashboardsOrFolders provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) HasAdminPermissionInDashboardsOrFolders(ctx context.Context, query *folder.HasAdminPermissionInDashboardsOrFoldersQuery) (bool, error) {
 	ret := _m.Called(ctx, query)
@@ -762,6 +817,114 @@ func (_c *FakeDashboardService_MakeUserAdmin_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// PurgeTrashedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) PurgeTrashedDashboards(ctx context.Context, cmd *PurgeTrashedDashboardsCommand) (int64, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeTrashedDashboardsCommand) (int64, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeTrashedDashboardsCommand) int64); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *PurgeTrashedDashboardsCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FakeDashboardService_PurgeTrashedDashboards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeTrashedDashboards'
+type FakeDashboardService_PurgeTrashedDashboards_Call struct {
+	*mock.Call
+}
+
+// PurgeTrashedDashboards is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *PurgeTrashedDashboardsCommand
+func (_e *FakeDashboardService_Expecter) PurgeTrashedDashboards(ctx interface{}, cmd interface{}) *FakeDashboardService_PurgeTrashedDashboards_Call {
+	return &FakeDashboardService_PurgeTrashedDashboards_Call{Call: _e.mock.On("PurgeTrashedDashboards", ctx, cmd)}
+}
+
+func (_c *FakeDashboardService_PurgeTrashedDashboards_Call) Run(run func(ctx context.Context, cmd *PurgeTrashedDashboardsCommand)) *FakeDashboardService_PurgeTrashedDashboards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*PurgeTrashedDashboardsCommand))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardService_PurgeTrashedDashboards_Call) Return(_a0 int64, _a1 error) *FakeDashboardService_PurgeTrashedDashboards_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FakeDashboardService_PurgeTrashedDashboards_Call) RunAndReturn(run func(context.Context, *PurgeTrashedDashboardsCommand) (int64, error)) *FakeDashboardService_PurgeTrashedDashboards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) (*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardCommand) (*Dashboard, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardCommand) *Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FakeDashboardService_RestoreDashboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreDashboard'
+type FakeDashboardService_RestoreDashboard_Call struct {
+	*mock.Call
+}
+
+// RestoreDashboard is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *RestoreDashboardCommand
+func (_e *FakeDashboardService_Expecter) RestoreDashboard(ctx interface{}, cmd interface{}) *FakeDashboardService_RestoreDashboard_Call {
+	return &FakeDashboardService_RestoreDashboard_Call{Call: _e.mock.On("RestoreDashboard", ctx, cmd)}
+}
+
+func (_c *FakeDashboardService_RestoreDashboard_Call) Run(run func(ctx context.Context, cmd *RestoreDashboardCommand)) *FakeDashboardService_RestoreDashboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*RestoreDashboardCommand))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardService_RestoreDashboard_Call) Return(_a0 *Dashboard, _a1 error) *FakeDashboardService_RestoreDashboard_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FakeDashboardService_RestoreDashboard_Call) RunAndReturn(run func(context.Context, *RestoreDashboardCommand) (*Dashboard, error)) *FakeDashboardService_RestoreDashboard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
@@ -141,6 +141,10 @@ func (d *dashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd dashb
 	var result *dashboards.Dashboard
 	var err error
 	err = d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := d.purgeConflictingTrashedDashboards(sess, &cmd); err != nil {
+			return err
+		}
+
 		result, err = saveDashboard(sess, &cmd, d.emitEntityEvent())
 		if err != nil {
 			return err
@@ -159,6 +163,10 @@ func (d *dashboardStore) SaveDashboard(ctx context.Context, cmd dashboards.SaveD
 	var result *dashboards.Dashboard
 	var err error
 	err = d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := d.purgeConflictingTrashedDashboards(sess, &cmd); err != nil {
+			return err
+		}
+
 		result, err = saveDashboard(sess, &cmd, d.emitEntityEvent())
 		if err != nil {
 			return err
@@ -262,7 +270,7 @@ func (d *dashboardStore) Count(ctx context.Context, scopeParams *quota.ScopePara
 
 	r := result{}
 	if err := d.store.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
-		rawSQL := fmt.Sprintf("SELECT COUNT(*) AS count FROM dashboard WHERE is_folder=%s", d.store.GetDialect().BooleanStr(false))
+		rawSQL := fmt.Sprintf("SELECT COUNT(*) AS count FROM dashboard WHERE is_folder=%s AND deleted IS NULL", d.store.GetDialect().BooleanStr(false))
 		if _, err := sess.SQL(rawSQL).Get(&r); err != nil {
 			return err
 		}
@@ -279,7 +287,7 @@ func (d *dashboardStore) Count(ctx context.Context, scopeParams *quota.ScopePara
 
 	if scopeParams != nil && scopeParams.OrgID != 0 {
 		if err := d.store.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
-			rawSQL := fmt.Sprintf("SELECT COUNT(*) AS count FROM dashboard WHERE org_id=? AND is_folder=%s", d.store.GetDialect().BooleanStr(false))
+			rawSQL := fmt.Sprintf("SELECT COUNT(*) AS count FROM dashboard WHERE org_id=? AND is_folder=%s AND deleted IS NULL", d.store.GetDialect().BooleanStr(false))
 			if _, err := sess.SQL(rawSQL, scopeParams.OrgID).Get(&r); err != nil {
 				return err
 			}
@@ -305,7 +313,7 @@ func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.D
 
 	if dash.ID > 0 {
 		var err error
-		dashWithIdExists, err = sess.Where("id=? AND org_id=?", dash.ID, dash.OrgID).Get(&existingById)
+		dashWithIdExists, err = sess.Where("id=? AND org_id=? AND deleted IS NULL", dash.ID, dash.OrgID).Get(&existingById)
 		if err != nil {
 			return false, fmt.Errorf("SQL query for existing dashboard by ID failed: %w", err)
 		}
@@ -324,7 +332,7 @@ func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.D
 
 	if dash.UID != "" {
 		var err error
-		dashWithUidExists, err = sess.Where("org_id=? AND uid=?", dash.OrgID, dash.UID).Get(&existingByUid)
+		dashWithUidExists, err = sess.Where("org_id=? AND uid=? AND deleted IS NULL", dash.OrgID, dash.UID).Get(&existingByUid)
 		if err != nil {
 			return false, fmt.Errorf("SQL query for existing dashboard by UID failed: %w", err)
 		}
@@ -388,7 +396,7 @@ func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.D
 func getExistingDashboardByTitleAndFolder(sess *db.Session, dash *dashboards.Dashboard, dialect migrator.Dialect, overwrite,
 	isParentFolderChanged bool) (bool, error) {
 	var existing dashboards.Dashboard
-	exists, err := sess.Where("org_id=? AND slug=? AND (is_folder=? OR folder_id=?)", dash.OrgID, dash.Slug,
+	exists, err := sess.Where("org_id=? AND slug=? AND (is_folder=? OR folder_id=?) AND deleted IS NULL", dash.OrgID, dash.Slug,
 		dialect.BooleanStr(true), dash.FolderID).Get(&existing)
 	if err != nil {
 		return isParentFolderChanged, fmt.Errorf("SQL query for existing dashboard by org ID or folder ID failed: %w", err)
@@ -430,7 +438,7 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 
 	if dash.ID > 0 {
 		var existing dashboards.Dashboard
-		dashWithIdExists, err := sess.Where("id=? AND org_id=?", dash.ID, dash.OrgID).Get(&existing)
+		dashWithIdExists, err := sess.Where("id=? AND org_id=? AND deleted IS NULL", dash.ID, dash.OrgID).Get(&existing)
 		if err != nil {
 			return nil, err
 		}
@@ -673,7 +681,7 @@ func (d *dashboardStore) deleteAlertByIdInternal(alertId int64, reason string, s
 func (d *dashboardStore) GetDashboardsByPluginID(ctx context.Context, query *dashboards.GetDashboardsByPluginIDQuery) ([]*dashboards.Dashboard, error) {
 	var dashboards = make([]*dashboards.Dashboard, 0)
 	err := d.store.WithDbSession(ctx, func(dbSession *db.Session) error {
-		whereExpr := "org_id=? AND plugin_id=? AND is_folder=" + d.store.GetDialect().BooleanStr(false)
+		whereExpr := "org_id=? AND plugin_id=? AND deleted IS NULL AND is_folder=" + d.store.GetDialect().BooleanStr(false)
 
 		err := dbSession.Where(whereExpr, query.OrgID, query.PluginID).Find(&dashboards)
 		return err
@@ -711,7 +719,8 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 	}
 
 	if dashboard.IsFolder {
-		deletes = append(deletes, "DELETE FROM dashboard WHERE folder_id = ?")
+		// dashboards of the folder in the trash are kept until purged
+		deletes = append(deletes, "DELETE FROM dashboard WHERE folder_id = ? AND deleted IS NULL")
 
 		if err := d.deleteChildrenDashboardAssociations(sess, dashboard); err != nil {
 			return err
@@ -758,7 +767,7 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 		Id  int64
 		Uid string
 	}
-	err := sess.SQL("SELECT id, uid FROM dashboard WHERE folder_id = ?", dashboard.ID).Find(&dashIds)
+	err := sess.SQL("SELECT id, uid FROM dashboard WHERE folder_id = ? AND deleted IS NULL", dashboard.ID).Find(&dashIds)
 	if err != nil {
 		return err
 	}
@@ -777,12 +786,12 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 		}
 
 		childrenDeletes := []string{
-			"DELETE FROM dashboard_tag WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
-			"DELETE FROM star WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
-			"DELETE FROM dashboard_version WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
-			"DELETE FROM annotation WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
-			"DELETE FROM dashboard_provisioning WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
-			"DELETE FROM dashboard_acl WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
+			"DELETE FROM dashboard_tag WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted IS NULL)",
+			"DELETE FROM star WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted IS NULL)",
+			"DELETE FROM dashboard_version WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted IS NULL)",
+			"DELETE FROM annotation WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted IS NULL)",
+			"DELETE FROM dashboard_provisioning WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted IS NULL)",
+			"DELETE FROM dashboard_acl WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted IS NULL)",
 		}
 		for _, sql := range childrenDeletes {
 			_, err := sess.Exec(sql, dashboard.OrgID, dashboard.ID)
@@ -874,7 +883,7 @@ func (d *dashboardStore) GetDashboard(ctx context.Context, query *dashboards.Get
 			mustCols = append(mustCols, "folder_id")
 		}
 
-		has, err := sess.MustCols(mustCols...).Get(&dashboard)
+		has, err := sess.Where("deleted IS NULL").MustCols(mustCols...).Get(&dashboard)
 
 		if err != nil {
 			return err
@@ -894,7 +903,7 @@ func (d *dashboardStore) GetDashboard(ctx context.Context, query *dashboards.Get
 func (d *dashboardStore) GetDashboardUIDByID(ctx context.Context, query *dashboards.GetDashboardRefByIDQuery) (*dashboards.DashboardRef, error) {
 	us := &dashboards.DashboardRef{}
 	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
-		var rawSQL = `SELECT uid, slug from dashboard WHERE Id=?`
+		var rawSQL = `SELECT uid, slug from dashboard WHERE Id=? AND deleted IS NULL`
 		exists, err := sess.SQL(rawSQL, query.ID).Get(us)
 		if err != nil {
 			return err
@@ -924,6 +933,7 @@ func (d *dashboardStore) GetDashboards(ctx context.Context, query *dashboards.Ge
 		if query.OrgID > 0 {
 			session = sess.Where("org_id = ?", query.OrgID)
 		}
+		session = session.Where("deleted IS NULL")
 
 		err := session.Find(&dashboards)
 		return err
@@ -962,6 +972,7 @@ func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.F
 	}
 
 	filters = append(filters, query.Filters...)
+	filters = append(filters, searchstore.NotDeletedFilter{})
 
 	if query.OrgId != 0 {
 		filters = append(filters, searchstore.OrgFilter{OrgId: query.OrgId})
@@ -1025,7 +1036,7 @@ func (d *dashboardStore) GetDashboardTags(ctx context.Context, query *dashboards
 						term
 					FROM dashboard
 					INNER JOIN dashboard_tag on dashboard_tag.dashboard_id = dashboard.id
-					WHERE dashboard.org_id=?
+					WHERE dashboard.org_id=? AND dashboard.deleted IS NULL
 					GROUP BY term
 					ORDER BY term`
 
@@ -1050,7 +1061,7 @@ func (d *dashboardStore) CountDashboardsInFolder(
 	var err error
 	err = d.store.WithDbSession(ctx, func(sess *db.Session) error {
 		session := sess.In("folder_id", req.FolderID).In("org_id", req.OrgID).
-			In("is_folder", d.store.GetDialect().BooleanStr(false))
+			In("is_folder", d.store.GetDialect().BooleanStr(false)).Where("deleted IS NULL")
 		count, err = session.Count(&dashboards.Dashboard{})
 		return err
 	})
@@ -1073,7 +1084,7 @@ func (d *dashboardStore) DeleteDashboardsInFolder(
 			return err
 		}
 
-		_, err = sess.Where("folder_id = ? AND org_id = ? AND is_folder = ?", dashboard.ID, dashboard.OrgID, false).Delete(&dashboards.Dashboard{})
+		_, err = sess.Where("folder_id = ? AND org_id = ? AND is_folder = ? AND deleted IS NULL", dashboard.ID, dashboard.OrgID, false).Delete(&dashboards.Dashboard{})
 		return err
 	})
 }
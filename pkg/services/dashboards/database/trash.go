@@ -0,0 +1,324 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+func (d *dashboardStore) SoftDeleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		dashboard := dashboards.Dashboard{ID: cmd.ID, OrgID: cmd.OrgID}
+		has, err := sess.Where("deleted IS NULL").Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+
+		trashed := []*dashboards.Dashboard{&dashboard}
+		if dashboard.IsFolder {
+			// alert rules are not kept in the trash
+			if err := deleteFolderAlertRules(sess, dashboard, cmd.ForceDeleteFolderRules); err != nil {
+				return err
+			}
+
+			// the dashboards of the folder share its deletion time so that they are restored with it
+			children := make([]*dashboards.Dashboard, 0)
+			err := sess.Where("folder_id = ? AND org_id = ? AND deleted IS NULL", dashboard.ID, dashboard.OrgID).Find(&children)
+			if err != nil {
+				return err
+			}
+			trashed = append(trashed, children...)
+		}
+		return d.moveToTrash(sess, trashed)
+	})
+}
+
+func (d *dashboardStore) SoftDeleteDashboardsInFolder(ctx context.Context, req *dashboards.DeleteDashboardsInFolderRequest) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		folder := dashboards.Dashboard{OrgID: req.OrgID}
+		has, err := sess.Where("uid = ? AND org_id = ?", req.FolderUID, req.OrgID).Get(&folder)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboards.ErrFolderNotFound
+		}
+
+		children := make([]*dashboards.Dashboard, 0)
+		err = sess.Where("folder_id = ? AND org_id = ? AND is_folder = ? AND deleted IS NULL", folder.ID, folder.OrgID, false).Find(&children)
+		if err != nil {
+			return err
+		}
+		return d.moveToTrash(sess, children)
+	})
+}
+
+// moveToTrash flags the dashboards as deleted. Their permissions, versions, tags and stars are
+// kept so that restoring a dashboard or a folder brings it back as it was. Legacy alerts are removed and
+// extracted again the next time the dashboard is saved.
+func (d *dashboardStore) moveToTrash(sess *db.Session, trashed []*dashboards.Dashboard) error {
+	now := time.Now()
+	for _, dashboard := range trashed {
+		if err := d.deleteAlertDefinition(dashboard.ID, sess); err != nil {
+			return err
+		}
+
+		if _, err := sess.Exec("UPDATE dashboard SET deleted = ? WHERE id = ?", now, dashboard.ID); err != nil {
+			return err
+		}
+
+		if d.emitEntityEvent() {
+			if _, err := sess.Insert(createEntityEvent(dashboard, store.EntityEventTypeDelete)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *dashboardStore) GetTrashedDashboards(ctx context.Context, query *dashboards.GetTrashedDashboardsQuery) ([]*dashboards.TrashedDashboard, error) {
+	limit := query.Limit
+	if limit < 1 {
+		limit = 1000
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	result := make([]*dashboards.TrashedDashboard, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := `SELECT
+				dashboard.id,
+				dashboard.uid,
+				dashboard.title,
+				dashboard.folder_id,
+				folder.uid AS folder_uid,
+				folder.title AS folder_title,
+				dashboard.deleted
+			FROM dashboard
+			LEFT OUTER JOIN dashboard AS folder ON folder.id = dashboard.folder_id
+			WHERE dashboard.org_id = ? AND dashboard.deleted IS NOT NULL
+			ORDER BY dashboard.deleted DESC, dashboard.id ` + d.store.GetDialect().LimitOffset(int64(limit), int64((page-1)*limit))
+		return sess.SQL(rawSQL, query.OrgID).Find(&result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (d *dashboardStore) RestoreDashboard(ctx context.Context, cmd *dashboards.RestoreDashboardCommand) (*dashboards.Dashboard, error) {
+	var dashboard dashboards.Dashboard
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ? AND deleted IS NOT NULL", cmd.OrgID, cmd.UID).Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+
+		if dashboard.IsFolder {
+			// restore the dashboards that were moved to the trash along with the folder
+			_, err := sess.Exec("UPDATE dashboard SET deleted = NULL WHERE org_id = ? AND folder_id = ? AND deleted = ?",
+				dashboard.OrgID, dashboard.ID, dashboard.Deleted)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := d.restoreParentFolder(sess, &dashboard); err != nil {
+			return err
+		}
+		return d.restore(sess, &dashboard)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard.SetID(dashboard.ID)
+	dashboard.SetUID(dashboard.UID)
+	return &dashboard, nil
+}
+
+// restoreParentFolder restores the folder of the dashboard, or the parent of a nested folder, when
+// it is in the trash, so that the restored dashboard keeps the permissions inherited from it.
+// Dashboards whose folder was purged are restored to the General folder.
+func (d *dashboardStore) restoreParentFolder(sess *db.Session, dashboard *dashboards.Dashboard) error {
+	parent := dashboards.Dashboard{}
+	has := false
+	var err error
+	if dashboard.IsFolder {
+		var parentUID string
+		found, err := sess.Table("folder").Where("org_id = ? AND uid = ? AND parent_uid IS NOT NULL", dashboard.OrgID, dashboard.UID).
+			Cols("parent_uid").Get(&parentUID)
+		if err != nil {
+			return err
+		}
+		if !found || parentUID == "" {
+			return nil
+		}
+		has, err = sess.Where("org_id = ? AND uid = ? AND is_folder = ?", dashboard.OrgID, parentUID, d.store.GetDialect().BooleanStr(true)).
+			Get(&parent)
+		if err != nil {
+			return err
+		}
+	} else if dashboard.FolderID > 0 {
+		has, err = sess.Where("org_id = ? AND id = ? AND is_folder = ?", dashboard.OrgID, dashboard.FolderID, d.store.GetDialect().BooleanStr(true)).
+			Get(&parent)
+		if err != nil {
+			return err
+		}
+		if !has {
+			dashboard.FolderID = 0
+			return nil
+		}
+	}
+
+	if !has || parent.Deleted.IsZero() {
+		return nil
+	}
+	if err := d.restoreParentFolder(sess, &parent); err != nil {
+		return err
+	}
+	return d.restore(sess, &parent)
+}
+
+// restore takes the dashboard out of the trash, unless a dashboard with the same title was
+// created in its folder in the meantime. A dashboard in the trash with the same title in the
+// folder is replaced by the restored dashboard.
+func (d *dashboardStore) restore(sess *db.Session, dashboard *dashboards.Dashboard) error {
+	conflict, err := sess.Table("dashboard").Where("org_id = ? AND folder_id = ? AND title = ? AND id <> ? AND deleted IS NULL",
+		dashboard.OrgID, dashboard.FolderID, dashboard.Title, dashboard.ID).Exist()
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return dashboards.ErrDashboardWithSameNameInFolderExists
+	}
+
+	trashedConflicts := make([]*dashboards.Dashboard, 0)
+	err = sess.Cols("id", "org_id", "uid", "is_folder").Where("org_id = ? AND folder_id = ? AND title = ? AND id <> ? AND deleted IS NOT NULL",
+		dashboard.OrgID, dashboard.FolderID, dashboard.Title, dashboard.ID).Find(&trashedConflicts)
+	if err != nil {
+		return err
+	}
+	for _, trashed := range trashedConflicts {
+		if _, err := d.purgeDashboard(sess, trashed); err != nil {
+			return err
+		}
+	}
+
+	if _, err := sess.Exec("UPDATE dashboard SET deleted = NULL, folder_id = ? WHERE id = ?", dashboard.FolderID, dashboard.ID); err != nil {
+		return err
+	}
+	dashboard.Deleted = time.Time{}
+
+	if d.emitEntityEvent() {
+		if _, err := sess.Insert(createEntityEvent(dashboard, store.EntityEventTypeCreate)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dashboardStore) PurgeTrashedDashboards(ctx context.Context, cmd *dashboards.PurgeTrashedDashboardsCommand) (int64, error) {
+	var purged int64
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		trashed := make([]*dashboards.Dashboard, 0)
+		query := sess.Cols("id", "org_id", "uid", "is_folder").Where("deleted IS NOT NULL")
+		if cmd.UID != "" {
+			query = query.Where("org_id = ? AND uid = ?", cmd.OrgID, cmd.UID)
+		} else {
+			query = query.Where("deleted < ?", cmd.DeletedBefore)
+		}
+		if err := query.Find(&trashed); err != nil {
+			return err
+		}
+
+		if cmd.UID != "" && len(trashed) == 0 {
+			return dashboards.ErrDashboardNotFound
+		}
+
+		for _, dashboard := range trashed {
+			count, err := d.purgeDashboard(sess, dashboard)
+			// dashboards of a folder are purged along with it
+			if errors.Is(err, dashboards.ErrDashboardNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			purged += count
+		}
+		return nil
+	})
+	return purged, err
+}
+
+// purgeDashboard permanently deletes a dashboard of the trash. The dashboards of a folder that are
+// in the trash are deleted with it, as is the nested folder entry of the folder. It returns the
+// number of deleted dashboards.
+func (d *dashboardStore) purgeDashboard(sess *db.Session, dashboard *dashboards.Dashboard) (int64, error) {
+	var purged int64
+	if dashboard.IsFolder {
+		children := make([]*dashboards.Dashboard, 0)
+		if err := sess.Cols("id", "org_id").Where("folder_id = ? AND org_id = ? AND deleted IS NOT NULL", dashboard.ID, dashboard.OrgID).Find(&children); err != nil {
+			return 0, err
+		}
+		for _, child := range children {
+			if err := d.deleteDashboard(&dashboards.DeleteDashboardCommand{ID: child.ID, OrgID: child.OrgID}, sess, false); err != nil {
+				return 0, err
+			}
+			purged++
+		}
+
+		if _, err := sess.Exec("UPDATE folder SET parent_uid = NULL WHERE org_id = ? AND parent_uid = ?", dashboard.OrgID, dashboard.UID); err != nil {
+			return 0, err
+		}
+		if _, err := sess.Exec("DELETE FROM folder WHERE org_id = ? AND uid = ?", dashboard.OrgID, dashboard.UID); err != nil {
+			return 0, err
+		}
+	}
+
+	// the delete event was emitted when the dashboard was moved to the trash, and the alert rules
+	// of a folder were deleted at that time
+	cmd := &dashboards.DeleteDashboardCommand{ID: dashboard.ID, OrgID: dashboard.OrgID, ForceDeleteFolderRules: true}
+	if err := d.deleteDashboard(cmd, sess, false); err != nil {
+		return 0, err
+	}
+	return purged + 1, nil
+}
+
+// purgeConflictingTrashedDashboards permanently deletes the dashboards in the trash that hold the
+// UID or the title in the folder of the saved dashboard, as the saved dashboard replaces them.
+func (d *dashboardStore) purgeConflictingTrashedDashboards(sess *db.Session, cmd *dashboards.SaveDashboardCommand) error {
+	dash := cmd.GetDashboardModel()
+
+	where := "org_id = ? AND deleted IS NOT NULL AND ((folder_id = ? AND title = ?)"
+	args := []interface{}{dash.OrgID, dash.FolderID, dash.Title}
+	if dash.UID != "" {
+		where += " OR uid = ?"
+		args = append(args, dash.UID)
+	}
+	where += ")"
+
+	conflicts := make([]*dashboards.Dashboard, 0)
+	if err := sess.Cols("id", "org_id", "uid", "is_folder").Where(where, args...).Find(&conflicts); err != nil {
+		return err
+	}
+
+	for _, conflict := range conflicts {
+		if _, err := d.purgeDashboard(sess, conflict); err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return err
+		}
+	}
+	return nil
+}
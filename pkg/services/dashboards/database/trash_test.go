@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+)
+
+func TestIntegrationDashboardTrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+
+	var sqlStore db.DB
+	var dashboardStore dashboards.Store
+	var savedFolder, savedDash, savedDash2 *dashboards.Dashboard
+
+	setup := func(t *testing.T) {
+		store, cfg := db.InitTestDBwithCfg(t)
+		sqlStore = store
+		var err error
+		dashboardStore, err = ProvideDashboardStore(store, cfg, testFeatureToggles, tagimpl.ProvideService(store, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		savedFolder = insertTestDashboard(t, dashboardStore, "trash folder", 1, 0, true)
+		savedDash = insertTestDashboard(t, dashboardStore, "trash dash", 1, savedFolder.ID, false, "prod")
+		savedDash2 = insertTestDashboard(t, dashboardStore, "trash dash 2", 1, 0, false)
+	}
+
+	countVersions := func(t *testing.T, dashboardID int64) int64 {
+		t.Helper()
+		var count int64
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			count, err = sess.Table("dashboard_version").Where("dashboard_id = ?", dashboardID).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Should hide a soft deleted dashboard and restore it", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedDash.ID, OrgID: 1}))
+
+		_, err := dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: savedDash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		tags, err := dashboardStore.GetDashboardTags(ctx, &dashboards.GetDashboardTagsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, savedDash.UID, trashed[0].UID)
+		assert.Equal(t, savedFolder.UID, trashed[0].FolderUID)
+		assert.False(t, trashed[0].Deleted.IsZero())
+
+		restored, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, savedFolder.ID, restored.FolderID)
+		assert.Equal(t, int64(1), countVersions(t, savedDash.ID))
+
+		_, err = dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should keep the dashboards of a deleted folder in the trash", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboardsInFolder(ctx, &dashboards.DeleteDashboardsInFolderRequest{FolderUID: savedFolder.UID, OrgID: 1}))
+		require.NoError(t, dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1}))
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Empty(t, trashed[0].FolderUID)
+
+		restored, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), restored.FolderID)
+		assert.Equal(t, int64(1), countVersions(t, savedDash.ID))
+	})
+
+	t.Run("Should move a folder to the trash with its dashboards and restore them with its permissions", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, updateDashboardACL(t, dashboardStore, savedFolder.ID, dashboards.DashboardACL{
+			OrgID: 1, UserID: 10, DashboardID: savedFolder.ID, Permission: dashboards.PERMISSION_EDIT,
+		}))
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1}))
+
+		_, err := dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: savedFolder.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 2)
+
+		restored, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedFolder.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.True(t, restored.IsFolder)
+
+		dash, err := dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, savedFolder.ID, dash.FolderID)
+
+		acl, err := dashboardStore.GetDashboardACLInfoList(ctx, &dashboards.GetDashboardACLInfoListQuery{DashboardID: savedDash.ID, OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, acl, 1)
+		assert.Equal(t, savedFolder.ID, acl[0].DashboardID)
+	})
+
+	t.Run("Should restore the folder of a dashboard from the trash", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1}))
+
+		restored, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, savedFolder.ID, restored.FolderID)
+
+		_, err = dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: savedFolder.UID, OrgID: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should purge the dashboards of a purged folder", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1}))
+
+		purged, err := dashboardStore.PurgeTrashedDashboards(ctx, &dashboards.PurgeTrashedDashboardsCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Empty(t, trashed)
+	})
+
+	t.Run("Should replace a trashed dashboard with the same title when restoring", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedDash.ID, OrgID: 1}))
+		sameTitle := insertTestDashboard(t, dashboardStore, "trash dash", 1, 0, false)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: sameTitle.ID, OrgID: 1}))
+		require.NoError(t, dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1}))
+
+		restored, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), restored.FolderID)
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Empty(t, trashed)
+	})
+
+	t.Run("Should not restore over a dashboard with the same title", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboardsInFolder(ctx, &dashboards.DeleteDashboardsInFolderRequest{FolderUID: savedFolder.UID, OrgID: 1}))
+		require.NoError(t, dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1}))
+		insertTestDashboard(t, dashboardStore, "trash dash", 1, 0, false)
+
+		_, err := dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameNameInFolderExists)
+	})
+
+	t.Run("Should replace a trashed dashboard when saving one with the same uid", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedDash2.ID, OrgID: 1}))
+
+		saved, err := dashboardStore.SaveDashboard(ctx, dashboards.SaveDashboardCommand{
+			OrgID: 1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"uid":   savedDash2.UID,
+				"title": savedDash2.Title,
+			}),
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, savedDash2.ID, saved.ID)
+		assert.Equal(t, int64(0), countVersions(t, savedDash2.ID))
+
+		trashed, err := dashboardStore.GetTrashedDashboards(ctx, &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Empty(t, trashed)
+	})
+
+	t.Run("Should purge dashboards deleted before the retention", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedDash.ID, OrgID: 1}))
+
+		purged, err := dashboardStore.PurgeTrashedDashboards(ctx, &dashboards.PurgeTrashedDashboardsCommand{DeletedBefore: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = dashboardStore.PurgeTrashedDashboards(ctx, &dashboards.PurgeTrashedDashboardsCommand{DeletedBefore: time.Now().Add(time.Minute)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		assert.Equal(t, int64(0), countVersions(t, savedDash.ID))
+
+		_, err = dashboardStore.RestoreDashboard(ctx, &dashboards.RestoreDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+	})
+
+	t.Run("Should purge a single dashboard", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, dashboardStore.SoftDeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{ID: savedDash.ID, OrgID: 1}))
+
+		purged, err := dashboardStore.PurgeTrashedDashboards(ctx, &dashboards.PurgeTrashedDashboardsCommand{OrgID: 1, UID: savedDash.UID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = dashboardStore.PurgeTrashedDashboards(ctx, &dashboards.PurgeTrashedDashboardsCommand{OrgID: 1, UID: savedDash2.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+	})
+}
@@ -2,6 +2,7 @@ package dashboards
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -163,3 +164,27 @@ type UpdatePluginDashboardError struct {
 func (d UpdatePluginDashboardError) Error() string {
 	return "Dashboard belongs to plugin"
 }
+
+// SchemaValidationError is returned when a dashboard does not match the dashboard kind schema
+// and schema validation is strict.
+type SchemaValidationError struct {
+	Errors []SchemaFieldError
+}
+
+// SchemaFieldError is a violation of the dashboard schema by a field of the dashboard.
+type SchemaFieldError struct {
+	// Path is the path of the field in the dashboard, like panels[2].gridPos.h.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e SchemaValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return "Dashboard does not match the schema"
+	}
+	first := e.Errors[0]
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("Dashboard does not match the schema: %s: %s", first.Path, first.Message)
+	}
+	return fmt.Sprintf("Dashboard does not match the schema: %s: %s (and %d more errors)", first.Path, first.Message, len(e.Errors)-1)
+}
@@ -43,6 +43,9 @@ type Dashboard struct {
 
 	Title string
 	Data  *simplejson.Json
+
+	// Deleted is set while the dashboard is in the trash.
+	Deleted time.Time
 }
 
 func (d *Dashboard) SetID(id int64) {
@@ -197,6 +200,9 @@ type SaveDashboardCommand struct {
 	FolderID     int64            `json:"folderId" xorm:"folder_id"`
 	FolderUID    string           `json:"folderUid" xorm:"folder_uid"`
 	IsFolder     bool             `json:"isFolder"`
+	// AutoMerge merges the changes with the ones saved by others since the version of the dashboard
+	// instead of failing with a version mismatch, unless they conflict.
+	AutoMerge bool `json:"autoMerge"`
 
 	UpdatedAt time.Time
 }
@@ -345,6 +351,37 @@ type DeleteDashboardsInFolderRequest struct {
 	OrgID     int64
 }
 
+// TrashedDashboard is a deleted dashboard kept in the trash until it is restored or purged.
+type TrashedDashboard struct {
+	ID       int64  `xorm:"id" json:"id"`
+	UID      string `xorm:"uid" json:"uid"`
+	Title    string `json:"title"`
+	FolderID int64  `xorm:"folder_id" json:"folderId"`
+	// FolderUID and FolderTitle are empty when the folder of the dashboard was deleted permanently.
+	FolderUID   string    `xorm:"folder_uid" json:"folderUid"`
+	FolderTitle string    `xorm:"folder_title" json:"folderTitle"`
+	Deleted     time.Time `json:"deleted"`
+}
+
+type GetTrashedDashboardsQuery struct {
+	OrgID int64
+	Limit int
+	Page  int
+}
+
+type RestoreDashboardCommand struct {
+	OrgID int64
+	UID   string
+}
+
+// PurgeTrashedDashboardsCommand purges a single dashboard when UID is set, otherwise all
+// dashboards moved to the trash before DeletedBefore.
+type PurgeTrashedDashboardsCommand struct {
+	OrgID         int64
+	UID           string
+	DeletedBefore time.Time
+}
+
 //
 // DASHBOARD ACL
 //
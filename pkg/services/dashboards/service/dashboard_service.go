@@ -124,6 +124,10 @@ func (dr *DashboardServiceImpl) BuildSaveDashboardCommand(ctx context.Context, d
 		return nil, err
 	}
 
+	if err := dr.validateDashboardSchema(dash); err != nil {
+		return nil, err
+	}
+
 	if shouldValidateAlerts {
 		dashAlertInfo := alerting.DashAlertInfo{Dash: dash, User: dto.User, OrgID: dash.OrgID}
 		if err := dr.dashAlertExtractor.ValidateAlerts(ctx, dashAlertInfo); err != nil {
@@ -463,9 +467,25 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 		}
 	}
 	cmd := &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}
+	// provisioned dashboards are removed along with their files and are not kept in the trash
+	if validateProvisionedDashboard && dr.cfg.DashboardTrashRetention > 0 {
+		return dr.dashboardStore.SoftDeleteDashboard(ctx, cmd)
+	}
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
+func (dr *DashboardServiceImpl) GetTrashedDashboards(ctx context.Context, query *dashboards.GetTrashedDashboardsQuery) ([]*dashboards.TrashedDashboard, error) {
+	return dr.dashboardStore.GetTrashedDashboards(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) RestoreDashboard(ctx context.Context, cmd *dashboards.RestoreDashboardCommand) (*dashboards.Dashboard, error) {
+	return dr.dashboardStore.RestoreDashboard(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) PurgeTrashedDashboards(ctx context.Context, cmd *dashboards.PurgeTrashedDashboardsCommand) (int64, error) {
+	return dr.dashboardStore.PurgeTrashedDashboards(ctx, cmd)
+}
+
 func (dr *DashboardServiceImpl) ImportDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO) (
 	*dashboards.Dashboard, error) {
 	if err := validateDashboardRefreshInterval(dto.Dashboard); err != nil {
@@ -644,6 +664,9 @@ func (dr DashboardServiceImpl) CountInFolder(ctx context.Context, orgID int64, u
 }
 
 func (dr *DashboardServiceImpl) DeleteInFolder(ctx context.Context, orgID int64, UID string) error {
+	if dr.cfg.DashboardTrashRetention > 0 {
+		return dr.dashboardStore.SoftDeleteDashboardsInFolder(ctx, &dashboards.DeleteDashboardsInFolderRequest{FolderUID: UID, OrgID: orgID})
+	}
 	return dr.dashboardStore.DeleteDashboardsInFolder(ctx, &dashboards.DeleteDashboardsInFolderRequest{FolderUID: UID, OrgID: orgID})
 }
 
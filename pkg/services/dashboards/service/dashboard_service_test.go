@@ -286,3 +286,52 @@ func TestDashboardService(t *testing.T) {
 		})
 	})
 }
+
+func TestValidateDashboardSchema(t *testing.T) {
+	newDashboard := func(data map[string]interface{}) *dashboards.Dashboard {
+		return dashboards.NewDashboardFromJson(simplejson.NewFromAny(data))
+	}
+	invalid := newDashboard(map[string]interface{}{
+		"title":         "Invalid",
+		"schemaVersion": 36,
+		"refresh":       5,
+		"panels": []interface{}{
+			map[string]interface{}{"type": "row", "title": "Row", "collapsed": "no", "gridPos": map[string]interface{}{"h": 1, "w": 24, "x": 0, "y": 0}},
+		},
+	})
+
+	newService := func(mode string) *DashboardServiceImpl {
+		cfg := setting.NewCfg()
+		cfg.DashboardSchemaValidation = mode
+		return &DashboardServiceImpl{cfg: cfg, log: log.New("test.logger")}
+	}
+
+	t.Run("strict mode rejects dashboards with field-level errors", func(t *testing.T) {
+		err := newService(setting.DashboardSchemaValidationStrict).validateDashboardSchema(invalid)
+		var schemaErr dashboards.SchemaValidationError
+		require.ErrorAs(t, err, &schemaErr)
+
+		paths := make([]string, 0, len(schemaErr.Errors))
+		for _, e := range schemaErr.Errors {
+			require.NotEmpty(t, e.Message)
+			paths = append(paths, e.Path)
+		}
+		require.Contains(t, paths, "refresh")
+		require.Contains(t, paths, "panels[0].collapsed")
+	})
+
+	t.Run("strict mode accepts valid dashboards", func(t *testing.T) {
+		valid := newDashboard(map[string]interface{}{"title": "Valid", "schemaVersion": 36, "refresh": "5s"})
+		require.NoError(t, newService(setting.DashboardSchemaValidationStrict).validateDashboardSchema(valid))
+	})
+
+	t.Run("dashboards older than the handoff schema version are not validated", func(t *testing.T) {
+		old := newDashboard(map[string]interface{}{"title": "Old", "schemaVersion": 27, "refresh": 5})
+		require.NoError(t, newService(setting.DashboardSchemaValidationStrict).validateDashboardSchema(old))
+	})
+
+	t.Run("report mode and off do not reject dashboards", func(t *testing.T) {
+		require.NoError(t, newService(setting.DashboardSchemaValidationReport).validateDashboardSchema(invalid))
+		require.NoError(t, newService(setting.DashboardSchemaValidationOff).validateDashboardSchema(invalid))
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	cueerrors "cuelang.org/go/cue/errors"
+
+	"github.com/grafana/grafana/pkg/cuectx"
+	"github.com/grafana/grafana/pkg/kinds/dashboard"
+	"github.com/grafana/grafana/pkg/registry/corekind"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// validateDashboardSchema validates a dashboard against the latest schema of the dashboard kind,
+// depending on the configured schema validation mode.
+func (dr *DashboardServiceImpl) validateDashboardSchema(dash *dashboards.Dashboard) error {
+	mode := dr.cfg.DashboardSchemaValidation
+	if mode == "" || mode == setting.DashboardSchemaValidationOff || dash.IsFolder {
+		return nil
+	}
+
+	// Only validate if the schemaVersion is at least the handoff version (the minimum
+	// schemaVersion against which the dashboard schema is known to work), or if
+	// schemaVersion is absent.
+	if schemaVersion, err := dash.Data.Get("schemaVersion").Int(); err == nil && schemaVersion < dashboard.HandoffSchemaVersion {
+		return nil
+	}
+
+	fieldErrors, err := schemaFieldErrors(dash)
+	if err != nil {
+		return err
+	}
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	if mode == setting.DashboardSchemaValidationReport {
+		fields := make([]string, 0, len(fieldErrors))
+		for _, e := range fieldErrors {
+			fields = append(fields, e.Path+": "+e.Message)
+		}
+		dr.log.Warn("Dashboard does not match the schema", "uid", dash.UID, "title", dash.Title, "orgId", dash.OrgID, "errors", strings.Join(fields, "; "))
+		return nil
+	}
+	return dashboards.SchemaValidationError{Errors: fieldErrors}
+}
+
+// schemaFieldErrors lists the fields of a dashboard that do not match the schema of the dashboard kind.
+func schemaFieldErrors(dash *dashboards.Dashboard) ([]dashboards.SchemaFieldError, error) {
+	data, err := dash.Data.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Schemas expect the dashboard to live in the spec field
+	sch := corekind.NewBase(nil).Dashboard().Lineage().Latest()
+	value := cuectx.GrafanaCUEContext().CompileBytes([]byte(`{"spec": ` + string(data) + "}"))
+	err = sch.Underlying().Unify(value).Validate(cue.Concrete(false))
+	if err == nil {
+		return nil, nil
+	}
+
+	var fieldErrors []dashboards.SchemaFieldError
+	seen := map[dashboards.SchemaFieldError]bool{}
+	for _, e := range cueerrors.Errors(err) {
+		path, ok := specPath(e.Path())
+		if !ok {
+			continue
+		}
+		format, args := e.Msg()
+		if strings.Contains(format, "errors in empty disjunction") {
+			// the errors of the branches of the disjunction are reported on their own
+			continue
+		}
+		fieldError := dashboards.SchemaFieldError{Path: path, Message: fmt.Sprintf(format, args...)}
+		if seen[fieldError] {
+			continue
+		}
+		seen[fieldError] = true
+		fieldErrors = append(fieldErrors, fieldError)
+	}
+	if len(fieldErrors) == 0 {
+		// the error is not about a field of the dashboard
+		fieldErrors = append(fieldErrors, dashboards.SchemaFieldError{Message: err.Error()})
+	}
+	return fieldErrors, nil
+}
+
+// specPath formats the path of a field of the spec of the dashboard resource, like panels[2].gridPos.h.
+// Errors reported on other fields, like the ones the schema derives from the spec, are skipped.
+func specPath(path []string) (string, bool) {
+	for i, p := range path {
+		if p != "spec" {
+			continue
+		}
+		var b strings.Builder
+		for _, field := range path[i+1:] {
+			if _, err := strconv.Atoi(field); err == nil {
+				b.WriteString("[" + field + "]")
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(field)
+		}
+		return b.String(), true
+	}
+	return "", false
+}
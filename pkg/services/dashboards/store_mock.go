@@ -857,6 +857,61 @@ func (_c *FakeDashboardStore_GetProvisionedDataByDashboardUID_Call) RunAndReturn
 	return _c
 }
 
+// GetTrashedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*TrashedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetTrashedDashboardsQuery) []*TrashedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*TrashedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetTrashedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FakeDashboardStore_GetTrashedDashboards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTrashedDashboards'
+type FakeDashboardStore_GetTrashedDashboards_Call struct {
+	*mock.Call
+}
+
+// GetTrashedDashboards is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *GetTrashedDashboardsQuery
+func (_e *FakeDashboardStore_Expecter) GetTrashedDashboards(ctx interface{}, query interface{}) *FakeDashboardStore_GetTrashedDashboards_Call {
+	return &FakeDashboardStore_GetTrashedDashboards_Call{Call: _e.mock.On("GetTrashedDashboards", ctx, query)}
+}
+
+func (_c *FakeDashboardStore_GetTrashedDashboards_Call) Run(run func(ctx context.Context, query *GetTrashedDashboardsQuery)) *FakeDashboardStore_GetTrashedDashboards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*GetTrashedDashboardsQuery))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardStore_GetTrashedDashboards_Call) Return(_a0 []*TrashedDashboard, _a1 error) *FakeDashboardStore_GetTrashedDashboards_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FakeDashboardStore_GetTrashedDashboards_Call) RunAndReturn(run func(context.Context, *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)) *FakeDashboardStore_GetTrashedDashboards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // HasAdminPermissionInDashboardsOrFolders provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardStore) HasAdminPermissionInDashboardsOrFolders(ctx context.Context, query *folder.HasAdminPermissionInDashboardsOrFoldersQuery) (bool, error) {
 	ret := _m.Called(ctx, query)
@@ -963,6 +1018,114 @@ func (_c *FakeDashboardStore_HasEditPermissionInFolders_Call) RunAndReturn(run f
 	return _c
 }
 
+// PurgeTrashedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) PurgeTrashedDashboards(ctx context.Context, cmd *PurgeTrashedDashboardsCommand) (int64, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeTrashedDashboardsCommand) (int64, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeTrashedDashboardsCommand) int64); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *PurgeTrashedDashboardsCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FakeDashboardStore_PurgeTrashedDashboards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeTrashedDashboards'
+type FakeDashboardStore_PurgeTrashedDashboards_Call struct {
+	*mock.Call
+}
+
+// PurgeTrashedDashboards is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *PurgeTrashedDashboardsCommand
+func (_e *FakeDashboardStore_Expecter) PurgeTrashedDashboards(ctx interface{}, cmd interface{}) *FakeDashboardStore_PurgeTrashedDashboards_Call {
+	return &FakeDashboardStore_PurgeTrashedDashboards_Call{Call: _e.mock.On("PurgeTrashedDashboards", ctx, cmd)}
+}
+
+func (_c *FakeDashboardStore_PurgeTrashedDashboards_Call) Run(run func(ctx context.Context, cmd *PurgeTrashedDashboardsCommand)) *FakeDashboardStore_PurgeTrashedDashboards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*PurgeTrashedDashboardsCommand))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardStore_PurgeTrashedDashboards_Call) Return(_a0 int64, _a1 error) *FakeDashboardStore_PurgeTrashedDashboards_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FakeDashboardStore_PurgeTrashedDashboards_Call) RunAndReturn(run func(context.Context, *PurgeTrashedDashboardsCommand) (int64, error)) *FakeDashboardStore_PurgeTrashedDashboards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) (*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardCommand) (*Dashboard, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardCommand) *Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FakeDashboardStore_RestoreDashboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreDashboard'
+type FakeDashboardStore_RestoreDashboard_Call struct {
+	*mock.Call
+}
+
+// RestoreDashboard is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *RestoreDashboardCommand
+func (_e *FakeDashboardStore_Expecter) RestoreDashboard(ctx interface{}, cmd interface{}) *FakeDashboardStore_RestoreDashboard_Call {
+	return &FakeDashboardStore_RestoreDashboard_Call{Call: _e.mock.On("RestoreDashboard", ctx, cmd)}
+}
+
+func (_c *FakeDashboardStore_RestoreDashboard_Call) Run(run func(ctx context.Context, cmd *RestoreDashboardCommand)) *FakeDashboardStore_RestoreDashboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*RestoreDashboardCommand))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardStore_RestoreDashboard_Call) Return(_a0 *Dashboard, _a1 error) *FakeDashboardStore_RestoreDashboard_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FakeDashboardStore_RestoreDashboard_Call) RunAndReturn(run func(context.Context, *RestoreDashboardCommand) (*Dashboard, error)) *FakeDashboardStore_RestoreDashboard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -1118,6 +1281,92 @@ func (_c *FakeDashboardStore_SaveProvisionedDashboard_Call) RunAndReturn(run fun
 	return _c
 }
 
+// SoftDeleteDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) SoftDeleteDashboard(ctx context.Context, cmd *DeleteDashboardCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FakeDashboardStore_SoftDeleteDashboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteDashboard'
+type FakeDashboardStore_SoftDeleteDashboard_Call struct {
+	*mock.Call
+}
+
+// SoftDeleteDashboard is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *DeleteDashboardCommand
+func (_e *FakeDashboardStore_Expecter) SoftDeleteDashboard(ctx interface{}, cmd interface{}) *FakeDashboardStore_SoftDeleteDashboard_Call {
+	return &FakeDashboardStore_SoftDeleteDashboard_Call{Call: _e.mock.On("SoftDeleteDashboard", ctx, cmd)}
+}
+
+func (_c *FakeDashboardStore_SoftDeleteDashboard_Call) Run(run func(ctx context.Context, cmd *DeleteDashboardCommand)) *FakeDashboardStore_SoftDeleteDashboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*DeleteDashboardCommand))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardStore_SoftDeleteDashboard_Call) Return(_a0 error) *FakeDashboardStore_SoftDeleteDashboard_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FakeDashboardStore_SoftDeleteDashboard_Call) RunAndReturn(run func(context.Context, *DeleteDashboardCommand) error) *FakeDashboardStore_SoftDeleteDashboard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SoftDeleteDashboardsInFolder provides a mock function with given fields: ctx, request
+func (_m *FakeDashboardStore) SoftDeleteDashboardsInFolder(ctx context.Context, request *DeleteDashboardsInFolderRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardsInFolderRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FakeDashboardStore_SoftDeleteDashboardsInFolder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteDashboardsInFolder'
+type FakeDashboardStore_SoftDeleteDashboardsInFolder_Call struct {
+	*mock.Call
+}
+
+// SoftDeleteDashboardsInFolder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *DeleteDashboardsInFolderRequest
+func (_e *FakeDashboardStore_Expecter) SoftDeleteDashboardsInFolder(ctx interface{}, request interface{}) *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call {
+	return &FakeDashboardStore_SoftDeleteDashboardsInFolder_Call{Call: _e.mock.On("SoftDeleteDashboardsInFolder", ctx, request)}
+}
+
+func (_c *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call) Run(run func(ctx context.Context, request *DeleteDashboardsInFolderRequest)) *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*DeleteDashboardsInFolderRequest))
+	})
+	return _c
+}
+
+func (_c *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call) Return(_a0 error) *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call) RunAndReturn(run func(context.Context, *DeleteDashboardsInFolderRequest) error) *FakeDashboardStore_SoftDeleteDashboardsInFolder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UnprovisionDashboard provides a mock function with given fields: ctx, id
 func (_m *FakeDashboardStore) UnprovisionDashboard(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -449,10 +449,18 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 	if cmd.OrgID < 1 {
 		return folder.ErrBadRequest.Errorf("invalid orgID")
 	}
+	// folders moved to the trash keep their nested folder entry so that they are restored in place
+	trash := s.cfg.DashboardTrashRetention > 0
 	result := []string{cmd.UID}
 	err := s.db.InTransaction(ctx, func(ctx context.Context) error {
 		if s.features.IsEnabled(featuremgmt.FlagNestedFolders) {
-			subfolders, err := s.nestedFolderDelete(ctx, cmd)
+			var subfolders []string
+			var err error
+			if trash {
+				subfolders, err = s.getDescendantUIDs(ctx, cmd.OrgID, cmd.UID)
+			} else {
+				subfolders, err = s.nestedFolderDelete(ctx, cmd)
+			}
 
 			if err != nil {
 				logger.Error("the delete folder on folder table failed with err: ", "error", err)
@@ -479,6 +487,17 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 				return dashboards.ErrFolderAccessDenied
 			}
 
+			if trash {
+				// the dashboards of the folder are moved to the trash along with it
+				if err := s.legacySoftDelete(ctx, cmd, dashFolder); err != nil {
+					return err
+				}
+				if err := s.deleteChildrenInFolder(ctx, dashFolder.OrgID, dashFolder.UID); err != nil {
+					return err
+				}
+				continue
+			}
+
 			if err := s.deleteChildrenInFolder(ctx, dashFolder.OrgID, dashFolder.UID); err != nil {
 				return err
 			}
@@ -512,6 +531,34 @@ func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderComm
 	return nil
 }
 
+func (s *Service) legacySoftDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, dashFolder *folder.Folder) error {
+	deleteCmd := dashboards.DeleteDashboardCommand{OrgID: cmd.OrgID, ID: dashFolder.ID, ForceDeleteFolderRules: cmd.ForceDeleteRules}
+
+	if err := s.dashboardStore.SoftDeleteDashboard(ctx, &deleteCmd); err != nil {
+		return toFolderError(err)
+	}
+	return nil
+}
+
+// getDescendantUIDs returns the UIDs of all the nested folders under the folder.
+func (s *Service) getDescendantUIDs(ctx context.Context, orgID int64, uid string) ([]string, error) {
+	children, err := s.store.GetChildren(ctx, folder.GetChildrenQuery{UID: uid, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, f := range children {
+		result = append(result, f.UID)
+		descendants, err := s.getDescendantUIDs(ctx, orgID, f.UID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, descendants...)
+	}
+	return result, nil
+}
+
 func (s *Service) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error) {
 	if cmd.SignedInUser == nil {
 		return nil, folder.ErrBadRequest.Errorf("missing signed in user")
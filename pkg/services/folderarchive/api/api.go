@@ -0,0 +1,182 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folderarchive"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type FolderArchiveAPI struct {
+	folderArchiveService folderarchive.Service
+	ac                   accesscontrol.AccessControl
+}
+
+func New(folderArchiveService folderarchive.Service, ac accesscontrol.AccessControl) *FolderArchiveAPI {
+	return &FolderArchiveAPI{
+		folderArchiveService: folderArchiveService,
+		ac:                   ac,
+	}
+}
+
+func (api *FolderArchiveAPI) RegisterAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(api.ac)
+	uidScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(accesscontrol.Parameter(":uid"))
+	routeRegister.Group("/api/folders", func(route routing.RouteRegister) {
+		route.Get(
+			"/:uid/export",
+			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionFoldersRead, uidScope)),
+			routing.Wrap(api.ExportFolder),
+		)
+		route.Post(
+			"/import",
+			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionFoldersCreate)),
+			routing.Wrap(api.ImportFolder),
+		)
+		route.Post(
+			"/move",
+			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionFoldersWrite)),
+			routing.Wrap(api.MoveFolderContents),
+		)
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route GET /folders/{folder_uid}/export folders exportFolder
+//
+// Export a folder with its subfolders and their contents.
+//
+// The archive contains the dashboards, library panels and alert rules of the folders, the permissions
+// managed on the folders and dashboards, and the data sources they use.
+//
+// Responses:
+// 200: exportFolderResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *FolderArchiveAPI) ExportFolder(c *contextmodel.ReqContext) response.Response {
+	archive, err := api.folderArchiveService.Export(c.Req.Context(), &folderarchive.ExportQuery{
+		OrgID: c.OrgID,
+		UID:   web.Params(c.Req)[":uid"],
+		User:  c.SignedInUser,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
+	if c.QueryBoolWithDefault("download", false) {
+		return response.JSONDownload(http.StatusOK, archive, "folder-"+archive.FolderUID+".json")
+	}
+	return response.JSON(http.StatusOK, archive)
+}
+
+// swagger:route POST /folders/import folders importFolder
+//
+// Import a folder archive.
+//
+// Data sources of the archive are replaced with the ones they are mapped to, or else with the data
+// sources of the organization with the same UID, or the same name and type. With `dryRun` the import is
+// only validated. Nothing is imported when the report has errors.
+//
+// Responses:
+// 200: importFolderResponse
+// 400: importFolderResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *FolderArchiveAPI) ImportFolder(c *contextmodel.ReqContext) response.Response {
+	cmd := folderarchive.ImportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.User = c.SignedInUser
+
+	report, err := api.folderArchiveService.Import(c.Req.Context(), &cmd)
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	if !cmd.DryRun && !report.Valid() {
+		return response.JSON(http.StatusBadRequest, report)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route POST /folders/move folders moveFolderContents
+//
+// Move folders and dashboards into a folder.
+//
+// Each folder and dashboard is moved with the same permission checks as when it is moved on its own.
+// The ones that cannot be moved are reported and do not stop the others from being moved.
+//
+// Responses:
+// 200: moveFolderContentsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *FolderArchiveAPI) MoveFolderContents(c *contextmodel.ReqContext) response.Response {
+	cmd := folderarchive.MoveCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.User = c.SignedInUser
+
+	result, err := api.folderArchiveService.Move(c.Req.Context(), &cmd)
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters exportFolder
+type ExportFolderParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// Set the Content-Disposition header so that the archive is downloaded.
+	// in:query
+	// required:false
+	Download bool `json:"download"`
+}
+
+// swagger:response exportFolderResponse
+type ExportFolderResponse struct {
+	// in: body
+	Body folderarchive.Archive `json:"body"`
+}
+
+// swagger:parameters importFolder
+type ImportFolderParams struct {
+	// in:body
+	// required:true
+	Body folderarchive.ImportCommand
+}
+
+// swagger:response importFolderResponse
+type ImportFolderResponse struct {
+	// in: body
+	Body folderarchive.ImportReport `json:"body"`
+}
+
+// swagger:parameters moveFolderContents
+type MoveFolderContentsParams struct {
+	// in:body
+	// required:true
+	Body folderarchive.MoveCommand
+}
+
+// swagger:response moveFolderContentsResponse
+type MoveFolderContentsResponse struct {
+	// in: body
+	Body folderarchive.MoveResult `json:"body"`
+}
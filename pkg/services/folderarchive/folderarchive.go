@@ -0,0 +1,200 @@
+package folderarchive
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// ArchiveVersion is the version of the archive format written by Export.
+const ArchiveVersion = 1
+
+var (
+	ErrUnsupportedArchiveVersion = errutil.NewBase(errutil.StatusBadRequest, "folderarchive.unsupportedVersion", errutil.WithPublicMessage("Unsupported archive version"))
+	ErrInvalidArchive            = errutil.NewBase(errutil.StatusBadRequest, "folderarchive.invalidArchive", errutil.WithPublicMessage("Invalid archive"))
+)
+
+// Archive is a folder with its subfolders and their contents, as exported from one
+// organization to be imported into another organization or instance.
+type Archive struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	// FolderUID is the UID of the exported folder, the root of the archive.
+	FolderUID       string                  `json:"folderUid"`
+	Folders         []ArchiveFolder         `json:"folders"`
+	Dashboards      []ArchiveDashboard      `json:"dashboards"`
+	LibraryElements []ArchiveLibraryElement `json:"libraryElements"`
+	AlertRules      []ArchiveAlertRule      `json:"alertRules"`
+	// Datasources are the data sources queried by the dashboards, library elements and alert rules.
+	Datasources []ArchiveDatasource `json:"datasources"`
+}
+
+// ArchiveFolder is a folder of an archive. Parent folders are listed before their subfolders.
+type ArchiveFolder struct {
+	UID         string              `json:"uid"`
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	ParentUID   string              `json:"parentUid,omitempty"`
+	Permissions []ArchivePermission `json:"permissions,omitempty"`
+}
+
+type ArchiveDashboard struct {
+	UID         string              `json:"uid"`
+	FolderUID   string              `json:"folderUid"`
+	Dashboard   *simplejson.Json    `json:"dashboard"`
+	Permissions []ArchivePermission `json:"permissions,omitempty"`
+}
+
+type ArchiveLibraryElement struct {
+	UID       string          `json:"uid"`
+	FolderUID string          `json:"folderUid"`
+	Name      string          `json:"name"`
+	Kind      int64           `json:"kind"`
+	Model     json.RawMessage `json:"model"`
+}
+
+type ArchiveAlertRule struct {
+	UID             string                       `json:"uid"`
+	FolderUID       string                       `json:"folderUid"`
+	Title           string                       `json:"title"`
+	RuleGroup       string                       `json:"ruleGroup"`
+	RuleGroupIndex  int                          `json:"ruleGroupIndex"`
+	IntervalSeconds int64                        `json:"intervalSeconds"`
+	Condition       string                       `json:"condition"`
+	Data            []ngmodels.AlertQuery        `json:"data"`
+	NoDataState     ngmodels.NoDataState         `json:"noDataState"`
+	ExecErrState    ngmodels.ExecutionErrorState `json:"execErrState"`
+	For             string                       `json:"for"`
+	Annotations     map[string]string            `json:"annotations,omitempty"`
+	Labels          map[string]string            `json:"labels,omitempty"`
+	IsPaused        bool                         `json:"isPaused"`
+}
+
+type ArchiveDatasource struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ArchivePermission is a permission granted on a folder or dashboard to a user, a team or a basic role.
+// Users and teams are referenced by login and name as their IDs differ between organizations.
+type ArchivePermission struct {
+	UserLogin  string `json:"userLogin,omitempty"`
+	Team       string `json:"team,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Permission string `json:"permission"`
+}
+
+type ExportQuery struct {
+	OrgID int64
+	UID   string
+	User  *user.SignedInUser
+}
+
+type ImportCommand struct {
+	Archive *Archive `json:"archive"`
+	// ParentUID is the folder to import the root folder of the archive into, the root level if empty.
+	ParentUID string `json:"parentUid"`
+	// Datasources maps the UIDs of the data sources of the archive to the UIDs of data sources of
+	// the organization. Unmapped data sources are matched by UID and then by name and type.
+	Datasources map[string]string `json:"datasources"`
+	// Overwrite updates the folders, dashboards, library elements and alert rules that already exist.
+	Overwrite bool `json:"overwrite"`
+	// DryRun validates the import without changing anything.
+	DryRun bool `json:"dryRun"`
+
+	OrgID int64              `json:"-"`
+	User  *user.SignedInUser `json:"-"`
+}
+
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+)
+
+// ImportReport describes what an import does, or would do on a dry run.
+type ImportReport struct {
+	DryRun          bool                `json:"dryRun"`
+	Imported        bool                `json:"imported"`
+	Folders         []ImportItem        `json:"folders"`
+	Dashboards      []ImportItem        `json:"dashboards"`
+	LibraryElements []ImportItem        `json:"libraryElements"`
+	AlertRules      []ImportItem        `json:"alertRules"`
+	Datasources     []DatasourceMapping `json:"datasources"`
+	// Errors prevent the import.
+	Errors []string `json:"errors"`
+	// Warnings are about parts of the archive that are skipped, like permissions of unknown users.
+	Warnings []string `json:"warnings"`
+}
+
+// Valid reports whether the archive can be imported.
+func (r *ImportReport) Valid() bool {
+	if len(r.Errors) > 0 {
+		return false
+	}
+	for _, items := range [][]ImportItem{r.Folders, r.Dashboards, r.LibraryElements, r.AlertRules} {
+		for _, item := range items {
+			if item.Error != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type ImportItem struct {
+	UID       string       `json:"uid"`
+	Title     string       `json:"title"`
+	FolderUID string       `json:"folderUid,omitempty"`
+	Action    ImportAction `json:"action,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// DatasourceMapping is the data source of the organization a data source of the archive is replaced with.
+type DatasourceMapping struct {
+	UID       string `json:"uid"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	TargetUID string `json:"targetUid,omitempty"`
+	// MatchedBy is how the target was found: mapping, uid or name.
+	MatchedBy string `json:"matchedBy,omitempty"`
+}
+
+type MoveCommand struct {
+	FolderUIDs    []string `json:"folderUids"`
+	DashboardUIDs []string `json:"dashboardUids"`
+	// ParentUID is the folder to move to, the root level if empty.
+	ParentUID string `json:"parentUid"`
+
+	OrgID int64              `json:"-"`
+	User  *user.SignedInUser `json:"-"`
+}
+
+type MoveResult struct {
+	Folders    []MoveItem `json:"folders"`
+	Dashboards []MoveItem `json:"dashboards"`
+}
+
+type MoveItem struct {
+	UID   string `json:"uid"`
+	Error string `json:"error,omitempty"`
+}
+
+// Service exports folder subtrees into archives, imports them and moves folders and dashboards in bulk.
+type Service interface {
+	// Export creates an archive of a folder, its subfolders and the dashboards, library elements,
+	// alert rules and permissions in them that the user can read.
+	Export(ctx context.Context, query *ExportQuery) (*Archive, error)
+	// Import creates or updates the contents of an archive, or validates them on a dry run.
+	Import(ctx context.Context, cmd *ImportCommand) (*ImportReport, error)
+	// Move moves folders and dashboards into another folder. Items that cannot be moved are reported
+	// without stopping the others from being moved.
+	Move(ctx context.Context, cmd *MoveCommand) (*MoveResult, error)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folderarchive"
+)
+
+// visitDatasourceRefs calls visit for every data source reference of a dashboard or panel model,
+// like the datasource of panels, targets and template variables. References to template variables
+// and to built-in data sources, like the Grafana, Mixed and Dashboard data sources, are skipped.
+func visitDatasourceRefs(v interface{}, visit func(ref map[string]interface{})) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ref, ok := child.(map[string]interface{}); ok && key == "datasource" && isExternalRef(ref) {
+				visit(ref)
+				continue
+			}
+			visitDatasourceRefs(child, visit)
+		}
+	case []interface{}:
+		for _, child := range value {
+			visitDatasourceRefs(child, visit)
+		}
+	}
+}
+
+func isExternalRef(ref map[string]interface{}) bool {
+	uid, _ := ref["uid"].(string)
+	refType, _ := ref["type"].(string)
+	return uid != "" && !strings.HasPrefix(uid, "$") && uid != expr.DatasourceUID && uid != expr.OldDatasourceUID && refType != "datasource"
+}
+
+// collectDatasourceUIDs adds the UIDs of the data sources referenced by model to uids.
+func collectDatasourceUIDs(model interface{}, uids map[string]bool) {
+	visitDatasourceRefs(model, func(ref map[string]interface{}) {
+		uids[ref["uid"].(string)] = true
+	})
+}
+
+// remapDatasourceRefs replaces the data source UIDs of the references of model.
+func remapDatasourceRefs(model interface{}, mapping map[string]string) {
+	visitDatasourceRefs(model, func(ref map[string]interface{}) {
+		if target, ok := mapping[ref["uid"].(string)]; ok {
+			ref["uid"] = target
+		}
+	})
+}
+
+func remapDashboard(dash *simplejson.Json, mapping map[string]string) (*simplejson.Json, error) {
+	// copy the dashboard so that the archive is left as it was
+	b, err := dash.Encode()
+	if err != nil {
+		return nil, err
+	}
+	remapped, err := simplejson.NewJson(b)
+	if err != nil {
+		return nil, err
+	}
+	remapDatasourceRefs(remapped.Interface(), mapping)
+	return remapped, nil
+}
+
+func remapRawModel(model json.RawMessage, mapping map[string]string) (json.RawMessage, error) {
+	if len(model) == 0 {
+		return model, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(model, &v); err != nil {
+		return nil, err
+	}
+	remapDatasourceRefs(v, mapping)
+	return json.Marshal(v)
+}
+
+// archiveDatasourceUIDs lists the data sources referenced by the contents of an archive.
+func archiveDatasourceUIDs(archive *folderarchive.Archive) (map[string]bool, error) {
+	uids := map[string]bool{}
+	for _, d := range archive.Dashboards {
+		if d.Dashboard != nil {
+			collectDatasourceUIDs(d.Dashboard.Interface(), uids)
+		}
+	}
+	for _, e := range archive.LibraryElements {
+		if len(e.Model) == 0 {
+			continue
+		}
+		var model interface{}
+		if err := json.Unmarshal(e.Model, &model); err != nil {
+			return nil, fmt.Errorf("library element %q: %w", e.UID, err)
+		}
+		collectDatasourceUIDs(model, uids)
+	}
+	for _, r := range archive.AlertRules {
+		for _, q := range r.Data {
+			if isExpression, _ := q.IsExpression(); !isExpression && q.DatasourceUID != "" {
+				uids[q.DatasourceUID] = true
+			}
+		}
+	}
+	return uids, nil
+}
+
+// resolveDatasources finds the data sources of the organization to replace the data sources of the archive with.
+// A data source is replaced with the one it is mapped to, or else with the data source with the same UID,
+// or else with the data source with the same name and type.
+func (s *FolderArchiveService) resolveDatasources(ctx context.Context, cmd *folderarchive.ImportCommand, report *folderarchive.ImportReport) (map[string]string, error) {
+	referenced, err := archiveDatasourceUIDs(cmd.Archive)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]folderarchive.ArchiveDatasource{}
+	for _, ds := range cmd.Archive.Datasources {
+		known[ds.UID] = ds
+	}
+
+	mapping := map[string]string{}
+	for _, uid := range sortedKeys(referenced) {
+		ds, ok := known[uid]
+		if !ok {
+			ds = folderarchive.ArchiveDatasource{UID: uid}
+		}
+		m := folderarchive.DatasourceMapping{UID: ds.UID, Name: ds.Name, Type: ds.Type}
+
+		if target, ok := cmd.Datasources[uid]; ok {
+			if _, err := s.getDatasource(ctx, &datasources.GetDataSourceQuery{UID: target, OrgID: cmd.OrgID}); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("data source %q is mapped to data source %q that does not exist", uid, target))
+			} else {
+				m.TargetUID, m.MatchedBy = target, "mapping"
+			}
+		} else if target, err := s.getDatasource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: cmd.OrgID}); err == nil {
+			m.TargetUID, m.MatchedBy = target.UID, "uid"
+		} else if target, err := s.getDatasource(ctx, &datasources.GetDataSourceQuery{Name: ds.Name, OrgID: cmd.OrgID}); ds.Name != "" && err == nil && target.Type == ds.Type {
+			m.TargetUID, m.MatchedBy = target.UID, "name"
+		} else {
+			report.Errors = append(report.Errors, fmt.Sprintf("data source %q (%s) not found, map it to an existing data source", uid, ds.Name))
+		}
+
+		if m.TargetUID != "" && m.TargetUID != uid {
+			mapping[uid] = m.TargetUID
+		}
+		report.Datasources = append(report.Datasources, m)
+	}
+	return mapping, nil
+}
+
+func (s *FolderArchiveService) getDatasource(ctx context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error) {
+	if query.UID == "" && query.Name == "" {
+		return nil, datasources.ErrDataSourceNotFound
+	}
+	ds, err := s.datasourceService.GetDataSource(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if ds == nil {
+		return nil, datasources.ErrDataSourceNotFound
+	}
+	return ds, nil
+}
+
+// exportDatasources describes the data sources referenced by the contents of an archive.
+func (s *FolderArchiveService) exportDatasources(ctx context.Context, orgID int64, archive *folderarchive.Archive) error {
+	uids, err := archiveDatasourceUIDs(archive)
+	if err != nil {
+		return err
+	}
+	for _, uid := range sortedKeys(uids) {
+		ds, err := s.getDatasource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: orgID})
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			// the reference is broken, it is kept as is and has to be mapped on import
+			archive.Datasources = append(archive.Datasources, folderarchive.ArchiveDatasource{UID: uid})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		archive.Datasources = append(archive.Datasources, folderarchive.ArchiveDatasource{UID: ds.UID, Name: ds.Name, Type: ds.Type})
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folderarchive"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const exportPageSize = 1000
+
+// Export creates an archive of a folder and its subfolders. The contents are the ones the user can read:
+// permissions are only exported when the user can read them and alert rules when the user can read the
+// rules of their folder.
+func (s *FolderArchiveService) Export(ctx context.Context, query *folderarchive.ExportQuery) (*folderarchive.Archive, error) {
+	root, err := s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &query.UID, OrgID: query.OrgID, SignedInUser: query.User})
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &folderarchive.Archive{
+		Version:         folderarchive.ArchiveVersion,
+		ExportedAt:      time.Now(),
+		FolderUID:       root.UID,
+		Folders:         []folderarchive.ArchiveFolder{},
+		Dashboards:      []folderarchive.ArchiveDashboard{},
+		LibraryElements: []folderarchive.ArchiveLibraryElement{},
+		AlertRules:      []folderarchive.ArchiveAlertRule{},
+		Datasources:     []folderarchive.ArchiveDatasource{},
+	}
+
+	folders, err := s.getSubtree(ctx, query, root)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range folders {
+		archiveFolder := folderarchive.ArchiveFolder{UID: f.UID, Title: f.Title, Description: f.Description, ParentUID: f.ParentUID}
+		if i == 0 {
+			archiveFolder.ParentUID = ""
+		}
+		if archiveFolder.Permissions, err = s.exportPermissions(ctx, query.User, s.folderPermissionsService, dashboards.ActionFoldersPermissionsRead,
+			dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID), f.UID); err != nil {
+			return nil, err
+		}
+		archive.Folders = append(archive.Folders, archiveFolder)
+	}
+
+	if err := s.exportDashboards(ctx, query, folders, archive); err != nil {
+		return nil, err
+	}
+	if err := s.exportLibraryElements(ctx, query, folders, archive); err != nil {
+		return nil, err
+	}
+	if err := s.exportAlertRules(ctx, query, folders, archive); err != nil {
+		return nil, err
+	}
+	if err := s.exportDatasources(ctx, query.OrgID, archive); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// getSubtree lists a folder and its subfolders, parents before their children. Subfolders only exist
+// when nested folders are enabled.
+func (s *FolderArchiveService) getSubtree(ctx context.Context, query *folderarchive.ExportQuery, root *folder.Folder) ([]*folder.Folder, error) {
+	folders := []*folder.Folder{root}
+	if !s.features.IsEnabled(featuremgmt.FlagNestedFolders) {
+		return folders, nil
+	}
+
+	for i := 0; i < len(folders); i++ {
+		children, err := s.folderService.GetChildren(ctx, &folder.GetChildrenQuery{UID: folders[i].UID, OrgID: query.OrgID, SignedInUser: query.User})
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, children...)
+	}
+	return folders, nil
+}
+
+func (s *FolderArchiveService) exportDashboards(ctx context.Context, query *folderarchive.ExportQuery, folders []*folder.Folder, archive *folderarchive.Archive) error {
+	folderIDs := make([]int64, 0, len(folders))
+	for _, f := range folders {
+		folderIDs = append(folderIDs, f.ID)
+	}
+
+	for page := int64(1); ; page++ {
+		hits, err := s.dashboardService.FindDashboards(ctx, &dashboards.FindPersistedDashboardsQuery{
+			OrgId:        query.OrgID,
+			SignedInUser: query.User,
+			FolderIds:    folderIDs,
+			Type:         searchstore.TypeDashboard,
+			Limit:        exportPageSize,
+			Page:         page,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range hits {
+			dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: hit.UID, OrgID: query.OrgID})
+			if err != nil {
+				return err
+			}
+			data := dash.Data
+			// ids and versions are not kept between organizations
+			data.Del("id")
+			data.Del("version")
+
+			permissions, err := s.exportPermissions(ctx, query.User, s.dashboardPermissionsService, dashboards.ActionDashboardsPermissionsRead,
+				dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dash.UID), dash.UID)
+			if err != nil {
+				return err
+			}
+			archive.Dashboards = append(archive.Dashboards, folderarchive.ArchiveDashboard{
+				UID:         dash.UID,
+				FolderUID:   hit.FolderUID,
+				Dashboard:   data,
+				Permissions: permissions,
+			})
+		}
+
+		if len(hits) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func (s *FolderArchiveService) exportLibraryElements(ctx context.Context, query *folderarchive.ExportQuery, folders []*folder.Folder, archive *folderarchive.Archive) error {
+	uids := make([]string, 0, len(folders))
+	for _, f := range folders {
+		uids = append(uids, f.UID)
+	}
+
+	for page := 1; ; page++ {
+		result, err := s.libraryElementService.GetAllElements(ctx, query.User, model.SearchLibraryElementsQuery{
+			FolderFilterUIDs: strings.Join(uids, ","),
+			PerPage:          exportPageSize,
+			Page:             page,
+		})
+		if err != nil {
+			return err
+		}
+		for _, e := range result.Elements {
+			archive.LibraryElements = append(archive.LibraryElements, folderarchive.ArchiveLibraryElement{
+				UID:       e.UID,
+				FolderUID: e.FolderUID,
+				Name:      e.Name,
+				Kind:      e.Kind,
+				Model:     e.Model,
+			})
+		}
+		if len(result.Elements) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func (s *FolderArchiveService) exportAlertRules(ctx context.Context, query *folderarchive.ExportQuery, folders []*folder.Folder, archive *folderarchive.Archive) error {
+	uids := make([]string, 0, len(folders))
+	for _, f := range folders {
+		canRead, err := s.ac.Evaluate(ctx, query.User, accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)))
+		if err != nil {
+			return err
+		}
+		if canRead {
+			uids = append(uids, f.UID)
+		}
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	rules, err := s.alertRuleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: query.OrgID, NamespaceUIDs: uids})
+	if err != nil {
+		return err
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.NamespaceUID != b.NamespaceUID {
+			return a.NamespaceUID < b.NamespaceUID
+		}
+		if a.RuleGroup != b.RuleGroup {
+			return a.RuleGroup < b.RuleGroup
+		}
+		return a.RuleGroupIndex < b.RuleGroupIndex
+	})
+
+	for _, r := range rules {
+		archive.AlertRules = append(archive.AlertRules, folderarchive.ArchiveAlertRule{
+			UID:             r.UID,
+			FolderUID:       r.NamespaceUID,
+			Title:           r.Title,
+			RuleGroup:       r.RuleGroup,
+			RuleGroupIndex:  r.RuleGroupIndex,
+			IntervalSeconds: r.IntervalSeconds,
+			Condition:       r.Condition,
+			Data:            r.Data,
+			NoDataState:     r.NoDataState,
+			ExecErrState:    r.ExecErrState,
+			For:             r.For.String(),
+			Annotations:     r.Annotations,
+			Labels:          r.Labels,
+			IsPaused:        r.IsPaused,
+		})
+	}
+	return nil
+}
+
+// exportPermissions lists the permissions managed on a folder or dashboard, if the user can read them.
+func (s *FolderArchiveService) exportPermissions(ctx context.Context, usr *user.SignedInUser, service accesscontrol.PermissionsService,
+	action, scope, uid string) ([]folderarchive.ArchivePermission, error) {
+	canRead, err := s.ac.Evaluate(ctx, usr, accesscontrol.EvalPermission(action, scope))
+	if err != nil || !canRead {
+		return nil, err
+	}
+
+	current, err := service.GetPermissions(ctx, usr, uid)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]folderarchive.ArchivePermission, 0, len(current))
+	for _, p := range current {
+		// permissions granted through roles or inherited from the folder are not managed on the resource
+		if !p.IsManaged || p.IsInherited {
+			continue
+		}
+		permission := service.MapActions(p)
+		if permission == "" {
+			continue
+		}
+		permissions = append(permissions, folderarchive.ArchivePermission{
+			UserLogin:  p.UserLogin,
+			Team:       p.Team,
+			Role:       p.BuiltInRole,
+			Permission: permission,
+		})
+	}
+	return permissions, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
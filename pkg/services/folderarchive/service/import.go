@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folderarchive"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// importPlan holds what was found while validating an import and is needed to carry it out.
+type importPlan struct {
+	datasources map[string]string
+	// parents maps the folders of the archive to the folders to create them in.
+	parents map[string]string
+	// existing folders, dashboards, library elements and alert rules by UID
+	folders         map[string]*folder.Folder
+	dashboards      map[string]bool
+	libraryElements map[string]model.LibraryElementDTO
+	alertRules      map[string]*ngmodels.AlertRule
+	permissions     map[string][]accesscontrol.SetResourcePermissionCommand
+}
+
+// Import validates an archive and, unless it is a dry run, creates or updates its contents in a single
+// transaction. The report lists the errors that prevent the import. Nothing is changed when there is one.
+func (s *FolderArchiveService) Import(ctx context.Context, cmd *folderarchive.ImportCommand) (*folderarchive.ImportReport, error) {
+	if cmd.Archive == nil || len(cmd.Archive.Folders) == 0 || cmd.Archive.Folders[0].UID != cmd.Archive.FolderUID {
+		return nil, folderarchive.ErrInvalidArchive.Errorf("the archive does not start with its root folder")
+	}
+	if cmd.Archive.Version != folderarchive.ArchiveVersion {
+		return nil, folderarchive.ErrUnsupportedArchiveVersion.Errorf("unsupported archive version %d", cmd.Archive.Version)
+	}
+
+	report := &folderarchive.ImportReport{
+		DryRun:          cmd.DryRun,
+		Folders:         []folderarchive.ImportItem{},
+		Dashboards:      []folderarchive.ImportItem{},
+		LibraryElements: []folderarchive.ImportItem{},
+		AlertRules:      []folderarchive.ImportItem{},
+		Datasources:     []folderarchive.DatasourceMapping{},
+		Errors:          []string{},
+		Warnings:        []string{},
+	}
+
+	plan, err := s.planImport(ctx, cmd, report)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.DryRun || !report.Valid() {
+		return report, nil
+	}
+
+	warnings := report.Warnings
+	err = s.sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		// the transaction is retried when the database is locked
+		report.Warnings = warnings[:len(warnings):len(warnings)]
+		return s.executeImport(ctx, cmd, plan, report)
+	})
+	if err != nil {
+		var itemErr *importError
+		if errors.As(err, &itemErr) {
+			itemErr.item.Error = itemErr.Error()
+		}
+		report.Errors = append(report.Errors, fmt.Sprintf("the import was rolled back: %s", err))
+		return report, nil
+	}
+	report.Imported = true
+	return report, nil
+}
+
+// importError is the failure of an item of the archive, which rolls back the whole import.
+type importError struct {
+	item *folderarchive.ImportItem
+	err  error
+}
+
+func (e *importError) Error() string {
+	return e.err.Error()
+}
+
+func (e *importError) Unwrap() error {
+	return e.err
+}
+
+func (s *FolderArchiveService) planImport(ctx context.Context, cmd *folderarchive.ImportCommand, report *folderarchive.ImportReport) (*importPlan, error) {
+	plan := &importPlan{
+		parents:         map[string]string{},
+		folders:         map[string]*folder.Folder{},
+		dashboards:      map[string]bool{},
+		libraryElements: map[string]model.LibraryElementDTO{},
+		alertRules:      map[string]*ngmodels.AlertRule{},
+		permissions:     map[string][]accesscontrol.SetResourcePermissionCommand{},
+	}
+
+	var err error
+	if plan.datasources, err = s.resolveDatasources(ctx, cmd, report); err != nil {
+		return nil, err
+	}
+	if err := s.planFolders(ctx, cmd, plan, report); err != nil {
+		return nil, err
+	}
+	if err := s.planDashboards(ctx, cmd, plan, report); err != nil {
+		return nil, err
+	}
+	if err := s.planLibraryElements(ctx, cmd, plan, report); err != nil {
+		return nil, err
+	}
+	if err := s.planAlertRules(ctx, cmd, plan, report); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *FolderArchiveService) planFolders(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport) error {
+	nested := s.features.IsEnabled(featuremgmt.FlagNestedFolders)
+	if cmd.ParentUID != "" {
+		if !nested {
+			report.Errors = append(report.Errors, "nested folders are not enabled, the archive can only be imported at the root level")
+		} else if _, err := s.getParentFolder(ctx, cmd.OrgID, cmd.ParentUID, cmd.User); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("parent folder %q: %s", cmd.ParentUID, err))
+		}
+	}
+
+	for i, f := range cmd.Archive.Folders {
+		item := folderarchive.ImportItem{UID: f.UID, Title: f.Title, FolderUID: f.ParentUID, Action: folderarchive.ImportActionCreate}
+		parentUID := f.ParentUID
+		if i == 0 {
+			parentUID, item.FolderUID = cmd.ParentUID, cmd.ParentUID
+		}
+
+		_, seen := plan.parents[f.UID]
+		_, parentSeen := plan.parents[parentUID]
+		switch {
+		case f.UID == "" || seen:
+			item.Error = "the folder UID is missing or not unique"
+		case i > 0 && !parentSeen:
+			item.Error = fmt.Sprintf("parent folder %q is not in the archive before the folder", parentUID)
+		case i > 0 && !nested:
+			item.Error = "nested folders are not enabled"
+		}
+		plan.parents[f.UID] = parentUID
+
+		if item.Error == "" {
+			existing, err := s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &f.UID, OrgID: cmd.OrgID, SignedInUser: cmd.User})
+			switch {
+			case err == nil && !cmd.Overwrite:
+				item.Error = "the folder already exists"
+			case err == nil:
+				item.Action = folderarchive.ImportActionUpdate
+				plan.folders[f.UID] = existing
+			case !isNotFound(err):
+				item.Error = err.Error()
+			}
+		}
+
+		if err := s.planPermissions(ctx, cmd, plan, report, f.UID, f.Permissions); err != nil {
+			return err
+		}
+		report.Folders = append(report.Folders, item)
+	}
+	return nil
+}
+
+func (s *FolderArchiveService) planDashboards(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport) error {
+	for _, d := range cmd.Archive.Dashboards {
+		item := folderarchive.ImportItem{UID: d.UID, FolderUID: d.FolderUID, Action: folderarchive.ImportActionCreate}
+		if _, ok := plan.parents[d.FolderUID]; !ok {
+			item.Error = fmt.Sprintf("folder %q is not in the archive", d.FolderUID)
+		}
+		if d.Dashboard == nil || d.UID == "" {
+			item.Error = "the dashboard or its UID is missing"
+			report.Dashboards = append(report.Dashboards, item)
+			continue
+		}
+		item.Title = d.Dashboard.Get("title").MustString()
+
+		if item.Error == "" {
+			_, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: d.UID, OrgID: cmd.OrgID})
+			switch {
+			case err == nil && !cmd.Overwrite:
+				item.Error = "the dashboard already exists"
+			case err == nil:
+				item.Action = folderarchive.ImportActionUpdate
+				plan.dashboards[d.UID] = true
+			case !isNotFound(err):
+				item.Error = err.Error()
+			}
+		}
+
+		if err := s.planPermissions(ctx, cmd, plan, report, d.UID, d.Permissions); err != nil {
+			return err
+		}
+		report.Dashboards = append(report.Dashboards, item)
+	}
+	return nil
+}
+
+func (s *FolderArchiveService) planLibraryElements(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport) error {
+	for _, e := range cmd.Archive.LibraryElements {
+		item := folderarchive.ImportItem{UID: e.UID, Title: e.Name, FolderUID: e.FolderUID, Action: folderarchive.ImportActionCreate}
+		if _, ok := plan.parents[e.FolderUID]; !ok {
+			item.Error = fmt.Sprintf("folder %q is not in the archive", e.FolderUID)
+		}
+
+		if item.Error == "" {
+			existing, err := s.libraryElementService.GetElement(ctx, cmd.User, e.UID)
+			switch {
+			case err == nil && !cmd.Overwrite:
+				item.Error = "the library element already exists"
+			case err == nil:
+				item.Action = folderarchive.ImportActionUpdate
+				plan.libraryElements[e.UID] = existing
+			case !errors.Is(err, model.ErrLibraryElementNotFound):
+				item.Error = err.Error()
+			}
+		}
+		report.LibraryElements = append(report.LibraryElements, item)
+	}
+	return nil
+}
+
+func (s *FolderArchiveService) planAlertRules(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport) error {
+	groupIntervals := map[string]int64{}
+	for _, r := range cmd.Archive.AlertRules {
+		item := folderarchive.ImportItem{UID: r.UID, Title: r.Title, FolderUID: r.FolderUID, Action: folderarchive.ImportActionCreate}
+		groupKey := r.FolderUID + "/" + r.RuleGroup
+		interval, inGroup := groupIntervals[groupKey]
+		_, inArchive := plan.parents[r.FolderUID]
+		_, forErr := time.ParseDuration(r.For)
+		switch {
+		case r.UID == "":
+			item.Error = "the alert rule UID is missing"
+		case !inArchive:
+			item.Error = fmt.Sprintf("folder %q is not in the archive", r.FolderUID)
+		case forErr != nil:
+			item.Error = fmt.Sprintf("invalid pending period %q", r.For)
+		case r.IntervalSeconds <= 0:
+			item.Error = "the interval must be positive"
+		case inGroup && interval != r.IntervalSeconds:
+			item.Error = fmt.Sprintf("the rules of group %q have different intervals", r.RuleGroup)
+		}
+		groupIntervals[groupKey] = r.IntervalSeconds
+
+		if item.Error == "" {
+			existing, err := s.alertRuleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{UID: r.UID, OrgID: cmd.OrgID})
+			switch {
+			case err == nil && !cmd.Overwrite:
+				item.Error = "the alert rule already exists"
+			case err == nil:
+				item.Action = folderarchive.ImportActionUpdate
+				plan.alertRules[r.UID] = existing
+			case !errors.Is(err, ngmodels.ErrAlertRuleNotFound):
+				item.Error = err.Error()
+			}
+		}
+
+		if existing, ok := plan.alertRules[r.UID]; ok && item.Error == "" {
+			provenance, err := s.alertRuleStore.GetProvenance(ctx, existing, cmd.OrgID)
+			if err != nil {
+				return err
+			}
+			if provenance != ngmodels.ProvenanceNone {
+				item.Error = fmt.Sprintf("the alert rule is provisioned (%s) and cannot be overwritten", provenance)
+			}
+		}
+
+		if item.Error == "" {
+			// the alert rule service does not check permissions, so they are checked here. Folders that are
+			// created by the import are administered by the user.
+			action := accesscontrol.ActionAlertingRuleCreate
+			if item.Action == folderarchive.ImportActionUpdate {
+				action = accesscontrol.ActionAlertingRuleUpdate
+			}
+			evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleCreate)
+			if _, exists := plan.folders[r.FolderUID]; exists {
+				evaluator = accesscontrol.EvalPermission(action, dashboards.ScopeFoldersProvider.GetResourceScopeUID(r.FolderUID))
+			}
+			// an overwritten rule is also changed in the folder it is currently in
+			if existing, ok := plan.alertRules[r.UID]; ok {
+				evaluator = accesscontrol.EvalAll(evaluator,
+					accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleUpdate, dashboards.ScopeFoldersProvider.GetResourceScopeUID(existing.NamespaceUID)))
+			}
+			allowed, err := s.ac.Evaluate(ctx, cmd.User, evaluator)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				item.Error = "you are not allowed to save alert rules in the folder"
+			}
+		}
+		report.AlertRules = append(report.AlertRules, item)
+	}
+	return nil
+}
+
+// planPermissions resolves the users and teams of the permissions of a folder or dashboard.
+// Permissions of users and teams that do not exist are skipped with a warning.
+func (s *FolderArchiveService) planPermissions(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport,
+	uid string, permissions []folderarchive.ArchivePermission) error {
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(permissions))
+	for _, p := range permissions {
+		c := accesscontrol.SetResourcePermissionCommand{BuiltinRole: p.Role, Permission: p.Permission}
+		switch {
+		case p.UserLogin != "":
+			usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: p.UserLogin})
+			if errors.Is(err, user.ErrUserNotFound) {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("permission of %q: user %q not found", uid, p.UserLogin))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			c.UserID = usr.ID
+		case p.Team != "":
+			result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{OrgID: cmd.OrgID, Name: p.Team, Limit: 1, Page: 1, SignedInUser: cmd.User})
+			if err != nil {
+				return err
+			}
+			if len(result.Teams) == 0 {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("permission of %q: team %q not found", uid, p.Team))
+				continue
+			}
+			c.TeamID = result.Teams[0].ID
+		case p.Role == "":
+			continue
+		}
+		commands = append(commands, c)
+	}
+	if len(commands) > 0 {
+		plan.permissions[uid] = commands
+	}
+	return nil
+}
+
+// executeImport creates or updates the contents of the archive. It stops at the first failure, which is
+// returned as an importError so that the transaction of the import is rolled back.
+func (s *FolderArchiveService) executeImport(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport) error {
+	folderIDs := map[string]int64{}
+	for i, f := range cmd.Archive.Folders {
+		imported, err := s.importFolder(ctx, cmd, plan, f)
+		if err != nil {
+			return &importError{item: &report.Folders[i], err: err}
+		}
+		folderIDs[f.UID] = imported.ID
+		if err := s.importPermissions(ctx, cmd, plan, report, s.folderPermissionsService, dashboards.ActionFoldersPermissionsWrite,
+			dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID), f.UID, report.Folders[i].Action); err != nil {
+			return &importError{item: &report.Folders[i], err: err}
+		}
+	}
+
+	// library elements are imported before the dashboards that use them
+	for i, e := range cmd.Archive.LibraryElements {
+		if err := s.importLibraryElement(ctx, cmd, plan, e, folderIDs[e.FolderUID]); err != nil {
+			return &importError{item: &report.LibraryElements[i], err: err}
+		}
+	}
+
+	for i, d := range cmd.Archive.Dashboards {
+		dash, err := remapDashboard(d.Dashboard, plan.datasources)
+		if err == nil {
+			_, err = s.dashboardImportService.ImportDashboard(ctx, &dashboardimport.ImportDashboardRequest{
+				Dashboard: dash,
+				Overwrite: cmd.Overwrite,
+				FolderUid: d.FolderUID,
+				User:      cmd.User,
+			})
+		}
+		if err == nil {
+			err = s.importPermissions(ctx, cmd, plan, report, s.dashboardPermissionsService, dashboards.ActionDashboardsPermissionsWrite,
+				dashboards.ScopeDashboardsProvider.GetResourceScopeUID(d.UID), d.UID, report.Dashboards[i].Action)
+		}
+		if err != nil {
+			return &importError{item: &report.Dashboards[i], err: err}
+		}
+	}
+
+	return s.importAlertRules(ctx, cmd, plan, report)
+}
+
+func (s *FolderArchiveService) importFolder(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, f folderarchive.ArchiveFolder) (*folder.Folder, error) {
+	parentUID := plan.parents[f.UID]
+	existing, ok := plan.folders[f.UID]
+	if !ok {
+		return s.folderService.Create(ctx, &folder.CreateFolderCommand{
+			UID:          f.UID,
+			OrgID:        cmd.OrgID,
+			Title:        f.Title,
+			Description:  f.Description,
+			ParentUID:    parentUID,
+			SignedInUser: cmd.User,
+		})
+	}
+
+	updated, err := s.folderService.Update(ctx, &folder.UpdateFolderCommand{
+		UID:            f.UID,
+		OrgID:          cmd.OrgID,
+		NewTitle:       &f.Title,
+		NewDescription: &f.Description,
+		Overwrite:      true,
+		SignedInUser:   cmd.User,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.features.IsEnabled(featuremgmt.FlagNestedFolders) && existing.ParentUID != parentUID {
+		if _, err := s.folderService.Move(ctx, &folder.MoveFolderCommand{UID: f.UID, NewParentUID: parentUID, OrgID: cmd.OrgID, SignedInUser: cmd.User}); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+func (s *FolderArchiveService) importLibraryElement(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, e folderarchive.ArchiveLibraryElement, folderID int64) error {
+	elementModel, err := remapRawModel(e.Model, plan.datasources)
+	if err != nil {
+		return err
+	}
+
+	existing, ok := plan.libraryElements[e.UID]
+	if !ok {
+		_, err = s.libraryElementService.CreateElement(ctx, cmd.User, model.CreateLibraryElementCommand{
+			FolderID: folderID,
+			Name:     e.Name,
+			Model:    elementModel,
+			Kind:     e.Kind,
+			UID:      e.UID,
+		})
+		return err
+	}
+
+	_, err = s.libraryElementService.PatchElement(ctx, cmd.User, model.PatchLibraryElementCommand{
+		FolderID: folderID,
+		Name:     e.Name,
+		Model:    elementModel,
+		Kind:     e.Kind,
+		Version:  existing.Version,
+	}, e.UID)
+	return err
+}
+
+// importAlertRules saves the alert rules with the alerting rule service, then sets the intervals of their
+// groups as the service keeps the interval of existing groups.
+func (s *FolderArchiveService) importAlertRules(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport) error {
+	var groups []ngmodels.AlertRuleGroupKey
+	groupItems := map[ngmodels.AlertRuleGroupKey]int{}
+
+	for i, r := range cmd.Archive.AlertRules {
+		rule, err := toAlertRule(cmd.OrgID, r, plan.datasources)
+		if err == nil {
+			if _, ok := plan.alertRules[r.UID]; ok {
+				_, err = s.alertRuleService.UpdateAlertRule(ctx, rule, ngmodels.ProvenanceNone)
+			} else {
+				_, err = s.alertRuleService.CreateAlertRule(ctx, rule, ngmodels.ProvenanceNone, cmd.User.UserID)
+			}
+		}
+		if err != nil {
+			return &importError{item: &report.AlertRules[i], err: err}
+		}
+
+		key := rule.GetGroupKey()
+		if _, ok := groupItems[key]; !ok {
+			groups = append(groups, key)
+			groupItems[key] = i
+		}
+	}
+
+	for _, key := range groups {
+		i := groupItems[key]
+		if err := s.alertRuleService.UpdateRuleGroup(ctx, key.OrgID, key.NamespaceUID, key.RuleGroup, cmd.Archive.AlertRules[i].IntervalSeconds); err != nil {
+			return &importError{item: &report.AlertRules[i], err: err}
+		}
+	}
+	return nil
+}
+
+func toAlertRule(orgID int64, r folderarchive.ArchiveAlertRule, mapping map[string]string) (ngmodels.AlertRule, error) {
+	pending, err := time.ParseDuration(r.For)
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+
+	data := make([]ngmodels.AlertQuery, 0, len(r.Data))
+	for _, q := range r.Data {
+		if target, ok := mapping[q.DatasourceUID]; ok {
+			q.DatasourceUID = target
+		}
+		if q.Model, err = remapRawModel(q.Model, mapping); err != nil {
+			return ngmodels.AlertRule{}, err
+		}
+		data = append(data, q)
+	}
+
+	return ngmodels.AlertRule{
+		OrgID:           orgID,
+		UID:             r.UID,
+		NamespaceUID:    r.FolderUID,
+		Title:           r.Title,
+		RuleGroup:       r.RuleGroup,
+		RuleGroupIndex:  r.RuleGroupIndex,
+		IntervalSeconds: r.IntervalSeconds,
+		Condition:       r.Condition,
+		Data:            data,
+		NoDataState:     r.NoDataState,
+		ExecErrState:    r.ExecErrState,
+		For:             pending,
+		Annotations:     r.Annotations,
+		Labels:          r.Labels,
+		IsPaused:        r.IsPaused,
+		Updated:         time.Now(),
+		Version:         1,
+	}, nil
+}
+
+// importPermissions grants the permissions of the archive on an imported folder or dashboard. The user
+// administers the folders and dashboards they create, permissions of the ones that are updated are only
+// changed when the user can change them.
+func (s *FolderArchiveService) importPermissions(ctx context.Context, cmd *folderarchive.ImportCommand, plan *importPlan, report *folderarchive.ImportReport,
+	service accesscontrol.PermissionsService, action, scope, uid string, importAction folderarchive.ImportAction) error {
+	commands, ok := plan.permissions[uid]
+	if !ok {
+		return nil
+	}
+	if importAction == folderarchive.ImportActionUpdate {
+		allowed, err := s.ac.Evaluate(ctx, cmd.User, accesscontrol.EvalPermission(action, scope))
+		if err != nil || !allowed {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("permissions of %q were not imported: not allowed to change them", uid))
+			return nil
+		}
+	}
+	if _, err := service.SetPermissions(ctx, cmd.OrgID, uid, commands...); err != nil {
+		return fmt.Errorf("failed to import the permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folderarchive"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngprovisioning "github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// AlertRuleStore is the part of the alerting store used to export alert rules and to plan their import.
+type AlertRuleStore interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
+	GetProvenance(ctx context.Context, o ngmodels.Provisionable, org int64) (ngmodels.Provenance, error)
+}
+
+// AlertRuleService is the part of the alerting provisioning service used to import alert rules. It
+// validates the rules, checks their provenance and the alert rule quota.
+type AlertRuleService interface {
+	CreateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance, userID int64) (ngmodels.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance) (ngmodels.AlertRule, error)
+	UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, features featuremgmt.FeatureToggles, ac accesscontrol.AccessControl,
+	folderService folder.Service, dashboardService dashboards.DashboardService, dashboardImportService dashboardimport.Service,
+	libraryElementService libraryelements.Service, ruleStore *ngstore.DBstore, quotaService quota.Service, datasourceService datasources.DataSourceService,
+	folderPermissionsService accesscontrol.FolderPermissionsService, dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	userService user.Service, teamService team.Service,
+) *FolderArchiveService {
+	logger := log.New("folderarchive")
+	// the alert rules are imported the same way as through the alerting provisioning API
+	alertRuleService := ngprovisioning.NewAlertRuleService(ruleStore, ruleStore, dashboardService, quotaService, ruleStore,
+		int64(cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
+		int64(cfg.UnifiedAlerting.BaseInterval.Seconds()), logger)
+
+	s := &FolderArchiveService{
+		sqlStore:                    sqlStore,
+		features:                    features,
+		ac:                          ac,
+		folderService:               folderService,
+		dashboardService:            dashboardService,
+		dashboardImportService:      dashboardImportService,
+		libraryElementService:       libraryElementService,
+		alertRuleStore:              ruleStore,
+		alertRuleService:            alertRuleService,
+		datasourceService:           datasourceService,
+		folderPermissionsService:    folderPermissionsService,
+		dashboardPermissionsService: dashboardPermissionsService,
+		userService:                 userService,
+		teamService:                 teamService,
+		log:                         logger,
+	}
+
+	return s
+}
+
+type FolderArchiveService struct {
+	sqlStore                    db.DB
+	features                    featuremgmt.FeatureToggles
+	ac                          accesscontrol.AccessControl
+	folderService               folder.Service
+	dashboardService            dashboards.DashboardService
+	dashboardImportService      dashboardimport.Service
+	libraryElementService       libraryelements.Service
+	alertRuleStore              AlertRuleStore
+	alertRuleService            AlertRuleService
+	datasourceService           datasources.DataSourceService
+	folderPermissionsService    accesscontrol.FolderPermissionsService
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService
+	userService                 user.Service
+	teamService                 team.Service
+	log                         log.Logger
+}
+
+var _ folderarchive.Service = (*FolderArchiveService)(nil)
+
+// Move moves folders and dashboards into another folder. Folders are moved with the folder service
+// and dashboards are saved in their new folder, so the same permission checks apply as when they
+// are moved one at a time.
+func (s *FolderArchiveService) Move(ctx context.Context, cmd *folderarchive.MoveCommand) (*folderarchive.MoveResult, error) {
+	result := &folderarchive.MoveResult{Folders: []folderarchive.MoveItem{}, Dashboards: []folderarchive.MoveItem{}}
+
+	parent, err := s.getParentFolder(ctx, cmd.OrgID, cmd.ParentUID, cmd.User)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uid := range cmd.FolderUIDs {
+		item := folderarchive.MoveItem{UID: uid}
+		if _, err := s.folderService.Move(ctx, &folder.MoveFolderCommand{UID: uid, NewParentUID: cmd.ParentUID, OrgID: cmd.OrgID, SignedInUser: cmd.User}); err != nil {
+			item.Error = err.Error()
+		}
+		result.Folders = append(result.Folders, item)
+	}
+
+	for _, uid := range cmd.DashboardUIDs {
+		item := folderarchive.MoveItem{UID: uid}
+		if err := s.moveDashboard(ctx, cmd, uid, parent); err != nil {
+			item.Error = err.Error()
+		}
+		result.Dashboards = append(result.Dashboards, item)
+	}
+
+	return result, nil
+}
+
+func (s *FolderArchiveService) moveDashboard(ctx context.Context, cmd *folderarchive.MoveCommand, uid string, parent *folder.Folder) error {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: uid, OrgID: cmd.OrgID})
+	if err != nil {
+		return err
+	}
+	if dash.IsFolder {
+		return dashboards.ErrDashboardNotFound
+	}
+	if dash.FolderID == parent.ID {
+		return nil
+	}
+
+	saveCmd := dashboards.SaveDashboardCommand{
+		Dashboard: dash.Data,
+		OrgID:     cmd.OrgID,
+		UserID:    cmd.User.UserID,
+		FolderID:  parent.ID,
+		Message:   "Moved to " + parent.Title,
+	}
+	_, err = s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+		OrgID:     cmd.OrgID,
+		Dashboard: saveCmd.GetDashboardModel(),
+		Message:   saveCmd.Message,
+		User:      cmd.User,
+	}, false)
+	return err
+}
+
+// getParentFolder gets the folder to move or import into. The General folder is returned for an empty UID.
+func (s *FolderArchiveService) getParentFolder(ctx context.Context, orgID int64, uid string, usr *user.SignedInUser) (*folder.Folder, error) {
+	if uid == "" {
+		general := folder.GeneralFolder
+		return &general, nil
+	}
+	return s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &uid, OrgID: orgID, SignedInUser: usr})
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, dashboards.ErrDashboardNotFound)
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/db/dbtest"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/folderarchive"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRemapDatasourceRefs(t *testing.T) {
+	dash := simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{
+			map[string]interface{}{
+				"datasource": map[string]interface{}{"type": "prometheus", "uid": "prom"},
+				"targets": []interface{}{
+					map[string]interface{}{"datasource": map[string]interface{}{"type": "prometheus", "uid": "prom"}},
+					map[string]interface{}{"datasource": map[string]interface{}{"type": "__expr__", "uid": expr.DatasourceUID}},
+				},
+			},
+			map[string]interface{}{
+				"datasource": map[string]interface{}{"type": "loki", "uid": "${ds}"},
+			},
+			map[string]interface{}{
+				"datasource": map[string]interface{}{"type": "datasource", "uid": "-- Mixed --"},
+			},
+		},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"datasource": map[string]interface{}{"type": "loki", "uid": "logs"}},
+			},
+		},
+	})
+
+	uids := map[string]bool{}
+	collectDatasourceUIDs(dash.Interface(), uids)
+	require.Equal(t, []string{"logs", "prom"}, sortedKeys(uids))
+
+	remapped, err := remapDashboard(dash, map[string]string{"prom": "prom-2"})
+	require.NoError(t, err)
+	panels := remapped.Get("panels")
+	require.Equal(t, "prom-2", panels.GetIndex(0).GetPath("datasource", "uid").MustString())
+	require.Equal(t, "prom-2", panels.GetIndex(0).Get("targets").GetIndex(0).GetPath("datasource", "uid").MustString())
+	require.Equal(t, expr.DatasourceUID, panels.GetIndex(0).Get("targets").GetIndex(1).GetPath("datasource", "uid").MustString())
+	require.Equal(t, "${ds}", panels.GetIndex(1).GetPath("datasource", "uid").MustString())
+	require.Equal(t, "logs", remapped.GetPath("templating", "list").GetIndex(0).GetPath("datasource", "uid").MustString())
+	// the archive is left as it was
+	require.Equal(t, "prom", dash.Get("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+
+	model, err := remapRawModel([]byte(`{"datasource":{"type":"prometheus","uid":"prom"}}`), map[string]string{"prom": "prom-2"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"datasource":{"type":"prometheus","uid":"prom-2"}}`, string(model))
+}
+
+func TestImport(t *testing.T) {
+	usr := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin}
+
+	newArchive := func() *folderarchive.Archive {
+		return &folderarchive.Archive{
+			Version:   folderarchive.ArchiveVersion,
+			FolderUID: "team",
+			Folders:   []folderarchive.ArchiveFolder{{UID: "team", Title: "Team"}},
+			Dashboards: []folderarchive.ArchiveDashboard{{
+				UID:       "dash",
+				FolderUID: "team",
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{
+					"uid":   "dash",
+					"title": "Overview",
+					"panels": []interface{}{
+						map[string]interface{}{"datasource": map[string]interface{}{"type": "prometheus", "uid": "old-prom"}},
+					},
+				}),
+			}},
+			LibraryElements: []folderarchive.ArchiveLibraryElement{},
+			AlertRules: []folderarchive.ArchiveAlertRule{{
+				UID:             "rule",
+				FolderUID:       "team",
+				Title:           "High latency",
+				RuleGroup:       "latency",
+				IntervalSeconds: 60,
+				Condition:       "A",
+				Data:            []ngmodels.AlertQuery{{RefID: "A", DatasourceUID: "old-prom"}},
+				For:             "5m0s",
+			}},
+			Datasources: []folderarchive.ArchiveDatasource{{UID: "old-prom", Name: "Prometheus", Type: "prometheus"}},
+		}
+	}
+
+	setup := func(t *testing.T) (*FolderArchiveService, *foldertest.FakeService, *dashboards.FakeDashboardService) {
+		folderService := foldertest.NewFakeService()
+		folderService.ExpectedError = dashboards.ErrFolderNotFound
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+
+		return &FolderArchiveService{
+			features:              featuremgmt.WithFeatures(),
+			ac:                    actest.FakeAccessControl{ExpectedEvaluate: true},
+			folderService:         folderService,
+			dashboardService:      dashboardService,
+			libraryElementService: &fakeLibraryElementService{},
+			alertRuleStore:        &fakeAlertRuleStore{},
+			alertRuleService:      &fakeAlertRuleService{},
+			sqlStore:              &fakeDB{FakeDB: dbtest.NewFakeDB()},
+			datasourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+				{UID: "new-prom", Name: "Prometheus", Type: "prometheus", OrgID: 1},
+			}},
+			log: log.NewNopLogger(),
+		}, folderService, dashboardService
+	}
+
+	t.Run("dry run reports what would be created without changing anything", func(t *testing.T) {
+		s, _, _ := setup(t)
+		alertRuleService := &fakeAlertRuleService{}
+		s.alertRuleService = alertRuleService
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.True(t, report.Valid(), report.Errors)
+		require.True(t, report.DryRun)
+		require.False(t, report.Imported)
+
+		require.Equal(t, []folderarchive.ImportItem{{UID: "team", Title: "Team", Action: folderarchive.ImportActionCreate}}, report.Folders)
+		require.Equal(t, []folderarchive.ImportItem{{UID: "dash", Title: "Overview", FolderUID: "team", Action: folderarchive.ImportActionCreate}}, report.Dashboards)
+		require.Equal(t, []folderarchive.ImportItem{{UID: "rule", Title: "High latency", FolderUID: "team", Action: folderarchive.ImportActionCreate}}, report.AlertRules)
+		require.Equal(t, []folderarchive.DatasourceMapping{
+			{UID: "old-prom", Name: "Prometheus", Type: "prometheus", TargetUID: "new-prom", MatchedBy: "name"},
+		}, report.Datasources)
+		require.Empty(t, alertRuleService.created)
+	})
+
+	t.Run("explicit data source mappings take precedence", func(t *testing.T) {
+		s, _, _ := setup(t)
+		s.datasourceService = &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{UID: "new-prom", Name: "Prometheus", Type: "prometheus", OrgID: 1},
+			{UID: "other-prom", Name: "Other", Type: "prometheus", OrgID: 1},
+		}}
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{
+			Archive:     newArchive(),
+			Datasources: map[string]string{"old-prom": "other-prom"},
+			DryRun:      true,
+			OrgID:       1,
+			User:        usr,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "other-prom", report.Datasources[0].TargetUID)
+		require.Equal(t, "mapping", report.Datasources[0].MatchedBy)
+	})
+
+	t.Run("unknown data sources are reported and prevent the import", func(t *testing.T) {
+		s, _, _ := setup(t)
+		s.datasourceService = &fakeDatasources.FakeDataSourceService{}
+		alertRuleService := &fakeAlertRuleService{}
+		s.alertRuleService = alertRuleService
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.False(t, report.Valid())
+		require.False(t, report.Imported)
+		require.Len(t, report.Errors, 1)
+		require.Contains(t, report.Errors[0], "old-prom")
+		require.Empty(t, alertRuleService.created)
+	})
+
+	t.Run("existing contents are only updated with overwrite", func(t *testing.T) {
+		s, folderService, _ := setup(t)
+		folderService.ExpectedError = nil
+		folderService.ExpectedFolder = &folder.Folder{ID: 3, UID: "team", Title: "Team"}
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.False(t, report.Valid())
+		require.Equal(t, "the folder already exists", report.Folders[0].Error)
+
+		report, err = s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), Overwrite: true, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.True(t, report.Valid(), report.Errors)
+		require.Equal(t, folderarchive.ImportActionUpdate, report.Folders[0].Action)
+	})
+
+	t.Run("overwriting an alert rule requires updating it in its current folder", func(t *testing.T) {
+		s, folderService, _ := setup(t)
+		folderService.ExpectedError = nil
+		folderService.ExpectedFolder = &folder.Folder{ID: 3, UID: "team", Title: "Team"}
+		s.alertRuleStore = &fakeAlertRuleStore{existing: &ngmodels.AlertRule{UID: "rule", OrgID: 1, NamespaceUID: "other"}}
+		permissions := []accesscontrol.Permission{{Action: accesscontrol.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("team")}}
+		s.ac = acmock.New().WithPermissions(permissions)
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), Overwrite: true, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.Equal(t, "you are not allowed to save alert rules in the folder", report.AlertRules[0].Error)
+
+		permissions = append(permissions, accesscontrol.Permission{Action: accesscontrol.ActionAlertingRuleUpdate, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("other")})
+		s.ac = acmock.New().WithPermissions(permissions)
+		report, err = s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), Overwrite: true, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.Empty(t, report.AlertRules[0].Error)
+		require.Equal(t, folderarchive.ImportActionUpdate, report.AlertRules[0].Action)
+	})
+
+	t.Run("provisioned alert rules cannot be overwritten", func(t *testing.T) {
+		s, _, _ := setup(t)
+		s.alertRuleStore = &fakeAlertRuleStore{existing: &ngmodels.AlertRule{UID: "rule", OrgID: 1, NamespaceUID: "team"}, provenance: ngmodels.ProvenanceFile}
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: newArchive(), Overwrite: true, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.Contains(t, report.AlertRules[0].Error, "provisioned")
+	})
+
+	t.Run("alert rules are imported with the alert rule service", func(t *testing.T) {
+		s, folderService, _ := setup(t)
+		folderService.ExpectedError = nil
+		folderService.ExpectedFolder = &folder.Folder{ID: 3, UID: "team", Title: "Team"}
+		alertRuleService := &fakeAlertRuleService{}
+		s.alertRuleService = alertRuleService
+		archive := newArchive()
+		archive.Dashboards = nil
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: archive, Overwrite: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.True(t, report.Valid(), report.Errors)
+		require.True(t, report.Imported)
+		require.Len(t, alertRuleService.created, 1)
+		require.Equal(t, "new-prom", alertRuleService.created[0].Data[0].DatasourceUID)
+		require.Equal(t, map[string]int64{"team/latency": 60}, alertRuleService.intervals)
+	})
+
+	t.Run("a failure rolls back the whole import", func(t *testing.T) {
+		s, folderService, _ := setup(t)
+		folderService.ExpectedError = nil
+		folderService.ExpectedFolder = &folder.Folder{ID: 3, UID: "team", Title: "Team"}
+		s.alertRuleService = &fakeAlertRuleService{err: ngmodels.ErrQuotaReached}
+		db := &fakeDB{FakeDB: dbtest.NewFakeDB()}
+		s.sqlStore = db
+		archive := newArchive()
+		archive.Dashboards = nil
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: archive, Overwrite: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.False(t, report.Imported)
+		require.ErrorIs(t, db.err, ngmodels.ErrQuotaReached)
+		require.Equal(t, ngmodels.ErrQuotaReached.Error(), report.AlertRules[0].Error)
+		require.Len(t, report.Errors, 1)
+		require.Contains(t, report.Errors[0], "rolled back")
+	})
+
+	t.Run("alert rules of a group must have the same interval", func(t *testing.T) {
+		s, _, _ := setup(t)
+		archive := newArchive()
+		rule := archive.AlertRules[0]
+		rule.UID, rule.IntervalSeconds = "rule-2", 30
+		archive.AlertRules = append(archive.AlertRules, rule)
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: archive, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.Empty(t, report.AlertRules[0].Error)
+		require.Contains(t, report.AlertRules[1].Error, "different intervals")
+	})
+
+	t.Run("subfolders require nested folders", func(t *testing.T) {
+		s, _, _ := setup(t)
+		archive := newArchive()
+		archive.Folders = append(archive.Folders, folderarchive.ArchiveFolder{UID: "child", Title: "Child", ParentUID: "team"})
+
+		report, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: archive, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.Equal(t, "nested folders are not enabled", report.Folders[1].Error)
+
+		s.features = featuremgmt.WithFeatures(featuremgmt.FlagNestedFolders)
+		report, err = s.Import(context.Background(), &folderarchive.ImportCommand{Archive: archive, DryRun: true, OrgID: 1, User: usr})
+		require.NoError(t, err)
+		require.True(t, report.Valid(), report.Errors)
+	})
+
+	t.Run("unsupported archive versions are rejected", func(t *testing.T) {
+		s, _, _ := setup(t)
+		archive := newArchive()
+		archive.Version = folderarchive.ArchiveVersion + 1
+
+		_, err := s.Import(context.Background(), &folderarchive.ImportCommand{Archive: archive, OrgID: 1, User: usr})
+		require.ErrorIs(t, err, folderarchive.ErrUnsupportedArchiveVersion)
+	})
+}
+
+func TestMove(t *testing.T) {
+	usr := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin}
+	folderService := foldertest.NewFakeService()
+	folderService.ExpectedFolder = &folder.Folder{ID: 2, UID: "target", Title: "Target"}
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "dash", OrgID: 1}).
+		Return(&dashboards.Dashboard{ID: 5, UID: "dash", FolderID: 1, Data: simplejson.NewFromAny(map[string]interface{}{"uid": "dash", "title": "Dash"})}, nil)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "missing", OrgID: 1}).
+		Return(nil, dashboards.ErrDashboardNotFound)
+	var saved *dashboards.SaveDashboardDTO
+	dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, false).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*dashboards.SaveDashboardDTO)
+	}).Return(&dashboards.Dashboard{}, nil)
+
+	s := &FolderArchiveService{
+		features:         featuremgmt.WithFeatures(),
+		folderService:    folderService,
+		dashboardService: dashboardService,
+		log:              log.NewNopLogger(),
+	}
+
+	result, err := s.Move(context.Background(), &folderarchive.MoveCommand{
+		DashboardUIDs: []string{"dash", "missing"},
+		ParentUID:     "target",
+		OrgID:         1,
+		User:          usr,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []folderarchive.MoveItem{{UID: "dash"}, {UID: "missing", Error: dashboards.ErrDashboardNotFound.Error()}}, result.Dashboards)
+	require.Equal(t, int64(2), saved.Dashboard.FolderID)
+	require.Equal(t, "Moved to Target", saved.Message)
+}
+
+func TestPlanPermissions(t *testing.T) {
+	s := &FolderArchiveService{teamService: &fakeTeamService{FakeService: teamtest.NewFakeService(), teams: map[string]*team.TeamDTO{
+		"Platform": {ID: 10, OrgID: 1, Name: "Platform"},
+	}}}
+	cmd := &folderarchive.ImportCommand{OrgID: 1, User: &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin}}
+	plan := &importPlan{permissions: map[string][]accesscontrol.SetResourcePermissionCommand{}}
+	report := &folderarchive.ImportReport{}
+
+	err := s.planPermissions(context.Background(), cmd, plan, report, "team", []folderarchive.ArchivePermission{
+		{Team: "Platform", Permission: "Edit"},
+		{Team: "Missing", Permission: "View"},
+		{Role: "Viewer", Permission: "View"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []accesscontrol.SetResourcePermissionCommand{
+		{TeamID: 10, Permission: "Edit"},
+		{BuiltinRole: "Viewer", Permission: "View"},
+	}, plan.permissions["team"])
+	require.Equal(t, []string{`permission of "team": team "Missing" not found`}, report.Warnings)
+}
+
+type fakeTeamService struct {
+	*teamtest.FakeService
+	teams map[string]*team.TeamDTO
+}
+
+func (f *fakeTeamService) SearchTeams(_ context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	// the store computes the offset of a limited search from its page, which starts at 1
+	if query.Limit > 0 && query.Page < 1 {
+		return team.SearchTeamQueryResult{}, fmt.Errorf("invalid page %d for a search limited to %d teams", query.Page, query.Limit)
+	}
+	result := team.SearchTeamQueryResult{}
+	if t, ok := f.teams[query.Name]; ok {
+		result.Teams = append(result.Teams, t)
+	}
+	return result, nil
+}
+
+type fakeLibraryElementService struct {
+	libraryelements.Service
+}
+
+func (s *fakeLibraryElementService) GetElement(c context.Context, signedInUser *user.SignedInUser, UID string) (model.LibraryElementDTO, error) {
+	return model.LibraryElementDTO{}, model.ErrLibraryElementNotFound
+}
+
+type fakeDB struct {
+	*dbtest.FakeDB
+	err error
+}
+
+func (db *fakeDB) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	db.err = fn(ctx)
+	return db.err
+}
+
+type fakeAlertRuleStore struct {
+	existing   *ngmodels.AlertRule
+	provenance ngmodels.Provenance
+}
+
+func (s *fakeAlertRuleStore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error) {
+	return nil, nil
+}
+
+func (s *fakeAlertRuleStore) GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error) {
+	if s.existing == nil {
+		return nil, ngmodels.ErrAlertRuleNotFound
+	}
+	return s.existing, nil
+}
+
+func (s *fakeAlertRuleStore) GetProvenance(ctx context.Context, o ngmodels.Provisionable, org int64) (ngmodels.Provenance, error) {
+	return s.provenance, nil
+}
+
+type fakeAlertRuleService struct {
+	created   []ngmodels.AlertRule
+	updated   []ngmodels.AlertRule
+	intervals map[string]int64
+	err       error
+}
+
+func (s *fakeAlertRuleService) CreateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance, userID int64) (ngmodels.AlertRule, error) {
+	if s.err != nil {
+		return ngmodels.AlertRule{}, s.err
+	}
+	s.created = append(s.created, rule)
+	return rule, nil
+}
+
+func (s *fakeAlertRuleService) UpdateAlertRule(ctx context.Context, rule ngmodels.AlertRule, provenance ngmodels.Provenance) (ngmodels.AlertRule, error) {
+	if s.err != nil {
+		return ngmodels.AlertRule{}, s.err
+	}
+	s.updated = append(s.updated, rule)
+	return rule, nil
+}
+
+func (s *fakeAlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+	if s.intervals == nil {
+		s.intervals = map[string]int64{}
+	}
+	s.intervals[namespaceUID+"/"+ruleGroup] = intervalSeconds
+	return nil
+}
@@ -0,0 +1,96 @@
+package ageprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Kind is the provider kind used in encryption provider identifiers, e.g. age.v1
+const Kind = "age"
+
+type Config struct {
+	// IdentityFile is the path of an age key file, as generated by age-keygen
+	IdentityFile string
+}
+
+// ParseConfig reads the configuration of an age provider from its [security.encryption.age.<name>] section
+func ParseConfig(section setting.Section) (Config, error) {
+	cfg := Config{
+		IdentityFile: section.KeyValue("identity_file").Value(),
+	}
+
+	if cfg.IdentityFile == "" {
+		return cfg, errors.New("identity_file is required")
+	}
+
+	return cfg, nil
+}
+
+// Provider encrypts data keys with the X25519 identities of an age key file kept outside of grafana.ini.
+// Data keys are encrypted to the first identity of the file. Every identity of the file can decrypt, so
+// keys can be rotated by adding a new identity at the top of the file.
+type Provider struct {
+	recipient  age.Recipient
+	identities []age.Identity
+}
+
+func New(cfg Config) (*Provider, error) {
+	// nolint:gosec
+	// The path is read from the Grafana configuration, not from user input.
+	f, err := os.Open(cfg.IdentityFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age identity file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity file: %w", err)
+	}
+
+	first, ok := identities[0].(*age.X25519Identity)
+	if !ok {
+		return nil, errors.New("the first identity of the age identity file must be an X25519 identity")
+	}
+
+	return &Provider{
+		recipient:  first.Recipient(),
+		identities: identities,
+	}, nil
+}
+
+var _ secrets.Provider = &Provider{}
+
+func (p *Provider) Encrypt(_ context.Context, blob []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, p.recipient)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(blob); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (p *Provider) Decrypt(_ context.Context, blob []byte) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(blob), p.identities...)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,74 @@
+package ageprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIdentityFile(t *testing.T, identities ...*age.X25519Identity) string {
+	t.Helper()
+
+	var content string
+	for _, identity := range identities {
+		content += "# public key: " + identity.Recipient().String() + "\n" + identity.String() + "\n"
+	}
+	path := filepath.Join(t.TempDir(), "grafana.agekey")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	oldIdentity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	newIdentity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	t.Run("encrypts and decrypts with the key file", func(t *testing.T) {
+		p, err := New(Config{IdentityFile: writeIdentityFile(t, oldIdentity)})
+		require.NoError(t, err)
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(encrypted), "data key")
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("decrypts with rotated identities", func(t *testing.T) {
+		old, err := New(Config{IdentityFile: writeIdentityFile(t, oldIdentity)})
+		require.NoError(t, err)
+		encrypted, err := old.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+
+		rotated, err := New(Config{IdentityFile: writeIdentityFile(t, newIdentity, oldIdentity)})
+		require.NoError(t, err)
+		decrypted, err := rotated.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+
+		// new data keys are only encrypted for the first identity
+		encrypted, err = rotated.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		_, err = old.Decrypt(ctx, encrypted)
+		assert.Error(t, err)
+	})
+
+	t.Run("fails without a valid key file", func(t *testing.T) {
+		_, err := New(Config{IdentityFile: filepath.Join(t.TempDir(), "missing")})
+		assert.Error(t, err)
+
+		path := filepath.Join(t.TempDir(), "invalid")
+		require.NoError(t, os.WriteFile(path, []byte("not a key"), 0600))
+		_, err = New(Config{IdentityFile: path})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+package hashicorpvault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Kind is the provider kind used in encryption provider identifiers, e.g. hashicorpvault.v1
+const Kind = "hashicorpvault"
+
+type Config struct {
+	// URL of the Vault server, e.g. http://127.0.0.1:8200
+	URL string
+	// Token used to authenticate against Vault
+	Token string
+	// Namespace of the transit engine, Vault Enterprise only
+	Namespace string
+	// TransitEnginePath is the path the transit secrets engine is mounted on
+	TransitEnginePath string
+	// KeyRing is the name of the transit encryption key
+	KeyRing string
+	Timeout time.Duration
+}
+
+// ParseConfig reads the configuration of a Vault provider from its [security.encryption.hashicorpvault.<name>] section
+func ParseConfig(section setting.Section) (Config, error) {
+	cfg := Config{
+		URL:               section.KeyValue("url").Value(),
+		Token:             section.KeyValue("token").Value(),
+		Namespace:         section.KeyValue("namespace").Value(),
+		TransitEnginePath: strings.Trim(section.KeyValue("transit_engine_path").MustString("transit"), "/"),
+		KeyRing:           section.KeyValue("key_ring").Value(),
+		Timeout:           section.KeyValue("timeout").MustDuration(10 * time.Second),
+	}
+
+	if cfg.URL == "" {
+		return cfg, errors.New("url is required")
+	}
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return cfg, fmt.Errorf("invalid url: %w", err)
+	}
+	if cfg.Token == "" {
+		return cfg, errors.New("token is required")
+	}
+	if cfg.KeyRing == "" {
+		return cfg, errors.New("key_ring is required")
+	}
+
+	return cfg, nil
+}
+
+// Provider encrypts data keys with the HashiCorp Vault transit secrets engine, so the key encryption key
+// never leaves Vault
+type Provider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func New(cfg Config) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+var _ secrets.Provider = &Provider{}
+
+func (p *Provider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := p.call(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(blob)}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, errors.New("vault returned an empty ciphertext")
+	}
+
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+func (p *Provider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := p.call(ctx, "decrypt", map[string]string{"ciphertext": string(blob)}, &resp); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (p *Provider) call(ctx context.Context, operation string, body interface{}, result interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimRight(p.cfg.URL, "/"), p.cfg.TransitEnginePath, operation, url.PathEscape(p.cfg.KeyRing))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault transit %s failed: %w", operation, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		return fmt.Errorf("vault transit %s failed with status %d: %s", operation, resp.StatusCode, strings.Join(vaultErr.Errors, ", "))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
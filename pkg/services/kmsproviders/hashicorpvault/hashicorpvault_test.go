@@ -0,0 +1,121 @@
+package hashicorpvault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testToken = "root"
+
+// newTransitServer stands in for a Vault dev server with the transit engine mounted on transit/ and a grafana key.
+// Ciphertexts are the reversed base64 plaintext, prefixed like Vault's.
+func newTransitServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/grafana":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{"ciphertext": "vault:v1:" + reverse(body["plaintext"])},
+			})
+		case "/v1/transit/decrypt/grafana":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{"plaintext": reverse(strings.TrimPrefix(body["ciphertext"], "vault:v1:"))},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func TestProvider(t *testing.T) {
+	server := newTransitServer(t)
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+
+	t.Run("encrypts and decrypts with the transit engine", func(t *testing.T) {
+		p := New(Config{URL: server.URL, Token: testToken, TransitEnginePath: "transit", KeyRing: "grafana"})
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(encrypted), "vault:v1:"))
+		assert.NotContains(t, string(encrypted), base64.StdEncoding.EncodeToString([]byte("data key")))
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("returns vault errors", func(t *testing.T) {
+		p := New(Config{URL: server.URL, Token: "wrong", TransitEnginePath: "transit", KeyRing: "grafana"})
+
+		_, err := p.Encrypt(ctx, []byte("data key"))
+		require.ErrorContains(t, err, "permission denied")
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	parse := func(t *testing.T, raw string) (Config, error) {
+		t.Helper()
+		f, err := ini.Load([]byte(raw))
+		require.NoError(t, err)
+		settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: f}}
+		return ParseConfig(settings.Section("security.encryption.hashicorpvault.v1"))
+	}
+
+	t.Run("reads the section", func(t *testing.T) {
+		cfg, err := parse(t, `
+[security.encryption.hashicorpvault.v1]
+url = http://127.0.0.1:8200
+token = root
+key_ring = grafana
+transit_engine_path = /custom-transit/
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "http://127.0.0.1:8200", cfg.URL)
+		assert.Equal(t, "root", cfg.Token)
+		assert.Equal(t, "grafana", cfg.KeyRing)
+		assert.Equal(t, "custom-transit", cfg.TransitEnginePath)
+	})
+
+	for name, raw := range map[string]string{
+		"url is required":      "[security.encryption.hashicorpvault.v1]\ntoken = root\nkey_ring = grafana",
+		"token is required":    "[security.encryption.hashicorpvault.v1]\nurl = http://127.0.0.1:8200\nkey_ring = grafana",
+		"key_ring is required": "[security.encryption.hashicorpvault.v1]\nurl = http://127.0.0.1:8200\ntoken = root",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parse(t, raw)
+			require.EqualError(t, err, name)
+		})
+	}
+}
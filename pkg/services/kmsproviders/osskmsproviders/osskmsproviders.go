@@ -1,10 +1,16 @@
 package osskmsproviders
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/ageprovider"
 	grafana "github.com/grafana/grafana/pkg/services/kmsproviders/defaultprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/hashicorpvault"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -13,6 +19,7 @@ type Service struct {
 	enc      encryption.Internal
 	settings setting.Provider
 	features featuremgmt.FeatureToggles
+	log      log.Logger
 }
 
 func ProvideService(enc encryption.Internal, settings setting.Provider, features featuremgmt.FeatureToggles) Service {
@@ -20,11 +27,62 @@ func ProvideService(enc encryption.Internal, settings setting.Provider, features
 		enc:      enc,
 		settings: settings,
 		features: features,
+		log:      log.New("kmsproviders"),
 	}
 }
 
 func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.settings, s.enc),
-	}, nil
+	}
+
+	available := s.settings.KeyValue("security", "available_encryption_providers").MustString("")
+	for _, id := range strings.Fields(strings.ReplaceAll(available, ",", " ")) {
+		providerID := kmsproviders.NormalizeProviderID(secrets.ProviderID(id))
+		if _, ok := providers[providerID]; ok {
+			continue
+		}
+
+		provider, err := s.newProvider(providerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure encryption provider %s: %w", providerID, err)
+		}
+		if provider != nil {
+			providers[providerID] = provider
+		}
+	}
+
+	return providers, nil
+}
+
+// newProvider configures a provider from its [security.encryption.<kind>.<name>] section.
+// It returns nil for kinds that are not available in OSS.
+func (s Service) newProvider(id secrets.ProviderID) (secrets.Provider, error) {
+	kind, err := id.Kind()
+	if err != nil {
+		return nil, err
+	}
+
+	section := s.settings.Section("security.encryption." + string(id))
+	switch kind {
+	case hashicorpvault.Kind:
+		cfg, err := hashicorpvault.ParseConfig(section)
+		if err != nil {
+			return nil, err
+		}
+		return hashicorpvault.New(cfg), nil
+	case ageprovider.Kind:
+		cfg, err := ageprovider.ParseConfig(section)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := ageprovider.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		s.log.Warn("Encryption provider kind is not available", "provider", id, "kind", kind)
+		return nil, nil
+	}
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/ldapsync"
 	"github.com/grafana/grafana/pkg/services/org"
 )
 
@@ -19,6 +20,19 @@ type SyncLDAPUserParams struct {
 	UserID int64 `json:"user_id"`
 }
 
+// swagger:parameters postSyncAllUsersWithLDAP
+type SyncAllLDAPUsersParams struct {
+	// in:query
+	// required:false
+	DryRun bool `json:"dryRun"`
+}
+
+// swagger:response postSyncAllUsersWithLDAPResponse
+type SyncAllLDAPUsersResponse struct {
+	// in:body
+	Body ldapsync.SyncReport `json:"body"`
+}
+
 // LDAPAttribute is a serializer for user attributes mapped from LDAP. Is meant to display both the serialized value and the LDAP key we received it from.
 type LDAPAttribute struct {
 	ConfigAttributeValue string `json:"cfgAttrValue"`
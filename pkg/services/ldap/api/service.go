@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/ldapsync"
 	"github.com/grafana/grafana/pkg/services/ldap/multildap"
 	"github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/login"
@@ -37,12 +38,13 @@ type Service struct {
 	sessionService    auth.UserTokenService
 	log               log.Logger
 	ldapService       service.LDAP
+	ldapSync          ldapsync.Service
 }
 
 func ProvideService(cfg *setting.Cfg, router routing.RouteRegister, accessControl ac.AccessControl,
 	userService user.Service, authInfoService login.AuthInfoService, ldapGroupsService ldap.Groups,
 	loginService login.Service, orgService org.Service, ldapService service.LDAP,
-	sessionService auth.UserTokenService, bundleRegistry supportbundles.Service) *Service {
+	sessionService auth.UserTokenService, bundleRegistry supportbundles.Service, ldapSync ldapsync.Service) *Service {
 	s := &Service{
 		cfg:               cfg,
 		userService:       userService,
@@ -52,6 +54,7 @@ func ProvideService(cfg *setting.Cfg, router routing.RouteRegister, accessContro
 		orgService:        orgService,
 		sessionService:    sessionService,
 		ldapService:       ldapService,
+		ldapSync:          ldapSync,
 		log:               log.New("ldap.api"),
 	}
 
@@ -60,6 +63,7 @@ func ProvideService(cfg *setting.Cfg, router routing.RouteRegister, accessContro
 
 	router.Group("/api/admin", func(adminRoute routing.RouteRegister) {
 		adminRoute.Post("/ldap/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionLDAPConfigReload)), routing.Wrap(s.ReloadLDAPCfg))
+		adminRoute.Post("/ldap/sync", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionLDAPUsersSync)), routing.Wrap(s.PostSyncAllUsersWithLDAP))
 		adminRoute.Post("/ldap/sync/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionLDAPUsersSync)), routing.Wrap(s.PostSyncUserWithLDAP))
 		adminRoute.Get("/ldap/:username", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionLDAPUsersRead)), routing.Wrap(s.GetUserFromLDAP))
 		adminRoute.Get("/ldap/status", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionLDAPStatusRead)), routing.Wrap(s.GetLDAPStatus))
@@ -247,6 +251,39 @@ func (s *Service) PostSyncUserWithLDAP(c *contextmodel.ReqContext) response.Resp
 	return response.Success("User synced successfully")
 }
 
+// swagger:route POST /admin/ldap/sync admin_ldap postSyncAllUsersWithLDAP
+//
+// Synchronizes the organization roles, teams and Grafana admin flag of all LDAP users with their LDAP groups.
+// Users no longer found in LDAP are disabled. With `dryRun` set, the changes are only reported.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `ldap.user:sync`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: postSyncAllUsersWithLDAPResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) PostSyncAllUsersWithLDAP(c *contextmodel.ReqContext) response.Response {
+	if !s.cfg.LDAPAuthEnabled {
+		return response.Error(http.StatusBadRequest, "LDAP is not enabled", nil)
+	}
+
+	dryRun := c.QueryBool("dryRun")
+	report, err := s.ldapSync.Sync(c.Req.Context(), dryRun)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to sync users with LDAP", err)
+	}
+
+	if !dryRun {
+		s.log.Info("Synced LDAP users", "checked", report.Checked, "changed", len(report.Users), "userId", c.UserID)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
 // swagger:route GET /admin/ldap/{user_name} admin_ldap getUserFromLDAP
 //
 // Finds an user based on a username in LDAP. This helps illustrate how would the particular user be mapped in Grafana when synced.
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/ldapsync"
 	"github.com/grafana/grafana/pkg/services/ldap/multildap"
 	"github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/login"
@@ -52,6 +53,10 @@ func (m *LDAPMock) Users(logins []string) ([]*login.ExternalUserInfo, error) {
 	return s, nil
 }
 
+func (m *LDAPMock) UsersFromAllServers(logins []string) ([]*login.ExternalUserInfo, error) {
+	return m.Users(logins)
+}
+
 func (m *LDAPMock) User(login string) (*login.ExternalUserInfo, ldap.ServerConfig, error) {
 	return m.UserSearchResult, m.UserSearchConfig, m.UserSearchError
 }
@@ -73,6 +78,7 @@ func setupAPITest(t *testing.T, opts ...func(a *Service)) (*Service, *webtest.Se
 		service.NewLDAPFakeService(),
 		authtest.NewFakeUserAuthTokenService(),
 		supportbundlestest.NewFakeBundleService(),
+		ldapsync.NewFakeService(),
 	)
 
 	for _, o := range opts {
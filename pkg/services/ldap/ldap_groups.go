@@ -2,6 +2,8 @@ package ldap
 
 type Groups interface {
 	GetTeams(groups []string, orgIDs []int64) ([]TeamOrgGroupDTO, error)
+	// HasTeamMappings reports whether any LDAP group is mapped to a team.
+	HasTeamMappings() (bool, error)
 }
 
 type OSSGroups struct{}
@@ -13,3 +15,7 @@ func ProvideGroupsService() *OSSGroups {
 func (*OSSGroups) GetTeams(_ []string, _ []int64) ([]TeamOrgGroupDTO, error) {
 	return nil, nil
 }
+
+func (*OSSGroups) HasTeamMappings() (bool, error) {
+	return false, nil
+}
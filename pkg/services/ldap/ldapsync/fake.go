@@ -0,0 +1,20 @@
+package ldapsync
+
+import "context"
+
+type FakeService struct {
+	ExpectedReport *SyncReport
+	ExpectedError  error
+	DryRun         bool
+	SyncCalled     bool
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (s *FakeService) Sync(ctx context.Context, dryRun bool) (*SyncReport, error) {
+	s.SyncCalled = true
+	s.DryRun = dryRun
+	return s.ExpectedReport, s.ExpectedError
+}
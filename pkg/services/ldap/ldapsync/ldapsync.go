@@ -0,0 +1,64 @@
+package ldapsync
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// Service synchronizes the org roles, teams and Grafana admin flag of all LDAP users with
+// their LDAP groups, without waiting for the users to log in again.
+type Service interface {
+	// Sync walks all users authenticated through LDAP and applies the changes found, unless
+	// dryRun is set. It returns the changes found for each user.
+	Sync(ctx context.Context, dryRun bool) (*SyncReport, error)
+}
+
+// SyncReport is the outcome of a synchronization.
+type SyncReport struct {
+	DryRun   bool          `json:"dryRun"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	// Checked is the number of LDAP users looked up.
+	Checked int `json:"checked"`
+	// Users lists the users with changes or errors.
+	Users []UserDiff `json:"users"`
+}
+
+// UserDiff is the difference between a user in Grafana and in LDAP.
+type UserDiff struct {
+	UserID int64  `json:"userId"`
+	Login  string `json:"login"`
+	// Disable is set when the user is no longer in LDAP and gets disabled.
+	Disable bool `json:"disable,omitempty"`
+	// Enable is set when a disabled user is back in LDAP.
+	Enable bool `json:"enable,omitempty"`
+	// GrafanaAdmin is the new value of the Grafana admin flag when it changes.
+	GrafanaAdmin *bool         `json:"grafanaAdmin,omitempty"`
+	OrgRoles     []OrgRoleDiff `json:"orgRoles,omitempty"`
+	// TeamsAdded lists the teams mapped to the LDAP groups of the user that the user joins.
+	TeamsAdded []TeamDiff `json:"teamsAdded,omitempty"`
+	// TeamsRemoved lists the teams the user was added to by the sync and is no longer mapped to.
+	TeamsRemoved []TeamDiff `json:"teamsRemoved,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// TeamDiff is a team the user joins or leaves.
+type TeamDiff struct {
+	OrgID    int64  `json:"orgId"`
+	TeamID   int64  `json:"teamId"`
+	TeamName string `json:"teamName"`
+}
+
+// OrgRoleDiff is a change of role in an organization. From is empty when the user is added
+// to the organization and To is empty when the user is removed from it.
+type OrgRoleDiff struct {
+	OrgID int64        `json:"orgId"`
+	From  org.RoleType `json:"from,omitempty"`
+	To    org.RoleType `json:"to,omitempty"`
+}
+
+func (d *UserDiff) hasChanges() bool {
+	return d.Disable || d.Enable || d.GrafanaAdmin != nil || len(d.OrgRoles) > 0 || len(d.TeamsAdded) > 0 || len(d.TeamsRemoved) > 0 || d.Error != ""
+}
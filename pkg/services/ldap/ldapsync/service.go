@@ -0,0 +1,496 @@
+package ldapsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/multildap"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	lockActionName = "ldap group sync"
+	searchPageSize = 500
+	kvNamespace    = "ldap.sync"
+)
+
+var (
+	ErrLDAPDisabled = errors.New("LDAP is not enabled")
+	ErrNoLDAPClient = errors.New("failed to find the LDAP server")
+)
+
+var _ Service = new(SyncService)
+
+type SyncService struct {
+	cfg            *setting.Cfg
+	log            log.Logger
+	serverLock     *serverlock.ServerLockService
+	ldapService    service.LDAP
+	ldapGroups     ldap.Groups
+	userService    user.Service
+	orgService     org.Service
+	teamService    team.Service
+	loginService   login.Service
+	sessionService auth.UserTokenService
+	// kv records the team memberships added by the sync, the only ones it removes
+	kv *kvstore.NamespacedKVStore
+}
+
+func ProvideService(cfg *setting.Cfg, serverLock *serverlock.ServerLockService, ldapService service.LDAP,
+	ldapGroups ldap.Groups, userService user.Service, orgService org.Service, teamService team.Service,
+	loginService login.Service, sessionService auth.UserTokenService, kvStore kvstore.KVStore) *SyncService {
+	return &SyncService{
+		cfg:            cfg,
+		log:            log.New("ldap.sync"),
+		serverLock:     serverLock,
+		ldapService:    ldapService,
+		ldapGroups:     ldapGroups,
+		userService:    userService,
+		orgService:     orgService,
+		teamService:    teamService,
+		loginService:   loginService,
+		sessionService: sessionService,
+		kv:             kvstore.WithNamespace(kvStore, 0, kvNamespace),
+	}
+}
+
+func (s *SyncService) IsDisabled() bool {
+	return !s.cfg.LDAPAuthEnabled || !s.cfg.LDAPActiveSyncEnabled
+}
+
+// Run synchronizes the LDAP users on the schedule set by sync_cron. Only one instance
+// of a highly available setup runs each synchronization.
+func (s *SyncService) Run(ctx context.Context) error {
+	schedule, err := cron.ParseStandard(s.cfg.LDAPSyncCron)
+	if err != nil {
+		s.log.Error("Invalid LDAP sync schedule, background sync is disabled", "sync_cron", s.cfg.LDAPSyncCron, "error", err)
+		return nil
+	}
+
+	for {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-timer.C:
+			// skip the run when another instance already synced for this occurrence
+			maxInterval := schedule.Next(next).Sub(next) / 2
+			err := s.serverLock.LockAndExecute(ctx, lockActionName, maxInterval, func(ctx context.Context) {
+				report, err := s.Sync(ctx, false)
+				if err != nil {
+					s.log.Error("Failed to sync LDAP users", "error", err)
+					return
+				}
+				s.log.Info("Synced LDAP users", "checked", report.Checked, "changed", len(report.Users), "duration", report.Duration)
+			})
+			if err != nil {
+				s.log.Error("Failed to lock and execute LDAP sync", "error", err)
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *SyncService) Sync(ctx context.Context, dryRun bool) (*SyncReport, error) {
+	if !s.cfg.LDAPAuthEnabled {
+		return nil, ErrLDAPDisabled
+	}
+
+	client := s.ldapService.Client()
+	if client == nil {
+		return nil, ErrNoLDAPClient
+	}
+
+	// teams are left alone until groups are mapped to them
+	syncTeams, err := s.ldapGroups.HasTeamMappings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the team mappings of LDAP groups: %w", err)
+	}
+
+	report := &SyncReport{DryRun: dryRun, Started: time.Now(), Users: make([]UserDiff, 0)}
+	for page := 1; ; page++ {
+		result, err := s.userService.Search(ctx, &user.SearchUsersQuery{
+			SignedInUser: usersReader(),
+			AuthModule:   login.LDAPAuthModule,
+			Page:         page,
+			Limit:        searchPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search LDAP users: %w", err)
+		}
+
+		logins := make([]string, 0, len(result.Users))
+		for _, hit := range result.Users {
+			logins = append(logins, hit.Login)
+		}
+
+		extUsers := make(map[string]*login.ExternalUserInfo, len(logins))
+		// users missing from the reachable servers may still exist on an unreachable one
+		allServersReached := true
+		if len(logins) > 0 {
+			found, err := client.UsersFromAllServers(logins)
+			if errors.Is(err, multildap.ErrUnreachableServer) {
+				s.log.Warn("Not disabling users missing from LDAP while an LDAP server is unreachable", "error", err)
+				allServersReached = false
+				found, err = client.Users(logins)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up users in LDAP: %w", err)
+			}
+			for _, extUser := range found {
+				extUsers[strings.ToLower(extUser.Login)] = extUser
+			}
+		}
+
+		for _, hit := range result.Users {
+			report.Checked++
+			diff := s.syncUser(ctx, hit, extUsers[strings.ToLower(hit.Login)], allServersReached, syncTeams, dryRun)
+			if diff.hasChanges() {
+				report.Users = append(report.Users, diff)
+			}
+		}
+
+		if len(result.Users) < searchPageSize {
+			break
+		}
+	}
+
+	report.Duration = time.Since(report.Started)
+	return report, nil
+}
+
+func (s *SyncService) syncUser(ctx context.Context, hit *user.UserSearchHitDTO, extUser *login.ExternalUserInfo, allServersReached, syncTeams, dryRun bool) UserDiff {
+	diff := UserDiff{UserID: hit.ID, Login: hit.Login}
+
+	// users missing from LDAP or without any matching group lose access
+	if extUser == nil || extUser.IsDisabled {
+		if hit.IsDisabled {
+			return diff
+		}
+		if extUser == nil && !allServersReached {
+			diff.Error = "refusing to disable a user missing from LDAP while an LDAP server is unreachable"
+			return diff
+		}
+		if s.cfg.AdminUser == hit.Login {
+			diff.Error = "refusing to disable the Grafana server admin"
+			return diff
+		}
+
+		diff.Disable = true
+		if !dryRun {
+			if err := s.disableUser(ctx, hit); err != nil {
+				diff.Error = err.Error()
+			}
+		}
+		return diff
+	}
+
+	diff.Enable = hit.IsDisabled
+	if extUser.IsGrafanaAdmin != nil && *extUser.IsGrafanaAdmin != hit.IsAdmin {
+		diff.GrafanaAdmin = extUser.IsGrafanaAdmin
+	}
+
+	var err error
+	if diff.OrgRoles, err = s.orgRolesDiff(ctx, hit.ID, extUser); err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	var recorded, synced []int64
+	if syncTeams {
+		if recorded, err = s.addedTeams(ctx, hit.ID); err != nil {
+			diff.Error = err.Error()
+			return diff
+		}
+		if diff.TeamsAdded, diff.TeamsRemoved, synced, err = s.teamsDiff(ctx, hit.ID, extUser, recorded); err != nil {
+			diff.Error = err.Error()
+			return diff
+		}
+	}
+
+	if !dryRun {
+		_, err := s.loginService.UpsertUser(ctx, &login.UpsertUserCommand{
+			ExternalUser:     extUser,
+			SignupAllowed:    false,
+			UserLookupParams: login.UserLookupParams{UserID: &hit.ID},
+		})
+		if err != nil {
+			diff.Error = err.Error()
+			return diff
+		}
+		if !syncTeams {
+			return diff
+		}
+		if err := s.syncTeams(ctx, hit.ID, recorded, synced, diff.TeamsAdded, diff.TeamsRemoved); err != nil {
+			diff.Error = err.Error()
+		}
+	}
+	return diff
+}
+
+func (s *SyncService) disableUser(ctx context.Context, hit *user.UserSearchHitDTO) error {
+	if err := s.loginService.DisableExternalUser(ctx, hit.Login); err != nil {
+		return fmt.Errorf("failed to disable user: %w", err)
+	}
+	if err := s.sessionService.RevokeAllUserTokens(ctx, hit.ID); err != nil {
+		return fmt.Errorf("failed to revoke the sessions of the user: %w", err)
+	}
+	return nil
+}
+
+// orgRolesDiff mirrors the org role synchronization done when the user logs in.
+func (s *SyncService) orgRolesDiff(ctx context.Context, userID int64, extUser *login.ExternalUserInfo) ([]OrgRoleDiff, error) {
+	if len(extUser.OrgRoles) == 0 {
+		return nil, nil
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the organizations of the user: %w", err)
+	}
+
+	diffs := make([]OrgRoleDiff, 0)
+	current := make(map[int64]bool, len(orgs))
+	for _, o := range orgs {
+		current[o.OrgID] = true
+		if role := extUser.OrgRoles[o.OrgID]; role != o.Role {
+			diffs = append(diffs, OrgRoleDiff{OrgID: o.OrgID, From: o.Role, To: role})
+		}
+	}
+	for orgID, role := range extUser.OrgRoles {
+		if !current[orgID] {
+			diffs = append(diffs, OrgRoleDiff{OrgID: orgID, To: role})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].OrgID < diffs[j].OrgID })
+	return diffs, nil
+}
+
+// teamsDiff returns the teams mapped to the LDAP groups of the user that the user is not a member
+// of yet, and the teams the user was added to by the sync that are no longer mapped to its groups.
+// Memberships added by other means, including other external systems, are left alone. It also
+// returns the recorded teams the user is still a member of.
+func (s *SyncService) teamsDiff(ctx context.Context, userID int64, extUser *login.ExternalUserInfo, recorded []int64) ([]TeamDiff, []TeamDiff, []int64, error) {
+	orgIDs := make([]int64, 0, len(extUser.OrgRoles))
+	for orgID := range extUser.OrgRoles {
+		orgIDs = append(orgIDs, orgID)
+	}
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+
+	mapped, err := s.ldapGroups.GetTeams(extUser.Groups, orgIDs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get the teams of the user: %w", err)
+	}
+
+	// the memberships of the user in all organizations
+	memberships, err := s.teamService.GetUserTeamMemberships(ctx, 0, userID, false)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get the teams of the user: %w", err)
+	}
+	memberOf := make(map[int64]bool, len(memberships))
+	for _, m := range memberships {
+		memberOf[m.TeamID] = true
+	}
+
+	addedBySync := make(map[int64]bool, len(recorded))
+	for _, teamID := range recorded {
+		addedBySync[teamID] = true
+	}
+
+	teamsAdded := make([]TeamDiff, 0)
+	keep := make(map[int64]bool, len(mapped))
+	orgNames := make(map[int64]string, len(orgIDs))
+	if len(mapped) > 0 {
+		for _, orgID := range orgIDs {
+			o, err := s.orgService.GetByID(ctx, &org.GetOrgByIDQuery{ID: orgID})
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to get organization %d: %w", orgID, err)
+			}
+			orgNames[orgID] = o.Name
+		}
+	}
+	for _, t := range mapped {
+		for _, orgID := range orgIDs {
+			if orgNames[orgID] != t.OrgName {
+				continue
+			}
+			teamID, err := s.teamIDByName(ctx, orgID, t.TeamName)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if teamID == 0 {
+				s.log.Debug("Skipping team mapped to an LDAP group that does not exist", "team", t.TeamName, "orgId", orgID)
+				continue
+			}
+			keep[teamID] = true
+			if !memberOf[teamID] {
+				memberOf[teamID] = true
+				teamsAdded = append(teamsAdded, TeamDiff{OrgID: orgID, TeamID: teamID, TeamName: t.TeamName})
+			}
+		}
+	}
+
+	teamsRemoved := make([]TeamDiff, 0)
+	synced := make([]int64, 0, len(recorded))
+	for _, m := range memberships {
+		if !m.External || !addedBySync[m.TeamID] {
+			continue
+		}
+		synced = append(synced, m.TeamID)
+		if keep[m.TeamID] {
+			continue
+		}
+		t, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: m.OrgID, ID: m.TeamID, SignedInUser: teamsReader(m.OrgID)})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get team %d: %w", m.TeamID, err)
+		}
+		teamsRemoved = append(teamsRemoved, TeamDiff{OrgID: m.OrgID, TeamID: m.TeamID, TeamName: t.Name})
+	}
+
+	return teamsAdded, teamsRemoved, synced, nil
+}
+
+// teamIDByName returns the ID of the team with the given name, or 0 when there is none.
+func (s *SyncService) teamIDByName(ctx context.Context, orgID int64, name string) (int64, error) {
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: teamsReader(orgID),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to search team %s: %w", name, err)
+	}
+	if len(result.Teams) == 0 {
+		return 0, nil
+	}
+	return result.Teams[0].ID, nil
+}
+
+// syncTeams applies the team changes as external memberships and records the teams the sync
+// added the user to, so that a later sync can remove them. Recorded teams the user has left since
+// are forgotten.
+func (s *SyncService) syncTeams(ctx context.Context, userID int64, recorded, synced []int64, added, removed []TeamDiff) error {
+	if len(added) == 0 && len(removed) == 0 && len(synced) == len(recorded) {
+		return nil
+	}
+
+	teamIDs := make(map[int64]bool, len(synced)+len(added))
+	for _, teamID := range synced {
+		teamIDs[teamID] = true
+	}
+
+	var syncErr error
+	for _, t := range added {
+		err := s.teamService.AddTeamMember(userID, t.OrgID, t.TeamID, true, 0)
+		// the team sync of the login may have added the user already
+		if err != nil && !errors.Is(err, team.ErrTeamMemberAlreadyAdded) {
+			syncErr = fmt.Errorf("failed to add the user to team %s: %w", t.TeamName, err)
+			break
+		}
+		teamIDs[t.TeamID] = true
+	}
+	for _, t := range removed {
+		if syncErr != nil {
+			break
+		}
+		err := s.teamService.RemoveTeamMember(ctx, &team.RemoveTeamMemberCommand{OrgID: t.OrgID, UserID: userID, TeamID: t.TeamID})
+		if err != nil && !errors.Is(err, team.ErrTeamMemberNotFound) {
+			syncErr = fmt.Errorf("failed to remove the user from team %s: %w", t.TeamName, err)
+			break
+		}
+		delete(teamIDs, t.TeamID)
+	}
+
+	// record the changes applied so far even when one of them failed
+	if err := s.setAddedTeams(ctx, userID, teamIDs); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// addedTeams returns the IDs of the teams the sync added the user to.
+func (s *SyncService) addedTeams(ctx context.Context, userID int64) ([]int64, error) {
+	value, ok, err := s.kv.Get(ctx, addedTeamsKey(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the teams added by the sync: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	var teamIDs []int64
+	if err := json.Unmarshal([]byte(value), &teamIDs); err != nil {
+		return nil, fmt.Errorf("failed to read the teams added by the sync: %w", err)
+	}
+	return teamIDs, nil
+}
+
+func (s *SyncService) setAddedTeams(ctx context.Context, userID int64, teamIDs map[int64]bool) error {
+	if len(teamIDs) == 0 {
+		if err := s.kv.Del(ctx, addedTeamsKey(userID)); err != nil {
+			return fmt.Errorf("failed to record the teams added by the sync: %w", err)
+		}
+		return nil
+	}
+
+	ids := make([]int64, 0, len(teamIDs))
+	for teamID := range teamIDs {
+		ids = append(ids, teamID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	value, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	if err := s.kv.Set(ctx, addedTeamsKey(userID), string(value)); err != nil {
+		return fmt.Errorf("failed to record the teams added by the sync: %w", err)
+	}
+	return nil
+}
+
+func addedTeamsKey(userID int64) string {
+	return fmt.Sprintf("teams.%d", userID)
+}
+
+// usersReader is the identity used to list the users of all organizations.
+func usersReader() *user.SignedInUser {
+	return &user.SignedInUser{
+		OrgID: ac.GlobalOrgID,
+		Permissions: map[int64]map[string][]string{
+			ac.GlobalOrgID: {ac.ActionUsersRead: {ac.ScopeGlobalUsersAll}},
+		},
+	}
+}
+
+// teamsReader is the identity used to list the teams of a user in an organization.
+func teamsReader(orgID int64) *user.SignedInUser {
+	return &user.SignedInUser{
+		OrgID: orgID,
+		Permissions: map[int64]map[string][]string{
+			orgID: {ac.ActionTeamsRead: {ac.ScopeTeamsAll}},
+		},
+	}
+}
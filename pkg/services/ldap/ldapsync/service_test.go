@@ -0,0 +1,253 @@
+package ldapsync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/multildap"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type multiLDAPMock struct {
+	multildap.IMultiLDAP
+	users       []*login.ExternalUserInfo
+	unreachable bool
+}
+
+func (m *multiLDAPMock) Users(logins []string) ([]*login.ExternalUserInfo, error) {
+	return m.users, nil
+}
+
+func (m *multiLDAPMock) UsersFromAllServers(logins []string) ([]*login.ExternalUserInfo, error) {
+	if m.unreachable {
+		return nil, fmt.Errorf("%w second:389", multildap.ErrUnreachableServer)
+	}
+	return m.users, nil
+}
+
+type groupsMock struct {
+	teams []ldap.TeamOrgGroupDTO
+}
+
+func (m *groupsMock) GetTeams(groups []string, orgIDs []int64) ([]ldap.TeamOrgGroupDTO, error) {
+	return m.teams, nil
+}
+
+func (m *groupsMock) HasTeamMappings() (bool, error) {
+	return true, nil
+}
+
+type teamServiceMock struct {
+	*teamtest.FakeService
+	teams   map[string]*team.TeamDTO
+	added   []int64
+	removed []int64
+}
+
+func (m *teamServiceMock) SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	result := team.SearchTeamQueryResult{Teams: []*team.TeamDTO{}}
+	if t, ok := m.teams[query.Name]; ok {
+		result.Teams = append(result.Teams, t)
+	}
+	return result, nil
+}
+
+func (m *teamServiceMock) GetTeamByID(ctx context.Context, query *team.GetTeamByIDQuery) (*team.TeamDTO, error) {
+	for _, t := range m.teams {
+		if t.ID == query.ID {
+			return t, nil
+		}
+	}
+	return nil, team.ErrTeamNotFound
+}
+
+func (m *teamServiceMock) AddTeamMember(userID, orgID, teamID int64, isExternal bool, permission dashboards.PermissionType) error {
+	m.added = append(m.added, teamID)
+	return nil
+}
+
+func (m *teamServiceMock) RemoveTeamMember(ctx context.Context, cmd *team.RemoveTeamMemberCommand) error {
+	m.removed = append(m.removed, cmd.TeamID)
+	return nil
+}
+
+type loginServiceMock struct {
+	login.Service
+	upserted []int64
+	disabled []string
+}
+
+func (m *loginServiceMock) UpsertUser(ctx context.Context, cmd *login.UpsertUserCommand) (*user.User, error) {
+	m.upserted = append(m.upserted, *cmd.UserLookupParams.UserID)
+	return &user.User{ID: *cmd.UserLookupParams.UserID}, nil
+}
+
+func (m *loginServiceMock) DisableExternalUser(ctx context.Context, username string) error {
+	m.disabled = append(m.disabled, username)
+	return nil
+}
+
+func TestSyncService_Sync(t *testing.T) {
+	setup := func(t *testing.T) (*SyncService, *loginServiceMock) {
+		t.Helper()
+
+		cfg := setting.NewCfg()
+		cfg.LDAPAuthEnabled = true
+		cfg.AdminUser = "admin"
+
+		userService := usertest.NewUserServiceFake()
+		userService.ExpectedSearchUsers = user.SearchUserQueryResult{Users: []*user.UserSearchHitDTO{
+			{ID: 1, Login: "admin"},
+			{ID: 2, Login: "alice"},
+			{ID: 3, Login: "bob"},
+		}}
+
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}
+
+		ldapService := service.NewLDAPFakeService()
+		ldapService.ExpectedClient = &multiLDAPMock{users: []*login.ExternalUserInfo{
+			{Login: "Alice", OrgRoles: map[int64]org.RoleType{1: org.RoleEditor}},
+		}}
+
+		loginService := &loginServiceMock{}
+		return ProvideService(cfg, nil, ldapService, ldap.ProvideGroupsService(), userService, orgService,
+			&teamtest.FakeService{}, loginService, authtest.NewFakeUserAuthTokenService(), kvstore.NewFakeKVStore()), loginService
+	}
+
+	t.Run("should report changes without applying them on dry run", func(t *testing.T) {
+		s, loginService := setup(t)
+
+		report, err := s.Sync(context.Background(), true)
+		require.NoError(t, err)
+
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 3, report.Checked)
+		require.Len(t, report.Users, 3)
+		assert.Equal(t, "admin", report.Users[0].Login)
+		assert.False(t, report.Users[0].Disable)
+		assert.NotEmpty(t, report.Users[0].Error)
+		assert.Equal(t, []OrgRoleDiff{{OrgID: 1, From: org.RoleViewer, To: org.RoleEditor}}, report.Users[1].OrgRoles)
+		assert.Equal(t, "bob", report.Users[2].Login)
+		assert.True(t, report.Users[2].Disable)
+
+		assert.Empty(t, loginService.upserted)
+		assert.Empty(t, loginService.disabled)
+	})
+
+	t.Run("should apply changes", func(t *testing.T) {
+		s, loginService := setup(t)
+
+		report, err := s.Sync(context.Background(), false)
+		require.NoError(t, err)
+
+		assert.False(t, report.DryRun)
+		assert.Equal(t, []int64{2}, loginService.upserted)
+		assert.Equal(t, []string{"bob"}, loginService.disabled)
+	})
+
+	t.Run("should not disable users missing from LDAP while a server is unreachable", func(t *testing.T) {
+		s, loginService := setup(t)
+		s.ldapService.Client().(*multiLDAPMock).unreachable = true
+
+		report, err := s.Sync(context.Background(), false)
+		require.NoError(t, err)
+
+		require.Len(t, report.Users, 3)
+		assert.Equal(t, "bob", report.Users[2].Login)
+		assert.False(t, report.Users[2].Disable)
+		assert.NotEmpty(t, report.Users[2].Error)
+		assert.Empty(t, loginService.disabled)
+		assert.Equal(t, []int64{2}, loginService.upserted)
+	})
+
+	t.Run("should add mapped teams and remove teams that are no longer mapped", func(t *testing.T) {
+		s, _ := setup(t)
+		s.orgService.(*orgtest.FakeOrgService).ExpectedOrg = &org.Org{ID: 1, Name: "Main Org."}
+		s.ldapGroups = &groupsMock{teams: []ldap.TeamOrgGroupDTO{
+			{TeamName: "editors", OrgName: "Main Org.", GroupDN: "cn=editors"},
+			{TeamName: "admins", OrgName: "Main Org.", GroupDN: "cn=admins"},
+			{TeamName: "missing", OrgName: "Main Org.", GroupDN: "cn=missing"},
+		}}
+		teamService := &teamServiceMock{
+			FakeService: &teamtest.FakeService{ExpectedMembers: []*team.TeamMemberDTO{
+				{OrgID: 1, TeamID: 11, UserID: 2, External: true},
+				{OrgID: 1, TeamID: 12, UserID: 2, External: true},
+				{OrgID: 1, TeamID: 13, UserID: 2},
+				{OrgID: 1, TeamID: 14, UserID: 2, External: true},
+			}},
+			teams: map[string]*team.TeamDTO{
+				"editors": {ID: 10, OrgID: 1, Name: "editors"},
+				"admins":  {ID: 11, OrgID: 1, Name: "admins"},
+				"former":  {ID: 12, OrgID: 1, Name: "former"},
+				"manual":  {ID: 13, OrgID: 1, Name: "manual"},
+				"scim":    {ID: 14, OrgID: 1, Name: "scim"},
+			},
+		}
+		s.teamService = teamService
+		// the membership of team 14 was added by another external system
+		require.NoError(t, s.kv.Set(context.Background(), "teams.2", "[11,12]"))
+
+		report, err := s.Sync(context.Background(), true)
+		require.NoError(t, err)
+		require.Len(t, report.Users, 3)
+		assert.Equal(t, []TeamDiff{{OrgID: 1, TeamID: 10, TeamName: "editors"}}, report.Users[1].TeamsAdded)
+		assert.Equal(t, []TeamDiff{{OrgID: 1, TeamID: 12, TeamName: "former"}}, report.Users[1].TeamsRemoved)
+		assert.Empty(t, teamService.added)
+		assert.Empty(t, teamService.removed)
+
+		_, err = s.Sync(context.Background(), false)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{10}, teamService.added)
+		assert.Equal(t, []int64{12}, teamService.removed)
+
+		recorded, err := s.addedTeams(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{10, 11}, recorded)
+	})
+
+	t.Run("should not sync teams when no LDAP group is mapped to a team", func(t *testing.T) {
+		s, _ := setup(t)
+		teamService := &teamServiceMock{
+			FakeService: &teamtest.FakeService{ExpectedMembers: []*team.TeamMemberDTO{
+				{OrgID: 1, TeamID: 12, UserID: 2, External: true},
+			}},
+			teams: map[string]*team.TeamDTO{
+				"former": {ID: 12, OrgID: 1, Name: "former"},
+			},
+		}
+		s.teamService = teamService
+		require.NoError(t, s.kv.Set(context.Background(), "teams.2", "[12]"))
+
+		report, err := s.Sync(context.Background(), false)
+		require.NoError(t, err)
+		require.Len(t, report.Users, 3)
+		assert.Empty(t, report.Users[1].TeamsRemoved)
+		assert.Empty(t, teamService.added)
+		assert.Empty(t, teamService.removed)
+	})
+
+	t.Run("should fail when LDAP is disabled", func(t *testing.T) {
+		s, _ := setup(t)
+		s.cfg.LDAPAuthEnabled = false
+
+		_, err := s.Sync(context.Background(), true)
+		require.ErrorIs(t, err, ErrLDAPDisabled)
+	})
+}
@@ -2,6 +2,7 @@ package multildap
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -25,6 +26,8 @@ var (
 	ErrNoLDAPServers = errors.New("no LDAP servers are configured")
 	// ErrDidNotFindUser if request for user is unsuccessful
 	ErrDidNotFindUser = errors.New("did not find a user")
+	// ErrUnreachableServer is returned by UsersFromAllServers when one of the LDAP servers cannot be reached
+	ErrUnreachableServer = errors.New("unable to reach LDAP server")
 )
 
 // ServerStatus holds the LDAP server status
@@ -46,6 +49,10 @@ type IMultiLDAP interface {
 		[]*login.ExternalUserInfo, error,
 	)
 
+	UsersFromAllServers(logins []string) (
+		[]*login.ExternalUserInfo, error,
+	)
+
 	User(login string) (
 		*login.ExternalUserInfo, ldap.ServerConfig, error,
 	)
@@ -201,6 +208,22 @@ func (multiples *MultiLDAP) User(login string) (
 func (multiples *MultiLDAP) Users(logins []string) (
 	[]*login.ExternalUserInfo,
 	error,
+) {
+	return multiples.users(logins, false)
+}
+
+// UsersFromAllServers gets users from multiple LDAP servers like Users, but fails when any of
+// the servers cannot be reached, so that a login missing from the result is missing from all servers
+func (multiples *MultiLDAP) UsersFromAllServers(logins []string) (
+	[]*login.ExternalUserInfo,
+	error,
+) {
+	return multiples.users(logins, true)
+}
+
+func (multiples *MultiLDAP) users(logins []string, requireAllServers bool) (
+	[]*login.ExternalUserInfo,
+	error,
 ) {
 	var result []*login.ExternalUserInfo
 
@@ -214,6 +237,10 @@ func (multiples *MultiLDAP) Users(logins []string) (
 		if err := server.Dial(); err != nil {
 			logDialFailure(err, config)
 
+			if requireAllServers {
+				return nil, fmt.Errorf("%w %s:%d: %s", ErrUnreachableServer, config.Host, config.Port, err)
+			}
+
 			// Only return an error if it is the last server so we can try next server
 			if index == len(multiples.configs)-1 {
 				return nil, err
@@ -413,6 +413,23 @@ func TestMultiLDAP(t *testing.T) {
 			teardown()
 		})
 
+		t.Run("Should fail when any server is unreachable if all servers are required", func(t *testing.T) {
+			mock := setup()
+
+			mock.dialErrReturn = errors.New("Dial error")
+
+			multi := New([]*ldap.ServerConfig{
+				{}, {},
+			}, setting.NewCfg())
+			_, err := multi.UsersFromAllServers([]string{"test"})
+
+			require.Equal(t, 1, mock.dialCalledTimes)
+			require.Equal(t, 0, mock.usersCalledTimes)
+			require.ErrorIs(t, err, ErrUnreachableServer)
+
+			teardown()
+		})
+
 		t.Run("Should get users", func(t *testing.T) {
 			mock := setup()
 
@@ -3,6 +3,7 @@ package libraryelements
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -23,6 +24,10 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 		entities.Get("/:uid/connections/", middleware.ReqSignedIn, routing.Wrap(l.getConnectionsHandler))
 		entities.Get("/name/:name", middleware.ReqSignedIn, routing.Wrap(l.getByNameHandler))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(l.patchHandler))
+		entities.Get("/:uid/versions", middleware.ReqSignedIn, routing.Wrap(l.getVersionsHandler))
+		entities.Get("/:uid/versions/:version", middleware.ReqSignedIn, routing.Wrap(l.getVersionHandler))
+		entities.Post("/:uid/restore", middleware.ReqSignedIn, routing.Wrap(l.restoreVersionHandler))
+		entities.Post("/:uid/diff", middleware.ReqSignedIn, routing.Wrap(l.diffHandler))
 	})
 }
 
@@ -242,6 +247,110 @@ func (l *LibraryElementService) getByNameHandler(c *contextmodel.ReqContext) res
 	return response.JSON(http.StatusOK, model.LibraryElementArrayResponse{Result: elements})
 }
 
+// swagger:route GET /library-elements/{library_element_uid}/versions library_elements getLibraryElementVersions
+//
+// Get library element versions.
+//
+// Returns the saved versions of a library element, newest first. The models of the versions are omitted.
+//
+// Responses:
+// 200: getLibraryElementVersionsResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	query := model.ListLibraryElementVersionsQuery{
+		Limit: c.QueryInt("limit"),
+		Start: c.QueryInt("start"),
+	}
+	versions, err := l.listLibraryElementVersions(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], query)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionsResponse{Result: versions})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/versions/{library_element_version} library_elements getLibraryElementVersion
+//
+// Get a library element version.
+//
+// Returns a saved version of a library element, including its model.
+//
+// Responses:
+// 200: getLibraryElementVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+	elementVersion, err := l.getLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionResponse{Result: elementVersion})
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/restore library_elements restoreLibraryElementVersion
+//
+// Restore a library element version.
+//
+// Saves the name and model of a previous version as a new version of the library element.
+// The element stays in its current folder. Every connected dashboard shows the restored version.
+//
+// Responses:
+// 200: getLibraryElementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) restoreVersionHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := model.RestoreLibraryElementVersionCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	element, err := l.restoreLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], cmd.Version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to restore library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementResponse{Result: element})
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/diff library_elements diffLibraryElement
+//
+// Compare library element versions.
+//
+// Compares two versions of a library element, or a version with an unsaved model, and lists the
+// connected dashboards the change would affect. The base version defaults to the current version.
+//
+// Responses:
+// 200: diffLibraryElementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) diffHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := model.DiffLibraryElementCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := l.diffLibraryElement(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], cmd)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to compare library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementDiffResponse{Result: result})
+}
+
 func toLibraryElementError(err error, message string) response.Response {
 	if errors.Is(err, model.ErrLibraryElementAlreadyExists) {
 		return response.Error(400, model.ErrLibraryElementAlreadyExists.Error(), err)
@@ -270,10 +379,16 @@ func toLibraryElementError(err error, message string) response.Response {
 	if errors.Is(err, model.ErrLibraryElementUIDTooLong) {
 		return response.Error(400, model.ErrLibraryElementUIDTooLong.Error(), err)
 	}
+	if errors.Is(err, model.ErrLibraryElementVersionNotFound) {
+		return response.Error(404, model.ErrLibraryElementVersionNotFound.Error(), err)
+	}
+	if errors.Is(err, model.ErrLibraryElementDiffNothingToCompare) {
+		return response.Error(400, model.ErrLibraryElementDiffNothingToCompare.Error(), err)
+	}
 	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
 }
 
-// swagger:parameters getLibraryElementByUID getLibraryElementConnections
+// swagger:parameters getLibraryElementByUID getLibraryElementConnections getLibraryElementVersions
 type LibraryElementByUID struct {
 	// in:path
 	// required:true
@@ -388,3 +503,65 @@ type GetLibraryElementConnectionsResponse struct {
 	// in: body
 	Body model.LibraryElementConnectionsResponse `json:"body"`
 }
+
+// swagger:parameters getLibraryElementVersions
+type GetLibraryElementVersionsParams struct {
+	// Maximum number of results to return
+	// in:query
+	// required:false
+	// default: 1000
+	Limit int `json:"limit"`
+	// Version to start from when returning queries
+	// in:query
+	// required:false
+	// default: 0
+	Start int `json:"start"`
+}
+
+// swagger:parameters getLibraryElementVersion
+type GetLibraryElementVersionParams struct {
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+	// in:path
+	// required:true
+	Version int64 `json:"library_element_version"`
+}
+
+// swagger:parameters restoreLibraryElementVersion
+type RestoreLibraryElementVersionParams struct {
+	// in:body
+	// required:true
+	Body model.RestoreLibraryElementVersionCommand `json:"body"`
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+}
+
+// swagger:parameters diffLibraryElement
+type DiffLibraryElementParams struct {
+	// in:body
+	// required:true
+	Body model.DiffLibraryElementCommand `json:"body"`
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+}
+
+// swagger:response getLibraryElementVersionsResponse
+type GetLibraryElementVersionsResponse struct {
+	// in: body
+	Body model.LibraryElementVersionsResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementVersionResponse
+type GetLibraryElementVersionResponse struct {
+	// in: body
+	Body model.LibraryElementVersionResponse `json:"body"`
+}
+
+// swagger:response diffLibraryElementResponse
+type DiffLibraryElementResponse struct {
+	// in: body
+	Body model.LibraryElementDiffResponse `json:"body"`
+}
@@ -150,7 +150,7 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		return insertLibraryElementVersion(session, element, 0)
 	})
 
 	dto := model.LibraryElementDTO{
@@ -211,6 +211,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 			return model.ErrLibraryElementHasConnections
 		}
 
+		if err := deleteLibraryElementVersions(session, element.ID); err != nil {
+			return err
+		}
 		result, err := session.Exec("DELETE FROM library_element WHERE id=?", element.ID)
 		if err != nil {
 			return err
@@ -540,6 +543,9 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		if err := syncFieldsWithModel(&libraryElement); err != nil {
 			return err
 		}
+		if err := ensureLibraryElementVersion(session, elementInDB); err != nil {
+			return err
+		}
 		if rowsAffected, err := session.ID(elementInDB.ID).Update(&libraryElement); err != nil {
 			if l.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
 				return model.ErrLibraryElementAlreadyExists
@@ -548,6 +554,9 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if err := insertLibraryElementVersion(session, libraryElement, cmd.RestoredFrom); err != nil {
+			return err
+		}
 
 		dto = model.LibraryElementDTO{
 			ID:          libraryElement.ID,
@@ -783,6 +792,9 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 			if err != nil {
 				return err
 			}
+			if err := deleteLibraryElementVersions(session, elementID.ID); err != nil {
+				return err
+			}
 		}
 		if _, err := session.Exec("DELETE FROM library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.OrgID); err != nil {
 			return err
@@ -30,6 +30,12 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.Rout
 type Service interface {
 	CreateElement(c context.Context, signedInUser *user.SignedInUser, cmd model.CreateLibraryElementCommand) (model.LibraryElementDTO, error)
 	GetElement(c context.Context, signedInUser *user.SignedInUser, UID string) (model.LibraryElementDTO, error)
+	GetAllElements(c context.Context, signedInUser *user.SignedInUser, query model.SearchLibraryElementsQuery) (model.LibraryElementSearchResult, error)
+	PatchElement(c context.Context, signedInUser *user.SignedInUser, cmd model.PatchLibraryElementCommand, UID string) (model.LibraryElementDTO, error)
+	DeleteElement(c context.Context, signedInUser *user.SignedInUser, UID string) (int64, error)
+	ListElementVersions(c context.Context, signedInUser *user.SignedInUser, UID string, query model.ListLibraryElementVersionsQuery) ([]model.LibraryElementVersionDTO, error)
+	GetElementVersion(c context.Context, signedInUser *user.SignedInUser, UID string, version int64) (model.LibraryElementVersionDTO, error)
+	RestoreElementVersion(c context.Context, signedInUser *user.SignedInUser, UID string, version int64) (model.LibraryElementDTO, error)
 	GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]model.LibraryElementDTO, error)
 	ConnectElementsToDashboard(c context.Context, signedInUser *user.SignedInUser, elementUIDs []string, dashboardID int64) error
 	DisconnectElementsFromDashboard(c context.Context, dashboardID int64) error
@@ -56,6 +62,36 @@ func (l *LibraryElementService) GetElement(c context.Context, signedInUser *user
 	return l.getLibraryElementByUid(c, signedInUser, UID)
 }
 
+// GetAllElements gets the elements matching the query that the user can view.
+func (l *LibraryElementService) GetAllElements(c context.Context, signedInUser *user.SignedInUser, query model.SearchLibraryElementsQuery) (model.LibraryElementSearchResult, error) {
+	return l.getAllLibraryElements(c, signedInUser, query)
+}
+
+// PatchElement updates an element identified by UID.
+func (l *LibraryElementService) PatchElement(c context.Context, signedInUser *user.SignedInUser, cmd model.PatchLibraryElementCommand, UID string) (model.LibraryElementDTO, error) {
+	return l.patchLibraryElement(c, signedInUser, cmd, UID)
+}
+
+// DeleteElement deletes an element identified by UID and returns its ID.
+func (l *LibraryElementService) DeleteElement(c context.Context, signedInUser *user.SignedInUser, UID string) (int64, error) {
+	return l.deleteLibraryElement(c, signedInUser, UID)
+}
+
+// ListElementVersions lists the saved versions of an element, newest first.
+func (l *LibraryElementService) ListElementVersions(c context.Context, signedInUser *user.SignedInUser, UID string, query model.ListLibraryElementVersionsQuery) ([]model.LibraryElementVersionDTO, error) {
+	return l.listLibraryElementVersions(c, signedInUser, UID, query)
+}
+
+// GetElementVersion gets a saved version of an element.
+func (l *LibraryElementService) GetElementVersion(c context.Context, signedInUser *user.SignedInUser, UID string, version int64) (model.LibraryElementVersionDTO, error) {
+	return l.getLibraryElementVersion(c, signedInUser, UID, version)
+}
+
+// RestoreElementVersion saves a previous version of an element as its newest version.
+func (l *LibraryElementService) RestoreElementVersion(c context.Context, signedInUser *user.SignedInUser, UID string, version int64) (model.LibraryElementDTO, error) {
+	return l.restoreLibraryElementVersion(c, signedInUser, UID, version)
+}
+
 // GetElementsForDashboard gets all connected elements for a specific dashboard.
 func (l *LibraryElementService) GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]model.LibraryElementDTO, error) {
 	return l.getElementsForDashboardID(c, dashboardID)
@@ -0,0 +1,154 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestLibraryElementVersions(t *testing.T) {
+	patchPanel := func(t *testing.T, sc scenarioContext, version int64, title string) {
+		t.Helper()
+		cmd := model.PatchLibraryElementCommand{
+			FolderID: -1,
+			Model:    []byte(`{"title": "` + title + `", "type": "text"}`),
+			Kind:     int64(model.PanelElement),
+			Version:  version,
+		}
+		sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+		sc.reqContext.Req.Body = mockRequestBody(cmd)
+		resp := sc.service.patchHandler(sc.reqContext)
+		require.Equal(t, 200, resp.Status())
+	}
+
+	scenarioWithPanel(t, "When an admin lists the versions of a patched library panel, it should return them newest first without models",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanel(t, sc, 1, "Patched")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.getVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementVersionsResponse
+			unmarshalVersionResponse(t, resp, &result)
+
+			require.Len(t, result.Result, 2)
+			require.Equal(t, int64(2), result.Result[0].Version)
+			require.Equal(t, int64(1), result.Result[1].Version)
+			require.Equal(t, sc.initialResult.Result.UID, result.Result[0].ElementUID)
+			require.Nil(t, result.Result[0].Model)
+		})
+
+	scenarioWithPanel(t, "When an admin gets a version of a library panel, it should return its model",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanel(t, sc, 1, "Patched")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementVersionResponse
+			unmarshalVersionResponse(t, resp, &result)
+			require.Equal(t, "Text - Library Panel", simplejson.MustJson(result.Result.Model).Get("title").MustString())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "99"})
+			resp = sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin restores a version of a library panel, it should save it as a new version",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			patchPanel(t, sc, 1, "Patched")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(model.RestoreLibraryElementVersionCommand{Version: 1})
+			resp := sc.service.restoreVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			restored := validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, int64(3), restored.Result.Version)
+			require.Equal(t, "Text - Library Panel", restored.Result.Model["title"])
+			require.Equal(t, sc.initialResult.Result.FolderID, restored.Result.FolderID)
+
+			resp = sc.service.getVersionsHandler(sc.reqContext)
+			var versions model.LibraryElementVersionsResponse
+			unmarshalVersionResponse(t, resp, &versions)
+			require.Len(t, versions.Result, 3)
+			require.Equal(t, int64(1), versions.Result[0].RestoredFrom)
+		})
+
+	scenarioWithPanel(t, "When an admin restores a version that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(model.RestoreLibraryElementVersionCommand{Version: 42})
+			resp := sc.service.restoreVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin compares versions of a connected library panel, it should list the affected dashboards",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			dash := dashboards.Dashboard{
+				Title: "Testing diff",
+				Data:  simplejson.NewFromAny(map[string]interface{}{}),
+			}
+			dashInDB := createDashboard(t, sc.sqlStore, sc.user, &dash, sc.folder.ID)
+			err := sc.service.ConnectElementsToDashboard(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, []string{sc.initialResult.Result.UID}, dashInDB.ID)
+			require.NoError(t, err)
+			patchPanel(t, sc, 1, "Patched")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(model.DiffLibraryElementCommand{BaseVersion: 1, NewVersion: 2})
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementDiffResponse
+			unmarshalVersionResponse(t, resp, &result)
+
+			require.Equal(t, int64(1), result.Result.BaseVersion)
+			require.NotEmpty(t, result.Result.Delta)
+			require.Equal(t, int64(1), result.Result.ConnectedDashboards)
+			require.Len(t, result.Result.AffectedDashboards, 1)
+			require.Equal(t, dashInDB.UID, result.Result.AffectedDashboards[0].UID)
+			require.Equal(t, sc.folder.UID, result.Result.AffectedDashboards[0].FolderUID)
+		})
+
+	scenarioWithPanel(t, "When an admin compares the current version with an identical model, the delta should be empty",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			current, err := json.Marshal(sc.initialResult.Result.Model)
+			require.NoError(t, err)
+			sc.reqContext.Req.Body = mockRequestBody(model.DiffLibraryElementCommand{Model: current})
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementDiffResponse
+			unmarshalVersionResponse(t, resp, &result)
+			require.Empty(t, result.Result.Delta)
+			require.Empty(t, result.Result.AffectedDashboards)
+		})
+
+	scenarioWithPanel(t, "When an admin compares without a version or model, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.SignedInUser.Permissions[sc.reqContext.OrgID][dashboards.ActionFoldersRead] = []string{dashboards.ScopeFoldersAll}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(model.DiffLibraryElementCommand{})
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
+
+func unmarshalVersionResponse(t *testing.T, resp response.Response, v interface{}) {
+	t.Helper()
+
+	require.Equal(t, 200, resp.Status())
+	err := json.Unmarshal(resp.Body(), v)
+	require.NoError(t, err)
+}
@@ -133,6 +133,74 @@ type LibraryElementConnectionDTO struct {
 	CreatedBy     librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
 }
 
+// LibraryElementVersion is the model for a saved version of a library element.
+type LibraryElementVersion struct {
+	ID           int64 `xorm:"pk autoincr 'id'"`
+	OrgID        int64 `xorm:"org_id"`
+	ElementID    int64 `xorm:"element_id"`
+	Version      int64
+	RestoredFrom int64
+	Name         string
+	FolderID     int64 `xorm:"folder_id"`
+	Model        json.RawMessage
+	Created      time.Time
+	CreatedBy    int64
+}
+
+// LibraryElementVersionWithMeta is the model used to retrieve versions with the creator's details.
+type LibraryElementVersionWithMeta struct {
+	ID             int64 `xorm:"pk autoincr 'id'"`
+	OrgID          int64 `xorm:"org_id"`
+	ElementID      int64 `xorm:"element_id"`
+	Version        int64
+	RestoredFrom   int64
+	Name           string
+	FolderID       int64 `xorm:"folder_id"`
+	Model          json.RawMessage
+	Created        time.Time
+	CreatedBy      int64
+	CreatedByName  string
+	CreatedByEmail string
+}
+
+// LibraryElementVersionDTO is the frontend DTO for element versions.
+type LibraryElementVersionDTO struct {
+	ID           int64                                  `json:"id"`
+	ElementID    int64                                  `json:"elementId"`
+	ElementUID   string                                 `json:"elementUid"`
+	Version      int64                                  `json:"version"`
+	RestoredFrom int64                                  `json:"restoredFrom,omitempty"`
+	Name         string                                 `json:"name"`
+	FolderID     int64                                  `json:"folderId"`
+	Model        json.RawMessage                        `json:"model,omitempty"`
+	Created      time.Time                              `json:"created"`
+	CreatedBy    librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
+}
+
+// LibraryElementAffectedDashboard is a dashboard connected to a library element that a change would affect.
+type LibraryElementAffectedDashboard struct {
+	ID        int64  `xorm:"id" json:"id"`
+	UID       string `xorm:"uid" json:"uid"`
+	Title     string `xorm:"title" json:"title"`
+	FolderID  int64  `xorm:"folder_id" json:"folderId"`
+	FolderUID string `xorm:"folder_uid" json:"folderUid"`
+}
+
+// LibraryElementDiffResult is the result of comparing two versions of a library element.
+type LibraryElementDiffResult struct {
+	// BaseVersion is the version the comparison started from.
+	BaseVersion int64 `json:"baseVersion"`
+	// NewVersion is the version compared against, zero when an unsaved model was compared.
+	NewVersion int64 `json:"newVersion,omitempty"`
+	// Delta is the JSON diff between the two models, empty when they are identical.
+	// swagger:type object
+	Delta json.RawMessage `json:"delta,omitempty"`
+	// AffectedDashboards are the connected dashboards the user can see.
+	AffectedDashboards []LibraryElementAffectedDashboard `json:"affectedDashboards"`
+	// ConnectedDashboards is the total number of connected dashboards, including the ones the user can't see.
+	ConnectedDashboards int64 `json:"connectedDashboards"`
+}
+
 var (
 	// errLibraryElementAlreadyExists is an error for when the user tries to add a library element that already exists.
 	ErrLibraryElementAlreadyExists = errors.New("library element with that name or UID already exists")
@@ -152,6 +220,10 @@ var (
 	ErrLibraryElementInvalidUID = errors.New("uid contains illegal characters")
 	// errLibraryElementUIDTooLong is an error for when the uid of a library element is invalid
 	ErrLibraryElementUIDTooLong = errors.New("uid too long, max 40 characters")
+	// ErrLibraryElementVersionNotFound is an error for when a version of a library element can't be found.
+	ErrLibraryElementVersionNotFound = errors.New("library element version could not be found")
+	// ErrLibraryElementDiffNothingToCompare is an error for when a diff request has neither a new version nor a model.
+	ErrLibraryElementDiffNothingToCompare = errors.New("either a version or a model to compare with is required")
 )
 
 // Commands
@@ -198,6 +270,34 @@ type PatchLibraryElementCommand struct {
 	Version int64 `json:"version" binding:"Required"`
 	// required: false
 	UID string `json:"uid"`
+	// RestoredFrom is the version the patch restores, if any.
+	RestoredFrom int64 `json:"-"`
+}
+
+// RestoreLibraryElementVersionCommand is the command for restoring a previous version of a LibraryElement
+// swagger:model
+type RestoreLibraryElementVersionCommand struct {
+	// Version of the library element to restore.
+	// required: true
+	Version int64 `json:"version" binding:"Required"`
+}
+
+// DiffLibraryElementCommand is the command for comparing versions of a LibraryElement
+// swagger:model
+type DiffLibraryElementCommand struct {
+	// Version to compare from, defaults to the current version.
+	BaseVersion int64 `json:"baseVersion"`
+	// Version to compare with. Either this or model is required.
+	NewVersion int64 `json:"newVersion"`
+	// Unsaved model to compare with.
+	// swagger:type object
+	Model json.RawMessage `json:"model,omitempty"`
+}
+
+// ListLibraryElementVersionsQuery is the query used for listing the versions of an element
+type ListLibraryElementVersionsQuery struct {
+	Limit int
+	Start int
 }
 
 // SearchLibraryElementsQuery is the query used for searching for Elements
@@ -233,6 +333,21 @@ type LibraryElementConnectionsResponse struct {
 	Result []LibraryElementConnectionDTO `json:"result"`
 }
 
+// LibraryElementVersionResponse is a response struct for LibraryElementVersionDTO.
+type LibraryElementVersionResponse struct {
+	Result LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementVersionsResponse is a response struct for an array of LibraryElementVersionDTO.
+type LibraryElementVersionsResponse struct {
+	Result []LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementDiffResponse is a response struct for LibraryElementDiffResult.
+type LibraryElementDiffResponse struct {
+	Result LibraryElementDiffResult `json:"result"`
+}
+
 // DeleteLibraryElementResponse is the response struct for deleting a library element.
 type DeleteLibraryElementResponse struct {
 	ID      int64  `json:"id"`
@@ -249,4 +364,7 @@ const (
 	VariableElement
 )
 
-const LibraryElementConnectionTableName = "library_element_connection"
+const (
+	LibraryElementConnectionTableName = "library_element_connection"
+	LibraryElementVersionTableName    = "library_element_version"
+)
@@ -0,0 +1,257 @@
+package libraryelements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/kinds/librarypanel"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const defaultVersionsLimit = 1000
+
+// insertLibraryElementVersion stores the state of element as a version.
+func insertLibraryElementVersion(session *db.Session, element model.LibraryElement, restoredFrom int64) error {
+	version := model.LibraryElementVersion{
+		OrgID:        element.OrgID,
+		ElementID:    element.ID,
+		Version:      element.Version,
+		RestoredFrom: restoredFrom,
+		Name:         element.Name,
+		FolderID:     element.FolderID,
+		Model:        element.Model,
+		Created:      element.Updated,
+		CreatedBy:    element.UpdatedBy,
+	}
+	_, err := session.Insert(&version)
+	return err
+}
+
+// ensureLibraryElementVersion backfills the version of an element that was saved before
+// versions were recorded, so that it can be restored after being patched.
+func ensureLibraryElementVersion(session *db.Session, element model.LibraryElementWithMeta) error {
+	exists, err := session.Table(model.LibraryElementVersionTableName).
+		Where("element_id=? AND version=?", element.ID, element.Version).Exist()
+	if err != nil || exists {
+		return err
+	}
+
+	return insertLibraryElementVersion(session, model.LibraryElement{
+		ID:        element.ID,
+		OrgID:     element.OrgID,
+		FolderID:  element.FolderID,
+		Name:      element.Name,
+		Model:     element.Model,
+		Version:   element.Version,
+		Updated:   element.Updated,
+		UpdatedBy: element.UpdatedBy,
+	}, 0)
+}
+
+func deleteLibraryElementVersions(session *db.Session, elementID int64) error {
+	_, err := session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", elementID)
+	return err
+}
+
+func (l *LibraryElementService) getVersionsWithMeta(session *db.Session, elementID int64, where string, limit, start int, args ...interface{}) ([]model.LibraryElementVersionWithMeta, error) {
+	versions := make([]model.LibraryElementVersionWithMeta, 0)
+	sql := "SELECT lev.*, u.login AS created_by_name, u.email AS created_by_email" +
+		" FROM " + model.LibraryElementVersionTableName + " AS lev" +
+		" LEFT JOIN " + l.SQLStore.GetDialect().Quote("user") + " AS u ON lev.created_by = u.id" +
+		" WHERE lev.element_id=?" + where +
+		" ORDER BY lev.version DESC"
+	if limit > 0 {
+		sql += " " + l.SQLStore.GetDialect().LimitOffset(int64(limit), int64(start))
+	}
+	err := session.SQL(sql, append([]interface{}{elementID}, args...)...).Find(&versions)
+	return versions, err
+}
+
+func toLibraryElementVersionDTO(element model.LibraryElementDTO, version model.LibraryElementVersionWithMeta) model.LibraryElementVersionDTO {
+	return model.LibraryElementVersionDTO{
+		ID:           version.ID,
+		ElementID:    version.ElementID,
+		ElementUID:   element.UID,
+		Version:      version.Version,
+		RestoredFrom: version.RestoredFrom,
+		Name:         version.Name,
+		FolderID:     version.FolderID,
+		Model:        version.Model,
+		Created:      version.Created,
+		CreatedBy: librarypanel.LibraryElementDTOMetaUser{
+			Id:        version.CreatedBy,
+			Name:      version.CreatedByName,
+			AvatarUrl: dtos.GetGravatarUrl(version.CreatedByEmail),
+		},
+	}
+}
+
+// listLibraryElementVersions lists the versions of a Library Element, newest first, without their models.
+func (l *LibraryElementService) listLibraryElementVersions(c context.Context, signedInUser *user.SignedInUser, uid string, query model.ListLibraryElementVersionsQuery) ([]model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return nil, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultVersionsLimit
+	}
+	if query.Start < 0 {
+		query.Start = 0
+	}
+
+	result := make([]model.LibraryElementVersionDTO, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		versions, err := l.getVersionsWithMeta(session, element.ID, "", query.Limit, query.Start)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			dto := toLibraryElementVersionDTO(element, version)
+			dto.Model = nil
+			result = append(result, dto)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// getLibraryElementVersion gets a single version of a Library Element.
+func (l *LibraryElementService) getLibraryElementVersion(c context.Context, signedInUser *user.SignedInUser, uid string, version int64) (model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return model.LibraryElementVersionDTO{}, err
+	}
+
+	var dto model.LibraryElementVersionDTO
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		versions, err := l.getVersionsWithMeta(session, element.ID, " AND lev.version=?", 0, 0, version)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			return model.ErrLibraryElementVersionNotFound
+		}
+		dto = toLibraryElementVersionDTO(element, versions[0])
+		return nil
+	})
+	return dto, err
+}
+
+// restoreLibraryElementVersion saves the name and model of a previous version as the newest version
+// of a Library Element. The element keeps its current folder.
+func (l *LibraryElementService) restoreLibraryElementVersion(c context.Context, signedInUser *user.SignedInUser, uid string, version int64) (model.LibraryElementDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+	restore, err := l.getLibraryElementVersion(c, signedInUser, uid, version)
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+
+	return l.patchLibraryElement(c, signedInUser, model.PatchLibraryElementCommand{
+		FolderID:     -1,
+		Name:         restore.Name,
+		Model:        restore.Model,
+		Kind:         element.Kind,
+		Version:      element.Version,
+		RestoredFrom: restore.Version,
+	}, uid)
+}
+
+// diffLibraryElement compares two versions of a Library Element, or a version with an unsaved model,
+// and lists the connected dashboards the change would affect.
+func (l *LibraryElementService) diffLibraryElement(c context.Context, signedInUser *user.SignedInUser, uid string, cmd model.DiffLibraryElementCommand) (model.LibraryElementDiffResult, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return model.LibraryElementDiffResult{}, err
+	}
+	if cmd.NewVersion == 0 && len(cmd.Model) == 0 {
+		return model.LibraryElementDiffResult{}, model.ErrLibraryElementDiffNothingToCompare
+	}
+
+	modelForVersion := func(version int64) (json.RawMessage, error) {
+		if version == 0 || version == element.Version {
+			return element.Model, nil
+		}
+		v, err := l.getLibraryElementVersion(c, signedInUser, uid, version)
+		if err != nil {
+			return nil, err
+		}
+		return v.Model, nil
+	}
+
+	result := model.LibraryElementDiffResult{
+		BaseVersion:         cmd.BaseVersion,
+		NewVersion:          cmd.NewVersion,
+		AffectedDashboards:  make([]model.LibraryElementAffectedDashboard, 0),
+		ConnectedDashboards: element.Meta.ConnectedDashboards,
+	}
+	if result.BaseVersion == 0 {
+		result.BaseVersion = element.Version
+	}
+
+	baseModel, err := modelForVersion(cmd.BaseVersion)
+	if err != nil {
+		return model.LibraryElementDiffResult{}, err
+	}
+	newModel := cmd.Model
+	if cmd.NewVersion != 0 {
+		if newModel, err = modelForVersion(cmd.NewVersion); err != nil {
+			return model.LibraryElementDiffResult{}, err
+		}
+	}
+
+	baseJSON, err := simplejson.NewJson(baseModel)
+	if err != nil {
+		return model.LibraryElementDiffResult{}, err
+	}
+	newJSON, err := simplejson.NewJson(newModel)
+	if err != nil {
+		return model.LibraryElementDiffResult{}, err
+	}
+	diff, err := dashdiffs.CalculateDiff(c, &dashdiffs.Options{OrgId: signedInUser.OrgID, DiffType: dashdiffs.DiffDelta}, baseJSON, newJSON)
+	if err != nil && !errors.Is(err, dashdiffs.ErrNilDiff) {
+		return model.LibraryElementDiffResult{}, err
+	}
+	if diff != nil {
+		result.Delta = diff.Delta
+	}
+
+	if result.AffectedDashboards, err = l.getAffectedDashboards(c, signedInUser, element.ID); err != nil {
+		return model.LibraryElementDiffResult{}, err
+	}
+	return result, nil
+}
+
+// getAffectedDashboards gets the connected dashboards of a Library Element that the user can view.
+func (l *LibraryElementService) getAffectedDashboards(c context.Context, signedInUser *user.SignedInUser, elementID int64) ([]model.LibraryElementAffectedDashboard, error) {
+	affected := make([]model.LibraryElementAffectedDashboard, 0)
+	recursiveQueriesAreSupported, err := l.SQLStore.RecursiveQueriesAreSupported()
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		builder := db.NewSqlBuilder(l.Cfg, l.features, l.SQLStore.GetDialect(), recursiveQueriesAreSupported)
+		builder.Write("SELECT dashboard.id, dashboard.uid, dashboard.title, dashboard.folder_id, coalesce(parent.uid, '') AS folder_uid")
+		builder.Write(" FROM " + model.LibraryElementConnectionTableName + " AS lec")
+		builder.Write(" INNER JOIN dashboard AS dashboard ON lec.connection_id = dashboard.id")
+		builder.Write(" LEFT JOIN dashboard AS parent ON dashboard.folder_id = parent.id")
+		builder.Write(" WHERE lec.element_id=? AND lec.kind=?", elementID, model.Dashboard)
+		if signedInUser.OrgRole != org.RoleAdmin {
+			builder.WriteDashboardPermissionFilter(signedInUser, dashboards.PERMISSION_VIEW)
+		}
+		builder.Write(" ORDER BY dashboard.title ASC")
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&affected)
+	})
+	return affected, err
+}
@@ -50,6 +50,7 @@ const (
 	GrafanaComAuthModule = "oauth_grafana_com"
 	GrafanaNetAuthModule = "oauth_grafananet"
 	OktaAuthModule       = "oauth_okta"
+	OIDCAuthModule       = "oauth_oidc"
 
 	// labels
 	SAMLLabel = "SAML"
@@ -64,6 +65,7 @@ const (
 	GithubLabel       = "GitHub"
 	GrafanaComLabel   = "grafana.com"
 	OktaLabel         = "Okta"
+	OIDCLabel         = "OpenID Connect"
 )
 
 // IsExternnalySynced is used to tell if the user roles are externally synced
@@ -107,6 +109,8 @@ func IsExternallySynced(cfg *setting.Cfg, authModule string) bool {
 		return !cfg.GrafanaComSkipOrgRoleSync
 	case GenericOAuthModule:
 		return !cfg.GenericOAuthSkipOrgRoleSync
+	case OIDCAuthModule:
+		return !cfg.OIDCSkipOrgRoleSync
 	}
 	return true
 }
@@ -133,6 +137,8 @@ func IsProviderEnabled(cfg *setting.Cfg, authModule string) bool {
 		return cfg.GrafanaComAuthEnabled
 	case GenericOAuthModule:
 		return cfg.GenericOAuthAuthEnabled
+	case OIDCAuthModule:
+		return cfg.OIDCAuthEnabled
 	}
 	return false
 }
@@ -162,6 +168,8 @@ func GetAuthProviderLabel(authModule string) string {
 		return AuthProxyLabel
 	case GenericOAuthModule:
 		return GenericOAuthLabel
+	case OIDCAuthModule:
+		return OIDCLabel
 	default:
 		return "Unknown"
 	}
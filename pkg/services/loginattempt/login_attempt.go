@@ -2,6 +2,7 @@ package loginattempt
 
 import (
 	"context"
+	"time"
 )
 
 type Service interface {
@@ -10,13 +11,48 @@ type Service interface {
 	// Validate checks if username has to many login attempts inside a window.
 	// Will return true if provided username do not have too many attempts.
 	Validate(ctx context.Context, username string) (bool, error)
+	// ValidateIPAddress checks if the IP address or its subnet is locked out after too many login attempts.
+	// Will return true if the IP address is allowed to log in.
+	ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error)
 	// Reset resets all login attempts attached to username
 	Reset(ctx context.Context, username string) error
+	// ListLockouts returns the usernames, IP addresses and subnets that are currently locked out
+	ListLockouts(ctx context.Context) ([]*Lockout, error)
+	// ClearLockout removes the login attempts of a username, IP address or subnet
+	ClearLockout(ctx context.Context, cmd *ClearLockoutCommand) error
 }
 
 type LoginAttempt struct {
 	Id        int64
 	Username  string
 	IpAddress string
+	IpSubnet  string
 	Created   int64
 }
+
+type LockoutType string
+
+const (
+	LockoutTypeUsername LockoutType = "username"
+	LockoutTypeIP       LockoutType = "ip"
+	LockoutTypeSubnet   LockoutType = "subnet"
+)
+
+func (t LockoutType) IsValid() bool {
+	return t == LockoutTypeUsername || t == LockoutTypeIP || t == LockoutTypeSubnet
+}
+
+// Lockout is a username, IP address or subnet that can't be used to log in
+type Lockout struct {
+	Type LockoutType `json:"type"`
+	// Key is the username, IP address or subnet in CIDR notation
+	Key         string    `json:"key"`
+	Attempts    int64     `json:"attempts"`
+	LastAttempt time.Time `json:"lastAttempt"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+type ClearLockoutCommand struct {
+	Type LockoutType `json:"type" binding:"Required"`
+	Key  string      `json:"key" binding:"Required"`
+}
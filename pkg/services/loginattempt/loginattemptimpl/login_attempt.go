@@ -2,11 +2,14 @@ package loginattemptimpl
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -38,6 +41,7 @@ func (s *Service) Run(ctx context.Context) error {
 	}
 
 	ticker := time.NewTicker(time.Minute * 10)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
@@ -53,10 +57,16 @@ func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
 		return nil
 	}
 
-	_, err := s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
+	cmd := CreateLoginAttemptCommand{
 		Username:  username,
 		IpAddress: IPAddress,
-	})
+	}
+	if ip := parseIP(IPAddress); ip != nil {
+		cmd.IpAddress = ip.String()
+		cmd.IpSubnet = s.subnet(ip).String()
+	}
+
+	_, err := s.store.CreateLoginAttempt(ctx, cmd)
 	return err
 }
 
@@ -64,6 +74,173 @@ func (s *Service) Reset(ctx context.Context, username string) error {
 	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{username})
 }
 
+func (s *Service) ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error) {
+	if s.cfg.DisableBruteForceLoginProtection {
+		return true, nil
+	}
+
+	ip := parseIP(IPAddress)
+	if ip == nil || s.isAllowed(ip) {
+		return true, nil
+	}
+
+	now := time.Now()
+	checks := []struct {
+		lockoutType loginattempt.LockoutType
+		key         string
+		maxAttempts int64
+	}{
+		{loginattempt.LockoutTypeIP, ip.String(), s.cfg.BruteForce.IPMaxAttempts},
+		{loginattempt.LockoutTypeSubnet, s.subnet(ip).String(), s.cfg.BruteForce.SubnetMaxAttempts},
+	}
+
+	for _, check := range checks {
+		if check.maxAttempts <= 0 {
+			continue
+		}
+
+		stats, err := s.store.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{
+			Type:  check.lockoutType,
+			Key:   check.key,
+			Since: now.Add(-s.lockoutWindow()),
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if lockedUntil := s.lockedUntil(stats, check.maxAttempts); lockedUntil.After(now) {
+			s.logger.Debug("Login attempt blocked", "type", check.lockoutType, "key", check.key, "lockedUntil", lockedUntil)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *Service) ListLockouts(ctx context.Context) ([]*loginattempt.Lockout, error) {
+	lockouts := make([]*loginattempt.Lockout, 0)
+	if s.cfg.DisableBruteForceLoginProtection {
+		return lockouts, nil
+	}
+
+	now := time.Now()
+
+	users, err := s.store.ListLoginAttemptStats(ctx, ListLoginAttemptStatsQuery{
+		Type:        loginattempt.LockoutTypeUsername,
+		Since:       now.Add(-loginAttemptsWindow),
+		MinAttempts: maxInvalidLoginAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, stats := range users {
+		// usernames are unlocked once the attempts move out of the window, the last attempt is the latest it can happen
+		lockouts = append(lockouts, newLockout(loginattempt.LockoutTypeUsername, stats, time.Unix(stats.LastAttempt, 0).Add(loginAttemptsWindow)))
+	}
+
+	for _, t := range []loginattempt.LockoutType{loginattempt.LockoutTypeIP, loginattempt.LockoutTypeSubnet} {
+		maxAttempts := s.cfg.BruteForce.IPMaxAttempts
+		if t == loginattempt.LockoutTypeSubnet {
+			maxAttempts = s.cfg.BruteForce.SubnetMaxAttempts
+		}
+		if maxAttempts <= 0 {
+			continue
+		}
+
+		result, err := s.store.ListLoginAttemptStats(ctx, ListLoginAttemptStatsQuery{
+			Type:        t,
+			Since:       now.Add(-s.lockoutWindow()),
+			MinAttempts: maxAttempts,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stats := range result {
+			if lockedUntil := s.lockedUntil(stats, maxAttempts); lockedUntil.After(now) {
+				lockouts = append(lockouts, newLockout(t, stats, lockedUntil))
+			}
+		}
+	}
+
+	return lockouts, nil
+}
+
+func (s *Service) ClearLockout(ctx context.Context, cmd *loginattempt.ClearLockoutCommand) error {
+	key := cmd.Key
+	if cmd.Type == loginattempt.LockoutTypeIP {
+		if ip := parseIP(key); ip != nil {
+			key = ip.String()
+		}
+	}
+	return s.store.DeleteLoginAttemptsByKey(ctx, DeleteLoginAttemptsByKeyCommand{Type: cmd.Type, Key: key})
+}
+
+// lockedUntil returns when the lockout of an IP address or subnet ends. The first lockout happens after maxAttempts
+// failed logins, its duration doubles for every additional maxAttempts failed logins.
+func (s *Service) lockedUntil(stats LoginAttemptStats, maxAttempts int64) time.Time {
+	if stats.Attempts < maxAttempts {
+		return time.Time{}
+	}
+
+	duration := s.cfg.BruteForce.LockoutDuration
+	for level := stats.Attempts / maxAttempts; level > 1 && duration < s.cfg.BruteForce.MaxLockoutDuration; level-- {
+		duration *= 2
+	}
+	if duration > s.cfg.BruteForce.MaxLockoutDuration {
+		duration = s.cfg.BruteForce.MaxLockoutDuration
+	}
+
+	return time.Unix(stats.LastAttempt, 0).Add(duration)
+}
+
+// lockoutWindow is how long attempts count towards IP address and subnet lockouts
+func (s *Service) lockoutWindow() time.Duration {
+	if s.cfg.BruteForce.MaxLockoutDuration > loginAttemptsWindow {
+		return s.cfg.BruteForce.MaxLockoutDuration
+	}
+	return loginAttemptsWindow
+}
+
+func (s *Service) isAllowed(ip net.IP) bool {
+	for _, ipNet := range s.cfg.BruteForce.AllowedNetworks {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) subnet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(s.cfg.BruteForce.IPv4SubnetPrefix, 32)
+		return &net.IPNet{IP: ip4.Mask(mask), Mask: mask}
+	}
+	mask := net.CIDRMask(s.cfg.BruteForce.IPv6SubnetPrefix, 128)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+func parseIP(address string) net.IP {
+	ip, err := network.GetIPFromAddress(address)
+	if err != nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
+func newLockout(t loginattempt.LockoutType, stats LoginAttemptStats, lockedUntil time.Time) *loginattempt.Lockout {
+	return &loginattempt.Lockout{
+		Type:        t,
+		Key:         stats.Key,
+		Attempts:    stats.Attempts,
+		LastAttempt: time.Unix(stats.LastAttempt, 0),
+		LockedUntil: lockedUntil,
+	}
+}
+
 func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
 	if s.cfg.DisableBruteForceLoginProtection {
 		return true, nil
@@ -88,8 +265,13 @@ func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
 
 func (s *Service) cleanup(ctx context.Context) {
 	err := s.lock.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func(context.Context) {
+		// attempts are kept as long as they count towards IP address and subnet lockouts
+		retention := time.Minute * 10
+		if window := s.lockoutWindow(); window > retention {
+			retention = window
+		}
 		cmd := DeleteOldLoginAttemptsCommand{
-			OlderThan: time.Now().Add(time.Minute * -10),
+			OlderThan: time.Now().Add(-retention),
 		}
 		if deletedLogs, err := s.store.DeleteOldLoginAttempts(ctx, cmd); err != nil {
 			s.logger.Error("Problem deleting expired login attempts", "error", err.Error())
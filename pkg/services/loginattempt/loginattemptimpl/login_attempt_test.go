@@ -2,10 +2,14 @@ package loginattemptimpl
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -79,12 +83,126 @@ func TestService_Validate(t *testing.T) {
 	}
 }
 
+func TestService_ValidateIPAddress(t *testing.T) {
+	now := time.Now()
+	_, trusted, _ := net.ParseCIDR("10.0.0.0/8")
+
+	testCases := []struct {
+		name     string
+		address  string
+		stats    map[string]LoginAttemptStats
+		disabled bool
+		expected bool
+	}{
+		{
+			name:     "should allow IP address without attempts",
+			address:  "192.168.1.10",
+			expected: true,
+		},
+		{
+			name:     "should allow IP address below max attempts",
+			address:  "192.168.1.10",
+			stats:    map[string]LoginAttemptStats{"192.168.1.10": {Attempts: 19, LastAttempt: now.Unix()}},
+			expected: true,
+		},
+		{
+			name:     "should block IP address at max attempts",
+			address:  "192.168.1.10:3000",
+			stats:    map[string]LoginAttemptStats{"192.168.1.10": {Attempts: 20, LastAttempt: now.Unix()}},
+			expected: false,
+		},
+		{
+			name:     "should allow IP address once the lockout expired",
+			address:  "192.168.1.10",
+			stats:    map[string]LoginAttemptStats{"192.168.1.10": {Attempts: 20, LastAttempt: now.Add(-6 * time.Minute).Unix()}},
+			expected: true,
+		},
+		{
+			name:     "should double the lockout when the attempts exceed the limit again",
+			address:  "192.168.1.10",
+			stats:    map[string]LoginAttemptStats{"192.168.1.10": {Attempts: 40, LastAttempt: now.Add(-6 * time.Minute).Unix()}},
+			expected: false,
+		},
+		{
+			name:     "should cap the lockout duration",
+			address:  "192.168.1.10",
+			stats:    map[string]LoginAttemptStats{"192.168.1.10": {Attempts: 1000, LastAttempt: now.Add(-61 * time.Minute).Unix()}},
+			expected: true,
+		},
+		{
+			name:     "should block subnet at max attempts",
+			address:  "192.168.1.10",
+			stats:    map[string]LoginAttemptStats{"192.168.1.0/24": {Attempts: 100, LastAttempt: now.Unix()}},
+			expected: false,
+		},
+		{
+			name:     "should block IPv6 subnet at max attempts",
+			address:  "[2001:db8::1]:3000",
+			stats:    map[string]LoginAttemptStats{"2001:db8::/64": {Attempts: 100, LastAttempt: now.Unix()}},
+			expected: false,
+		},
+		{
+			name:     "should allow trusted networks",
+			address:  "10.1.2.3",
+			stats:    map[string]LoginAttemptStats{"10.1.2.3": {Attempts: 100, LastAttempt: now.Unix()}},
+			expected: true,
+		},
+		{
+			name:     "should allow when brute force protection is disabled",
+			address:  "192.168.1.10",
+			stats:    map[string]LoginAttemptStats{"192.168.1.10": {Attempts: 20, LastAttempt: now.Unix()}},
+			disabled: true,
+			expected: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.DisableBruteForceLoginProtection = tt.disabled
+			cfg.BruteForce = setting.BruteForceSettings{
+				IPMaxAttempts:      20,
+				SubnetMaxAttempts:  100,
+				IPv4SubnetPrefix:   24,
+				IPv6SubnetPrefix:   64,
+				LockoutDuration:    5 * time.Minute,
+				MaxLockoutDuration: time.Hour,
+				AllowedNetworks:    []*net.IPNet{trusted},
+			}
+			service := &Service{
+				store:  fakeStore{ExpectedStats: tt.stats},
+				cfg:    cfg,
+				logger: log.NewNopLogger(),
+			}
+
+			ok, err := service.ValidateIPAddress(context.Background(), tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
 var _ store = new(fakeStore)
 
 type fakeStore struct {
 	ExpectedErr         error
 	ExpectedCount       int64
 	ExpectedDeletedRows int64
+	ExpectedStats       map[string]LoginAttemptStats
+}
+
+func (f fakeStore) GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	stats := f.ExpectedStats[query.Key]
+	stats.Key = query.Key
+	return stats, f.ExpectedErr
+}
+
+func (f fakeStore) ListLoginAttemptStats(ctx context.Context, query ListLoginAttemptStatsQuery) ([]LoginAttemptStats, error) {
+	return nil, f.ExpectedErr
+}
+
+func (f fakeStore) DeleteLoginAttemptsByKey(ctx context.Context, cmd DeleteLoginAttemptsByKeyCommand) error {
+	return f.ExpectedErr
 }
 
 func (f fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
@@ -2,11 +2,14 @@ package loginattemptimpl
 
 import (
 	"time"
+
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 )
 
 type CreateLoginAttemptCommand struct {
 	Username  string
 	IpAddress string
+	IpSubnet  string
 }
 
 type GetUserLoginAttemptCountQuery struct {
@@ -21,3 +24,27 @@ type DeleteOldLoginAttemptsCommand struct {
 type DeleteLoginAttemptsCommand struct {
 	Username string
 }
+
+type GetLoginAttemptStatsQuery struct {
+	Type  loginattempt.LockoutType
+	Key   string
+	Since time.Time
+}
+
+type ListLoginAttemptStatsQuery struct {
+	Type        loginattempt.LockoutType
+	Since       time.Time
+	MinAttempts int64
+}
+
+type DeleteLoginAttemptsByKeyCommand struct {
+	Type loginattempt.LockoutType
+	Key  string
+}
+
+// LoginAttemptStats are the failed login attempts of a username, IP address or subnet
+type LoginAttemptStats struct {
+	Key         string `xorm:"attempt_key"`
+	Attempts    int64  `xorm:"attempts"`
+	LastAttempt int64  `xorm:"last_attempt"`
+}
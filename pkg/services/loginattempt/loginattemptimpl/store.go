@@ -2,6 +2,7 @@ package loginattemptimpl
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
@@ -18,6 +19,9 @@ type store interface {
 	DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLoginAttemptsCommand) (int64, error)
 	DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error
 	GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error)
+	GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error)
+	ListLoginAttemptStats(ctx context.Context, query ListLoginAttemptStatsQuery) ([]LoginAttemptStats, error)
+	DeleteLoginAttemptsByKey(ctx context.Context, cmd DeleteLoginAttemptsByKeyCommand) error
 }
 
 func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) (result loginattempt.LoginAttempt, err error) {
@@ -25,6 +29,7 @@ func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAtte
 		loginAttempt := loginattempt.LoginAttempt{
 			Username:  cmd.Username,
 			IpAddress: cmd.IpAddress,
+			IpSubnet:  cmd.IpSubnet,
 			Created:   xs.now().Unix(),
 		}
 
@@ -82,3 +87,67 @@ func (xs *xormStore) GetUserLoginAttemptCount(ctx context.Context, query GetUser
 
 	return total, err
 }
+
+func (xs *xormStore) GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	column, err := lockoutColumn(query.Type)
+	if err != nil {
+		return LoginAttemptStats{}, err
+	}
+
+	stats := LoginAttemptStats{Key: query.Key}
+	err = xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var result []LoginAttemptStats
+		rawSQL := fmt.Sprintf("SELECT COUNT(*) AS attempts, COALESCE(MAX(created), 0) AS last_attempt FROM login_attempt WHERE %s = ? AND created >= ?", column)
+		if err := sess.SQL(rawSQL, query.Key, query.Since.Unix()).Find(&result); err != nil {
+			return err
+		}
+		if len(result) > 0 {
+			stats.Attempts = result[0].Attempts
+			stats.LastAttempt = result[0].LastAttempt
+		}
+		return nil
+	})
+	return stats, err
+}
+
+func (xs *xormStore) ListLoginAttemptStats(ctx context.Context, query ListLoginAttemptStatsQuery) ([]LoginAttemptStats, error) {
+	column, err := lockoutColumn(query.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LoginAttemptStats, 0)
+	err = xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := fmt.Sprintf(
+			"SELECT %[1]s AS attempt_key, COUNT(*) AS attempts, MAX(created) AS last_attempt FROM login_attempt "+
+				"WHERE created >= ? AND %[1]s IS NOT NULL AND %[1]s != '' GROUP BY %[1]s HAVING COUNT(*) >= ? ORDER BY %[1]s",
+			column,
+		)
+		return sess.SQL(rawSQL, query.Since.Unix(), query.MinAttempts).Find(&result)
+	})
+	return result, err
+}
+
+func (xs *xormStore) DeleteLoginAttemptsByKey(ctx context.Context, cmd DeleteLoginAttemptsByKeyCommand) error {
+	column, err := lockoutColumn(cmd.Type)
+	if err != nil {
+		return err
+	}
+
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec(fmt.Sprintf("DELETE FROM login_attempt WHERE %s = ?", column), cmd.Key)
+		return err
+	})
+}
+
+func lockoutColumn(t loginattempt.LockoutType) (string, error) {
+	switch t {
+	case loginattempt.LockoutTypeUsername:
+		return "username", nil
+	case loginattempt.LockoutTypeIP:
+		return "ip_address", nil
+	case loginattempt.LockoutTypeSubnet:
+		return "ip_subnet", nil
+	}
+	return "", fmt.Errorf("unknown lockout type %q", t)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 )
 
 func TestIntegrationLoginAttemptsQuery(t *testing.T) {
@@ -143,3 +144,56 @@ func TestIntegrationLoginAttemptsDelete(t *testing.T) {
 		require.Equal(t, test.DeletedRows, deletedRows, test.Name)
 	}
 }
+
+func TestIntegrationLoginAttemptStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	now := time.Date(2023, 4, 1, 8, 0, 0, 0, time.UTC)
+	s := &xormStore{
+		db:  db.InitTestDB(t),
+		now: func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	for i, attempt := range []CreateLoginAttemptCommand{
+		{Username: "admin", IpAddress: "192.168.1.10", IpSubnet: "192.168.1.0/24"},
+		{Username: "editor", IpAddress: "192.168.1.10", IpSubnet: "192.168.1.0/24"},
+		{Username: "viewer", IpAddress: "192.168.1.11", IpSubnet: "192.168.1.0/24"},
+		{Username: "admin", IpAddress: "2001:db8::1", IpSubnet: "2001:db8::/64"},
+	} {
+		now = now.Add(time.Duration(i) * time.Minute)
+		_, err := s.CreateLoginAttempt(ctx, attempt)
+		require.NoError(t, err)
+	}
+
+	t.Run("should count attempts of IP address", func(t *testing.T) {
+		stats, err := s.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{Type: loginattempt.LockoutTypeIP, Key: "192.168.1.10"})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stats.Attempts)
+		require.Equal(t, now.Add(-5*time.Minute).Unix(), stats.LastAttempt)
+	})
+
+	t.Run("should return no attempts for unknown keys", func(t *testing.T) {
+		stats, err := s.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{Type: loginattempt.LockoutTypeIP, Key: "127.0.0.1"})
+		require.NoError(t, err)
+		require.Zero(t, stats.Attempts)
+	})
+
+	t.Run("should list subnets with min attempts", func(t *testing.T) {
+		stats, err := s.ListLoginAttemptStats(ctx, ListLoginAttemptStatsQuery{Type: loginattempt.LockoutTypeSubnet, MinAttempts: 2})
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		require.Equal(t, "192.168.1.0/24", stats[0].Key)
+		require.Equal(t, int64(3), stats[0].Attempts)
+	})
+
+	t.Run("should delete attempts of subnet", func(t *testing.T) {
+		require.NoError(t, s.DeleteLoginAttemptsByKey(ctx, DeleteLoginAttemptsByKeyCommand{Type: loginattempt.LockoutTypeSubnet, Key: "192.168.1.0/24"}))
+
+		count, err := s.GetUserLoginAttemptCount(ctx, GetUserLoginAttemptCountQuery{Username: "admin"})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+}
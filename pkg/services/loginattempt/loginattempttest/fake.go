@@ -9,8 +9,10 @@ import (
 var _ loginattempt.Service = new(FakeLoginAttemptService)
 
 type FakeLoginAttemptService struct {
-	ExpectedValid bool
-	ExpectedErr   error
+	ExpectedValid     bool
+	ExpectedIPBlocked bool
+	ExpectedLockouts  []*loginattempt.Lockout
+	ExpectedErr       error
 }
 
 func (f FakeLoginAttemptService) Add(ctx context.Context, username, IPAddress string) error {
@@ -24,3 +26,15 @@ func (f FakeLoginAttemptService) Reset(ctx context.Context, username string) err
 func (f FakeLoginAttemptService) Validate(ctx context.Context, username string) (bool, error) {
 	return f.ExpectedValid, f.ExpectedErr
 }
+
+func (f FakeLoginAttemptService) ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error) {
+	return !f.ExpectedIPBlocked, f.ExpectedErr
+}
+
+func (f FakeLoginAttemptService) ListLockouts(ctx context.Context) ([]*loginattempt.Lockout, error) {
+	return f.ExpectedLockouts, f.ExpectedErr
+}
+
+func (f FakeLoginAttemptService) ClearLockout(ctx context.Context, cmd *loginattempt.ClearLockoutCommand) error {
+	return f.ExpectedErr
+}
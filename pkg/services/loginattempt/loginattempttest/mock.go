@@ -9,12 +9,17 @@ import (
 var _ loginattempt.Service = new(MockLoginAttemptService)
 
 type MockLoginAttemptService struct {
-	AddCalled      bool
-	ResetCalled    bool
-	ValidateCalled bool
-
-	ExpectedValid bool
-	ExpectedErr   error
+	AddCalled               bool
+	ResetCalled             bool
+	ValidateCalled          bool
+	ValidateIPAddressCalled bool
+	ListLockoutsCalled      bool
+	ClearLockoutCalled      bool
+
+	ExpectedValid     bool
+	ExpectedIPBlocked bool
+	ExpectedLockouts  []*loginattempt.Lockout
+	ExpectedErr       error
 }
 
 func (f *MockLoginAttemptService) Add(ctx context.Context, username, IPAddress string) error {
@@ -31,3 +36,18 @@ func (f *MockLoginAttemptService) Validate(ctx context.Context, username string)
 	f.ValidateCalled = true
 	return f.ExpectedValid, f.ExpectedErr
 }
+
+func (f *MockLoginAttemptService) ValidateIPAddress(ctx context.Context, IPAddress string) (bool, error) {
+	f.ValidateIPAddressCalled = true
+	return !f.ExpectedIPBlocked, f.ExpectedErr
+}
+
+func (f *MockLoginAttemptService) ListLockouts(ctx context.Context) ([]*loginattempt.Lockout, error) {
+	f.ListLockoutsCalled = true
+	return f.ExpectedLockouts, f.ExpectedErr
+}
+
+func (f *MockLoginAttemptService) ClearLockout(ctx context.Context, cmd *loginattempt.ClearLockoutCommand) error {
+	f.ClearLockoutCalled = true
+	return f.ExpectedErr
+}
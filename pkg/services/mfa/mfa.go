@@ -0,0 +1,56 @@
+package mfa
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// Service manages the second factors of users signing in with a Grafana password and
+// the challenges they have to pass before a session is created for them.
+type Service interface {
+	// IsEnabled returns true if users can enroll second factors
+	IsEnabled() bool
+	// GetStatus returns the second factors enrolled by the user and whether one is required
+	GetStatus(ctx context.Context, userID int64) (*Status, error)
+	// AllowsBasicAuth returns false if the user has to pass a challenge to sign in and
+	// basic auth isn't allowed for such users
+	AllowsBasicAuth(ctx context.Context, userID int64) (bool, error)
+
+	// Challenge starts a challenge for a user that signed in with a password.
+	// Returns nil if the user doesn't need a second factor to sign in.
+	Challenge(ctx context.Context, userID int64) (*Challenge, error)
+	// BeginChallengeWebAuthn returns the options for signing a pending challenge with a security key
+	BeginChallengeWebAuthn(ctx context.Context, token string) (*protocol.CredentialAssertion, error)
+	// EnrollChallengeTOTP starts TOTP enrollment for a pending challenge of a user that has to
+	// enroll a second factor before signing in
+	EnrollChallengeTOTP(ctx context.Context, token string) (*TOTPEnrollment, error)
+	// VerifyChallenge completes a pending challenge, the challenge is invalidated on success
+	// and after too many failed attempts
+	VerifyChallenge(ctx context.Context, cmd *VerifyChallengeCommand) (*VerifyChallengeResult, error)
+
+	// EnrollTOTP generates a new TOTP secret for the user, it is used once activated
+	EnrollTOTP(ctx context.Context, userID int64) (*TOTPEnrollment, error)
+	// ActivateTOTP activates an enrolled TOTP secret and returns new recovery codes
+	ActivateTOTP(ctx context.Context, userID int64, code string) ([]string, error)
+	// DisableTOTP removes the TOTP secret and recovery codes of the user
+	DisableTOTP(ctx context.Context, userID int64, code string) error
+	// RegenerateRecoveryCodes replaces the recovery codes of the user
+	RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) ([]string, error)
+
+	// BeginWebAuthnRegistration returns the options for registering a new security key
+	BeginWebAuthnRegistration(ctx context.Context, userID int64) (*protocol.CredentialCreation, error)
+	// FinishWebAuthnRegistration verifies and stores the security key created by the browser
+	FinishWebAuthnRegistration(ctx context.Context, userID int64, name string, response io.Reader) (*WebAuthnCredential, error)
+	// DeleteWebAuthnCredential removes a security key of the user once the user proved one of their second factors
+	DeleteWebAuthnCredential(ctx context.Context, userID, credentialID int64, cmd *VerifyFactorCommand) error
+
+	// Reset removes all second factors of the user
+	Reset(ctx context.Context, userID int64) error
+
+	// GetOrgPolicy returns the second factor policy of the org
+	GetOrgPolicy(ctx context.Context, orgID int64) (*OrgPolicy, error)
+	// UpdateOrgPolicy updates the second factor policy of the org
+	UpdateOrgPolicy(ctx context.Context, policy *OrgPolicy) error
+}
@@ -0,0 +1,87 @@
+package mfaimpl
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+const (
+	recoveryCodeCount = 10
+	// characters that can't be confused with each other when read from paper
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// generateRecoveryCodes returns new recovery codes and their JSON encoded hashes for storage
+func generateRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return nil, "", err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	encoded, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(encoded), nil
+}
+
+func randomRecoveryCode() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// recovery codes are random enough for a plain hash, they don't need a password hashing function
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeRecoveryCodes(encoded string) []string {
+	if encoded == "" {
+		return nil
+	}
+	var hashes []string
+	if err := json.Unmarshal([]byte(encoded), &hashes); err != nil {
+		return nil
+	}
+	return hashes
+}
+
+// consumeRecoveryCode returns the stored hashes without the code, or false if the code isn't one of them
+func consumeRecoveryCode(encoded, code string) (string, bool) {
+	hashes := decodeRecoveryCodes(encoded)
+	hash := hashRecoveryCode(code)
+	for i, h := range hashes {
+		if h == hash {
+			remaining := append(hashes[:i:i], hashes[i+1:]...)
+			b, err := json.Marshal(remaining)
+			if err != nil {
+				return "", false
+			}
+			return string(b), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,768 @@
+package mfaimpl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	challengeKeyPrefix    = "mfa-challenge:"
+	registrationKeyPrefix = "mfa-webauthn-registration:"
+	// basicAuthCacheTTL is how long AllowsBasicAuth results are cached, basic auth checks them on every request
+	basicAuthCacheTTL = time.Minute
+)
+
+var _ mfa.Service = new(Service)
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, cache remotecache.CacheStorage, secretsService secrets.Service,
+	userService user.Service, orgService org.Service, loginAttempts loginattempt.Service) *Service {
+	s := &Service{
+		cfg:            cfg,
+		store:          &xormStore{db: sqlStore, now: time.Now},
+		cache:          cache,
+		basicAuthCache: localcache.New(basicAuthCacheTTL, 2*basicAuthCacheTTL),
+		secrets:        secretsService,
+		userService:    userService,
+		orgService:     orgService,
+		loginAttempts:  loginAttempts,
+		log:            log.New("mfa"),
+		now:            time.Now,
+	}
+
+	if cfg.MFA.Enabled {
+		wa, err := newWebAuthn(cfg)
+		if err != nil {
+			s.log.Warn("Security keys are disabled, the relying party is misconfigured", "error", err)
+		}
+		s.webAuthn = wa
+	}
+
+	return s
+}
+
+type Service struct {
+	cfg   *setting.Cfg
+	store store
+	cache remotecache.CacheStorage
+	// basicAuthCache caches AllowsBasicAuth per user id, it is cleared when the second factors of a user change
+	basicAuthCache *localcache.CacheService
+	secrets        secrets.Service
+	userService    user.Service
+	orgService     org.Service
+	loginAttempts  loginattempt.Service
+	// webAuthn is nil if security keys can't be used
+	webAuthn *webauthn.WebAuthn
+	log      log.Logger
+	now      func() time.Time
+}
+
+// challengeState is stored in the remote cache while the user completes a challenge
+type challengeState struct {
+	UserID             int64                 `json:"userId"`
+	Expires            time.Time             `json:"expires"`
+	EnrollmentRequired bool                  `json:"enrollmentRequired"`
+	WebAuthnSession    *webauthn.SessionData `json:"webauthnSession,omitempty"`
+}
+
+func newWebAuthn(cfg *setting.Cfg) (*webauthn.WebAuthn, error) {
+	rpID := cfg.MFA.WebAuthnRPID
+	origins := cfg.MFA.WebAuthnRPOrigins
+	if rpID == "" || len(origins) == 0 {
+		appURL, err := url.Parse(cfg.AppURL)
+		if err != nil {
+			return nil, err
+		}
+		if rpID == "" {
+			rpID = appURL.Hostname()
+		}
+		if len(origins) == 0 {
+			origins = []string{appURL.Scheme + "://" + appURL.Host}
+		}
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPDisplayName: cfg.MFA.Issuer,
+		RPID:          rpID,
+		RPOrigins:     origins,
+	})
+}
+
+func (s *Service) IsEnabled() bool {
+	return s.cfg.MFA.Enabled
+}
+
+func (s *Service) GetStatus(ctx context.Context, userID int64) (*mfa.Status, error) {
+	status := &mfa.Status{Enabled: s.IsEnabled()}
+
+	settings, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings != nil {
+		status.TOTPEnabled = settings.TOTPEnabled
+		status.RecoveryCodesRemaining = len(decodeRecoveryCodes(settings.RecoveryCodes))
+	}
+
+	status.WebAuthnCredentials, err = s.store.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Required, err = s.required(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// required returns true if the server or one of the orgs of the user requires a second factor
+func (s *Service) required(ctx context.Context, userID int64) (bool, error) {
+	if s.cfg.MFA.Enforce {
+		return true, nil
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+
+	orgIDs := make([]int64, 0, len(orgs))
+	for _, o := range orgs {
+		orgIDs = append(orgIDs, o.OrgID)
+	}
+	return s.store.RequiredByAnyOrg(ctx, orgIDs)
+}
+
+func (s *Service) AllowsBasicAuth(ctx context.Context, userID int64) (bool, error) {
+	if !s.IsEnabled() || s.cfg.MFA.AllowBasicAuth {
+		return true, nil
+	}
+
+	key := strconv.FormatInt(userID, 10)
+	if allowed, ok := s.basicAuthCache.Get(key); ok {
+		return allowed.(bool), nil
+	}
+
+	status, err := s.GetStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	allowed := !status.Enrolled() && !status.Required
+	s.basicAuthCache.SetDefault(key, allowed)
+	return allowed, nil
+}
+
+func (s *Service) Challenge(ctx context.Context, userID int64) (*mfa.Challenge, error) {
+	if !s.IsEnabled() {
+		return nil, nil
+	}
+
+	status, err := s.GetStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Enrolled() && !status.Required {
+		return nil, nil
+	}
+
+	challenge := &mfa.Challenge{EnrollmentRequired: !status.Enrolled()}
+	if status.TOTPEnabled || challenge.EnrollmentRequired {
+		challenge.Methods = append(challenge.Methods, mfa.MethodTOTP)
+	}
+	if len(status.WebAuthnCredentials) > 0 && s.webAuthn != nil {
+		challenge.Methods = append(challenge.Methods, mfa.MethodWebAuthn)
+	}
+	if status.RecoveryCodesRemaining > 0 {
+		challenge.Methods = append(challenge.Methods, mfa.MethodRecoveryCode)
+	}
+
+	challenge.Token, err = util.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &challengeState{
+		UserID:             userID,
+		Expires:            s.now().Add(s.cfg.MFA.ChallengeTTL),
+		EnrollmentRequired: challenge.EnrollmentRequired,
+	}
+	if err := s.saveChallenge(ctx, challenge.Token, state); err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateChallengeAttempts(ctx, hashToken(challenge.Token), state.Expires); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+func (s *Service) BeginChallengeWebAuthn(ctx context.Context, token string) (*protocol.CredentialAssertion, error) {
+	if s.webAuthn == nil {
+		return nil, mfa.ErrNotEnabled.Errorf("security keys are not configured")
+	}
+
+	state, err := s.loadChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	waUser, err := s.webAuthnUser(ctx, state.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(waUser.credentials) == 0 {
+		return nil, mfa.ErrNotEnrolled.Errorf("user has no security keys")
+	}
+
+	assertion, session, err := s.webAuthn.BeginLogin(waUser)
+	if err != nil {
+		return nil, err
+	}
+
+	state.WebAuthnSession = session
+	if err := s.saveChallenge(ctx, token, state); err != nil {
+		return nil, err
+	}
+	return assertion, nil
+}
+
+func (s *Service) EnrollChallengeTOTP(ctx context.Context, token string) (*mfa.TOTPEnrollment, error) {
+	state, err := s.loadChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !state.EnrollmentRequired {
+		return nil, mfa.ErrAlreadyEnrolled.Errorf("user already has a second factor")
+	}
+	return s.EnrollTOTP(ctx, state.UserID)
+}
+
+func (s *Service) VerifyChallenge(ctx context.Context, cmd *mfa.VerifyChallengeCommand) (*mfa.VerifyChallengeResult, error) {
+	state, err := s.loadChallenge(ctx, cmd.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: state.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	// invalid second factors are recorded as failed logins, so signing in again doesn't give new attempts
+	ok, err := s.loginAttempts.Validate(ctx, usr.Login)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.deleteChallenge(ctx, cmd.Token)
+		return nil, mfa.ErrTooManyAttempts.Errorf("too many failed login attempts for user %d", state.UserID)
+	}
+	if cmd.IPAddress != "" {
+		ok, err := s.loginAttempts.ValidateIPAddress(ctx, cmd.IPAddress)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			s.deleteChallenge(ctx, cmd.Token)
+			return nil, mfa.ErrTooManyAttempts.Errorf("too many failed login attempts from ip address")
+		}
+	}
+
+	// the attempt is counted before it is verified, concurrent requests can't exceed the limit
+	attempts, err := s.store.AddChallengeAttempt(ctx, hashToken(cmd.Token))
+	if err != nil {
+		return nil, err
+	}
+	if attempts == 0 || attempts > s.cfg.MFA.MaxChallengeAttempts {
+		s.deleteChallenge(ctx, cmd.Token)
+		return nil, mfa.ErrChallengeNotFound.Errorf("challenge has no attempts left")
+	}
+
+	result := &mfa.VerifyChallengeResult{UserID: state.UserID}
+
+	var verifyErr error
+	switch {
+	case len(cmd.WebAuthn) > 0:
+		verifyErr = s.verifyWebAuthn(ctx, state, cmd.WebAuthn)
+		// the assertion challenge can only be signed once
+		state.WebAuthnSession = nil
+	case cmd.RecoveryCode != "":
+		verifyErr = s.useRecoveryCode(ctx, state.UserID, cmd.RecoveryCode)
+	case state.EnrollmentRequired:
+		result.RecoveryCodes, verifyErr = s.ActivateTOTP(ctx, state.UserID, cmd.Code)
+	default:
+		verifyErr = s.verifyTOTP(ctx, state.UserID, cmd.Code)
+	}
+
+	if verifyErr != nil {
+		if err := s.loginAttempts.Add(ctx, usr.Login, cmd.IPAddress); err != nil {
+			s.log.FromContext(ctx).Warn("Failed to record invalid second factor attempt", "userId", state.UserID, "error", err)
+		}
+
+		if attempts >= s.cfg.MFA.MaxChallengeAttempts {
+			s.log.FromContext(ctx).Warn("Too many invalid second factor attempts, invalidating challenge", "userId", state.UserID)
+			s.deleteChallenge(ctx, cmd.Token)
+		} else if len(cmd.WebAuthn) > 0 {
+			if err := s.saveChallenge(ctx, cmd.Token, state); err != nil {
+				return nil, err
+			}
+		}
+		return nil, verifyErr
+	}
+
+	s.deleteChallenge(ctx, cmd.Token)
+	return result, nil
+}
+
+func (s *Service) EnrollTOTP(ctx context.Context, userID int64) (*mfa.TOTPEnrollment, error) {
+	if !s.IsEnabled() {
+		return nil, mfa.ErrNotEnabled.Errorf("multi-factor authentication is not enabled")
+	}
+
+	settings, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings != nil && settings.TOTPEnabled {
+		return nil, mfa.ErrAlreadyEnrolled.Errorf("user already has TOTP enabled")
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return nil, err
+	}
+
+	if settings == nil {
+		settings = &userMFA{UserID: userID}
+	}
+	settings.TOTPSecret = base64.StdEncoding.EncodeToString(encrypted)
+	settings.TOTPLastStep = 0
+	if err := s.store.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return &mfa.TOTPEnrollment{
+		Secret: secret,
+		URL:    totpURL(s.cfg.MFA.Issuer, usr.Login, secret),
+	}, nil
+}
+
+func (s *Service) ActivateTOTP(ctx context.Context, userID int64, code string) ([]string, error) {
+	settings, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil || settings.TOTPSecret == "" {
+		return nil, mfa.ErrNotEnrolled.Errorf("user has not enrolled TOTP")
+	}
+	if settings.TOTPEnabled {
+		return nil, mfa.ErrAlreadyEnrolled.Errorf("user already has TOTP enabled")
+	}
+
+	secret, err := s.decryptSecret(ctx, settings.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := validateTOTP(secret, code, s.now(), settings.TOTPLastStep)
+	if !ok {
+		return nil, mfa.ErrInvalidCode.Errorf("invalid TOTP code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	settings.TOTPEnabled = true
+	settings.TOTPLastStep = step
+	settings.RecoveryCodes = hashes
+	if err := s.store.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+	s.clearBasicAuthCache(userID)
+
+	return codes, nil
+}
+
+func (s *Service) DisableTOTP(ctx context.Context, userID int64, code string) error {
+	if err := s.verifyTOTP(ctx, userID, code); err != nil {
+		return err
+	}
+
+	// recovery codes belong to the TOTP factor
+	if err := s.store.Save(ctx, &userMFA{UserID: userID}); err != nil {
+		return err
+	}
+	s.clearBasicAuthCache(userID)
+	return nil
+}
+
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) ([]string, error) {
+	if err := s.verifyTOTP(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	settings.RecoveryCodes = hashes
+	if err := s.store.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *Service) BeginWebAuthnRegistration(ctx context.Context, userID int64) (*protocol.CredentialCreation, error) {
+	if !s.IsEnabled() || s.webAuthn == nil {
+		return nil, mfa.ErrNotEnabled.Errorf("security keys are not configured")
+	}
+
+	waUser, err := s.webAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(waUser.credentials))
+	for _, c := range waUser.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	creation, session, err := s.webAuthn.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, registrationKey(userID), value, s.cfg.MFA.ChallengeTTL); err != nil {
+		return nil, err
+	}
+
+	return creation, nil
+}
+
+func (s *Service) FinishWebAuthnRegistration(ctx context.Context, userID int64, name string, response io.Reader) (*mfa.WebAuthnCredential, error) {
+	if !s.IsEnabled() || s.webAuthn == nil {
+		return nil, mfa.ErrNotEnabled.Errorf("security keys are not configured")
+	}
+
+	value, err := s.cache.Get(ctx, registrationKey(userID))
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, mfa.ErrInvalidCredential.Errorf("no pending security key registration")
+		}
+		return nil, err
+	}
+	_ = s.cache.Delete(ctx, registrationKey(userID))
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(value, &session); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(response)
+	if err != nil {
+		return nil, mfa.ErrInvalidCredential.Errorf("failed to parse security key response: %w", err)
+	}
+
+	waUser, err := s.webAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.webAuthn.CreateCredential(waUser, session, parsed)
+	if err != nil {
+		return nil, mfa.ErrInvalidCredential.Errorf("failed to verify security key: %w", err)
+	}
+
+	if name == "" {
+		name = "Security key"
+	}
+	credential := fromWebAuthnCredential(userID, name, created)
+	if err := s.store.CreateWebAuthnCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+	s.clearBasicAuthCache(userID)
+
+	return credential, nil
+}
+
+func (s *Service) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID int64, cmd *mfa.VerifyFactorCommand) error {
+	if err := s.verifyFactor(ctx, userID, cmd); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteWebAuthnCredential(ctx, userID, credentialID); err != nil {
+		return err
+	}
+	s.clearBasicAuthCache(userID)
+	return nil
+}
+
+func (s *Service) Reset(ctx context.Context, userID int64) error {
+	if err := s.store.Delete(ctx, userID); err != nil {
+		return err
+	}
+	_ = s.cache.Delete(ctx, registrationKey(userID))
+	s.clearBasicAuthCache(userID)
+	return nil
+}
+
+func (s *Service) GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error) {
+	return s.store.GetOrgPolicy(ctx, orgID)
+}
+
+func (s *Service) UpdateOrgPolicy(ctx context.Context, policy *mfa.OrgPolicy) error {
+	if err := s.store.SaveOrgPolicy(ctx, policy); err != nil {
+		return err
+	}
+	// the policy applies to every member of the org
+	s.basicAuthCache.Flush()
+	return nil
+}
+
+// verifyFactor checks the second factor a signed in user proved before changing their second factors
+func (s *Service) verifyFactor(ctx context.Context, userID int64, cmd *mfa.VerifyFactorCommand) error {
+	switch {
+	case len(cmd.WebAuthn) > 0:
+		state, err := s.loadChallenge(ctx, cmd.Token)
+		if err != nil {
+			return err
+		}
+		if state.UserID != userID {
+			return mfa.ErrChallengeNotFound.Errorf("challenge belongs to another user")
+		}
+		// the assertion challenge can only be signed once
+		defer s.deleteChallenge(ctx, cmd.Token)
+		return s.verifyWebAuthn(ctx, state, cmd.WebAuthn)
+	case cmd.RecoveryCode != "":
+		return s.useRecoveryCode(ctx, userID, cmd.RecoveryCode)
+	case cmd.Code != "":
+		return s.verifyTOTP(ctx, userID, cmd.Code)
+	default:
+		return mfa.ErrInvalidCode.Errorf("no second factor provided")
+	}
+}
+
+func (s *Service) verifyTOTP(ctx context.Context, userID int64, code string) error {
+	settings, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if settings == nil || !settings.TOTPEnabled {
+		return mfa.ErrNotEnrolled.Errorf("user has not enabled TOTP")
+	}
+
+	secret, err := s.decryptSecret(ctx, settings.TOTPSecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := validateTOTP(secret, code, s.now(), settings.TOTPLastStep)
+	if !ok {
+		return mfa.ErrInvalidCode.Errorf("invalid TOTP code")
+	}
+
+	// a concurrent request may have used the same code
+	used, err := s.store.UseTOTPStep(ctx, userID, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return mfa.ErrInvalidCode.Errorf("TOTP code was already used")
+	}
+	return nil
+}
+
+func (s *Service) useRecoveryCode(ctx context.Context, userID int64, code string) error {
+	settings, err := s.store.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return mfa.ErrInvalidCode.Errorf("user has no recovery codes")
+	}
+
+	remaining, ok := consumeRecoveryCode(settings.RecoveryCodes, code)
+	if !ok {
+		return mfa.ErrInvalidCode.Errorf("invalid recovery code")
+	}
+
+	replaced, err := s.store.ReplaceRecoveryCodes(ctx, userID, settings.RecoveryCodes, remaining)
+	if err != nil {
+		return err
+	}
+	if !replaced {
+		return mfa.ErrInvalidCode.Errorf("recovery code was already used")
+	}
+
+	s.log.FromContext(ctx).Info("Recovery code used", "userId", userID, "remaining", len(decodeRecoveryCodes(remaining)))
+	return nil
+}
+
+func (s *Service) verifyWebAuthn(ctx context.Context, state *challengeState, response json.RawMessage) error {
+	if s.webAuthn == nil {
+		return mfa.ErrNotEnabled.Errorf("security keys are not configured")
+	}
+	if state.WebAuthnSession == nil {
+		return mfa.ErrInvalidCredential.Errorf("no pending security key assertion")
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return mfa.ErrInvalidCredential.Errorf("failed to parse security key response: %w", err)
+	}
+
+	waUser, err := s.webAuthnUser(ctx, state.UserID)
+	if err != nil {
+		return err
+	}
+
+	validated, err := s.webAuthn.ValidateLogin(waUser, *state.WebAuthnSession, parsed)
+	if err != nil {
+		return mfa.ErrInvalidCredential.Errorf("failed to verify security key: %w", err)
+	}
+	if validated.Authenticator.CloneWarning {
+		return mfa.ErrInvalidCredential.Errorf("security key signature counter went backwards, the key may be cloned")
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(validated.ID)
+	for _, c := range waUser.credentials {
+		if c.CredentialID == id {
+			return s.store.UpdateWebAuthnCredentialUse(ctx, c.ID, int64(validated.Authenticator.SignCount), s.now())
+		}
+	}
+	return mfa.ErrCredentialNotFound.Errorf("security key not registered for user")
+}
+
+func (s *Service) webAuthnUser(ctx context.Context, userID int64) (*webAuthnUser, error) {
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.store.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnUser{id: usr.ID, login: usr.Login, name: usr.Name, credentials: credentials}, nil
+}
+
+func (s *Service) decryptSecret(ctx context.Context, stored string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", err
+	}
+	secret, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func (s *Service) loadChallenge(ctx context.Context, token string) (*challengeState, error) {
+	if token == "" {
+		return nil, mfa.ErrChallengeNotFound.Errorf("no challenge token")
+	}
+
+	value, err := s.cache.Get(ctx, challengeKeyPrefix+token)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, mfa.ErrChallengeNotFound.Errorf("challenge not found")
+		}
+		return nil, err
+	}
+
+	state := &challengeState{}
+	if err := json.Unmarshal(value, state); err != nil {
+		return nil, err
+	}
+	if !s.now().Before(state.Expires) {
+		s.deleteChallenge(ctx, token)
+		return nil, mfa.ErrChallengeNotFound.Errorf("challenge expired")
+	}
+
+	return state, nil
+}
+
+func (s *Service) saveChallenge(ctx context.Context, token string, state *challengeState) error {
+	ttl := state.Expires.Sub(s.now())
+	if ttl <= 0 {
+		return mfa.ErrChallengeNotFound.Errorf("challenge expired")
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, challengeKeyPrefix+token, value, ttl)
+}
+
+func (s *Service) deleteChallenge(ctx context.Context, token string) {
+	if err := s.cache.Delete(ctx, challengeKeyPrefix+token); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to delete second factor challenge", "error", err)
+	}
+	if err := s.store.DeleteChallengeAttempts(ctx, hashToken(token)); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to delete second factor challenge attempts", "error", err)
+	}
+}
+
+func (s *Service) clearBasicAuthCache(userID int64) {
+	s.basicAuthCache.Delete(strconv.FormatInt(userID, 10))
+}
+
+// hashToken returns the key challenge attempts are stored under, the token itself is only kept in the cache
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func registrationKey(userID int64) string {
+	return fmt.Sprintf("%s%d", registrationKeyPrefix, userID)
+}
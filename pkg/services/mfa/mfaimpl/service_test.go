@@ -0,0 +1,390 @@
+package mfaimpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattemptimpl"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationMFAService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	const userID = 1
+
+	t.Run("should not challenge users without second factor", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		assert.Nil(t, challenge)
+
+		allowed, err := s.AllowsBasicAuth(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("should challenge users with TOTP enabled", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		recoveryCodes := enableTOTP(t, s, userID)
+
+		status, err := s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, status.TOTPEnabled)
+		assert.Equal(t, len(recoveryCodes), status.RecoveryCodesRemaining)
+
+		allowed, err := s.AllowsBasicAuth(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+		assert.False(t, challenge.EnrollmentRequired)
+		assert.Equal(t, []string{mfa.MethodTOTP, mfa.MethodRecoveryCode}, challenge.Methods)
+
+		// the code used to activate TOTP can't be reused
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, 0)})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+
+		result, err := s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, totpPeriod)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(userID), result.UserID)
+
+		// challenges can only be completed once
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, totpPeriod)})
+		assert.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	t.Run("should accept recovery codes once", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		recoveryCodes := enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, RecoveryCode: recoveryCodes[0]})
+		require.NoError(t, err)
+
+		challenge, err = s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, RecoveryCode: recoveryCodes[0]})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+
+		status, err := s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, len(recoveryCodes)-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("should invalidate challenge after too many attempts", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.MaxChallengeAttempts = 2 })
+		enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000"})
+			assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+		}
+
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, totpPeriod)})
+		assert.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	t.Run("should count concurrent attempts", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.MaxChallengeAttempts = 3 })
+		enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		var invalid int32
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000"})
+				if errors.Is(err, mfa.ErrInvalidCode) {
+					atomic.AddInt32(&invalid, 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// only the attempts of the challenge were verified, the others were rejected
+		assert.LessOrEqual(t, atomic.LoadInt32(&invalid), int32(3))
+	})
+
+	t.Run("should record invalid attempts as failed logins", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: true}
+		s := setupTestServiceWithLoginAttempts(t, func(cfg *setting.Cfg) {}, loginAttempts)
+		enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000", IPAddress: "127.0.0.1"})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+		assert.True(t, loginAttempts.AddCalled)
+	})
+
+	t.Run("should reject challenges of locked out users", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{ExpectedValid: true}
+		s := setupTestServiceWithLoginAttempts(t, func(cfg *setting.Cfg) {}, loginAttempts)
+		enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+
+		loginAttempts.ExpectedValid = false
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, totpPeriod)})
+		assert.ErrorIs(t, err, mfa.ErrTooManyAttempts)
+
+		// the challenge is invalidated as well
+		loginAttempts.ExpectedValid = true
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, totpPeriod)})
+		assert.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	t.Run("should lock out ip addresses after too many invalid attempts", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.BruteForce = setting.BruteForceSettings{
+			IPMaxAttempts:      3,
+			IPv4SubnetPrefix:   24,
+			LockoutDuration:    5 * time.Minute,
+			MaxLockoutDuration: time.Hour,
+		}
+		s := setupTestServiceWithLoginAttempts(t, func(cfg *setting.Cfg) {}, loginattemptimpl.ProvideService(db.InitTestDB(t), cfg, nil))
+		enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000", IPAddress: "192.168.1.10"})
+			assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+		}
+
+		// the address is locked out even with a valid code
+		challenge, err = s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: currentCode(t, s, userID, totpPeriod), IPAddress: "192.168.1.10"})
+		assert.ErrorIs(t, err, mfa.ErrTooManyAttempts)
+	})
+
+	t.Run("should clear cached basic auth checks when second factors change", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+
+		allowed, err := s.AllowsBasicAuth(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+
+		enableTOTP(t, s, userID)
+		allowed, err = s.AllowsBasicAuth(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+
+		require.NoError(t, s.Reset(ctx, userID))
+		allowed, err = s.AllowsBasicAuth(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("should reject expired challenges", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		enableTOTP(t, s, userID)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+
+		now := time.Now()
+		s.now = func() time.Time { return now.Add(s.cfg.MFA.ChallengeTTL) }
+		_, err = s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: "000000"})
+		assert.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+	})
+
+	t.Run("should require enrollment when org requires a second factor", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		require.NoError(t, s.UpdateOrgPolicy(ctx, &mfa.OrgPolicy{OrgID: 1, Required: true}))
+
+		allowed, err := s.AllowsBasicAuth(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+		assert.True(t, challenge.EnrollmentRequired)
+
+		enrollment, err := s.EnrollChallengeTOTP(ctx, challenge.Token)
+		require.NoError(t, err)
+
+		step, ok := currentStep(s.now())
+		require.True(t, ok)
+		result, err := s.VerifyChallenge(ctx, &mfa.VerifyChallengeCommand{Token: challenge.Token, Code: codeAt(t, enrollment.Secret, step)})
+		require.NoError(t, err)
+		assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+
+		status, err := s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, status.TOTPEnabled)
+		assert.True(t, status.Required)
+	})
+
+	t.Run("should require enrollment when server enforces second factors", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.Enforce = true })
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+		assert.True(t, challenge.EnrollmentRequired)
+		assert.Equal(t, []string{mfa.MethodTOTP}, challenge.Methods)
+	})
+
+	t.Run("should remove second factors on reset", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		enableTOTP(t, s, userID)
+
+		require.NoError(t, s.Reset(ctx, userID))
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		assert.Nil(t, challenge)
+	})
+
+	t.Run("should disable TOTP with a valid code", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		enableTOTP(t, s, userID)
+
+		err := s.DisableTOTP(ctx, userID, "000000")
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+
+		require.NoError(t, s.DisableTOTP(ctx, userID, currentCode(t, s, userID, totpPeriod)))
+		status, err := s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, status.TOTPEnabled)
+		assert.Zero(t, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("should remove security keys with a second factor", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) {})
+		recoveryCodes := enableTOTP(t, s, userID)
+		for _, name := range []string{"first", "second"} {
+			require.NoError(t, s.store.CreateWebAuthnCredential(ctx, &mfa.WebAuthnCredential{UserID: userID, Name: name, CredentialID: name, Created: time.Now()}))
+		}
+		credentials, err := s.store.ListWebAuthnCredentials(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, credentials, 2)
+
+		err = s.DeleteWebAuthnCredential(ctx, userID, credentials[0].ID, &mfa.VerifyFactorCommand{})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+		err = s.DeleteWebAuthnCredential(ctx, userID, credentials[0].ID, &mfa.VerifyFactorCommand{Code: "000000"})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+		err = s.DeleteWebAuthnCredential(ctx, userID, credentials[0].ID, &mfa.VerifyFactorCommand{Token: "unknown", WebAuthn: []byte(`{}`)})
+		assert.ErrorIs(t, err, mfa.ErrChallengeNotFound)
+
+		require.NoError(t, s.DeleteWebAuthnCredential(ctx, userID, credentials[0].ID, &mfa.VerifyFactorCommand{Code: currentCode(t, s, userID, totpPeriod)}))
+		require.NoError(t, s.DeleteWebAuthnCredential(ctx, userID, credentials[1].ID, &mfa.VerifyFactorCommand{RecoveryCode: recoveryCodes[0]}))
+		credentials, err = s.store.ListWebAuthnCredentials(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, credentials)
+	})
+
+	t.Run("should not challenge users when disabled", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.Enforce = true })
+		s.cfg.MFA.Enabled = false
+
+		challenge, err := s.Challenge(ctx, userID)
+		require.NoError(t, err)
+		assert.Nil(t, challenge)
+	})
+}
+
+func setupTestService(t *testing.T, configure func(cfg *setting.Cfg)) *Service {
+	t.Helper()
+	return setupTestServiceWithLoginAttempts(t, configure, loginattempttest.FakeLoginAttemptService{ExpectedValid: true})
+}
+
+func setupTestServiceWithLoginAttempts(t *testing.T, configure func(cfg *setting.Cfg), loginAttempts loginattempt.Service) *Service {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.MFA = setting.MFASettings{
+		Enabled:              true,
+		Issuer:               "Grafana",
+		ChallengeTTL:         5 * time.Minute,
+		MaxChallengeAttempts: 5,
+	}
+	configure(cfg)
+
+	return ProvideService(
+		cfg,
+		db.InitTestDB(t),
+		remotecache.NewFakeStore(t),
+		fakes.NewFakeSecretsService(),
+		&usertest.FakeUserService{ExpectedUser: &user.User{ID: 1, Login: "admin"}},
+		&orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1}}},
+		loginAttempts,
+	)
+}
+
+// enableTOTP enrolls and activates TOTP and returns the recovery codes
+func enableTOTP(t *testing.T, s *Service, userID int64) []string {
+	t.Helper()
+
+	enrollment, err := s.EnrollTOTP(context.Background(), userID)
+	require.NoError(t, err)
+
+	step, ok := currentStep(s.now())
+	require.True(t, ok)
+	codes, err := s.ActivateTOTP(context.Background(), userID, codeAt(t, enrollment.Secret, step))
+	require.NoError(t, err)
+	return codes
+}
+
+// currentCode returns the code of the user's secret at now + offset
+func currentCode(t *testing.T, s *Service, userID int64, offset time.Duration) string {
+	t.Helper()
+
+	settings, err := s.store.Get(context.Background(), userID)
+	require.NoError(t, err)
+	secret, err := s.decryptSecret(context.Background(), settings.TOTPSecret)
+	require.NoError(t, err)
+
+	step, ok := currentStep(s.now().Add(offset))
+	require.True(t, ok)
+	return codeAt(t, secret, step)
+}
+
+func currentStep(now time.Time) (int64, bool) {
+	return now.Unix() / int64(totpPeriod.Seconds()), true
+}
+
+func codeAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	key, err := totpEncoding.DecodeString(secret)
+	require.NoError(t, err)
+	return totpCode(key, step)
+}
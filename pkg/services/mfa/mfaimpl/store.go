@@ -0,0 +1,286 @@
+package mfaimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+type userMFA struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	UserID       int64  `xorm:"user_id"`
+	TOTPSecret   string `xorm:"totp_secret"`
+	TOTPEnabled  bool   `xorm:"totp_enabled"`
+	TOTPLastStep int64  `xorm:"totp_last_step"`
+	// JSON list of hashed recovery codes
+	RecoveryCodes string `xorm:"recovery_codes"`
+	Created       time.Time
+	Updated       time.Time
+}
+
+func (userMFA) TableName() string {
+	return "user_mfa"
+}
+
+type orgMFAPolicy struct {
+	ID       int64 `xorm:"pk autoincr 'id'"`
+	OrgID    int64 `xorm:"org_id"`
+	Required bool  `xorm:"required"`
+	Updated  time.Time
+}
+
+func (orgMFAPolicy) TableName() string {
+	return "org_mfa_policy"
+}
+
+type challengeAttempt struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	Token    string `xorm:"token"`
+	Attempts int    `xorm:"attempts"`
+	Expires  int64  `xorm:"expires"`
+}
+
+func (challengeAttempt) TableName() string {
+	return "user_mfa_challenge_attempt"
+}
+
+type store interface {
+	// Get returns nil if the user has no second factor settings
+	Get(ctx context.Context, userID int64) (*userMFA, error)
+	Save(ctx context.Context, settings *userMFA) error
+	// UseTOTPStep stores the time step of an accepted code, returns false if a code of the same or a later step was already used
+	UseTOTPStep(ctx context.Context, userID, step int64) (bool, error)
+	// ReplaceRecoveryCodes replaces the recovery codes if they are unchanged, returns false otherwise
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, old, new string) (bool, error)
+	Delete(ctx context.Context, userID int64) error
+
+	ListWebAuthnCredentials(ctx context.Context, userID int64) ([]*mfa.WebAuthnCredential, error)
+	CreateWebAuthnCredential(ctx context.Context, credential *mfa.WebAuthnCredential) error
+	UpdateWebAuthnCredentialUse(ctx context.Context, id int64, signCount int64, lastUsed time.Time) error
+	DeleteWebAuthnCredential(ctx context.Context, userID, id int64) error
+
+	GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error)
+	SaveOrgPolicy(ctx context.Context, policy *mfa.OrgPolicy) error
+	// RequiredByAnyOrg returns true if one of the orgs requires a second factor
+	RequiredByAnyOrg(ctx context.Context, orgIDs []int64) (bool, error)
+
+	// CreateChallengeAttempts starts counting the attempts of a challenge and removes the counts of expired challenges
+	CreateChallengeAttempts(ctx context.Context, token string, expires time.Time) error
+	// AddChallengeAttempt increments the attempts of a challenge and returns the attempts so far,
+	// returns 0 if the challenge isn't counted
+	AddChallengeAttempt(ctx context.Context, token string) (int, error)
+	DeleteChallengeAttempts(ctx context.Context, token string) error
+}
+
+type xormStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *xormStore) Get(ctx context.Context, userID int64) (*userMFA, error) {
+	var result *userMFA
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		settings := &userMFA{}
+		has, err := sess.Where("user_id = ?", userID).Get(settings)
+		if err != nil {
+			return err
+		}
+		if has {
+			result = settings
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *xormStore) Save(ctx context.Context, settings *userMFA) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		settings.Updated = s.now()
+
+		existing := &userMFA{}
+		has, err := sess.Where("user_id = ?", settings.UserID).Get(existing)
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			settings.Created = settings.Updated
+			_, err := sess.Insert(settings)
+			return err
+		}
+
+		settings.ID = existing.ID
+		settings.Created = existing.Created
+		_, err = sess.ID(existing.ID).AllCols().Update(settings)
+		return err
+	})
+}
+
+func (s *xormStore) UseTOTPStep(ctx context.Context, userID, step int64) (bool, error) {
+	var updated bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_mfa SET totp_last_step = ? WHERE user_id = ? AND totp_last_step < ?", step, userID, step)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		updated = affected == 1
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) ReplaceRecoveryCodes(ctx context.Context, userID int64, old, new string) (bool, error) {
+	var updated bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_mfa SET recovery_codes = ?, updated = ? WHERE user_id = ? AND recovery_codes = ?", new, s.now(), userID, old)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		updated = affected == 1
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) Delete(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_mfa WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM user_mfa_webauthn_credential WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *xormStore) ListWebAuthnCredentials(ctx context.Context, userID int64) ([]*mfa.WebAuthnCredential, error) {
+	credentials := make([]*mfa.WebAuthnCredential, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("user_id = ?", userID).Asc("id").Find(&credentials)
+	})
+	return credentials, err
+}
+
+func (s *xormStore) CreateWebAuthnCredential(ctx context.Context, credential *mfa.WebAuthnCredential) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		credential.Created = s.now()
+		_, err := sess.Insert(credential)
+		return err
+	})
+}
+
+func (s *xormStore) UpdateWebAuthnCredentialUse(ctx context.Context, id int64, signCount int64, lastUsed time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE user_mfa_webauthn_credential SET sign_count = ?, last_used = ? WHERE id = ?", signCount, lastUsed, id)
+		return err
+	})
+}
+
+func (s *xormStore) DeleteWebAuthnCredential(ctx context.Context, userID, id int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_mfa_webauthn_credential WHERE id = ? AND user_id = ?", id, userID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return mfa.ErrCredentialNotFound.Errorf("no security key with id %d", id)
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error) {
+	policy := &mfa.OrgPolicy{OrgID: orgID}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		stored := &orgMFAPolicy{}
+		has, err := sess.Where("org_id = ?", orgID).Get(stored)
+		if err != nil {
+			return err
+		}
+		if has {
+			policy.Required = stored.Required
+		}
+		return nil
+	})
+	return policy, err
+}
+
+func (s *xormStore) SaveOrgPolicy(ctx context.Context, policy *mfa.OrgPolicy) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		stored := &orgMFAPolicy{}
+		has, err := sess.Where("org_id = ?", policy.OrgID).Get(stored)
+		if err != nil {
+			return err
+		}
+
+		stored.OrgID = policy.OrgID
+		stored.Required = policy.Required
+		stored.Updated = s.now()
+		if !has {
+			_, err := sess.Insert(stored)
+			return err
+		}
+		_, err = sess.ID(stored.ID).AllCols().Update(stored)
+		return err
+	})
+}
+
+func (s *xormStore) RequiredByAnyOrg(ctx context.Context, orgIDs []int64) (bool, error) {
+	if len(orgIDs) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		count, err = sess.In("org_id", orgIDs).And("required = ?", true).Count(&orgMFAPolicy{})
+		return err
+	})
+	return count > 0, err
+}
+
+func (s *xormStore) CreateChallengeAttempts(ctx context.Context, token string, expires time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_mfa_challenge_attempt WHERE expires < ?", s.now().Unix()); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&challengeAttempt{Token: token, Expires: expires.Unix()})
+		return err
+	})
+}
+
+func (s *xormStore) AddChallengeAttempt(ctx context.Context, token string) (int, error) {
+	var attempts int
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_mfa_challenge_attempt SET attempts = attempts + 1 WHERE token = ?", token)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil || affected == 0 {
+			return err
+		}
+
+		counted := &challengeAttempt{}
+		if _, err := sess.Where("token = ?", token).Get(counted); err != nil {
+			return err
+		}
+		attempts = counted.Attempts
+		return nil
+	})
+	return attempts, err
+}
+
+func (s *xormStore) DeleteChallengeAttempts(ctx context.Context, token string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_mfa_challenge_attempt WHERE token = ?", token)
+		return err
+	})
+}
@@ -0,0 +1,98 @@
+package mfaimpl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B test secret
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// 6 digit versions of the SHA1 codes from RFC 6238 appendix B
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range tests {
+		step := unix / int64(totpPeriod.Seconds())
+		assert.Equal(t, expected, totpCode([]byte("12345678901234567890"), step), "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := now.Unix() / int64(totpPeriod.Seconds())
+
+	t.Run("should accept code of the current time step", func(t *testing.T) {
+		step, ok := validateTOTP(rfcSecret, "081804", now, 0)
+		require.True(t, ok)
+		assert.Equal(t, current, step)
+	})
+
+	t.Run("should accept codes within the allowed clock drift", func(t *testing.T) {
+		_, ok := validateTOTP(rfcSecret, "081804", now.Add(totpPeriod), 0)
+		assert.True(t, ok)
+		_, ok = validateTOTP(rfcSecret, "081804", now.Add(-totpPeriod), 0)
+		assert.True(t, ok)
+	})
+
+	t.Run("should reject codes outside the allowed clock drift", func(t *testing.T) {
+		_, ok := validateTOTP(rfcSecret, "081804", now.Add(2*totpPeriod), 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject codes that were already used", func(t *testing.T) {
+		_, ok := validateTOTP(rfcSecret, "081804", now, current)
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject malformed codes", func(t *testing.T) {
+		_, ok := validateTOTP(rfcSecret, "81804", now, 0)
+		assert.False(t, ok)
+		_, ok = validateTOTP(rfcSecret, "", now, 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("should accept codes with spaces", func(t *testing.T) {
+		_, ok := validateTOTP(rfcSecret, "081 804", now, 0)
+		assert.True(t, ok)
+	})
+}
+
+func TestTOTPURL(t *testing.T) {
+	u, err := url.Parse(totpURL("Grafana", "admin", "SECRET"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Grafana:admin", u.Path)
+	assert.Equal(t, "SECRET", u.Query().Get("secret"))
+	assert.Equal(t, "Grafana", u.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, decodeRecoveryCodes(hashes), recoveryCodeCount)
+
+	remaining, ok := consumeRecoveryCode(hashes, codes[3])
+	require.True(t, ok)
+	assert.Len(t, decodeRecoveryCodes(remaining), recoveryCodeCount-1)
+
+	_, ok = consumeRecoveryCode(remaining, codes[3])
+	assert.False(t, ok, "recovery codes can only be used once")
+
+	_, ok = consumeRecoveryCode(remaining, " "+codes[4][:5]+codes[4][6:])
+	assert.True(t, ok, "recovery codes are normalized")
+}
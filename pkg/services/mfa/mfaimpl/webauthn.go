@@ -0,0 +1,106 @@
+package mfaimpl
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+var _ webauthn.User = new(webAuthnUser)
+
+// webAuthnUser adapts a user and their stored security keys to the webauthn library
+type webAuthnUser struct {
+	id          int64
+	login       string
+	name        string
+	credentials []*mfa.WebAuthnCredential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatInt(u.id, 10))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.login
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	if u.name != "" {
+		return u.name
+	}
+	return u.login
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		credential, err := toWebAuthnCredential(c)
+		if err != nil {
+			// corrupt credentials can't be used to sign in, skip them
+			continue
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials
+}
+
+func toWebAuthnCredential(c *mfa.WebAuthnCredential) (webauthn.Credential, error) {
+	id, err := base64.RawURLEncoding.DecodeString(c.CredentialID)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(c.PublicKey)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+	aaguid, err := hex.DecodeString(c.AAGUID)
+	if err != nil {
+		return webauthn.Credential{}, err
+	}
+
+	var transports []protocol.AuthenticatorTransport
+	for _, t := range strings.Split(c.Transports, ",") {
+		if t != "" {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+	}
+
+	return webauthn.Credential{
+		ID:              id,
+		PublicKey:       publicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    aaguid,
+			SignCount: uint32(c.SignCount),
+		},
+	}, nil
+}
+
+func fromWebAuthnCredential(userID int64, name string, c *webauthn.Credential) *mfa.WebAuthnCredential {
+	transports := make([]string, 0, len(c.Transport))
+	for _, t := range c.Transport {
+		transports = append(transports, string(t))
+	}
+
+	return &mfa.WebAuthnCredential{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    base64.RawURLEncoding.EncodeToString(c.ID),
+		PublicKey:       base64.StdEncoding.EncodeToString(c.PublicKey),
+		AttestationType: c.AttestationType,
+		AAGUID:          hex.EncodeToString(c.Authenticator.AAGUID),
+		SignCount:       int64(c.Authenticator.SignCount),
+		Transports:      strings.Join(transports, ","),
+	}
+}
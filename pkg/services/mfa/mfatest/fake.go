@@ -0,0 +1,99 @@
+package mfatest
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-webauthn/webauthn/protocol"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+var _ mfa.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedEnabled         bool
+	ExpectedStatus          *mfa.Status
+	ExpectedAllowsBasicAuth bool
+	ExpectedChallenge       *mfa.Challenge
+	ExpectedAssertion       *protocol.CredentialAssertion
+	ExpectedCreation        *protocol.CredentialCreation
+	ExpectedEnrollment      *mfa.TOTPEnrollment
+	ExpectedVerifyResult    *mfa.VerifyChallengeResult
+	ExpectedRecoveryCodes   []string
+	ExpectedCredential      *mfa.WebAuthnCredential
+	ExpectedOrgPolicy       *mfa.OrgPolicy
+	ExpectedErr             error
+
+	// VerifyCommand is the last command passed to VerifyChallenge
+	VerifyCommand *mfa.VerifyChallengeCommand
+}
+
+func (f *FakeService) IsEnabled() bool {
+	return f.ExpectedEnabled
+}
+
+func (f *FakeService) GetStatus(ctx context.Context, userID int64) (*mfa.Status, error) {
+	return f.ExpectedStatus, f.ExpectedErr
+}
+
+func (f *FakeService) AllowsBasicAuth(ctx context.Context, userID int64) (bool, error) {
+	return f.ExpectedAllowsBasicAuth, f.ExpectedErr
+}
+
+func (f *FakeService) Challenge(ctx context.Context, userID int64) (*mfa.Challenge, error) {
+	return f.ExpectedChallenge, f.ExpectedErr
+}
+
+func (f *FakeService) BeginChallengeWebAuthn(ctx context.Context, token string) (*protocol.CredentialAssertion, error) {
+	return f.ExpectedAssertion, f.ExpectedErr
+}
+
+func (f *FakeService) EnrollChallengeTOTP(ctx context.Context, token string) (*mfa.TOTPEnrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) VerifyChallenge(ctx context.Context, cmd *mfa.VerifyChallengeCommand) (*mfa.VerifyChallengeResult, error) {
+	f.VerifyCommand = cmd
+	return f.ExpectedVerifyResult, f.ExpectedErr
+}
+
+func (f *FakeService) EnrollTOTP(ctx context.Context, userID int64) (*mfa.TOTPEnrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) ActivateTOTP(ctx context.Context, userID int64, code string) ([]string, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedErr
+}
+
+func (f *FakeService) DisableTOTP(ctx context.Context, userID int64, code string) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) ([]string, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedErr
+}
+
+func (f *FakeService) BeginWebAuthnRegistration(ctx context.Context, userID int64) (*protocol.CredentialCreation, error) {
+	return f.ExpectedCreation, f.ExpectedErr
+}
+
+func (f *FakeService) FinishWebAuthnRegistration(ctx context.Context, userID int64, name string, response io.Reader) (*mfa.WebAuthnCredential, error) {
+	return f.ExpectedCredential, f.ExpectedErr
+}
+
+func (f *FakeService) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID int64, cmd *mfa.VerifyFactorCommand) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) Reset(ctx context.Context, userID int64) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error) {
+	return f.ExpectedOrgPolicy, f.ExpectedErr
+}
+
+func (f *FakeService) UpdateOrgPolicy(ctx context.Context, policy *mfa.OrgPolicy) error {
+	return f.ExpectedErr
+}
@@ -0,0 +1,123 @@
+package mfa
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	MethodTOTP         = "totp"
+	MethodWebAuthn     = "webauthn"
+	MethodRecoveryCode = "recovery_code"
+)
+
+var (
+	ErrNotEnabled         = errutil.NewBase(errutil.StatusBadRequest, "mfa.disabled", errutil.WithPublicMessage("Multi-factor authentication is not enabled"))
+	ErrNotEnrolled        = errutil.NewBase(errutil.StatusBadRequest, "mfa.not-enrolled", errutil.WithPublicMessage("No second factor enrolled"))
+	ErrAlreadyEnrolled    = errutil.NewBase(errutil.StatusBadRequest, "mfa.already-enrolled", errutil.WithPublicMessage("Second factor already enrolled"))
+	ErrInvalidCode        = errutil.NewBase(errutil.StatusUnauthorized, "mfa.invalid-code", errutil.WithPublicMessage("Invalid verification code"))
+	ErrChallengeNotFound  = errutil.NewBase(errutil.StatusUnauthorized, "mfa.challenge-not-found", errutil.WithPublicMessage("Verification expired, sign in again"))
+	ErrCredentialNotFound = errutil.NewBase(errutil.StatusNotFound, "mfa.credential-not-found", errutil.WithPublicMessage("Security key not found"))
+	ErrInvalidCredential  = errutil.NewBase(errutil.StatusBadRequest, "mfa.invalid-credential", errutil.WithPublicMessage("Invalid security key response"))
+	ErrTooManyAttempts    = errutil.NewBase(errutil.StatusTooManyRequests, "mfa.too-many-attempts", errutil.WithPublicMessage("Too many invalid verification attempts, try again later"))
+
+	ErrChallengeRequired = errutil.NewBase(errutil.StatusUnauthorized, "mfa.challenge-required")
+	challengeRequired    = ErrChallengeRequired.MustTemplate("second factor required for user", errutil.WithPublic("Second factor required"))
+)
+
+// NewChallengeRequiredError returns the error for a user that signed in with a password and has to complete
+// the challenge. The challenge token and methods are part of the public payload of the error.
+func NewChallengeRequiredError(challenge *Challenge) error {
+	return challengeRequired.Build(errutil.TemplateData{
+		Public: map[string]interface{}{
+			"mfaChallenge":          challenge.Token,
+			"mfaMethods":            challenge.Methods,
+			"mfaEnrollmentRequired": challenge.EnrollmentRequired,
+		},
+	})
+}
+
+// Status describes the second factors enrolled by a user
+type Status struct {
+	// Enabled is true if second factors can be enrolled
+	Enabled bool `json:"enabled"`
+	// Required is true if the server or one of the orgs of the user requires a second factor
+	Required               bool                  `json:"required"`
+	TOTPEnabled            bool                  `json:"totpEnabled"`
+	RecoveryCodesRemaining int                   `json:"recoveryCodesRemaining"`
+	WebAuthnCredentials    []*WebAuthnCredential `json:"webauthnCredentials"`
+}
+
+// Enrolled returns true if the user has at least one second factor
+func (s *Status) Enrolled() bool {
+	return s.TOTPEnabled || len(s.WebAuthnCredentials) > 0
+}
+
+// WebAuthnCredential is a security key registered by a user
+type WebAuthnCredential struct {
+	ID              int64      `json:"id" xorm:"pk autoincr 'id'"`
+	UserID          int64      `json:"-" xorm:"user_id"`
+	Name            string     `json:"name" xorm:"name"`
+	CredentialID    string     `json:"-" xorm:"credential_id"`
+	PublicKey       string     `json:"-" xorm:"public_key"`
+	AttestationType string     `json:"-" xorm:"attestation_type"`
+	AAGUID          string     `json:"-" xorm:"aaguid"`
+	SignCount       int64      `json:"-" xorm:"sign_count"`
+	Transports      string     `json:"-" xorm:"transports"`
+	Created         time.Time  `json:"created"`
+	LastUsed        *time.Time `json:"lastUsed" xorm:"last_used"`
+}
+
+func (WebAuthnCredential) TableName() string {
+	return "user_mfa_webauthn_credential"
+}
+
+// TOTPEnrollment is shown to the user to add the TOTP secret to an authenticator app
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is the otpauth:// URL of the secret, usually rendered as a QR code
+	URL string `json:"url"`
+}
+
+// Challenge is a pending second factor verification of a user that signed in with a password
+type Challenge struct {
+	Token   string   `json:"mfaChallenge"`
+	Methods []string `json:"mfaMethods"`
+	// EnrollmentRequired is true if the user has no second factor but one is required,
+	// the user has to enroll TOTP as part of the challenge
+	EnrollmentRequired bool `json:"mfaEnrollmentRequired"`
+}
+
+type VerifyChallengeCommand struct {
+	Token        string `json:"challenge" binding:"Required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+	// WebAuthn is the assertion returned by navigator.credentials.get
+	WebAuthn json.RawMessage `json:"webauthn"`
+	// IPAddress of the client, failed attempts are recorded with the login attempts of the user
+	IPAddress string `json:"-"`
+}
+
+// VerifyFactorCommand proves that a signed in user has one of their second factors before they change them.
+// A security key is verified with the assertion of a challenge of the user, started with BeginChallengeWebAuthn.
+type VerifyFactorCommand struct {
+	Code         string          `json:"code"`
+	RecoveryCode string          `json:"recoveryCode"`
+	Token        string          `json:"challenge"`
+	WebAuthn     json.RawMessage `json:"webauthn"`
+}
+
+type VerifyChallengeResult struct {
+	UserID int64
+	// RecoveryCodes are set when the user enrolled TOTP as part of the challenge
+	RecoveryCodes []string
+}
+
+// OrgPolicy is the second factor policy of an org
+type OrgPolicy struct {
+	OrgID int64 `json:"orgId"`
+	// Required requires members of the org signing in with a password to pass a second factor challenge
+	Required bool `json:"required"`
+}
@@ -233,6 +233,7 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM alert WHERE org_id = ?",
 			"DELETE FROM annotation WHERE org_id = ?",
 			"DELETE FROM kv_store WHERE org_id = ?",
+			"DELETE FROM org_mfa_policy WHERE org_id = ?",
 		}
 
 		for _, sql := range deletes {
@@ -794,6 +795,8 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_mfa WHERE user_id = ?",
+		"DELETE FROM user_mfa_webauthn_credential WHERE user_id = ?",
 	}
 	return deletes
 }
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	pCfg "github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
@@ -27,10 +29,21 @@ func ProvideConfig(settingProvider setting.Provider, grafanaCfg *setting.Cfg, fe
 		return nil, fmt.Errorf("new opentelemetry cfg: %w", err)
 	}
 
-	return pCfg.NewCfg(
+	repositories, err := extractPluginRepositories(settingProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginSettings := extractPluginSettings(settingProvider)
+	process, processes, err := extractPluginProcesses(settingProvider, pluginSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := pCfg.NewCfg(
 		settingProvider.KeyValue("", "app_mode").MustBool(grafanaCfg.Env == setting.Dev),
 		grafanaCfg.PluginsPath,
-		extractPluginSettings(settingProvider),
+		pluginSettings,
 		allowedUnsigned,
 		allowedAuth,
 		aws.KeyValue("assume_role_enabled").MustBool(grafanaCfg.AWSAssumeRoleEnabled),
@@ -41,7 +54,12 @@ func ProvideConfig(settingProvider setting.Provider, grafanaCfg *setting.Cfg, fe
 		grafanaCfg.PluginsCDNURLTemplate,
 		tracingCfg,
 		featuremgmt.ProvideToggles(features),
-	), nil
+	)
+	cfg.PluginRepositories = repositories
+	cfg.PluginProcess = process
+	cfg.PluginProcesses = processes
+	cfg.PluginCgroup = plugins.KeyValue("process_cgroup").MustString("")
+	return cfg, nil
 }
 
 func extractPluginSettings(settingProvider setting.Provider) setting.PluginSettings {
@@ -61,3 +79,114 @@ func extractPluginSettings(settingProvider setting.Provider) setting.PluginSetti
 
 	return ps
 }
+
+// extractPluginRepositories reads the repositories listed in the plugins section from their
+// plugin_repository.<name> sections.
+func extractPluginRepositories(settingProvider setting.Provider) ([]pCfg.PluginRepository, error) {
+	var repositories []pCfg.PluginRepository
+	for _, name := range strings.Split(settingProvider.KeyValue("plugins", "repositories").MustString(""), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		section := settingProvider.Section("plugin_repository." + name)
+		repository := pCfg.PluginRepository{
+			Name:          name,
+			Type:          section.KeyValue("type").MustString(pCfg.PluginRepositoryGrafanaCom),
+			URL:           section.KeyValue("url").MustString(""),
+			Token:         section.KeyValue("token").MustString(""),
+			SkipTLSVerify: section.KeyValue("tls_skip_verify_insecure").MustBool(false),
+		}
+		switch repository.Type {
+		case pCfg.PluginRepositoryGrafanaCom, pCfg.PluginRepositoryIndex, pCfg.PluginRepositoryOCI:
+		default:
+			return nil, fmt.Errorf("plugin repository %q has an invalid type %q", name, repository.Type)
+		}
+		if repository.URL == "" {
+			return nil, fmt.Errorf("plugin repository %q has no url", name)
+		}
+		repositories = append(repositories, repository)
+	}
+	return repositories, nil
+}
+
+// pluginProcessKeys are the settings of plugin.<id> sections configuring the process of the plugin rather than
+// the plugin itself.
+var pluginProcessKeys = []string{"memory_limit", "cpu_limit", "max_restarts"}
+
+// extractPluginProcesses reads the default resource limits and restart policy of backend plugin processes from
+// the plugins section, and the ones of plugins overriding them from their plugin.<id> sections. The latter are
+// removed from the plugin settings, so that they are not passed to the plugins as GF_PLUGIN_* environment variables.
+func extractPluginProcesses(settingProvider setting.Provider, pluginSettings setting.PluginSettings) (pCfg.PluginProcess, map[string]pCfg.PluginProcess, error) {
+	plugins := settingProvider.Section("plugins")
+	process := pCfg.PluginProcess{
+		RestartBackoff:    plugins.KeyValue("process_restart_backoff").MustDuration(time.Second),
+		MaxRestartBackoff: plugins.KeyValue("process_max_restart_backoff").MustDuration(time.Minute),
+	}
+	err := parsePluginProcess(&process, map[string]string{
+		"memory_limit": plugins.KeyValue("process_memory_limit").MustString(""),
+		"cpu_limit":    plugins.KeyValue("process_cpu_limit").MustString(""),
+		"max_restarts": plugins.KeyValue("process_max_restarts").MustString("10"),
+	})
+	if err != nil {
+		return pCfg.PluginProcess{}, nil, fmt.Errorf("invalid plugin process settings: %w", err)
+	}
+
+	processes := map[string]pCfg.PluginProcess{}
+	for pluginID, settings := range pluginSettings {
+		p := process
+		if err := parsePluginProcess(&p, settings); err != nil {
+			return pCfg.PluginProcess{}, nil, fmt.Errorf("invalid process settings of plugin %s: %w", pluginID, err)
+		}
+		for _, key := range pluginProcessKeys {
+			delete(settings, key)
+		}
+		if p != process {
+			processes[pluginID] = p
+		}
+	}
+	return process, processes, nil
+}
+
+// parsePluginProcess overrides the resource limits and maximum restarts of a process with the
+// memory_limit, cpu_limit and max_restarts settings.
+func parsePluginProcess(p *pCfg.PluginProcess, settings map[string]string) error {
+	var err error
+	if v := strings.TrimSpace(settings["memory_limit"]); v != "" {
+		if p.MemoryLimit, err = parseMemoryLimit(v); err != nil {
+			return fmt.Errorf("memory_limit: %w", err)
+		}
+	}
+	if v := strings.TrimSpace(settings["cpu_limit"]); v != "" {
+		if p.CPULimit, err = strconv.ParseFloat(v, 64); err != nil || p.CPULimit < 0 {
+			return fmt.Errorf("cpu_limit: %q is not a number of cores", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["max_restarts"]); v != "" {
+		if p.MaxRestarts, err = strconv.Atoi(v); err != nil || p.MaxRestarts < 0 {
+			return fmt.Errorf("max_restarts: %q is not a number of restarts", v)
+		}
+	}
+	return nil
+}
+
+// parseMemoryLimit parses a number of bytes with an optional K, M or G suffix, like 512M.
+func parseMemoryLimit(s string) (int64, error) {
+	n, unit := strings.ToUpper(s), int64(1)
+	switch n[len(n)-1] {
+	case 'K':
+		unit = 1 << 10
+	case 'M':
+		unit = 1 << 20
+	case 'G':
+		unit = 1 << 30
+	}
+	if unit > 1 {
+		n = n[:len(n)-1]
+	}
+	v, err := strconv.ParseInt(n, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%q is not a number of bytes", s)
+	}
+	return v * unit, nil
+}
@@ -2,9 +2,11 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"gopkg.in/ini.v1"
 
+	pCfg "github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/setting"
 
 	"github.com/stretchr/testify/require"
@@ -36,3 +38,88 @@ func TestPluginSettings(t *testing.T) {
 	require.Equal(t, ps["secret-plugin"]["secret_key"], "secret")
 	require.Equal(t, ps["secret-plugin"]["normal_key"], "not a secret")
 }
+
+func TestPluginRepositories(t *testing.T) {
+	raw, err := ini.Load([]byte(`
+		[plugins]
+		repositories = mirror, registry
+
+		[plugin_repository.mirror]
+		type = index
+		url = /mnt/plugins/index.json
+
+		[plugin_repository.registry]
+		type = oci
+		url = https://registry.example.com/plugins
+		token = secret
+		tls_skip_verify_insecure = true`))
+	require.NoError(t, err)
+
+	repositories, err := extractPluginRepositories(&setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}})
+	require.NoError(t, err)
+	require.Equal(t, []pCfg.PluginRepository{
+		{Name: "mirror", Type: pCfg.PluginRepositoryIndex, URL: "/mnt/plugins/index.json"},
+		{Name: "registry", Type: pCfg.PluginRepositoryOCI, URL: "https://registry.example.com/plugins", Token: "secret", SkipTLSVerify: true},
+	}, repositories)
+
+	t.Run("Should fail for a repository without url", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+		[plugins]
+		repositories = mirror`))
+		require.NoError(t, err)
+
+		_, err = extractPluginRepositories(&setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}})
+		require.Error(t, err)
+	})
+}
+
+func TestPluginProcesses(t *testing.T) {
+	raw, err := ini.Load([]byte(`
+		[plugins]
+		process_memory_limit = 1G
+		process_max_restarts = 5
+		process_restart_backoff = 2s
+
+		[plugin.test-datasource]
+		memory_limit = 512M
+		cpu_limit = 0.5
+
+		[plugin.other-datasource]
+		foo = bar`))
+	require.NoError(t, err)
+
+	settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}
+	pluginSettings := extractPluginSettings(settings)
+	process, processes, err := extractPluginProcesses(settings, pluginSettings)
+	require.NoError(t, err)
+	require.Equal(t, pCfg.PluginProcess{
+		MemoryLimit:       1 << 30,
+		MaxRestarts:       5,
+		RestartBackoff:    2 * time.Second,
+		MaxRestartBackoff: time.Minute,
+	}, process)
+	require.Equal(t, map[string]pCfg.PluginProcess{
+		"test-datasource": {
+			MemoryLimit:       512 << 20,
+			CPULimit:          0.5,
+			MaxRestarts:       5,
+			RestartBackoff:    2 * time.Second,
+			MaxRestartBackoff: time.Minute,
+		},
+	}, processes)
+	require.Equal(t, setting.PluginSettings{
+		"test-datasource":  {},
+		"other-datasource": {"foo": "bar"},
+	}, pluginSettings)
+
+	t.Run("Should fail for an invalid limit", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+		[plugin.test-datasource]
+		memory_limit = lots`))
+		require.NoError(t, err)
+
+		settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}
+		_, _, err = extractPluginProcesses(settings, extractPluginSettings(settings))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,445 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type ProvisionerConfig struct {
+	Path                        string
+	OrgService                  org.Service
+	UserService                 user.Service
+	TeamService                 team.Service
+	TeamPermissionsService      accesscontrol.TeamPermissionsService
+	ServiceAccountsService      serviceaccounts.Service
+	FolderPermissionsService    accesscontrol.FolderPermissionsService
+	DashboardPermissionsService accesscontrol.DashboardPermissionsService
+	ProvenanceService           provenance.Service
+}
+
+// Provision scans a directory for access provisioning files and provisions the teams, users,
+// service accounts and folder and dashboard permissions in those files. Provisioned resources
+// are marked with the file provenance so that they cannot be changed through the API.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.access")
+	reader := configReader{log: logger}
+	files, err := reader.readConfig(cfg.Path)
+	if err != nil {
+		return err
+	}
+
+	p := &AccessProvisioner{log: logger, cfg: cfg}
+	for _, file := range files {
+		if err := p.apply(ctx, file); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	return nil
+}
+
+// AccessProvisioner is responsible for provisioning access control resources based on
+// configuration read by the `configReader`
+type AccessProvisioner struct {
+	log log.Logger
+	cfg ProvisionerConfig
+}
+
+func (p *AccessProvisioner) apply(ctx context.Context, file *accessFile) error {
+	for _, sa := range file.DeleteServiceAccounts {
+		if err := p.deleteServiceAccount(ctx, sa); err != nil {
+			return fmt.Errorf("service account %q: %w", sa.Name, err)
+		}
+	}
+	for _, t := range file.DeleteTeams {
+		if err := p.deleteTeam(ctx, t); err != nil {
+			return fmt.Errorf("team %q: %w", t.Name, err)
+		}
+	}
+	for _, u := range file.DeleteUsers {
+		if err := p.deleteUser(ctx, u); err != nil {
+			return fmt.Errorf("user %q: %w", u.Login, err)
+		}
+	}
+
+	// users first, as they are referenced by the teams and the permissions
+	for _, u := range file.Users {
+		if err := p.provisionUser(ctx, u); err != nil {
+			return fmt.Errorf("user %q: %w", u.Login, err)
+		}
+	}
+	for _, t := range file.Teams {
+		if err := p.provisionTeam(ctx, t); err != nil {
+			return fmt.Errorf("team %q: %w", t.Name, err)
+		}
+	}
+	for _, sa := range file.ServiceAccounts {
+		if err := p.provisionServiceAccount(ctx, sa); err != nil {
+			return fmt.Errorf("service account %q: %w", sa.Name, err)
+		}
+	}
+	for _, rp := range file.FolderPermissions {
+		if err := p.provisionPermissions(ctx, rp, p.cfg.FolderPermissionsService, provenance.RecordTypeFolderPermissions); err != nil {
+			return fmt.Errorf("permissions of folder %q: %w", rp.UID, err)
+		}
+	}
+	for _, rp := range file.DashboardPermissions {
+		if err := p.provisionPermissions(ctx, rp, p.cfg.DashboardPermissionsService, provenance.RecordTypeDashboardPermissions); err != nil {
+			return fmt.Errorf("permissions of dashboard %q: %w", rp.UID, err)
+		}
+	}
+	return nil
+}
+
+func (p *AccessProvisioner) provisionUser(ctx context.Context, u *userFromConfig) error {
+	usr, err := p.cfg.UserService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: u.Login})
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		p.log.Info("Creating user from configuration", "login", u.Login)
+		usr, err = p.cfg.UserService.Create(ctx, &user.CreateUserCommand{
+			Login:    u.Login,
+			Email:    u.Email,
+			Name:     u.Name,
+			Password: u.Password,
+			IsAdmin:  u.IsGrafanaAdmin,
+			// the user is only added to the default organization when no organization is configured
+			SkipOrgSetup: len(u.Orgs) > 0,
+		})
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		p.log.Debug("Updating user from configuration", "login", u.Login)
+		// the password is only set when the user is created so that it can be changed by the user
+		if err := p.cfg.UserService.Update(ctx, &user.UpdateUserCommand{
+			UserID: usr.ID,
+			Login:  u.Login,
+			Email:  u.Email,
+			Name:   u.Name,
+		}); err != nil {
+			return err
+		}
+		if usr.IsAdmin != u.IsGrafanaAdmin {
+			if err := p.cfg.UserService.UpdatePermissions(ctx, usr.ID, u.IsGrafanaAdmin); err != nil {
+				return err
+			}
+		}
+	}
+
+	memberships, err := p.cfg.OrgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+	if err != nil {
+		return err
+	}
+	roles := make(map[int64]org.RoleType, len(memberships))
+	for _, m := range memberships {
+		roles[m.OrgID] = m.Role
+	}
+
+	for _, o := range u.Orgs {
+		orgID, err := p.resolveOrg(ctx, o.orgRef)
+		if err != nil {
+			return err
+		}
+
+		role := org.RoleType(o.Role)
+		current, isMember := roles[orgID]
+		switch {
+		case !isMember:
+			err = p.cfg.OrgService.AddOrgUser(ctx, &org.AddOrgUserCommand{LoginOrEmail: usr.Login, Role: role, OrgID: orgID, UserID: usr.ID})
+		case current != role:
+			err = p.cfg.OrgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{Role: role, OrgID: orgID, UserID: usr.ID})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.cfg.ProvenanceService.SetProvenance(ctx, accesscontrol.GlobalOrgID, provenance.RecordTypeUser, formatID(usr.ID), provenance.ProvenanceFile)
+}
+
+func (p *AccessProvisioner) deleteUser(ctx context.Context, u *deleteUserFromConfig) error {
+	usr, err := p.cfg.UserService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: u.Login})
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p.log.Info("Deleting user from configuration", "login", u.Login)
+	memberships, err := p.cfg.OrgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+	if err != nil {
+		return err
+	}
+	if len(memberships) == 0 {
+		if err := p.cfg.UserService.Delete(ctx, &user.DeleteUserCommand{UserID: usr.ID}); err != nil {
+			return err
+		}
+	}
+	// removing the user from its last organization deletes it
+	for _, m := range memberships {
+		if err := p.cfg.OrgService.RemoveOrgUser(ctx, &org.RemoveOrgUserCommand{UserID: usr.ID, OrgID: m.OrgID, ShouldDeleteOrphanedUser: true}); err != nil {
+			return err
+		}
+	}
+
+	return p.cfg.ProvenanceService.DeleteProvenance(ctx, accesscontrol.GlobalOrgID, provenance.RecordTypeUser, formatID(usr.ID))
+}
+
+func (p *AccessProvisioner) provisionTeam(ctx context.Context, t *teamFromConfig) error {
+	orgID, err := p.resolveOrg(ctx, t.orgRef)
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.getTeamByName(ctx, orgID, t.Name)
+	if err != nil {
+		return err
+	}
+
+	var teamID int64
+	if existing == nil {
+		p.log.Info("Creating team from configuration", "name", t.Name, "orgId", orgID)
+		created, err := p.cfg.TeamService.CreateTeam(t.Name, t.Email, orgID)
+		if err != nil {
+			return err
+		}
+		teamID = created.ID
+	} else {
+		teamID = existing.ID
+		if err := p.cfg.TeamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: teamID, Name: t.Name, Email: t.Email, OrgID: orgID}); err != nil {
+			return err
+		}
+	}
+
+	members, err := p.cfg.TeamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: orgID, TeamID: teamID, SignedInUser: backgroundUser(orgID)})
+	if err != nil {
+		return err
+	}
+	current := make(map[int64]string, len(members))
+	for _, m := range members {
+		current[m.UserID] = teamPermissionName(m.Permission)
+	}
+
+	// members are managed by the file, the ones that are not listed anymore are removed
+	desired := make(map[int64]string, len(t.Members))
+	for _, m := range t.Members {
+		usr, err := p.cfg.UserService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: m.Login})
+		if err != nil {
+			return fmt.Errorf("member %q: %w", m.Login, err)
+		}
+		desired[usr.ID] = m.Permission
+	}
+
+	resourceID := formatID(teamID)
+	for userID, permission := range desired {
+		if current[userID] == permission {
+			continue
+		}
+		if _, err := p.cfg.TeamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID}, resourceID, permission); err != nil {
+			return err
+		}
+	}
+	for userID := range current {
+		if _, ok := desired[userID]; ok {
+			continue
+		}
+		if _, err := p.cfg.TeamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID}, resourceID, ""); err != nil {
+			return err
+		}
+	}
+
+	return p.cfg.ProvenanceService.SetProvenance(ctx, orgID, provenance.RecordTypeTeam, resourceID, provenance.ProvenanceFile)
+}
+
+func (p *AccessProvisioner) deleteTeam(ctx context.Context, t *deleteTeamFromConfig) error {
+	orgID, err := p.resolveOrg(ctx, t.orgRef)
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.getTeamByName(ctx, orgID, t.Name)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	p.log.Info("Deleting team from configuration", "name", t.Name, "orgId", orgID)
+	if err := p.cfg.TeamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: orgID, ID: existing.ID}); err != nil {
+		return err
+	}
+	return p.cfg.ProvenanceService.DeleteProvenance(ctx, orgID, provenance.RecordTypeTeam, formatID(existing.ID))
+}
+
+func (p *AccessProvisioner) getTeamByName(ctx context.Context, orgID int64, name string) (*team.TeamDTO, error) {
+	result, err := p.cfg.TeamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: backgroundUser(orgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Teams) == 0 {
+		return nil, nil
+	}
+	return result.Teams[0], nil
+}
+
+func (p *AccessProvisioner) provisionServiceAccount(ctx context.Context, sa *serviceAccountFromConfig) error {
+	orgID, err := p.resolveOrg(ctx, sa.orgRef)
+	if err != nil {
+		return err
+	}
+
+	role := org.RoleType(sa.Role)
+	isDisabled := sa.IsDisabled
+	id, err := p.cfg.ServiceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, sa.Name)
+	switch {
+	case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+		p.log.Info("Creating service account from configuration", "name", sa.Name, "orgId", orgID)
+		created, err := p.cfg.ServiceAccountsService.CreateServiceAccount(ctx, orgID, &serviceaccounts.CreateServiceAccountForm{
+			Name:       sa.Name,
+			Role:       &role,
+			IsDisabled: &isDisabled,
+		})
+		if err != nil {
+			return err
+		}
+		id = created.Id
+	case err != nil:
+		return err
+	default:
+		if _, err := p.cfg.ServiceAccountsService.UpdateServiceAccount(ctx, orgID, id, &serviceaccounts.UpdateServiceAccountForm{
+			ServiceAccountID: id,
+			Role:             &role,
+			IsDisabled:       &isDisabled,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return p.cfg.ProvenanceService.SetProvenance(ctx, orgID, provenance.RecordTypeServiceAccount, formatID(id), provenance.ProvenanceFile)
+}
+
+func (p *AccessProvisioner) deleteServiceAccount(ctx context.Context, sa *deleteServiceAccountFromConfig) error {
+	orgID, err := p.resolveOrg(ctx, sa.orgRef)
+	if err != nil {
+		return err
+	}
+
+	id, err := p.cfg.ServiceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, sa.Name)
+	if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p.log.Info("Deleting service account from configuration", "name", sa.Name, "orgId", orgID)
+	if err := p.cfg.ServiceAccountsService.DeleteServiceAccount(ctx, orgID, id); err != nil {
+		return err
+	}
+	return p.cfg.ProvenanceService.DeleteProvenance(ctx, orgID, provenance.RecordTypeServiceAccount, formatID(id))
+}
+
+// provisionPermissions replaces the managed permissions of the folder or dashboard with the ones of the file.
+func (p *AccessProvisioner) provisionPermissions(ctx context.Context, rp *resourcePermissionsFromConfig,
+	service accesscontrol.PermissionsService, recordType string) error {
+	orgID, err := p.resolveOrg(ctx, rp.orgRef)
+	if err != nil {
+		return err
+	}
+
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(rp.Permissions))
+	desired := map[string]bool{}
+	for _, perm := range rp.Permissions {
+		cmd := accesscontrol.SetResourcePermissionCommand{BuiltinRole: perm.Role, Permission: perm.Permission}
+		switch {
+		case perm.UserLogin != "":
+			usr, err := p.cfg.UserService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: perm.UserLogin})
+			if err != nil {
+				return fmt.Errorf("user %q: %w", perm.UserLogin, err)
+			}
+			cmd.UserID = usr.ID
+		case perm.Team != "":
+			t, err := p.getTeamByName(ctx, orgID, perm.Team)
+			if err != nil {
+				return err
+			}
+			if t == nil {
+				return fmt.Errorf("team %q: %w", perm.Team, team.ErrTeamNotFound)
+			}
+			cmd.TeamID = t.ID
+		}
+		desired[permissionKey(cmd.UserID, cmd.TeamID, cmd.BuiltinRole)] = true
+		commands = append(commands, cmd)
+	}
+
+	current, err := service.GetPermissions(ctx, backgroundUser(orgID), rp.UID)
+	if err != nil {
+		return err
+	}
+	for _, c := range current {
+		// permissions granted through roles or inherited from the folder are not managed on the resource
+		if !c.IsManaged || c.IsInherited {
+			continue
+		}
+		if desired[permissionKey(c.UserId, c.TeamId, c.BuiltInRole)] {
+			continue
+		}
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{UserID: c.UserId, TeamID: c.TeamId, BuiltinRole: c.BuiltInRole})
+	}
+
+	if _, err := service.SetPermissions(ctx, orgID, rp.UID, commands...); err != nil {
+		return err
+	}
+	return p.cfg.ProvenanceService.SetProvenance(ctx, orgID, recordType, rp.UID, provenance.ProvenanceFile)
+}
+
+func (p *AccessProvisioner) resolveOrg(ctx context.Context, ref orgRef) (int64, error) {
+	if ref.OrgID > 0 {
+		return ref.OrgID, nil
+	}
+	o, err := p.cfg.OrgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: ref.OrgName})
+	if err != nil {
+		return 0, fmt.Errorf("organization %q: %w", ref.OrgName, err)
+	}
+	return o.ID, nil
+}
+
+// backgroundUser is the identity used to look up the teams, their members and the permissions of an organization.
+func backgroundUser(orgID int64) *user.SignedInUser {
+	return accesscontrol.BackgroundUser("access_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	})
+}
+
+func teamPermissionName(permission dashboards.PermissionType) string {
+	if permission == dashboards.PERMISSION_ADMIN {
+		return "Admin"
+	}
+	return "Member"
+}
+
+func permissionKey(userID, teamID int64, role string) string {
+	return fmt.Sprintf("%d/%d/%s", userID, teamID, role)
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,164 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestAccessProvisioner(t *testing.T) {
+	users := &fakeUserService{users: map[string]*user.User{
+		"jdoe":  {ID: 1, Login: "jdoe"},
+		"other": {ID: 2, Login: "other"},
+	}}
+	teams := &fakeTeamService{FakeService: teamtest.NewFakeService(), teams: map[string]*team.TeamDTO{
+		"Platform": {ID: 10, OrgID: 1, Name: "Platform"},
+	}}
+
+	t.Run("should reconcile the members of a team", func(t *testing.T) {
+		// other is an admin that is not listed anymore, jdoe is promoted
+		teams.ExpectedMembers = []*team.TeamMemberDTO{
+			{UserID: 1, Permission: 0},
+			{UserID: 2, Permission: dashboards.PERMISSION_ADMIN},
+		}
+		teamPermissions := &fakePermissionsService{FakePermissionsService: &actest.FakePermissionsService{}}
+		provenances := provenance.NewFakeService()
+		p := newTestProvisioner(users, teams, teamPermissions, nil, provenances)
+
+		err := p.apply(context.Background(), &accessFile{Teams: []*teamFromConfig{{
+			orgRef:  orgRef{OrgID: 1},
+			Name:    "Platform",
+			Members: []*teamMemberFromConfig{{Login: "jdoe", Permission: "Admin"}},
+		}}})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"1=Admin", "2="}, teamPermissions.setUsers)
+		p2, err := provenances.GetProvenance(context.Background(), 1, provenance.RecordTypeTeam, "10")
+		require.NoError(t, err)
+		assert.Equal(t, provenance.ProvenanceFile, p2)
+	})
+
+	t.Run("should replace the managed permissions of a folder", func(t *testing.T) {
+		folderPermissions := &fakePermissionsService{FakePermissionsService: &actest.FakePermissionsService{
+			ExpectedPermissions: []accesscontrol.ResourcePermission{
+				{UserId: 2, IsManaged: true},
+				{TeamId: 10, IsManaged: true},
+				{BuiltInRole: "Editor", IsManaged: true, IsInherited: true},
+				{BuiltInRole: "Admin", IsManaged: false},
+			},
+		}}
+		provenances := provenance.NewFakeService()
+		p := newTestProvisioner(users, teams, nil, folderPermissions, provenances)
+
+		err := p.apply(context.Background(), &accessFile{FolderPermissions: []*resourcePermissionsFromConfig{{
+			orgRef: orgRef{OrgID: 1},
+			UID:    "platform",
+			Permissions: []*permissionFromConfig{
+				{Team: "Platform", Permission: "Edit"},
+				{UserLogin: "jdoe", Permission: "Admin"},
+			},
+		}}})
+		require.NoError(t, err)
+
+		assert.Equal(t, []accesscontrol.SetResourcePermissionCommand{
+			{TeamID: 10, Permission: "Edit"},
+			{UserID: 1, Permission: "Admin"},
+			{UserID: 2},
+		}, folderPermissions.setCommands)
+		p2, err := provenances.GetProvenance(context.Background(), 1, provenance.RecordTypeFolderPermissions, "platform")
+		require.NoError(t, err)
+		assert.Equal(t, provenance.ProvenanceFile, p2)
+	})
+
+	t.Run("should look up a team by name with a limited search", func(t *testing.T) {
+		p := newTestProvisioner(users, teams, nil, nil, provenance.NewFakeService())
+		found, err := p.getTeamByName(context.Background(), 1, "Platform")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, int64(10), found.ID)
+
+		found, err = p.getTeamByName(context.Background(), 1, "Missing")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("should fail on an unknown team", func(t *testing.T) {
+		p := newTestProvisioner(users, teams, nil, &fakePermissionsService{FakePermissionsService: &actest.FakePermissionsService{}}, provenance.NewFakeService())
+		err := p.apply(context.Background(), &accessFile{FolderPermissions: []*resourcePermissionsFromConfig{{
+			orgRef:      orgRef{OrgID: 1},
+			UID:         "platform",
+			Permissions: []*permissionFromConfig{{Team: "Missing", Permission: "View"}},
+		}}})
+		require.ErrorIs(t, err, team.ErrTeamNotFound)
+	})
+}
+
+func newTestProvisioner(users user.Service, teams team.Service, teamPermissions, folderPermissions *fakePermissionsService, provenances provenance.Service) *AccessProvisioner {
+	return &AccessProvisioner{log: log.NewNopLogger(), cfg: ProvisionerConfig{
+		OrgService:               orgtest.NewOrgServiceFake(),
+		UserService:              users,
+		TeamService:              teams,
+		TeamPermissionsService:   teamPermissions,
+		FolderPermissionsService: folderPermissions,
+		ProvenanceService:        provenances,
+	}}
+}
+
+type fakeUserService struct {
+	usertest.FakeUserService
+	users map[string]*user.User
+}
+
+func (f *fakeUserService) GetByLogin(_ context.Context, query *user.GetUserByLoginQuery) (*user.User, error) {
+	if u, ok := f.users[query.LoginOrEmail]; ok {
+		return u, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+type fakeTeamService struct {
+	*teamtest.FakeService
+	teams map[string]*team.TeamDTO
+}
+
+func (f *fakeTeamService) SearchTeams(_ context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	// the store computes the offset of a limited search from its page, which starts at 1
+	if query.Limit > 0 && query.Page < 1 {
+		return team.SearchTeamQueryResult{}, fmt.Errorf("invalid page %d for a search limited to %d teams", query.Page, query.Limit)
+	}
+	result := team.SearchTeamQueryResult{}
+	if t, ok := f.teams[query.Name]; ok {
+		result.Teams = append(result.Teams, t)
+	}
+	return result, nil
+}
+
+type fakePermissionsService struct {
+	*actest.FakePermissionsService
+	setUsers    []string
+	setCommands []accesscontrol.SetResourcePermissionCommand
+}
+
+func (f *fakePermissionsService) SetUserPermission(_ context.Context, _ int64, u accesscontrol.User, _, permission string) (*accesscontrol.ResourcePermission, error) {
+	f.setUsers = append(f.setUsers, formatID(u.ID)+"="+permission)
+	return nil, nil
+}
+
+func (f *fakePermissionsService) SetPermissions(_ context.Context, _ int64, _ string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	f.setCommands = append(f.setCommands, commands...)
+	return nil, nil
+}
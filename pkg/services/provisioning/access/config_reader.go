@@ -0,0 +1,193 @@
+package access
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+var (
+	teamMemberPermissions = map[string]bool{"Member": true, "Admin": true}
+	resourcePermissions   = map[string]bool{"View": true, "Edit": true, "Admin": true}
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*accessFile, error) {
+	var files []*accessFile
+	cr.log.Debug("Looking for access provisioning files", "path", path)
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read access provisioning files from directory", "path", path, "error", err)
+		return files, nil
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".yaml") && !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+
+		cr.log.Debug("Parsing access provisioning file", "path", path, "file.Name", entry.Name())
+		file, err := cr.parseConfig(path, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failure to parse file %s: %w", entry.Name(), err)
+		}
+
+		if err := validate(file); err != nil {
+			return nil, fmt.Errorf("invalid file %s: %w", entry.Name(), err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (cr *configReader) parseConfig(path string, name string) (*accessFile, error) {
+	filename, err := filepath.Abs(filepath.Join(path, name))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *accessFileV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToModel(name), nil
+}
+
+// validate checks the required fields and the permission names, and defaults missing organizations to the main one.
+func validate(file *accessFile) error {
+	var errStrings []string
+	fail := func(format string, args ...interface{}) {
+		errStrings = append(errStrings, fmt.Sprintf(format, args...))
+	}
+
+	for i, t := range file.Teams {
+		t.orgRef.setDefault()
+		if t.Name == "" {
+			fail("team item %d doesn't contain required field name", i+1)
+		}
+		for j, m := range t.Members {
+			if m.Login == "" {
+				fail("member item %d of team %q doesn't contain required field login", j+1, t.Name)
+			}
+			if m.Permission == "" {
+				m.Permission = "Member"
+			}
+			if !teamMemberPermissions[m.Permission] {
+				fail("member %q of team %q has invalid permission %q, must be Member or Admin", m.Login, t.Name, m.Permission)
+			}
+		}
+	}
+
+	for i, t := range file.DeleteTeams {
+		t.orgRef.setDefault()
+		if t.Name == "" {
+			fail("deleteTeams item %d doesn't contain required field name", i+1)
+		}
+	}
+
+	for i, u := range file.Users {
+		if u.Login == "" {
+			fail("user item %d doesn't contain required field login", i+1)
+		}
+		for _, o := range u.Orgs {
+			o.orgRef.setDefault()
+			if o.Role == "" {
+				o.Role = string(org.RoleViewer)
+			}
+			if !org.RoleType(o.Role).IsValid() {
+				fail("user %q has invalid role %q", u.Login, o.Role)
+			}
+		}
+	}
+
+	for i, u := range file.DeleteUsers {
+		if u.Login == "" {
+			fail("deleteUsers item %d doesn't contain required field login", i+1)
+		}
+	}
+
+	for i, sa := range file.ServiceAccounts {
+		sa.orgRef.setDefault()
+		if sa.Name == "" {
+			fail("service account item %d doesn't contain required field name", i+1)
+		}
+		if sa.Role == "" {
+			sa.Role = string(org.RoleViewer)
+		}
+		if !org.RoleType(sa.Role).IsValid() {
+			fail("service account %q has invalid role %q", sa.Name, sa.Role)
+		}
+	}
+
+	for i, sa := range file.DeleteServiceAccounts {
+		sa.orgRef.setDefault()
+		if sa.Name == "" {
+			fail("deleteServiceAccounts item %d doesn't contain required field name", i+1)
+		}
+	}
+
+	for _, resources := range []struct {
+		kind  string
+		items []*resourcePermissionsFromConfig
+	}{
+		{"folderPermissions", file.FolderPermissions},
+		{"dashboardPermissions", file.DashboardPermissions},
+	} {
+		kind := resources.kind
+		for i, rp := range resources.items {
+			rp.orgRef.setDefault()
+			if rp.UID == "" {
+				fail("%s item %d doesn't contain required field uid", kind, i+1)
+			}
+			for _, p := range rp.Permissions {
+				set := 0
+				for _, v := range []string{p.UserLogin, p.Team, p.Role} {
+					if v != "" {
+						set++
+					}
+				}
+				if set != 1 {
+					fail("permission of %s %q must have exactly one of user, team or role", kind, rp.UID)
+				}
+				if p.Role != "" && !org.RoleType(p.Role).IsValid() {
+					fail("permission of %s %q has invalid role %q", kind, rp.UID, p.Role)
+				}
+				if !resourcePermissions[p.Permission] {
+					fail("permission of %s %q has invalid permission %q, must be View, Edit or Admin", kind, rp.UID, p.Permission)
+				}
+			}
+		}
+	}
+
+	if len(errStrings) != 0 {
+		return fmt.Errorf(strings.Join(errStrings, "\n"))
+	}
+	return nil
+}
+
+// setDefault references the main organization when neither the ID nor the name is set.
+func (ref *orgRef) setDefault() {
+	if ref.OrgID < 1 && ref.OrgName == "" {
+		ref.OrgID = 1
+	}
+}
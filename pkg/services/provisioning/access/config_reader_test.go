@@ -0,0 +1,78 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	testFileCorrectProperties  = "./testdata/correct-properties"
+	testFileInvalidPermissions = "./testdata/invalid-permissions"
+	testFileBrokenYAML         = "./testdata/broken-yaml"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := configReader{log: log.NewNopLogger()}
+
+	t.Run("a file with correct properties should not error", func(t *testing.T) {
+		t.Setenv("TEST_ACCESS_PASSWORD", "secret")
+		files, err := reader.readConfig(testFileCorrectProperties)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		file := files[0]
+
+		require.Len(t, file.Users, 1)
+		assert.Equal(t, "secret", file.Users[0].Password)
+		require.Len(t, file.Users[0].Orgs, 2)
+		assert.Equal(t, orgRef{OrgID: 2}, file.Users[0].Orgs[0].orgRef)
+		assert.Equal(t, "Editor", file.Users[0].Orgs[0].Role)
+		assert.Equal(t, orgRef{OrgName: "Other"}, file.Users[0].Orgs[1].orgRef)
+		assert.Equal(t, "Viewer", file.Users[0].Orgs[1].Role)
+		require.Len(t, file.DeleteUsers, 1)
+
+		require.Len(t, file.Teams, 1)
+		assert.Equal(t, int64(1), file.Teams[0].OrgID, "the organization should default to 1")
+		require.Len(t, file.Teams[0].Members, 2)
+		assert.Equal(t, "Admin", file.Teams[0].Members[0].Permission)
+		assert.Equal(t, "Member", file.Teams[0].Members[1].Permission)
+		require.Len(t, file.DeleteTeams, 1)
+		assert.Equal(t, int64(2), file.DeleteTeams[0].OrgID)
+
+		require.Len(t, file.ServiceAccounts, 1)
+		assert.Equal(t, "Editor", file.ServiceAccounts[0].Role)
+		require.Len(t, file.DeleteServiceAccounts, 1)
+
+		require.Len(t, file.FolderPermissions, 1)
+		assert.Equal(t, "platform", file.FolderPermissions[0].UID)
+		assert.Equal(t, []*permissionFromConfig{
+			{Team: "Platform", Permission: "Edit"},
+			{Role: "Viewer", Permission: "View"},
+		}, file.FolderPermissions[0].Permissions)
+		require.Len(t, file.DashboardPermissions, 1)
+		assert.Equal(t, int64(2), file.DashboardPermissions[0].OrgID)
+	})
+
+	t.Run("a file with invalid properties should error", func(t *testing.T) {
+		_, err := reader.readConfig(testFileInvalidPermissions)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `member "jdoe" of team "Platform" has invalid permission "Owner"`)
+		assert.Contains(t, err.Error(), "service account item 1 doesn't contain required field name")
+		assert.Contains(t, err.Error(), `permission of folderPermissions "platform" must have exactly one of user, team or role`)
+		assert.Contains(t, err.Error(), `permission of folderPermissions "platform" has invalid permission "Read"`)
+	})
+
+	t.Run("a broken YAML file should error", func(t *testing.T) {
+		_, err := reader.readConfig(testFileBrokenYAML)
+		require.Error(t, err)
+	})
+
+	t.Run("a missing folder should not error", func(t *testing.T) {
+		files, err := reader.readConfig("./testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+}
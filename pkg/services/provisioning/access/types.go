@@ -0,0 +1,242 @@
+package access
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// accessFile is a normalized data object for access provisioning files. Any config version should be
+// mappable to this type.
+type accessFile struct {
+	Filename              string
+	Teams                 []*teamFromConfig
+	DeleteTeams           []*deleteTeamFromConfig
+	Users                 []*userFromConfig
+	DeleteUsers           []*deleteUserFromConfig
+	ServiceAccounts       []*serviceAccountFromConfig
+	DeleteServiceAccounts []*deleteServiceAccountFromConfig
+	FolderPermissions     []*resourcePermissionsFromConfig
+	DashboardPermissions  []*resourcePermissionsFromConfig
+}
+
+// orgRef references an organization by ID or by name.
+type orgRef struct {
+	OrgID   int64
+	OrgName string
+}
+
+type teamFromConfig struct {
+	orgRef
+	Name    string
+	Email   string
+	Members []*teamMemberFromConfig
+}
+
+type teamMemberFromConfig struct {
+	Login      string
+	Permission string
+}
+
+type deleteTeamFromConfig struct {
+	orgRef
+	Name string
+}
+
+type userFromConfig struct {
+	Login          string
+	Email          string
+	Name           string
+	Password       string
+	IsGrafanaAdmin bool
+	Orgs           []*userOrgFromConfig
+}
+
+type userOrgFromConfig struct {
+	orgRef
+	Role string
+}
+
+type deleteUserFromConfig struct {
+	Login string
+}
+
+type serviceAccountFromConfig struct {
+	orgRef
+	Name       string
+	Role       string
+	IsDisabled bool
+}
+
+type deleteServiceAccountFromConfig struct {
+	orgRef
+	Name string
+}
+
+type resourcePermissionsFromConfig struct {
+	orgRef
+	UID         string
+	Permissions []*permissionFromConfig
+}
+
+// permissionFromConfig grants a permission to exactly one of a user, a team or a basic role.
+type permissionFromConfig struct {
+	UserLogin  string
+	Team       string
+	Role       string
+	Permission string
+}
+
+type accessFileV1 struct {
+	configVersion
+	Teams                 []*teamV1                 `json:"teams" yaml:"teams"`
+	DeleteTeams           []*deleteTeamV1           `json:"deleteTeams" yaml:"deleteTeams"`
+	Users                 []*userV1                 `json:"users" yaml:"users"`
+	DeleteUsers           []*deleteUserV1           `json:"deleteUsers" yaml:"deleteUsers"`
+	ServiceAccounts       []*serviceAccountV1       `json:"serviceAccounts" yaml:"serviceAccounts"`
+	DeleteServiceAccounts []*deleteServiceAccountV1 `json:"deleteServiceAccounts" yaml:"deleteServiceAccounts"`
+	FolderPermissions     []*resourcePermissionsV1  `json:"folderPermissions" yaml:"folderPermissions"`
+	DashboardPermissions  []*resourcePermissionsV1  `json:"dashboardPermissions" yaml:"dashboardPermissions"`
+}
+
+type orgRefV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+type teamV1 struct {
+	orgRefV1 `yaml:",inline"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+	Email    values.StringValue `json:"email" yaml:"email"`
+	Members  []*teamMemberV1    `json:"members" yaml:"members"`
+}
+
+type teamMemberV1 struct {
+	Login      values.StringValue `json:"login" yaml:"login"`
+	Permission values.StringValue `json:"permission" yaml:"permission"`
+}
+
+type deleteTeamV1 struct {
+	orgRefV1 `yaml:",inline"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+}
+
+type userV1 struct {
+	Login          values.StringValue `json:"login" yaml:"login"`
+	Email          values.StringValue `json:"email" yaml:"email"`
+	Name           values.StringValue `json:"name" yaml:"name"`
+	Password       values.StringValue `json:"password" yaml:"password"`
+	IsGrafanaAdmin values.BoolValue   `json:"isGrafanaAdmin" yaml:"isGrafanaAdmin"`
+	Orgs           []*userOrgV1       `json:"orgs" yaml:"orgs"`
+}
+
+type userOrgV1 struct {
+	orgRefV1 `yaml:",inline"`
+	Role     values.StringValue `json:"role" yaml:"role"`
+}
+
+type deleteUserV1 struct {
+	Login values.StringValue `json:"login" yaml:"login"`
+}
+
+type serviceAccountV1 struct {
+	orgRefV1   `yaml:",inline"`
+	Name       values.StringValue `json:"name" yaml:"name"`
+	Role       values.StringValue `json:"role" yaml:"role"`
+	IsDisabled values.BoolValue   `json:"isDisabled" yaml:"isDisabled"`
+}
+
+type deleteServiceAccountV1 struct {
+	orgRefV1 `yaml:",inline"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+}
+
+type resourcePermissionsV1 struct {
+	orgRefV1    `yaml:",inline"`
+	UID         values.StringValue `json:"uid" yaml:"uid"`
+	Permissions []*permissionV1    `json:"permissions" yaml:"permissions"`
+}
+
+type permissionV1 struct {
+	User       values.StringValue `json:"user" yaml:"user"`
+	Team       values.StringValue `json:"team" yaml:"team"`
+	Role       values.StringValue `json:"role" yaml:"role"`
+	Permission values.StringValue `json:"permission" yaml:"permission"`
+}
+
+func (ref orgRefV1) mapToModel() orgRef {
+	return orgRef{OrgID: ref.OrgID.Value(), OrgName: ref.OrgName.Value()}
+}
+
+// mapToModel maps config syntax to a normalized accessFile object. Every version of the config syntax
+// should have this function.
+func (cfg *accessFileV1) mapToModel(filename string) *accessFile {
+	r := &accessFile{Filename: filename}
+	if cfg == nil {
+		return r
+	}
+
+	for _, t := range cfg.Teams {
+		team := &teamFromConfig{orgRef: t.mapToModel(), Name: t.Name.Value(), Email: t.Email.Value()}
+		for _, m := range t.Members {
+			team.Members = append(team.Members, &teamMemberFromConfig{Login: m.Login.Value(), Permission: m.Permission.Value()})
+		}
+		r.Teams = append(r.Teams, team)
+	}
+	for _, t := range cfg.DeleteTeams {
+		r.DeleteTeams = append(r.DeleteTeams, &deleteTeamFromConfig{orgRef: t.mapToModel(), Name: t.Name.Value()})
+	}
+
+	for _, u := range cfg.Users {
+		usr := &userFromConfig{
+			Login:          u.Login.Value(),
+			Email:          u.Email.Value(),
+			Name:           u.Name.Value(),
+			Password:       u.Password.Value(),
+			IsGrafanaAdmin: u.IsGrafanaAdmin.Value(),
+		}
+		for _, o := range u.Orgs {
+			usr.Orgs = append(usr.Orgs, &userOrgFromConfig{orgRef: o.mapToModel(), Role: o.Role.Value()})
+		}
+		r.Users = append(r.Users, usr)
+	}
+	for _, u := range cfg.DeleteUsers {
+		r.DeleteUsers = append(r.DeleteUsers, &deleteUserFromConfig{Login: u.Login.Value()})
+	}
+
+	for _, sa := range cfg.ServiceAccounts {
+		r.ServiceAccounts = append(r.ServiceAccounts, &serviceAccountFromConfig{
+			orgRef:     sa.mapToModel(),
+			Name:       sa.Name.Value(),
+			Role:       sa.Role.Value(),
+			IsDisabled: sa.IsDisabled.Value(),
+		})
+	}
+	for _, sa := range cfg.DeleteServiceAccounts {
+		r.DeleteServiceAccounts = append(r.DeleteServiceAccounts, &deleteServiceAccountFromConfig{orgRef: sa.mapToModel(), Name: sa.Name.Value()})
+	}
+
+	r.FolderPermissions = mapResourcePermissions(cfg.FolderPermissions)
+	r.DashboardPermissions = mapResourcePermissions(cfg.DashboardPermissions)
+
+	return r
+}
+
+func mapResourcePermissions(resources []*resourcePermissionsV1) []*resourcePermissionsFromConfig {
+	var result []*resourcePermissionsFromConfig
+	for _, rp := range resources {
+		resource := &resourcePermissionsFromConfig{orgRef: rp.mapToModel(), UID: rp.UID.Value()}
+		for _, p := range rp.Permissions {
+			resource.Permissions = append(resource.Permissions, &permissionFromConfig{
+				UserLogin:  p.User.Value(),
+				Team:       p.Team.Value(),
+				Role:       p.Role.Value(),
+				Permission: p.Permission.Value(),
+			})
+		}
+		result = append(result, resource)
+	}
+	return result
+}
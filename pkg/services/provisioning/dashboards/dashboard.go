@@ -133,7 +133,7 @@ func getFileReaders(
 
 	for _, config := range configs {
 		switch config.Type {
-		case "file":
+		case "file", "http", "git":
 			fileReader, err := NewDashboardFileReader(config, logger.New("type", config.Type, "name", config.Name), service, store)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
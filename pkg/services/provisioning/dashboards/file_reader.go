@@ -34,6 +34,10 @@ type FileReader struct {
 	dashboardProvisioningService dashboards.DashboardProvisioningService
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
+	// source copies the dashboards of http and git providers to Path before each walk.
+	source dashboardSource
+	// watch reloads the dashboards on file system events in addition to polling.
+	watch bool
 
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
@@ -42,15 +46,30 @@ type FileReader struct {
 
 // NewDashboardFileReader returns a new filereader based on `config`
 func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	source, err := newDashboardSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	var path string
-	path, ok := cfg.Options["path"].(string)
-	if !ok {
-		path, ok = cfg.Options["folder"].(string)
+	if source != nil {
+		path = source.dir()
+	} else {
+		var ok bool
+		path, ok = cfg.Options["path"].(string)
 		if !ok {
-			return nil, fmt.Errorf("failed to load dashboards, path param is not a string")
+			path, ok = cfg.Options["folder"].(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to load dashboards, path param is not a string")
+			}
+
+			log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
 		}
+	}
 
-		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
+	watch, _ := cfg.Options["watch"].(bool)
+	if watch && source != nil {
+		return nil, fmt.Errorf("'watch' option is only supported by file providers")
 	}
 
 	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
@@ -65,15 +84,29 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		dashboardProvisioningService: service,
 		dashboardStore:               dashboardStore,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		source:                       source,
+		watch:                        watch,
 		usageTracker:                 newUsageTracker(),
 	}, nil
 }
 
 // pollChanges periodically runs walkDisk based on interval specified in the config.
+// Providers with the watch option also run it shortly after the files change.
 func (fr *FileReader) pollChanges(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
+	defer ticker.Stop()
+
+	var changes <-chan struct{}
+	if fr.watch {
+		changes = fr.watchChanges(ctx)
+	}
+
 	for {
 		select {
+		case <-changes:
+			if err := fr.walkDisk(ctx); err != nil {
+				fr.log.Error("failed to search for dashboards", "error", err)
+			}
 		case <-ticker.C:
 			if err := fr.walkDisk(ctx); err != nil {
 				fr.log.Error("failed to search for dashboards", "error", err)
@@ -87,6 +120,13 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	if fr.source != nil {
+		// keep provisioning the last synced copy when the remote is unavailable
+		if err := fr.source.sync(ctx); err != nil {
+			fr.log.Error("failed to sync dashboards from remote", "error", err)
+		}
+	}
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
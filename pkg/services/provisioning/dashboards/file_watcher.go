@@ -0,0 +1,86 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce groups the events of a batch of file changes, e.g. a git pull, into a single walk.
+const watchDebounce = 500 * time.Millisecond
+
+// watchChanges watches the provider path and its sub folders and notifies the returned channel once
+// the files stopped changing. When the path cannot be watched, the provider falls back to polling.
+func (fr *FileReader) watchChanges(ctx context.Context) <-chan struct{} {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fr.log.Warn("Failed to watch dashboards, falling back to polling", "error", err)
+		return nil
+	}
+
+	if err := fr.addWatches(watcher, fr.resolvedPath()); err != nil {
+		fr.log.Warn("Failed to watch dashboards, falling back to polling", "path", fr.Path, "error", err)
+		_ = watcher.Close()
+		return nil
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer func() { _ = watcher.Close() }()
+
+		debounce := time.NewTimer(watchDebounce)
+		debounce.Stop()
+		defer debounce.Stop()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						if err := fr.addWatches(watcher, event.Name); err != nil {
+							fr.log.Warn("Failed to watch dashboards folder", "path", event.Name, "error", err)
+						}
+					}
+				}
+				debounce.Reset(watchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fr.log.Error("Dashboards watcher error", "error", err)
+			case <-debounce.C:
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes
+}
+
+// addWatches adds the folder and its sub folders to the watcher, skipping hidden ones like walkDisk does.
+func (fr *FileReader) addWatches(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
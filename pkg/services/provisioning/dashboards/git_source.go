@@ -0,0 +1,140 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// gitSource keeps a clone of a git repository checked out at a pinned branch, tag or commit.
+type gitSource struct {
+	url       string
+	ref       string
+	path      string
+	directory string
+	auth      transport.AuthMethod
+}
+
+func newGitSource(cfg *config) (*gitSource, error) {
+	url, _ := cfg.Options["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("url param is required for git dashboard providers")
+	}
+
+	ref, _ := cfg.Options["ref"].(string)
+	if ref == "" {
+		ref = "main"
+	}
+
+	directory, _ := cfg.Options["directory"].(string)
+	directory = filepath.Clean(directory)
+	if filepath.IsAbs(directory) || directory == ".." || strings.HasPrefix(directory, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("directory param must be a relative path inside the repository")
+	}
+
+	s := &gitSource{
+		url:       url,
+		ref:       ref,
+		path:      localCopyPath(cfg),
+		directory: directory,
+	}
+
+	username, _ := cfg.Options["username"].(string)
+	password, _ := cfg.Options["password"].(string)
+	if username != "" || password != "" {
+		s.auth = &githttp.BasicAuth{Username: username, Password: password}
+	}
+
+	return s, nil
+}
+
+func (s *gitSource) dir() string {
+	return filepath.Join(s.path, s.directory)
+}
+
+func (s *gitSource) sync(ctx context.Context) error {
+	repo, err := s.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs: []gitconfig.RefSpec{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Auth:  s.auth,
+		Force: true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+
+	hash, err := s.resolve(repo)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true})
+}
+
+// open returns the local clone, cloning the repository again when it is missing or points to another remote.
+func (s *gitSource) open(ctx context.Context) (*git.Repository, error) {
+	repo, err := git.PlainOpen(s.path)
+	if err == nil {
+		remote, err := repo.Remote(git.DefaultRemoteName)
+		if err == nil && len(remote.Config().URLs) > 0 && remote.Config().URLs[0] == s.url {
+			return repo, nil
+		}
+	} else if !errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, err
+	}
+
+	if err := checkLocalCopyDir(s.path); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(s.path); err != nil {
+		return nil, err
+	}
+	repo, err = git.PlainCloneContext(ctx, s.path, false, &git.CloneOptions{
+		URL:        s.url,
+		Auth:       s.auth,
+		NoCheckout: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := markLocalCopyDir(s.path); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// resolve returns the commit of the ref, which can be a remote branch, a tag or a commit hash.
+func (s *gitSource) resolve(repo *git.Repository) (plumbing.Hash, error) {
+	for _, rev := range []string{
+		"refs/remotes/" + git.DefaultRemoteName + "/" + s.ref,
+		"refs/tags/" + s.ref,
+		s.ref,
+	} {
+		hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err == nil {
+			return *hash, nil
+		}
+	}
+	return plumbing.ZeroHash, fmt.Errorf("git ref %s not found in %s", s.ref, s.url)
+}
@@ -0,0 +1,298 @@
+package dashboards
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/slugify"
+)
+
+const (
+	// maxRemoteDashboardsSize limits the size of a downloaded dashboard or tarball, compressed and extracted.
+	maxRemoteDashboardsSize = 100 << 20
+	// maxRemoteDashboardSize limits the size of each extracted dashboard.
+	maxRemoteDashboardSize = 20 << 20
+	remoteFetchTimeout     = 30 * time.Second
+	// localCopyMarker is written to the local copies Grafana creates, so that a directory it did not
+	// create is never replaced or removed.
+	localCopyMarker = ".grafana-provisioning"
+)
+
+var (
+	errRemoteDashboardsTooLarge = fmt.Errorf("remote dashboards are larger than %d bytes", maxRemoteDashboardsSize)
+	errRemoteDashboardTooLarge  = fmt.Errorf("remote dashboard is larger than %d bytes", maxRemoteDashboardSize)
+)
+
+// dashboardSource copies the dashboards of a remote provider to a local directory which is then
+// read the same way as a file provider.
+type dashboardSource interface {
+	// sync updates the local copy of the dashboards.
+	sync(ctx context.Context) error
+	// dir is the local directory holding the dashboards.
+	dir() string
+}
+
+// newDashboardSource returns the source of the provider, or nil for file providers.
+func newDashboardSource(cfg *config) (dashboardSource, error) {
+	switch cfg.Type {
+	case "http":
+		return newHTTPSource(cfg)
+	case "git":
+		return newGitSource(cfg)
+	}
+	return nil, nil
+}
+
+// localCopyPath returns the path option of a remote provider, defaulting to a directory named after the provider.
+func localCopyPath(cfg *config) string {
+	if p, ok := cfg.Options["path"].(string); ok && p != "" {
+		return p
+	}
+	return filepath.Join(os.TempDir(), "grafana", "provisioning", "dashboards", slugify.Slugify(cfg.Name))
+}
+
+// httpSource downloads a dashboard JSON file or a tarball of dashboards from an HTTP(S) URL. The
+// download is skipped when the server reports the content unchanged through its ETag.
+type httpSource struct {
+	url     string
+	path    string
+	headers map[string]string
+	client  *http.Client
+	etag    string
+}
+
+func newHTTPSource(cfg *config) (*httpSource, error) {
+	rawURL, _ := cfg.Options["url"].(string)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("url param must be an http or https URL")
+	}
+
+	headers := map[string]string{}
+	if h, ok := cfg.Options["headers"].(map[string]interface{}); ok {
+		for name, value := range h {
+			if v, ok := value.(string); ok {
+				headers[name] = v
+			}
+		}
+	}
+
+	return &httpSource{
+		url:     rawURL,
+		path:    localCopyPath(cfg),
+		headers: headers,
+		client:  &http.Client{Timeout: remoteFetchTimeout},
+	}, nil
+}
+
+func (s *httpSource) dir() string {
+	return s.path
+}
+
+func (s *httpSource) sync(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+	// only rely on the cached ETag while the local copy is there
+	if _, err := os.Stat(s.path); err == nil && s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, s.url)
+	}
+
+	if resp.ContentLength > maxRemoteDashboardsSize {
+		return errRemoteDashboardsTooLarge
+	}
+
+	body := bufio.NewReader(&sizeLimitedReader{r: resp.Body, remaining: maxRemoteDashboardsSize, err: errRemoteDashboardsTooLarge})
+	if err := replaceDir(s.path, func(dir string) error {
+		return s.extract(body, resp.Header.Get("Content-Type"), dir)
+	}); err != nil {
+		return err
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	return nil
+}
+
+// extract writes the downloaded content to dir, unpacking it when it is a tarball.
+func (s *httpSource) extract(body *bufio.Reader, contentType string, dir string) error {
+	u, err := url.Parse(s.url)
+	if err != nil {
+		return err
+	}
+	name := path.Base(u.Path)
+
+	// gzip magic number
+	if magic, err := body.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gz.Close() }()
+		// a small tarball can decompress to much more than was downloaded
+		return extractTar(&sizeLimitedReader{r: gz, remaining: maxRemoteDashboardsSize, err: errRemoteDashboardsTooLarge}, dir)
+	}
+
+	if strings.HasSuffix(name, ".tar") || strings.Contains(contentType, "x-tar") {
+		return extractTar(body, dir)
+	}
+
+	if !strings.HasSuffix(name, ".json") {
+		name = "dashboard.json"
+	}
+	return writeFile(filepath.Join(dir, name), &sizeLimitedReader{r: body, remaining: maxRemoteDashboardSize, err: errRemoteDashboardTooLarge})
+}
+
+// extractTar writes the JSON files of the tarball to dir, keeping their folder structure.
+func extractTar(r io.Reader, dir string) error {
+	var extracted int64
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, ".json") {
+			continue
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file path in tarball: %s", header.Name)
+		}
+
+		// the reader of an entry returns exactly its size
+		if header.Size > maxRemoteDashboardSize {
+			return fmt.Errorf("%s: %w", header.Name, errRemoteDashboardTooLarge)
+		}
+		if extracted += header.Size; extracted > maxRemoteDashboardsSize {
+			return errRemoteDashboardsTooLarge
+		}
+
+		if err := writeFile(filepath.Join(dir, name), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// sizeLimitedReader fails with err instead of silently truncating the content once more than remaining
+// bytes are read.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, l.err
+	}
+	return n, err
+}
+
+func writeFile(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0750); err != nil {
+		return err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the path is checked to stay in the local copy directory.
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// replaceDir fills a new directory with fill and swaps it with dir, so that dir is never left half written.
+func replaceDir(dir string, fill func(dir string) error) error {
+	if err := checkLocalCopyDir(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0750); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-")
+	if err != nil {
+		return err
+	}
+
+	if err := fill(tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	if err := markLocalCopyDir(tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+// checkLocalCopyDir returns an error when dir is a non-empty directory which was not created by Grafana,
+// as it would be lost when replacing the local copy.
+func checkLocalCopyDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(entries) == 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, localCopyMarker)); err != nil {
+		return fmt.Errorf("refusing to replace %s: the directory is not empty and was not created by Grafana", dir)
+	}
+	return nil
+}
+
+// markLocalCopyDir marks dir as a local copy created by Grafana.
+func markLocalCopyDir(dir string) error {
+	return os.WriteFile(filepath.Join(dir, localCopyMarker), nil, 0600)
+}
@@ -0,0 +1,331 @@
+package dashboards
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestHTTPSource(t *testing.T) {
+	tarball := func(t *testing.T, files map[string]string) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, content := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	newSource := func(t *testing.T, url string) *httpSource {
+		t.Helper()
+		source, err := newHTTPSource(&config{
+			Name:    "remote",
+			Type:    "http",
+			Options: map[string]interface{}{"url": url, "path": filepath.Join(t.TempDir(), "remote")},
+		})
+		require.NoError(t, err)
+		return source
+	}
+
+	t.Run("Should extract a tarball and skip unchanged downloads", func(t *testing.T) {
+		body := tarball(t, map[string]string{
+			"team-a/dashboard.json": `{"title": "A"}`,
+			"README.md":             "not a dashboard",
+		})
+		downloads := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			downloads++
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write(body)
+		}))
+		defer server.Close()
+
+		source := newSource(t, server.URL+"/dashboards.tar.gz")
+		require.NoError(t, source.sync(context.Background()))
+		require.NoError(t, source.sync(context.Background()))
+		assert.Equal(t, 1, downloads)
+
+		content, err := os.ReadFile(filepath.Join(source.dir(), "team-a", "dashboard.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title": "A"}`, string(content))
+		assert.NoFileExists(t, filepath.Join(source.dir(), "README.md"))
+	})
+
+	t.Run("Should download a single dashboard", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"title": "Single"}`))
+		}))
+		defer server.Close()
+
+		source := newSource(t, server.URL+"/single.json")
+		require.NoError(t, source.sync(context.Background()))
+
+		content, err := os.ReadFile(filepath.Join(source.dir(), "single.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title": "Single"}`, string(content))
+	})
+
+	t.Run("Should keep the last copy when the download fails", func(t *testing.T) {
+		fail := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"title": "Single"}`))
+		}))
+		defer server.Close()
+
+		source := newSource(t, server.URL+"/single.json")
+		require.NoError(t, source.sync(context.Background()))
+
+		fail = true
+		require.Error(t, source.sync(context.Background()))
+		assert.FileExists(t, filepath.Join(source.dir(), "single.json"))
+	})
+
+	t.Run("Should reject files outside of the local copy", func(t *testing.T) {
+		body := tarball(t, map[string]string{"../escape.json": `{}`})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(body)
+		}))
+		defer server.Close()
+
+		source := newSource(t, server.URL+"/dashboards.tgz")
+		require.Error(t, source.sync(context.Background()))
+		assert.NoFileExists(t, filepath.Join(filepath.Dir(source.dir()), "escape.json"))
+	})
+
+	t.Run("Should not replace a directory Grafana did not create", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"title": "Single"}`))
+		}))
+		defer server.Close()
+
+		source := newSource(t, server.URL+"/single.json")
+		require.NoError(t, os.MkdirAll(source.dir(), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(source.dir(), "notes.txt"), []byte("keep me"), 0600))
+
+		require.Error(t, source.sync(context.Background()))
+		assert.FileExists(t, filepath.Join(source.dir(), "notes.txt"))
+		assert.NoFileExists(t, filepath.Join(source.dir(), "single.json"))
+	})
+
+	t.Run("Should fail instead of truncating content over the size limit", func(t *testing.T) {
+		_, err := io.ReadAll(&sizeLimitedReader{r: strings.NewReader("0123456789"), remaining: 10, err: errRemoteDashboardsTooLarge})
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(&sizeLimitedReader{r: strings.NewReader("0123456789"), remaining: 9, err: errRemoteDashboardsTooLarge})
+		require.ErrorIs(t, err, errRemoteDashboardsTooLarge)
+	})
+
+	t.Run("Should limit the size of extracted dashboards", func(t *testing.T) {
+		// a tarball of zeros is a fraction of its extracted size
+		bomb := func(t *testing.T, ext string, files int, size int64) []byte {
+			t.Helper()
+			var buf bytes.Buffer
+			gz, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+			require.NoError(t, err)
+			tw := tar.NewWriter(gz)
+			for i := 0; i < files; i++ {
+				require.NoError(t, tw.WriteHeader(&tar.Header{Name: fmt.Sprintf("dashboard-%d%s", i, ext), Mode: 0600, Size: size, Typeflag: tar.TypeReg}))
+				_, err := io.CopyN(tw, zeroReader{}, size)
+				require.NoError(t, err)
+			}
+			require.NoError(t, tw.Close())
+			require.NoError(t, gz.Close())
+			require.Less(t, buf.Len(), maxRemoteDashboardSize/100)
+			return buf.Bytes()
+		}
+
+		for name, tc := range map[string]struct {
+			body     []byte
+			expected error
+		}{
+			"a dashboard":    {body: bomb(t, ".json", 1, maxRemoteDashboardSize+1), expected: errRemoteDashboardTooLarge},
+			"the dashboards": {body: bomb(t, ".json", 6, maxRemoteDashboardSize-1), expected: errRemoteDashboardsTooLarge},
+			// files that are not dashboards are skipped, but still decompressed
+			"the decompressed tarball": {body: bomb(t, ".md", 1, maxRemoteDashboardsSize+1), expected: errRemoteDashboardsTooLarge},
+		} {
+			t.Run(name, func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write(tc.body)
+				}))
+				defer server.Close()
+
+				source := newSource(t, server.URL+"/dashboards.tar.gz")
+				require.ErrorIs(t, source.sync(context.Background()), tc.expected)
+				assert.NoDirExists(t, source.dir())
+			})
+		}
+	})
+
+	t.Run("Should require an http url", func(t *testing.T) {
+		_, err := newHTTPSource(&config{Name: "remote", Options: map[string]interface{}{"url": "file:///etc/passwd"}})
+		require.Error(t, err)
+	})
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestGitSource(t *testing.T) {
+	// dashboards are committed in a work repository and pushed to the bare repository the source clones
+	remote := t.TempDir()
+	_, err := git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	work := t.TempDir()
+	repo, err := git.PlainInit(work, false)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{remote}})
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(t *testing.T, title string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Join(work, "dashboards"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(work, "dashboards", "dashboard.json"), []byte(`{"title": "`+title+`"}`), 0600))
+		_, err := worktree.Add("dashboards/dashboard.json")
+		require.NoError(t, err)
+		_, err = worktree.Commit(title, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+		require.NoError(t, err)
+		require.NoError(t, repo.Push(&git.PushOptions{RefSpecs: []gitconfig.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"}}))
+	}
+
+	commit(t, "v1")
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1", head.Hash(), nil)
+	require.NoError(t, err)
+	commit(t, "v2")
+
+	readTitle := func(t *testing.T, source *gitSource) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(source.dir(), "dashboard.json"))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	path := filepath.Join(t.TempDir(), "clone")
+	newSource := func(t *testing.T, ref string) *gitSource {
+		t.Helper()
+		source, err := newGitSource(&config{
+			Name:    "git",
+			Type:    "git",
+			Options: map[string]interface{}{"url": remote, "ref": ref, "directory": "dashboards", "path": path},
+		})
+		require.NoError(t, err)
+		return source
+	}
+
+	t.Run("Should check out a tag", func(t *testing.T) {
+		source := newSource(t, "v1")
+		require.NoError(t, source.sync(context.Background()))
+		assert.JSONEq(t, `{"title": "v1"}`, readTitle(t, source))
+	})
+
+	t.Run("Should follow a branch", func(t *testing.T) {
+		source := newSource(t, "master")
+		require.NoError(t, source.sync(context.Background()))
+		assert.JSONEq(t, `{"title": "v2"}`, readTitle(t, source))
+
+		commit(t, "v3")
+		require.NoError(t, source.sync(context.Background()))
+		assert.JSONEq(t, `{"title": "v3"}`, readTitle(t, source))
+	})
+
+	t.Run("Should fail on an unknown ref", func(t *testing.T) {
+		require.Error(t, newSource(t, "missing").sync(context.Background()))
+	})
+
+	t.Run("Should not replace a directory Grafana did not create", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep me"), 0600))
+		source, err := newGitSource(&config{
+			Name:    "git",
+			Type:    "git",
+			Options: map[string]interface{}{"url": remote, "path": dir},
+		})
+		require.NoError(t, err)
+
+		require.Error(t, source.sync(context.Background()))
+		assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+	})
+
+	t.Run("Should reject a directory outside of the repository", func(t *testing.T) {
+		_, err := newGitSource(&config{Name: "git", Options: map[string]interface{}{"url": remote, "directory": "../.."}})
+		require.Error(t, err)
+	})
+}
+
+func TestFileReaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	reader, err := NewDashboardFileReader(&config{
+		Name:    "watched",
+		Type:    "file",
+		Options: map[string]interface{}{"path": dir, "watch": true},
+	}, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := reader.watchChanges(ctx)
+	require.NotNil(t, changes)
+
+	// files in new sub folders are watched as well
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "team-a"), 0750))
+	require.Eventually(t, func() bool {
+		select {
+		case <-changes:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team-a", "dashboard.json"), []byte(`{}`), 0600))
+	require.Eventually(t, func() bool {
+		select {
+		case <-changes:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -3,9 +3,11 @@ package datasources
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/correlations"
@@ -269,6 +271,52 @@ func TestDatasourceAsConfig(t *testing.T) {
 			require.Equal(t, 0, len(correlationsStore.deletedByTargetUID))
 		})
 
+		t.Run("Provisions exported external correlations with transformations as they were", func(t *testing.T) {
+			config := correlations.CorrelationConfig{
+				Field:  "message",
+				Type:   correlations.ConfigTypeExternal,
+				Target: map[string]interface{}{"url": "https://example.com/trace/${traceId}"},
+				Transformations: correlations.Transformations{
+					{Type: "jsonpath", Expression: "$.trace.id", MapValue: "traceId"},
+					{Type: "regex", Expression: "user=(\\w+)", Field: "message", MapValue: "user"},
+				},
+			}
+			correlation, err := correlations.NewProvisioningCorrelationExport(correlations.Correlation{
+				Label:       "Trace",
+				Description: "Open the trace",
+				Config:      config,
+			})
+			require.NoError(t, err)
+
+			export, err := yaml.Marshal(correlations.ProvisioningExport{
+				APIVersion: 1,
+				Datasources: []correlations.ProvisioningDataSourceExport{{
+					OrgID:        1,
+					Name:         "Loki",
+					Type:         "loki",
+					UID:          "loki",
+					Correlations: []correlations.ProvisioningCorrelationExport{correlation},
+				}},
+			})
+			require.NoError(t, err)
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "datasources.yaml"), export, 0600))
+
+			store := &spyStore{}
+			orgFake := &orgtest.FakeOrgService{}
+			correlationsStore := &mockCorrelationsStore{}
+			dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+			err = dc.applyChanges(context.Background(), dir)
+			require.NoError(t, err)
+
+			require.Len(t, correlationsStore.created, 1)
+			created := correlationsStore.created[0]
+			require.Nil(t, created.TargetUID)
+			require.Equal(t, "Trace", created.Label)
+			require.Equal(t, "Open the trace", created.Description)
+			require.Equal(t, config, created.Config)
+		})
+
 		t.Run("Deleting datasource deletes existing correlations", func(t *testing.T) {
 			store := &spyStore{items: []*datasources.DataSource{{Name: "old-data-source", OrgID: 1, ID: 1, UID: "some-uid"}}}
 			orgFake := &orgtest.FakeOrgService{}
@@ -0,0 +1,158 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+)
+
+var elementKinds = map[string]model.LibraryElementKind{
+	"panel":    model.PanelElement,
+	"variable": model.VariableElement,
+}
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*libraryElementsFile, error) {
+	var files []*libraryElementsFile
+	cr.log.Debug("Looking for library element provisioning files", "path", path)
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read library element provisioning files from directory", "path", path, "error", err)
+		return files, nil
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".yaml") && !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+
+		cr.log.Debug("Parsing library element provisioning file", "path", path, "file.Name", entry.Name())
+		file, err := cr.parseConfig(path, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failure to parse file %s: %w", entry.Name(), err)
+		}
+
+		if err := cr.loadModels(path, file); err != nil {
+			return nil, fmt.Errorf("failure to load models of file %s: %w", entry.Name(), err)
+		}
+
+		if err := validate(file); err != nil {
+			return nil, fmt.Errorf("invalid file %s: %w", entry.Name(), err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (cr *configReader) parseConfig(path string, name string) (*libraryElementsFile, error) {
+	filename, err := filepath.Abs(filepath.Join(path, name))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *libraryElementsFileV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToModel(name), nil
+}
+
+// loadModels reads the models of the elements that reference a JSON file, relative to the provisioning directory.
+func (cr *configReader) loadModels(path string, file *libraryElementsFile) error {
+	for _, el := range file.LibraryElements {
+		if el.File == "" {
+			continue
+		}
+		if el.Model != nil {
+			return fmt.Errorf("library element %q has both a model and a file", el.UID)
+		}
+
+		filename := el.File
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(path, filename)
+		}
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because the file is configured by the administrator
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("library element %q: %w", el.UID, err)
+		}
+		if err := json.Unmarshal(data, &el.Model); err != nil {
+			return fmt.Errorf("library element %q: invalid model in %s: %w", el.UID, el.File, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the required fields and the element kinds, and defaults missing organizations to the main one.
+func validate(file *libraryElementsFile) error {
+	var errStrings []string
+	fail := func(format string, args ...interface{}) {
+		errStrings = append(errStrings, fmt.Sprintf(format, args...))
+	}
+
+	uids := map[string]bool{}
+	for i, el := range file.LibraryElements {
+		el.orgRef.setDefault()
+		if el.UID == "" {
+			fail("library element item %d doesn't contain required field uid", i+1)
+		}
+		if el.Name == "" {
+			fail("library element item %d doesn't contain required field name", i+1)
+		}
+		if el.Kind == "" {
+			el.Kind = "panel"
+		}
+		if _, ok := elementKinds[el.Kind]; !ok {
+			fail("library element %q has invalid kind %q, must be panel or variable", el.UID, el.Kind)
+		}
+		if el.Model == nil {
+			fail("library element %q doesn't contain a model or a file", el.UID)
+		}
+		key := fmt.Sprintf("%d/%s/%s", el.OrgID, el.OrgName, el.UID)
+		if uids[key] {
+			fail("library element %q is provisioned more than once", el.UID)
+		}
+		uids[key] = true
+	}
+
+	for i, el := range file.DeleteLibraryElements {
+		el.orgRef.setDefault()
+		if el.UID == "" {
+			fail("deleteLibraryElements item %d doesn't contain required field uid", i+1)
+		}
+	}
+
+	if len(errStrings) != 0 {
+		return fmt.Errorf(strings.Join(errStrings, "\n"))
+	}
+	return nil
+}
+
+// setDefault references the main organization when neither the ID nor the name is set.
+func (ref *orgRef) setDefault() {
+	if ref.OrgID < 1 && ref.OrgName == "" {
+		ref.OrgID = 1
+	}
+}
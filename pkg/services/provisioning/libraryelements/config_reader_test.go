@@ -0,0 +1,66 @@
+package libraryelements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	testFileCorrectProperties = "./testdata/correct-properties"
+	testFileInvalidKind       = "./testdata/invalid-kind"
+	testFileBrokenYAML        = "./testdata/broken-yaml"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := configReader{log: log.NewNopLogger()}
+
+	t.Run("a file with correct properties should not error", func(t *testing.T) {
+		t.Setenv("ENVIRONMENTS", "dev,prod")
+		files, err := reader.readConfig(testFileCorrectProperties)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		file := files[0]
+
+		require.Len(t, file.LibraryElements, 2)
+		panel := file.LibraryElements[0]
+		assert.Equal(t, orgRef{OrgID: 1}, panel.orgRef, "the organization should default to 1")
+		assert.Equal(t, "panel", panel.Kind, "the kind should default to panel")
+		assert.Equal(t, "platform", panel.FolderUID)
+		assert.Equal(t, map[string]interface{}{
+			"type":       "timeseries",
+			"title":      "Service latency",
+			"datasource": "${datasource}",
+		}, panel.Model)
+
+		variable := file.LibraryElements[1]
+		assert.Equal(t, int64(2), variable.OrgID)
+		assert.Equal(t, "variable", variable.Kind)
+		assert.Equal(t, "${ENVIRONMENTS}", variable.Model["query"], "models should not be interpolated")
+
+		require.Len(t, file.DeleteLibraryElements, 1)
+		assert.Equal(t, orgRef{OrgName: "Other"}, file.DeleteLibraryElements[0].orgRef)
+	})
+
+	t.Run("a file with invalid properties should error", func(t *testing.T) {
+		_, err := reader.readConfig(testFileInvalidKind)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `library element "service-latency" has invalid kind "dashboard"`)
+		assert.Contains(t, err.Error(), "library element item 2 doesn't contain required field uid")
+		assert.Contains(t, err.Error(), `library element "without-model" doesn't contain a model or a file`)
+	})
+
+	t.Run("a broken YAML file should error", func(t *testing.T) {
+		_, err := reader.readConfig(testFileBrokenYAML)
+		require.Error(t, err)
+	})
+
+	t.Run("a missing folder should not error", func(t *testing.T) {
+		files, err := reader.readConfig("./testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+}
@@ -0,0 +1,193 @@
+package libraryelements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// syncedModelFields are added to the stored model when saving an element, so they're only
+// compared when they are set in the configuration.
+var syncedModelFields = []string{"name", "type", "description"}
+
+type ProvisionerConfig struct {
+	Path                  string
+	OrgService            org.Service
+	FolderService         folder.Service
+	LibraryElementService libraryelements.Service
+}
+
+// Provision scans a directory for library element provisioning files and creates or updates the
+// library panels and variables in those files. An element is only updated when its configuration
+// changed, so that every change is recorded as a single version.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.libraryelements")
+	reader := configReader{log: logger}
+	files, err := reader.readConfig(cfg.Path)
+	if err != nil {
+		return err
+	}
+
+	p := &LibraryElementProvisioner{log: logger, cfg: cfg}
+	for _, file := range files {
+		if err := p.apply(ctx, file); err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+	}
+	return nil
+}
+
+// LibraryElementProvisioner is responsible for provisioning library elements based on
+// configuration read by the `configReader`
+type LibraryElementProvisioner struct {
+	log log.Logger
+	cfg ProvisionerConfig
+}
+
+func (p *LibraryElementProvisioner) apply(ctx context.Context, file *libraryElementsFile) error {
+	for _, el := range file.DeleteLibraryElements {
+		if err := p.deleteElement(ctx, el); err != nil {
+			return fmt.Errorf("library element %q: %w", el.UID, err)
+		}
+	}
+	for _, el := range file.LibraryElements {
+		if err := p.provisionElement(ctx, el); err != nil {
+			return fmt.Errorf("library element %q: %w", el.UID, err)
+		}
+	}
+	return nil
+}
+
+func (p *LibraryElementProvisioner) provisionElement(ctx context.Context, el *libraryElementFromConfig) error {
+	orgID, err := p.resolveOrg(ctx, el.orgRef)
+	if err != nil {
+		return err
+	}
+	usr := backgroundUser(orgID)
+	folderID, err := p.resolveFolder(ctx, usr, el.FolderUID)
+	if err != nil {
+		return err
+	}
+	elementModel, err := json.Marshal(el.Model)
+	if err != nil {
+		return err
+	}
+	kind := int64(elementKinds[el.Kind])
+
+	existing, err := p.cfg.LibraryElementService.GetElement(ctx, usr, el.UID)
+	if errors.Is(err, model.ErrLibraryElementNotFound) {
+		p.log.Info("Creating library element from configuration", "uid", el.UID, "orgId", orgID)
+		_, err := p.cfg.LibraryElementService.CreateElement(ctx, usr, model.CreateLibraryElementCommand{
+			FolderID: folderID,
+			Name:     el.Name,
+			Model:    elementModel,
+			Kind:     kind,
+			UID:      el.UID,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Kind != kind {
+		return fmt.Errorf("the kind of an existing library element cannot be changed")
+	}
+	if existing.Name == el.Name && existing.FolderID == folderID && modelsEqual(existing.Model, el.Model) {
+		p.log.Debug("Library element from configuration is up to date", "uid", el.UID, "orgId", orgID)
+		return nil
+	}
+
+	p.log.Info("Updating library element from configuration", "uid", el.UID, "orgId", orgID)
+	_, err = p.cfg.LibraryElementService.PatchElement(ctx, usr, model.PatchLibraryElementCommand{
+		FolderID: folderID,
+		Name:     el.Name,
+		Model:    elementModel,
+		Kind:     kind,
+		Version:  existing.Version,
+	}, el.UID)
+	return err
+}
+
+func (p *LibraryElementProvisioner) deleteElement(ctx context.Context, el *deleteLibraryElementFromConfig) error {
+	orgID, err := p.resolveOrg(ctx, el.orgRef)
+	if err != nil {
+		return err
+	}
+	_, err = p.cfg.LibraryElementService.DeleteElement(ctx, backgroundUser(orgID), el.UID)
+	if errors.Is(err, model.ErrLibraryElementNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.log.Info("Deleted library element from configuration", "uid", el.UID, "orgId", orgID)
+	return nil
+}
+
+func (p *LibraryElementProvisioner) resolveOrg(ctx context.Context, ref orgRef) (int64, error) {
+	if ref.OrgID > 0 {
+		return ref.OrgID, nil
+	}
+	o, err := p.cfg.OrgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: ref.OrgName})
+	if err != nil {
+		return 0, fmt.Errorf("organization %q: %w", ref.OrgName, err)
+	}
+	return o.ID, nil
+}
+
+func (p *LibraryElementProvisioner) resolveFolder(ctx context.Context, usr *user.SignedInUser, folderUID string) (int64, error) {
+	if folderUID == "" || folderUID == accesscontrol.GeneralFolderUID {
+		return 0, nil
+	}
+	f, err := p.cfg.FolderService.Get(ctx, &folder.GetFolderQuery{OrgID: usr.OrgID, UID: &folderUID, SignedInUser: usr})
+	if err != nil {
+		return 0, fmt.Errorf("folder %q: %w", folderUID, err)
+	}
+	return f.ID, nil
+}
+
+// modelsEqual reports whether the stored model matches the configured one.
+func modelsEqual(stored json.RawMessage, configured map[string]interface{}) bool {
+	var storedModel map[string]interface{}
+	if err := json.Unmarshal(stored, &storedModel); err != nil {
+		return false
+	}
+	// round trip the configured model so that both use the same types
+	data, err := json.Marshal(configured)
+	if err != nil {
+		return false
+	}
+	var configuredModel map[string]interface{}
+	if err := json.Unmarshal(data, &configuredModel); err != nil {
+		return false
+	}
+
+	for _, field := range syncedModelFields {
+		if _, ok := configuredModel[field]; !ok {
+			delete(storedModel, field)
+		}
+	}
+	return reflect.DeepEqual(storedModel, configuredModel)
+}
+
+// backgroundUser is the identity used to manage the library elements of an organization.
+func backgroundUser(orgID int64) *user.SignedInUser {
+	return accesscontrol.BackgroundUser("library_element_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeFoldersAll},
+	})
+}
@@ -0,0 +1,158 @@
+package libraryelements
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestLibraryElementProvisioner(t *testing.T) {
+	panel := func() *libraryElementFromConfig {
+		return &libraryElementFromConfig{
+			orgRef:    orgRef{OrgID: 1},
+			UID:       "service-latency",
+			Name:      "Service latency",
+			Kind:      "panel",
+			FolderUID: "platform",
+			Model:     map[string]interface{}{"type": "timeseries", "title": "Service latency"},
+		}
+	}
+
+	t.Run("should create a missing element in the configured folder", func(t *testing.T) {
+		elements := newFakeLibraryElementService()
+		p := newTestProvisioner(elements)
+
+		err := p.apply(context.Background(), &libraryElementsFile{LibraryElements: []*libraryElementFromConfig{panel()}})
+		require.NoError(t, err)
+
+		require.Len(t, elements.created, 1)
+		assert.Equal(t, "service-latency", elements.created[0].UID)
+		assert.Equal(t, int64(42), elements.created[0].FolderID)
+		assert.Equal(t, int64(model.PanelElement), elements.created[0].Kind)
+		assert.Empty(t, elements.patched)
+	})
+
+	t.Run("should not update an element that did not change", func(t *testing.T) {
+		elements := newFakeLibraryElementService()
+		// the stored model contains the description that is added when saving
+		elements.elements["service-latency"] = model.LibraryElementDTO{
+			UID:      "service-latency",
+			Name:     "Service latency",
+			Kind:     int64(model.PanelElement),
+			FolderID: 42,
+			Model:    json.RawMessage(`{"type": "timeseries", "title": "Service latency", "description": ""}`),
+			Version:  3,
+		}
+		p := newTestProvisioner(elements)
+
+		err := p.apply(context.Background(), &libraryElementsFile{LibraryElements: []*libraryElementFromConfig{panel()}})
+		require.NoError(t, err)
+		assert.Empty(t, elements.created)
+		assert.Empty(t, elements.patched)
+	})
+
+	t.Run("should update an element whose model changed", func(t *testing.T) {
+		elements := newFakeLibraryElementService()
+		elements.elements["service-latency"] = model.LibraryElementDTO{
+			UID:      "service-latency",
+			Name:     "Service latency",
+			Kind:     int64(model.PanelElement),
+			FolderID: 42,
+			Model:    json.RawMessage(`{"type": "stat", "title": "Service latency"}`),
+			Version:  3,
+		}
+		p := newTestProvisioner(elements)
+
+		err := p.apply(context.Background(), &libraryElementsFile{LibraryElements: []*libraryElementFromConfig{panel()}})
+		require.NoError(t, err)
+		require.Len(t, elements.patched, 1)
+		assert.Equal(t, int64(3), elements.patched[0].Version)
+		assert.JSONEq(t, `{"type": "timeseries", "title": "Service latency"}`, string(elements.patched[0].Model))
+	})
+
+	t.Run("should not change the kind of an element", func(t *testing.T) {
+		elements := newFakeLibraryElementService()
+		elements.elements["service-latency"] = model.LibraryElementDTO{UID: "service-latency", Kind: int64(model.VariableElement)}
+		p := newTestProvisioner(elements)
+
+		err := p.apply(context.Background(), &libraryElementsFile{LibraryElements: []*libraryElementFromConfig{panel()}})
+		require.Error(t, err)
+		assert.Empty(t, elements.patched)
+	})
+
+	t.Run("should ignore elements to delete that do not exist", func(t *testing.T) {
+		elements := newFakeLibraryElementService()
+		elements.elements["legacy"] = model.LibraryElementDTO{UID: "legacy"}
+		p := newTestProvisioner(elements)
+
+		err := p.apply(context.Background(), &libraryElementsFile{DeleteLibraryElements: []*deleteLibraryElementFromConfig{
+			{orgRef: orgRef{OrgID: 1}, UID: "legacy"},
+			{orgRef: orgRef{OrgID: 1}, UID: "missing"},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"legacy"}, elements.deleted)
+	})
+}
+
+func newTestProvisioner(elements *fakeLibraryElementService) *LibraryElementProvisioner {
+	folders := foldertest.NewFakeService()
+	folders.ExpectedFolder = &folder.Folder{ID: 42, UID: "platform"}
+	return &LibraryElementProvisioner{
+		log: log.NewNopLogger(),
+		cfg: ProvisionerConfig{
+			OrgService:            orgtest.NewOrgServiceFake(),
+			FolderService:         folders,
+			LibraryElementService: elements,
+		},
+	}
+}
+
+// fakeLibraryElementService keeps elements in memory and records the changes.
+type fakeLibraryElementService struct {
+	libraryelements.Service
+	elements map[string]model.LibraryElementDTO
+	created  []model.CreateLibraryElementCommand
+	patched  []model.PatchLibraryElementCommand
+	deleted  []string
+}
+
+func newFakeLibraryElementService() *fakeLibraryElementService {
+	return &fakeLibraryElementService{elements: map[string]model.LibraryElementDTO{}}
+}
+
+func (f *fakeLibraryElementService) GetElement(_ context.Context, _ *user.SignedInUser, uid string) (model.LibraryElementDTO, error) {
+	el, ok := f.elements[uid]
+	if !ok {
+		return model.LibraryElementDTO{}, model.ErrLibraryElementNotFound
+	}
+	return el, nil
+}
+
+func (f *fakeLibraryElementService) CreateElement(_ context.Context, _ *user.SignedInUser, cmd model.CreateLibraryElementCommand) (model.LibraryElementDTO, error) {
+	f.created = append(f.created, cmd)
+	return model.LibraryElementDTO{UID: cmd.UID}, nil
+}
+
+func (f *fakeLibraryElementService) PatchElement(_ context.Context, _ *user.SignedInUser, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementDTO, error) {
+	f.patched = append(f.patched, cmd)
+	return model.LibraryElementDTO{UID: uid}, nil
+}
+
+func (f *fakeLibraryElementService) DeleteElement(_ context.Context, _ *user.SignedInUser, uid string) (int64, error) {
+	if _, ok := f.elements[uid]; !ok {
+		return 0, model.ErrLibraryElementNotFound
+	}
+	f.deleted = append(f.deleted, uid)
+	return 1, nil
+}
@@ -0,0 +1,96 @@
+package libraryelements
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// libraryElementsFile is a normalized data object for library element provisioning files. Any config
+// version should be mappable to this type.
+type libraryElementsFile struct {
+	Filename              string
+	LibraryElements       []*libraryElementFromConfig
+	DeleteLibraryElements []*deleteLibraryElementFromConfig
+}
+
+// orgRef references an organization by ID or by name.
+type orgRef struct {
+	OrgID   int64
+	OrgName string
+}
+
+type libraryElementFromConfig struct {
+	orgRef
+	UID       string
+	Name      string
+	Kind      string
+	FolderUID string
+	// File is the path of a JSON file holding the model, relative to the provisioning file.
+	File  string
+	Model map[string]interface{}
+}
+
+type deleteLibraryElementFromConfig struct {
+	orgRef
+	UID string
+}
+
+type libraryElementsFileV1 struct {
+	configVersion
+	LibraryElements       []*libraryElementV1       `json:"libraryElements" yaml:"libraryElements"`
+	DeleteLibraryElements []*deleteLibraryElementV1 `json:"deleteLibraryElements" yaml:"deleteLibraryElements"`
+}
+
+type orgRefV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+type libraryElementV1 struct {
+	orgRefV1  `yaml:",inline"`
+	UID       values.StringValue `json:"uid" yaml:"uid"`
+	Name      values.StringValue `json:"name" yaml:"name"`
+	Kind      values.StringValue `json:"kind" yaml:"kind"`
+	FolderUID values.StringValue `json:"folderUid" yaml:"folderUid"`
+	File      values.StringValue `json:"file" yaml:"file"`
+	// Model is not interpolated, as panel models use the same syntax for template variables.
+	Model map[string]interface{} `json:"model" yaml:"model"`
+}
+
+type deleteLibraryElementV1 struct {
+	orgRefV1 `yaml:",inline"`
+	UID      values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (ref orgRefV1) mapToModel() orgRef {
+	return orgRef{OrgID: ref.OrgID.Value(), OrgName: ref.OrgName.Value()}
+}
+
+// mapToModel maps config syntax to a normalized libraryElementsFile object. Every version of the config
+// syntax should have this function.
+func (cfg *libraryElementsFileV1) mapToModel(filename string) *libraryElementsFile {
+	r := &libraryElementsFile{Filename: filename}
+	if cfg == nil {
+		return r
+	}
+
+	for _, el := range cfg.LibraryElements {
+		r.LibraryElements = append(r.LibraryElements, &libraryElementFromConfig{
+			orgRef:    el.mapToModel(),
+			UID:       el.UID.Value(),
+			Name:      el.Name.Value(),
+			Kind:      el.Kind.Value(),
+			FolderUID: el.FolderUID.Value(),
+			File:      el.File.Value(),
+			Model:     el.Model,
+		})
+	}
+	for _, el := range cfg.DeleteLibraryElements {
+		r.DeleteLibraryElements = append(r.DeleteLibraryElements, &deleteLibraryElementFromConfig{orgRef: el.mapToModel(), UID: el.UID.Value()})
+	}
+
+	return r
+}
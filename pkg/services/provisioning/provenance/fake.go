@@ -0,0 +1,49 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+)
+
+var _ Service = new(FakeService)
+
+// FakeService keeps provenances in memory.
+type FakeService struct {
+	records map[string]Provenance
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{records: map[string]Provenance{}}
+}
+
+func fakeKey(orgID int64, recordType, recordKey string) string {
+	return fmt.Sprintf("%d/%s/%s", orgID, recordType, recordKey)
+}
+
+func (f *FakeService) GetProvenance(_ context.Context, orgID int64, recordType, recordKey string) (Provenance, error) {
+	return f.records[fakeKey(orgID, recordType, recordKey)], nil
+}
+
+func (f *FakeService) GetProvenances(_ context.Context, orgID int64, recordType string) (map[string]Provenance, error) {
+	result := map[string]Provenance{}
+	prefix := fakeKey(orgID, recordType, "")
+	for key, p := range f.records {
+		if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+			result[key[len(prefix):]] = p
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeService) SetProvenance(_ context.Context, orgID int64, recordType, recordKey string, p Provenance) error {
+	if p == ProvenanceNone {
+		delete(f.records, fakeKey(orgID, recordType, recordKey))
+		return nil
+	}
+	f.records[fakeKey(orgID, recordType, recordKey)] = p
+	return nil
+}
+
+func (f *FakeService) DeleteProvenance(ctx context.Context, orgID int64, recordType, recordKey string) error {
+	return f.SetProvenance(ctx, orgID, recordType, recordKey, ProvenanceNone)
+}
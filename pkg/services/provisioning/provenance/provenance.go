@@ -0,0 +1,57 @@
+// Package provenance keeps track of the access control resources, like teams or folder
+// permissions, that are managed by file provisioning so that they cannot be changed through
+// the API.
+package provenance
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+type Provenance string
+
+const (
+	ProvenanceNone Provenance = ""
+	ProvenanceFile Provenance = "file"
+)
+
+// Record types of the provisioned resources. Users are not part of an organization and are
+// recorded in the global organization.
+const (
+	RecordTypeTeam                 = "team"
+	RecordTypeUser                 = "user"
+	RecordTypeServiceAccount       = "serviceAccount"
+	RecordTypeFolderPermissions    = "folderPermissions"
+	RecordTypeDashboardPermissions = "dashboardPermissions"
+)
+
+var ErrProvisioned = errutil.NewBase(errutil.StatusBadRequest, "provenance.provisioned",
+	errutil.WithPublicMessage("Resource is provisioned from a file and cannot be changed"))
+
+type Service interface {
+	// GetProvenance returns the provenance of the record, ProvenanceNone if it is not provisioned.
+	GetProvenance(ctx context.Context, orgID int64, recordType, recordKey string) (Provenance, error)
+	// GetProvenances returns the provenances of the records of the type, by record key.
+	GetProvenances(ctx context.Context, orgID int64, recordType string) (map[string]Provenance, error)
+	SetProvenance(ctx context.Context, orgID int64, recordType, recordKey string, p Provenance) error
+	DeleteProvenance(ctx context.Context, orgID int64, recordType, recordKey string) error
+}
+
+// CheckWritable returns ErrProvisioned when the record is provisioned.
+func CheckWritable(ctx context.Context, s Service, orgID int64, recordType, recordKey string) error {
+	p, err := s.GetProvenance(ctx, orgID, recordType, recordKey)
+	if err != nil {
+		return err
+	}
+	if p != ProvenanceNone {
+		return ErrProvisioned.Errorf("%s %s is provisioned", recordType, recordKey)
+	}
+	return nil
+}
+
+// IsProvisioned reports whether err was returned for a provisioned record.
+func IsProvisioned(err error) bool {
+	return errors.Is(err, ErrProvisioned)
+}
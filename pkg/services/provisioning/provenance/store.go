@@ -0,0 +1,80 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+// record is stored in the provenance_type table that alerting also uses for its provisioned
+// resources, the record types keep them apart.
+type record struct {
+	ID         int64 `xorm:"pk autoincr 'id'"`
+	OrgID      int64 `xorm:"'org_id'"`
+	RecordKey  string
+	RecordType string
+	Provenance Provenance
+}
+
+func (record) TableName() string {
+	return "provenance_type"
+}
+
+type store struct {
+	db db.DB
+}
+
+func ProvideService(sqlStore db.DB) Service {
+	return &store{db: sqlStore}
+}
+
+func (s *store) GetProvenance(ctx context.Context, orgID int64, recordType, recordKey string) (Provenance, error) {
+	provenance := ProvenanceNone
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var r record
+		has, err := sess.Where("record_key = ? AND record_type = ? AND org_id = ?", recordKey, recordType, orgID).Get(&r)
+		if err != nil {
+			return fmt.Errorf("failed to query for provenance: %w", err)
+		}
+		if has {
+			provenance = r.Provenance
+		}
+		return nil
+	})
+	return provenance, err
+}
+
+func (s *store) GetProvenances(ctx context.Context, orgID int64, recordType string) (map[string]Provenance, error) {
+	result := make(map[string]Provenance)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		records := make([]*record, 0)
+		if err := sess.Where("record_type = ? AND org_id = ?", recordType, orgID).Find(&records); err != nil {
+			return fmt.Errorf("failed to query for provenances: %w", err)
+		}
+		for _, r := range records {
+			result[r.RecordKey] = r.Provenance
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *store) SetProvenance(ctx context.Context, orgID int64, recordType, recordKey string, p Provenance) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("record_key = ? AND record_type = ? AND org_id = ?", recordKey, recordType, orgID).Delete(&record{}); err != nil {
+			return fmt.Errorf("failed to delete pre-existing provenance: %w", err)
+		}
+		if p == ProvenanceNone {
+			return nil
+		}
+		if _, err := sess.Insert(&record{OrgID: orgID, RecordKey: recordKey, RecordType: recordType, Provenance: p}); err != nil {
+			return fmt.Errorf("failed to save provenance: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *store) DeleteProvenance(ctx context.Context, orgID int64, recordType, recordKey string) error {
+	return s.SetProvenance(ctx, orgID, recordType, recordKey, ProvenanceNone)
+}
@@ -0,0 +1,46 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+func TestIntegrationProvenanceStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	s := ProvideService(db.InitTestDB(t))
+
+	t.Run("records are not provisioned by default", func(t *testing.T) {
+		p, err := s.GetProvenance(ctx, 1, RecordTypeTeam, "1")
+		require.NoError(t, err)
+		require.Equal(t, ProvenanceNone, p)
+		require.NoError(t, CheckWritable(ctx, s, 1, RecordTypeTeam, "1"))
+	})
+
+	t.Run("provisioned records are not writable", func(t *testing.T) {
+		require.NoError(t, s.SetProvenance(ctx, 1, RecordTypeTeam, "2", ProvenanceFile))
+		// setting it again must not duplicate the record
+		require.NoError(t, s.SetProvenance(ctx, 1, RecordTypeTeam, "2", ProvenanceFile))
+
+		err := CheckWritable(ctx, s, 1, RecordTypeTeam, "2")
+		require.True(t, IsProvisioned(err))
+		require.NoError(t, CheckWritable(ctx, s, 2, RecordTypeTeam, "2"), "provenances are scoped to the organization")
+		require.NoError(t, CheckWritable(ctx, s, 1, RecordTypeServiceAccount, "2"), "provenances are scoped to the record type")
+
+		provenances, err := s.GetProvenances(ctx, 1, RecordTypeTeam)
+		require.NoError(t, err)
+		require.Equal(t, map[string]Provenance{"2": ProvenanceFile}, provenances)
+	})
+
+	t.Run("deleted provenances make records writable again", func(t *testing.T) {
+		require.NoError(t, s.SetProvenance(ctx, 1, RecordTypeUser, "3", ProvenanceFile))
+		require.NoError(t, s.DeleteProvenance(ctx, 1, RecordTypeUser, "3"))
+		require.NoError(t, CheckWritable(ctx, s, 1, RecordTypeUser, "3"))
+	})
+}
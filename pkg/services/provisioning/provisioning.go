@@ -17,19 +17,26 @@ import (
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/provisioning/access"
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	prov_libraryelements "github.com/grafana/grafana/pkg/services/provisioning/libraryelements"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -51,6 +58,14 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	userService user.Service,
+	teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	serviceAccountsService serviceaccounts.Service,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	provenanceService provenance.Service,
+	libraryElementService libraryelements.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -64,8 +79,11 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionAccess:              access.Provision,
+		provisionLibraryElements:     prov_libraryelements.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
+		folderService:                folderService,
 		datasourceService:            datasourceService,
 		correlationsService:          correlationsService,
 		alertingService:              alertingService,
@@ -75,6 +93,14 @@ func ProvideService(
 		secretService:                secrectService,
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
+		userService:                  userService,
+		teamService:                  teamService,
+		teamPermissionsService:       teamPermissionsService,
+		serviceAccountsService:       serviceAccountsService,
+		folderPermissionsService:     folderPermissionsService,
+		dashboardPermissionsService:  dashboardPermissionsService,
+		provenanceService:            provenanceService,
+		libraryElementService:        libraryElementService,
 	}
 	return s, nil
 }
@@ -87,6 +113,8 @@ type ProvisioningService interface {
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionAccess(ctx context.Context) error
+	ProvisionLibraryElements(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -95,11 +123,13 @@ type ProvisioningService interface {
 func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 	logger := log.New("provisioning")
 	return &ProvisioningServiceImpl{
-		log:                     logger,
-		newDashboardProvisioner: dashboards.New,
-		provisionNotifiers:      notifiers.Provision,
-		provisionDatasources:    datasources.Provision,
-		provisionPlugins:        plugins.Provision,
+		log:                      logger,
+		newDashboardProvisioner:  dashboards.New,
+		provisionNotifiers:       notifiers.Provision,
+		provisionDatasources:     datasources.Provision,
+		provisionPlugins:         plugins.Provision,
+		provisionAccess:          access.Provision,
+		provisionLibraryElements: prov_libraryelements.Provision,
 	}
 }
 
@@ -111,11 +141,13 @@ func newProvisioningServiceImpl(
 	provisionPlugins func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error,
 ) *ProvisioningServiceImpl {
 	return &ProvisioningServiceImpl{
-		log:                     log.New("provisioning"),
-		newDashboardProvisioner: newDashboardProvisioner,
-		provisionNotifiers:      provisionNotifiers,
-		provisionDatasources:    provisionDatasources,
-		provisionPlugins:        provisionPlugins,
+		log:                      log.New("provisioning"),
+		newDashboardProvisioner:  newDashboardProvisioner,
+		provisionNotifiers:       provisionNotifiers,
+		provisionDatasources:     provisionDatasources,
+		provisionPlugins:         provisionPlugins,
+		provisionAccess:          access.Provision,
+		provisionLibraryElements: prov_libraryelements.Provision,
 	}
 }
 
@@ -135,9 +167,12 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionAccess              func(context.Context, access.ProvisionerConfig) error
+	provisionLibraryElements     func(context.Context, prov_libraryelements.ProvisionerConfig) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
+	folderService                folder.Service
 	datasourceService            datasourceservice.DataSourceService
 	correlationsService          correlations.Service
 	alertingService              *alerting.AlertNotificationService
@@ -145,6 +180,14 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	userService                  user.Service
+	teamService                  team.Service
+	teamPermissionsService       accesscontrol.TeamPermissionsService
+	serviceAccountsService       serviceaccounts.Service
+	folderPermissionsService     accesscontrol.FolderPermissionsService
+	dashboardPermissionsService  accesscontrol.DashboardPermissionsService
+	provenanceService            provenance.Service
+	libraryElementService        libraryelements.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -181,6 +224,18 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 		ps.searchService.TriggerReIndex()
 	}
 
+	// library elements are provisioned after the dashboards, as they can be stored in provisioned folders
+	if err := ps.ProvisionLibraryElements(ctx); err != nil {
+		ps.log.Error("Failed to provision library elements", "error", err)
+		return err
+	}
+
+	// access is provisioned after the dashboards, as folder and dashboard permissions can reference provisioned ones
+	if err := ps.ProvisionAccess(ctx); err != nil {
+		ps.log.Error("Failed to provision access", "error", err)
+		return err
+	}
+
 	for {
 		// Wait for unlock. This is tied to new dashboardProvisioner to be instantiated before we start polling.
 		ps.mutex.Lock()
@@ -294,6 +349,43 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	return ps.provisionAlerting(ctx, cfg)
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAccess(ctx context.Context) error {
+	accessPath := filepath.Join(ps.Cfg.ProvisioningPath, "access")
+	cfg := access.ProvisionerConfig{
+		Path:                        accessPath,
+		OrgService:                  ps.orgService,
+		UserService:                 ps.userService,
+		TeamService:                 ps.teamService,
+		TeamPermissionsService:      ps.teamPermissionsService,
+		ServiceAccountsService:      ps.serviceAccountsService,
+		FolderPermissionsService:    ps.folderPermissionsService,
+		DashboardPermissionsService: ps.dashboardPermissionsService,
+		ProvenanceService:           ps.provenanceService,
+	}
+	if err := ps.provisionAccess(ctx, cfg); err != nil {
+		err = fmt.Errorf("%v: %w", "Access provisioning error", err)
+		ps.log.Error("Failed to provision access", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionLibraryElements(ctx context.Context) error {
+	libraryElementsPath := filepath.Join(ps.Cfg.ProvisioningPath, "library-elements")
+	cfg := prov_libraryelements.ProvisionerConfig{
+		Path:                  libraryElementsPath,
+		OrgService:            ps.orgService,
+		FolderService:         ps.folderService,
+		LibraryElementService: ps.libraryElementService,
+	}
+	if err := ps.provisionLibraryElements(ctx, cfg); err != nil {
+		err = fmt.Errorf("%v: %w", "Library elements provisioning error", err)
+		ps.log.Error("Failed to provision library elements", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
 	return ps.dashboardProvisioner.GetProvisionerResolvedPath(name)
 }
@@ -9,6 +9,8 @@ type Calls struct {
 	ProvisionNotifications              []interface{}
 	ProvisionDashboards                 []interface{}
 	ProvisionAlerting                   []interface{}
+	ProvisionAccess                     []interface{}
+	ProvisionLibraryElements            []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
 	Run                                 []interface{}
@@ -77,6 +79,16 @@ func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) erro
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionAccess(ctx context.Context) error {
+	mock.Calls.ProvisionAccess = append(mock.Calls.ProvisionAccess, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionLibraryElements(ctx context.Context) error {
+	mock.Calls.ProvisionLibraryElements = append(mock.Calls.ProvisionLibraryElements, nil)
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
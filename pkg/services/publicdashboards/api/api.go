@@ -1,6 +1,9 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -55,9 +58,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
 
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
+	// Public dashboards shared by email also require the session created from the recipient magic link
+	reqEmailSession := RequiresEmailSession(api.PublicDashboardService)
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", reqEmailSession, routing.Wrap(api.ViewPublicDashboard))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", reqEmailSession, routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", reqEmailSession, routing.Wrap(api.GetAnnotations))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.AccessControl)
@@ -85,6 +90,21 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// List recipients of a public dashboard shared by email
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/recipients",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.ListRecipients))
+
+	// Invite a recipient to a public dashboard shared by email
+	api.RouteRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/recipients",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.AddRecipient))
+
+	// Revoke a recipient access
+	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/recipients/:recipientUid",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.RevokeRecipient))
 }
 
 // ListPublicDashboards Gets list of public dashboards by orgId
@@ -164,11 +184,24 @@ func (api *Api) UpdatePublicDashboard(c *contextmodel.ReqContext) response.Respo
 		return response.Err(ErrInvalidUid.Errorf("UpdatePublicDashboard: invalid Uid %s", uid))
 	}
 
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return response.Err(ErrBadRequest.Errorf("UpdatePublicDashboard: bad request data %v", err))
+	}
+	c.Req.Body = io.NopCloser(bytes.NewReader(body))
+
 	pd := &PublicDashboard{}
 	if err := web.Bind(c.Req, pd); err != nil {
 		return response.Err(ErrBadRequest.Errorf("UpdatePublicDashboard: bad request data %v", err))
 	}
 
+	// a null expiry removes it while leaving it out keeps the saved one
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return response.Err(ErrBadRequest.Errorf("UpdatePublicDashboard: bad request data %v", err))
+	}
+	_, hasExpiresAt := fields["expiresAt"]
+
 	// Always set the orgID and userID from the session
 	pd.OrgId = c.OrgID
 	pd.Uid = uid
@@ -177,10 +210,11 @@ func (api *Api) UpdatePublicDashboard(c *contextmodel.ReqContext) response.Respo
 		OrgId:           c.OrgID,
 		DashboardUid:    dashboardUid,
 		PublicDashboard: pd,
+		ClearExpiresAt:  hasExpiresAt && pd.ExpiresAt == nil,
 	}
 
 	// Update the public dashboard
-	pd, err := api.PublicDashboardService.Update(c.Req.Context(), c.SignedInUser, &dto)
+	pd, err = api.PublicDashboardService.Update(c.Req.Context(), c.SignedInUser, &dto)
 	if err != nil {
 		return response.Err(err)
 	}
@@ -204,6 +238,79 @@ func (api *Api) DeletePublicDashboard(c *contextmodel.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, nil)
 }
 
+// ListRecipients Gets the recipients of a public dashboard shared by email
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/recipients
+func (api *Api) ListRecipients(c *contextmodel.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "ListRecipients")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	recipients, err := api.PublicDashboardService.FindRecipients(c.Req.Context(), c.OrgID, dashboardUid, uid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, recipients)
+}
+
+// AddRecipient Invites a recipient to a public dashboard shared by email
+// POST /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/recipients
+func (api *Api) AddRecipient(c *contextmodel.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "AddRecipient")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	dto := &AddRecipientDTO{}
+	if err := web.Bind(c.Req, dto); err != nil {
+		return response.Err(ErrBadRequest.Errorf("AddRecipient: bad request data %v", err))
+	}
+
+	recipient, err := api.PublicDashboardService.AddRecipient(c.Req.Context(), c.SignedInUser, dashboardUid, uid, dto)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, recipient)
+}
+
+// RevokeRecipient Removes a recipient access to a public dashboard shared by email
+// DELETE /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/recipients/:recipientUid
+func (api *Api) RevokeRecipient(c *contextmodel.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "RevokeRecipient")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	recipientUid := web.Params(c.Req)[":recipientUid"]
+	if !validation.IsValidShortUID(recipientUid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokeRecipient: invalid recipient Uid %s", recipientUid))
+	}
+
+	err = api.PublicDashboardService.RevokeRecipient(c.Req.Context(), c.OrgID, dashboardUid, uid, recipientUid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, nil)
+}
+
+// publicDashboardParams Gets the dashboard and public dashboard uids from the route
+func publicDashboardParams(c *contextmodel.ReqContext, handler string) (string, string, error) {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return "", "", ErrInvalidUid.Errorf("%s: invalid dashboard Uid %s", handler, dashboardUid)
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return "", "", ErrInvalidUid.Errorf("%s: invalid Uid %s", handler, uid)
+	}
+
+	return dashboardUid, uid, nil
+}
+
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
@@ -641,3 +641,49 @@ func TestAPIUpdatePublicDashboard(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIUpdatePublicDashboardExpiry(t *testing.T) {
+	adminUser := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleEditor, Login: "testEditorUser", Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsPublicWrite: {dashboards.ScopeDashboardsAll}}}}
+
+	testCases := []struct {
+		Name                   string
+		Body                   string
+		ExpectedClearExpiresAt bool
+	}{
+		{
+			Name:                   "Leaving out the expiry keeps it",
+			Body:                   `{"isEnabled": true}`,
+			ExpectedClearExpiresAt: false,
+		},
+		{
+			Name:                   "A null expiry removes it",
+			Body:                   `{"isEnabled": true, "expiresAt": null}`,
+			ExpectedClearExpiresAt: true,
+		},
+		{
+			Name:                   "A new expiry replaces it",
+			Body:                   `{"isEnabled": true, "expiresAt": "2100-01-01T00:00:00Z"}`,
+			ExpectedClearExpiresAt: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+
+			var dto *SavePublicDashboardDTO
+			service.On("Update", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				dto = args.Get(2).(*SavePublicDashboardDTO)
+			}).Return(&PublicDashboard{Uid: "success"}, nil)
+
+			cfg := setting.NewCfg()
+			features := featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards)
+			testServer := setupTestServer(t, cfg, features, service, nil, adminUser)
+			response := callAPI(testServer, http.MethodPut, "/api/dashboards/uid/abc1234/public-dashboards/1234asdfasdf", strings.NewReader(test.Body), t)
+			require.Equal(t, http.StatusOK, response.Code)
+
+			require.NotNil(t, dto)
+			assert.Equal(t, test.ExpectedClearExpiresAt, dto.ClearExpiresAt)
+		})
+	}
+}
@@ -2,8 +2,11 @@ package api
 
 import (
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
@@ -67,3 +70,55 @@ func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
 	}
 }
+
+// EmailSessionCookieName Every public dashboard shared by email gets its own session cookie
+func EmailSessionCookieName(accessToken string) string {
+	return "grafana_public_dashboard_session_" + accessToken
+}
+
+// SetEmailSessionFromMagicLink Exchanges the magic link token of an email invitation for a session cookie and
+// redirects to the public dashboard without the token in the url
+func SetEmailSessionFromMagicLink(publicDashboardService publicdashboards.Service) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		magicLinkToken := c.Query("magicLinkToken")
+		if magicLinkToken == "" {
+			return
+		}
+
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !validation.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		session, err := publicDashboardService.NewEmailSession(c.Req.Context(), accessToken, magicLinkToken)
+		if err != nil {
+			c.Logger.Warn("Failed to create public dashboard session from magic link", "error", err)
+			return
+		}
+
+		maxAge := int(time.Until(session.ExpiresAt).Seconds())
+		cookies.WriteCookie(c.Resp, EmailSessionCookieName(accessToken), url.QueryEscape(session.Token), maxAge, nil)
+
+		query := c.Req.URL.Query()
+		query.Del("magicLinkToken")
+		redirect := *c.Req.URL
+		redirect.RawQuery = query.Encode()
+		c.Redirect(redirect.RequestURI())
+	}
+}
+
+// RequiresEmailSession Middleware to enforce that recipients of a public dashboard shared by email have a valid session
+func RequiresEmailSession(publicDashboardService publicdashboards.Service) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		accessToken, ok := web.Params(c.Req)[":accessToken"]
+		if !ok || !validation.IsValidAccessToken(accessToken) {
+			// handlers respond with the access token error
+			return
+		}
+
+		sessionToken := c.GetCookie(EmailSessionCookieName(accessToken))
+		if err := publicDashboardService.ValidateEmailSession(c.Req.Context(), accessToken, sessionToken); err != nil {
+			c.WriteErr(err)
+		}
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
@@ -144,6 +147,69 @@ func TestSetPublicDashboardOrgIdOnContext(t *testing.T) {
 	}
 }
 
+func TestSetEmailSessionFromMagicLink(t *testing.T) {
+	t.Run("Exchanges the magic link for a session cookie and redirects without it", func(t *testing.T) {
+		publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+		publicdashboardService.On("NewEmailSession", mock.Anything, validAccessToken, "magic").
+			Return(&EmailSessionDTO{Token: "session", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		params := map[string]string{":accessToken": validAccessToken}
+		path := fmt.Sprintf("/public-dashboards/%s?magicLinkToken=magic&orgId=1", validAccessToken)
+
+		_, resp := runMw(t, nil, "GET", path, params, SetEmailSessionFromMagicLink(publicdashboardService))
+		require.Equal(t, http.StatusFound, resp.Code)
+		assert.Equal(t, fmt.Sprintf("/public-dashboards/%s?orgId=1", validAccessToken), resp.Header().Get("Location"))
+		assert.Contains(t, resp.Header().Get("Set-Cookie"), EmailSessionCookieName(validAccessToken)+"=session")
+	})
+
+	t.Run("Does nothing without a magic link", func(t *testing.T) {
+		publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+		params := map[string]string{":accessToken": validAccessToken}
+
+		_, resp := runMw(t, nil, "GET", "/public-dashboards/"+validAccessToken, params, SetEmailSessionFromMagicLink(publicdashboardService))
+		require.Equal(t, http.StatusOK, resp.Code)
+		publicdashboardService.AssertNotCalled(t, "NewEmailSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRequiresEmailSession(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		ValidateErr          error
+		ExpectedResponseCode int
+	}{
+		{
+			Name:                 "Returns 200 when the session is valid",
+			ValidateErr:          nil,
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 401 when there is no valid session",
+			ValidateErr:          ErrEmailSessionRequired.Errorf("no session"),
+			ExpectedResponseCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("ValidateEmailSession", mock.Anything, validAccessToken, "session").Return(tt.ValidateErr)
+			params := map[string]string{":accessToken": validAccessToken}
+
+			ctx := &contextmodel.ReqContext{Context: &web.Context{}, Logger: log.New("test")}
+			request, err := http.NewRequest("GET", "/api/public/dashboards/"+validAccessToken, nil)
+			require.NoError(t, err)
+			request.AddCookie(&http.Cookie{Name: EmailSessionCookieName(validAccessToken), Value: "session"})
+			request = web.SetURLParams(request, params)
+			ctx.Req = request
+			response := httptest.NewRecorder()
+			ctx.Context.Resp = web.NewResponseWriter("GET", response)
+
+			RequiresEmailSession(publicdashboardService)(ctx)
+			require.Equal(t, tt.ExpectedResponseCode, response.Code)
+		})
+	}
+}
+
 func TestSetPublicDashboardFlag(t *testing.T) {
 	t.Run("Adds context.IsPublicDashboardView=true to request", func(t *testing.T) {
 		ctx := &contextmodel.ReqContext{}
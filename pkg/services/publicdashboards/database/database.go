@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -177,9 +178,9 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true AND (expires_at IS NULL OR expires_at > ?)"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessToken, time.Now().UTC().Format("2006-01-02 15:04:05")).Count()
 		if err != nil {
 			return err
 		}
@@ -233,14 +234,26 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		templateVariablesJSON, err := json.Marshal(cmd.PublicDashboard.TemplateVariables)
+		if err != nil {
+			return err
+		}
+
+		var expiresAt interface{}
+		if cmd.PublicDashboard.ExpiresAt != nil {
+			expiresAt = cmd.PublicDashboard.ExpiresAt.UTC().Format("2006-01-02 15:04:05")
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, template_variables = ?, updated_by = ?, updated_at = ?, expires_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			string(templateVariablesJSON),
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
+			expiresAt,
 			cmd.PublicDashboard.Uid)
 
 		if err != nil {
@@ -255,20 +268,164 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 	return affectedRows, err
 }
 
-// Deletes a public dashboard
+// Deletes a public dashboard along with its email shares and sessions
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int64, error) {
 	dashboard := &PublicDashboard{Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil {
+			return err
+		}
+
+		if _, err = sess.Delete(&EmailSession{PublicDashboardUid: uid}); err != nil {
+			return err
+		}
+		_, err = sess.Delete(&EmailShare{PublicDashboardUid: uid})
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// FindEmailShares Returns the recipients of a public dashboard shared by email
+func (d *PublicDashboardStoreImpl) FindEmailShares(ctx context.Context, publicDashboardUid string) ([]EmailShare, error) {
+	shares := make([]EmailShare, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ?", publicDashboardUid).OrderBy("recipient ASC").Find(&shares)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// FindEmailShareByMagicLinkToken Returns the email share matching a hashed magic link token or nil if not found
+func (d *PublicDashboardStoreImpl) FindEmailShareByMagicLinkToken(ctx context.Context, publicDashboardUid string, magicLinkToken string) (*EmailShare, error) {
+	return d.findEmailShare(ctx, &EmailShare{PublicDashboardUid: publicDashboardUid, MagicLinkToken: magicLinkToken})
+}
+
+// FindEmailShare Returns an email share by uid or nil if not found
+func (d *PublicDashboardStoreImpl) FindEmailShare(ctx context.Context, publicDashboardUid string, uid string) (*EmailShare, error) {
+	return d.findEmailShare(ctx, &EmailShare{PublicDashboardUid: publicDashboardUid, Uid: uid})
+}
+
+func (d *PublicDashboardStoreImpl) findEmailShare(ctx context.Context, share *EmailShare) (*EmailShare, error) {
+	if share.PublicDashboardUid == "" {
+		return nil, nil
+	}
+
+	var found bool
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(share)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return share, nil
+}
+
+// CreateEmailShare Adds a recipient to a public dashboard
+func (d *PublicDashboardStoreImpl) CreateEmailShare(ctx context.Context, cmd CreateEmailShareCommand) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Insert(&cmd.EmailShare)
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// DeleteEmailShare Removes a recipient and signs out all of its sessions
+func (d *PublicDashboardStoreImpl) DeleteEmailShare(ctx context.Context, publicDashboardUid string, uid string) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Delete(&EmailShare{PublicDashboardUid: publicDashboardUid, Uid: uid})
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Delete(&EmailSession{PublicDashboardUid: publicDashboardUid, EmailShareUid: uid})
 		return err
 	})
 
 	return affectedRows, err
 }
 
+// UpdateEmailShareLastViewedAt Records when a recipient last viewed the public dashboard
+func (d *PublicDashboardStoreImpl) UpdateEmailShareLastViewedAt(ctx context.Context, uid string, lastViewedAt time.Time) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_public_email_share SET last_viewed_at = ? WHERE uid = ?",
+			lastViewedAt.UTC().Format("2006-01-02 15:04:05"), uid)
+		return err
+	})
+}
+
+// UseEmailShareMagicLink Marks the magic link of a recipient as used, returns false if it was already used
+func (d *PublicDashboardStoreImpl) UseEmailShareMagicLink(ctx context.Context, uid string, usedAt time.Time) (bool, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE dashboard_public_email_share SET magic_link_used_at = ? WHERE uid = ? AND magic_link_used_at IS NULL",
+			usedAt.UTC().Format("2006-01-02 15:04:05"), uid)
+		if err != nil {
+			return err
+		}
+		affectedRows, err = res.RowsAffected()
+		return err
+	})
+
+	return affectedRows == 1, err
+}
+
+// CreateEmailSession Stores a session for a recipient
+func (d *PublicDashboardStoreImpl) CreateEmailSession(ctx context.Context, cmd CreateEmailSessionCommand) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Insert(&cmd.EmailSession)
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// FindEmailSession Returns a session by hashed token or nil if not found
+func (d *PublicDashboardStoreImpl) FindEmailSession(ctx context.Context, publicDashboardUid string, token string) (*EmailSession, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var found bool
+	session := &EmailSession{PublicDashboardUid: publicDashboardUid, Token: token}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(session)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return session, nil
+}
+
 func (d *PublicDashboardStoreImpl) FindByDashboardFolder(ctx context.Context, dashboard *dashboards.Dashboard) ([]*PublicDashboard, error) {
 	if dashboard == nil || !dashboard.IsFolder {
 		return nil, nil
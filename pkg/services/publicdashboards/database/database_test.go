@@ -169,6 +169,29 @@ func TestIntegrationExistsEnabledByAccessToken(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, res)
 	})
+
+	t.Run("ExistsEnabledByAccessToken takes the expiration of the public dashboard into account", func(t *testing.T) {
+		setup()
+
+		for name, tc := range map[string]struct {
+			expiresAt time.Time
+			exists    bool
+		}{
+			"expired": {expiresAt: time.Now().Add(-time.Hour), exists: false},
+			"active":  {expiresAt: time.Now().Add(time.Hour), exists: true},
+		} {
+			expiresAt := tc.expiresAt
+			pubdash := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+			pubdash.ExpiresAt = &expiresAt
+			pubdash.UpdatedAt = time.Now()
+			_, err := publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *pubdash})
+			require.NoError(t, err)
+
+			res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), pubdash.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exists, res, name)
+		}
+	})
 }
 
 func TestIntegrationExistsEnabledByDashboardUid(t *testing.T) {
@@ -275,13 +298,14 @@ func TestIntegrationFindByDashboardUid(t *testing.T) {
 		setup()
 		cmd := SavePublicDashboardCommand{
 			PublicDashboard: PublicDashboard{
-				IsEnabled:    true,
-				Uid:          "pubdash-uid",
-				DashboardUid: savedDashboard.UID,
-				OrgId:        savedDashboard.OrgID,
-				TimeSettings: DefaultTimeSettings,
-				CreatedAt:    DefaultTime,
-				CreatedBy:    7,
+				IsEnabled:         true,
+				Uid:               "pubdash-uid",
+				DashboardUid:      savedDashboard.UID,
+				OrgId:             savedDashboard.OrgID,
+				TimeSettings:      DefaultTimeSettings,
+				TemplateVariables: &TemplateVariableSettings{},
+				CreatedAt:         DefaultTime,
+				CreatedBy:         7,
 			},
 		}
 
@@ -342,14 +366,15 @@ func TestIntegrationFindByAccessToken(t *testing.T) {
 		setup()
 		cmd := SavePublicDashboardCommand{
 			PublicDashboard: PublicDashboard{
-				IsEnabled:    true,
-				Uid:          "pubdash-uid",
-				DashboardUid: savedDashboard.UID,
-				OrgId:        savedDashboard.OrgID,
-				TimeSettings: DefaultTimeSettings,
-				CreatedAt:    DefaultTime,
-				CreatedBy:    7,
-				AccessToken:  "thisisavalidaccesstoken",
+				IsEnabled:         true,
+				Uid:               "pubdash-uid",
+				DashboardUid:      savedDashboard.UID,
+				OrgId:             savedDashboard.OrgID,
+				TimeSettings:      DefaultTimeSettings,
+				TemplateVariables: &TemplateVariableSettings{},
+				CreatedAt:         DefaultTime,
+				CreatedBy:         7,
+				AccessToken:       "thisisavalidaccesstoken",
 			},
 		}
 
@@ -827,3 +852,166 @@ func insertPublicDashboard(t *testing.T, publicdashboardStore *PublicDashboardSt
 
 	return pubdash
 }
+
+func TestIntegrationEmailShares(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var pubdash *PublicDashboard
+
+	setup := func() {
+		sqlStore, cfg := db.InitTestDBwithCfg(t)
+		quotaService := quotatest.New(false, nil)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotaService)
+		require.NoError(t, err)
+		publicdashboardStore = ProvideStore(sqlStore)
+		savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+		pubdash = insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, EmailShareType)
+	}
+
+	insertEmailShare := func(t *testing.T, uid string, recipient string) {
+		t.Helper()
+		affectedRows, err := publicdashboardStore.CreateEmailShare(context.Background(), CreateEmailShareCommand{
+			EmailShare: EmailShare{
+				Uid:                uid,
+				PublicDashboardUid: pubdash.Uid,
+				Recipient:          recipient,
+				MagicLinkToken:     "magic-" + uid,
+				CreatedAt:          DefaultTime,
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, affectedRows)
+	}
+
+	insertEmailSession := func(t *testing.T, shareUid string, token string) {
+		t.Helper()
+		affectedRows, err := publicdashboardStore.CreateEmailSession(context.Background(), CreateEmailSessionCommand{
+			EmailSession: EmailSession{
+				Token:              token,
+				EmailShareUid:      shareUid,
+				PublicDashboardUid: pubdash.Uid,
+				CreatedAt:          DefaultTime,
+				ExpiresAt:          DefaultTime.Add(EmailSessionDuration),
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, affectedRows)
+	}
+
+	t.Run("FindEmailShares returns the recipients of a public dashboard ordered by recipient", func(t *testing.T) {
+		setup()
+		insertEmailShare(t, "b", "b@example.com")
+		insertEmailShare(t, "a", "a@example.com")
+
+		shares, err := publicdashboardStore.FindEmailShares(context.Background(), pubdash.Uid)
+		require.NoError(t, err)
+		require.Len(t, shares, 2)
+		assert.Equal(t, "a@example.com", shares[0].Recipient)
+		assert.Equal(t, "b@example.com", shares[1].Recipient)
+
+		shares, err = publicdashboardStore.FindEmailShares(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, shares)
+	})
+
+	t.Run("FindEmailShare and FindEmailShareByMagicLinkToken are scoped to the public dashboard", func(t *testing.T) {
+		setup()
+		insertEmailShare(t, "a", "a@example.com")
+
+		share, err := publicdashboardStore.FindEmailShare(context.Background(), pubdash.Uid, "a")
+		require.NoError(t, err)
+		require.NotNil(t, share)
+		assert.Equal(t, "a@example.com", share.Recipient)
+		assert.Equal(t, DefaultTime, share.CreatedAt.UTC())
+
+		share, err = publicdashboardStore.FindEmailShareByMagicLinkToken(context.Background(), pubdash.Uid, "magic-a")
+		require.NoError(t, err)
+		require.NotNil(t, share)
+		assert.Equal(t, "a", share.Uid)
+
+		share, err = publicdashboardStore.FindEmailShare(context.Background(), "other", "a")
+		require.NoError(t, err)
+		assert.Nil(t, share)
+
+		share, err = publicdashboardStore.FindEmailShareByMagicLinkToken(context.Background(), "", "magic-a")
+		require.NoError(t, err)
+		assert.Nil(t, share)
+	})
+
+	t.Run("UpdateEmailShareLastViewedAt stores the last viewed time", func(t *testing.T) {
+		setup()
+		insertEmailShare(t, "a", "a@example.com")
+
+		err := publicdashboardStore.UpdateEmailShareLastViewedAt(context.Background(), "a", DefaultTime)
+		require.NoError(t, err)
+
+		share, err := publicdashboardStore.FindEmailShare(context.Background(), pubdash.Uid, "a")
+		require.NoError(t, err)
+		require.NotNil(t, share.LastViewedAt)
+		assert.Equal(t, DefaultTime, share.LastViewedAt.UTC())
+	})
+
+	t.Run("UseEmailShareMagicLink only uses a magic link once", func(t *testing.T) {
+		setup()
+		insertEmailShare(t, "a", "a@example.com")
+
+		used, err := publicdashboardStore.UseEmailShareMagicLink(context.Background(), "a", DefaultTime)
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		used, err = publicdashboardStore.UseEmailShareMagicLink(context.Background(), "a", DefaultTime.Add(time.Minute))
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		share, err := publicdashboardStore.FindEmailShare(context.Background(), pubdash.Uid, "a")
+		require.NoError(t, err)
+		require.NotNil(t, share.MagicLinkUsedAt)
+		assert.Equal(t, DefaultTime, share.MagicLinkUsedAt.UTC())
+	})
+
+	t.Run("FindEmailSession returns sessions by token", func(t *testing.T) {
+		setup()
+		insertEmailShare(t, "a", "a@example.com")
+		insertEmailSession(t, "a", "token")
+
+		session, err := publicdashboardStore.FindEmailSession(context.Background(), pubdash.Uid, "token")
+		require.NoError(t, err)
+		require.NotNil(t, session)
+		assert.Equal(t, "a", session.EmailShareUid)
+		assert.Equal(t, DefaultTime.Add(EmailSessionDuration), session.ExpiresAt.UTC())
+
+		session, err = publicdashboardStore.FindEmailSession(context.Background(), pubdash.Uid, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, session)
+
+		session, err = publicdashboardStore.FindEmailSession(context.Background(), pubdash.Uid, "")
+		require.NoError(t, err)
+		assert.Nil(t, session)
+	})
+
+	t.Run("DeleteEmailShare removes the recipient and its sessions", func(t *testing.T) {
+		setup()
+		insertEmailShare(t, "a", "a@example.com")
+		insertEmailShare(t, "b", "b@example.com")
+		insertEmailSession(t, "a", "token-a")
+		insertEmailSession(t, "b", "token-b")
+
+		affectedRows, err := publicdashboardStore.DeleteEmailShare(context.Background(), pubdash.Uid, "a")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affectedRows)
+
+		share, err := publicdashboardStore.FindEmailShare(context.Background(), pubdash.Uid, "a")
+		require.NoError(t, err)
+		assert.Nil(t, share)
+
+		session, err := publicdashboardStore.FindEmailSession(context.Background(), pubdash.Uid, "token-a")
+		require.NoError(t, err)
+		assert.Nil(t, session)
+
+		session, err = publicdashboardStore.FindEmailSession(context.Background(), pubdash.Uid, "token-b")
+		require.NoError(t, err)
+		assert.NotNil(t, session)
+	})
+}
@@ -6,6 +6,7 @@ var (
 	ErrInternalServerError = errutil.NewBase(errutil.StatusInternal, "publicdashboards.internalServerError", errutil.WithPublicMessage("Internal server error"))
 
 	ErrPublicDashboardNotFound = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.notFound", errutil.WithPublicMessage("Public dashboard not found"))
+	ErrRecipientNotFound       = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.recipientNotFound", errutil.WithPublicMessage("Recipient not found"))
 	ErrDashboardNotFound       = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.dashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
 	ErrPanelNotFound           = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.panelNotFound", errutil.WithPublicMessage("Public dashboard panel not found"))
 
@@ -19,8 +20,18 @@ var (
 	ErrInvalidInterval                     = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidInterval", errutil.WithPublicMessage("intervalMS should be greater than 0"))
 	ErrInvalidMaxDataPoints                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
+	ErrInvalidTemplateVariables            = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTemplateVariables", errutil.WithPublicMessage("Invalid template variable settings"))
+	ErrInvalidTemplateVariableValue        = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTemplateVariableValue", errutil.WithPublicMessage("Invalid template variable value"))
 	ErrInvalidShareType                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
 	ErrDashboardIsPublic                   = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
+	ErrInvalidExpiry                       = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidExpiry", errutil.WithPublicMessage("Expiry date must be in the future"))
+	ErrInvalidRecipient                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidRecipient", errutil.WithPublicMessage("Invalid recipient email address"))
+	ErrRecipientAlreadyInvited             = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.recipientAlreadyInvited", errutil.WithPublicMessage("Recipient was already invited"))
+	ErrNotSharedByEmail                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.notSharedByEmail", errutil.WithPublicMessage("Public dashboard is not shared by email"))
+
+	ErrInvalidMagicLink     = errutil.NewBase(errutil.StatusUnauthorized, "publicdashboards.invalidMagicLink", errutil.WithPublicMessage("Invalid or expired link"))
+	ErrEmailSessionRequired = errutil.NewBase(errutil.StatusUnauthorized, "publicdashboards.emailSessionRequired", errutil.WithPublicMessage("Public dashboard requires an email invitation"))
 
 	ErrPublicDashboardNotEnabled = errutil.NewBase(errutil.StatusForbidden, "publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardExpired    = errutil.NewBase(errutil.StatusForbidden, "publicdashboards.expired", errutil.WithPublicMessage("Public dashboard expired"))
 )
@@ -40,20 +40,22 @@ var (
 type ShareType string
 
 type PublicDashboard struct {
-	Uid                  string        `json:"uid" xorm:"pk uid"`
-	DashboardUid         string        `json:"dashboardUid" xorm:"dashboard_uid"`
-	OrgId                int64         `json:"-" xorm:"org_id"` // Don't ever marshal orgId to Json
-	TimeSettings         *TimeSettings `json:"timeSettings" xorm:"time_settings"`
-	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
-	AccessToken          string        `json:"accessToken" xorm:"access_token"`
-	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
-	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
-	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
-	CreatedBy            int64         `json:"createdBy" xorm:"created_by"`
-	UpdatedBy            int64         `json:"updatedBy" xorm:"updated_by"`
-	CreatedAt            time.Time     `json:"createdAt" xorm:"created_at"`
-	UpdatedAt            time.Time     `json:"updatedAt" xorm:"updated_at"`
+	Uid                  string                    `json:"uid" xorm:"pk uid"`
+	DashboardUid         string                    `json:"dashboardUid" xorm:"dashboard_uid"`
+	OrgId                int64                     `json:"-" xorm:"org_id"` // Don't ever marshal orgId to Json
+	TimeSettings         *TimeSettings             `json:"timeSettings" xorm:"time_settings"`
+	TemplateVariables    *TemplateVariableSettings `json:"templateVariables" xorm:"template_variables"`
+	IsEnabled            bool                      `json:"isEnabled" xorm:"is_enabled"`
+	AccessToken          string                    `json:"accessToken" xorm:"access_token"`
+	AnnotationsEnabled   bool                      `json:"annotationsEnabled" xorm:"annotations_enabled"`
+	TimeSelectionEnabled bool                      `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
+	Share                ShareType                 `json:"share" xorm:"share"`
+	Recipients           []EmailDTO                `json:"recipients,omitempty" xorm:"-"`
+	CreatedBy            int64                     `json:"createdBy" xorm:"created_by"`
+	UpdatedBy            int64                     `json:"updatedBy" xorm:"updated_by"`
+	CreatedAt            time.Time                 `json:"createdAt" xorm:"created_at"`
+	UpdatedAt            time.Time                 `json:"updatedAt" xorm:"updated_at"`
+	ExpiresAt            *time.Time                `json:"expiresAt,omitempty" xorm:"expires_at"`
 }
 
 type EmailDTO struct {
@@ -61,6 +63,50 @@ type EmailDTO struct {
 	Recipient string `json:"recipient"`
 }
 
+// EmailSessionDuration is how long a recipient stays signed in after following a magic link
+const EmailSessionDuration = 30 * 24 * time.Hour
+
+// EmailShare is a recipient invited to view a public dashboard shared by email
+type EmailShare struct {
+	Id                 int64      `json:"-" xorm:"pk autoincr 'id'"`
+	Uid                string     `json:"uid" xorm:"uid"`
+	PublicDashboardUid string     `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	Recipient          string     `json:"recipient" xorm:"recipient"`
+	MagicLinkToken     string     `json:"-" xorm:"magic_link_token"` // sha256 of the token sent by email
+	CreatedAt          time.Time  `json:"createdAt" xorm:"created_at"`
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+	LastViewedAt       *time.Time `json:"lastViewedAt,omitempty" xorm:"last_viewed_at"`
+	MagicLinkUsedAt    *time.Time `json:"magicLinkUsedAt,omitempty" xorm:"magic_link_used_at"`
+}
+
+func (es EmailShare) TableName() string {
+	return "dashboard_public_email_share"
+}
+
+// EmailSession is created when a recipient follows a magic link and is revoked with the email share
+type EmailSession struct {
+	Id                 int64     `xorm:"pk autoincr 'id'"`
+	Token              string    `xorm:"token"` // sha256 of the cookie value
+	EmailShareUid      string    `xorm:"email_share_uid"`
+	PublicDashboardUid string    `xorm:"public_dashboard_uid"`
+	CreatedAt          time.Time `xorm:"created_at"`
+	ExpiresAt          time.Time `xorm:"expires_at"`
+}
+
+func (es EmailSession) TableName() string {
+	return "dashboard_public_session"
+}
+
+// IsExpired returns true once the public dashboard is past its expiry date
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !now.Before(*pd.ExpiresAt)
+}
+
+// IsExpired returns true once the recipient access is past its expiry date
+func (es EmailShare) IsExpired(now time.Time) bool {
+	return es.ExpiresAt != nil && !now.Before(*es.ExpiresAt)
+}
+
 // Alias the generated type
 type DashAnnotation = dashboard.AnnotationQuery
 
@@ -133,6 +179,8 @@ type SavePublicDashboardDTO struct {
 	OrgId           int64
 	UserId          int64
 	PublicDashboard *PublicDashboard
+	// ClearExpiresAt is set when an update removes the expiry, a nil ExpiresAt keeps the saved one
+	ClearExpiresAt bool
 }
 
 type PublicDashboardQueryDTO struct {
@@ -140,6 +188,20 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeSettings
+	// Values selected by the viewer, keyed by variable name. Only allowed variables can be set.
+	Variables map[string][]string
+}
+
+// DTO for inviting a recipient to an email shared public dashboard
+type AddRecipientDTO struct {
+	Recipient string     `json:"recipient"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// EmailSessionDTO is the session handed to a recipient in exchange for a magic link token
+type EmailSessionDTO struct {
+	Token     string
+	ExpiresAt time.Time
 }
 
 type AnnotationsQueryDTO struct {
@@ -154,3 +216,11 @@ type AnnotationsQueryDTO struct {
 type SavePublicDashboardCommand struct {
 	PublicDashboard PublicDashboard
 }
+
+type CreateEmailShareCommand struct {
+	EmailShare EmailShare
+}
+
+type CreateEmailSessionCommand struct {
+	EmailSession EmailSession
+}
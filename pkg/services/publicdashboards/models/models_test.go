@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPublicDashboardTableName(t *testing.T) {
@@ -72,3 +73,29 @@ func TestBuildTimeSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyTemplateVariableSettings(t *testing.T) {
+	dashboardData, err := simplejson.NewJson([]byte(`{
+		"templating": {
+			"list": [
+				{"name": "service", "type": "custom", "options": [{"value": "api"}, {"value": "web"}, {"value": "db"}]},
+				{"name": "env", "type": "custom", "options": [{"value": "prod"}]},
+				{"name": "region", "type": "custom", "options": [{"value": "eu"}]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	pubdash := PublicDashboard{TemplateVariables: &TemplateVariableSettings{Allowed: []AllowedTemplateVariable{
+		{Name: "service", ValuesSource: TemplateVariableValuesSourceList, Values: []string{"api", "web"}},
+		{Name: "env", ValuesSource: TemplateVariableValuesSourceDashboard},
+	}}}
+	pubdash.ApplyTemplateVariableSettings(dashboardData)
+
+	variables := dashboardData.GetPath("templating", "list")
+	assert.Len(t, variables.GetIndex(0).Get("options").MustArray(), 2)
+	_, hidden := variables.GetIndex(0).CheckGet("hide")
+	assert.False(t, hidden)
+	assert.Len(t, variables.GetIndex(1).Get("options").MustArray(), 1)
+	assert.Equal(t, 2, variables.GetIndex(2).Get("hide").MustInt())
+}
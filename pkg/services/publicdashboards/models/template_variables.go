@@ -0,0 +1,169 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// TemplateVariableAllValue is the value of the "All" option of a template variable
+const TemplateVariableAllValue = "$__all"
+
+// templateVariableHidden is the dashboard JSON value used to hide a variable picker
+const templateVariableHidden = 2
+
+type TemplateVariableValuesSource string
+
+const (
+	// TemplateVariableValuesSourceDashboard permits the options saved on the dashboard variable
+	TemplateVariableValuesSourceDashboard TemplateVariableValuesSource = "dashboard"
+	// TemplateVariableValuesSourceList permits only the values listed on the public dashboard
+	TemplateVariableValuesSourceList TemplateVariableValuesSource = "list"
+)
+
+// Variables that would let viewers run arbitrary filters or switch data sources can't be allowed
+var unsupportedTemplateVariableTypes = map[string]bool{
+	"adhoc":      true,
+	"datasource": true,
+}
+
+// TemplateVariableSettings lists the template variables viewers of a public dashboard can change. Every other
+// variable keeps the value saved on the dashboard.
+type TemplateVariableSettings struct {
+	Allowed []AllowedTemplateVariable `json:"allowed"`
+}
+
+type AllowedTemplateVariable struct {
+	Name         string                       `json:"name"`
+	ValuesSource TemplateVariableValuesSource `json:"valuesSource"`
+	Values       []string                     `json:"values,omitempty"`
+}
+
+func (tv *TemplateVariableSettings) FromDB(data []byte) error {
+	return json.Unmarshal(data, tv)
+}
+
+func (tv *TemplateVariableSettings) ToDB() ([]byte, error) {
+	return json.Marshal(tv)
+}
+
+// Find returns the allow-list entry of a variable or nil if viewers can't change it
+func (tv *TemplateVariableSettings) Find(name string) *AllowedTemplateVariable {
+	if tv == nil {
+		return nil
+	}
+	for i := range tv.Allowed {
+		if tv.Allowed[i].Name == name {
+			return &tv.Allowed[i]
+		}
+	}
+	return nil
+}
+
+// DashboardTemplateVariable is a template variable as saved in the dashboard JSON
+type DashboardTemplateVariable struct {
+	Name       string
+	Type       string
+	Multi      bool
+	IncludeAll bool
+	AllValue   string
+	Current    []string
+	Options    []string
+}
+
+// IsSupported returns false for variable types viewers can never change
+func (v DashboardTemplateVariable) IsSupported() bool {
+	return !unsupportedTemplateVariableTypes[v.Type]
+}
+
+// PermittedValues returns the values viewers can select for an allowed variable. Query variables that refresh on
+// load don't save their options, so only the saved value is permitted unless values are listed.
+func (v DashboardTemplateVariable) PermittedValues(allowed AllowedTemplateVariable) []string {
+	if allowed.ValuesSource == TemplateVariableValuesSourceList {
+		return allowed.Values
+	}
+
+	values := append(append([]string{}, v.Options...), v.Current...)
+	if v.IncludeAll {
+		values = append(values, TemplateVariableAllValue)
+	}
+	return values
+}
+
+// GetDashboardTemplateVariables reads the template variables of a dashboard
+func GetDashboardTemplateVariables(data *simplejson.Json) []DashboardTemplateVariable {
+	list := data.GetPath("templating", "list").MustArray()
+	variables := make([]DashboardTemplateVariable, 0, len(list))
+	for _, obj := range list {
+		v := simplejson.NewFromAny(obj)
+		variable := DashboardTemplateVariable{
+			Name:       v.Get("name").MustString(),
+			Type:       v.Get("type").MustString(),
+			Multi:      v.Get("multi").MustBool(),
+			IncludeAll: v.Get("includeAll").MustBool(),
+			AllValue:   v.Get("allValue").MustString(),
+			Current:    stringOrStrings(v.GetPath("current", "value")),
+		}
+
+		for _, option := range v.Get("options").MustArray() {
+			value := simplejson.NewFromAny(option).Get("value").MustString()
+			if value != "" && value != TemplateVariableAllValue {
+				variable.Options = append(variable.Options, value)
+			}
+		}
+
+		switch variable.Type {
+		case "constant":
+			variable.Current = []string{v.Get("query").MustString()}
+		case "custom":
+			// options are not always saved for custom variables, but they can be read from the query
+			if len(variable.Options) == 0 {
+				for _, value := range strings.Split(v.Get("query").MustString(), ",") {
+					if value = strings.TrimSpace(value); value != "" {
+						variable.Options = append(variable.Options, value)
+					}
+				}
+			}
+		}
+
+		variables = append(variables, variable)
+	}
+	return variables
+}
+
+// ApplyTemplateVariableSettings hides the pickers of variables viewers can't change and restricts the options of
+// allowed variables to the permitted values
+func (pd PublicDashboard) ApplyTemplateVariableSettings(data *simplejson.Json) {
+	for _, obj := range data.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		allowed := pd.TemplateVariables.Find(v.Get("name").MustString())
+		if allowed == nil || unsupportedTemplateVariableTypes[v.Get("type").MustString()] {
+			v.Set("hide", templateVariableHidden)
+			continue
+		}
+
+		if allowed.ValuesSource != TemplateVariableValuesSourceList {
+			continue
+		}
+
+		permitted := make(map[string]bool, len(allowed.Values))
+		for _, value := range allowed.Values {
+			permitted[value] = true
+		}
+		options := make([]interface{}, 0, len(allowed.Values))
+		for _, option := range v.Get("options").MustArray() {
+			if permitted[simplejson.NewFromAny(option).Get("value").MustString()] {
+				options = append(options, option)
+			}
+		}
+		v.Set("options", options)
+	}
+}
+
+func stringOrStrings(value *simplejson.Json) []string {
+	if s, err := value.String(); err == nil {
+		return []string{s}
+	}
+	return value.MustStringArray()
+}
@@ -23,6 +23,29 @@ type FakePublicDashboardService struct {
 	mock.Mock
 }
 
+// AddRecipient provides a mock function with given fields: ctx, u, dashboardUid, uid, dto
+func (_m *FakePublicDashboardService) AddRecipient(ctx context.Context, u *user.SignedInUser, dashboardUid string, uid string, dto *models.AddRecipientDTO) (*models.EmailShare, error) {
+	ret := _m.Called(ctx, u, dashboardUid, uid, dto)
+
+	var r0 *models.EmailShare
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, string, string, *models.AddRecipientDTO) *models.EmailShare); ok {
+		r0 = rf(ctx, u, dashboardUid, uid, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, string, string, *models.AddRecipientDTO) error); ok {
+		r1 = rf(ctx, u, dashboardUid, uid, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -318,6 +341,29 @@ func (_m *FakePublicDashboardService) FindPublicDashboardAndDashboardByAccessTok
 	return r0, r1, r2
 }
 
+// FindRecipients provides a mock function with given fields: ctx, orgId, dashboardUid, uid
+func (_m *FakePublicDashboardService) FindRecipients(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]models.EmailShare, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid)
+
+	var r0 []models.EmailShare
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []models.EmailShare); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.EmailShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetricRequest provides a mock function with given fields: ctx, dashboard, publicDashboard, panelId, reqDTO
 func (_m *FakePublicDashboardService) GetMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	ret := _m.Called(ctx, dashboard, publicDashboard, panelId, reqDTO)
@@ -360,13 +406,13 @@ func (_m *FakePublicDashboardService) GetOrgIdByAccessToken(ctx context.Context,
 	return r0, r1
 }
 
-// GetQueryDataResponse provides a mock function with given fields: ctx, skipCache, reqDTO, panelId, accessToken
-func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context, skipCache bool, reqDTO models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error) {
-	ret := _m.Called(ctx, skipCache, reqDTO, panelId, accessToken)
+// GetQueryDataResponse provides a mock function with given fields: ctx, skipDSCache, reqDTO, panelId, accessToken
+func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context, skipDSCache bool, reqDTO models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error) {
+	ret := _m.Called(ctx, skipDSCache, reqDTO, panelId, accessToken)
 
 	var r0 *backend.QueryDataResponse
 	if rf, ok := ret.Get(0).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) *backend.QueryDataResponse); ok {
-		r0 = rf(ctx, skipCache, reqDTO, panelId, accessToken)
+		r0 = rf(ctx, skipDSCache, reqDTO, panelId, accessToken)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*backend.QueryDataResponse)
@@ -375,7 +421,30 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, bool, models.PublicDashboardQueryDTO, int64, string) error); ok {
-		r1 = rf(ctx, skipCache, reqDTO, panelId, accessToken)
+		r1 = rf(ctx, skipDSCache, reqDTO, panelId, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailSession provides a mock function with given fields: ctx, accessToken, magicLinkToken
+func (_m *FakePublicDashboardService) NewEmailSession(ctx context.Context, accessToken string, magicLinkToken string) (*models.EmailSessionDTO, error) {
+	ret := _m.Called(ctx, accessToken, magicLinkToken)
+
+	var r0 *models.EmailSessionDTO
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.EmailSessionDTO); ok {
+		r0 = rf(ctx, accessToken, magicLinkToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailSessionDTO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accessToken, magicLinkToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -425,6 +494,20 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
+// RevokeRecipient provides a mock function with given fields: ctx, orgId, dashboardUid, uid, recipientUid
+func (_m *FakePublicDashboardService) RevokeRecipient(ctx context.Context, orgId int64, dashboardUid string, uid string, recipientUid string) error {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid, recipientUid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) error); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid, recipientUid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -448,6 +531,20 @@ func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// ValidateEmailSession provides a mock function with given fields: ctx, accessToken, sessionToken
+func (_m *FakePublicDashboardService) ValidateEmailSession(ctx context.Context, accessToken string, sessionToken string) error {
+	ret := _m.Called(ctx, accessToken, sessionToken)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, accessToken, sessionToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewFakePublicDashboardService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -6,8 +6,11 @@ import (
 	context "context"
 
 	dashboards "github.com/grafana/grafana/pkg/services/dashboards"
-	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -36,6 +39,48 @@ func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// CreateEmailSession provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) CreateEmailSession(ctx context.Context, cmd models.CreateEmailSessionCommand) (int64, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, models.CreateEmailSessionCommand) int64); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.CreateEmailSessionCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateEmailShare provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardStore) CreateEmailShare(ctx context.Context, cmd models.CreateEmailShareCommand) (int64, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, models.CreateEmailShareCommand) int64); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.CreateEmailShareCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int64, error) {
 	ret := _m.Called(ctx, uid)
@@ -57,6 +102,27 @@ func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int
 	return r0, r1
 }
 
+// DeleteEmailShare provides a mock function with given fields: ctx, publicDashboardUid, uid
+func (_m *FakePublicDashboardStore) DeleteEmailShare(ctx context.Context, publicDashboardUid string, uid string) (int64, error) {
+	ret := _m.Called(ctx, publicDashboardUid, uid)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -237,6 +303,98 @@ func (_m *FakePublicDashboardStore) FindDashboard(ctx context.Context, orgId int
 	return r0, r1
 }
 
+// FindEmailSession provides a mock function with given fields: ctx, publicDashboardUid, token
+func (_m *FakePublicDashboardStore) FindEmailSession(ctx context.Context, publicDashboardUid string, token string) (*models.EmailSession, error) {
+	ret := _m.Called(ctx, publicDashboardUid, token)
+
+	var r0 *models.EmailSession
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.EmailSession); ok {
+		r0 = rf(ctx, publicDashboardUid, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailSession)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailShare provides a mock function with given fields: ctx, publicDashboardUid, uid
+func (_m *FakePublicDashboardStore) FindEmailShare(ctx context.Context, publicDashboardUid string, uid string) (*models.EmailShare, error) {
+	ret := _m.Called(ctx, publicDashboardUid, uid)
+
+	var r0 *models.EmailShare
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.EmailShare); ok {
+		r0 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailShareByMagicLinkToken provides a mock function with given fields: ctx, publicDashboardUid, magicLinkToken
+func (_m *FakePublicDashboardStore) FindEmailShareByMagicLinkToken(ctx context.Context, publicDashboardUid string, magicLinkToken string) (*models.EmailShare, error) {
+	ret := _m.Called(ctx, publicDashboardUid, magicLinkToken)
+
+	var r0 *models.EmailShare
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.EmailShare); ok {
+		r0 = rf(ctx, publicDashboardUid, magicLinkToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmailShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid, magicLinkToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailShares provides a mock function with given fields: ctx, publicDashboardUid
+func (_m *FakePublicDashboardStore) FindEmailShares(ctx context.Context, publicDashboardUid string) ([]models.EmailShare, error) {
+	ret := _m.Called(ctx, publicDashboardUid)
+
+	var r0 []models.EmailShare
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.EmailShare); ok {
+		r0 = rf(ctx, publicDashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.EmailShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetrics provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardStore) GetMetrics(ctx context.Context) (*models.Metrics, error) {
 	ret := _m.Called(ctx)
@@ -302,6 +460,41 @@ func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// UpdateEmailShareLastViewedAt provides a mock function with given fields: ctx, uid, lastViewedAt
+func (_m *FakePublicDashboardStore) UpdateEmailShareLastViewedAt(ctx context.Context, uid string, lastViewedAt time.Time) error {
+	ret := _m.Called(ctx, uid, lastViewedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, uid, lastViewedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseEmailShareMagicLink provides a mock function with given fields: ctx, uid, usedAt
+func (_m *FakePublicDashboardStore) UseEmailShareMagicLink(ctx context.Context, uid string, usedAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, uid, usedAt)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(ctx, uid, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, uid, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewFakePublicDashboardStore interface {
 	mock.TestingT
 	Cleanup(func())
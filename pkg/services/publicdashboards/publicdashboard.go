@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -36,6 +37,12 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	FindRecipients(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]EmailShare, error)
+	AddRecipient(ctx context.Context, u *user.SignedInUser, dashboardUid string, uid string, dto *AddRecipientDTO) (*EmailShare, error)
+	RevokeRecipient(ctx context.Context, orgId int64, dashboardUid string, uid string, recipientUid string) error
+	NewEmailSession(ctx context.Context, accessToken string, magicLinkToken string) (*EmailSessionDTO, error)
+	ValidateEmailSession(ctx context.Context, accessToken string, sessionToken string) error
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -62,4 +69,14 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+
+	FindEmailShares(ctx context.Context, publicDashboardUid string) ([]EmailShare, error)
+	FindEmailShare(ctx context.Context, publicDashboardUid string, uid string) (*EmailShare, error)
+	FindEmailShareByMagicLinkToken(ctx context.Context, publicDashboardUid string, magicLinkToken string) (*EmailShare, error)
+	CreateEmailShare(ctx context.Context, cmd CreateEmailShareCommand) (int64, error)
+	DeleteEmailShare(ctx context.Context, publicDashboardUid string, uid string) (int64, error)
+	UpdateEmailShareLastViewedAt(ctx context.Context, uid string, lastViewedAt time.Time) error
+	UseEmailShareMagicLink(ctx context.Context, uid string, usedAt time.Time) (bool, error)
+	CreateEmailSession(ctx context.Context, cmd CreateEmailSessionCommand) (int64, error)
+	FindEmailSession(ctx context.Context, publicDashboardUid string, token string) (*EmailSession, error)
 }
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/notifications"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const publicDashboardInviteTemplate = "public_dashboard_invite"
+
+// emailShareLastViewedInterval is the minimum time between two writes of a recipient's last viewed time
+const emailShareLastViewedInterval = 5 * time.Minute
+
+// FindRecipients Returns the recipients of a public dashboard shared by email
+func (pd *PublicDashboardServiceImpl) FindRecipients(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]EmailShare, error) {
+	pubdash, err := pd.findOrgPublicDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := pd.store.FindEmailShares(ctx, pubdash.Uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindRecipients: failed to find recipients of public dashboard %s: %w", uid, err)
+	}
+
+	return shares, nil
+}
+
+// AddRecipient Invites a recipient to an email shared public dashboard and sends them a magic link
+func (pd *PublicDashboardServiceImpl) AddRecipient(ctx context.Context, u *user.SignedInUser, dashboardUid string, uid string, dto *AddRecipientDTO) (*EmailShare, error) {
+	recipient := strings.TrimSpace(dto.Recipient)
+	if !util.IsEmail(recipient) {
+		return nil, ErrInvalidRecipient.Errorf("AddRecipient: invalid recipient %q", dto.Recipient)
+	}
+
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry.Errorf("AddRecipient: expiry date %s is in the past", dto.ExpiresAt)
+	}
+
+	pubdash, err := pd.findOrgPublicDashboard(ctx, u.OrgID, dashboardUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubdash.Share != EmailShareType {
+		return nil, ErrNotSharedByEmail.Errorf("AddRecipient: public dashboard %s is shared as %s", uid, pubdash.Share)
+	}
+
+	shares, err := pd.store.FindEmailShares(ctx, pubdash.Uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("AddRecipient: failed to find recipients of public dashboard %s: %w", uid, err)
+	}
+	for _, share := range shares {
+		if strings.EqualFold(share.Recipient, recipient) {
+			return nil, ErrRecipientAlreadyInvited.Errorf("AddRecipient: %s was already invited to public dashboard %s", recipient, uid)
+		}
+	}
+
+	magicLinkToken, err := GenerateAccessToken()
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("AddRecipient: failed to generate magic link: %w", err)
+	}
+
+	cmd := CreateEmailShareCommand{
+		EmailShare: EmailShare{
+			Uid:                util.GenerateShortUID(),
+			PublicDashboardUid: pubdash.Uid,
+			Recipient:          recipient,
+			MagicLinkToken:     hashToken(magicLinkToken),
+			CreatedAt:          time.Now(),
+			ExpiresAt:          dto.ExpiresAt,
+		},
+	}
+
+	affectedRows, err := pd.store.CreateEmailShare(ctx, cmd)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("AddRecipient: failed to add recipient to public dashboard %s: %w", uid, err)
+	} else if affectedRows == 0 {
+		return nil, ErrInternalServerError.Errorf("AddRecipient: failed to add recipient to public dashboard %s. 0 rows changed, no error reported.", uid)
+	}
+
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, pubdash.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pd.notifications.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+		To:       []string{recipient},
+		Template: publicDashboardInviteTemplate,
+		Data: map[string]interface{}{
+			"InvitedBy":      u.NameOrFallback(),
+			"DashboardTitle": dashboard.Title,
+			"MagicLink":      fmt.Sprintf("%spublic-dashboards/%s?magicLinkToken=%s", pd.cfg.AppURL, pubdash.AccessToken, magicLinkToken),
+		},
+	})
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("AddRecipient: failed to send invitation to %s: %w", recipient, err)
+	}
+
+	pd.log.Info("Public dashboard recipient added", "publicDashboardUid", pubdash.Uid, "recipientUid", cmd.EmailShare.Uid, "user", u.Login)
+
+	return &cmd.EmailShare, nil
+}
+
+// RevokeRecipient Removes access for a recipient, including the sessions they already have
+func (pd *PublicDashboardServiceImpl) RevokeRecipient(ctx context.Context, orgId int64, dashboardUid string, uid string, recipientUid string) error {
+	pubdash, err := pd.findOrgPublicDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return err
+	}
+
+	affectedRows, err := pd.store.DeleteEmailShare(ctx, pubdash.Uid, recipientUid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("RevokeRecipient: failed to revoke recipient %s: %w", recipientUid, err)
+	}
+
+	if affectedRows == 0 {
+		return ErrRecipientNotFound.Errorf("RevokeRecipient: recipient %s not found for public dashboard %s", recipientUid, uid)
+	}
+
+	return nil
+}
+
+// NewEmailSession Exchanges the magic link token sent to a recipient for a session
+func (pd *PublicDashboardServiceImpl) NewEmailSession(ctx context.Context, accessToken string, magicLinkToken string) (*EmailSessionDTO, error) {
+	pubdash, _, err := pd.FindEnabledPublicDashboardAndDashboardByAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubdash.Share != EmailShareType {
+		return nil, ErrNotSharedByEmail.Errorf("NewEmailSession: public dashboard %s is shared as %s", pubdash.Uid, pubdash.Share)
+	}
+
+	share, err := pd.store.FindEmailShareByMagicLinkToken(ctx, pubdash.Uid, hashToken(magicLinkToken))
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("NewEmailSession: failed to find recipient: %w", err)
+	}
+
+	now := time.Now()
+	if share == nil || share.IsExpired(now) || share.MagicLinkUsedAt != nil {
+		return nil, ErrInvalidMagicLink.Errorf("NewEmailSession: invalid or expired magic link for public dashboard %s", pubdash.Uid)
+	}
+
+	// magic links are single-use, only the first request to claim the link gets a session
+	used, err := pd.store.UseEmailShareMagicLink(ctx, share.Uid, now)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("NewEmailSession: failed to use magic link: %w", err)
+	}
+	if !used {
+		return nil, ErrInvalidMagicLink.Errorf("NewEmailSession: magic link of recipient %s was already used", share.Uid)
+	}
+
+	sessionToken, err := GenerateAccessToken()
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("NewEmailSession: failed to generate session token: %w", err)
+	}
+
+	// the session never outlives the recipient access nor the public dashboard
+	expiresAt := now.Add(EmailSessionDuration)
+	for _, limit := range []*time.Time{share.ExpiresAt, pubdash.ExpiresAt} {
+		if limit != nil && limit.Before(expiresAt) {
+			expiresAt = *limit
+		}
+	}
+
+	_, err = pd.store.CreateEmailSession(ctx, CreateEmailSessionCommand{
+		EmailSession: EmailSession{
+			Token:              hashToken(sessionToken),
+			EmailShareUid:      share.Uid,
+			PublicDashboardUid: pubdash.Uid,
+			CreatedAt:          now,
+			ExpiresAt:          expiresAt,
+		},
+	})
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("NewEmailSession: failed to create session: %w", err)
+	}
+
+	return &EmailSessionDTO{Token: sessionToken, ExpiresAt: expiresAt}, nil
+}
+
+// ValidateEmailSession Ensures the session belongs to a current recipient when the public dashboard is shared by
+// email, and records the recipient view. Public dashboards shared with anyone do not require a session.
+func (pd *PublicDashboardServiceImpl) ValidateEmailSession(ctx context.Context, accessToken string, sessionToken string) error {
+	pubdash, err := pd.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	if pubdash.Share != EmailShareType {
+		return nil
+	}
+
+	session, err := pd.store.FindEmailSession(ctx, pubdash.Uid, hashToken(sessionToken))
+	if err != nil {
+		return ErrInternalServerError.Errorf("ValidateEmailSession: failed to find session: %w", err)
+	}
+
+	now := time.Now()
+	if session == nil || !now.Before(session.ExpiresAt) {
+		return ErrEmailSessionRequired.Errorf("ValidateEmailSession: no valid session for public dashboard %s", pubdash.Uid)
+	}
+
+	share, err := pd.store.FindEmailShare(ctx, pubdash.Uid, session.EmailShareUid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("ValidateEmailSession: failed to find recipient: %w", err)
+	}
+
+	if share == nil || share.IsExpired(now) {
+		return ErrEmailSessionRequired.Errorf("ValidateEmailSession: recipient access revoked or expired for public dashboard %s", pubdash.Uid)
+	}
+
+	// last viewed is informational, only write it once per interval instead of on every query
+	if share.LastViewedAt == nil || now.Sub(*share.LastViewedAt) >= emailShareLastViewedInterval {
+		if err := pd.store.UpdateEmailShareLastViewedAt(ctx, share.Uid, now); err != nil {
+			pd.log.Warn("Failed to update public dashboard recipient last viewed time", "recipientUid", share.Uid, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// findOrgPublicDashboard Gets a public dashboard by uid, making sure it belongs to the dashboard and org of the request
+func (pd *PublicDashboardServiceImpl) findOrgPublicDashboard(ctx context.Context, orgId int64, dashboardUid string, uid string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("findOrgPublicDashboard: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+
+	if pubdash == nil || pubdash.OrgId != orgId || pubdash.DashboardUid != dashboardUid {
+		return nil, ErrPublicDashboardNotFound.Errorf("findOrgPublicDashboard: public dashboard not found by uid: %s", uid)
+	}
+
+	return pubdash, nil
+}
+
+// hashToken Tokens handed out to recipients are only stored hashed
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newEmailSharingService(t *testing.T) (*PublicDashboardServiceImpl, *FakePublicDashboardStore, *notifications.NotificationServiceMock) {
+	store := NewFakePublicDashboardStore(t)
+	ns := notifications.MockNotificationService()
+	service := &PublicDashboardServiceImpl{
+		log:           log.New("test.logger"),
+		cfg:           &setting.Cfg{AppURL: "https://grafana.example.com/"},
+		store:         store,
+		notifications: ns,
+	}
+	return service, store, ns
+}
+
+func TestAddRecipient(t *testing.T) {
+	u := &user.SignedInUser{UserID: 1, OrgID: 1, Login: "admin", Name: "Admin"}
+	pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, AccessToken: "token", Share: EmailShareType, IsEnabled: true}
+
+	t.Run("sends a magic link and stores the hashed token", func(t *testing.T) {
+		service, store, ns := newEmailSharingService(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("FindEmailShares", mock.Anything, "pubdash").Return([]EmailShare{}, nil)
+		store.On("FindDashboard", mock.Anything, int64(1), "dash").Return(&dashboards.Dashboard{UID: "dash", Title: "My dashboard"}, nil)
+
+		var stored CreateEmailShareCommand
+		store.On("CreateEmailShare", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(CreateEmailShareCommand)
+		}).Return(int64(1), nil)
+
+		share, err := service.AddRecipient(context.Background(), u, "dash", "pubdash", &AddRecipientDTO{Recipient: " someone@example.com "})
+		require.NoError(t, err)
+		assert.Equal(t, "someone@example.com", share.Recipient)
+		assert.Equal(t, "pubdash", share.PublicDashboardUid)
+
+		assert.Equal(t, []string{"someone@example.com"}, ns.Email.To)
+		assert.Equal(t, publicDashboardInviteTemplate, ns.Email.Template)
+		assert.Equal(t, "My dashboard", ns.Email.Data["DashboardTitle"])
+
+		link := ns.Email.Data["MagicLink"].(string)
+		prefix := "https://grafana.example.com/public-dashboards/token?magicLinkToken="
+		require.Contains(t, link, prefix)
+		assert.Equal(t, hashToken(link[len(prefix):]), stored.EmailShare.MagicLinkToken)
+	})
+
+	t.Run("rejects invalid email addresses", func(t *testing.T) {
+		service, _, _ := newEmailSharingService(t)
+
+		_, err := service.AddRecipient(context.Background(), u, "dash", "pubdash", &AddRecipientDTO{Recipient: "not an email"})
+		require.ErrorIs(t, err, ErrInvalidRecipient)
+	})
+
+	t.Run("rejects expiry dates in the past", func(t *testing.T) {
+		service, _, _ := newEmailSharingService(t)
+		expiresAt := time.Now().Add(-time.Minute)
+
+		_, err := service.AddRecipient(context.Background(), u, "dash", "pubdash", &AddRecipientDTO{Recipient: "someone@example.com", ExpiresAt: &expiresAt})
+		require.ErrorIs(t, err, ErrInvalidExpiry)
+	})
+
+	t.Run("rejects public dashboards shared with anyone", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		public := *pubdash
+		public.Share = PublicShareType
+		store.On("Find", mock.Anything, "pubdash").Return(&public, nil)
+
+		_, err := service.AddRecipient(context.Background(), u, "dash", "pubdash", &AddRecipientDTO{Recipient: "someone@example.com"})
+		require.ErrorIs(t, err, ErrNotSharedByEmail)
+	})
+
+	t.Run("rejects recipients that were already invited", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("FindEmailShares", mock.Anything, "pubdash").Return([]EmailShare{{Uid: "share", Recipient: "Someone@example.com"}}, nil)
+
+		_, err := service.AddRecipient(context.Background(), u, "dash", "pubdash", &AddRecipientDTO{Recipient: "someone@example.com"})
+		require.ErrorIs(t, err, ErrRecipientAlreadyInvited)
+	})
+
+	t.Run("returns not found when the public dashboard belongs to another org", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+
+		otherOrgUser := &user.SignedInUser{UserID: 1, OrgID: 2}
+		_, err := service.AddRecipient(context.Background(), otherOrgUser, "dash", "pubdash", &AddRecipientDTO{Recipient: "someone@example.com"})
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestRevokeRecipient(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, Share: EmailShareType}
+
+	t.Run("deletes the recipient", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("DeleteEmailShare", mock.Anything, "pubdash", "share").Return(int64(1), nil)
+
+		err := service.RevokeRecipient(context.Background(), 1, "dash", "pubdash", "share")
+		require.NoError(t, err)
+	})
+
+	t.Run("returns not found when the recipient does not exist", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		store.On("DeleteEmailShare", mock.Anything, "pubdash", "share").Return(int64(0), nil)
+
+		err := service.RevokeRecipient(context.Background(), 1, "dash", "pubdash", "share")
+		require.ErrorIs(t, err, ErrRecipientNotFound)
+	})
+}
+
+func TestNewEmailSession(t *testing.T) {
+	dashboard := &dashboards.Dashboard{UID: "dash", OrgID: 1}
+
+	t.Run("creates a session that does not outlive the recipient access", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, AccessToken: "token", Share: EmailShareType, IsEnabled: true}
+		shareExpiresAt := time.Now().Add(time.Hour)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("FindDashboard", mock.Anything, int64(1), "dash").Return(dashboard, nil)
+		store.On("FindEmailShareByMagicLinkToken", mock.Anything, "pubdash", hashToken("magic")).
+			Return(&EmailShare{Uid: "share", PublicDashboardUid: "pubdash", ExpiresAt: &shareExpiresAt}, nil)
+		store.On("UseEmailShareMagicLink", mock.Anything, "share", mock.Anything).Return(true, nil)
+
+		var stored CreateEmailSessionCommand
+		store.On("CreateEmailSession", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(CreateEmailSessionCommand)
+		}).Return(int64(1), nil)
+
+		session, err := service.NewEmailSession(context.Background(), "token", "magic")
+		require.NoError(t, err)
+		assert.Equal(t, shareExpiresAt, session.ExpiresAt)
+		assert.Equal(t, hashToken(session.Token), stored.EmailSession.Token)
+		assert.Equal(t, "share", stored.EmailSession.EmailShareUid)
+	})
+
+	t.Run("rejects unknown magic links", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, AccessToken: "token", Share: EmailShareType, IsEnabled: true}
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("FindDashboard", mock.Anything, int64(1), "dash").Return(dashboard, nil)
+		store.On("FindEmailShareByMagicLinkToken", mock.Anything, "pubdash", hashToken("magic")).Return(nil, nil)
+
+		_, err := service.NewEmailSession(context.Background(), "token", "magic")
+		require.ErrorIs(t, err, ErrInvalidMagicLink)
+	})
+
+	t.Run("rejects magic links that were already used", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, AccessToken: "token", Share: EmailShareType, IsEnabled: true}
+		usedAt := time.Now().Add(-time.Minute)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("FindDashboard", mock.Anything, int64(1), "dash").Return(dashboard, nil)
+		store.On("FindEmailShareByMagicLinkToken", mock.Anything, "pubdash", hashToken("magic")).
+			Return(&EmailShare{Uid: "share", PublicDashboardUid: "pubdash", MagicLinkUsedAt: &usedAt}, nil)
+
+		_, err := service.NewEmailSession(context.Background(), "token", "magic")
+		require.ErrorIs(t, err, ErrInvalidMagicLink)
+		store.AssertNotCalled(t, "CreateEmailSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects magic links used by a concurrent request", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, AccessToken: "token", Share: EmailShareType, IsEnabled: true}
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("FindDashboard", mock.Anything, int64(1), "dash").Return(dashboard, nil)
+		store.On("FindEmailShareByMagicLinkToken", mock.Anything, "pubdash", hashToken("magic")).
+			Return(&EmailShare{Uid: "share", PublicDashboardUid: "pubdash"}, nil)
+		store.On("UseEmailShareMagicLink", mock.Anything, "share", mock.Anything).Return(false, nil)
+
+		_, err := service.NewEmailSession(context.Background(), "token", "magic")
+		require.ErrorIs(t, err, ErrInvalidMagicLink)
+		store.AssertNotCalled(t, "CreateEmailSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects expired public dashboards", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		expiredAt := time.Now().Add(-time.Hour)
+		pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1, AccessToken: "token", Share: EmailShareType, IsEnabled: true, ExpiresAt: &expiredAt}
+		store.On("FindByAccessToken", mock.Anything, "token").Return(pubdash, nil)
+		store.On("FindDashboard", mock.Anything, int64(1), "dash").Return(dashboard, nil)
+
+		_, err := service.NewEmailSession(context.Background(), "token", "magic")
+		require.ErrorIs(t, err, ErrPublicDashboardExpired)
+	})
+}
+
+func TestValidateEmailSession(t *testing.T) {
+	t.Run("does not require a session for public dashboards shared with anyone", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&PublicDashboard{Uid: "pubdash", Share: PublicShareType}, nil)
+
+		err := service.ValidateEmailSession(context.Background(), "token", "")
+		require.NoError(t, err)
+	})
+
+	t.Run("accepts a valid session and records the view", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&PublicDashboard{Uid: "pubdash", Share: EmailShareType}, nil)
+		store.On("FindEmailSession", mock.Anything, "pubdash", hashToken("session")).
+			Return(&EmailSession{EmailShareUid: "share", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		store.On("FindEmailShare", mock.Anything, "pubdash", "share").Return(&EmailShare{Uid: "share"}, nil)
+		store.On("UpdateEmailShareLastViewedAt", mock.Anything, "share", mock.Anything).Return(nil)
+
+		err := service.ValidateEmailSession(context.Background(), "token", "session")
+		require.NoError(t, err)
+	})
+
+	t.Run("does not record the view again within the update interval", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		lastViewedAt := time.Now().Add(-time.Minute)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&PublicDashboard{Uid: "pubdash", Share: EmailShareType}, nil)
+		store.On("FindEmailSession", mock.Anything, "pubdash", hashToken("session")).
+			Return(&EmailSession{EmailShareUid: "share", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		store.On("FindEmailShare", mock.Anything, "pubdash", "share").Return(&EmailShare{Uid: "share", LastViewedAt: &lastViewedAt}, nil)
+
+		err := service.ValidateEmailSession(context.Background(), "token", "session")
+		require.NoError(t, err)
+		store.AssertNotCalled(t, "UpdateEmailShareLastViewedAt", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects expired sessions", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&PublicDashboard{Uid: "pubdash", Share: EmailShareType}, nil)
+		store.On("FindEmailSession", mock.Anything, "pubdash", hashToken("session")).
+			Return(&EmailSession{EmailShareUid: "share", ExpiresAt: time.Now().Add(-time.Hour)}, nil)
+
+		err := service.ValidateEmailSession(context.Background(), "token", "session")
+		require.ErrorIs(t, err, ErrEmailSessionRequired)
+	})
+
+	t.Run("rejects sessions of revoked recipients", func(t *testing.T) {
+		service, store, _ := newEmailSharingService(t)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&PublicDashboard{Uid: "pubdash", Share: EmailShareType}, nil)
+		store.On("FindEmailSession", mock.Anything, "pubdash", hashToken("session")).
+			Return(&EmailSession{EmailShareUid: "share", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		store.On("FindEmailShare", mock.Anything, "pubdash", "share").Return(nil, nil)
+
+		err := service.ValidateEmailSession(context.Background(), "token", "session")
+		require.ErrorIs(t, err, ErrEmailSessionRequired)
+	})
+}
@@ -159,6 +159,12 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, da
 		return dtos.MetricRequest{}, models.ErrPanelNotFound.Errorf("buildMetricRequest: public dashboard panel not found")
 	}
 
+	variables, err := resolveTemplateVariables(dashboard, publicDashboard, reqDTO.Variables)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+	interpolateQueries(queries, variables)
+
 	ts := publicDashboard.BuildTimeSettings(dashboard, reqDTO)
 
 	// determine safe resolution to query data at
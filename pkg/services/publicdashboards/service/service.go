@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
@@ -34,6 +35,7 @@ type PublicDashboardServiceImpl struct {
 	AnnotationsRepo    annotations.Repository
 	ac                 accesscontrol.AccessControl
 	serviceWrapper     publicdashboards.ServiceWrapper
+	notifications      notifications.EmailSender
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -51,6 +53,7 @@ func ProvideService(
 	anno annotations.Repository,
 	ac accesscontrol.AccessControl,
 	serviceWrapper publicdashboards.ServiceWrapper,
+	notificationService notifications.Service,
 ) *PublicDashboardServiceImpl {
 	return &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
@@ -61,6 +64,7 @@ func ProvideService(
 		AnnotationsRepo:    anno,
 		ac:                 ac,
 		serviceWrapper:     serviceWrapper,
+		notifications:      notificationService,
 	}
 }
 
@@ -116,6 +120,10 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardExpired.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard expired accessToken: %s", accessToken)
+	}
+
 	return pubdash, dash, err
 }
 
@@ -147,7 +155,12 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validation.ValidateTemplateVariableSettings(dto.PublicDashboard.TemplateVariables, dashboard.Data)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +180,10 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.TimeSettings = &TimeSettings{}
 	}
 
+	if dto.PublicDashboard.TemplateVariables == nil {
+		dto.PublicDashboard.TemplateVariables = &TemplateVariableSettings{}
+	}
+
 	if dto.PublicDashboard.Share == "" {
 		dto.PublicDashboard.Share = PublicShareType
 	}
@@ -190,9 +207,11 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 			AnnotationsEnabled:   dto.PublicDashboard.AnnotationsEnabled,
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
+			TemplateVariables:    dto.PublicDashboard.TemplateVariables,
 			Share:                dto.PublicDashboard.Share,
 			CreatedBy:            dto.UserId,
 			CreatedAt:            time.Now(),
+			ExpiresAt:            dto.PublicDashboard.ExpiresAt,
 			AccessToken:          accessToken,
 		},
 	}
@@ -233,6 +252,11 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		return nil, ErrDashboardNotFound.Errorf("Update: dashboard not found by orgId: %d and dashboardUid: %s", u.OrgID, dto.DashboardUid)
 	}
 
+	err = validation.ValidateTemplateVariableSettings(dto.PublicDashboard.TemplateVariables, dashboard.Data)
+	if err != nil {
+		return nil, err
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, dto.PublicDashboard.Uid)
 	if err != nil {
@@ -250,6 +274,15 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.Share = existingPubdash.Share
 	}
 
+	// settings left out of the request keep their saved value
+	if dto.PublicDashboard.TemplateVariables == nil {
+		dto.PublicDashboard.TemplateVariables = existingPubdash.TemplateVariables
+	}
+
+	if dto.PublicDashboard.ExpiresAt == nil && !dto.ClearExpiresAt {
+		dto.PublicDashboard.ExpiresAt = existingPubdash.ExpiresAt
+	}
+
 	// set values to update
 	cmd := SavePublicDashboardCommand{
 		PublicDashboard: PublicDashboard{
@@ -258,9 +291,11 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 			AnnotationsEnabled:   dto.PublicDashboard.AnnotationsEnabled,
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
+			TemplateVariables:    dto.PublicDashboard.TemplateVariables,
 			Share:                dto.PublicDashboard.Share,
 			UpdatedBy:            dto.UserId,
 			UpdatedAt:            time.Now(),
+			ExpiresAt:            dto.PublicDashboard.ExpiresAt,
 		},
 	}
 
@@ -550,6 +550,80 @@ func TestUpdatePublicDashboard(t *testing.T) {
 
 		assert.Equal(t, &TimeSettings{}, updatedPubdash.TimeSettings)
 	})
+
+	t.Run("Updating keeps the expiry and template variables left out of the request", func(t *testing.T) {
+		sqlStore := db.InitTestDB(t)
+		quotaService := quotatest.New(false, nil)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotaService)
+		require.NoError(t, err)
+		publicdashboardStore := database.ProvideStore(sqlStore)
+		serviceWrapper := ProvideServiceWrapper(publicdashboardStore)
+
+		templateVars := []map[string]interface{}{{"name": "service", "type": "custom"}}
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, templateVars, nil)
+
+		service := &PublicDashboardServiceImpl{
+			log:            log.New("test.logger"),
+			store:          publicdashboardStore,
+			serviceWrapper: serviceWrapper,
+		}
+
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		templateVariables := &TemplateVariableSettings{Allowed: []AllowedTemplateVariable{{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard}}}
+		dto := &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			OrgId:        dashboard.OrgID,
+			UserId:       7,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled:         true,
+				TimeSettings:      timeSettings,
+				TemplateVariables: templateVariables,
+				ExpiresAt:         &expiresAt,
+			},
+		}
+
+		savedPubdash, err := service.Create(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		// only toggle annotations
+		dto = &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			OrgId:        dashboard.OrgID,
+			UserId:       8,
+			PublicDashboard: &PublicDashboard{
+				Uid:                savedPubdash.Uid,
+				IsEnabled:          true,
+				AnnotationsEnabled: true,
+			},
+		}
+
+		updatedPubdash, err := service.Update(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		assert.True(t, updatedPubdash.AnnotationsEnabled)
+		assert.Equal(t, templateVariables, updatedPubdash.TemplateVariables)
+		require.NotNil(t, updatedPubdash.ExpiresAt)
+		assert.Equal(t, expiresAt, updatedPubdash.ExpiresAt.UTC())
+
+		// remove the expiry and the allowed template variables
+		dto = &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			OrgId:        dashboard.OrgID,
+			UserId:       8,
+			PublicDashboard: &PublicDashboard{
+				Uid:               savedPubdash.Uid,
+				IsEnabled:         true,
+				TemplateVariables: &TemplateVariableSettings{},
+			},
+			ClearExpiresAt: true,
+		}
+
+		updatedPubdash, err = service.Update(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		assert.Empty(t, updatedPubdash.TemplateVariables.Allowed)
+		assert.Nil(t, updatedPubdash.ExpiresAt)
+	})
 }
 
 func TestDeletePublicDashboard(t *testing.T) {
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// Same syntax the frontend template service supports: $var, [[var:format]] and ${var.fieldPath:format}
+var templateVariableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?:\.([^:^\}]+))?(?::([^\}]+))?\}`)
+
+// Query fields that select where the query runs are never interpolated, so viewers can't switch data sources
+var notInterpolatedQueryFields = map[string]bool{
+	"datasource": true,
+	"refId":      true,
+}
+
+// Data sources format multi value variables for their query language when no format is set
+var defaultMultiValueFormats = map[string]string{
+	"prometheus": "regex",
+	"loki":       "regex",
+	"mysql":      "sqlstring",
+	"postgres":   "sqlstring",
+	"mssql":      "sqlstring",
+}
+
+type templateVariableValue struct {
+	values []string
+	// multi value and include all variables get the data source default format
+	multi bool
+	// custom all values are interpolated as they are, without formatting
+	raw bool
+}
+
+// resolveTemplateVariables returns the value of every dashboard template variable. Viewers can only change allowed
+// variables, to permitted values. Everything else keeps the value saved on the dashboard.
+func resolveTemplateVariables(dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, requested map[string][]string) (map[string]templateVariableValue, error) {
+	resolved := make(map[string]templateVariableValue)
+	for _, v := range models.GetDashboardTemplateVariables(dashboard.Data) {
+		values := v.Current
+
+		if selected, ok := requested[v.Name]; ok {
+			if err := validateTemplateVariableValues(v, publicDashboard.TemplateVariables.Find(v.Name), selected); err != nil {
+				return nil, err
+			}
+			values = selected
+		}
+
+		value := expandAllValue(v, values)
+		value.multi = v.Multi || v.IncludeAll
+		resolved[v.Name] = value
+	}
+
+	for name := range requested {
+		if _, ok := resolved[name]; !ok {
+			return nil, models.ErrInvalidTemplateVariableValue.Errorf("resolveTemplateVariables: template variable %s not found", name)
+		}
+	}
+
+	return resolved, nil
+}
+
+func validateTemplateVariableValues(v models.DashboardTemplateVariable, allowed *models.AllowedTemplateVariable, values []string) error {
+	if allowed == nil || !v.IsSupported() {
+		return models.ErrInvalidTemplateVariableValue.Errorf("validateTemplateVariableValues: template variable %s can't be changed", v.Name)
+	}
+
+	if len(values) == 0 || (len(values) > 1 && !v.Multi) {
+		return models.ErrInvalidTemplateVariableValue.Errorf("validateTemplateVariableValues: invalid number of values for template variable %s", v.Name)
+	}
+
+	permitted := make(map[string]bool)
+	for _, value := range v.PermittedValues(*allowed) {
+		permitted[value] = true
+	}
+
+	for _, value := range values {
+		if value == models.TemplateVariableAllValue && !v.IncludeAll {
+			return models.ErrInvalidTemplateVariableValue.Errorf("validateTemplateVariableValues: template variable %s has no all option", v.Name)
+		}
+		if !permitted[value] {
+			return models.ErrInvalidTemplateVariableValue.Errorf("validateTemplateVariableValues: value %q is not permitted for template variable %s", value, v.Name)
+		}
+	}
+
+	return nil
+}
+
+func expandAllValue(v models.DashboardTemplateVariable, values []string) templateVariableValue {
+	for _, value := range values {
+		if value != models.TemplateVariableAllValue {
+			continue
+		}
+		if v.AllValue != "" {
+			return templateVariableValue{values: []string{v.AllValue}, raw: true}
+		}
+		return templateVariableValue{values: v.Options}
+	}
+	return templateVariableValue{values: values}
+}
+
+// interpolateQueries replaces template variables in every field of the queries except the data source
+func interpolateQueries(queries []*simplejson.Json, variables map[string]templateVariableValue) {
+	if len(variables) == 0 {
+		return
+	}
+
+	for _, query := range queries {
+		fields, err := query.Map()
+		if err != nil {
+			continue
+		}
+		defaultFormat := defaultMultiValueFormats[query.GetPath("datasource", "type").MustString()]
+		for key, value := range fields {
+			if notInterpolatedQueryFields[key] {
+				continue
+			}
+			fields[key] = interpolateValue(value, variables, defaultFormat)
+		}
+	}
+}
+
+func interpolateValue(value interface{}, variables map[string]templateVariableValue, defaultFormat string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, variables, defaultFormat)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = interpolateValue(item, variables, defaultFormat)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = interpolateValue(item, variables, defaultFormat)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func interpolateString(s string, variables map[string]templateVariableValue, defaultFormat string) string {
+	return templateVariableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := templateVariableRegex.FindStringSubmatch(match)
+		name := firstNonEmpty(groups[1], groups[2], groups[4])
+		format := firstNonEmpty(groups[3], groups[6])
+
+		variable, ok := variables[name]
+		if !ok {
+			// builtin variables such as $__interval are interpolated by the data sources
+			return match
+		}
+		if variable.raw {
+			return strings.Join(variable.values, ",")
+		}
+		if format == "" && variable.multi {
+			format = defaultFormat
+		}
+		return formatTemplateVariableValues(variable.values, format)
+	})
+}
+
+// formatTemplateVariableValues formats values like the frontend template service does for the most common formats
+func formatTemplateVariableValues(values []string, format string) string {
+	switch format {
+	case "raw":
+		return strings.Join(values, ",")
+	case "csv":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, value := range values {
+			escaped[i] = regexp.QuoteMeta(value)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "singlequote":
+		return quoteEach(values, "'", `\'`)
+	case "doublequote":
+		return quoteEach(values, `"`, `\"`)
+	case "sqlstring":
+		return quoteEach(values, "'", "''")
+	case "json":
+		var b []byte
+		if len(values) == 1 {
+			b, _ = json.Marshal(values[0])
+		} else {
+			b, _ = json.Marshal(values)
+		}
+		return string(b)
+	default:
+		// glob, which is also the default for multi value variables
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
+
+func quoteEach(values []string, quote string, escaped string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote + strings.ReplaceAll(value, quote, escaped) + quote
+	}
+	return strings.Join(quoted, ",")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardWithTemplateVariables = `{
+  "time": {"from": "now-6h", "to": "now"},
+  "panels": [
+    {
+      "id": 1,
+      "datasource": {"type": "prometheus", "uid": "${ds}"},
+      "targets": [
+        {
+          "refId": "A",
+          "datasource": {"type": "prometheus", "uid": "promds"},
+          "expr": "rate(http_requests_total{service=~\"$service\", env=\"${env}\"}[$__rate_interval])"
+        },
+        {
+          "refId": "B",
+          "datasource": {"type": "prometheus", "uid": "promds"},
+          "expr": "up{service=\"${service:csv}\"}",
+          "legendFormat": "[[env]]"
+        }
+      ]
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "service",
+        "type": "custom",
+        "multi": true,
+        "includeAll": true,
+        "query": "api,web,worker",
+        "current": {"text": ["api"], "value": ["api"]},
+        "options": []
+      },
+      {
+        "name": "env",
+        "type": "query",
+        "current": {"text": "prod", "value": "prod"},
+        "options": []
+      },
+      {
+        "name": "ds",
+        "type": "datasource",
+        "current": {"text": "Prometheus", "value": "promds"},
+        "options": []
+      }
+    ]
+  }
+}`
+
+func newTemplateVariablesDashboard(t *testing.T) *dashboards.Dashboard {
+	t.Helper()
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	return &dashboards.Dashboard{UID: "dash", OrgID: 1, Data: data}
+}
+
+func TestResolveTemplateVariables(t *testing.T) {
+	pubdash := &PublicDashboard{
+		TemplateVariables: &TemplateVariableSettings{Allowed: []AllowedTemplateVariable{
+			{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard},
+			{Name: "env", ValuesSource: TemplateVariableValuesSourceList, Values: []string{"prod", "staging"}},
+		}},
+	}
+
+	t.Run("uses the saved values when nothing is selected", func(t *testing.T) {
+		variables, err := resolveTemplateVariables(newTemplateVariablesDashboard(t), pubdash, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api"}, variables["service"].values)
+		assert.Equal(t, []string{"prod"}, variables["env"].values)
+		assert.Equal(t, []string{"promds"}, variables["ds"].values)
+	})
+
+	t.Run("uses permitted values selected by the viewer", func(t *testing.T) {
+		variables, err := resolveTemplateVariables(newTemplateVariablesDashboard(t), pubdash, map[string][]string{
+			"service": {"web", "worker"},
+			"env":     {"staging"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"web", "worker"}, variables["service"].values)
+		assert.Equal(t, []string{"staging"}, variables["env"].values)
+	})
+
+	t.Run("expands the all option", func(t *testing.T) {
+		variables, err := resolveTemplateVariables(newTemplateVariablesDashboard(t), pubdash, map[string][]string{
+			"service": {TemplateVariableAllValue},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api", "web", "worker"}, variables["service"].values)
+	})
+
+	testCases := []struct {
+		name      string
+		requested map[string][]string
+	}{
+		{name: "rejects values that are not permitted", requested: map[string][]string{"env": {"dev"}}},
+		{name: "rejects values not in the dashboard options", requested: map[string][]string{"service": {"db"}}},
+		{name: "rejects variables that are not allowed", requested: map[string][]string{"ds": {"promds"}}},
+		{name: "rejects unknown variables", requested: map[string][]string{"unknown": {"value"}}},
+		{name: "rejects many values for single value variables", requested: map[string][]string{"env": {"prod", "staging"}}},
+		{name: "rejects empty values", requested: map[string][]string{"env": {}}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveTemplateVariables(newTemplateVariablesDashboard(t), pubdash, tt.requested)
+			require.ErrorIs(t, err, ErrInvalidTemplateVariableValue)
+		})
+	}
+}
+
+func TestFormatTemplateVariableValues(t *testing.T) {
+	testCases := []struct {
+		format   string
+		values   []string
+		expected string
+	}{
+		{format: "", values: []string{"a"}, expected: "a"},
+		{format: "", values: []string{"a", "b"}, expected: "{a,b}"},
+		{format: "glob", values: []string{"a", "b"}, expected: "{a,b}"},
+		{format: "raw", values: []string{"a", "b"}, expected: "a,b"},
+		{format: "csv", values: []string{"a", "b"}, expected: "a,b"},
+		{format: "pipe", values: []string{"a", "b"}, expected: "a|b"},
+		{format: "regex", values: []string{"a.b"}, expected: `a\.b`},
+		{format: "regex", values: []string{"a.b", "c"}, expected: `(a\.b|c)`},
+		{format: "singlequote", values: []string{"it's", "b"}, expected: `'it\'s','b'`},
+		{format: "doublequote", values: []string{"a", "b"}, expected: `"a","b"`},
+		{format: "sqlstring", values: []string{"it's"}, expected: `'it''s'`},
+		{format: "json", values: []string{"a", "b"}, expected: `["a","b"]`},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.format+"/"+tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatTemplateVariableValues(tt.values, tt.format))
+		})
+	}
+}
+
+func TestBuildMetricRequestWithTemplateVariables(t *testing.T) {
+	service := &PublicDashboardServiceImpl{
+		log:                log.New("test.logger"),
+		intervalCalculator: intervalv2.NewCalculator(),
+	}
+	pubdash := &PublicDashboard{
+		TemplateVariables: &TemplateVariableSettings{Allowed: []AllowedTemplateVariable{
+			{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard},
+		}},
+	}
+
+	reqDTO, err := service.buildMetricRequest(context.Background(), newTemplateVariablesDashboard(t), pubdash, 1, PublicDashboardQueryDTO{
+		IntervalMs:    10000,
+		MaxDataPoints: 200,
+		Variables:     map[string][]string{"service": {"api", "web"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, reqDTO.Queries, 2)
+
+	assert.Equal(t, `rate(http_requests_total{service=~"(api|web)", env="prod"}[$__rate_interval])`, reqDTO.Queries[0].Get("expr").MustString())
+	// an explicit format wins over the data source default
+	assert.Equal(t, `up{service="api,web"}`, reqDTO.Queries[1].Get("expr").MustString())
+	assert.Equal(t, "prod", reqDTO.Queries[1].Get("legendFormat").MustString())
+	// the data source is never interpolated
+	assert.Equal(t, "promds", reqDTO.Queries[0].GetPath("datasource", "uid").MustString())
+}
@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
@@ -13,6 +16,10 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if dto.PublicDashboard.ExpiresAt != nil && !dto.PublicDashboard.ExpiresAt.After(time.Now()) {
+		return ErrInvalidExpiry.Errorf("ValidateSavePublicDashboard: expiry date is in the past")
+	}
+
 	return nil
 }
 
@@ -28,14 +35,66 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 	if pd.TimeSelectionEnabled {
 		timeRange := legacydata.NewDataTimeRange(req.TimeRange.From, req.TimeRange.To)
 
-		_, err := timeRange.ParseFrom()
+		from, err := timeRange.ParseFrom()
 		if err != nil {
 			return ErrInvalidTimeRange.Errorf("ValidateQueryPublicDashboardRequest: time range from is invalid")
 		}
-		_, err = timeRange.ParseTo()
+		to, err := timeRange.ParseTo()
 		if err != nil {
 			return ErrInvalidTimeRange.Errorf("ValidateQueryPublicDashboardRequest: time range to is invalid")
 		}
+
+		if from.After(to) {
+			return ErrInvalidTimeRange.Errorf("ValidateQueryPublicDashboardRequest: time range from is after to")
+		}
+	}
+
+	for name := range req.Variables {
+		if pd.TemplateVariables.Find(name) == nil {
+			return ErrInvalidTemplateVariableValue.Errorf("ValidateQueryPublicDashboardRequest: template variable %s can't be changed", name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateTemplateVariableSettings asserts that every allowed variable exists on the dashboard, can be changed by
+// viewers and has values to pick from
+func ValidateTemplateVariableSettings(settings *TemplateVariableSettings, dashboardData *simplejson.Json) error {
+	if settings == nil || len(settings.Allowed) == 0 {
+		return nil
+	}
+
+	variables := make(map[string]DashboardTemplateVariable)
+	for _, v := range GetDashboardTemplateVariables(dashboardData) {
+		variables[v.Name] = v
+	}
+
+	seen := make(map[string]bool, len(settings.Allowed))
+	for _, allowed := range settings.Allowed {
+		if seen[allowed.Name] {
+			return ErrInvalidTemplateVariables.Errorf("ValidateTemplateVariableSettings: template variable %s is allowed more than once", allowed.Name)
+		}
+		seen[allowed.Name] = true
+
+		v, ok := variables[allowed.Name]
+		if !ok {
+			return ErrInvalidTemplateVariables.Errorf("ValidateTemplateVariableSettings: template variable %s not found on dashboard", allowed.Name)
+		}
+
+		if !v.IsSupported() {
+			return ErrInvalidTemplateVariables.Errorf("ValidateTemplateVariableSettings: template variables of type %s can't be changed on public dashboards", v.Type)
+		}
+
+		switch allowed.ValuesSource {
+		case TemplateVariableValuesSourceDashboard:
+		case TemplateVariableValuesSourceList:
+			if len(allowed.Values) == 0 {
+				return ErrInvalidTemplateVariables.Errorf("ValidateTemplateVariableSettings: template variable %s has no permitted values", allowed.Name)
+			}
+		default:
+			return ErrInvalidTemplateVariables.Errorf("ValidateTemplateVariableSettings: invalid values source %q for template variable %s", allowed.ValuesSource, allowed.Name)
+		}
 	}
 
 	return nil
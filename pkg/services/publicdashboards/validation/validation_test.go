@@ -2,7 +2,9 @@ package validation
 
 import (
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,14 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when expiry date is in the past", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{Share: PublicShareType, ExpiresAt: &expiresAt}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidExpiry)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -114,6 +124,45 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns validation error when time range from is after to",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					TimeRange: TimeSettings{
+						From: "now",
+						To:   "now-1h",
+					},
+				},
+				pd: &PublicDashboard{
+					TimeSelectionEnabled: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns no error when an allowed template variable is set",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"service": {"api"}},
+				},
+				pd: &PublicDashboard{
+					TemplateVariables: &TemplateVariableSettings{Allowed: []AllowedTemplateVariable{{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when a template variable that is not allowed is set",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {"prod"}},
+				},
+				pd: &PublicDashboard{
+					TemplateVariables: &TemplateVariableSettings{Allowed: []AllowedTemplateVariable{{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard}}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Returns validation error when time range from or to is blank",
 			args: args{
@@ -170,3 +219,67 @@ func TestValidUid(t *testing.T) {
 		assert.False(t, IsValidShortUID("afqrz7j%%"))
 	})
 }
+
+func TestValidateTemplateVariableSettings(t *testing.T) {
+	dashboardData := simplejson.NewFromAny(map[string]interface{}{
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"name": "service", "type": "custom", "query": "api,web"},
+				map[string]interface{}{"name": "filters", "type": "adhoc"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		allowed  []AllowedTemplateVariable
+		expected error
+	}{
+		{
+			name:    "Returns no error when no variables are allowed",
+			allowed: nil,
+		},
+		{
+			name:    "Returns no error when allowed variables are valid",
+			allowed: []AllowedTemplateVariable{{Name: "service", ValuesSource: TemplateVariableValuesSourceList, Values: []string{"api"}}},
+		},
+		{
+			name:     "Returns error when variable is not on the dashboard",
+			allowed:  []AllowedTemplateVariable{{Name: "env", ValuesSource: TemplateVariableValuesSourceDashboard}},
+			expected: ErrInvalidTemplateVariables,
+		},
+		{
+			name:     "Returns error when variable type is not supported",
+			allowed:  []AllowedTemplateVariable{{Name: "filters", ValuesSource: TemplateVariableValuesSourceDashboard}},
+			expected: ErrInvalidTemplateVariables,
+		},
+		{
+			name:     "Returns error when list has no values",
+			allowed:  []AllowedTemplateVariable{{Name: "service", ValuesSource: TemplateVariableValuesSourceList}},
+			expected: ErrInvalidTemplateVariables,
+		},
+		{
+			name:     "Returns error when values source is invalid",
+			allowed:  []AllowedTemplateVariable{{Name: "service", ValuesSource: "query"}},
+			expected: ErrInvalidTemplateVariables,
+		},
+		{
+			name: "Returns error when variable is allowed twice",
+			allowed: []AllowedTemplateVariable{
+				{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard},
+				{Name: "service", ValuesSource: TemplateVariableValuesSourceDashboard},
+			},
+			expected: ErrInvalidTemplateVariables,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplateVariableSettings(&TemplateVariableSettings{Allowed: tt.allowed}, dashboardData)
+			if tt.expected == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
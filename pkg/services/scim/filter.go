@@ -0,0 +1,477 @@
+package scim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed SCIM filter expression (RFC 7644 section 3.4.2.2). Filters are
+// evaluated against the JSON representation of a resource.
+type Filter interface {
+	Matches(resource map[string]interface{}) bool
+}
+
+// ParseFilter parses a filter such as `userName eq "admin" and active eq true`
+func ParseFilter(expression string) (Filter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in filter", p.peek().value)
+	}
+	return filter, nil
+}
+
+type andFilter []Filter
+
+func (f andFilter) Matches(resource map[string]interface{}) bool {
+	for _, filter := range f {
+		if !filter.Matches(resource) {
+			return false
+		}
+	}
+	return true
+}
+
+type orFilter []Filter
+
+func (f orFilter) Matches(resource map[string]interface{}) bool {
+	for _, filter := range f {
+		if filter.Matches(resource) {
+			return true
+		}
+	}
+	return false
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+func (f notFilter) Matches(resource map[string]interface{}) bool {
+	return !f.filter.Matches(resource)
+}
+
+// valuePathFilter matches if an entry of a multi-valued attribute matches the filter, e.g. emails[type eq "work"]
+type valuePathFilter struct {
+	path   string
+	filter Filter
+}
+
+func (f valuePathFilter) Matches(resource map[string]interface{}) bool {
+	for _, value := range resolvePath(resource, f.path) {
+		if entry, ok := value.(map[string]interface{}); ok && f.filter.Matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareFilter struct {
+	path     string
+	operator string
+	value    interface{}
+}
+
+func (f compareFilter) Matches(resource map[string]interface{}) bool {
+	values := resolvePath(resource, f.path)
+
+	switch f.operator {
+	case "pr":
+		return len(values) > 0
+	case "ne":
+		for _, value := range values {
+			if compareValues(value, f.value, "eq") {
+				return false
+			}
+		}
+		return true
+	}
+
+	if f.value == nil && f.operator == "eq" {
+		return len(values) == 0
+	}
+
+	for _, value := range values {
+		if compareValues(value, f.value, f.operator) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareValues(actual, expected interface{}, operator string) bool {
+	// complex values without sub-attribute are compared by their value sub-attribute
+	if entry, ok := actual.(map[string]interface{}); ok {
+		actual = entry["value"]
+	}
+
+	switch expected := expected.(type) {
+	case bool:
+		actual, ok := asBool(actual)
+		return ok && operator == "eq" && actual == expected
+	case float64:
+		actual, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch operator {
+		case "eq":
+			return actual == expected
+		case "gt":
+			return actual > expected
+		case "ge":
+			return actual >= expected
+		case "lt":
+			return actual < expected
+		case "le":
+			return actual <= expected
+		}
+		return false
+	case string:
+		actual, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+		switch operator {
+		case "eq":
+			return actual == expected
+		case "co":
+			return strings.Contains(actual, expected)
+		case "sw":
+			return strings.HasPrefix(actual, expected)
+		case "ew":
+			return strings.HasSuffix(actual, expected)
+		case "gt":
+			return actual > expected
+		case "ge":
+			return actual >= expected
+		case "lt":
+			return actual < expected
+		case "le":
+			return actual <= expected
+		}
+	}
+	return false
+}
+
+// resolvePath returns the values of an attribute path such as name.givenName or emails.value,
+// sub-attributes of multi-valued attributes are collected from every entry
+func resolvePath(resource map[string]interface{}, path string) []interface{} {
+	current := []interface{}{resource}
+	for _, part := range strings.Split(stripSchema(path), ".") {
+		next := make([]interface{}, 0, len(current))
+		for _, value := range current {
+			entry, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			child, ok := entry[findKey(entry, part)]
+			if !ok || child == nil {
+				continue
+			}
+			if list, ok := child.([]interface{}); ok {
+				next = append(next, list...)
+			} else {
+				next = append(next, child)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// stripSchema removes the schema URN of fully qualified attribute paths
+func stripSchema(path string) string {
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		return path[strings.LastIndex(path, ":")+1:]
+	}
+	return path
+}
+
+// findKey returns the key of an attribute in the resource, attribute names are case-insensitive
+func findKey(resource map[string]interface{}, name string) string {
+	if _, ok := resource[name]; ok {
+		return name
+	}
+	for key := range resource {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+func asBool(value interface{}) (bool, bool) {
+	switch value := value.(type) {
+	case bool:
+		return value, true
+	case string:
+		b, err := strconv.ParseBool(value)
+		return b, err == nil
+	}
+	return false, false
+}
+
+var filterOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "gt": true, "ge": true, "lt": true, "le": true,
+}
+
+type filterTokenKind int
+
+const (
+	tokenWord filterTokenKind = iota
+	tokenString
+	tokenOpenParen
+	tokenCloseParen
+	tokenOpenBracket
+	tokenCloseBracket
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	value string
+}
+
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0)
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenOpenParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenCloseParen, ")"})
+			i++
+		case r == '[':
+			tokens = append(tokens, filterToken{tokenOpenBracket, "["})
+			i++
+		case r == ']':
+			tokens = append(tokens, filterToken{tokenCloseBracket, "]"})
+			i++
+		case r == '"':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			tokens = append(tokens, filterToken{tokenString, sb.String()})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()[]\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenWord, string(runes[start:i])})
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() (filterToken, error) {
+	if p.done() {
+		return filterToken{}, fmt.Errorf("unexpected end of filter")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token, nil
+}
+
+func (p *filterParser) isKeyword(keyword string) bool {
+	return !p.done() && p.peek().kind == tokenWord && strings.EqualFold(p.peek().value, keyword)
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	filters := orFilter{}
+	for {
+		filter, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+		if !p.isKeyword("or") {
+			break
+		}
+		p.pos++
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return filters, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	filters := andFilter{}
+	for {
+		filter, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+		if !p.isKeyword("and") {
+			break
+		}
+		p.pos++
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return filters, nil
+}
+
+func (p *filterParser) parseUnary() (Filter, error) {
+	if p.isKeyword("not") {
+		p.pos++
+		filter, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+		return notFilter{filter}, nil
+	}
+
+	if !p.done() && p.peek().kind == tokenOpenParen {
+		return p.parseGroup()
+	}
+
+	return p.parseAttribute()
+}
+
+func (p *filterParser) parseGroup() (Filter, error) {
+	if err := p.expect(tokenOpenParen); err != nil {
+		return nil, err
+	}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenCloseParen); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (p *filterParser) parseAttribute() (Filter, error) {
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if token.kind != tokenWord {
+		return nil, fmt.Errorf("expected attribute path but got %q", token.value)
+	}
+	path := token.value
+
+	if !p.done() && p.peek().kind == tokenOpenBracket {
+		p.pos++
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenCloseBracket); err != nil {
+			return nil, err
+		}
+		return valuePathFilter{path: path, filter: filter}, nil
+	}
+
+	token, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	operator := strings.ToLower(token.value)
+	if token.kind != tokenWord || (operator != "pr" && !filterOperators[operator]) {
+		return nil, fmt.Errorf("unknown operator %q", token.value)
+	}
+	if operator == "pr" {
+		return compareFilter{path: path, operator: operator}, nil
+	}
+
+	token, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseFilterValue(token)
+	if err != nil {
+		return nil, err
+	}
+	return compareFilter{path: path, operator: operator, value: value}, nil
+}
+
+func (p *filterParser) expect(kind filterTokenKind) error {
+	token, err := p.next()
+	if err != nil {
+		return err
+	}
+	if token.kind != kind {
+		return fmt.Errorf("unexpected %q in filter", token.value)
+	}
+	return nil
+}
+
+func parseFilterValue(token filterToken) (interface{}, error) {
+	if token.kind == tokenString {
+		return token.value, nil
+	}
+	if token.kind != tokenWord {
+		return nil, fmt.Errorf("expected value but got %q", token.value)
+	}
+
+	switch strings.ToLower(token.value) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(token.value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q in filter", token.value)
+	}
+	return number, nil
+}
@@ -0,0 +1,75 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	resource := map[string]interface{}{
+		"id":          "42",
+		"userName":    "Bjensen",
+		"displayName": "Barbara Jensen",
+		"active":      true,
+		"name":        map[string]interface{}{"formatted": "Barbara Jensen", "familyName": "Jensen"},
+		"emails": []interface{}{
+			map[string]interface{}{"value": "bjensen@example.com", "type": "work", "primary": true},
+			map[string]interface{}{"value": "babs@jensen.org", "type": "home"},
+		},
+	}
+
+	tests := []struct {
+		filter   string
+		expected bool
+	}{
+		{filter: `userName eq "bjensen"`, expected: true},
+		{filter: `USERNAME EQ "BJENSEN"`, expected: true},
+		{filter: `userName ne "bjensen"`, expected: false},
+		{filter: `userName sw "bj"`, expected: true},
+		{filter: `userName ew "sen"`, expected: true},
+		{filter: `displayName co "bara J"`, expected: true},
+		{filter: `name.familyName eq "Jensen"`, expected: true},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bjensen"`, expected: true},
+		{filter: `emails.value eq "babs@jensen.org"`, expected: true},
+		{filter: `emails eq "babs@jensen.org"`, expected: true},
+		{filter: `emails[type eq "work" and value co "@example.com"]`, expected: true},
+		{filter: `emails[type eq "work" and value co "@jensen.org"]`, expected: false},
+		{filter: `active eq true`, expected: true},
+		{filter: `active eq false`, expected: false},
+		{filter: `title pr`, expected: false},
+		{filter: `title eq null`, expected: true},
+		{filter: `userName eq "x" or active eq true`, expected: true},
+		{filter: `userName eq "x" or (active eq true and id eq "43")`, expected: false},
+		{filter: `not (userName eq "x")`, expected: true},
+		{filter: `id gt "41" and id le "42"`, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter.Matches(resource))
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expression := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName foo "x"`,
+		`userName eq "x`,
+		`(userName eq "x"`,
+		`userName eq "x" and`,
+		`emails[type eq "work"`,
+		`userName eq unquoted`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := ParseFilter(expression)
+			require.Error(t, err)
+		})
+	}
+}
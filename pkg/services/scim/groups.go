@@ -0,0 +1,294 @@
+package scim
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) ListGroups(c *contextmodel.ReqContext) response.Response {
+	result, err := s.teamService.SearchTeams(c.Req.Context(), &team.SearchTeamsQuery{
+		OrgID:        c.OrgID,
+		Page:         1,
+		SignedInUser: c.SignedInUser,
+		HiddenUsers:  map[string]struct{}{},
+	})
+	if err != nil {
+		return s.internalError("Failed to list groups", err)
+	}
+
+	// identity providers exclude members when they only look up groups by name
+	excludeMembers := strings.Contains(strings.ToLower(c.Query("excludedAttributes")), "members")
+
+	resources := make([]interface{}, 0, len(result.Teams))
+	for _, t := range result.Teams {
+		var members []*team.TeamMemberDTO
+		if !excludeMembers {
+			if members, err = s.getTeamMembers(c, t.ID); err != nil {
+				return s.internalError("Failed to get group members", err)
+			}
+		}
+		resources = append(resources, s.toGroup(t, members))
+	}
+	return listResponse(c, resources)
+}
+
+func (s *Service) GetGroup(c *contextmodel.ReqContext) response.Response {
+	t, errResp := s.getTeam(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	members, err := s.getTeamMembers(c, t.ID)
+	if err != nil {
+		return s.internalError("Failed to get group members", err)
+	}
+	return scimResponse(http.StatusOK, s.toGroup(t, members))
+}
+
+func (s *Service) CreateGroup(c *contextmodel.ReqContext) response.Response {
+	var resource Group
+	if err := decodeBody(c, &resource); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidSyntax, err.Error())
+	}
+	if resource.DisplayName == "" {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, "displayName is required")
+	}
+
+	// validate members before creating the team so a failed request does not leave an empty team behind
+	if errResp := s.validateMembers(c, resource.Members); errResp != nil {
+		return errResp
+	}
+
+	created, err := s.teamService.CreateTeam(resource.DisplayName, "", c.OrgID)
+	if errors.Is(err, team.ErrTeamNameTaken) {
+		return errorResponse(http.StatusConflict, ErrorTypeUniqueness, "a group with this displayName already exists")
+	}
+	if err != nil {
+		return s.internalError("Failed to create group", err)
+	}
+
+	if errResp := s.setMembers(c, created.ID, nil, resource.Members); errResp != nil {
+		return errResp
+	}
+
+	return s.groupResponse(c, http.StatusCreated, created.ID)
+}
+
+func (s *Service) ReplaceGroup(c *contextmodel.ReqContext) response.Response {
+	t, errResp := s.getTeam(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	var resource Group
+	if err := decodeBody(c, &resource); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidSyntax, err.Error())
+	}
+	return s.saveGroup(c, t, &resource)
+}
+
+func (s *Service) PatchGroup(c *contextmodel.ReqContext) response.Response {
+	t, errResp := s.getTeam(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	var patch PatchRequest
+	if err := decodeBody(c, &patch); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidSyntax, err.Error())
+	}
+
+	members, err := s.getTeamMembers(c, t.ID)
+	if err != nil {
+		return s.internalError("Failed to get group members", err)
+	}
+
+	values, err := toMap(s.toGroup(t, members))
+	if err != nil {
+		return s.internalError("Failed to patch group", err)
+	}
+	if err := ApplyPatch(values, patch.Operations); err != nil {
+		return patchErrorResponse(err)
+	}
+
+	var resource Group
+	if err := fromMap(values, &resource); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, err.Error())
+	}
+	return s.saveGroup(c, t, &resource)
+}
+
+func (s *Service) DeleteGroup(c *contextmodel.ReqContext) response.Response {
+	t, errResp := s.getTeam(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := s.teamService.DeleteTeam(c.Req.Context(), &team.DeleteTeamCommand{OrgID: c.OrgID, ID: t.ID}); err != nil {
+		return s.internalError("Failed to delete group", err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (s *Service) saveGroup(c *contextmodel.ReqContext, t *team.TeamDTO, resource *Group) response.Response {
+	if resource.DisplayName == "" {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, "displayName is required")
+	}
+	if errResp := s.validateMembers(c, resource.Members); errResp != nil {
+		return errResp
+	}
+
+	if resource.DisplayName != t.Name {
+		err := s.teamService.UpdateTeam(c.Req.Context(), &team.UpdateTeamCommand{
+			ID:    t.ID,
+			OrgID: c.OrgID,
+			Name:  resource.DisplayName,
+			Email: t.Email,
+		})
+		if errors.Is(err, team.ErrTeamNameTaken) {
+			return errorResponse(http.StatusConflict, ErrorTypeUniqueness, "a group with this displayName already exists")
+		}
+		if err != nil {
+			return s.internalError("Failed to update group", err)
+		}
+	}
+
+	current, err := s.getTeamMembers(c, t.ID)
+	if err != nil {
+		return s.internalError("Failed to get group members", err)
+	}
+	if errResp := s.setMembers(c, t.ID, current, resource.Members); errResp != nil {
+		return errResp
+	}
+
+	return s.groupResponse(c, http.StatusOK, t.ID)
+}
+
+// validateMembers checks that every member refers to a user of the org
+func (s *Service) validateMembers(c *contextmodel.ReqContext, members []MultiValued) response.Response {
+	for _, member := range members {
+		userID, ok := parseID(member.Value)
+		if !ok {
+			return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, "unknown member "+member.Value)
+		}
+		orgUser, err := s.findOrgUser(c.Req.Context(), c, userID)
+		if err != nil {
+			return s.internalError("Failed to get user", err)
+		}
+		if orgUser == nil {
+			return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, "unknown member "+member.Value)
+		}
+	}
+	return nil
+}
+
+// setMembers adds and removes team members so they match the members of the group
+func (s *Service) setMembers(c *contextmodel.ReqContext, teamID int64, current []*team.TeamMemberDTO, members []MultiValued) response.Response {
+	desired := make(map[int64]bool, len(members))
+	for _, member := range members {
+		userID, _ := parseID(member.Value)
+		desired[userID] = true
+	}
+
+	existing := make(map[int64]bool, len(current))
+	for _, member := range current {
+		existing[member.UserID] = true
+		if desired[member.UserID] {
+			continue
+		}
+		err := s.teamService.RemoveTeamMember(c.Req.Context(), &team.RemoveTeamMemberCommand{
+			OrgID:  c.OrgID,
+			TeamID: teamID,
+			UserID: member.UserID,
+		})
+		if err != nil && !errors.Is(err, team.ErrTeamMemberNotFound) {
+			return s.internalError("Failed to remove group member", err)
+		}
+	}
+
+	for userID := range desired {
+		if existing[userID] {
+			continue
+		}
+		err := s.teamService.AddTeamMember(userID, c.OrgID, teamID, true, 0)
+		if err != nil && !errors.Is(err, team.ErrTeamMemberAlreadyAdded) {
+			return s.internalError("Failed to add group member", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) groupResponse(c *contextmodel.ReqContext, status int, teamID int64) response.Response {
+	t, err := s.teamService.GetTeamByID(c.Req.Context(), &team.GetTeamByIDQuery{
+		OrgID:        c.OrgID,
+		ID:           teamID,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return s.internalError("Failed to get group", err)
+	}
+	members, err := s.getTeamMembers(c, teamID)
+	if err != nil {
+		return s.internalError("Failed to get group members", err)
+	}
+	return scimResponse(status, s.toGroup(t, members))
+}
+
+// getTeam returns the team of a SCIM id or a SCIM error response
+func (s *Service) getTeam(c *contextmodel.ReqContext, id string) (*team.TeamDTO, response.Response) {
+	teamID, ok := parseID(id)
+	if !ok {
+		return nil, errorResponse(http.StatusNotFound, "", "group not found")
+	}
+
+	t, err := s.teamService.GetTeamByID(c.Req.Context(), &team.GetTeamByIDQuery{
+		OrgID:        c.OrgID,
+		ID:           teamID,
+		SignedInUser: c.SignedInUser,
+	})
+	if errors.Is(err, team.ErrTeamNotFound) {
+		return nil, errorResponse(http.StatusNotFound, "", "group not found")
+	}
+	if err != nil {
+		return nil, s.internalError("Failed to get group", err)
+	}
+	return t, nil
+}
+
+func (s *Service) getTeamMembers(c *contextmodel.ReqContext, teamID int64) ([]*team.TeamMemberDTO, error) {
+	return s.teamService.GetTeamMembers(c.Req.Context(), &team.GetTeamMembersQuery{
+		OrgID:        c.OrgID,
+		TeamID:       teamID,
+		SignedInUser: c.SignedInUser,
+	})
+}
+
+func (s *Service) toGroup(t *team.TeamDTO, members []*team.TeamMemberDTO) Group {
+	id := strconv.FormatInt(t.ID, 10)
+	resource := Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          id,
+		DisplayName: t.Name,
+		Members:     make([]MultiValued, 0, len(members)),
+		Meta: &Meta{
+			ResourceType: "Group",
+			Location:     s.location("Groups", id),
+		},
+	}
+	for _, member := range members {
+		memberID := strconv.FormatInt(member.UserID, 10)
+		resource.Members = append(resource.Members, MultiValued{
+			Value:   memberID,
+			Display: member.Login,
+			Ref:     s.location("Users", memberID),
+		})
+	}
+	return resource
+}
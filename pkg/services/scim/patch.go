@@ -0,0 +1,235 @@
+package scim
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// patchError is returned when a PATCH operation cannot be applied, it carries the SCIM error type
+type patchError struct {
+	scimType string
+	detail   string
+}
+
+func (e *patchError) Error() string {
+	return e.detail
+}
+
+func newPatchError(scimType, format string, args ...interface{}) *patchError {
+	return &patchError{scimType: scimType, detail: fmt.Sprintf(format, args...)}
+}
+
+// ApplyPatch applies the operations of a PATCH request (RFC 7644 section 3.5.2) to the
+// JSON representation of a resource.
+func ApplyPatch(resource map[string]interface{}, operations []PatchOperation) error {
+	for _, operation := range operations {
+		if err := applyOperation(resource, operation); err != nil {
+			return err
+		}
+	}
+
+	// some identity providers send booleans as strings
+	if key := findKey(resource, "active"); resource[key] != nil {
+		if active, ok := asBool(resource[key]); ok {
+			resource[key] = active
+		}
+	}
+	return nil
+}
+
+func applyOperation(resource map[string]interface{}, operation PatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return newPatchError(ErrorTypeInvalidSyntax, "unsupported patch operation %q", operation.Op)
+	}
+
+	if operation.Path == "" {
+		if op == "remove" {
+			return newPatchError(ErrorTypeNoTarget, "path is required for remove operations")
+		}
+		values, ok := operation.Value.(map[string]interface{})
+		if !ok {
+			return newPatchError(ErrorTypeInvalidValue, "value must be an object when path is omitted")
+		}
+		for path, value := range values {
+			if err := applyPath(resource, op, path, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return applyPath(resource, op, operation.Path, operation.Value)
+}
+
+func applyPath(resource map[string]interface{}, op, path string, value interface{}) error {
+	attr, filter, sub, err := parsePatchPath(path)
+	if err != nil {
+		return err
+	}
+
+	if filter != nil {
+		return applyFiltered(resource, op, attr, filter, sub, value)
+	}
+
+	if sub != "" {
+		parent, ok := resource[findKey(resource, attr)].(map[string]interface{})
+		if !ok {
+			if op == "remove" {
+				return nil
+			}
+			parent = map[string]interface{}{}
+			resource[findKey(resource, attr)] = parent
+		}
+		if op == "remove" {
+			delete(parent, findKey(parent, sub))
+		} else {
+			parent[findKey(parent, sub)] = value
+		}
+		return nil
+	}
+
+	key := findKey(resource, attr)
+	existing, isList := resource[key].([]interface{})
+
+	switch op {
+	case "remove":
+		// removing entries of a multi-valued attribute by value, e.g. members
+		if list, ok := value.([]interface{}); ok && isList {
+			resource[key] = removeEntries(existing, list)
+			return nil
+		}
+		delete(resource, key)
+	case "add":
+		if list, ok := value.([]interface{}); ok && isList {
+			resource[key] = appendEntries(existing, list)
+			return nil
+		}
+		if current, ok := resource[key].(map[string]interface{}); ok {
+			if values, ok := value.(map[string]interface{}); ok {
+				for k, v := range values {
+					current[findKey(current, k)] = v
+				}
+				return nil
+			}
+		}
+		resource[key] = value
+	case "replace":
+		resource[key] = value
+	}
+	return nil
+}
+
+func applyFiltered(resource map[string]interface{}, op, attr string, filter Filter, sub string, value interface{}) error {
+	key := findKey(resource, attr)
+	existing, _ := resource[key].([]interface{})
+
+	result := make([]interface{}, 0, len(existing))
+	matched := false
+	for _, item := range existing {
+		entry, ok := item.(map[string]interface{})
+		if !ok || !filter.Matches(entry) {
+			result = append(result, item)
+			continue
+		}
+		matched = true
+
+		switch {
+		case op == "remove" && sub == "":
+			continue
+		case op == "remove":
+			delete(entry, findKey(entry, sub))
+		case sub != "":
+			entry[findKey(entry, sub)] = value
+		default:
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return newPatchError(ErrorTypeInvalidValue, "value for %q must be an object", attr)
+			}
+			for k, v := range values {
+				entry[findKey(entry, k)] = v
+			}
+		}
+		result = append(result, entry)
+	}
+
+	if !matched && op != "remove" {
+		// create the entry when the filter identifies it, e.g. emails[type eq "work"].value
+		compare, ok := filter.(compareFilter)
+		if !ok || compare.operator != "eq" || sub == "" {
+			return newPatchError(ErrorTypeNoTarget, "no %q entry matches the filter", attr)
+		}
+		result = append(result, map[string]interface{}{compare.path: compare.value, sub: value})
+	}
+
+	resource[key] = result
+	return nil
+}
+
+// parsePatchPath splits a path like emails[type eq "work"].value into its attribute, value filter and sub-attribute
+func parsePatchPath(path string) (string, Filter, string, error) {
+	path = stripSchema(path)
+
+	open := strings.Index(path, "[")
+	if open < 0 {
+		if attr, sub, ok := strings.Cut(path, "."); ok {
+			return attr, nil, sub, nil
+		}
+		return path, nil, "", nil
+	}
+
+	end := strings.LastIndex(path, "]")
+	if end < open {
+		return "", nil, "", newPatchError(ErrorTypeInvalidPath, "invalid path %q", path)
+	}
+
+	filter, err := ParseFilter(path[open+1 : end])
+	if err != nil {
+		return "", nil, "", newPatchError(ErrorTypeInvalidPath, "invalid filter in path %q: %s", path, err)
+	}
+
+	sub := strings.TrimPrefix(path[end+1:], ".")
+	return path[:open], filter, sub, nil
+}
+
+func entryValue(item interface{}) interface{} {
+	if entry, ok := item.(map[string]interface{}); ok {
+		return entry[findKey(entry, "value")]
+	}
+	return item
+}
+
+func appendEntries(existing []interface{}, entries []interface{}) []interface{} {
+	for _, entry := range entries {
+		found := false
+		value := entryValue(entry)
+		for _, item := range existing {
+			if value != nil && reflect.DeepEqual(entryValue(item), value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, entry)
+		}
+	}
+	return existing
+}
+
+func removeEntries(existing []interface{}, entries []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(existing))
+	for _, item := range existing {
+		remove := false
+		for _, entry := range entries {
+			if reflect.DeepEqual(entryValue(item), entryValue(entry)) {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			result = append(result, item)
+		}
+	}
+	return result
+}
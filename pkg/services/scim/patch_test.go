@@ -0,0 +1,120 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPatchTestUser() map[string]interface{} {
+	return map[string]interface{}{
+		"userName":    "bjensen",
+		"displayName": "Barbara Jensen",
+		"active":      true,
+		"emails": []interface{}{
+			map[string]interface{}{"value": "bjensen@example.com", "type": "work", "primary": true},
+		},
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Run("replace attribute by path", func(t *testing.T) {
+		resource := newPatchTestUser()
+		err := ApplyPatch(resource, []PatchOperation{{Op: "replace", Path: "displayName", Value: "Babs"}})
+		require.NoError(t, err)
+		assert.Equal(t, "Babs", resource["displayName"])
+	})
+
+	t.Run("replace without path and string boolean", func(t *testing.T) {
+		resource := newPatchTestUser()
+		err := ApplyPatch(resource, []PatchOperation{{Op: "Replace", Value: map[string]interface{}{"active": "False"}}})
+		require.NoError(t, err)
+		assert.Equal(t, false, resource["active"])
+	})
+
+	t.Run("replace without path with dotted attribute", func(t *testing.T) {
+		resource := newPatchTestUser()
+		err := ApplyPatch(resource, []PatchOperation{{Op: "replace", Value: map[string]interface{}{"name.givenName": "Barbara"}}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"givenName": "Barbara"}, resource["name"])
+	})
+
+	t.Run("replace sub-attribute of filtered entry", func(t *testing.T) {
+		resource := newPatchTestUser()
+		err := ApplyPatch(resource, []PatchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: "babs@example.com"}})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"value": "babs@example.com", "type": "work", "primary": true},
+		}, resource["emails"])
+	})
+
+	t.Run("add creates entry identified by filter", func(t *testing.T) {
+		resource := newPatchTestUser()
+		err := ApplyPatch(resource, []PatchOperation{{Op: "add", Path: `emails[type eq "home"].value`, Value: "babs@jensen.org"}})
+		require.NoError(t, err)
+		assert.Len(t, resource["emails"], 2)
+	})
+
+	t.Run("add appends members without duplicates", func(t *testing.T) {
+		resource := map[string]interface{}{"members": []interface{}{map[string]interface{}{"value": "1"}}}
+		err := ApplyPatch(resource, []PatchOperation{{Op: "add", Path: "members", Value: []interface{}{
+			map[string]interface{}{"value": "1"},
+			map[string]interface{}{"value": "2"},
+		}}})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"value": "1"},
+			map[string]interface{}{"value": "2"},
+		}, resource["members"])
+	})
+
+	t.Run("remove member by filter", func(t *testing.T) {
+		resource := map[string]interface{}{"members": []interface{}{
+			map[string]interface{}{"value": "1"},
+			map[string]interface{}{"value": "2"},
+		}}
+		err := ApplyPatch(resource, []PatchOperation{{Op: "remove", Path: `members[value eq "1"]`}})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{map[string]interface{}{"value": "2"}}, resource["members"])
+	})
+
+	t.Run("remove member by value", func(t *testing.T) {
+		resource := map[string]interface{}{"members": []interface{}{
+			map[string]interface{}{"value": "1"},
+			map[string]interface{}{"value": "2"},
+		}}
+		err := ApplyPatch(resource, []PatchOperation{{Op: "remove", Path: "members", Value: []interface{}{map[string]interface{}{"value": "2"}}}})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{map[string]interface{}{"value": "1"}}, resource["members"])
+	})
+
+	t.Run("remove attribute", func(t *testing.T) {
+		resource := newPatchTestUser()
+		err := ApplyPatch(resource, []PatchOperation{{Op: "remove", Path: "displayName"}})
+		require.NoError(t, err)
+		assert.NotContains(t, resource, "displayName")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			operation PatchOperation
+			scimType  string
+		}{
+			{name: "unknown op", operation: PatchOperation{Op: "move", Path: "userName"}, scimType: ErrorTypeInvalidSyntax},
+			{name: "remove without path", operation: PatchOperation{Op: "remove"}, scimType: ErrorTypeNoTarget},
+			{name: "value is not an object", operation: PatchOperation{Op: "replace", Value: "x"}, scimType: ErrorTypeInvalidValue},
+			{name: "invalid filter", operation: PatchOperation{Op: "replace", Path: `emails[type foo "x"].value`, Value: "x"}, scimType: ErrorTypeInvalidPath},
+			{name: "no target", operation: PatchOperation{Op: "replace", Path: `emails[type ne "work"].value`, Value: "x"}, scimType: ErrorTypeNoTarget},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := ApplyPatch(newPatchTestUser(), []PatchOperation{tt.operation})
+				var patchErr *patchError
+				require.ErrorAs(t, err, &patchErr)
+				assert.Equal(t, tt.scimType, patchErr.scimType)
+			})
+		}
+	})
+}
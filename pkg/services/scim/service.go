@@ -0,0 +1,258 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Service is a SCIM 2.0 server (RFC 7644) that lets identity providers provision users and groups.
+// Users are mapped to members of the org of the calling service account and groups to teams.
+type Service struct {
+	cfg            *setting.Cfg
+	log            log.Logger
+	accessControl  ac.AccessControl
+	userService    user.Service
+	orgService     org.Service
+	teamService    team.Service
+	sessionService auth.UserTokenService
+}
+
+func ProvideService(cfg *setting.Cfg, router routing.RouteRegister, accessControl ac.AccessControl,
+	userService user.Service, orgService org.Service, teamService team.Service,
+	sessionService auth.UserTokenService) *Service {
+	s := &Service{
+		cfg:            cfg,
+		log:            log.New("scim"),
+		accessControl:  accessControl,
+		userService:    userService,
+		orgService:     orgService,
+		teamService:    teamService,
+		sessionService: sessionService,
+	}
+
+	if !cfg.SCIM.Enabled {
+		return s
+	}
+
+	authorize := ac.Middleware(accessControl)
+	reqOrgAdmin := middleware.ReqOrgAdmin
+
+	router.Group("/api/scim/v2", func(scimRoute routing.RouteRegister) {
+		scimRoute.Get("/ServiceProviderConfig", routing.Wrap(s.GetServiceProviderConfig))
+		scimRoute.Get("/ResourceTypes", routing.Wrap(s.GetResourceTypes))
+
+		scimRoute.Get("/Users", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgUsersRead)), routing.Wrap(s.ListUsers))
+		scimRoute.Post("/Users", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgUsersAdd)), routing.Wrap(s.CreateUser))
+		scimRoute.Get("/Users/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgUsersRead)), routing.Wrap(s.GetUser))
+		scimRoute.Put("/Users/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgUsersWrite)), routing.Wrap(s.ReplaceUser))
+		scimRoute.Patch("/Users/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgUsersWrite)), routing.Wrap(s.PatchUser))
+		scimRoute.Delete("/Users/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionOrgUsersRemove)), routing.Wrap(s.DeleteUser))
+
+		scimRoute.Get("/Groups", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionTeamsRead)), routing.Wrap(s.ListGroups))
+		scimRoute.Post("/Groups", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionTeamsCreate)), routing.Wrap(s.CreateGroup))
+		scimRoute.Get("/Groups/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionTeamsRead)), routing.Wrap(s.GetGroup))
+		scimRoute.Put("/Groups/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionTeamsWrite)), routing.Wrap(s.ReplaceGroup))
+		scimRoute.Patch("/Groups/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionTeamsWrite)), routing.Wrap(s.PatchGroup))
+		scimRoute.Delete("/Groups/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionTeamsDelete)), routing.Wrap(s.DeleteGroup))
+	}, middleware.ReqSignedIn, requireServiceAccount)
+
+	return s
+}
+
+// requireServiceAccount only lets service account tokens use the SCIM API, identity providers
+// authenticate with a bearer token and provisioning must not depend on a user session.
+func requireServiceAccount(c *contextmodel.ReqContext) {
+	if !c.SignedInUser.IsServiceAccountUser() {
+		errorResponse(http.StatusForbidden, "", "SCIM requests must be authenticated with a service account token").WriteTo(c)
+	}
+}
+
+func (s *Service) GetServiceProviderConfig(c *contextmodel.ReqContext) response.Response {
+	return scimResponse(http.StatusOK, map[string]interface{}{
+		"schemas":        []string{SchemaServiceProviderConfig},
+		"patch":          map[string]interface{}{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": maxResults},
+		"changePassword": map[string]interface{}{"supported": false},
+		"sort":           map[string]interface{}{"supported": false},
+		"etag":           map[string]interface{}{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "Service account token",
+			"description": "Authentication with a Grafana service account token",
+			"primary":     true,
+		}},
+		"meta": Meta{ResourceType: "ServiceProviderConfig", Location: s.location("ServiceProviderConfig")},
+	})
+}
+
+func (s *Service) GetResourceTypes(c *contextmodel.ReqContext) response.Response {
+	resourceTypes := []interface{}{
+		map[string]interface{}{
+			"schemas":  []string{SchemaResourceType},
+			"id":       "User",
+			"name":     "User",
+			"endpoint": "/Users",
+			"schema":   SchemaUser,
+			"meta":     Meta{ResourceType: "ResourceType", Location: s.location("ResourceTypes", "User")},
+		},
+		map[string]interface{}{
+			"schemas":  []string{SchemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   SchemaGroup,
+			"meta":     Meta{ResourceType: "ResourceType", Location: s.location("ResourceTypes", "Group")},
+		},
+	}
+
+	return scimResponse(http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+// maxResults is the page size used when the identity provider does not request one
+const maxResults = 1000
+
+// listResponse filters and paginates resources according to the filter, startIndex and count query parameters
+func listResponse(c *contextmodel.ReqContext, resources []interface{}) response.Response {
+	if expression := c.Query("filter"); expression != "" {
+		filter, err := ParseFilter(expression)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, ErrorTypeInvalidFilter, err.Error())
+		}
+
+		filtered := make([]interface{}, 0, len(resources))
+		for _, resource := range resources {
+			values, err := toMap(resource)
+			if err != nil {
+				return errorResponse(http.StatusInternalServerError, "", "failed to evaluate filter")
+			}
+			if filter.Matches(values) {
+				filtered = append(filtered, resource)
+			}
+		}
+		resources = filtered
+	}
+
+	startIndex := c.QueryInt("startIndex")
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count := maxResults
+	if c.Query("count") != "" {
+		count = c.QueryInt("count")
+		if count < 0 {
+			count = 0
+		}
+	}
+
+	total := len(resources)
+	page := make([]interface{}, 0)
+	if startIndex <= total {
+		end := startIndex - 1 + count
+		if end > total {
+			end = total
+		}
+		page = resources[startIndex-1 : end]
+	}
+
+	return scimResponse(http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+func (s *Service) location(parts ...string) string {
+	return strings.TrimSuffix(s.cfg.AppURL, "/") + "/api/scim/v2/" + strings.Join(parts, "/")
+}
+
+func scimResponse(status int, body interface{}) response.Response {
+	return response.JSON(status, body).SetHeader("Content-Type", ContentType)
+}
+
+func errorResponse(status int, scimType, detail string) *response.NormalResponse {
+	return response.JSON(status, Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}).SetHeader("Content-Type", ContentType)
+}
+
+func (s *Service) internalError(message string, err error) response.Response {
+	s.log.Error(message, "error", err)
+	return errorResponse(http.StatusInternalServerError, "", message)
+}
+
+func patchErrorResponse(err error) response.Response {
+	var patchErr *patchError
+	if errors.As(err, &patchErr) {
+		return errorResponse(http.StatusBadRequest, patchErr.scimType, patchErr.detail)
+	}
+	return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, err.Error())
+}
+
+// decodeBody decodes a SCIM request, web.Bind is not used since it only accepts application/json
+func decodeBody(c *contextmodel.ReqContext, v interface{}) error {
+	if contentType := c.Req.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != ContentType && mediaType != "application/json") {
+			return fmt.Errorf("unsupported content type %q", contentType)
+		}
+	}
+	if err := json.NewDecoder(c.Req.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// toMap returns the JSON representation of a resource that filters and patches are applied to
+func toMap(resource interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func fromMap(values map[string]interface{}, resource interface{}) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, resource)
+}
+
+func parseID(id string) (int64, bool) {
+	value, err := strconv.ParseInt(id, 10, 64)
+	return value, err == nil && value > 0
+}
@@ -0,0 +1,382 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func setupAPITest(t *testing.T, opts ...func(s *Service)) (*Service, *webtest.Server) {
+	t.Helper()
+	router := routing.NewRouteRegister()
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.SCIM = setting.SCIMSettings{Enabled: true, DefaultOrgRole: "Viewer"}
+
+	s := ProvideService(cfg,
+		router,
+		acimpl.ProvideAccessControl(cfg),
+		usertest.NewUserServiceFake(),
+		&orgtest.FakeOrgService{},
+		teamtest.NewFakeService(),
+		authtest.NewFakeUserAuthTokenService(),
+	)
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s, webtest.NewServer(t, router)
+}
+
+func serviceAccount(permissions ...string) *user.SignedInUser {
+	scopes := map[string][]string{}
+	for _, permission := range permissions {
+		scopes[permission] = []string{"*"}
+	}
+	return &user.SignedInUser{
+		UserID:           10,
+		OrgID:            1,
+		OrgRole:          org.RoleViewer,
+		IsServiceAccount: true,
+		Permissions:      map[int64]map[string][]string{1: scopes},
+	}
+}
+
+func sendRequest(t *testing.T, server *webtest.Server, method, url, body string, signedInUser *user.SignedInUser) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	req := server.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", ContentType)
+	webtest.RequestWithSignedInUser(req, signedInUser)
+
+	res, err := server.Send(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+
+	result := map[string]interface{}{}
+	if res.StatusCode != http.StatusNoContent {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+	}
+	return res, result
+}
+
+func TestService_Authorization(t *testing.T) {
+	_, server := setupAPITest(t)
+
+	t.Run("should reject users that are not service accounts", func(t *testing.T) {
+		usr := serviceAccount(accesscontrol.ActionOrgUsersRead)
+		usr.IsServiceAccount = false
+		res, body := sendRequest(t, server, http.MethodGet, "/api/scim/v2/Users", "", usr)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		assert.Equal(t, ContentType, res.Header.Get("Content-Type"))
+		assert.Equal(t, []interface{}{SchemaError}, body["schemas"])
+	})
+
+	t.Run("should reject service accounts without permission", func(t *testing.T) {
+		res, _ := sendRequest(t, server, http.MethodGet, "/api/scim/v2/Users", "", serviceAccount())
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should not register routes when disabled", func(t *testing.T) {
+		router := routing.NewRouteRegister()
+		cfg := setting.NewCfg()
+		ProvideService(cfg, router, acimpl.ProvideAccessControl(cfg), usertest.NewUserServiceFake(),
+			&orgtest.FakeOrgService{}, teamtest.NewFakeService(), authtest.NewFakeUserAuthTokenService())
+		server := webtest.NewServer(t, router)
+
+		req := server.NewGetRequest("/api/scim/v2/Users")
+		webtest.RequestWithSignedInUser(req, serviceAccount(accesscontrol.ActionOrgUsersRead))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestService_Users(t *testing.T) {
+	orgUsers := []*org.OrgUserDTO{
+		{OrgID: 1, UserID: 1, Login: "alice", Email: "alice@example.com", Name: "Alice"},
+		{OrgID: 1, UserID: 2, Login: "bob", Email: "bob@example.com", Name: "Bob", IsDisabled: true},
+	}
+
+	t.Run("should list and filter users", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers}
+		})
+
+		res, body := sendRequest(t, server, http.MethodGet, `/api/scim/v2/Users?filter=userName+eq+"Bob"`, "", serviceAccount(accesscontrol.ActionOrgUsersRead))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 1, body["totalResults"])
+		resources := body["Resources"].([]interface{})
+		require.Len(t, resources, 1)
+		bob := resources[0].(map[string]interface{})
+		assert.Equal(t, "2", bob["id"])
+		assert.Equal(t, false, bob["active"])
+	})
+
+	t.Run("should paginate users", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers}
+		})
+
+		res, body := sendRequest(t, server, http.MethodGet, "/api/scim/v2/Users?startIndex=2&count=5", "", serviceAccount(accesscontrol.ActionOrgUsersRead))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 2, body["totalResults"])
+		assert.EqualValues(t, 1, body["itemsPerPage"])
+		assert.EqualValues(t, 2, body["startIndex"])
+	})
+
+	t.Run("should return invalid filter error", func(t *testing.T) {
+		_, server := setupAPITest(t)
+
+		res, body := sendRequest(t, server, http.MethodGet, `/api/scim/v2/Users?filter=userName+foo`, "", serviceAccount(accesscontrol.ActionOrgUsersRead))
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, ErrorTypeInvalidFilter, body["scimType"])
+	})
+
+	t.Run("should return not found for users outside the org", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers}
+		})
+
+		res, _ := sendRequest(t, server, http.MethodGet, "/api/scim/v2/Users/3", "", serviceAccount(accesscontrol.ActionOrgUsersRead))
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should create user and add it to the org", func(t *testing.T) {
+		var created *user.CreateUserCommand
+		_, server := setupAPITest(t, func(s *Service) {
+			userService := usertest.NewUserServiceFake()
+			userService.ExpectedError = user.ErrUserNotFound
+			userService.CreateFn = func(ctx context.Context, cmd *user.CreateUserCommand) (*user.User, error) {
+				created = cmd
+				return &user.User{ID: 1, Login: cmd.Login}, nil
+			}
+			s.userService = userService
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers[:1]}
+		})
+
+		res, body := sendRequest(t, server, http.MethodPost, "/api/scim/v2/Users", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "alice",
+			"name": {"givenName": "Alice", "familyName": "Smith"},
+			"emails": [{"value": "alice@example.com", "primary": true}]
+		}`, serviceAccount(accesscontrol.ActionOrgUsersAdd, accesscontrol.ActionUsersCreate))
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.NotNil(t, created)
+		assert.Equal(t, "alice", created.Login)
+		assert.Equal(t, "alice@example.com", created.Email)
+		assert.Equal(t, "Alice Smith", created.Name)
+		assert.True(t, created.SkipOrgSetup)
+		assert.Equal(t, "1", body["id"])
+	})
+
+	t.Run("should require the global permission to create users", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			userService := usertest.NewUserServiceFake()
+			userService.ExpectedError = user.ErrUserNotFound
+			userService.CreateFn = func(ctx context.Context, cmd *user.CreateUserCommand) (*user.User, error) {
+				t.Fatal("user should not be created")
+				return nil, nil
+			}
+			s.userService = userService
+		})
+
+		res, _ := sendRequest(t, server, http.MethodPost, "/api/scim/v2/Users", `{"userName": "alice"}`, serviceAccount(accesscontrol.ActionOrgUsersAdd))
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should reject existing org member", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			userService := usertest.NewUserServiceFake()
+			userService.ExpectedUser = &user.User{ID: 1, Login: "alice"}
+			s.userService = userService
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers[:1]}
+		})
+
+		res, body := sendRequest(t, server, http.MethodPost, "/api/scim/v2/Users", `{"userName": "alice"}`, serviceAccount(accesscontrol.ActionOrgUsersAdd))
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, ErrorTypeUniqueness, body["scimType"])
+	})
+
+	t.Run("should deactivate user and revoke sessions", func(t *testing.T) {
+		var disabled *user.DisableUserCommand
+		var revoked int64
+		_, server := setupAPITest(t, func(s *Service) {
+			userService := usertest.NewUserServiceFake()
+			userService.ExpectedUser = &user.User{ID: 1, Login: "alice"}
+			userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+				disabled = cmd
+				return nil
+			}
+			s.userService = userService
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers[:1], ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1}}}
+			sessionService := authtest.NewFakeUserAuthTokenService()
+			sessionService.RevokeAllUserTokensProvider = func(ctx context.Context, userID int64) error {
+				revoked = userID
+				return nil
+			}
+			s.sessionService = sessionService
+		})
+
+		res, _ := sendRequest(t, server, http.MethodPatch, "/api/scim/v2/Users/1", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "Replace", "path": "active", "value": "False"}]
+		}`, serviceAccount(accesscontrol.ActionOrgUsersWrite))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NotNil(t, disabled)
+		assert.True(t, disabled.IsDisabled)
+		assert.EqualValues(t, 1, revoked)
+	})
+
+	for _, tc := range []struct {
+		desc string
+		user *user.User
+		orgs []*org.UserOrgDTO
+	}{
+		{desc: "server admins", user: &user.User{ID: 1, Login: "alice", IsAdmin: true}, orgs: []*org.UserOrgDTO{{OrgID: 1}}},
+		{desc: "members of other orgs", user: &user.User{ID: 1, Login: "alice"}, orgs: []*org.UserOrgDTO{{OrgID: 1}, {OrgID: 2}}},
+	} {
+		t.Run("should not change "+tc.desc+" without the global permission", func(t *testing.T) {
+			updated := false
+			disabled := false
+			_, server := setupAPITest(t, func(s *Service) {
+				userService := usertest.NewUserServiceFake()
+				userService.ExpectedUser = tc.user
+				userService.UpdateFn = func(ctx context.Context, cmd *user.UpdateUserCommand) error {
+					updated = true
+					return nil
+				}
+				userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+					disabled = true
+					return nil
+				}
+				s.userService = userService
+				s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: orgUsers[:1], ExpectedUserOrgDTO: tc.orgs}
+			})
+
+			body := `{
+				"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+				"Operations": [{"op": "Replace", "path": "active", "value": "False"}]
+			}`
+			res, _ := sendRequest(t, server, http.MethodPatch, "/api/scim/v2/Users/1", body, serviceAccount(accesscontrol.ActionOrgUsersWrite))
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assert.False(t, updated)
+			assert.False(t, disabled)
+
+			res, _ = sendRequest(t, server, http.MethodPatch, "/api/scim/v2/Users/1", body,
+				serviceAccount(accesscontrol.ActionOrgUsersWrite, accesscontrol.ActionUsersWrite, accesscontrol.ActionUsersDisable))
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.True(t, updated)
+			assert.True(t, disabled)
+		})
+	}
+
+	t.Run("should remove user from org and revoke sessions", func(t *testing.T) {
+		var revoked int64
+		_, server := setupAPITest(t, func(s *Service) {
+			userService := usertest.NewUserServiceFake()
+			userService.ExpectedUser = &user.User{ID: 1, Login: "alice"}
+			s.userService = userService
+			s.orgService = &orgtest.FakeOrgService{
+				ExpectedOrgUsers:        orgUsers[:1],
+				ExpectedOrgListResponse: orgtest.OrgListResponse{{OrgID: 1, Response: nil}},
+			}
+			sessionService := authtest.NewFakeUserAuthTokenService()
+			sessionService.RevokeAllUserTokensProvider = func(ctx context.Context, userID int64) error {
+				revoked = userID
+				return nil
+			}
+			s.sessionService = sessionService
+		})
+
+		res, _ := sendRequest(t, server, http.MethodDelete, "/api/scim/v2/Users/1", "", serviceAccount(accesscontrol.ActionOrgUsersRemove))
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.EqualValues(t, 1, revoked)
+	})
+}
+
+func TestService_Groups(t *testing.T) {
+	t.Run("should get group with members", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.teamService = &teamtest.FakeService{
+				ExpectedTeamDTO: &team.TeamDTO{ID: 5, OrgID: 1, Name: "Engineering"},
+				ExpectedMembers: []*team.TeamMemberDTO{{OrgID: 1, TeamID: 5, UserID: 1, Login: "alice"}},
+			}
+		})
+
+		res, body := sendRequest(t, server, http.MethodGet, "/api/scim/v2/Groups/5", "", serviceAccount(accesscontrol.ActionTeamsRead))
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "Engineering", body["displayName"])
+		members := body["members"].([]interface{})
+		require.Len(t, members, 1)
+		assert.Equal(t, "1", members[0].(map[string]interface{})["value"])
+	})
+
+	t.Run("should return not found for unknown group", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.teamService = &teamtest.FakeService{ExpectedError: team.ErrTeamNotFound}
+		})
+
+		res, _ := sendRequest(t, server, http.MethodGet, "/api/scim/v2/Groups/5", "", serviceAccount(accesscontrol.ActionTeamsRead))
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should reject members that are not in the org", func(t *testing.T) {
+		_, server := setupAPITest(t)
+
+		res, body := sendRequest(t, server, http.MethodPost, "/api/scim/v2/Groups", `{
+			"displayName": "Engineering",
+			"members": [{"value": "7"}]
+		}`, serviceAccount(accesscontrol.ActionTeamsCreate))
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, ErrorTypeInvalidValue, body["scimType"])
+	})
+
+	t.Run("should reject duplicate group", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.teamService = &teamtest.FakeService{ExpectedError: team.ErrTeamNameTaken}
+		})
+
+		res, body := sendRequest(t, server, http.MethodPost, "/api/scim/v2/Groups", `{"displayName": "Engineering"}`, serviceAccount(accesscontrol.ActionTeamsCreate))
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, ErrorTypeUniqueness, body["scimType"])
+	})
+
+	t.Run("should patch group members", func(t *testing.T) {
+		_, server := setupAPITest(t, func(s *Service) {
+			s.orgService = &orgtest.FakeOrgService{ExpectedOrgUsers: []*org.OrgUserDTO{{OrgID: 1, UserID: 2, Login: "bob"}}}
+			s.teamService = &teamtest.FakeService{
+				ExpectedTeamDTO: &team.TeamDTO{ID: 5, OrgID: 1, Name: "Engineering"},
+				ExpectedMembers: []*team.TeamMemberDTO{{OrgID: 1, TeamID: 5, UserID: 1, Login: "alice"}},
+			}
+		})
+
+		res, _ := sendRequest(t, server, http.MethodPatch, "/api/scim/v2/Groups/5", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "remove", "path": "members[value eq \"1\"]"},
+				{"op": "add", "path": "members", "value": [{"value": "2"}]}
+			]
+		}`, serviceAccount(accesscontrol.ActionTeamsWrite))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
@@ -0,0 +1,333 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) ListUsers(c *contextmodel.ReqContext) response.Response {
+	orgUsers, err := s.orgService.GetOrgUsers(c.Req.Context(), &org.GetOrgUsersQuery{
+		OrgID: c.OrgID,
+		User:  c.SignedInUser,
+	})
+	if err != nil {
+		return s.internalError("Failed to list users", err)
+	}
+
+	resources := make([]interface{}, 0, len(orgUsers))
+	for _, orgUser := range orgUsers {
+		resources = append(resources, s.toUser(orgUser))
+	}
+	return listResponse(c, resources)
+}
+
+func (s *Service) GetUser(c *contextmodel.ReqContext) response.Response {
+	orgUser, errResp := s.getOrgUser(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+	return scimResponse(http.StatusOK, s.toUser(orgUser))
+}
+
+// CreateUser adds the user to the org of the service account, the Grafana user is created
+// unless a user with the same login already exists. Creating users requires the global users:create permission.
+func (s *Service) CreateUser(c *contextmodel.ReqContext) response.Response {
+	var resource User
+	if err := decodeBody(c, &resource); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidSyntax, err.Error())
+	}
+	if resource.UserName == "" {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, "userName is required")
+	}
+
+	ctx := c.Req.Context()
+	usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: resource.UserName})
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		canCreate, err := s.accessControl.Evaluate(ctx, c.SignedInUser, ac.EvalPermission(ac.ActionUsersCreate))
+		if err != nil {
+			return s.internalError("Failed to check permissions", err)
+		}
+		if !canCreate {
+			return errorResponse(http.StatusForbidden, "", "creating users requires the users:create permission")
+		}
+
+		usr, err = s.userService.Create(ctx, &user.CreateUserCommand{
+			Login:        resource.UserName,
+			Email:        resource.PrimaryEmail(),
+			Name:         resource.FullName(),
+			IsDisabled:   !resource.IsActive(),
+			SkipOrgSetup: true,
+		})
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return errorResponse(http.StatusConflict, ErrorTypeUniqueness, "a user with this userName or email already exists")
+		}
+		if err != nil {
+			return s.internalError("Failed to create user", err)
+		}
+	case err != nil:
+		return s.internalError("Failed to get user", err)
+	default:
+		if usr.IsServiceAccount {
+			return errorResponse(http.StatusConflict, ErrorTypeUniqueness, "userName is used by a service account")
+		}
+		existing, err := s.findOrgUser(ctx, c, usr.ID)
+		if err != nil {
+			return s.internalError("Failed to get user", err)
+		}
+		if existing != nil {
+			return errorResponse(http.StatusConflict, ErrorTypeUniqueness, "user already exists")
+		}
+	}
+
+	err = s.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{
+		OrgID:  c.OrgID,
+		UserID: usr.ID,
+		Role:   org.RoleType(s.cfg.SCIM.DefaultOrgRole),
+	})
+	if err != nil && !errors.Is(err, org.ErrOrgUserAlreadyAdded) {
+		return s.internalError("Failed to add user to organization", err)
+	}
+
+	orgUser, err := s.findOrgUser(ctx, c, usr.ID)
+	if err != nil || orgUser == nil {
+		return s.internalError("Failed to get user", err)
+	}
+	return scimResponse(http.StatusCreated, s.toUser(orgUser))
+}
+
+func (s *Service) ReplaceUser(c *contextmodel.ReqContext) response.Response {
+	orgUser, errResp := s.getOrgUser(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	var resource User
+	if err := decodeBody(c, &resource); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidSyntax, err.Error())
+	}
+	return s.saveUser(c, orgUser, &resource)
+}
+
+func (s *Service) PatchUser(c *contextmodel.ReqContext) response.Response {
+	orgUser, errResp := s.getOrgUser(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	var patch PatchRequest
+	if err := decodeBody(c, &patch); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidSyntax, err.Error())
+	}
+
+	values, err := toMap(s.toUser(orgUser))
+	if err != nil {
+		return s.internalError("Failed to patch user", err)
+	}
+	if err := ApplyPatch(values, patch.Operations); err != nil {
+		return patchErrorResponse(err)
+	}
+
+	var resource User
+	if err := fromMap(values, &resource); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, err.Error())
+	}
+
+	// the Grafana name is exposed as displayName and name.formatted, use whichever attribute was patched
+	if resource.DisplayName == orgUser.Name {
+		resource.DisplayName = ""
+		if resource.Name != nil && resource.Name.Formatted == orgUser.Name && (resource.Name.GivenName != "" || resource.Name.FamilyName != "") {
+			resource.Name.Formatted = ""
+		}
+	}
+	return s.saveUser(c, orgUser, &resource)
+}
+
+// DeleteUser removes the user from the org, users that are not a member of any other org are deleted.
+// Sessions are revoked so deprovisioned users lose access immediately.
+func (s *Service) DeleteUser(c *contextmodel.ReqContext) response.Response {
+	orgUser, errResp := s.getOrgUser(c, web.Params(c.Req)[":id"])
+	if errResp != nil {
+		return errResp
+	}
+
+	// sessions are shared by all orgs of the user
+	revoke := s.authorizeUserWrite(c, orgUser.UserID, ac.ActionUsersLogout) == nil
+
+	ctx := c.Req.Context()
+	err := s.orgService.RemoveOrgUser(ctx, &org.RemoveOrgUserCommand{
+		OrgID:                    c.OrgID,
+		UserID:                   orgUser.UserID,
+		ShouldDeleteOrphanedUser: true,
+	})
+	if errors.Is(err, org.ErrLastOrgAdmin) {
+		return errorResponse(http.StatusBadRequest, ErrorTypeMutability, err.Error())
+	}
+	if err != nil {
+		return s.internalError("Failed to remove user", err)
+	}
+
+	if revoke {
+		if err := s.sessionService.RevokeAllUserTokens(ctx, orgUser.UserID); err != nil {
+			return s.internalError("Failed to revoke user sessions", err)
+		}
+	}
+
+	return response.Empty(http.StatusNoContent)
+}
+
+func (s *Service) saveUser(c *contextmodel.ReqContext, orgUser *org.OrgUserDTO, resource *User) response.Response {
+	if resource.UserName == "" {
+		return errorResponse(http.StatusBadRequest, ErrorTypeInvalidValue, "userName is required")
+	}
+
+	if errResp := s.authorizeUserWrite(c, orgUser.UserID, ac.ActionUsersWrite); errResp != nil {
+		return errResp
+	}
+
+	email := resource.PrimaryEmail()
+	if email == "" {
+		email = resource.UserName
+	}
+
+	ctx := c.Req.Context()
+	err := s.userService.Update(ctx, &user.UpdateUserCommand{
+		UserID: orgUser.UserID,
+		Login:  resource.UserName,
+		Email:  email,
+		Name:   resource.FullName(),
+	})
+	if err != nil {
+		var conflict *user.ErrCaseInsensitiveLoginConflict
+		if errors.Is(err, user.ErrUserAlreadyExists) || errors.As(err, &conflict) {
+			return errorResponse(http.StatusConflict, ErrorTypeUniqueness, "a user with this userName or email already exists")
+		}
+		return s.internalError("Failed to update user", err)
+	}
+
+	if disabled := !resource.IsActive(); disabled != orgUser.IsDisabled {
+		if errResp := s.authorizeUserWrite(c, orgUser.UserID, ac.ActionUsersDisable); errResp != nil {
+			return errResp
+		}
+		if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: orgUser.UserID, IsDisabled: disabled}); err != nil {
+			return s.internalError("Failed to update user", err)
+		}
+		if disabled {
+			if err := s.sessionService.RevokeAllUserTokens(ctx, orgUser.UserID); err != nil {
+				return s.internalError("Failed to revoke user sessions", err)
+			}
+		}
+	}
+
+	updated, err := s.findOrgUser(ctx, c, orgUser.UserID)
+	if err != nil || updated == nil {
+		return s.internalError("Failed to get user", err)
+	}
+	return scimResponse(http.StatusOK, s.toUser(updated))
+}
+
+// authorizeUserWrite returns an error response if the service account can't change the Grafana user.
+// Users are shared by all orgs, without the global permission only users that are neither server
+// admins nor members of another org can be changed.
+func (s *Service) authorizeUserWrite(c *contextmodel.ReqContext, userID int64, action string) response.Response {
+	ctx := c.Req.Context()
+	scope := ac.Scope("global.users", "id", strconv.FormatInt(userID, 10))
+	hasGlobalPermission, err := s.accessControl.Evaluate(ctx, c.SignedInUser, ac.EvalPermission(action, scope))
+	if err != nil {
+		return s.internalError("Failed to check permissions", err)
+	}
+	if hasGlobalPermission {
+		return nil
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return s.internalError("Failed to get user", err)
+	}
+	if usr.IsAdmin {
+		return errorResponse(http.StatusForbidden, "", "changing server admins requires the "+action+" permission")
+	}
+
+	userOrgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return s.internalError("Failed to get user organizations", err)
+	}
+	for _, userOrg := range userOrgs {
+		if userOrg.OrgID != c.OrgID {
+			return errorResponse(http.StatusForbidden, "", "changing members of other organizations requires the "+action+" permission")
+		}
+	}
+	return nil
+}
+
+// getOrgUser returns the org user of a SCIM id or a SCIM error response
+func (s *Service) getOrgUser(c *contextmodel.ReqContext, id string) (*org.OrgUserDTO, response.Response) {
+	userID, ok := parseID(id)
+	if !ok {
+		return nil, errorResponse(http.StatusNotFound, "", "user not found")
+	}
+
+	orgUser, err := s.findOrgUser(c.Req.Context(), c, userID)
+	if err != nil {
+		return nil, s.internalError("Failed to get user", err)
+	}
+	if orgUser == nil {
+		return nil, errorResponse(http.StatusNotFound, "", "user not found")
+	}
+	return orgUser, nil
+}
+
+func (s *Service) findOrgUser(ctx context.Context, c *contextmodel.ReqContext, userID int64) (*org.OrgUserDTO, error) {
+	orgUsers, err := s.orgService.GetOrgUsers(ctx, &org.GetOrgUsersQuery{
+		OrgID:  c.OrgID,
+		UserID: userID,
+		User:   c.SignedInUser,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, orgUser := range orgUsers {
+		if orgUser.UserID == userID {
+			return orgUser, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Service) toUser(orgUser *org.OrgUserDTO) User {
+	id := strconv.FormatInt(orgUser.UserID, 10)
+	active := !orgUser.IsDisabled
+	resource := User{
+		Schemas:     []string{SchemaUser},
+		ID:          id,
+		UserName:    orgUser.Login,
+		DisplayName: orgUser.Name,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Location:     s.location("Users", id),
+		},
+	}
+	if orgUser.Name != "" {
+		resource.Name = &Name{Formatted: orgUser.Name}
+	}
+	if orgUser.Email != "" {
+		resource.Emails = []MultiValued{{Value: orgUser.Email, Type: "work", Primary: true}}
+	}
+	if !orgUser.Created.IsZero() {
+		resource.Meta.Created = &orgUser.Created
+	}
+	if !orgUser.Updated.IsZero() {
+		resource.Meta.LastModified = &orgUser.Updated
+	}
+	return resource
+}
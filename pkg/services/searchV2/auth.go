@@ -70,7 +70,7 @@ func (a *simpleAuthService) GetDashboardReadFilter(ctx context.Context, orgID in
 		sql, params := filter.Where()
 		sess.Table("dashboard").
 			Where(sql, params...).
-			Where("org_id = ?", user.OrgID).
+			Where("org_id = ? AND deleted IS NULL", user.OrgID).
 			Cols("uid")
 
 		err := sess.Find(&rows)
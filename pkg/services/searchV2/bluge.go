@@ -29,6 +29,7 @@ const (
 	documentFieldName_sort   = "name_sort"
 	documentFieldName_ngram  = "name_ngram"
 	documentFieldLocation    = "location" // parent path
+	documentFieldFolder      = "folder"   // folder UID, only indexed for aggregations
 	documentFieldPanelType   = "panel_type"
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
@@ -110,7 +111,7 @@ func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDas
 			location += "/"
 		}
 		location += dash.uid
-		docs := getDashboardPanelDocs(dash, location)
+		docs := getDashboardPanelDocs(dash, folderUID, location)
 
 		for _, panelDoc := range docs {
 			batch.Insert(panelDoc)
@@ -157,6 +158,7 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 	doc := newSearchDocument(dash.uid, dash.summary.Name, dash.summary.Description, url).
 		AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindDashboard)).Aggregatable().StoreValue()).
 		AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
+		AddField(bluge.NewKeywordField(documentFieldFolder, location).Aggregatable()).
 		AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, dash.created).Sortable().StoreValue()).
 		AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, dash.updated).Sortable().StoreValue())
 
@@ -188,7 +190,9 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 	return doc
 }
 
-func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
+// getDashboardPanelDocs returns the documents of the panels of a dashboard in a folder. The location of
+// the panels is the location of the dashboard followed by its UID.
+func getDashboardPanelDocs(dash dashboard, folderUID, location string) []*bluge.Document {
 	dashURL := fmt.Sprintf("/d/%s/%s", dash.uid, slugify.Slugify(dash.summary.Name))
 
 	var docs []*bluge.Document
@@ -205,6 +209,7 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 		url := fmt.Sprintf("%s?viewPanel=%d", dashURL, panelId)
 		doc := newSearchDocument(panel.UID, panel.Name, panel.Description, url).
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
+			AddField(bluge.NewKeywordField(documentFieldFolder, folderUID).Aggregatable()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
 		for _, ref := range panel.References {
@@ -378,6 +383,16 @@ func doSearchQuery(
 	response := &backend.DataResponse{}
 	header := &customMeta{}
 
+	facetFields := make([]string, len(q.Facet))
+	for i, t := range q.Facet {
+		field, ok := facetFieldLookup[t.Field]
+		if !ok {
+			response.Error = fmt.Errorf("%w: %q", ErrInvalidFacetField, t.Field)
+			return response
+		}
+		facetFields[i] = field
+	}
+
 	reader, cancel, err := index.readerForIndex(indexTypeDashboard)
 	if err != nil {
 		logger.Error("error getting reader for dashboard index: %v", err)
@@ -482,12 +497,12 @@ func doSearchQuery(
 		header.SortBy = strings.TrimPrefix(q.Sort, "-")
 	}
 
-	for _, t := range q.Facet {
+	for i, t := range q.Facet {
 		lim := t.Limit
 		if lim < 1 {
 			lim = 50
 		}
-		req.AddAggregation(t.Field, aggregations.NewTermsAggregation(search.Field(t.Field), lim))
+		req.AddAggregation(t.Field, aggregations.NewTermsAggregation(search.Field(facetFields[i]), lim))
 	}
 
 	// execute this search on the reader
@@ -59,6 +59,10 @@ func (s *searchHTTPService) doQuery(c *contextmodel.ReqContext) response.Respons
 
 	resp := s.search.doDashboardQuery(c.Req.Context(), c.SignedInUser, c.OrgID, *query)
 
+	if errors.Is(resp.Error, ErrInvalidFacetField) {
+		return response.Error(400, resp.Error.Error(), resp.Error)
+	}
+
 	if resp.Error != nil {
 		return response.Error(500, "error handling search request", resp.Error)
 	}
@@ -777,7 +777,7 @@ func (i *searchIndex) updateDashboard(ctx context.Context, orgID int64, index *o
 		location += "/"
 	}
 	location += dash.uid
-	panelDocs := getDashboardPanelDocs(dash, location)
+	panelDocs := getDashboardPanelDocs(dash, folderUID, location)
 	actualPanelIDs := make([]string, 0, len(panelDocs))
 	for _, panelDoc := range panelDocs {
 		actualPanelIDs = append(actualPanelIDs, string(panelDoc.ID().Term()))
@@ -844,8 +844,9 @@ func (l sqlDashboardLoader) loadAllDashboards(ctx context.Context, limit int, or
 
 			rows := make([]*dashboardQueryResult, 0)
 			err := l.sql.WithDbSession(dashboardQueryCtx, func(sess *db.Session) error {
+				// dashboards in the trash are not searchable
 				sess.Table("dashboard").
-					Where("org_id = ?", orgID)
+					Where("org_id = ? AND deleted IS NULL", orgID)
 
 				if lastID > 0 {
 					sess.Where("id > ?", lastID)
@@ -731,3 +731,97 @@ func TestDashboardIndex_MultiTermPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+var dashboardsWithFacets = []dashboard{
+	{
+		id:       1,
+		uid:      "1",
+		isFolder: true,
+		summary: &entity.EntitySummary{
+			Name: "My folder",
+		},
+	},
+	{
+		id:       2,
+		uid:      "2",
+		folderID: 1,
+		summary: &entity.EntitySummary{
+			Name:   "Dashboard in folder",
+			Labels: map[string]string{"prod": "", "team-a": ""},
+			References: []*entity.EntityExternalReference{
+				{Family: entity.StandardKindDataSource, Type: "prometheus", Identifier: "prom-1"},
+			},
+		},
+	},
+	{
+		id:  3,
+		uid: "3",
+		summary: &entity.EntitySummary{
+			Name:   "Dashboard in general",
+			Labels: map[string]string{"prod": ""},
+			References: []*entity.EntityExternalReference{
+				{Family: entity.StandardKindDataSource, Type: "prometheus", Identifier: "prom-1"},
+				{Family: entity.StandardKindDataSource, Type: "loki", Identifier: "loki-1"},
+			},
+		},
+	},
+}
+
+func TestDashboardIndex_Facets(t *testing.T) {
+	t.Run("facets-counts", func(t *testing.T) {
+		index := initTestOrgIndexFromDashes(t, dashboardsWithFacets)
+		checkSearchResponse(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{
+				Query: "Dashboard",
+				Facet: []FacetField{{Field: "tags"}, {Field: "folder"}, {Field: "datasource"}, {Field: "ds_type"}, {Field: "kind"}},
+			},
+		)
+	})
+	t.Run("facets-permission-filtered", func(t *testing.T) {
+		index := initTestOrgIndexFromDashes(t, dashboardsWithFacets)
+		filter := func(kind entityKind, uid, parent string) bool {
+			return uid != "3"
+		}
+		resp := doSearchQuery(context.Background(), testLogger, index, filter,
+			DashboardQuery{Query: "Dashboard", Facet: []FacetField{{Field: "tag"}}},
+			&NoopQueryExtender{}, "")
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 2)
+		tags := resp.Frames[1]
+		require.Equal(t, "Facet: tag", tags.Name)
+		require.Equal(t, 2, tags.Rows())
+		require.Equal(t, uint64(1), tags.Fields[1].At(0))
+		require.Equal(t, uint64(1), tags.Fields[1].At(1))
+	})
+	t.Run("facets-panels", func(t *testing.T) {
+		dashboards := make([]dashboard, 0, len(dashboardsWithFacets))
+		for _, dash := range dashboardsWithFacets {
+			if !dash.isFolder {
+				summary := *dash.summary
+				summary.Nested = []*entity.EntitySummary{newNestedPanel(dash.id, dash.id, "Panel")}
+				dash.summary = &summary
+			}
+			dashboards = append(dashboards, dash)
+		}
+		index := initTestOrgIndexFromDashes(t, dashboards)
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter,
+			DashboardQuery{Query: "Panel", Kind: []string{string(entityKindPanel)}, Facet: []FacetField{{Field: "folder"}}},
+			&NoopQueryExtender{}, "")
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 2)
+		folders := resp.Frames[1]
+		require.Equal(t, "Facet: folder", folders.Name)
+		require.Equal(t, 2, folders.Rows())
+		require.Equal(t, "1", folders.Fields[0].At(0))
+		require.Equal(t, uint64(1), folders.Fields[1].At(0))
+		require.Equal(t, "general", folders.Fields[0].At(1))
+		require.Equal(t, uint64(1), folders.Fields[1].At(1))
+	})
+	t.Run("facets-unknown-field", func(t *testing.T) {
+		index := initTestOrgIndexFromDashes(t, dashboardsWithFacets)
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter,
+			DashboardQuery{Query: "Dashboard", Facet: []FacetField{{Field: "name"}}},
+			&NoopQueryExtender{}, "")
+		require.ErrorIs(t, resp.Error, ErrInvalidFacetField)
+	})
+}
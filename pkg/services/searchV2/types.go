@@ -2,6 +2,7 @@ package searchV2
 
 import (
 	"context"
+	"errors"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
@@ -9,6 +10,24 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
+// ErrInvalidFacetField is returned when a query asks for a facet that is not aggregatable.
+var ErrInvalidFacetField = errors.New("invalid facet field")
+
+// facetFieldLookup maps the facet names accepted in a query to the indexed field they aggregate.
+// The aggregations run over the same (permission filtered) query as the results.
+var facetFieldLookup = map[string]string{
+	"kind":        documentFieldKind,
+	"tag":         documentFieldTag,
+	"tags":        documentFieldTag,
+	"folder":      documentFieldFolder,
+	"location":    documentFieldLocation,
+	"panel_type":  documentFieldPanelType,
+	"datasource":  documentFieldDSUID,
+	"ds_uid":      documentFieldDSUID,
+	"ds_type":     documentFieldDSType,
+	"transformer": documentFieldTransformer,
+}
+
 type FacetField struct {
 	Field string `json:"field"`
 	Limit int    `json:"limit,omitempty"` // explicit page size
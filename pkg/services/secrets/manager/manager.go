@@ -447,6 +447,11 @@ func (s *SecretsService) GetProviders() map[secrets.ProviderID]secrets.Provider
 	return s.providers
 }
 
+// CurrentProviderID returns the provider used to encrypt new data keys
+func (s *SecretsService) CurrentProviderID() secrets.ProviderID {
+	return s.currentProviderID
+}
+
 func (s *SecretsService) RotateDataKeys(ctx context.Context) error {
 	s.log.Info("Data keys rotation triggered, acquiring lock...")
 
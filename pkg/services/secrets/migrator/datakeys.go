@@ -0,0 +1,83 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// ReEncryptDataKeys re-encrypts the data keys encrypted by a provider other than the current one with the current
+// provider. After changing the encryption_provider, the previous provider can be removed once it succeeds.
+// Keys that can't be re-encrypted, for example because their provider isn't configured, don't stop the process,
+// but make it return false.
+func (m *SecretsMigrator) ReEncryptDataKeys(ctx context.Context) (bool, error) {
+	err := m.initProvidersIfNeeded()
+	if err != nil {
+		return false, err
+	}
+
+	providers := m.secretsSrv.GetProviders()
+	currentID := m.secretsSrv.CurrentProviderID()
+	current, ok := providers[currentID]
+	if !ok {
+		return false, fmt.Errorf("missing configuration for current encryption provider %s", currentID)
+	}
+
+	var keys []*secrets.DataKey
+	if err := m.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("data_keys").Find(&keys)
+	}); err != nil {
+		return false, err
+	}
+
+	var anyFailure bool
+
+	for _, k := range keys {
+		providerID := kmsproviders.NormalizeProviderID(k.Provider)
+		if providerID == currentID {
+			continue
+		}
+
+		provider, ok := providers[providerID]
+		if !ok {
+			logger.Warn("Could not find provider to re-encrypt data key", "id", k.Id, "provider", k.Provider)
+			anyFailure = true
+			continue
+		}
+
+		decrypted, err := provider.Decrypt(ctx, k.EncryptedData)
+		if err != nil {
+			logger.Warn("Could not decrypt data key while re-encrypting it", "id", k.Id, "provider", k.Provider, "error", err)
+			anyFailure = true
+			continue
+		}
+
+		encrypted, err := current.Encrypt(ctx, decrypted)
+		if err != nil {
+			logger.Warn("Could not encrypt data key while re-encrypting it", "id", k.Id, "provider", currentID, "error", err)
+			anyFailure = true
+			continue
+		}
+
+		// The label is kept, so new data keys are still created for the current provider.
+		k.Provider = currentID
+		k.EncryptedData = encrypted
+		k.Updated = time.Now()
+		if err := m.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Table("data_keys").Where("name = ?", k.Id).Cols("provider", "encrypted_data", "updated").Update(k)
+			return err
+		}); err != nil {
+			logger.Warn("Could not update data key while re-encrypting it", "id", k.Id, "error", err)
+			anyFailure = true
+			continue
+		}
+
+		logger.Info("Data key re-encrypted with the current provider", "id", k.Id, "from", providerID, "to", currentID)
+	}
+
+	return !anyFailure, nil
+}
@@ -0,0 +1,103 @@
+package migrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	encryptionprovider "github.com/grafana/grafana/pkg/services/encryption/provider"
+	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/osskmsproviders"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestReEncryptDataKeys(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.InitTestDB(t)
+	store := database.ProvideSecretsStore(testDB)
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "grafana.agekey")
+	require.NoError(t, os.WriteFile(identityFile, []byte(identity.String()), 0600))
+
+	newService := func(t *testing.T, rawCfg string) (*manager.SecretsService, setting.Provider) {
+		t.Helper()
+		raw, err := ini.Load([]byte(rawCfg))
+		require.NoError(t, err)
+		settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}
+		features := featuremgmt.WithFeatures()
+
+		enc, err := encryptionservice.ProvideEncryptionService(encryptionprovider.Provider{}, &usagestats.UsageStatsMock{}, settings)
+		require.NoError(t, err)
+		svc, err := manager.ProvideSecretsService(store, osskmsproviders.ProvideService(enc, settings, features), enc, settings, features, &usagestats.UsageStatsMock{T: t})
+		require.NoError(t, err)
+		return svc, settings
+	}
+
+	// secrets encrypted with data keys of the default provider
+	defaultSvc, _ := newService(t, `
+[security]
+secret_key = first_secret_key
+`)
+	encrypted, err := defaultSvc.Encrypt(ctx, []byte("grafana"), secrets.WithoutScope())
+	require.NoError(t, err)
+
+	// switching to the age provider
+	ageSvc, settings := newService(t, `
+[security]
+secret_key = first_secret_key
+encryption_provider = age.v1
+available_encryption_providers = age.v1
+
+[security.encryption.age.v1]
+identity_file = `+identityFile+`
+`)
+	migrator := ProvideSecretsMigrator(nil, ageSvc, testDB, settings, featuremgmt.WithFeatures())
+
+	success, err := migrator.ReEncryptDataKeys(ctx)
+	require.NoError(t, err)
+	assert.True(t, success)
+
+	keys, err := store.GetAllDataKeys(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, keys)
+	for _, k := range keys {
+		assert.Equal(t, secrets.ProviderID("age.v1"), k.Provider)
+	}
+
+	// the secret key is no longer needed to decrypt the secrets
+	rotatedSvc, _ := newService(t, `
+[security]
+secret_key = another_secret_key
+encryption_provider = age.v1
+available_encryption_providers = age.v1
+
+[security.encryption.age.v1]
+identity_file = `+identityFile+`
+`)
+	decrypted, err := rotatedSvc.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("grafana"), decrypted)
+
+	t.Run("reports data keys of providers that are not configured", func(t *testing.T) {
+		_, err := testDB.GetSqlxSession().Exec(ctx, "UPDATE data_keys SET provider = ?", "hashicorpvault.v1")
+		require.NoError(t, err)
+
+		success, err := migrator.ReEncryptDataKeys(ctx)
+		require.NoError(t, err)
+		assert.False(t, success)
+	})
+}
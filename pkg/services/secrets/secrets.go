@@ -79,4 +79,10 @@ type Migrator interface {
 	// does not stop, but returns false as the first return (success or not)
 	// at the end of the process.
 	RollBackSecrets(ctx context.Context) (bool, error)
+	// ReEncryptDataKeys re-encrypts the data keys encrypted by any provider
+	// other than the current one with the current provider, so the previous
+	// provider can be removed. If a key-specific re-encryption fails, it does
+	// not stop, but returns false as the first return (success or not) at the
+	// end of the process.
+	ReEncryptDataKeys(ctx context.Context) (bool, error)
 }
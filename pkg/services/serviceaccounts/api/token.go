@@ -28,12 +28,17 @@ type TokenDTO struct {
 	Created *time.Time `json:"created"`
 	// example: 2022-03-23T10:31:02Z
 	LastUsedAt *time.Time `json:"lastUsedAt"`
+	// example: 192.168.1.1
+	LastUsedIP *string `json:"lastUsedIp"`
 	// example: 2022-03-23T10:31:02Z
 	Expiration *time.Time `json:"expiration"`
 	// example: 0
 	SecondsUntilExpiration *float64 `json:"secondsUntilExpiration"`
 	// example: false
 	HasExpired bool `json:"hasExpired"`
+	// ExpiresSoon is set when the token expires within the configured warning period.
+	// example: false
+	ExpiresSoon bool `json:"expiresSoon"`
 	// example: false
 	IsRevoked *bool `json:"isRevoked"`
 }
@@ -46,8 +51,6 @@ func hasExpired(expiration *int64) bool {
 	return (v).Before(time.Now())
 }
 
-const sevenDaysAhead = 7 * 24 * time.Hour
-
 // swagger:route GET /serviceaccounts/{serviceAccountId}/tokens service_accounts listTokens
 //
 // # Get service account tokens
@@ -83,14 +86,16 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *contextmodel.ReqContext) response
 			token                             = t // pin pointer
 			expiration             *time.Time = nil
 			secondsUntilExpiration float64    = 0
+			expiresSoon                       = false
 		)
 
 		isExpired := hasExpired(t.Expires)
 		if t.Expires != nil {
 			v := time.Unix(*t.Expires, 0)
 			expiration = &v
-			if !isExpired && (*expiration).Before(time.Now().Add(sevenDaysAhead)) {
+			if !isExpired && (*expiration).Before(time.Now().Add(api.cfg.SATokenExpiryWarningPeriod)) {
 				secondsUntilExpiration = time.Until(*expiration).Seconds()
+				expiresSoon = true
 			}
 		}
 
@@ -101,7 +106,9 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *contextmodel.ReqContext) response
 			Expiration:             expiration,
 			SecondsUntilExpiration: &secondsUntilExpiration,
 			HasExpired:             isExpired,
+			ExpiresSoon:            expiresSoon,
 			LastUsedAt:             token.LastUsedAt,
+			LastUsedIP:             token.LastUsedIP,
 			IsRevoked:              token.IsRevoked,
 		}
 	}
@@ -143,21 +150,8 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 	// Force affected service account to be the one referenced in the URL
 	cmd.OrgId = c.OrgID
 
-	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
-		if cmd.SecondsToLive == 0 {
-			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
-		}
-		if cmd.SecondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
-			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
-		}
-	}
-
-	if api.cfg.SATokenExpirationDayLimit > 0 {
-		dayExpireLimit := time.Now().Add(time.Duration(api.cfg.SATokenExpirationDayLimit) * time.Hour * 24).Truncate(24 * time.Hour)
-		expirationDate := time.Now().Add(time.Duration(cmd.SecondsToLive) * time.Second).Truncate(24 * time.Hour)
-		if expirationDate.After(dayExpireLimit) {
-			return response.Respond(http.StatusBadRequest, "The expiration date input exceeds the limit for service account access tokens expiration date")
-		}
+	if resp := api.validateTokenExpiration(cmd.SecondsToLive); resp != nil {
+		return resp
 	}
 
 	newKeyInfo, err := satokengen.New(ServiceID)
@@ -181,6 +175,28 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 	return response.JSON(http.StatusOK, result)
 }
 
+// validateTokenExpiration checks the token lifetime against the instance wide limits.
+func (api *ServiceAccountsAPI) validateTokenExpiration(secondsToLive int64) response.Response {
+	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
+		if secondsToLive == 0 {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
+		}
+		if secondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
+		}
+	}
+
+	if api.cfg.SATokenExpirationDayLimit > 0 {
+		dayExpireLimit := time.Now().Add(time.Duration(api.cfg.SATokenExpirationDayLimit) * time.Hour * 24).Truncate(24 * time.Hour)
+		expirationDate := time.Now().Add(time.Duration(secondsToLive) * time.Second).Truncate(24 * time.Hour)
+		if expirationDate.After(dayExpireLimit) {
+			return response.Respond(http.StatusBadRequest, "The expiration date input exceeds the limit for service account access tokens expiration date")
+		}
+	}
+
+	return nil
+}
+
 // swagger:route DELETE /serviceaccounts/{serviceAccountId}/tokens/{tokenId} service_accounts deleteToken
 //
 // # DeleteToken deletes service account tokens
@@ -220,6 +236,113 @@ func (api *ServiceAccountsAPI) DeleteToken(c *contextmodel.ReqContext) response.
 	return response.Success("Service account token deleted")
 }
 
+// swagger:route POST /serviceaccounts/{serviceAccountId}/tokens/{tokenId}/rotate service_accounts rotateToken
+//
+// # RotateToken replaces a service account token with a new one
+//
+// The new token takes over the name of the rotated token. The rotated token remains
+// valid for the grace period, so clients can switch to the new token without downtime.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:write` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: createTokenResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) RotateToken(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Token ID is invalid", err)
+	}
+
+	cmd := serviceaccounts.RotateServiceAccountTokenCommand{}
+	if c.Req.ContentLength != 0 {
+		if err = web.Bind(c.Req, &cmd); err != nil {
+			return response.Error(http.StatusBadRequest, "Bad request data", err)
+		}
+	}
+	cmd.OrgId = c.OrgID
+
+	if resp := api.validateTokenExpiration(cmd.SecondsToLive); resp != nil {
+		return resp
+	}
+
+	newKeyInfo, err := satokengen.New(ServiceID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Generating service account token failed", err)
+	}
+	cmd.Key = newKeyInfo.HashedKey
+
+	apiKey, err := api.service.RotateServiceAccountToken(c.Req.Context(), saID, tokenID, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to rotate service account token", err)
+	}
+
+	result := &dtos.NewApiKeyResult{
+		ID:   apiKey.ID,
+		Name: apiKey.Name,
+		Key:  newKeyInfo.ClientSecret,
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /serviceaccounts/token-policy service_accounts getTokenPolicy
+//
+// # Get the service account token policy of the organization
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:read`
+//
+// Responses:
+// 200: tokenPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) GetTokenPolicy(c *contextmodel.ReqContext) response.Response {
+	policy, err := api.service.GetTokenPolicy(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get token policy", err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /serviceaccounts/token-policy service_accounts updateTokenPolicy
+//
+// # Update the service account token policy of the organization
+//
+// The policy applies to tokens created or rotated after the update.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:write` scope: `serviceaccounts:*`
+//
+// Responses:
+// 200: tokenPolicyResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) UpdateTokenPolicy(c *contextmodel.ReqContext) response.Response {
+	policy := serviceaccounts.TokenPolicy{}
+	if err := web.Bind(c.Req, &policy); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	if err := api.service.UpdateTokenPolicy(c.Req.Context(), c.OrgID, &policy); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update token policy", err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
 // swagger:parameters listTokens
 type ListTokensParams struct {
 	// in:path
@@ -242,6 +365,28 @@ type DeleteTokenParams struct {
 	ServiceAccountId int64 `json:"serviceAccountId"`
 }
 
+// swagger:parameters rotateToken
+type RotateTokenParams struct {
+	// in:path
+	TokenId int64 `json:"tokenId"`
+	// in:path
+	ServiceAccountId int64 `json:"serviceAccountId"`
+	// in:body
+	Body serviceaccounts.RotateServiceAccountTokenCommand
+}
+
+// swagger:parameters updateTokenPolicy
+type UpdateTokenPolicyParams struct {
+	// in:body
+	Body serviceaccounts.TokenPolicy
+}
+
+// swagger:response tokenPolicyResponse
+type TokenPolicyResponse struct {
+	// in:body
+	Body *serviceaccounts.TokenPolicy
+}
+
 // swagger:response listTokensResponse
 type ListTokensResponse struct {
 	// in:body
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -56,6 +59,35 @@ func TestServiceAccountsAPI_ListTokens(t *testing.T) {
 	}
 }
 
+func TestServiceAccountsAPI_ListTokens_ExpiresSoon(t *testing.T) {
+	soon := time.Now().Add(24 * time.Hour).Unix()
+	later := time.Now().Add(30 * 24 * time.Hour).Unix()
+	server := setupTests(t, func(a *ServiceAccountsAPI) {
+		a.cfg.SATokenExpiryWarningPeriod = 7 * 24 * time.Hour
+		a.service = &fakeServiceAccountService{ExpectedServiceAccountTokens: []apikey.APIKey{
+			{ID: 1, Name: "soon", Expires: &soon},
+			{ID: 2, Name: "later", Expires: &later},
+			{ID: 3, Name: "never"},
+		}}
+	})
+	req := server.NewGetRequest("/api/serviceaccounts/1/tokens")
+	webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(
+		[]accesscontrol.Permission{{Action: serviceaccounts.ActionRead, Scope: "serviceaccounts:id:1"}},
+	)}})
+	res, err := server.Send(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var tokens []TokenDTO
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&tokens))
+	require.NoError(t, res.Body.Close())
+	require.Len(t, tokens, 3)
+	assert.True(t, tokens[0].ExpiresSoon)
+	assert.Greater(t, *tokens[0].SecondsUntilExpiration, float64(0))
+	assert.False(t, tokens[1].ExpiresSoon)
+	assert.False(t, tokens[2].ExpiresSoon)
+}
+
 func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 	type TestCase struct {
 		desc           string
@@ -176,3 +208,119 @@ func TestServiceAccountsAPI_DeleteToken(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAccountsAPI_RotateToken(t *testing.T) {
+	type TestCase struct {
+		desc           string
+		saID           int64
+		body           string
+		permissions    []accesscontrol.Permission
+		expectedErr    error
+		expectedAPIKey *apikey.APIKey
+		expectedCode   int
+	}
+
+	tests := []TestCase{
+		{
+			desc:           "should be able to rotate service account token with correct permission",
+			saID:           1,
+			body:           `{"secondsToLive": 3600, "gracePeriodSeconds": 600}`,
+			permissions:    []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedAPIKey: &apikey.APIKey{ID: 2, Name: "test"},
+			expectedCode:   http.StatusOK,
+		},
+		{
+			desc:           "should be able to rotate service account token without body",
+			saID:           1,
+			permissions:    []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedAPIKey: &apikey.APIKey{ID: 2, Name: "test"},
+			expectedCode:   http.StatusOK,
+		},
+		{
+			desc:         "should not be able to rotate service account token with wrong permission",
+			saID:         2,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not be able to rotate revoked service account token",
+			saID:         1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedErr:  serviceaccounts.ErrTokenNotRotatable.Errorf(""),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not be able to rotate service account token violating the token policy",
+			saID:         1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedErr:  serviceaccounts.ErrTokenPolicyViolation.Errorf(""),
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.cfg.ApiKeyMaxSecondsToLive = -1
+				a.service = &fakeServiceAccountService{
+					ExpectedErr:    tt.expectedErr,
+					ExpectedAPIKey: tt.expectedAPIKey,
+				}
+			})
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := server.NewRequest(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens/1/rotate", tt.saID), body)
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			if tt.expectedCode == http.StatusOK {
+				result := dtos.NewApiKeyResult{}
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+				assert.Equal(t, "test", result.Name)
+				assert.NotEmpty(t, result.Key)
+			}
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestServiceAccountsAPI_TokenPolicy(t *testing.T) {
+	t.Run("should return the token policy of the organization", func(t *testing.T) {
+		server := setupTests(t, func(a *ServiceAccountsAPI) {
+			a.service = &fakeServiceAccountService{ExpectedTokenPolicy: &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600, RequireExpiration: true}}
+		})
+
+		req := server.NewGetRequest("/api/serviceaccounts/token-policy")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {serviceaccounts.ActionRead: {}}}})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		policy := serviceaccounts.TokenPolicy{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&policy))
+		assert.Equal(t, serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600, RequireExpiration: true}, policy)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should require write permission on all service accounts to update the token policy", func(t *testing.T) {
+		for permissions, expectedCode := range map[string]int{
+			"serviceaccounts:id:1":   http.StatusForbidden,
+			serviceaccounts.ScopeAll: http.StatusOK,
+		} {
+			server := setupTests(t)
+
+			req := server.NewRequest(http.MethodPut, "/api/serviceaccounts/token-policy", strings.NewReader(`{"maxSecondsToLive": 3600}`))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {serviceaccounts.ActionWrite: {permissions}}}})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		}
+	})
+}
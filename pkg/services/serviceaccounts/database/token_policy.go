@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+const tokenPolicyKey = "tokenPolicy"
+
+// GetTokenPolicy returns the token policy of the organization. Organizations without a
+// stored policy get an empty policy that allows every token lifetime.
+func (s *ServiceAccountsStoreImpl) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	value, exists, err := s.kvStore.Get(ctx, orgID, "serviceaccounts", tokenPolicyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &serviceaccounts.TokenPolicy{}
+	if !exists {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("failed to decode token policy: %w", err)
+	}
+	return policy, nil
+}
+
+// UpdateTokenPolicy stores the token policy of the organization.
+func (s *ServiceAccountsStoreImpl) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return s.kvStore.Set(ctx, orgID, "serviceaccounts", tokenPolicyKey, string(value))
+}
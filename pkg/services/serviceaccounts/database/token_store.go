@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/apikey"
@@ -11,7 +12,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 )
 
-const maxRetrievedTokens = 300
+const (
+	maxRetrievedTokens = 300
+	maxTokenNameLength = 190
+)
+
+var timeNow = time.Now
 
 func (s *ServiceAccountsStoreImpl) ListTokens(
 	ctx context.Context, query *serviceaccounts.GetSATokensQuery,
@@ -109,6 +115,111 @@ func (s *ServiceAccountsStoreImpl) RevokeServiceAccountToken(ctx context.Context
 	})
 }
 
+// RotateServiceAccountToken replaces a token with a new key in a single transaction.
+// The new token takes over the name of the rotated one, while the rotated token is
+// renamed and expires once the grace period has passed.
+func (s *ServiceAccountsStoreImpl) RotateServiceAccountToken(ctx context.Context, serviceAccountId, tokenId int64, gracePeriod time.Duration, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	var newKey *apikey.APIKey
+
+	return newKey, s.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		oldKey := apikey.APIKey{}
+		exists, err := sess.Where("id=? AND org_id=? AND service_account_id=?", tokenId, cmd.OrgId, serviceAccountId).Get(&oldKey)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return serviceaccounts.ErrServiceAccountTokenNotFound.Errorf("service account token with id %d not found for service account with id %d", tokenId, serviceAccountId)
+		}
+
+		now := timeNow()
+		if (oldKey.IsRevoked != nil && *oldKey.IsRevoked) || (oldKey.Expires != nil && *oldKey.Expires <= now.Unix()) {
+			return serviceaccounts.ErrTokenNotRotatable.Errorf("service account token with id %d is revoked or expired", tokenId)
+		}
+		if cmd.SecondsToLive < 0 {
+			return serviceaccounts.ErrInvalidTokenExpiration.Errorf("invalid service account token expiration value %d", cmd.SecondsToLive)
+		}
+
+		graceExpires := now.Add(gracePeriod).Unix()
+		if oldKey.Expires == nil || *oldKey.Expires > graceExpires {
+			oldKey.Expires = &graceExpires
+		}
+		name := oldKey.Name
+		oldKey.Name = rotatedTokenName(name, now)
+		oldKey.Updated = now
+		if _, err := sess.ID(oldKey.ID).Cols("name", "expires", "updated").Update(&oldKey); err != nil {
+			return fmt.Errorf("failed to update rotated token: %w", err)
+		}
+
+		var expires *int64
+		if cmd.SecondsToLive > 0 {
+			v := now.Add(time.Duration(cmd.SecondsToLive) * time.Second).Unix()
+			expires = &v
+		}
+		isRevoked := false
+		key := apikey.APIKey{
+			OrgID:            cmd.OrgId,
+			Name:             name,
+			Role:             oldKey.Role,
+			Key:              cmd.Key,
+			Created:          now,
+			Updated:          now,
+			Expires:          expires,
+			ServiceAccountId: &serviceAccountId,
+			IsRevoked:        &isRevoked,
+		}
+		if _, err := sess.Insert(&key); err != nil {
+			return fmt.Errorf("failed to insert token: %w", err)
+		}
+
+		newKey = &key
+		return nil
+	})
+}
+
+// rotatedTokenName returns a unique name for a rotated token that fits the name column.
+func rotatedTokenName(name string, rotatedAt time.Time) string {
+	suffix := fmt.Sprintf(" (rotated %d)", rotatedAt.UnixNano())
+	if len(name)+len(suffix) > maxTokenNameLength {
+		name = name[:maxTokenNameLength-len(suffix)]
+	}
+	return name + suffix
+}
+
+// RevokeUnusedServiceAccountTokens revokes all service account tokens that have not been
+// used since the given time. Tokens that were never used are revoked once they were created
+// before that time. It returns the number of revoked tokens.
+func (s *ServiceAccountsStoreImpl) RevokeUnusedServiceAccountTokens(ctx context.Context, unusedSince time.Time) (int64, error) {
+	rawSQL := "UPDATE api_key SET is_revoked = ?, updated = ? WHERE service_account_id IS NOT NULL AND " +
+		"(is_revoked IS NULL OR is_revoked = ?) AND " +
+		"((last_used_at IS NULL AND created < ?) OR last_used_at < ?)"
+
+	var affected int64
+	err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		dialect := s.sqlStore.GetDialect()
+		result, err := sess.Exec(rawSQL, dialect.BooleanStr(true), timeNow(), dialect.BooleanStr(false), unusedSince, unusedSince)
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+// ListExpiringServiceAccountTokens returns the service account tokens that are not revoked
+// and expire after from and no later than to.
+func (s *ServiceAccountsStoreImpl) ListExpiringServiceAccountTokens(ctx context.Context, from, to time.Time) ([]apikey.APIKey, error) {
+	result := make([]apikey.APIKey, 0)
+	err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("service_account_id IS NOT NULL").
+			And("(is_revoked IS NULL OR is_revoked = ?)", s.sqlStore.GetDialect().BooleanStr(false)).
+			And("expires > ? AND expires <= ?", from.Unix(), to.Unix()).
+			Asc("expires").
+			Find(&result)
+	})
+	return result, err
+}
+
 // assignApiKeyToServiceAccount sets the API key service account ID
 func (s *ServiceAccountsStoreImpl) assignApiKeyToServiceAccount(sess *db.Session, apiKeyId int64, serviceAccountId int64) error {
 	key := apikey.APIKey{ID: apiKeyId}
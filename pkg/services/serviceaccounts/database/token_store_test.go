@@ -3,12 +3,15 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
 func TestStore_AddServiceAccountToken(t *testing.T) {
@@ -176,3 +179,178 @@ func TestStore_DeleteServiceAccountToken(t *testing.T) {
 		}
 	}
 }
+
+func TestStore_RotateServiceAccountToken(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	addToken := func(t *testing.T, name string, secondsToLive int64) *apikey.APIKey {
+		t.Helper()
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:          name,
+			OrgId:         sa.OrgID,
+			Key:           key.HashedKey,
+			SecondsToLive: secondsToLive,
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("should replace token and keep the old one valid during the grace period", func(t *testing.T) {
+		oldToken := addToken(t, "rotate", 0)
+
+		newToken, err := store.RotateServiceAccountToken(context.Background(), sa.ID, oldToken.ID, time.Hour, &serviceaccounts.RotateServiceAccountTokenCommand{
+			OrgId:         sa.OrgID,
+			Key:           "new-hashed-key",
+			SecondsToLive: 3600,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "rotate", newToken.Name)
+		require.NotNil(t, newToken.Expires)
+
+		keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{OrgID: &sa.OrgID, ServiceAccountID: &sa.ID})
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		for _, k := range keys {
+			if k.ID == oldToken.ID {
+				require.NotEqual(t, "rotate", k.Name)
+				require.NotNil(t, k.Expires)
+				require.InDelta(t, time.Now().Add(time.Hour).Unix(), *k.Expires, 5)
+				require.False(t, *k.IsRevoked)
+			}
+		}
+	})
+
+	t.Run("should not extend the expiration of the old token", func(t *testing.T) {
+		oldToken := addToken(t, "rotate-short-lived", 60)
+
+		_, err := store.RotateServiceAccountToken(context.Background(), sa.ID, oldToken.ID, time.Hour, &serviceaccounts.RotateServiceAccountTokenCommand{
+			OrgId: sa.OrgID,
+			Key:   "another-hashed-key",
+		})
+		require.NoError(t, err)
+
+		keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{OrgID: &sa.OrgID, ServiceAccountID: &sa.ID})
+		require.NoError(t, err)
+		for _, k := range keys {
+			if k.ID == oldToken.ID {
+				require.Equal(t, *oldToken.Expires, *k.Expires)
+			}
+		}
+	})
+
+	t.Run("should not rotate revoked tokens", func(t *testing.T) {
+		token := addToken(t, "rotate-revoked", 0)
+		require.NoError(t, store.RevokeServiceAccountToken(context.Background(), sa.OrgID, sa.ID, token.ID))
+
+		_, err := store.RotateServiceAccountToken(context.Background(), sa.ID, token.ID, time.Hour, &serviceaccounts.RotateServiceAccountTokenCommand{
+			OrgId: sa.OrgID,
+			Key:   "revoked-hashed-key",
+		})
+		require.ErrorIs(t, err, serviceaccounts.ErrTokenNotRotatable)
+	})
+
+	t.Run("should not rotate tokens of another service account", func(t *testing.T) {
+		token := addToken(t, "rotate-other", 0)
+
+		_, err := store.RotateServiceAccountToken(context.Background(), sa.ID+1, token.ID, time.Hour, &serviceaccounts.RotateServiceAccountTokenCommand{
+			OrgId: sa.OrgID,
+			Key:   "other-hashed-key",
+		})
+		require.ErrorIs(t, err, serviceaccounts.ErrServiceAccountTokenNotFound)
+	})
+}
+
+func TestStore_RevokeUnusedServiceAccountTokens(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	tokens := map[string]int64{}
+	for _, name := range []string{"recently-used", "unused", "never-used", "new"} {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:  name,
+			OrgId: sa.OrgID,
+			Key:   key.HashedKey,
+		})
+		require.NoError(t, err)
+		tokens[name] = token.ID
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, store.apiKeyService.UpdateAPIKeyLastUsed(context.Background(), tokens["recently-used"], "127.0.0.1"))
+	err := db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Exec("UPDATE api_key SET last_used_at = ?, created = ? WHERE id = ?", old, old, tokens["unused"]); err != nil {
+			return err
+		}
+		_, err := sess.Exec("UPDATE api_key SET created = ? WHERE id = ?", old, tokens["never-used"])
+		return err
+	})
+	require.NoError(t, err)
+
+	revoked, err := store.RevokeUnusedServiceAccountTokens(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), revoked)
+
+	keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{OrgID: &sa.OrgID, ServiceAccountID: &sa.ID})
+	require.NoError(t, err)
+	for _, k := range keys {
+		expected := k.Name == "unused" || k.Name == "never-used"
+		require.Equal(t, expected, *k.IsRevoked, k.Name)
+	}
+}
+
+func TestStore_ListExpiringServiceAccountTokens(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	for name, ttl := range map[string]time.Duration{"soon": 24 * time.Hour, "later": 30 * 24 * time.Hour, "never": 0, "revoked": time.Hour} {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:          name,
+			OrgId:         sa.OrgID,
+			Key:           key.HashedKey,
+			SecondsToLive: int64(ttl.Seconds()),
+		})
+		require.NoError(t, err)
+		if name == "revoked" {
+			require.NoError(t, store.RevokeServiceAccountToken(context.Background(), sa.OrgID, sa.ID, token.ID))
+		}
+	}
+
+	now := time.Now()
+	tokens, err := store.ListExpiringServiceAccountTokens(context.Background(), now, now.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, "soon", tokens[0].Name)
+
+	tokens, err = store.ListExpiringServiceAccountTokens(context.Background(), now.Add(2*24*time.Hour), now.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+}
+
+func TestStore_TokenPolicy(t *testing.T) {
+	_, store := setupTestDatabase(t)
+
+	policy, err := store.GetTokenPolicy(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, &serviceaccounts.TokenPolicy{}, policy)
+
+	expected := &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600, RequireExpiration: true}
+	require.NoError(t, store.UpdateTokenPolicy(context.Background(), 1, expected))
+
+	policy, err = store.GetTokenPolicy(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, expected, policy)
+
+	policy, err = store.GetTokenPolicy(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, &serviceaccounts.TokenPolicy{}, policy)
+}
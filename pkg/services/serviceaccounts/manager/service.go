@@ -9,10 +9,12 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/api"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/database"
@@ -25,16 +27,26 @@ import (
 const (
 	metricsCollectionInterval = time.Minute * 30
 	defaultSecretScanInterval = time.Minute * 5
+
+	// maxTokenRotationGracePeriod is the longest a rotated token is allowed to stay valid.
+	maxTokenRotationGracePeriod = 7 * 24 * time.Hour
+	tokenExpiryCheckInterval    = time.Hour
 )
 
 type ServiceAccountsService struct {
 	store             store
+	serverLock        *serverlock.ServerLockService
 	log               log.Logger
 	backgroundLog     log.Logger
 	secretScanService secretscan.Checker
 
 	secretScanEnabled  bool
 	secretScanInterval time.Duration
+
+	tokenRotationGracePeriod time.Duration
+	tokenUnusedDisableAfter  time.Duration
+	tokenUnusedCheckInterval time.Duration
+	tokenExpiryWarningPeriod time.Duration
 }
 
 func ProvideServiceAccountsService(
@@ -49,6 +61,8 @@ func ProvideServiceAccountsService(
 	orgService org.Service,
 	permissionService accesscontrol.ServiceAccountPermissionsService,
 	accesscontrolService accesscontrol.Service,
+	provenanceService provenance.Service,
+	serverLock *serverlock.ServerLockService,
 ) (*ServiceAccountsService, error) {
 	serviceAccountsStore := database.ProvideServiceAccountsStore(
 		cfg,
@@ -60,8 +74,20 @@ func ProvideServiceAccountsService(
 	)
 	s := &ServiceAccountsService{
 		store:         serviceAccountsStore,
+		serverLock:    serverLock,
 		log:           log.New("serviceaccounts"),
 		backgroundLog: log.New("serviceaccounts.background"),
+
+		tokenRotationGracePeriod: cfg.SATokenRotationGracePeriod,
+		tokenUnusedDisableAfter:  time.Duration(cfg.SATokenUnusedDisableDays) * 24 * time.Hour,
+		tokenUnusedCheckInterval: cfg.SATokenUnusedDisableJobInterval,
+		tokenExpiryWarningPeriod: cfg.SATokenExpiryWarningPeriod,
+	}
+
+	if s.tokenRotationGracePeriod > maxTokenRotationGracePeriod {
+		s.log.Warn("token rotation grace period is too high, decreasing to "+maxTokenRotationGracePeriod.String(),
+			"gracePeriod", s.tokenRotationGracePeriod)
+		s.tokenRotationGracePeriod = maxTokenRotationGracePeriod
 	}
 
 	if err := RegisterRoles(accesscontrolService); err != nil {
@@ -70,7 +96,7 @@ func ProvideServiceAccountsService(
 
 	usageStats.RegisterMetricsFunc(s.getUsageMetrics)
 
-	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService)
+	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService, provenanceService)
 	serviceaccountsAPI.RegisterAPIEndpoints()
 
 	s.secretScanEnabled = cfg.SectionWithEnvOverrides("secretscan").Key("enabled").MustBool(false)
@@ -120,6 +146,32 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 		defer tokenCheckTicker.Stop()
 	}
 
+	// Enforce a minimum interval of 1 minute.
+	if sa.tokenUnusedCheckInterval < time.Minute {
+		sa.tokenUnusedCheckInterval = time.Minute
+	}
+
+	unusedTokenTicker := time.NewTicker(sa.tokenUnusedCheckInterval)
+	if sa.tokenUnusedDisableAfter <= 0 {
+		unusedTokenTicker.Stop()
+	} else {
+		sa.backgroundLog.Debug("enabled revocation of unused tokens", "unusedFor", sa.tokenUnusedDisableAfter)
+		sa.revokeUnusedTokens(ctx)
+
+		defer unusedTokenTicker.Stop()
+	}
+
+	expiringTokenTicker := time.NewTicker(tokenExpiryCheckInterval)
+	if sa.tokenExpiryWarningPeriod <= 0 {
+		expiringTokenTicker.Stop()
+	} else {
+		sa.backgroundLog.Debug("enabled warnings for expiring tokens", "warnBefore", sa.tokenExpiryWarningPeriod)
+		// warn about every token already within the warning period on startup
+		sa.warnExpiringTokens(ctx, time.Now())
+
+		defer expiringTokenTicker.Stop()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -142,7 +194,56 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 			if err := sa.secretScanService.CheckTokens(ctx); err != nil {
 				sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
 			}
+		case <-unusedTokenTicker.C:
+			sa.backgroundLog.Debug("checking for unused tokens")
+
+			sa.revokeUnusedTokens(ctx)
+		case <-expiringTokenTicker.C:
+			sa.backgroundLog.Debug("checking for expiring tokens")
+
+			// only warn about tokens that entered the warning period since the previous check
+			sa.warnExpiringTokens(ctx, time.Now().Add(-tokenExpiryCheckInterval))
+		}
+	}
+}
+
+// revokeUnusedTokens revokes the tokens that have not been used for tokenUnusedDisableAfter.
+// Only one instance runs the revocation per check interval.
+func (sa *ServiceAccountsService) revokeUnusedTokens(ctx context.Context) {
+	err := sa.serverLock.LockAndExecute(ctx, "revoke unused service account tokens", sa.tokenUnusedCheckInterval/2, func(ctx context.Context) {
+		revoked, err := sa.store.RevokeUnusedServiceAccountTokens(ctx, time.Now().Add(-sa.tokenUnusedDisableAfter))
+		if err != nil {
+			sa.backgroundLog.Warn("Failed to revoke unused tokens", "error", err.Error())
+			return
+		}
+		if revoked > 0 {
+			sa.backgroundLog.Info("Revoked unused service account tokens", "count", revoked, "unusedFor", sa.tokenUnusedDisableAfter)
 		}
+	})
+	if err != nil {
+		sa.backgroundLog.Warn("Failed to lock and execute revocation of unused tokens", "error", err.Error())
+	}
+}
+
+// warnExpiringTokens logs a warning for every token that expires after since plus the warning
+// period and within the warning period from now.
+func (sa *ServiceAccountsService) warnExpiringTokens(ctx context.Context, since time.Time) {
+	err := sa.serverLock.LockAndExecute(ctx, "warn expiring service account tokens", tokenExpiryCheckInterval/2, func(ctx context.Context) {
+		now := time.Now()
+		tokens, err := sa.store.ListExpiringServiceAccountTokens(ctx, since.Add(sa.tokenExpiryWarningPeriod), now.Add(sa.tokenExpiryWarningPeriod))
+		if err != nil {
+			sa.backgroundLog.Warn("Failed to list expiring tokens", "error", err.Error())
+			return
+		}
+		for _, token := range tokens {
+			expires := time.Unix(*token.Expires, 0)
+			sa.backgroundLog.Warn("Service account token expires soon", "orgId", token.OrgID,
+				"serviceAccountId", *token.ServiceAccountId, "tokenId", token.ID, "name", token.Name,
+				"expires", expires, "expiresIn", expires.Sub(now).Round(time.Minute))
+		}
+	})
+	if err != nil {
+		sa.backgroundLog.Warn("Failed to lock and execute check for expiring tokens", "error", err.Error())
 	}
 }
 
@@ -209,9 +310,61 @@ func (sa *ServiceAccountsService) AddServiceAccountToken(ctx context.Context, se
 	if err := validServiceAccountID(serviceAccountID); err != nil {
 		return nil, err
 	}
+	if err := sa.validateTokenPolicy(ctx, query.OrgId, query.SecondsToLive); err != nil {
+		return nil, err
+	}
 	return sa.store.AddServiceAccountToken(ctx, serviceAccountID, query)
 }
 
+func (sa *ServiceAccountsService) RotateServiceAccountToken(ctx context.Context, serviceAccountID, tokenID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	if err := validOrgID(cmd.OrgId); err != nil {
+		return nil, err
+	}
+	if err := validServiceAccountID(serviceAccountID); err != nil {
+		return nil, err
+	}
+	if err := validServiceAccountTokenID(tokenID); err != nil {
+		return nil, err
+	}
+	if err := sa.validateTokenPolicy(ctx, cmd.OrgId, cmd.SecondsToLive); err != nil {
+		return nil, err
+	}
+
+	gracePeriod := sa.tokenRotationGracePeriod
+	if cmd.GracePeriodSeconds != nil {
+		if *cmd.GracePeriodSeconds < 0 || *cmd.GracePeriodSeconds > int64(maxTokenRotationGracePeriod/time.Second) {
+			return nil, serviceaccounts.ErrInvalidTokenExpiration.Errorf("invalid grace period %d", *cmd.GracePeriodSeconds)
+		}
+		gracePeriod = time.Duration(*cmd.GracePeriodSeconds) * time.Second
+	}
+	return sa.store.RotateServiceAccountToken(ctx, serviceAccountID, tokenID, gracePeriod, cmd)
+}
+
+func (sa *ServiceAccountsService) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	if err := validOrgID(orgID); err != nil {
+		return nil, err
+	}
+	return sa.store.GetTokenPolicy(ctx, orgID)
+}
+
+func (sa *ServiceAccountsService) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	if err := validOrgID(orgID); err != nil {
+		return err
+	}
+	if policy.MaxSecondsToLive < 0 {
+		return serviceaccounts.ErrInvalidTokenPolicy.Errorf("invalid maximum token lifetime %d", policy.MaxSecondsToLive)
+	}
+	return sa.store.UpdateTokenPolicy(ctx, orgID, policy)
+}
+
+func (sa *ServiceAccountsService) validateTokenPolicy(ctx context.Context, orgID int64, secondsToLive int64) error {
+	policy, err := sa.store.GetTokenPolicy(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return policy.Validate(secondsToLive)
+}
+
 func (sa *ServiceAccountsService) DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID int64, tokenID int64) error {
 	if err := validOrgID(orgID); err != nil {
 		return err
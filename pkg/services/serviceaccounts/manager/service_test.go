@@ -3,10 +3,14 @@ package manager
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -20,6 +24,9 @@ type FakeServiceAccountStore struct {
 	ExpectedStats                           *serviceaccounts.Stats
 	ExpectedAPIKeys                         []apikey.APIKey
 	ExpectedAPIKey                          *apikey.APIKey
+	ExpectedTokenPolicy                     *serviceaccounts.TokenPolicy
+	ExpectedRevokedTokens                   int64
+	RevokeUnusedCalls                       int
 	ExpectedBoolean                         bool
 	ExpectedError                           error
 }
@@ -94,6 +101,35 @@ func (f *FakeServiceAccountStore) DeleteServiceAccountToken(ctx context.Context,
 	return f.ExpectedError
 }
 
+// RotateServiceAccountToken is a fake rotating a service account token.
+func (f *FakeServiceAccountStore) RotateServiceAccountToken(ctx context.Context, serviceAccountID, tokenID int64, gracePeriod time.Duration, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	return f.ExpectedAPIKey, f.ExpectedError
+}
+
+// RevokeUnusedServiceAccountTokens is a fake revoking unused service account tokens.
+func (f *FakeServiceAccountStore) RevokeUnusedServiceAccountTokens(ctx context.Context, unusedSince time.Time) (int64, error) {
+	f.RevokeUnusedCalls++
+	return f.ExpectedRevokedTokens, f.ExpectedError
+}
+
+// ListExpiringServiceAccountTokens is a fake listing service account tokens that expire soon.
+func (f *FakeServiceAccountStore) ListExpiringServiceAccountTokens(ctx context.Context, from, to time.Time) ([]apikey.APIKey, error) {
+	return f.ExpectedAPIKeys, f.ExpectedError
+}
+
+// GetTokenPolicy is a fake getting the token policy of an organization.
+func (f *FakeServiceAccountStore) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	if f.ExpectedTokenPolicy == nil {
+		return &serviceaccounts.TokenPolicy{}, f.ExpectedError
+	}
+	return f.ExpectedTokenPolicy, f.ExpectedError
+}
+
+// UpdateTokenPolicy is a fake updating the token policy of an organization.
+func (f *FakeServiceAccountStore) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	return f.ExpectedError
+}
+
 // GetUsageMetrics is a fake getting usage metrics.
 func (f *FakeServiceAccountStore) GetUsageMetrics(ctx context.Context) (*serviceaccounts.Stats, error) {
 	return f.ExpectedStats, f.ExpectedError
@@ -109,7 +145,7 @@ func (f *SecretsCheckerFake) CheckTokens(ctx context.Context) error {
 
 func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 	storeMock := newServiceAccountStoreFake()
-	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0}
+	svc := ServiceAccountsService{store: storeMock, log: log.New("test"), backgroundLog: log.New("background.test"), secretScanService: &SecretsCheckerFake{}}
 	testOrgId := 1
 
 	t.Run("should create service account", func(t *testing.T) {
@@ -136,3 +172,81 @@ func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestProvideServiceAccount_TokenPolicy(t *testing.T) {
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{store: storeMock, log: log.New("test"), backgroundLog: log.New("background.test")}
+	storeMock.ExpectedTokenPolicy = &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600, RequireExpiration: true}
+
+	testCases := []struct {
+		desc          string
+		secondsToLive int64
+		expectedErr   error
+	}{
+		{desc: "should allow tokens within the limit", secondsToLive: 60},
+		{desc: "should reject tokens without expiration", secondsToLive: 0, expectedErr: serviceaccounts.ErrTokenPolicyViolation},
+		{desc: "should reject tokens exceeding the limit", secondsToLive: 7200, expectedErr: serviceaccounts.ErrTokenPolicyViolation},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := svc.AddServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{
+				Name: "token", OrgId: 1, SecondsToLive: tc.secondsToLive,
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = svc.RotateServiceAccountToken(context.Background(), 1, 1, &serviceaccounts.RotateServiceAccountTokenCommand{
+				OrgId: 1, SecondsToLive: tc.secondsToLive,
+			})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("should reject negative maximum lifetime", func(t *testing.T) {
+		err := svc.UpdateTokenPolicy(context.Background(), 1, &serviceaccounts.TokenPolicy{MaxSecondsToLive: -1})
+		require.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenPolicy)
+	})
+}
+
+func TestProvideServiceAccount_RotateGracePeriod(t *testing.T) {
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{store: storeMock, log: log.New("test"), backgroundLog: log.New("background.test")}
+
+	rotate := func(gracePeriod int64) error {
+		_, err := svc.RotateServiceAccountToken(context.Background(), 1, 1, &serviceaccounts.RotateServiceAccountTokenCommand{
+			OrgId: 1, GracePeriodSeconds: &gracePeriod,
+		})
+		return err
+	}
+
+	require.NoError(t, rotate(int64(maxTokenRotationGracePeriod/time.Second)))
+	require.ErrorIs(t, rotate(-1), serviceaccounts.ErrInvalidTokenExpiration)
+	require.ErrorIs(t, rotate(int64(maxTokenRotationGracePeriod/time.Second)+1), serviceaccounts.ErrInvalidTokenExpiration)
+}
+
+func TestIntegrationServiceAccount_RevokeUnusedTokensOncePerInterval(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{
+		store:                    storeMock,
+		serverLock:               serverlock.ProvideService(db.InitTestDB(t), tracing.InitializeTracerForTest()),
+		log:                      log.New("test"),
+		backgroundLog:            log.New("background.test"),
+		tokenUnusedDisableAfter:  24 * time.Hour,
+		tokenUnusedCheckInterval: time.Hour,
+	}
+
+	svc.revokeUnusedTokens(context.Background())
+	svc.revokeUnusedTokens(context.Background())
+	require.Equal(t, 1, storeMock.RevokeUnusedCalls)
+}
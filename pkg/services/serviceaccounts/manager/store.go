@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -21,5 +22,10 @@ type store interface {
 	RevokeServiceAccountToken(ctx context.Context, orgId, serviceAccountId, tokenId int64) error
 	AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*apikey.APIKey, error)
 	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
+	RotateServiceAccountToken(ctx context.Context, serviceAccountID, tokenID int64, gracePeriod time.Duration, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*apikey.APIKey, error)
+	RevokeUnusedServiceAccountTokens(ctx context.Context, unusedSince time.Time) (int64, error)
+	ListExpiringServiceAccountTokens(ctx context.Context, from, to time.Time) ([]apikey.APIKey, error)
+	GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error)
+	UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error
 	GetUsageMetrics(ctx context.Context) (*serviceaccounts.Stats, error)
 }
@@ -35,6 +35,9 @@ var (
 	ErrServiceAccountTokenNotFound       = errutil.NewBase(errutil.StatusNotFound, "serviceaccounts.ErrTokenNotFound", errutil.WithPublicMessage("service account token not found"))
 	ErrInvalidTokenExpiration            = errutil.NewBase(errutil.StatusValidationFailed, "serviceaccounts.ErrInvalidInput", errutil.WithPublicMessage("invalid SecondsToLive value"))
 	ErrDuplicateToken                    = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrTokenAlreadyExists", errutil.WithPublicMessage("service account token with given name already exists in the organization"))
+	ErrTokenPolicyViolation              = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrTokenPolicyViolation", errutil.WithPublicMessage("service account token expiration does not comply with the organization token policy"))
+	ErrInvalidTokenPolicy                = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrInvalidTokenPolicy", errutil.WithPublicMessage("invalid service account token policy"))
+	ErrTokenNotRotatable                 = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrTokenNotRotatable", errutil.WithPublicMessage("revoked or expired service account tokens cannot be rotated"))
 )
 
 type ServiceAccount struct {
@@ -92,6 +95,41 @@ type AddServiceAccountTokenCommand struct {
 	SecondsToLive int64  `json:"secondsToLive"`
 }
 
+type RotateServiceAccountTokenCommand struct {
+	// SecondsToLive of the new token.
+	SecondsToLive int64 `json:"secondsToLive"`
+	// GracePeriodSeconds during which the rotated token remains valid.
+	// Uses the configured default when not set and may not exceed 7 days.
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds"`
+	OrgId              int64  `json:"-"`
+	Key                string `json:"-"`
+}
+
+// TokenPolicy restricts the lifetime of service account tokens created in an organization.
+// swagger:model
+type TokenPolicy struct {
+	// Maximum number of seconds a token may live. Zero means no limit.
+	// example: 2592000
+	MaxSecondsToLive int64 `json:"maxSecondsToLive"`
+	// Reject tokens that never expire.
+	// example: true
+	RequireExpiration bool `json:"requireExpiration"`
+}
+
+// Validate checks that a token living for secondsToLive complies with the policy.
+func (p *TokenPolicy) Validate(secondsToLive int64) error {
+	if secondsToLive == 0 {
+		if p.RequireExpiration || p.MaxSecondsToLive > 0 {
+			return ErrTokenPolicyViolation.Errorf("the organization requires service account tokens to expire")
+		}
+		return nil
+	}
+	if p.MaxSecondsToLive > 0 && secondsToLive > p.MaxSecondsToLive {
+		return ErrTokenPolicyViolation.Errorf("token lifetime of %d seconds exceeds the organization limit of %d seconds", secondsToLive, p.MaxSecondsToLive)
+	}
+	return nil
+}
+
 type SearchOrgServiceAccountsQuery struct {
 	OrgID        int64
 	Query        string
@@ -0,0 +1,59 @@
+package accesscontrol
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func AddRoleAssignmentMigrations(mg *migrator.Migrator) {
+	roleAssignmentV1 := migrator.Table{
+		Name: "role_assignment",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "role_name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "builtin_role", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "justification", Type: migrator.DB_Text, Nullable: true},
+			{Name: "created_by", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "expires", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "user_id"}},
+			{Cols: []string{"org_id", "team_id"}},
+			{Cols: []string{"org_id", "builtin_role"}},
+			{Cols: []string{"expires"}},
+		},
+	}
+
+	mg.AddMigration("create role_assignment table", migrator.NewAddTableMigration(roleAssignmentV1))
+	mg.AddMigration("add index role_assignment.org_id_user_id", migrator.NewAddIndexMigration(roleAssignmentV1, roleAssignmentV1.Indices[0]))
+	mg.AddMigration("add index role_assignment.org_id_team_id", migrator.NewAddIndexMigration(roleAssignmentV1, roleAssignmentV1.Indices[1]))
+	mg.AddMigration("add index role_assignment.org_id_builtin_role", migrator.NewAddIndexMigration(roleAssignmentV1, roleAssignmentV1.Indices[2]))
+	mg.AddMigration("add index role_assignment.expires", migrator.NewAddIndexMigration(roleAssignmentV1, roleAssignmentV1.Indices[3]))
+
+	roleAssignmentEventV1 := migrator.Table{
+		Name: "role_assignment_event",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "assignment_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "event", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "role_name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "builtin_role", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "justification", Type: migrator.DB_Text, Nullable: true},
+			{Name: "actor_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "expires", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created"}},
+		},
+	}
+
+	mg.AddMigration("create role_assignment_event table", migrator.NewAddTableMigration(roleAssignmentEventV1))
+	mg.AddMigration("add index role_assignment_event.org_id_created", migrator.NewAddIndexMigration(roleAssignmentEventV1, roleAssignmentEventV1.Indices[0]))
+}
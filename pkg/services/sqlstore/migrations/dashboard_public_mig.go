@@ -96,4 +96,57 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	emailShareV1 := Table{
+		Name: "dashboard_public_email_share",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "recipient", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "magic_link_token", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+			{Name: "expires_at", Type: DB_DateTime, Nullable: true},
+			{Name: "last_viewed_at", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"uid"}, Type: UniqueIndex},
+			{Cols: []string{"public_dashboard_uid", "recipient"}, Type: UniqueIndex},
+			{Cols: []string{"magic_link_token"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard public email share table v1", NewAddTableMigration(emailShareV1))
+	addTableIndicesMigrations(mg, "v1", emailShareV1)
+
+	mg.AddMigration("add magic_link_used_at column to dashboard public email share", NewAddColumnMigration(emailShareV1, &Column{
+		Name:     "magic_link_used_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	sessionV1 := Table{
+		Name: "dashboard_public_session",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "token", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "email_share_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+			{Name: "expires_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"token"}, Type: UniqueIndex},
+			{Cols: []string{"email_share_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public session table v1", NewAddTableMigration(sessionV1))
+	addTableIndicesMigrations(mg, "v1", sessionV1)
 }
@@ -39,4 +39,19 @@ func addLoginAttemptMigrations(mg *Migrator) {
 		"username":   "username",
 		"ip_address": "ip_address",
 	})
+
+	// IPv6 addresses don't fit in 30 characters
+	mg.AddMigration("increase login_attempt.ip_address column to length 50", NewRawSQLMigration("").
+		Postgres("ALTER TABLE login_attempt ALTER COLUMN ip_address TYPE VARCHAR(50);").
+		Mysql("ALTER TABLE login_attempt MODIFY ip_address VARCHAR(50) NOT NULL;"))
+
+	mg.AddMigration("add ip_subnet column to login_attempt", NewAddColumnMigration(loginAttemptV2, &Column{
+		Name: "ip_subnet", Type: DB_NVarchar, Length: 50, Nullable: true,
+	}))
+	mg.AddMigration("add index login_attempt.ip_address", NewAddIndexMigration(loginAttemptV2, &Index{
+		Cols: []string{"ip_address"},
+	}))
+	mg.AddMigration("add index login_attempt.ip_subnet", NewAddIndexMigration(loginAttemptV2, &Index{
+		Cols: []string{"ip_subnet"},
+	}))
 }
@@ -0,0 +1,87 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addMFAMigrations(mg *Migrator) {
+	userMFAV1 := Table{
+		Name: "user_mfa",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			// encrypted with the secrets service
+			{Name: "totp_secret", Type: DB_Text, Nullable: true},
+			{Name: "totp_enabled", Type: DB_Bool, Nullable: false, Default: "0"},
+			// last accepted time step, codes can't be reused
+			{Name: "totp_last_step", Type: DB_BigInt, Nullable: false, Default: "0"},
+			// JSON list of hashed recovery codes
+			{Name: "recovery_codes", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_mfa table", NewAddTableMigration(userMFAV1))
+	mg.AddMigration("add unique index user_mfa.user_id", NewAddIndexMigration(userMFAV1, userMFAV1.Indices[0]))
+
+	webAuthnCredentialV1 := Table{
+		Name: "user_mfa_webauthn_credential",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "credential_id", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "public_key", Type: DB_Text, Nullable: false},
+			{Name: "attestation_type", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "aaguid", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "sign_count", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "transports", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "last_used", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}},
+			{Cols: []string{"credential_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_mfa_webauthn_credential table", NewAddTableMigration(webAuthnCredentialV1))
+	mg.AddMigration("add index user_mfa_webauthn_credential.user_id", NewAddIndexMigration(webAuthnCredentialV1, webAuthnCredentialV1.Indices[0]))
+	mg.AddMigration("add unique index user_mfa_webauthn_credential.credential_id", NewAddIndexMigration(webAuthnCredentialV1, webAuthnCredentialV1.Indices[1]))
+
+	orgPolicyV1 := Table{
+		Name: "org_mfa_policy",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "required", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create org_mfa_policy table", NewAddTableMigration(orgPolicyV1))
+	mg.AddMigration("add unique index org_mfa_policy.org_id", NewAddIndexMigration(orgPolicyV1, orgPolicyV1.Indices[0]))
+
+	// attempts are counted in the database, so concurrent requests can't exceed the limit of a challenge
+	challengeAttemptV1 := Table{
+		Name: "user_mfa_challenge_attempt",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			// sha256 of the challenge token
+			{Name: "token", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "expires", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"token"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_mfa_challenge_attempt table", NewAddTableMigration(challengeAttemptV1))
+	mg.AddMigration("add unique index user_mfa_challenge_attempt.token", NewAddIndexMigration(challengeAttemptV1, challengeAttemptV1.Indices[0]))
+}
@@ -96,6 +96,10 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	AddExternalAlertmanagerToDatasourceMigration(mg)
 
 	addFolderMigrations(mg)
+
+	addMFAMigrations(mg)
+
+	accesscontrol.AddRoleAssignmentMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
@@ -74,6 +74,13 @@ func (f OrgFilter) Where() (string, []interface{}) {
 	return "dashboard.org_id=?", []interface{}{f.OrgId}
 }
 
+// NotDeletedFilter excludes the dashboards in the trash.
+type NotDeletedFilter struct{}
+
+func (f NotDeletedFilter) Where() (string, []interface{}) {
+	return "dashboard.deleted IS NULL", nil
+}
+
 type TitleFilter struct {
 	Dialect migrator.Dialect
 	Title   string
@@ -101,6 +101,10 @@ type StorageGitConfig struct {
 	RequirePullRequest bool   `json:"requirePullRequest"`
 	PullInterval       string `json:"pullInterval"`
 
+	// How changes reach the remote: "github" uses the GitHub API, "git" commits to the
+	// local clone and pushes over plain git. Defaults to github when a token is set.
+	Provider string `json:"provider,omitempty"`
+
 	// SECURE JSON :grimicing:
 	AccessToken string `json:"accessToken,omitempty"` // Simplest auth method for github
 }
@@ -33,6 +33,9 @@ func UploadErrorToStatusCode(err error) int {
 	case errors.Is(err, ErrFileAlreadyExists):
 		return 400
 
+	case errors.Is(err, ErrPullRequestRequired):
+		return 400
+
 	case errors.Is(err, ErrAccessDenied):
 		return 403
 
@@ -46,6 +49,7 @@ func (s *standardStorageService) RegisterHTTPRoutes(storageRoute routing.RouteRe
 	storageRoute.Get("/list/*", routing.Wrap(s.list))
 	storageRoute.Get("/read/*", routing.Wrap(s.read))
 	storageRoute.Get("/options/*", routing.Wrap(s.getOptions))
+	storageRoute.Get("/changes/*", routing.Wrap(s.getChangeRequests))
 
 	// Write paths
 	reqGrafanaAdmin := middleware.ReqGrafanaAdmin
@@ -186,6 +190,15 @@ func (s *standardStorageService) getOptions(c *contextmodel.ReqContext) response
 	return response.JSON(200, opts)
 }
 
+func (s *standardStorageService) getChangeRequests(c *contextmodel.ReqContext) response.Response {
+	scope, path := getPathAndScope(c)
+	changes, err := s.changeRequests(c.Req.Context(), c.SignedInUser, scope+"/"+path)
+	if err != nil {
+		return response.Error(UploadErrorToStatusCode(err), err.Error(), err)
+	}
+	return response.JSON(200, changes)
+}
+
 func (s *standardStorageService) doDelete(c *contextmodel.ReqContext) response.Response {
 	// full path is api/storage/delete/upload/example.jpg, but we only want the part after upload
 	scope, path := getPathAndScope(c)
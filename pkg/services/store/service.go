@@ -576,6 +576,24 @@ func (s *standardStorageService) write(ctx context.Context, user *user.SignedInU
 	return root.Write(ctx, req)
 }
 
+func (s *standardStorageService) changeRequests(ctx context.Context, user *user.SignedInUser, path string) ([]*ChangeRequest, error) {
+	guardian := s.authService.newGuardian(ctx, user, getFirstSegment(path))
+	if !guardian.canView(path) {
+		return nil, ErrAccessDenied
+	}
+
+	root, _ := s.tree.getRoot(getOrgId(user), path)
+	if root == nil {
+		return nil, ErrStorageNotFound
+	}
+
+	tracker, ok := root.(changeRequestTracker)
+	if !ok {
+		return nil, ErrUnsupportedStorage
+	}
+	return tracker.ChangeRequests(ctx)
+}
+
 type workflowInfo struct {
 	Type        WriteValueWorkflow `json:"value"` // value matches selectable value
 	Label       string             `json:"label"`
@@ -600,11 +618,19 @@ func (s *standardStorageService) getWorkflowOptions(ctx context.Context, user *u
 	meta := root.Meta()
 	if meta.Config.Type == rootStorageTypeGit && meta.Config.Git != nil {
 		cfg := meta.Config.Git
-		options.Workflows = append(options.Workflows, workflowInfo{
-			Type:        WriteValueWorkflow_PR,
-			Label:       "Create pull request",
-			Description: "Create a new upstream pull request",
-		})
+		if cfg.Provider == gitProviderPlain {
+			options.Workflows = append(options.Workflows, workflowInfo{
+				Type:        WriteValueWorkflow_PR,
+				Label:       "Create change request",
+				Description: "Push a new branch to be reviewed and merged upstream",
+			})
+		} else {
+			options.Workflows = append(options.Workflows, workflowInfo{
+				Type:        WriteValueWorkflow_PR,
+				Label:       "Create pull request",
+				Description: "Create a new upstream pull request",
+			})
+		}
 		if !cfg.RequirePullRequest {
 			options.Workflows = append(options.Workflows, workflowInfo{
 				Type:        WriteValueWorkflow_Push,
@@ -5,23 +5,23 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"gocloud.dev/blob"
 
 	"github.com/grafana/grafana/pkg/infra/filestorage"
-	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 const rootStorageTypeGit = "git"
 
 var _ storageRuntime = &rootStorageGit{}
+var _ changeRequestTracker = &rootStorageGit{}
 
 type rootStorageGit struct {
 	settings *StorageGitConfig
@@ -29,6 +29,7 @@ type rootStorageGit struct {
 	root     string // repostitory root
 
 	github *githubHelper
+	remote *gitRemoteHelper // plain git provider
 	meta   RootStorageMeta
 	store  filestorage.FileStorage
 }
@@ -57,6 +58,15 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 			Text:     "Missing remote path configuration",
 		})
 	}
+	if cfg.Provider == "" && cfg.AccessToken != "" {
+		cfg.Provider = gitProviderGithub
+	}
+	if cfg.Provider != "" && cfg.Provider != gitProviderGithub && cfg.Provider != gitProviderPlain {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityError,
+			Text:     "Unsupported git provider: " + cfg.Provider,
+		})
+	}
 
 	if len(localWorkCache) < 2 {
 		meta.Notice = append(meta.Notice, data.Notice{
@@ -112,10 +122,13 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 			p := localWorkCache
 			if cfg.Root != "" {
 				p = filepath.Join(p, cfg.Root)
+				err = os.MkdirAll(p, 0750) // not yet in the remote
 			}
 
-			path := fmt.Sprintf("file://%s", p)
-			bucket, err := blob.OpenBucket(context.Background(), path)
+			var bucket *blob.Bucket
+			if err == nil {
+				bucket, err = blob.OpenBucket(context.Background(), fmt.Sprintf("file://%s", p))
+			}
 			if err != nil {
 				grafanaStorageLogger.Warn("error loading storage", "prefix", scfg.Prefix, "err", err)
 				meta.Notice = append(meta.Notice, data.Notice{
@@ -130,6 +143,16 @@ func newGitStorage(meta RootStorageMeta, scfg RootStorageConfig, localWorkCache
 				meta.Ready = true // exists!
 				s.root = p
 
+				if cfg.Provider == gitProviderPlain {
+					s.remote, err = newGitRemoteHelper(repo, localWorkCache, cfg.Branch)
+					if err != nil {
+						meta.Notice = append(meta.Notice, data.Notice{
+							Severity: data.NoticeSeverityError,
+							Text:     "error reading repository: " + err.Error(),
+						})
+					}
+				}
+
 				token := cfg.AccessToken
 				if strings.HasPrefix(token, "$") {
 					token = os.Getenv(token[1:])
@@ -225,7 +248,16 @@ func (s *rootStorageGit) Pull() error {
 	return nil
 }
 
-func (s *rootStorageGit) Write(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error) {
+func (s *rootStorageGit) Write(ctx context.Context, req *WriteValueRequest) (*WriteValueResponse, error) {
+	// The path is changed to the path in the repository, leave the request of the caller as is
+	c := *req
+	cmd := &c
+
+	if s.remote != nil {
+		cmd.Path = path.Join(s.settings.Root, cmd.Path)
+		return s.remote.write(ctx, cmd, s.settings.RequirePullRequest)
+	}
+
 	if s.github == nil {
 		return nil, fmt.Errorf("github client not initialized")
 	}
@@ -237,7 +269,7 @@ func (s *rootStorageGit) Write(ctx context.Context, cmd *WriteValueRequest) (*Wr
 	if cmd.Workflow == WriteValueWorkflow_PR {
 		prcmd := makePRCommand{
 			baseBranch: s.settings.Branch,
-			headBranch: fmt.Sprintf("grafana_ui_%d", time.Now().UnixMilli()),
+			headBranch: fmt.Sprintf("%s%d", gitChangeBranchPrefix, time.Now().UnixMilli()),
 			title:      cmd.Title,
 			body:       cmd.Message,
 		}
@@ -282,91 +314,50 @@ func (s *rootStorageGit) Write(ctx context.Context, cmd *WriteValueRequest) (*Wr
 	}
 
 	// Push to remote branch (save)
-	if cmd.Workflow == WriteValueWorkflow_Push || true {
-		res := &WriteValueResponse{
-			Branch: s.settings.Branch,
-		}
-		ref, _, err := s.github.getRef(ctx, s.settings.Branch)
-		if err != nil {
-			res.Code = 500
-			res.Message = "unable to create branch"
-			return res, nil
-		}
-		err = s.github.pushCommit(ctx, ref, cmd)
-		if err != nil {
-			res.Code = 500
-			res.Message = "error creating commit"
-			return res, nil
-		}
-		ref, _, _ = s.github.getRef(ctx, s.settings.Branch)
-		if ref != nil {
-			res.Hash = *ref.Object.SHA
-			res.URL = ref.GetURL()
-		}
-
-		err = s.Pull()
-		if err != nil {
-			res.Message = "error pulling: " + err.Error()
-		}
-
-		res.Code = 200
-		return res, nil
-	}
-
-	rel := cmd.Path
-	if s.meta.Config.Git.Root != "" {
-		rel = filepath.Join(s.meta.Config.Git.Root, cmd.Path)
-	}
-
-	fpath := filepath.Join(s.root, rel)
-	err := os.WriteFile(fpath, cmd.Body, 0644)
-	if err != nil {
-		return nil, err
+	res := &WriteValueResponse{
+		Branch: s.settings.Branch,
 	}
-
-	w, err := s.repo.Worktree()
+	ref, _, err := s.github.getRef(ctx, s.settings.Branch)
 	if err != nil {
-		return nil, err
+		res.Code = 500
+		res.Message = "unable to create branch"
+		return res, nil
 	}
-
-	// The file we just wrote
-	_, err = w.Add(rel)
+	err = s.github.pushCommit(ctx, ref, cmd)
 	if err != nil {
-		return nil, err
-	}
-
-	msg := cmd.Message
-	if msg == "" {
-		msg = "changes from grafana ui"
+		res.Code = 500
+		res.Message = "error creating commit"
+		return res, nil
 	}
-	usr := cmd.User
-	if usr == nil {
-		usr = &user.SignedInUser{}
+	ref, _, _ = s.github.getRef(ctx, s.settings.Branch)
+	if ref != nil {
+		res.Hash = *ref.Object.SHA
+		res.URL = ref.GetURL()
 	}
 
-	hash, err := w.Commit(msg, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  firstRealString(usr.Name, usr.Login, usr.Email, "?"),
-			Email: firstRealString(usr.Email, usr.Login, usr.Name, "?"),
-			When:  time.Now(),
-		},
-	})
+	err = s.Pull()
 	if err != nil {
-		return nil, err
+		res.Message = "error pulling: " + err.Error()
 	}
 
-	grafanaStorageLogger.Info("made commit", "hash", hash)
-	// err = s.repo.Push(&git.PushOptions{
-	// 	InsecureSkipTLS: true,
-	// })
+	res.Code = 200
+	return res, nil
+}
 
-	return &WriteValueResponse{
-		Hash:    hash.String(),
-		Message: "made commit",
-	}, nil
+// ChangeRequests lists the branches pushed for review and whether they were merged
+func (s *rootStorageGit) ChangeRequests(ctx context.Context) ([]*ChangeRequest, error) {
+	if s.remote == nil {
+		return nil, ErrUnsupportedStorage
+	}
+	return s.remote.changeRequests(ctx)
 }
 
 func (s *rootStorageGit) Sync() error {
+	if s.remote != nil {
+		s.remote.mu.Lock()
+		defer s.remote.mu.Unlock()
+	}
+
 	grafanaStorageLogger.Info("GIT PULL", "remote", s.settings.Remote)
 	err := s.Pull()
 	if err != nil {
@@ -0,0 +1,377 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	gitProviderGithub = "github"
+	gitProviderPlain  = "git"
+
+	gitRemoteName         = "origin"
+	gitDefaultBranch      = "main"
+	gitChangeBranchPrefix = "grafana_ui_"
+)
+
+var ErrPullRequestRequired = errors.New("changes must be submitted as a pull request")
+
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestStatusOpen   ChangeRequestStatus = "open"
+	ChangeRequestStatusMerged ChangeRequestStatus = "merged"
+	// ChangeRequestStatusClosed is a branch that was deleted from the remote without being merged
+	ChangeRequestStatusClosed ChangeRequestStatus = "closed"
+)
+
+// ChangeRequest is a branch pushed by grafana that is waiting to be merged into the base branch
+type ChangeRequest struct {
+	Branch  string              `json:"branch"`
+	Base    string              `json:"base"`
+	Hash    string              `json:"hash"`
+	Title   string              `json:"title,omitempty"`
+	Author  string              `json:"author,omitempty"`
+	Created time.Time           `json:"created"`
+	Status  ChangeRequestStatus `json:"status"`
+}
+
+// changeRequestTracker is implemented by storage that supports reviewable writes
+type changeRequestTracker interface {
+	ChangeRequests(ctx context.Context) ([]*ChangeRequest, error)
+}
+
+// gitRemoteHelper writes to the local clone and pushes over plain git.
+// Change requests are not stored anywhere else: every branch named with the
+// grafana prefix is a change request, and it is merged once its changes are
+// in the base branch.
+type gitRemoteHelper struct {
+	repo *git.Repository
+	root string // worktree root
+	base string // branch changes are merged into
+
+	mu sync.Mutex
+}
+
+func newGitRemoteHelper(repo *git.Repository, root string, base string) (*gitRemoteHelper, error) {
+	if base == "" {
+		head, err := repo.Head()
+		if err != nil {
+			if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+				return nil, err
+			}
+			base = gitDefaultBranch
+		} else {
+			base = head.Name().Short()
+		}
+	}
+	return &gitRemoteHelper{
+		repo: repo,
+		root: root,
+		base: base,
+	}, nil
+}
+
+func (g *gitRemoteHelper) write(ctx context.Context, cmd *WriteValueRequest, requirePR bool) (*WriteValueResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if cmd.Workflow != WriteValueWorkflow_PR && requirePR {
+		return nil, ErrPullRequestRequired
+	}
+
+	w, err := g.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	branch := g.base
+	if cmd.Workflow == WriteValueWorkflow_PR {
+		branch = fmt.Sprintf("%s%d", gitChangeBranchPrefix, time.Now().UnixMilli())
+	}
+
+	// Commit on top of the remote head so the push fast-forwards and change requests start from the latest state
+	if err = g.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if err = g.resetBase(w); err != nil {
+		return nil, err
+	}
+	if branch != g.base {
+		if err = g.checkout(w, branch, true); err != nil {
+			return nil, err
+		}
+		// Always leave the worktree on the base branch so reads and pulls see the merged state
+		defer func() {
+			if err := g.checkout(w, g.base, false); err != nil {
+				grafanaStorageLogger.Warn("error restoring base branch", "branch", g.base, "err", err)
+			}
+		}()
+	}
+
+	hash, err := g.commit(w, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := plumbing.NewBranchReferenceName(branch)
+	err = g.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", ref, ref))},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		// Drop the rejected commit so it does not end up in later writes
+		if rerr := g.resetBase(w); rerr != nil {
+			grafanaStorageLogger.Warn("error resetting base branch", "branch", g.base, "err", rerr)
+		}
+		if branch != g.base {
+			if rerr := g.repo.Storer.RemoveReference(ref); rerr != nil {
+				grafanaStorageLogger.Warn("error removing branch", "branch", branch, "err", rerr)
+			}
+		}
+		return &WriteValueResponse{
+			Code:    500,
+			Message: "error pushing: " + err.Error(),
+			Branch:  branch,
+			Hash:    hash.String(),
+		}, nil
+	}
+
+	return &WriteValueResponse{
+		Code:    200,
+		Message: "made commit",
+		Branch:  branch,
+		Hash:    hash.String(),
+		Pending: branch != g.base,
+	}, nil
+}
+
+// fetch updates the remote tracking branches
+func (g *gitRemoteHelper) fetch(ctx context.Context) error {
+	err := g.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", gitRemoteName))},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+	return nil
+}
+
+// resetBase checks out the base branch and resets it to the fetched remote head, discarding local commits
+func (g *gitRemoteHelper) resetBase(w *git.Worktree) error {
+	if err := g.checkout(w, g.base, false); err != nil {
+		return err
+	}
+	remote, err := g.repo.Reference(plumbing.NewRemoteReferenceName(gitRemoteName, g.base), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil // nothing pushed yet
+		}
+		return err
+	}
+	return w.Reset(&git.ResetOptions{Commit: remote.Hash(), Mode: git.HardReset})
+}
+
+func (g *gitRemoteHelper) checkout(w *git.Worktree, branch string, create bool) error {
+	opts := &git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	}
+	err := w.Checkout(opts)
+	if err == nil || create {
+		return err
+	}
+
+	// The branch may only exist on the remote, ie. right after the initial clone
+	remote, rerr := g.repo.Reference(plumbing.NewRemoteReferenceName(gitRemoteName, branch), true)
+	if rerr != nil {
+		return err
+	}
+	opts.Create = true
+	opts.Hash = remote.Hash()
+	return w.Checkout(opts)
+}
+
+func (g *gitRemoteHelper) commit(w *git.Worktree, cmd *WriteValueRequest) (plumbing.Hash, error) {
+	rel := strings.TrimPrefix(cmd.Path, "/")
+	fpath := filepath.Join(g.root, rel)
+	if err := os.MkdirAll(filepath.Dir(fpath), 0750); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := os.WriteFile(fpath, cmd.Body, 0600); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if _, err := w.Add(rel); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	msg := cmd.Message
+	if cmd.Title != "" {
+		msg = strings.TrimSpace(cmd.Title + "\n\n" + msg)
+	}
+	if msg == "" {
+		msg = "changes from grafana ui"
+	}
+	usr := cmd.User
+	if usr == nil {
+		usr = &user.SignedInUser{}
+	}
+
+	return w.Commit(msg, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  firstRealString(usr.Name, usr.Login, usr.Email, "?"),
+			Email: firstRealString(usr.Email, usr.Login, usr.Name, "?"),
+			When:  time.Now(),
+		},
+	})
+}
+
+func (g *gitRemoteHelper) changeRequests(ctx context.Context) ([]*ChangeRequest, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.fetch(ctx); err != nil {
+		return nil, err
+	}
+
+	// List the remote rather than the local tracking refs so deleted branches are noticed
+	remote, err := g.repo.Remote(gitRemoteName)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var baseCommit *object.Commit
+	heads := make(map[string]plumbing.Hash)
+	for _, ref := range refs {
+		if !ref.Name().IsBranch() {
+			continue
+		}
+		if ref.Name().Short() == g.base {
+			if baseCommit, err = g.repo.CommitObject(ref.Hash()); err != nil {
+				return nil, err
+			}
+		} else if strings.HasPrefix(ref.Name().Short(), gitChangeBranchPrefix) {
+			heads[ref.Name().Short()] = ref.Hash()
+		}
+	}
+	if baseCommit == nil {
+		return nil, fmt.Errorf("base branch %q not found on remote", g.base)
+	}
+
+	// Branches pushed from here are kept locally, so the ones deleted from the remote once merged are still listed
+	deleted := make(map[string]bool)
+	branches, err := g.repo.Branches()
+	if err != nil {
+		return nil, err
+	}
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if _, exists := heads[name]; !exists && strings.HasPrefix(name, gitChangeBranchPrefix) {
+			heads[name] = ref.Hash()
+			deleted[name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*ChangeRequest, 0, len(heads))
+	for branch, hash := range heads {
+		commit, err := g.repo.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		merged, err := isMerged(commit, baseCommit)
+		if err != nil {
+			return nil, err
+		}
+
+		status := ChangeRequestStatusOpen
+		if merged {
+			status = ChangeRequestStatusMerged
+		} else if deleted[branch] {
+			status = ChangeRequestStatusClosed
+		}
+		title, _, _ := strings.Cut(commit.Message, "\n")
+		changes = append(changes, &ChangeRequest{
+			Branch:  branch,
+			Base:    g.base,
+			Hash:    commit.Hash.String(),
+			Title:   title,
+			Author:  commit.Author.Email,
+			Created: commit.Author.When,
+			Status:  status,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Created.After(changes[j].Created)
+	})
+	return changes, nil
+}
+
+// isMerged checks if the changes of a branch are in the base branch. Squash and rebase merges create new
+// commits, so besides merged commits, a branch is merged when every file it changed has the same contents
+// in the base branch.
+func isMerged(commit *object.Commit, base *object.Commit) (bool, error) {
+	merged, err := commit.IsAncestor(base)
+	if err != nil || merged {
+		return merged, err
+	}
+
+	forks, err := commit.MergeBase(base)
+	if err != nil || len(forks) == 0 {
+		return false, err
+	}
+	forkTree, err := forks[0].Tree()
+	if err != nil {
+		return false, err
+	}
+	branchTree, err := commit.Tree()
+	if err != nil {
+		return false, err
+	}
+	baseTree, err := base.Tree()
+	if err != nil {
+		return false, err
+	}
+	changes, err := object.DiffTree(forkTree, branchTree)
+	if err != nil {
+		return false, err
+	}
+
+	for _, change := range changes {
+		if change.To.Name == "" { // deleted by the branch
+			if _, err := baseTree.FindEntry(change.From.Name); err == nil {
+				return false, nil
+			}
+			continue
+		}
+		entry, err := baseTree.FindEntry(change.To.Name)
+		if err != nil || entry.Hash != change.To.TreeEntry.Hash {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,218 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBareRemote creates a bare repository with a single commit on main
+func setupBareRemote(t *testing.T) (string, *git.Repository) {
+	t.Helper()
+	dir := t.TempDir()
+	remotePath := filepath.Join(dir, "remote.git")
+	main := plumbing.NewBranchReferenceName("main")
+
+	remote, err := git.PlainInit(remotePath, true)
+	require.NoError(t, err)
+	require.NoError(t, remote.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, main)))
+
+	seedPath := filepath.Join(dir, "seed")
+	seed, err := git.PlainInit(seedPath, false)
+	require.NoError(t, err)
+	require.NoError(t, seed.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, main)))
+	require.NoError(t, os.WriteFile(filepath.Join(seedPath, "README.md"), []byte("dashboards"), 0600))
+
+	w, err := seed.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add("README.md")
+	require.NoError(t, err)
+	_, err = w.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	_, err = seed.CreateRemote(&config.RemoteConfig{Name: gitRemoteName, URLs: []string{remotePath}})
+	require.NoError(t, err)
+	require.NoError(t, seed.Push(&git.PushOptions{RemoteName: gitRemoteName}))
+
+	return remotePath, remote
+}
+
+// pushToRemote commits files to the main branch of the remote from another clone, like another user would
+func pushToRemote(t *testing.T, remotePath string, files map[string]string) plumbing.Hash {
+	t.Helper()
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	clone, err := git.PlainClone(clonePath, false, &git.CloneOptions{URL: remotePath})
+	require.NoError(t, err)
+	w, err := clone.Worktree()
+	require.NoError(t, err)
+	for name, contents := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(clonePath, name)), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(clonePath, name), []byte(contents), 0600))
+		_, err = w.Add(name)
+		require.NoError(t, err)
+	}
+	hash, err := w.Commit("change from another clone", &git.CommitOptions{
+		Author: &object.Signature{Name: "other", Email: "other@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, clone.Push(&git.PushOptions{RemoteName: gitRemoteName}))
+	return hash
+}
+
+func TestGitStoragePlainRemote(t *testing.T) {
+	ctx := context.Background()
+	dashboard := []byte(`{"title": "test"}`)
+
+	newStorage := func(t *testing.T, remotePath string, requirePR bool) *rootStorageGit {
+		t.Helper()
+		s := newGitStorage(RootStorageMeta{}, RootStorageConfig{
+			Prefix: "git",
+			Git: &StorageGitConfig{
+				Remote:             remotePath,
+				Provider:           gitProviderPlain,
+				Root:               "dashboards",
+				RequirePullRequest: requirePR,
+			},
+		}, filepath.Join(t.TempDir(), "cache"))
+		require.Empty(t, s.Meta().Notice)
+		require.True(t, s.Meta().Ready)
+		return s
+	}
+
+	t.Run("push commits to the base branch", func(t *testing.T) {
+		remotePath, remote := setupBareRemote(t)
+		s := newStorage(t, remotePath, false)
+
+		res, err := s.Write(ctx, &WriteValueRequest{
+			Path:     "/a.json",
+			Body:     dashboard,
+			Workflow: WriteValueWorkflow_Push,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+		require.Equal(t, "main", res.Branch)
+		require.False(t, res.Pending)
+
+		ref, err := remote.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		require.Equal(t, res.Hash, ref.Hash().String())
+
+		commit, err := remote.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		file, err := commit.File("dashboards/a.json")
+		require.NoError(t, err)
+		contents, err := file.Contents()
+		require.NoError(t, err)
+		require.Equal(t, string(dashboard), contents)
+	})
+
+	t.Run("pull request creates a branch that is tracked until merged", func(t *testing.T) {
+		remotePath, remote := setupBareRemote(t)
+		s := newStorage(t, remotePath, true)
+
+		_, err := s.Write(ctx, &WriteValueRequest{
+			Path:     "/a.json",
+			Body:     dashboard,
+			Workflow: WriteValueWorkflow_Push,
+		})
+		require.ErrorIs(t, err, ErrPullRequestRequired)
+
+		res, err := s.Write(ctx, &WriteValueRequest{
+			Path:     "/a.json",
+			Body:     dashboard,
+			Title:    "Add dashboard",
+			Workflow: WriteValueWorkflow_PR,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+		require.True(t, res.Pending)
+		require.True(t, strings.HasPrefix(res.Branch, gitChangeBranchPrefix))
+
+		// The worktree stays on the base branch
+		_, err = os.Stat(filepath.Join(s.root, "a.json"))
+		require.True(t, os.IsNotExist(err))
+
+		changes, err := s.ChangeRequests(ctx)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, res.Branch, changes[0].Branch)
+		require.Equal(t, "main", changes[0].Base)
+		require.Equal(t, "Add dashboard", changes[0].Title)
+		require.Equal(t, ChangeRequestStatusOpen, changes[0].Status)
+
+		// Fast-forward main on the remote, as a reviewer merging the branch would
+		merged := plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), plumbing.NewHash(res.Hash))
+		require.NoError(t, remote.Storer.SetReference(merged))
+
+		changes, err = s.ChangeRequests(ctx)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, ChangeRequestStatusMerged, changes[0].Status)
+
+		require.NoError(t, s.Sync())
+		_, err = os.Stat(filepath.Join(s.root, "a.json"))
+		require.NoError(t, err)
+	})
+
+	t.Run("commits are based on the latest remote head", func(t *testing.T) {
+		remotePath, remote := setupBareRemote(t)
+		s := newStorage(t, remotePath, false)
+		other := pushToRemote(t, remotePath, map[string]string{"dashboards/b.json": "{}"})
+
+		req := &WriteValueRequest{
+			Path:     "/a.json",
+			Body:     dashboard,
+			Workflow: WriteValueWorkflow_Push,
+		}
+		res, err := s.Write(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, 200, res.Code, res.Message)
+		require.Equal(t, "/a.json", req.Path)
+
+		ref, err := remote.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		commit, err := remote.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		require.Equal(t, []plumbing.Hash{other}, commit.ParentHashes)
+	})
+
+	t.Run("squash merged and deleted branches are tracked", func(t *testing.T) {
+		remotePath, remote := setupBareRemote(t)
+		s := newStorage(t, remotePath, true)
+
+		squashed, err := s.Write(ctx, &WriteValueRequest{Path: "/a.json", Body: dashboard, Workflow: WriteValueWorkflow_PR})
+		require.NoError(t, err)
+		require.Equal(t, 200, squashed.Code, squashed.Message)
+		closed, err := s.Write(ctx, &WriteValueRequest{Path: "/b.json", Body: dashboard, Workflow: WriteValueWorkflow_PR})
+		require.NoError(t, err)
+		require.Equal(t, 200, closed.Code, closed.Message)
+
+		// Squash the first branch into main and delete both branches, as a reviewer would
+		pushToRemote(t, remotePath, map[string]string{"dashboards/a.json": string(dashboard)})
+		for _, branch := range []string{squashed.Branch, closed.Branch} {
+			require.NoError(t, remote.Storer.RemoveReference(plumbing.NewBranchReferenceName(branch)))
+		}
+
+		changes, err := s.ChangeRequests(ctx)
+		require.NoError(t, err)
+		status := map[string]ChangeRequestStatus{}
+		for _, change := range changes {
+			status[change.Branch] = change.Status
+		}
+		require.Equal(t, map[string]ChangeRequestStatus{
+			squashed.Branch: ChangeRequestStatusMerged,
+			closed.Branch:   ChangeRequestStatusClosed,
+		}, status)
+	})
+}
@@ -18,6 +18,7 @@ type FakeUserService struct {
 
 	GetSignedInUserFn func(ctx context.Context, query *user.GetSignedInUserQuery) (*user.SignedInUser, error)
 	CreateFn          func(ctx context.Context, cmd *user.CreateUserCommand) (*user.User, error)
+	UpdateFn          func(ctx context.Context, cmd *user.UpdateUserCommand) error
 	DisableFn         func(ctx context.Context, cmd *user.DisableUserCommand) error
 
 	counter int
@@ -60,6 +61,9 @@ func (f *FakeUserService) GetByEmail(ctx context.Context, query *user.GetUserByE
 }
 
 func (f *FakeUserService) Update(ctx context.Context, cmd *user.UpdateUserCommand) error {
+	if f.UpdateFn != nil {
+		return f.UpdateFn(ctx, cmd)
+	}
 	return f.ExpectedError
 }
 
@@ -50,6 +50,15 @@ const (
 	ApplicationName  = "Grafana"
 )
 
+// Modes of validating dashboards against the dashboard kind schema on save.
+const (
+	DashboardSchemaValidationOff = "off"
+	// DashboardSchemaValidationReport logs the dashboards that do not match the schema and saves them.
+	DashboardSchemaValidationReport = "report"
+	// DashboardSchemaValidationStrict rejects the dashboards that do not match the schema.
+	DashboardSchemaValidationStrict = "strict"
+)
+
 // zoneInfo names environment variable for setting the path to look for the timezone database in go
 const zoneInfo = "ZONEINFO"
 
@@ -253,6 +262,12 @@ type Cfg struct {
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	// DashboardTrashRetention is how long deleted dashboards are kept in the trash.
+	// Dashboards are deleted permanently right away when zero.
+	DashboardTrashRetention time.Duration
+	// DashboardSchemaValidation is how dashboards are validated against the dashboard kind schema
+	// when they are saved: off, report or strict.
+	DashboardSchemaValidation string
 
 	// Auth
 	LoginCookieName              string
@@ -354,7 +369,11 @@ type Cfg struct {
 	CaseInsensitiveLogin  bool // Login and Email will be considered case insensitive
 
 	// Service Accounts
-	SATokenExpirationDayLimit int
+	SATokenExpirationDayLimit       int
+	SATokenRotationGracePeriod      time.Duration
+	SATokenUnusedDisableDays        int
+	SATokenUnusedDisableJobInterval time.Duration
+	SATokenExpiryWarningPeriod      time.Duration
 
 	// Annotations
 	AnnotationCleanupJobBatchSize      int64
@@ -425,6 +444,10 @@ type Cfg struct {
 	GenericOAuthAuthEnabled     bool
 	GenericOAuthSkipOrgRoleSync bool
 
+	// OpenID Connect
+	OIDCAuthEnabled     bool
+	OIDCSkipOrgRoleSync bool
+
 	// LDAP
 	LDAPAuthEnabled       bool
 	LDAPSkipOrgRoleSync   bool
@@ -493,6 +516,18 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	// Query caching
+	QueryCaching QueryCachingSettings
+
+	// Multi-factor authentication
+	MFA MFASettings
+
+	// Brute force login protection by IP address and subnet
+	BruteForce BruteForceSettings
+
+	// SCIM user and group provisioning
+	SCIM SCIMSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -510,6 +545,8 @@ type Cfg struct {
 	RBACPermissionValidationEnabled bool
 	// Reset basic roles permissions on start-up
 	RBACResetBasicRoles bool
+	// Maximum duration of time-bound role assignments, 0 means no limit
+	RBACRoleAssignmentMaxDuration time.Duration
 
 	// GRPC Server.
 	GRPCServerNetwork   string
@@ -1025,6 +1062,16 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.DashboardTrashRetention, err = gtime.ParseDuration(valueAsString(dashboards, "trash_retention", "30d"))
+	if err != nil {
+		return err
+	}
+	cfg.DashboardSchemaValidation = valueAsString(dashboards, "schema_validation", DashboardSchemaValidationOff)
+	switch cfg.DashboardSchemaValidation {
+	case DashboardSchemaValidationOff, DashboardSchemaValidationReport, DashboardSchemaValidationStrict:
+	default:
+		return fmt.Errorf("invalid dashboards schema_validation %q, expected off, report or strict", cfg.DashboardSchemaValidation)
+	}
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1130,6 +1177,23 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
 
+	cfg.QueryCaching, err = readQueryCachingSettings(iniFile)
+	if err != nil {
+		return err
+	}
+
+	cfg.MFA, err = readMFASettings(iniFile)
+	if err != nil {
+		return err
+	}
+
+	cfg.BruteForce, err = readBruteForceSettings(iniFile)
+	if err != nil {
+		return err
+	}
+
+	cfg.SCIM = readSCIMSettings(iniFile)
+
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
 		// if the proxy is misconfigured, disable it rather than crashing
@@ -1432,6 +1496,12 @@ func readGenericOAuthSettings(iniFile *ini.File, cfg *Cfg) {
 	cfg.GenericOAuthSkipOrgRoleSync = sec.Key("skip_org_role_sync").MustBool(false)
 }
 
+func readAuthOIDCSettings(iniFile *ini.File, cfg *Cfg) {
+	sec := iniFile.Section("auth.oidc")
+	cfg.OIDCAuthEnabled = sec.Key("enabled").MustBool(false)
+	cfg.OIDCSkipOrgRoleSync = sec.Key("skip_org_role_sync").MustBool(false)
+}
+
 func readAuthOktaSettings(iniFile *ini.File, cfg *Cfg) {
 	sec := iniFile.Section("auth.okta")
 	cfg.OktaAuthEnabled = sec.Key("enabled").MustBool(false)
@@ -1506,6 +1576,9 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	// Generic OAuth
 	readGenericOAuthSettings(iniFile, cfg)
 
+	// OpenID Connect
+	readAuthOIDCSettings(iniFile, cfg)
+
 	// Okta Auth
 	readAuthOktaSettings(iniFile, cfg)
 
@@ -1575,6 +1648,7 @@ func readAccessControlSettings(iniFile *ini.File, cfg *Cfg) {
 	cfg.RBACPermissionCache = rbac.Key("permission_cache").MustBool(true)
 	cfg.RBACPermissionValidationEnabled = rbac.Key("permission_validation_enabled").MustBool(false)
 	cfg.RBACResetBasicRoles = rbac.Key("reset_basic_roles").MustBool(false)
+	cfg.RBACRoleAssignmentMaxDuration = rbac.Key("role_assignment_max_duration").MustDuration(0)
 }
 
 func readUserSettings(iniFile *ini.File, cfg *Cfg) error {
@@ -1626,6 +1700,10 @@ func readUserSettings(iniFile *ini.File, cfg *Cfg) error {
 func readServiceAccountSettings(iniFile *ini.File, cfg *Cfg) error {
 	serviceAccount := iniFile.Section("service_accounts")
 	cfg.SATokenExpirationDayLimit = serviceAccount.Key("token_expiration_day_limit").MustInt(-1)
+	cfg.SATokenRotationGracePeriod = serviceAccount.Key("token_rotation_grace_period").MustDuration(time.Hour)
+	cfg.SATokenUnusedDisableDays = serviceAccount.Key("token_unused_disable_days").MustInt(0)
+	cfg.SATokenUnusedDisableJobInterval = serviceAccount.Key("token_unused_disable_interval").MustDuration(time.Hour)
+	cfg.SATokenExpiryWarningPeriod = serviceAccount.Key("token_expiry_warning_period").MustDuration(7 * 24 * time.Hour)
 	return nil
 }
 
@@ -0,0 +1,76 @@
+package setting
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type BruteForceSettings struct {
+	// IPMaxAttempts is the number of failed logins from one IP address before it is locked out, 0 disables IP lockouts
+	IPMaxAttempts int64
+	// SubnetMaxAttempts is the number of failed logins from one subnet before it is locked out, 0 disables subnet lockouts
+	SubnetMaxAttempts int64
+	// IPv4SubnetPrefix and IPv6SubnetPrefix are the prefix lengths used to group addresses in subnets
+	IPv4SubnetPrefix int
+	IPv6SubnetPrefix int
+	// LockoutDuration is the duration of the first lockout of an IP address or subnet,
+	// it doubles every time the attempts exceed the limit again
+	LockoutDuration time.Duration
+	// MaxLockoutDuration caps the duration of lockouts
+	MaxLockoutDuration time.Duration
+	// AllowedNetworks are never locked out by IP address or subnet
+	AllowedNetworks []*net.IPNet
+}
+
+func readBruteForceSettings(iniFile *ini.File) (BruteForceSettings, error) {
+	security := iniFile.Section("security")
+
+	s := BruteForceSettings{
+		IPMaxAttempts:      security.Key("brute_force_ip_max_attempts").MustInt64(20),
+		SubnetMaxAttempts:  security.Key("brute_force_subnet_max_attempts").MustInt64(100),
+		IPv4SubnetPrefix:   security.Key("brute_force_ipv4_subnet_prefix").MustInt(24),
+		IPv6SubnetPrefix:   security.Key("brute_force_ipv6_subnet_prefix").MustInt(64),
+		LockoutDuration:    security.Key("brute_force_lockout_duration").MustDuration(5 * time.Minute),
+		MaxLockoutDuration: security.Key("brute_force_max_lockout_duration").MustDuration(time.Hour),
+	}
+
+	if s.IPMaxAttempts < 0 || s.SubnetMaxAttempts < 0 {
+		return s, fmt.Errorf("security brute_force_ip_max_attempts and brute_force_subnet_max_attempts can't be negative")
+	}
+	if s.IPv4SubnetPrefix < 0 || s.IPv4SubnetPrefix > 32 {
+		return s, fmt.Errorf("security brute_force_ipv4_subnet_prefix must be between 0 and 32")
+	}
+	if s.IPv6SubnetPrefix < 0 || s.IPv6SubnetPrefix > 128 {
+		return s, fmt.Errorf("security brute_force_ipv6_subnet_prefix must be between 0 and 128")
+	}
+	if s.LockoutDuration <= 0 {
+		return s, fmt.Errorf("security brute_force_lockout_duration must be positive")
+	}
+	if s.MaxLockoutDuration < s.LockoutDuration {
+		s.MaxLockoutDuration = s.LockoutDuration
+	}
+
+	for _, network := range util.SplitString(valueAsString(security, "brute_force_allowed_networks", "")) {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			// allow single addresses
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return s, fmt.Errorf("invalid network %q in security brute_force_allowed_networks", network)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+		s.AllowedNetworks = append(s.AllowedNetworks, ipNet)
+	}
+
+	return s, nil
+}
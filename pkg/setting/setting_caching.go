@@ -0,0 +1,55 @@
+package setting
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	QueryCachingBackendMemory = "memory"
+	QueryCachingBackendRemote = "remote"
+)
+
+type QueryCachingSettings struct {
+	Enabled      bool
+	Backend      string
+	TTL          time.Duration
+	ResourceTTL  time.Duration
+	MaxItems     int
+	MaxValueSize int
+	// DataSourceTTLs overrides TTL per data source UID. A zero TTL disables caching for the data source.
+	DataSourceTTLs map[string]time.Duration
+}
+
+func readQueryCachingSettings(iniFile *ini.File) (QueryCachingSettings, error) {
+	s := QueryCachingSettings{
+		DataSourceTTLs: map[string]time.Duration{},
+	}
+
+	cachingSection := iniFile.Section("caching")
+	s.Enabled = cachingSection.Key("enabled").MustBool(true)
+	s.Backend = valueAsString(cachingSection, "backend", QueryCachingBackendMemory)
+	s.TTL = cachingSection.Key("ttl").MustDuration(5 * time.Minute)
+	s.ResourceTTL = cachingSection.Key("resource_ttl").MustDuration(time.Minute)
+	s.MaxItems = cachingSection.Key("max_items").MustInt(1000)
+	s.MaxValueSize = cachingSection.Key("max_value_size").MustInt(10 * 1024 * 1024)
+
+	if s.Backend != QueryCachingBackendMemory && s.Backend != QueryCachingBackendRemote {
+		return s, fmt.Errorf("invalid caching backend %q, expected %q or %q", s.Backend, QueryCachingBackendMemory, QueryCachingBackendRemote)
+	}
+	if s.TTL <= 0 {
+		return s, fmt.Errorf("caching ttl must be positive")
+	}
+
+	for _, key := range iniFile.Section("caching.datasources").Keys() {
+		ttl, err := time.ParseDuration(key.Value())
+		if err != nil {
+			return s, fmt.Errorf("invalid caching ttl for data source %s: %w", key.Name(), err)
+		}
+		s.DataSourceTTLs[key.Name()] = ttl
+	}
+
+	return s, nil
+}
@@ -0,0 +1,53 @@
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadQueryCachingSettings(t *testing.T) {
+	t.Run("reads defaults", func(t *testing.T) {
+		s, err := readQueryCachingSettings(ini.Empty())
+		require.NoError(t, err)
+		assert.True(t, s.Enabled)
+		assert.Equal(t, QueryCachingBackendMemory, s.Backend)
+		assert.Equal(t, 5*time.Minute, s.TTL)
+		assert.Empty(t, s.DataSourceTTLs)
+	})
+
+	t.Run("reads data source ttls", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[caching]
+backend = remote
+ttl = 1m
+
+[caching.datasources]
+prometheus = 30s
+loki = 0
+`))
+		require.NoError(t, err)
+
+		s, err := readQueryCachingSettings(f)
+		require.NoError(t, err)
+		assert.Equal(t, QueryCachingBackendRemote, s.Backend)
+		assert.Equal(t, time.Minute, s.TTL)
+		assert.Equal(t, map[string]time.Duration{"prometheus": 30 * time.Second, "loki": 0}, s.DataSourceTTLs)
+	})
+
+	t.Run("fails on invalid settings", func(t *testing.T) {
+		for _, config := range []string{
+			"[caching]\nbackend = disk",
+			"[caching]\nttl = 0s",
+			"[caching.datasources]\nprometheus = soon",
+		} {
+			f, err := ini.Load([]byte(config))
+			require.NoError(t, err)
+			_, err = readQueryCachingSettings(f)
+			assert.Error(t, err, config)
+		}
+	})
+}
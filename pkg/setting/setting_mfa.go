@@ -0,0 +1,54 @@
+package setting
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type MFASettings struct {
+	// Enabled allows users to enroll second factors and challenges enrolled users after they sign in with a password
+	Enabled bool
+	// Enforce requires every user signing in with a Grafana password to pass a second factor challenge,
+	// users without a second factor have to enroll one before their session is created
+	Enforce bool
+	// AllowBasicAuth allows users with a second factor to authenticate API requests with basic auth
+	AllowBasicAuth bool
+	// Issuer is the name shown by authenticator apps next to TOTP codes
+	Issuer string
+	// ChallengeTTL is how long a user has to complete the challenge after signing in with a password
+	ChallengeTTL time.Duration
+	// MaxChallengeAttempts is the number of invalid codes accepted before a challenge is invalidated
+	MaxChallengeAttempts int
+	// WebAuthnRPID is the relying party ID of security keys, defaults to the host of root_url
+	WebAuthnRPID string
+	// WebAuthnRPOrigins are the origins allowed to use security keys, defaults to the origin of root_url
+	WebAuthnRPOrigins []string
+}
+
+func readMFASettings(iniFile *ini.File) (MFASettings, error) {
+	section := iniFile.Section("auth.mfa")
+
+	s := MFASettings{
+		Enabled:              section.Key("enabled").MustBool(false),
+		Enforce:              section.Key("enforce").MustBool(false),
+		AllowBasicAuth:       section.Key("allow_basic_auth").MustBool(false),
+		Issuer:               valueAsString(section, "issuer", "Grafana"),
+		ChallengeTTL:         section.Key("challenge_ttl").MustDuration(5 * time.Minute),
+		MaxChallengeAttempts: section.Key("max_challenge_attempts").MustInt(5),
+		WebAuthnRPID:         valueAsString(section, "webauthn_rp_id", ""),
+		WebAuthnRPOrigins:    util.SplitString(valueAsString(section, "webauthn_rp_origins", "")),
+	}
+
+	if s.ChallengeTTL <= 0 {
+		return s, fmt.Errorf("auth.mfa challenge_ttl must be positive")
+	}
+	if s.MaxChallengeAttempts <= 0 {
+		return s, fmt.Errorf("auth.mfa max_challenge_attempts must be positive")
+	}
+
+	return s, nil
+}
@@ -0,0 +1,21 @@
+package setting
+
+import (
+	"gopkg.in/ini.v1"
+)
+
+type SCIMSettings struct {
+	// Enabled registers the SCIM 2.0 endpoints
+	Enabled bool
+	// DefaultOrgRole is the role of users added to an org through SCIM
+	DefaultOrgRole string
+}
+
+func readSCIMSettings(iniFile *ini.File) SCIMSettings {
+	section := iniFile.Section("auth.scim")
+
+	return SCIMSettings{
+		Enabled:        section.Key("enabled").MustBool(false),
+		DefaultOrgRole: section.Key("default_org_role").In("Viewer", []string{"Viewer", "Editor", "Admin"}),
+	}
+}
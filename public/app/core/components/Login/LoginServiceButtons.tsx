@@ -65,6 +65,12 @@ const loginServices: () => LoginServices = () => {
       name: config.oauth?.okta?.name || 'Okta',
       icon: config.oauth?.okta?.icon || ('okta' as const),
     },
+    oidc: {
+      bgColor: '#262628',
+      enabled: oauthEnabled && Boolean(config.oauth.oidc),
+      name: config.oauth?.oidc?.name || 'OpenID Connect',
+      icon: config.oauth?.oidc?.icon || ('signin' as const),
+    },
     oauth: {
       bgColor: '#262628',
       enabled: oauthEnabled && Boolean(config.oauth.generic_oauth),